@@ -0,0 +1,175 @@
+package gfspapp
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	AdminTaskActionCancel   = "cancel"
+	AdminTaskActionRetry    = "retry"
+	AdminTaskActionPriority = "priority"
+	AdminTaskActionPause    = "pause"
+	AdminTaskActionResume   = "resume"
+)
+
+var (
+	ErrAdminUnauthorized   = gfsperrors.Register(BaseCodeSpace, http.StatusUnauthorized, 990604, "admin token is invalid")
+	ErrAdminDisabled       = gfsperrors.Register(BaseCodeSpace, http.StatusForbidden, 990605, "admin api is disabled")
+	ErrInvalidAdminRequest = gfsperrors.Register(BaseCodeSpace, http.StatusBadRequest, 990606, "invalid admin request")
+)
+
+// VerifyAdminToken checks the bearer token against the configured admin token, the admin
+// api is disabled if no admin token is configured. The authorization must carry the bearer scheme.
+func (g *GfSpBaseApp) VerifyAdminToken(authorization string) error {
+	if g.adminToken == "" {
+		return ErrAdminDisabled
+	}
+	token, ok := strings.CutPrefix(authorization, gfspclient.AdminTokenScheme)
+	if !ok {
+		return ErrAdminUnauthorized
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(g.adminToken)) != 1 {
+		return ErrAdminUnauthorized
+	}
+	return nil
+}
+
+func (g *GfSpBaseApp) verifyAdminContext(ctx context.Context) error {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(gfspclient.AdminTokenMetadataKey); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	return g.VerifyAdminToken(authorization)
+}
+
+// auditAdmin records the admin operation, the requests rejected by the admin token are
+// recorded as denied.
+func auditAdmin(ctx context.Context, action, resource string, detail map[string]string, err error) {
+	event := &audit.Event{
+		Type:     audit.EventAdminCommand,
+		Actor:    "admin",
+		Remote:   GetRPCRemoteAddress(ctx),
+		Action:   action,
+		Resource: resource,
+		Result:   audit.ResultSuccess,
+		Detail:   detail,
+	}
+	if err != nil {
+		event.Result = audit.ResultFailure
+		if err == ErrAdminUnauthorized || err == ErrAdminDisabled {
+			event.Result = audit.ResultDenied
+		}
+		if event.Detail == nil {
+			event.Detail = make(map[string]string)
+		}
+		event.Detail["error"] = err.Error()
+	}
+	audit.Emit(ctx, event)
+}
+
+func (g *GfSpBaseApp) GfSpListTasks(ctx context.Context, req *gfspserver.GfSpListTasksRequest) (
+	*gfspserver.GfSpListTasksResponse, error) {
+	if err := g.verifyAdminContext(ctx); err != nil {
+		auditAdmin(ctx, "list_tasks", req.GetSubKey(), nil, err)
+		log.CtxWarnw(ctx, "reject unauthorized admin request to list tasks", "error", err)
+		return &gfspserver.GfSpListTasksResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	filter := &coremodule.TaskFilter{
+		SubKey:      coretask.TKey(req.GetSubKey()),
+		MinRetry:    req.GetMinRetry(),
+		OnlyTimeout: req.GetOnlyTimeout(),
+		Limit:       int(req.GetLimit()),
+	}
+	if req.GetTaskType() != "" {
+		if filter.TaskType = coretask.TaskTypeFromName(req.GetTaskType()); filter.TaskType == coretask.TypeTaskUnknown {
+			return &gfspserver.GfSpListTasksResponse{Err: ErrInvalidAdminRequest}, nil
+		}
+	}
+	tasks, err := g.manager.ListTasks(ctx, filter)
+	auditAdmin(ctx, "list_tasks", req.GetSubKey(), map[string]string{
+		"task_type": req.GetTaskType(), "count": strconv.Itoa(len(tasks))}, err)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list tasks", "error", err)
+		return &gfspserver.GfSpListTasksResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	resp := &gfspserver.GfSpListTasksResponse{}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, &gfspserver.GfSpAdminTask{
+			Key:           task.Key().String(),
+			Type:          coretask.TaskTypeName(task.Type()),
+			Priority:      uint32(task.GetPriority()),
+			Retry:         task.GetRetry(),
+			MaxRetry:      task.GetMaxRetry(),
+			CreateTime:    task.GetCreateTime(),
+			UpdateTime:    task.GetUpdateTime(),
+			ExceedTimeout: task.ExceedTimeout(),
+			Address:       task.GetAddress(),
+			Info:          task.Info(),
+		})
+	}
+	for _, taskType := range g.manager.PausedTaskTypes(ctx) {
+		resp.PausedTaskTypes = append(resp.PausedTaskTypes, coretask.TaskTypeName(taskType))
+	}
+	return resp, nil
+}
+
+func (g *GfSpBaseApp) GfSpControlTask(ctx context.Context, req *gfspserver.GfSpControlTaskRequest) (
+	*gfspserver.GfSpControlTaskResponse, error) {
+	detail := map[string]string{"task_type": req.GetTaskType(), "priority": strconv.FormatUint(uint64(req.GetPriority()), 10)}
+	if err := g.verifyAdminContext(ctx); err != nil {
+		auditAdmin(ctx, "control_task_"+req.GetAction(), req.GetTaskKey(), detail, err)
+		log.CtxWarnw(ctx, "reject unauthorized admin request to control task", "error", err)
+		return &gfspserver.GfSpControlTaskResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	var (
+		key = coretask.TKey(req.GetTaskKey())
+		err error
+	)
+	switch req.GetAction() {
+	case AdminTaskActionCancel:
+		err = g.manager.CancelTask(ctx, key)
+	case AdminTaskActionRetry:
+		err = g.manager.RetryTask(ctx, key)
+	case AdminTaskActionPriority:
+		// the task of the unscheduling priority is never dispatched
+		if req.GetPriority() == uint32(coretask.UnSchedulingPriority) || req.GetPriority() > uint32(coretask.MaxTaskPriority) {
+			err = ErrInvalidAdminRequest
+			break
+		}
+		err = g.manager.SetTaskPriority(ctx, key, coretask.TPriority(req.GetPriority()))
+	case AdminTaskActionPause, AdminTaskActionResume:
+		taskType := coretask.TaskTypeFromName(req.GetTaskType())
+		if taskType == coretask.TypeTaskUnknown {
+			err = ErrInvalidAdminRequest
+			break
+		}
+		err = g.manager.PauseTaskDispatch(ctx, taskType, req.GetAction() == AdminTaskActionPause)
+	default:
+		err = ErrInvalidAdminRequest
+	}
+	auditAdmin(ctx, "control_task_"+req.GetAction(), req.GetTaskKey(), detail, err)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to control task", "action", req.GetAction(), "task_key", req.GetTaskKey(),
+			"task_type", req.GetTaskType(), "error", err)
+		return &gfspserver.GfSpControlTaskResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	log.CtxInfow(ctx, "succeed to control task", "action", req.GetAction(), "task_key", req.GetTaskKey(),
+		"task_type", req.GetTaskType())
+	return &gfspserver.GfSpControlTaskResponse{}, nil
+}
@@ -0,0 +1,29 @@
+package gfspapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGfSpBaseApp_VerifyAdminToken(t *testing.T) {
+	cases := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		wantedErr     error
+	}{
+		{name: "disabled", adminToken: "", authorization: "Bearer token", wantedErr: ErrAdminDisabled},
+		{name: "valid token", adminToken: "token", authorization: "Bearer token"},
+		{name: "missing scheme", adminToken: "token", authorization: "token", wantedErr: ErrAdminUnauthorized},
+		{name: "wrong scheme", adminToken: "token", authorization: "Basic token", wantedErr: ErrAdminUnauthorized},
+		{name: "wrong token", adminToken: "token", authorization: "Bearer other", wantedErr: ErrAdminUnauthorized},
+		{name: "empty", adminToken: "token", authorization: "", wantedErr: ErrAdminUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := &GfSpBaseApp{adminToken: c.adminToken}
+			require.Equal(t, c.wantedErr, g.VerifyAdminToken(c.authorization))
+		})
+	}
+}
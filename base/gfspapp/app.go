@@ -2,17 +2,23 @@ package gfspapp
 
 import (
 	"context"
+	"io"
+	"sync"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
 	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
 	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
 )
 
@@ -50,11 +56,22 @@ type GfSpBaseApp struct {
 	uploader      module.Uploader
 	metrics       module.Modular
 	pprof         module.Modular
+	tracing       *tracing.Tracing
 
 	appCtx    context.Context
 	appCancel context.CancelFunc
 	services  []corelifecycle.Service
 
+	draining      int32
+	drainTimeout  time.Duration
+	drainOnSignal bool
+	adminToken    string
+
+	configLoader  func() (*gfspconfig.GfSpConfig, error)
+	runningConfig *gfspconfig.GfSpConfig
+	reloadMux     sync.Mutex
+	taskMux       sync.RWMutex
+
 	uploadSpeed    int64
 	downloadSpeed  int64
 	replicateSpeed int64
@@ -115,6 +132,12 @@ func (g *GfSpBaseApp) GfSpDB() spdb.SPDB {
 	return g.gfSpDB
 }
 
+// GfSpDBWithContext returns the sp db client whose db calls are traced under the span of ctx,
+// the calls are not canceled with ctx, so the progress is still recorded for the canceled request.
+func (g *GfSpBaseApp) GfSpDBWithContext(ctx context.Context) spdb.SPDB {
+	return spdb.WithContext(tracing.DetachContext(ctx), g.gfSpDB)
+}
+
 // GfBsDB returns the block syncer db client.
 func (g *GfSpBaseApp) GfBsDB() bsdb.BSDB {
 	return g.gfBsDB
@@ -165,9 +188,21 @@ func (g *GfSpBaseApp) close(ctx context.Context) error {
 	g.GfSpClient().Close()
 	g.rcmgr.Close()
 	g.chain.Close()
+	if closer, ok := g.gfSpDB.(io.Closer); ok {
+		closer.Close()
+	}
+	audit.Close()
+	if g.tracing != nil {
+		g.tracing.Shutdown(ctx)
+	}
 	return nil
 }
 
+// EnableTracing returns an indicator whether enable the tracing.
+func (g *GfSpBaseApp) EnableTracing() bool {
+	return g.tracing != nil
+}
+
 // EnableMetrics returns an indicator whether enable the metrics service.
 func (g *GfSpBaseApp) EnableMetrics() bool {
 	return g.metrics != nil
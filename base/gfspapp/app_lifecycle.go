@@ -5,14 +5,22 @@ import (
 	"errors"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
+	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
 
 const (
 	// DefaultStopTime defines the default timeout for stopping services.
 	DefaultStopTime = 30
+	// DefaultDrainTimeout defines the default timeout in seconds for draining services.
+	DefaultDrainTimeout = 60
 )
 
 var _ corelifecycle.Lifecycle = &GfSpBaseApp{}
@@ -26,9 +34,33 @@ func (g *GfSpBaseApp) RegisterServices(services ...corelifecycle.Service) {
 func (g *GfSpBaseApp) StartServices(ctx context.Context) corelifecycle.Lifecycle {
 	g.appCtx, g.appCancel = context.WithCancel(ctx)
 	g.startServices(ctx)
+	if g.uploader != nil || g.receiver != nil {
+		go g.cleanStagedPieces(g.appCtx)
+	}
 	return g
 }
 
+// cleanStagedPieces periodically deletes the staged pieces left by the crashed uploading or
+// receiving, it runs once in the process that hosts the uploader or the receiver, the recently
+// staged pieces may belong to the other running instances sharing the piece store.
+func (g *GfSpBaseApp) cleanStagedPieces(ctx context.Context) {
+	ticker := time.NewTicker(piecestore.CleanStagedPiecesInterval)
+	defer ticker.Stop()
+	for {
+		cleaned, err := g.pieceStore.CleanStagedPieces(ctx, time.Now().Add(-piecestore.StaleStagedPieceDuration))
+		if err != nil {
+			log.CtxWarnw(ctx, "failed to clean stale staged pieces", "cleaned", cleaned, "error", err)
+		} else {
+			log.CtxInfow(ctx, "succeed to clean stale staged pieces", "cleaned", cleaned)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (g *GfSpBaseApp) startServices(ctx context.Context) {
 	for i, service := range g.services {
 		if err := service.Start(ctx); err != nil {
@@ -56,8 +88,25 @@ func (g *GfSpBaseApp) signals(sigs ...os.Signal) {
 		case <-g.appCtx.Done():
 			return
 		case sig := <-sigCh:
+			if sig == syscall.SIGHUP && g.configLoader != nil {
+				log.Infow("receive reload signal, reload config")
+				go func() {
+					ctx := context.Background()
+					changed, err := g.ReloadConfig(ctx)
+					auditAdmin(ctx, "reload_config", g.appID, map[string]string{
+						"trigger": "signal", "changed_fields": strings.Join(changed, ",")}, err)
+				}()
+				continue
+			}
 			for _, j := range sigs {
 				if j == sig {
+					if g.drainOnSignal && !g.Draining() {
+						log.Infow("receive stop signal, drain services before stopping", "signal", sig.String())
+						g.Drain(0)
+						auditAdmin(context.Background(), "drain", g.appID,
+							map[string]string{"trigger": "signal", "signal": sig.String()}, nil)
+						break
+					}
 					g.appCancel()
 					return
 				}
@@ -66,6 +115,50 @@ func (g *GfSpBaseApp) signals(sigs ...os.Signal) {
 	}
 }
 
+// Drain stops the services accepting new work and waits for the in-flight work, the app
+// is stopped after all the services are drained or the timeout is exceeded. It returns
+// immediately, and zero timeout means the configured drain timeout.
+func (g *GfSpBaseApp) Drain(timeout time.Duration) {
+	if !atomic.CompareAndSwapInt32(&g.draining, 0, 1) {
+		return
+	}
+	if timeout <= 0 {
+		timeout = g.drainTimeout
+	}
+	go g.drainServices(timeout)
+}
+
+// Draining returns an indicator whether the app is draining, the modules should reject
+// the new work with a retryable error in draining.
+func (g *GfSpBaseApp) Draining() bool {
+	return atomic.LoadInt32(&g.draining) == 1
+}
+
+func (g *GfSpBaseApp) drainServices(timeout time.Duration) {
+	log.Infow("start to drain services", "timeout", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, service := range g.services {
+		drainable, ok := service.(corelifecycle.Drainable)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(name string, drainable corelifecycle.Drainable) {
+			defer wg.Done()
+			if err := drainable.Drain(ctx); err != nil {
+				log.Errorw("failed to drain service", "service_name", name, "error", err)
+			} else {
+				log.Infow("succeed to drain service", "service_name", name)
+			}
+		}(service.Name(), drainable)
+	}
+	wg.Wait()
+	log.Infow("finish to drain services, stop the app", "deadline_exceeded", ctx.Err() != nil)
+	g.appCancel()
+}
+
 // Wait blocks until context is done.
 func (g *GfSpBaseApp) Wait(ctx context.Context) {
 	<-g.appCtx.Done()
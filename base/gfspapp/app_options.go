@@ -1,9 +1,10 @@
 package gfspapp
 
 import (
-	"math"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
@@ -11,12 +12,13 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfsprcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gnfd"
-	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
 	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/pprof"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
 	"github.com/bnb-chain/greenfield-storage-provider/store/config"
 	piecestoreclient "github.com/bnb-chain/greenfield-storage-provider/store/piecestore/client"
@@ -50,6 +52,21 @@ const (
 	DefaultLowTaskLimit = 16
 )
 
+// DefaultGfSpTracingOption installs the tracer provider before the other options, so the
+// rpc server, the dbs and the modules created later are traced.
+func DefaultGfSpTracingOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
+	if !cfg.Tracing.Enable {
+		return nil
+	}
+	t, err := tracing.NewTracing(&cfg.Tracing, cfg.Server)
+	if err != nil {
+		log.Errorw("failed to new tracing", "error", err)
+		return err
+	}
+	app.tracing = t
+	return nil
+}
+
 func DefaultStaticOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	if len(cfg.Server) == 0 {
 		cfg.Server = GetRegisterModulus()
@@ -65,20 +82,23 @@ func DefaultStaticOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	app.grpcAddress = cfg.GRPCAddress
 	app.operatorAddress = cfg.SpAccount.SpOperatorAddress
 	app.chainID = cfg.Chain.ChainID
-	app.uploadSpeed = cfg.Task.UploadTaskSpeed
-	app.downloadSpeed = cfg.Task.DownloadTaskSpeed
-	app.replicateSpeed = cfg.Task.ReplicateTaskSpeed
-	app.receiveSpeed = cfg.Task.ReceiveTaskSpeed
-	app.sealObjectTimeout = cfg.Task.SealObjectTaskTimeout
-	app.gcObjectTimeout = cfg.Task.GcObjectTaskTimeout
-	app.gcZombieTimeout = cfg.Task.GcZombieTaskTimeout
-	app.gcMetaTimeout = cfg.Task.GcMetaTaskTimeout
-	app.sealObjectRetry = cfg.Task.SealObjectTaskRetry
-	app.replicateRetry = cfg.Task.ReplicateTaskRetry
-	app.receiveConfirmRetry = cfg.Task.ReceiveConfirmTaskRetry
-	app.gcObjectRetry = cfg.Task.GcObjectTaskRetry
-	app.gcZombieRetry = cfg.Task.GcZombieTaskRetry
-	app.gcMetaRetry = cfg.Task.GcMetaTaskRetry
+	app.setTaskConfig(&cfg.Task)
+	if cfg.Lifecycle.DrainTimeout == 0 {
+		cfg.Lifecycle.DrainTimeout = DefaultDrainTimeout
+	}
+	app.drainTimeout = time.Duration(cfg.Lifecycle.DrainTimeout) * time.Second
+	app.drainOnSignal = cfg.Lifecycle.DrainOnSignal
+	app.adminToken = cfg.Manager.AdminToken
+	if cfg.Customize.ConfigLoader != nil {
+		// the running config is loaded by the same loader to be compared with the reloaded
+		// config, the default values filled by initializing are excluded
+		runningConfig, err := cfg.Customize.ConfigLoader()
+		if err != nil {
+			return err
+		}
+		app.configLoader = cfg.Customize.ConfigLoader
+		app.runningConfig = runningConfig
+	}
 	app.approver = &coremodule.NullModular{}
 	app.authenticator = &coremodule.NullModular{}
 	app.downloader = &coremodule.NilModular{}
@@ -94,6 +114,18 @@ func DefaultStaticOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	return nil
 }
 
+// DefaultGfSpAuditOption initializes the audit log, the records are tagged with the app id.
+func DefaultGfSpAuditOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
+	if !cfg.Audit.Enable {
+		return nil
+	}
+	if err := audit.Init(&cfg.Audit, app.appID); err != nil {
+		log.Errorw("failed to init audit log", "error", err)
+		return err
+	}
+	return nil
+}
+
 func DefaultGfSpClientOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	if cfg.Endpoint.ApproverEndpoint == "" {
 		cfg.Endpoint.ApproverEndpoint = cfg.GRPCAddress
@@ -319,32 +351,80 @@ func DefaultGfSpResourceManagerOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConf
 		if cfg.Rcmgr.GfSpLimiter != nil {
 			cfg.Customize.RcLimiter = cfg.Rcmgr.GfSpLimiter
 		} else {
-			cfg.Customize.RcLimiter = &gfsplimit.GfSpLimiter{
-				System: &gfsplimit.GfSpLimit{
-					Memory:              int64(0.9 * float32(DefaultMemoryLimit)),
-					Tasks:               DefaultTaskTotalLimit,
-					TasksHighPriority:   DefaultHighTaskLimit,
-					TasksMediumPriority: DefaultMediumTaskLimit,
-					TasksLowPriority:    DefaultLowTaskLimit,
-					Fd:                  math.MaxInt32,
-					Conns:               math.MaxInt32,
-					ConnsInbound:        math.MaxInt32,
-					ConnsOutbound:       math.MaxInt32,
-				},
-			}
+			cfg.Customize.RcLimiter = defaultRcLimiter()
 		}
 	}
 	if cfg.Customize.Rcmgr == nil {
 		cfg.Customize.Rcmgr = gfsprcmgr.NewResourceManager(cfg.Customize.RcLimiter)
 	}
 	if !cfg.Rcmgr.DisableRcmgr {
+		if err := validateBandwidthLimit(cfg); err != nil {
+			return err
+		}
 		app.rcmgr = cfg.Customize.Rcmgr
+		setBandwidthLimit(app.rcmgr.BandwidthLimiter(), nil, cfg)
+		if cfg.Rcmgr.BandwidthLimit > 0 && !cfg.Rcmgr.DisableDownloadBorrowing && !hasServer(cfg.Server, coremodule.DownloadModularName) &&
+			hasServer(cfg.Server, bandwidthSenders...) {
+			log.Warnw("the downloads of other processes do not borrow the bandwidth limit of this process",
+				"server", cfg.Server)
+		}
 	} else {
 		app.rcmgr = &corercmgr.NullResourceManager{}
 	}
 	return nil
 }
 
+// bandwidthSenders defines the modules that send pieces to other sps under the bandwidth limit.
+var bandwidthSenders = []string{coremodule.ExecuteModularName, coremodule.UploadModularName, coremodule.GateModularName}
+
+// validateBandwidthLimit rejects the negative bandwidth limit of the destination sp.
+func validateBandwidthLimit(cfg *gfspconfig.GfSpConfig) error {
+	for sp, limit := range cfg.Rcmgr.BandwidthLimitBySp {
+		if limit < 0 {
+			return fmt.Errorf("the bandwidth limit of sp %s is negative: %d", sp, limit)
+		}
+	}
+	return nil
+}
+
+// setBandwidthLimit applies the bandwidth limits of the config to the limiter, the destination
+// sps that are removed from the running config are reset to the default limit per sp.
+func setBandwidthLimit(limiter corercmgr.BandwidthLimiter, running *gfspconfig.GfSpConfig, cfg *gfspconfig.GfSpConfig) {
+	limiter.SetLimit(cfg.Rcmgr.BandwidthLimit, cfg.Rcmgr.BandwidthLimitPerSp)
+	if running != nil {
+		for sp := range running.Rcmgr.BandwidthLimitBySp {
+			if _, ok := cfg.Rcmgr.BandwidthLimitBySp[sp]; !ok {
+				limiter.SetDestinationLimit(sp, -1)
+			}
+		}
+	}
+	for sp, limit := range cfg.Rcmgr.BandwidthLimitBySp {
+		limiter.SetDestinationLimit(sp, limit)
+	}
+}
+
+// DownloadBorrowingEnabled returns true if the downloads borrow the bandwidth limit of the
+// process. The limiter is shared by the modules in one process, so the downloads only borrow
+// it if any module that sends pieces runs in the same process as the downloader.
+func DownloadBorrowingEnabled(cfg *gfspconfig.GfSpConfig) bool {
+	if cfg.Rcmgr.DisableRcmgr || cfg.Rcmgr.DisableDownloadBorrowing {
+		return false
+	}
+	return hasServer(cfg.Server, coremodule.DownloadModularName) && hasServer(cfg.Server, bandwidthSenders...)
+}
+
+// hasServer returns true if any of the modules runs in the process.
+func hasServer(servers []string, modules ...string) bool {
+	for _, server := range servers {
+		for _, module := range modules {
+			if server == module {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func DefaultGfSpConsensusOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	if cfg.Customize.Consensus != nil {
 		app.chain = cfg.Customize.Consensus
@@ -356,9 +436,14 @@ func DefaultGfSpConsensusOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) er
 	if len(cfg.Chain.ChainAddress) == 0 {
 		cfg.Chain.ChainAddress = []string{DefaultChainAddress}
 	}
+	if cfg.Chain.MaxEventWaiters == 0 {
+		cfg.Chain.MaxEventWaiters = gnfd.DefaultMaxEventWaiters
+	}
 	gnfdCfg := &gnfd.GnfdChainConfig{
-		ChainID:      cfg.Chain.ChainID,
-		ChainAddress: cfg.Chain.ChainAddress,
+		ChainID:                  cfg.Chain.ChainID,
+		ChainAddress:             cfg.Chain.ChainAddress,
+		DisableEventSubscription: cfg.Chain.DisableEventSubscription,
+		MaxEventWaiters:          cfg.Chain.MaxEventWaiters,
 	}
 	chain, err := gnfd.NewGnfd(gnfdCfg)
 	if err != nil {
@@ -428,7 +513,9 @@ func DefaultGfSpPprofOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error
 }
 
 var gfspBaseAppDefaultOptions = []Option{
+	DefaultGfSpTracingOption,
 	DefaultStaticOption,
+	DefaultGfSpAuditOption,
 	DefaultGfSpClientOption,
 	DefaultGfSpDBOption,
 	DefaultGfBsDBOption,
@@ -0,0 +1,61 @@
+package gfspapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+)
+
+func TestDownloadBorrowingEnabled(t *testing.T) {
+	cfg := &gfspconfig.GfSpConfig{Server: []string{coremodule.DownloadModularName}}
+	assert.False(t, DownloadBorrowingEnabled(cfg))
+	for _, sender := range []string{coremodule.ExecuteModularName, coremodule.UploadModularName, coremodule.GateModularName} {
+		cfg.Server = []string{coremodule.DownloadModularName, sender}
+		assert.True(t, DownloadBorrowingEnabled(cfg))
+	}
+	cfg.Rcmgr.DisableDownloadBorrowing = true
+	assert.False(t, DownloadBorrowingEnabled(cfg))
+	cfg.Rcmgr.DisableDownloadBorrowing = false
+	cfg.Server = []string{coremodule.ExecuteModularName}
+	assert.False(t, DownloadBorrowingEnabled(cfg))
+}
+
+type mockBandwidthLimiter struct {
+	corercmgr.NullBandwidthLimiter
+	global, perDestination int64
+	destinations           map[string]int64
+}
+
+func (m *mockBandwidthLimiter) SetLimit(global int64, perDestination int64) {
+	m.global, m.perDestination = global, perDestination
+}
+
+func (m *mockBandwidthLimiter) SetDestinationLimit(dest string, limit int64) {
+	m.destinations[dest] = limit
+}
+
+func TestSetBandwidthLimit(t *testing.T) {
+	limiter := &mockBandwidthLimiter{destinations: make(map[string]int64)}
+	running := &gfspconfig.GfSpConfig{}
+	running.Rcmgr.BandwidthLimit = 1024
+	running.Rcmgr.BandwidthLimitBySp = map[string]int64{"sp1": 10, "sp2": 20}
+	assert.NoError(t, validateBandwidthLimit(running))
+	setBandwidthLimit(limiter, nil, running)
+	assert.Equal(t, int64(1024), limiter.global)
+	assert.Equal(t, map[string]int64{"sp1": 10, "sp2": 20}, limiter.destinations)
+
+	// the sp removed from the config is reset to the default limit per sp
+	cfg := &gfspconfig.GfSpConfig{}
+	cfg.Rcmgr.BandwidthLimitPerSp = 100
+	cfg.Rcmgr.BandwidthLimitBySp = map[string]int64{"sp2": 30}
+	setBandwidthLimit(limiter, running, cfg)
+	assert.Equal(t, int64(100), limiter.perDestination)
+	assert.Equal(t, map[string]int64{"sp1": -1, "sp2": 30}, limiter.destinations)
+
+	cfg.Rcmgr.BandwidthLimitBySp["sp3"] = -1
+	assert.Error(t, validateBandwidthLimit(cfg))
+}
@@ -0,0 +1,175 @@
+package gfspapp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var (
+	ErrReloadDisabled = gfsperrors.Register(BaseCodeSpace, http.StatusNotImplemented, 991301, "config reloading is disabled")
+	ErrReloadRejected = gfsperrors.Register(BaseCodeSpace, http.StatusBadRequest, 991302, "config reloading is rejected")
+	ErrReloadFailed   = gfsperrors.Register(BaseCodeSpace, http.StatusInternalServerError, 991303, "failed to reload config")
+)
+
+// Reloadable is the interface to the module that supports reloading the configuration at
+// runtime.
+type Reloadable interface {
+	// Reload applies the runtime changeable fields of the new configuration to the module,
+	// the new configuration is loaded from the config file without the default values, the
+	// module should fill the default values as initializing.
+	Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error
+}
+
+// ReloadValidator is the interface to the reloadable module that checks the new configuration
+// before reloading, the reloading is rejected before any field is applied if it fails.
+type ReloadValidator interface {
+	// ValidateReload checks the new configuration without applying it.
+	ValidateReload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error
+}
+
+// makeReloadError keeps the detail of the error in the description, so the operator knows
+// which fields are rejected.
+func makeReloadError(code *gfsperrors.GfSpError, err error) *gfsperrors.GfSpError {
+	return &gfsperrors.GfSpError{
+		CodeSpace:      code.GetCodeSpace(),
+		HttpStatusCode: code.GetHttpStatusCode(),
+		InnerCode:      code.GetInnerCode(),
+		Description:    code.GetDescription() + ": " + err.Error(),
+	}
+}
+
+// ReloadConfig loads the latest configuration and applies the changed fields to the base
+// app and the modules, it returns the changed fields. The reloading is rejected if any
+// changed field can not be changed at runtime or any value is invalid, all the values are
+// validated before any field is applied.
+func (g *GfSpBaseApp) ReloadConfig(ctx context.Context) ([]string, error) {
+	if g.configLoader == nil {
+		return nil, ErrReloadDisabled
+	}
+	g.reloadMux.Lock()
+	defer g.reloadMux.Unlock()
+	cfg, err := g.configLoader()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to load config for reloading", "error", err)
+		return nil, makeReloadError(ErrReloadFailed, err)
+	}
+	changed, err := gfspconfig.CheckReload(g.runningConfig, cfg)
+	if err != nil {
+		log.CtxErrorw(ctx, "reject to reload config", "error", err)
+		return changed, makeReloadError(ErrReloadRejected, err)
+	}
+	if len(changed) == 0 {
+		log.CtxInfow(ctx, "no changed config to reload")
+		return nil, nil
+	}
+	isChanged := func(field string) bool {
+		for _, c := range changed {
+			if c == field {
+				return true
+			}
+		}
+		return false
+	}
+	if err = g.validateReload(ctx, cfg); err != nil {
+		log.CtxErrorw(ctx, "reject to reload invalid config", "error", err)
+		return changed, makeReloadError(ErrReloadRejected, err)
+	}
+	if isChanged("Log.Level") && cfg.Log.Level != "" {
+		level, _ := log.ParseLevel(cfg.Log.Level)
+		log.SetLevel(level)
+	}
+	g.setTaskConfig(&cfg.Task)
+	if isChanged("Rcmgr.GfSpLimiter") {
+		limiter := defaultRcLimiter()
+		if cfg.Rcmgr.GfSpLimiter != nil {
+			limiter = cfg.Rcmgr.GfSpLimiter
+		}
+		g.rcmgr.SetLimiter(limiter)
+	}
+	setBandwidthLimit(g.rcmgr.BandwidthLimiter(), g.runningConfig, cfg)
+	var failed bool
+	// the modules fill the default values of the sections in the copy, the running config
+	// keeps the loaded values to be compared with the next reloading
+	moduleCfg := *cfg
+	for _, service := range g.services {
+		reloadable, ok := service.(Reloadable)
+		if !ok {
+			continue
+		}
+		if err = reloadable.Reload(ctx, &moduleCfg); err != nil {
+			log.CtxErrorw(ctx, "failed to reload service", "service_name", service.Name(), "error", err)
+			failed = true
+		}
+	}
+	if failed {
+		// keep the running config, the failed fields are applied again by the next reloading
+		return changed, ErrReloadFailed
+	}
+	g.runningConfig = cfg
+	log.CtxInfow(ctx, "succeed to reload config", "changed", changed)
+	return changed, nil
+}
+
+// validateReload checks the new configuration by the base app and the modules that validate
+// the configuration, nothing is applied.
+func (g *GfSpBaseApp) validateReload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if err := gfspconfig.ValidateReload(cfg); err != nil {
+		return err
+	}
+	if err := validateBandwidthLimit(cfg); err != nil {
+		return err
+	}
+	moduleCfg := *cfg
+	for _, service := range g.services {
+		validator, ok := service.(ReloadValidator)
+		if !ok {
+			continue
+		}
+		if err := validator.ValidateReload(ctx, &moduleCfg); err != nil {
+			return fmt.Errorf("%s: %w", service.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (g *GfSpBaseApp) setTaskConfig(cfg *gfspconfig.TaskConfig) {
+	g.taskMux.Lock()
+	defer g.taskMux.Unlock()
+	g.uploadSpeed = cfg.UploadTaskSpeed
+	g.downloadSpeed = cfg.DownloadTaskSpeed
+	g.replicateSpeed = cfg.ReplicateTaskSpeed
+	g.receiveSpeed = cfg.ReceiveTaskSpeed
+	g.sealObjectTimeout = cfg.SealObjectTaskTimeout
+	g.gcObjectTimeout = cfg.GcObjectTaskTimeout
+	g.gcZombieTimeout = cfg.GcZombieTaskTimeout
+	g.gcMetaTimeout = cfg.GcMetaTaskTimeout
+	g.sealObjectRetry = cfg.SealObjectTaskRetry
+	g.replicateRetry = cfg.ReplicateTaskRetry
+	g.receiveConfirmRetry = cfg.ReceiveConfirmTaskRetry
+	g.gcObjectRetry = cfg.GcObjectTaskRetry
+	g.gcZombieRetry = cfg.GcZombieTaskRetry
+	g.gcMetaRetry = cfg.GcMetaTaskRetry
+}
+
+func defaultRcLimiter() *gfsplimit.GfSpLimiter {
+	return &gfsplimit.GfSpLimiter{
+		System: &gfsplimit.GfSpLimit{
+			Memory:              int64(0.9 * float32(DefaultMemoryLimit)),
+			Tasks:               DefaultTaskTotalLimit,
+			TasksHighPriority:   DefaultHighTaskLimit,
+			TasksMediumPriority: DefaultMediumTaskLimit,
+			TasksLowPriority:    DefaultLowTaskLimit,
+			Fd:                  math.MaxInt32,
+			Conns:               math.MaxInt32,
+			ConnsInbound:        math.MaxInt32,
+			ConnsOutbound:       math.MaxInt32,
+		},
+	}
+}
@@ -0,0 +1,161 @@
+package gfspapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsprcmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+)
+
+type mockReloadService struct {
+	validateErr error
+	reloadErr   error
+	reloaded    int
+}
+
+func (*mockReloadService) Name() string                    { return "mock" }
+func (*mockReloadService) Start(ctx context.Context) error { return nil }
+func (*mockReloadService) Stop(ctx context.Context) error  { return nil }
+
+func (s *mockReloadService) ValidateReload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	return s.validateErr
+}
+
+func (s *mockReloadService) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	s.reloaded++
+	return s.reloadErr
+}
+
+func setupReloadApp(t *testing.T, loaded *gfspconfig.GfSpConfig) (*GfSpBaseApp, *mockReloadService) {
+	running := &gfspconfig.GfSpConfig{}
+	running.Task.UploadTaskSpeed = 100
+	service := &mockReloadService{}
+	app := &GfSpBaseApp{
+		rcmgr:         gfsprcmgr.NewResourceManager(defaultRcLimiter()),
+		runningConfig: running,
+		configLoader:  func() (*gfspconfig.GfSpConfig, error) { return loaded, nil },
+	}
+	app.setTaskConfig(&running.Task)
+	app.RegisterServices(service)
+	return app, service
+}
+
+func TestGfSpBaseApp_ReloadConfig(t *testing.T) {
+	errMock := errors.New("mock error")
+	cases := []struct {
+		name        string
+		modify      func(cfg *gfspconfig.GfSpConfig)
+		validateErr error
+		reloadErr   error
+		wantedErr   error
+		wantedSpeed int64
+		reloaded    int
+	}{
+		{
+			name:        "reload task speed",
+			modify:      func(cfg *gfspconfig.GfSpConfig) {},
+			wantedSpeed: 200,
+			reloaded:    1,
+		},
+		{
+			name:        "restart field is rejected",
+			modify:      func(cfg *gfspconfig.GfSpConfig) { cfg.GRPCAddress = "localhost:9333" },
+			wantedErr:   ErrReloadRejected,
+			wantedSpeed: 100,
+		},
+		{
+			name:        "invalid value is rejected before applying",
+			modify:      func(cfg *gfspconfig.GfSpConfig) { cfg.Parallel.GlobalSealObjectParallel = -1 },
+			wantedErr:   ErrReloadRejected,
+			wantedSpeed: 100,
+		},
+		{
+			name:        "invalid log level is rejected before applying",
+			modify:      func(cfg *gfspconfig.GfSpConfig) { cfg.Log.Level = "verbose" },
+			wantedErr:   ErrReloadRejected,
+			wantedSpeed: 100,
+		},
+		{
+			name:        "module validation is rejected before applying",
+			modify:      func(cfg *gfspconfig.GfSpConfig) {},
+			validateErr: errMock,
+			wantedErr:   ErrReloadRejected,
+			wantedSpeed: 100,
+		},
+		{
+			name:        "module reload fails",
+			modify:      func(cfg *gfspconfig.GfSpConfig) {},
+			reloadErr:   errMock,
+			wantedErr:   ErrReloadFailed,
+			wantedSpeed: 200,
+			reloaded:    1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			loaded := &gfspconfig.GfSpConfig{}
+			loaded.Task.UploadTaskSpeed = 200
+			c.modify(loaded)
+			app, service := setupReloadApp(t, loaded)
+			service.validateErr = c.validateErr
+			service.reloadErr = c.reloadErr
+
+			_, err := app.ReloadConfig(context.Background())
+			if c.wantedErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, loaded, app.runningConfig)
+			} else {
+				require.Error(t, err)
+				require.Equal(t, c.wantedErr.(interface{ GetInnerCode() int32 }).GetInnerCode(),
+					err.(interface{ GetInnerCode() int32 }).GetInnerCode())
+				require.NotEqual(t, loaded, app.runningConfig)
+			}
+			require.Equal(t, c.wantedSpeed, app.uploadSpeed)
+			require.Equal(t, c.reloaded, service.reloaded)
+		})
+	}
+
+	app := &GfSpBaseApp{}
+	_, err := app.ReloadConfig(context.Background())
+	require.Equal(t, ErrReloadDisabled, err)
+}
+
+func TestGfSpBaseApp_LifecycleRequiresAdminToken(t *testing.T) {
+	loaded := &gfspconfig.GfSpConfig{}
+	loaded.Task.UploadTaskSpeed = 200
+	app, service := setupReloadApp(t, loaded)
+	withToken := func(authorization string) context.Context {
+		return metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(gfspclient.AdminTokenMetadataKey, authorization))
+	}
+
+	// the admin api is disabled without the admin token configured
+	drainResp, err := app.GfSpDrain(withToken("Bearer token"), &gfspserver.GfSpDrainRequest{})
+	require.NoError(t, err)
+	require.Equal(t, ErrAdminDisabled.GetInnerCode(), drainResp.GetErr().GetInnerCode())
+	require.False(t, app.Draining())
+
+	app.adminToken = "token"
+	drainResp, err = app.GfSpDrain(context.Background(), &gfspserver.GfSpDrainRequest{})
+	require.NoError(t, err)
+	require.Equal(t, ErrAdminUnauthorized.GetInnerCode(), drainResp.GetErr().GetInnerCode())
+	require.False(t, app.Draining())
+
+	reloadResp, err := app.GfSpReloadConfig(withToken("token"), &gfspserver.GfSpReloadConfigRequest{})
+	require.NoError(t, err)
+	require.Equal(t, ErrAdminUnauthorized.GetInnerCode(), reloadResp.GetErr().GetInnerCode())
+	require.Equal(t, 0, service.reloaded)
+
+	reloadResp, err = app.GfSpReloadConfig(withToken("Bearer token"), &gfspserver.GfSpReloadConfigRequest{})
+	require.NoError(t, err)
+	require.Nil(t, reloadResp.GetErr())
+	require.Equal(t, []string{"Task.UploadTaskSpeed"}, reloadResp.GetChangedFields())
+	require.Equal(t, 1, service.reloaded)
+}
@@ -3,10 +3,12 @@ package gfspapp
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
 	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
 
@@ -34,6 +36,8 @@ func (g *GfSpBaseApp) GfSpAskApproval(ctx context.Context, req *gfspserver.GfSpA
 		}
 		defer span.Done()
 		allow, err := g.OnAskCreateBucketApproval(ctx, approvalTask)
+		auditApproval(ctx, "create_bucket", approvalTask.GetCreateBucketInfo().GetCreator(),
+			approvalTask.GetCreateBucketInfo().GetBucketName(), approvalTask.GetExpiredHeight(), allow, err)
 		return &gfspserver.GfSpAskApprovalResponse{
 			Err:     gfsperrors.MakeGfSpError(err),
 			Allowed: allow,
@@ -50,6 +54,9 @@ func (g *GfSpBaseApp) GfSpAskApproval(ctx context.Context, req *gfspserver.GfSpA
 		}
 		defer span.Done()
 		allow, err := g.OnAskCreateObjectApproval(ctx, approvalTask)
+		auditApproval(ctx, "create_object", approvalTask.GetCreateObjectInfo().GetCreator(),
+			approvalTask.GetCreateObjectInfo().GetBucketName()+"/"+approvalTask.GetCreateObjectInfo().GetObjectName(),
+			approvalTask.GetExpiredHeight(), allow, err)
 		return &gfspserver.GfSpAskApprovalResponse{
 			Err:     gfsperrors.MakeGfSpError(err),
 			Allowed: allow,
@@ -62,6 +69,28 @@ func (g *GfSpBaseApp) GfSpAskApproval(ctx context.Context, req *gfspserver.GfSpA
 	}
 }
 
+// auditApproval records the result of the approval, the approval signed by sp allows the
+// creator to create the bucket or object, or allows the primary sp to replicate the pieces
+// to the secondary sp, until the expired height.
+func auditApproval(ctx context.Context, action, creator, resource string, expiredHeight uint64,
+	allow bool, err error) {
+	event := &audit.Event{
+		Type:     audit.EventApproval,
+		Actor:    creator,
+		Action:   action,
+		Resource: resource,
+		Result:   audit.ResultSuccess,
+		Detail:   map[string]string{"expired_height": strconv.FormatUint(expiredHeight, 10)},
+	}
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.Detail["error"] = err.Error()
+	} else if !allow {
+		event.Result = audit.ResultDenied
+	}
+	audit.Emit(ctx, event)
+}
+
 func (g *GfSpBaseApp) OnAskCreateBucketApproval(ctx context.Context, task task.ApprovalCreateBucketTask) (bool, error) {
 	if task == nil || task.GetCreateBucketInfo() == nil {
 		log.CtxError(ctx, "failed to ask create bucket approval due to bucket info pointer dangling")
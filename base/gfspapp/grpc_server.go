@@ -30,6 +30,9 @@ func (g *GfSpBaseApp) newRpcServer(options ...grpc.ServerOption) {
 	if g.EnableMetrics() {
 		options = append(options, utilgrpc.GetDefaultServerInterceptor()...)
 	}
+	if g.EnableTracing() {
+		options = append(options, utilgrpc.GetTracingServerInterceptor()...)
+	}
 	g.server = grpc.NewServer(options...)
 	gfspserver.RegisterGfSpApprovalServiceServer(g.server, g)
 	gfspserver.RegisterGfSpAuthenticationServiceServer(g.server, g)
@@ -41,6 +44,7 @@ func (g *GfSpBaseApp) newRpcServer(options ...grpc.ServerOption) {
 	gfspserver.RegisterGfSpSignServiceServer(g.server, g)
 	gfspserver.RegisterGfSpUploadServiceServer(g.server, g)
 	gfspserver.RegisterGfSpQueryTaskServiceServer(g.server, g)
+	gfspserver.RegisterGfSpLifecycleServiceServer(g.server, g)
 	reflection.Register(g.server)
 }
 
@@ -0,0 +1,51 @@
+package gfspapp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var ErrAppDraining = gfsperrors.Register(BaseCodeSpace, http.StatusServiceUnavailable, 991201, "server is draining, try again later")
+
+var _ gfspserver.GfSpLifecycleServiceServer = &GfSpBaseApp{}
+
+// GfSpDrain starts draining the services of the app, the app exits after drained. The request
+// must carry the admin token.
+func (g *GfSpBaseApp) GfSpDrain(ctx context.Context, req *gfspserver.GfSpDrainRequest) (
+	*gfspserver.GfSpDrainResponse, error) {
+	log.CtxInfow(ctx, "receive drain request", "timeout", req.GetTimeout(),
+		"remote", GetRPCRemoteAddress(ctx))
+	if err := g.verifyAdminContext(ctx); err != nil {
+		auditAdmin(ctx, "drain", g.appID, map[string]string{"trigger": "rpc"}, err)
+		return &gfspserver.GfSpDrainResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	g.Drain(time.Duration(req.GetTimeout()) * time.Second)
+	auditAdmin(ctx, "drain", g.appID, map[string]string{
+		"trigger": "rpc", "timeout": strconv.FormatInt(req.GetTimeout(), 10)}, nil)
+	return &gfspserver.GfSpDrainResponse{Draining: g.Draining()}, nil
+}
+
+// GfSpReloadConfig reloads the configuration of the app, the changed fields that require
+// restarting are rejected. The request must carry the admin token.
+func (g *GfSpBaseApp) GfSpReloadConfig(ctx context.Context, req *gfspserver.GfSpReloadConfigRequest) (
+	*gfspserver.GfSpReloadConfigResponse, error) {
+	log.CtxInfow(ctx, "receive reload config request", "remote", GetRPCRemoteAddress(ctx))
+	if err := g.verifyAdminContext(ctx); err != nil {
+		auditAdmin(ctx, "reload_config", g.appID, map[string]string{"trigger": "rpc"}, err)
+		return &gfspserver.GfSpReloadConfigResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	changed, err := g.ReloadConfig(ctx)
+	auditAdmin(ctx, "reload_config", g.appID, map[string]string{
+		"trigger": "rpc", "changed_fields": strings.Join(changed, ",")}, err)
+	return &gfspserver.GfSpReloadConfigResponse{
+		Err:           gfsperrors.MakeGfSpError(err),
+		ChangedFields: changed,
+	}, nil
+}
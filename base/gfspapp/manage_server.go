@@ -285,3 +285,29 @@ func (g *GfSpBaseApp) GfSpReportTask(ctx context.Context, req *gfspserver.GfSpRe
 	log.CtxInfow(ctx, "succeed to handle reported task")
 	return &gfspserver.GfSpReportTaskResponse{}, nil
 }
+
+func (g *GfSpBaseApp) GfSpRecoverSp(ctx context.Context, req *gfspserver.GfSpRecoverSpRequest) (
+	*gfspserver.GfSpRecoverSpResponse, error) {
+	job, err := g.manager.HandleRecoverSpJob(ctx, req.GetAction())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to handle recover sp job", "action", req.GetAction(), "error", err)
+		return &gfspserver.GfSpRecoverSpResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	resp := &gfspserver.GfSpRecoverSpResponse{}
+	if job != nil {
+		resp.Job = &gfspserver.GfSpRecoverSpJob{
+			JobId:                 job.JobID,
+			Status:                job.Status,
+			Cursor:                job.Cursor,
+			ScannedObjects:        job.ScannedObjects,
+			GeneratedTasks:        job.GeneratedTasks,
+			SkippedPieces:         job.SkippedPieces,
+			SucceedPieces:         job.SucceedPieces,
+			FailedPieces:          job.FailedPieces,
+			CreateTimestampSecond: job.CreateTimestampSecond,
+			UpdateTimestampSecond: job.UpdateTimestampSecond,
+		}
+	}
+	log.CtxInfow(ctx, "succeed to handle recover sp job", "action", req.GetAction())
+	return resp, nil
+}
@@ -66,3 +66,19 @@ func (g *GfSpBaseApp) GfSpDoneReplicatePiece(ctx context.Context, req *gfspserve
 		Signature:     signature,
 	}, nil
 }
+
+func (g *GfSpBaseApp) GfSpDiscardReplicatePiece(ctx context.Context, req *gfspserver.GfSpDiscardReplicatePieceRequest) (
+	*gfspserver.GfSpDiscardReplicatePieceResponse, error) {
+	task := req.GetReceivePieceTask()
+	if task == nil {
+		log.Error("failed to discard receive piece due to task pointer dangling")
+		return &gfspserver.GfSpDiscardReplicatePieceResponse{Err: ErrReceiveTaskDangling}, nil
+	}
+	ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
+	if err := g.receiver.HandleDiscardReceivePieceTask(ctx, task); err != nil {
+		log.CtxErrorw(ctx, "failed to discard replicate piece", "error", err)
+		return &gfspserver.GfSpDiscardReplicatePieceResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	log.CtxDebugw(ctx, "succeed to discard replicate pieces")
+	return &gfspserver.GfSpDiscardReplicatePieceResponse{}, nil
+}
@@ -75,6 +75,8 @@ const (
 // TaskTimeout returns the task timeout by task type and some task need payload size
 // to compute, example: upload, download, etc.
 func (g *GfSpBaseApp) TaskTimeout(task coretask.Task, size uint64) int64 {
+	g.taskMux.RLock()
+	defer g.taskMux.RUnlock()
 	switch task.Type() {
 	case coretask.TypeTaskCreateBucketApproval:
 		return NotUseTimeout
@@ -174,6 +176,8 @@ func (g *GfSpBaseApp) TaskTimeout(task coretask.Task, size uint64) int64 {
 
 // TaskMaxRetry returns the task max retry by task type.
 func (g *GfSpBaseApp) TaskMaxRetry(task coretask.Task) int64 {
+	g.taskMux.RLock()
+	defer g.taskMux.RUnlock()
 	switch task.Type() {
 	case coretask.TypeTaskCreateBucketApproval:
 		return NotUseRetry
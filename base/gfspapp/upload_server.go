@@ -13,6 +13,7 @@ import (
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 )
 
 var (
@@ -24,6 +25,9 @@ var (
 var _ gfspserver.GfSpUploadServiceServer = &GfSpBaseApp{}
 
 func (g *GfSpBaseApp) GfSpUploadObject(stream gfspserver.GfSpUploadService_GfSpUploadObjectServer) error {
+	if g.Draining() {
+		return stream.SendAndClose(&gfspserver.GfSpUploadObjectResponse{Err: ErrAppDraining})
+	}
 	var (
 		span          rcmgr.ResourceScopeSpan
 		task          *gfsptask.GfSpUploadObjectTask
@@ -31,7 +35,7 @@ func (g *GfSpBaseApp) GfSpUploadObject(stream gfspserver.GfSpUploadService_GfSpU
 		resp          = &gfspserver.GfSpUploadObjectResponse{}
 		pRead, pWrite = io.Pipe()
 		initCh        = make(chan struct{})
-		ctx, cancel   = context.WithCancel(context.Background())
+		ctx, cancel   = context.WithCancel(tracing.DetachContext(stream.Context()))
 		err           error
 		receiveSize   int
 	)
@@ -103,6 +107,9 @@ func (g *GfSpBaseApp) GfSpUploadObject(stream gfspserver.GfSpUploadService_GfSpU
 					pWrite.CloseWithError(err)
 					return
 				}
+				if task.GetTraceParent() == "" {
+					task.SetTraceParent(tracing.TraceParent(ctx))
+				}
 				g.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.UploaderBeginReceiveData, task.Key().String())
 				ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
 				span, err = g.uploader.ReserveResource(ctx, task.EstimateLimit().ScopeStat())
@@ -144,6 +151,9 @@ func (g *GfSpBaseApp) GfSpUploadObject(stream gfspserver.GfSpUploadService_GfSpU
 }
 
 func (g *GfSpBaseApp) GfSpResumableUploadObject(stream gfspserver.GfSpUploadService_GfSpResumableUploadObjectServer) error {
+	if g.Draining() {
+		return stream.SendAndClose(&gfspserver.GfSpResumableUploadObjectResponse{Err: ErrAppDraining})
+	}
 	var (
 		span          rcmgr.ResourceScopeSpan
 		task          *gfsptask.GfSpResumableUploadObjectTask
@@ -151,7 +161,7 @@ func (g *GfSpBaseApp) GfSpResumableUploadObject(stream gfspserver.GfSpUploadServ
 		resp          = &gfspserver.GfSpResumableUploadObjectResponse{}
 		pRead, pWrite = io.Pipe()
 		initCh        = make(chan struct{})
-		ctx, cancel   = context.WithCancel(context.Background())
+		ctx, cancel   = context.WithCancel(tracing.DetachContext(stream.Context()))
 		err           error
 		receiveSize   int
 	)
@@ -221,6 +231,9 @@ func (g *GfSpBaseApp) GfSpResumableUploadObject(stream gfspserver.GfSpUploadServ
 					pWrite.CloseWithError(err)
 					return
 				}
+				if task.GetTraceParent() == "" {
+					task.SetTraceParent(tracing.TraceParent(ctx))
+				}
 				ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
 				span, err = g.uploader.ReserveResource(ctx, task.EstimateLimit().ScopeStat())
 				if err != nil {
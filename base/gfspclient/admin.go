@@ -0,0 +1,59 @@
+package gfspclient
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// AdminTokenMetadataKey defines the grpc metadata key of the admin token.
+	AdminTokenMetadataKey = "authorization"
+	// AdminTokenScheme defines the scheme prefix of the admin token.
+	AdminTokenScheme = "Bearer "
+)
+
+func withAdminToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, AdminTokenMetadataKey, AdminTokenScheme+token)
+}
+
+// ListTasks lists the tasks that hold on manager by the filter of request.
+func (s *GfSpClient) ListTasks(ctx context.Context, token string, req *gfspserver.GfSpListTasksRequest) (
+	[]*gfspserver.GfSpAdminTask, []string, error) {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return nil, nil, ErrRpcUnknown
+	}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpListTasks(withAdminToken(ctx, token), req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to list tasks", "error", err)
+		return nil, nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, nil, resp.GetErr()
+	}
+	return resp.GetTasks(), resp.GetPausedTaskTypes(), nil
+}
+
+// ControlTask cancels, retries, reprioritizes the task or pauses, resumes dispatching the
+// type of tasks on manager.
+func (s *GfSpClient) ControlTask(ctx context.Context, token string, req *gfspserver.GfSpControlTaskRequest) error {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return ErrRpcUnknown
+	}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpControlTask(withAdminToken(ctx, token), req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to control task", "action", req.GetAction(), "error", err)
+		return ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetErr()
+	}
+	return nil
+}
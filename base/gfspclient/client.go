@@ -81,6 +81,8 @@ func NewGfSpClient(
 
 func (s *GfSpClient) Connection(ctx context.Context, address string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	options := append(DefaultClientOptions(), opts...)
+	// the interceptor does nothing but propagating the context if tracing is disabled
+	options = append(options, utilgrpc.GetTracingClientInterceptor()...)
 	return grpc.DialContext(ctx, address, options...)
 }
 
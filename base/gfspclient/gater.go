@@ -138,3 +138,38 @@ func (s *GfSpClient) DoneReplicatePieceToSecondary(ctx context.Context, endpoint
 	}
 	return integrity, signature, nil
 }
+
+// DiscardReplicatePieceToSecondary asks the secondary SP to discard the received pieces
+// of the replicate index, it shares the replicate piece path with the DELETE method.
+func (s *GfSpClient) DiscardReplicatePieceToSecondary(ctx context.Context, endpoint string,
+	approval coretask.ApprovalReplicatePieceTask, receive coretask.ReceivePieceTask) error {
+	req, err := http.NewRequest(http.MethodDelete, endpoint+ReplicateObjectPiecePath, nil)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to connect gateway", "endpoint", endpoint, "error", err)
+		return err
+	}
+	approvalTask := approval.(*gfsptask.GfSpReplicatePieceApprovalTask)
+	approvalMsg, err := json.Marshal(approvalTask)
+	if err != nil {
+		return err
+	}
+	approvalHeader := hex.EncodeToString(approvalMsg)
+
+	receiveTask := receive.(*gfsptask.GfSpReceivePieceTask)
+	receiveMsg, err := json.Marshal(receiveTask)
+	if err != nil {
+		return err
+	}
+	receiveHeader := hex.EncodeToString(receiveMsg)
+	req.Header.Add(GnfdReplicatePieceApprovalHeader, approvalHeader)
+	req.Header.Add(GnfdReceiveMsgHeader, receiveHeader)
+	resp, err := s.HTTPClient(ctx).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to discard replicate piece, StatusCode(%d) Endpoint(%s)", resp.StatusCode, endpoint)
+	}
+	return nil
+}
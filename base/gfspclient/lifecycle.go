@@ -0,0 +1,51 @@
+package gfspclient
+
+import (
+	"context"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// Drain asks the gfsp server of the endpoint to drain and exit, the timeout is in seconds.
+func (s *GfSpClient) Drain(ctx context.Context, endpoint string, token string, timeout int64) (bool, error) {
+	conn, connErr := s.Connection(ctx, endpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect gfsp server", "error", connErr)
+		return false, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &gfspserver.GfSpDrainRequest{
+		Timeout: timeout,
+	}
+	resp, err := gfspserver.NewGfSpLifecycleServiceClient(conn).GfSpDrain(withAdminToken(ctx, token), req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to drain", "error", err)
+		return false, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return false, resp.GetErr()
+	}
+	return resp.GetDraining(), nil
+}
+
+// ReloadConfig asks the gfsp server of the endpoint to reload the configuration, it returns
+// the changed fields.
+func (s *GfSpClient) ReloadConfig(ctx context.Context, endpoint string, token string) ([]string, error) {
+	conn, connErr := s.Connection(ctx, endpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect gfsp server", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	resp, err := gfspserver.NewGfSpLifecycleServiceClient(conn).GfSpReloadConfig(withAdminToken(ctx, token),
+		&gfspserver.GfSpReloadConfigRequest{})
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to reload config", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetChangedFields(), resp.GetErr()
+	}
+	return resp.GetChangedFields(), nil
+}
@@ -163,3 +163,23 @@ func (s *GfSpClient) ReportTask(ctx context.Context, report coretask.Task) error
 	}
 	return resp.GetErr()
 }
+
+// RecoverSp starts, pauses, resumes or queries the full sp recover job, it returns the
+// latest recover job, the job is nil if there is no job.
+func (s *GfSpClient) RecoverSp(ctx context.Context, action string) (*gfspserver.GfSpRecoverSpJob, error) {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	req := &gfspserver.GfSpRecoverSpRequest{Action: action}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpRecoverSp(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to recover sp", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, resp.GetErr()
+	}
+	return resp.GetJob(), nil
+}
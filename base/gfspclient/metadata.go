@@ -7,6 +7,7 @@ import (
 
 	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
 	payment_types "github.com/bnb-chain/greenfield/x/payment/types"
 	permission_types "github.com/bnb-chain/greenfield/x/permission/types"
 	storage_types "github.com/bnb-chain/greenfield/x/storage/types"
@@ -65,9 +66,19 @@ func (s *GfSpClient) GetUserBuckets(ctx context.Context, account string, include
 	return resp.GetBuckets(), nil
 }
 
-// ListObjectsByBucketName list objects info by a bucket name
+// listObjectsSortBy maps the sort field of bsdb to the sort field of the request
+var listObjectsSortBy = map[string]types.ListObjectsSortBy{
+	"":                     types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_OBJECT_NAME,
+	bsdb.SortByObjectName:  types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_OBJECT_NAME,
+	bsdb.SortByCreateTime:  types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_CREATE_TIME,
+	bsdb.SortByPayloadSize: types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_PAYLOAD_SIZE,
+}
+
+// ListObjectsByBucketName list objects info by a bucket name, the nil filter lists the objects
+// in the order of object name
 func (s *GfSpClient) ListObjectsByBucketName(ctx context.Context, bucketName string, accountId string, maxKeys uint64,
-	startAfter string, continuationToken string, delimiter string, prefix string, includeRemoved bool, opts ...grpc.DialOption) (
+	startAfter string, continuationToken string, delimiter string, prefix string, includeRemoved bool,
+	filter *bsdb.ListObjectsFilter, opts ...grpc.DialOption) (
 	objects []*types.Object, KeyCount uint64, MaxKeys uint64, IsTruncated bool, NextContinuationToken string,
 	Name string, Prefix string, Delimiter string, CommonPrefixes []string, ContinuationToken string, err error) {
 	conn, err := s.Connection(ctx, s.metadataEndpoint, opts...)
@@ -86,6 +97,17 @@ func (s *GfSpClient) ListObjectsByBucketName(ctx context.Context, bucketName str
 		Prefix:            prefix,
 		IncludeRemoved:    includeRemoved,
 	}
+	if filter != nil {
+		req.ContentType = filter.ContentType
+		req.MinPayloadSize = filter.MinPayloadSize
+		req.MaxPayloadSize = filter.MaxPayloadSize
+		req.StartCreateTime = filter.StartCreateTime
+		req.EndCreateTime = filter.EndCreateTime
+		req.ObjectStatus = filter.ObjectStatus
+		req.Owner = filter.Owner
+		req.SortBy = listObjectsSortBy[filter.SortBy]
+		req.SortDesc = filter.SortDesc
+	}
 
 	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpListObjectsByBucketName(ctx, req)
 	ctx = log.Context(ctx, resp)
@@ -353,6 +375,30 @@ func (s *GfSpClient) ListBucketReadRecord(ctx context.Context, bucket *storage_t
 	return resp.GetReadRecords(), resp.GetNextStartTimestampUs(), nil
 }
 
+func (s *GfSpClient) GetBucketReadUsage(ctx context.Context, bucket *storage_types.BucketInfo, startDay, endDay string,
+	opts ...grpc.DialOption) ([]*types.BucketReadUsage, error) {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &types.GfSpGetBucketReadUsageRequest{
+		BucketInfo: bucket,
+		StartDay:   startDay,
+		EndDay:     endDay,
+	}
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpGetBucketReadUsage(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to get bucket read usage", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, resp.GetErr()
+	}
+	return resp.GetUsages(), nil
+}
+
 func (s *GfSpClient) GetUploadObjectState(ctx context.Context, objectID uint64, opts ...grpc.DialOption) (int32, error) {
 	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
 	if connErr != nil {
@@ -463,3 +509,111 @@ func (s *GfSpClient) ListObjectsByObjectID(ctx context.Context, objectIDs []uint
 	}
 	return resp.Objects, nil
 }
+
+func (s *GfSpClient) ListObjectsBySp(ctx context.Context, spAddress string, startID uint64, limit int64, opts ...grpc.DialOption) ([]*types.Object, uint64, error) {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return nil, 0, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &types.GfSpListObjectsBySpRequest{
+		SpAddress: spAddress,
+		StartId:   startID,
+		Limit:     limit,
+	}
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpListObjectsBySp(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to list objects by sp", "error", err)
+		return nil, 0, ErrRpcUnknown
+	}
+	return resp.GetObjects(), resp.GetEndId(), nil
+}
+
+// GetBucketStats get the storage stats of a bucket
+func (s *GfSpClient) GetBucketStats(ctx context.Context, bucketName string, opts ...grpc.DialOption) (
+	*types.GfSpGetBucketStatsResponse, error) {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &types.GfSpGetBucketStatsRequest{
+		BucketName: bucketName,
+	}
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpGetBucketStats(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to get bucket stats", "error", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAccountStats get the storage stats of the objects owned by an account
+func (s *GfSpClient) GetAccountStats(ctx context.Context, accountAddress string, opts ...grpc.DialOption) (
+	*types.GfSpGetAccountStatsResponse, error) {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &types.GfSpGetAccountStatsRequest{
+		AccountAddress: accountAddress,
+	}
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpGetAccountStats(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to get account stats", "error", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetBlockSyncerStatus get the sync progress, mode and speed of the block syncer
+func (s *GfSpClient) GetBlockSyncerStatus(ctx context.Context, opts ...grpc.DialOption) (
+	*types.GfSpGetBlockSyncerStatusResponse, error) {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpGetBlockSyncerStatus(ctx, &types.GfSpGetBlockSyncerStatusRequest{})
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to get block syncer status", "error", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// StreamChangeEvents streams the change events after the cursor, the handler is called with every
+// received response and the response without events is the heartbeat. It returns when the ctx is
+// done, the stream is broken or the handler returns an error.
+func (s *GfSpClient) StreamChangeEvents(ctx context.Context, req *types.GfSpStreamChangeEventsRequest,
+	handler func(*types.GfSpStreamChangeEventsResponse) error, opts ...grpc.DialOption) error {
+	conn, connErr := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect metadata", "error", connErr)
+		return ErrRpcUnknown
+	}
+	defer conn.Close()
+	stream, err := types.NewGfSpMetadataServiceClient(conn).GfSpStreamChangeEvents(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to stream change events", "error", err)
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.CtxErrorw(ctx, "client failed to receive change events", "error", err)
+			return err
+		}
+		if err = handler(resp); err != nil {
+			return err
+		}
+	}
+}
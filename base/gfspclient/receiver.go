@@ -61,3 +61,24 @@ func (s *GfSpClient) DoneReplicatePiece(ctx context.Context, task coretask.Recei
 	}
 	return resp.GetIntegrityHash(), resp.GetSignature(), nil
 }
+
+func (s *GfSpClient) DiscardReplicatePiece(ctx context.Context, task coretask.ReceivePieceTask, opts ...grpc.DialOption) error {
+	conn, connErr := s.Connection(ctx, s.receiverEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect receiver", "error", connErr)
+		return ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &gfspserver.GfSpDiscardReplicatePieceRequest{
+		ReceivePieceTask: task.(*gfsptask.GfSpReceivePieceTask),
+	}
+	resp, err := gfspserver.NewGfSpReceiveServiceClient(conn).GfSpDiscardReplicatePiece(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to discard replicate piece", "error", err)
+		return ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetErr()
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretaskqueue "github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	storeconfig "github.com/bnb-chain/greenfield-storage-provider/store/config"
 	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
 )
@@ -29,6 +31,9 @@ type Customize struct {
 	NewTQueueWithLimit             coretaskqueue.NewTQueueWithLimit
 	NewStrategyTQueueFunc          coretaskqueue.NewTQueueOnStrategy
 	NewStrategyTQueueWithLimitFunc coretaskqueue.NewTQueueOnStrategyWithLimit
+	// ConfigLoader loads the latest configuration for reloading, reloading is disabled
+	// if it is nil.
+	ConfigLoader func() (*GfSpConfig, error)
 }
 
 // GfSpConfig defines the GfSp configuration.
@@ -47,6 +52,7 @@ type GfSpConfig struct {
 	Approval       ApprovalConfig
 	Bucket         BucketConfig
 	Gateway        GatewayConfig
+	Uploader       UploaderConfig
 	Executor       ExecutorConfig
 	P2P            P2PConfig
 	Parallel       ParallelConfig
@@ -58,6 +64,9 @@ type GfSpConfig struct {
 	BlockSyncer    BlockSyncerConfig
 	APIRateLimiter localhttp.RateLimiterConfig
 	Manager        ManagerConfig
+	Lifecycle      LifecycleConfig
+	Tracing        tracing.TracingConfig
+	Audit          audit.AuditConfig
 }
 
 // Apply sets the customized implement to the GfSp configuration, it will be called
@@ -92,6 +101,8 @@ type ChainConfig struct {
 	RejectSealFeeAmount        uint64
 	DiscontinueBucketGasLimit  uint64
 	DiscontinueBucketFeeAmount uint64
+	DisableEventSubscription   bool
+	MaxEventWaiters            int
 }
 
 type SpAccountConfig struct {
@@ -133,6 +144,15 @@ type GatewayConfig struct {
 	HTTPAddress string
 }
 
+type UploaderConfig struct {
+	// EnablePipelineReplicate enables replicating the pieces to secondary sps while
+	// uploading, the executor only replicates the missing pieces after uploading.
+	EnablePipelineReplicate bool
+	// PipelineReplicateQueueSize defines the max number of segments waiting to be
+	// replicated, the segments exceeding the queue are replicated by executor.
+	PipelineReplicateQueueSize int
+}
+
 type ExecutorConfig struct {
 	MaxExecuteNumber             int64
 	AskTaskInterval              int
@@ -141,6 +161,17 @@ type ExecutorConfig struct {
 	ListenSealTimeoutHeight      int
 	ListenSealRetryTimeout       int
 	MaxListenSealRetry           int
+	MaxReplicatePieceRetry       int64
+	// SecondaryScoreStrategy defines the strategy to rank the secondary sp approvals,
+	// supports FirstCome and Weighted.
+	SecondaryScoreStrategy  string
+	SecondaryCapacityWeight float64
+	SecondaryLoadWeight     float64
+	SecondarySuccessWeight  float64
+	SecondaryLatencyWeight  float64
+	// RecoveryHedgeDelay defines the seconds to wait for the slow recovery source before
+	// sending the hedged request to the other sources.
+	RecoveryHedgeDelay int64
 }
 
 type P2PConfig struct {
@@ -149,6 +180,9 @@ type P2PConfig struct {
 	P2PAntAddress string
 	P2PBootstrap  []string
 	P2PPingPeriod int
+	// SupportedRedundancy defines the redundancy types that sp supports as secondary sp,
+	// e.g. REDUNDANCY_EC_TYPE, REDUNDANCY_REPLICA_TYPE, empty means all.
+	SupportedRedundancy []string
 }
 
 type ParallelConfig struct {
@@ -209,6 +243,21 @@ type MonitorConfig struct {
 type RcmgrConfig struct {
 	DisableRcmgr bool
 	GfSpLimiter  *gfsplimit.GfSpLimiter
+	// BandwidthLimit defines the per-process outbound bandwidth limit of replicating and
+	// recovering pieces in bytes per second, zero means unlimited. Every process enforces
+	// its own limit, so the outbound bandwidth of the sp is the limit multiplied by the
+	// number of processes that send pieces.
+	BandwidthLimit int64
+	// BandwidthLimitPerSp defines the per-process outbound bandwidth limit to every
+	// destination sp in bytes per second, zero means unlimited.
+	BandwidthLimitPerSp int64
+	// BandwidthLimitBySp overrides BandwidthLimitPerSp of the destination sp, the key is the
+	// operator address of the sp and the value is bytes per second, zero means unlimited.
+	BandwidthLimitBySp map[string]int64
+	// DisableDownloadBorrowing disables the user downloads borrowing the bandwidth from
+	// BandwidthLimit. The downloads only borrow the limit of the process if the downloader
+	// runs with the executor, the uploader or the gater, which send pieces to other sps.
+	DisableDownloadBorrowing bool
 }
 
 type LogConfig struct {
@@ -217,11 +266,26 @@ type LogConfig struct {
 }
 
 type BlockSyncerConfig struct {
-	Modules      []string
-	Dsn          string
-	DsnSwitched  string
+	Modules     []string
+	Dsn         string
+	DsnSwitched string
+	// Workers defines the number of goroutines to fetch the blocks in the catch-up mode if
+	// CatchUpConcurrency is not set.
 	Workers      uint
 	EnableDualDB bool
+	// EnableCatchUp enables the catch-up mode when the block syncer lags behind the chain, the
+	// blocks are fetched concurrently and applied in order by batches, and the block syncer
+	// switches to follow the tip of the chain when it catches up, it enters the catch-up mode
+	// again if it falls behind. Without the catch-up mode, the blocks are synced one by one.
+	EnableCatchUp bool
+	// CatchUpConcurrency defines the number of goroutines to fetch the blocks in the catch-up mode.
+	CatchUpConcurrency uint
+	// CatchUpPrefetch defines the max number of the fetched blocks that are not applied yet.
+	CatchUpPrefetch uint
+	// CatchUpBatchSize defines the max number of the blocks that are applied in a db transaction.
+	CatchUpBatchSize uint
+	// CatchUpThreshold defines the lag of blocks under which the block syncer follows the tip.
+	CatchUpThreshold uint
 }
 
 type MetadataConfig struct {
@@ -231,5 +295,32 @@ type MetadataConfig struct {
 }
 
 type ManagerConfig struct {
-	EnableLoadTask bool
+	// EnableLoadTask loads the replicate, seal and gc tasks from the progress in sp db at start,
+	// it resumes the tasks left in the queues by the drained or stopped manager.
+	EnableLoadTask     bool
+	RecoverSpBatchSize int
+	RecoverSpInterval  int
+	// AdminToken defines the bearer token of the admin api, including the drain and the
+	// config reload rpc of every process, the admin api is disabled if it is empty.
+	AdminToken string
+	// AdminHTTPAddress defines the listen address of the admin http server on manager,
+	// the http server is disabled if it is empty.
+	AdminHTTPAddress string
+	// EnableWebhook enables delivering the events of the buckets to the webhook subscriptions.
+	EnableWebhook bool
+	// WebhookMaxAttempts defines the max attempts of delivering an event, the delivery is
+	// moved to the dead letter table after the attempts are exhausted.
+	WebhookMaxAttempts int
+	// WebhookRetryInterval defines the interval in seconds before the first retry of a failed
+	// delivery, the interval doubles at every retry.
+	WebhookRetryInterval int
+}
+
+type LifecycleConfig struct {
+	// DrainTimeout defines the deadline in seconds of draining the modules, the process
+	// exits after the modules are drained or the deadline is exceeded.
+	DrainTimeout int64
+	// DrainOnSignal enables draining the modules before stopping on the stop signal, the
+	// second signal stops the process immediately.
+	DrainOnSignal bool
 }
@@ -139,3 +139,16 @@ func CustomizeStrategyTQueueWithLimit(newFunc coretaskqueue.NewTQueueOnStrategyW
 		return nil
 	}
 }
+
+func CustomizeConfigLoader(loader func() (*GfSpConfig, error)) Option {
+	return func(cfg *GfSpConfig) error {
+		if cfg.Customize == nil {
+			cfg.Customize = &Customize{}
+		}
+		if cfg.Customize.ConfigLoader != nil {
+			return errors.New("repeated set config loader")
+		}
+		cfg.Customize.ConfigLoader = loader
+		return nil
+	}
+}
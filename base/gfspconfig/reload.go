@@ -0,0 +1,147 @@
+package gfspconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// reloadableFields defines the fields that can be changed at runtime by reloading, the
+// field ending with "." covers all the fields of the section.
+var reloadableFields = []string{
+	"Parallel.GlobalMaxUploadingParallel",
+	"Parallel.GlobalUploadObjectParallel",
+	"Parallel.GlobalReplicatePieceParallel",
+	"Parallel.GlobalSealObjectParallel",
+	"Parallel.GlobalReceiveObjectParallel",
+	"Parallel.GlobalGCObjectParallel",
+	"Parallel.GlobalGCZombieParallel",
+	"Parallel.GlobalGCMetaParallel",
+	"Parallel.GlobalDownloadObjectTaskCacheSize",
+	"Parallel.GlobalChallengePieceTaskCacheSize",
+	"Parallel.GlobalRecoveryPieceParallel",
+	"Parallel.UploadObjectParallelPerNode",
+	"Parallel.ReceivePieceParallelPerNode",
+	"Parallel.DownloadObjectParallelPerNode",
+	"Parallel.ChallengePieceParallelPerNode",
+	"Parallel.AskReplicateApprovalParallelPerNode",
+	"Parallel.QuerySPParallelPerNode",
+	"Task.",
+	"APIRateLimiter.",
+	"Rcmgr.GfSpLimiter",
+	"Rcmgr.BandwidthLimit",
+	"Rcmgr.BandwidthLimitPerSp",
+	"Rcmgr.BandwidthLimitBySp",
+	"Log.Level",
+}
+
+// Diff returns the names of the fields that differ between the two configurations, the
+// name is the path of the field separated by ".", e.g. "Parallel.GlobalSealObjectParallel".
+// The customized implements are ignored.
+func Diff(old *GfSpConfig, new *GfSpConfig) []string {
+	var changed []string
+	diffValue(reflect.ValueOf(*old), reflect.ValueOf(*new), "", &changed)
+	return changed
+}
+
+func diffValue(old reflect.Value, new reflect.Value, path string, changed *[]string) {
+	if old.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			*changed = append(*changed, path)
+		}
+		return
+	}
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() || field.Type == reflect.TypeOf(&Customize{}) {
+			continue
+		}
+		name := field.Name
+		if path != "" {
+			name = path + "." + field.Name
+		}
+		diffValue(old.Field(i), new.Field(i), name, changed)
+	}
+}
+
+// IsReloadable returns an indicator whether the field can be changed by reloading.
+func IsReloadable(field string) bool {
+	for _, reloadable := range reloadableFields {
+		if field == reloadable || (strings.HasSuffix(reloadable, ".") && strings.HasPrefix(field, reloadable)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckReload returns the changed fields between the running and the new configurations,
+// it returns error if any changed field requires restarting.
+func CheckReload(running *GfSpConfig, new *GfSpConfig) ([]string, error) {
+	changed := Diff(running, new)
+	var restart []string
+	for _, field := range changed {
+		if !IsReloadable(field) {
+			restart = append(restart, field)
+		}
+	}
+	if len(restart) > 0 {
+		return changed, fmt.Errorf("the changed fields %s can not be reloaded, restart is required",
+			strings.Join(restart, ", "))
+	}
+	return changed, nil
+}
+
+// ValidateReload checks the values of the reloadable fields of the new configuration, the
+// numbers must not be negative and the log level must be known, so the reloading can be
+// rejected before any field is applied.
+func ValidateReload(cfg *GfSpConfig) error {
+	var invalid []string
+	root := reflect.ValueOf(*cfg)
+	for _, field := range reloadableFields {
+		path := strings.TrimSuffix(field, ".")
+		value := root
+		for _, name := range strings.Split(path, ".") {
+			value = value.FieldByName(name)
+		}
+		validateValue(value, path, &invalid)
+	}
+	if cfg.Log.Level != "" {
+		if _, err := log.ParseLevel(cfg.Log.Level); err != nil {
+			invalid = append(invalid, "Log.Level")
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("the fields %s are invalid", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func validateValue(value reflect.Value, path string, invalid *[]string) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			*invalid = append(*invalid, path)
+		}
+	case reflect.Pointer:
+		if !value.IsNil() {
+			validateValue(value.Elem(), path, invalid)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s.%v", path, iter.Key()), invalid)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s.%d", path, i), invalid)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				validateValue(value.Field(i), path+"."+field.Name, invalid)
+			}
+		}
+	}
+}
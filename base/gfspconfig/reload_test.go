@@ -0,0 +1,81 @@
+package gfspconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+)
+
+func TestCheckReload(t *testing.T) {
+	running := &GfSpConfig{Customize: &Customize{}}
+	running.Parallel.GlobalSealObjectParallel = 10
+	running.Log.Level = "debug"
+
+	cfg := &GfSpConfig{}
+	cfg.Parallel.GlobalSealObjectParallel = 20
+	cfg.Log.Level = "info"
+	cfg.Task.UploadTaskSpeed = 100
+	cfg.Rcmgr.GfSpLimiter = &gfsplimit.GfSpLimiter{System: &gfsplimit.GfSpLimit{Memory: 1}}
+	cfg.APIRateLimiter.PathPattern = []localhttp.RateLimiterCell{{Key: "/", RateLimit: 1, RatePeriod: "S"}}
+	changed, err := CheckReload(running, cfg)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Parallel.GlobalSealObjectParallel", "Log.Level", "Task.UploadTaskSpeed",
+		"Rcmgr.GfSpLimiter", "APIRateLimiter.PathPattern"}, changed)
+
+	cfg.GRPCAddress = "localhost:9333"
+	cfg.Parallel.GlobalBatchGcObjectTimeInterval = 1
+	changed, err = CheckReload(running, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPCAddress, Parallel.GlobalBatchGcObjectTimeInterval")
+	assert.Len(t, changed, 7)
+}
+
+func TestValidateReload(t *testing.T) {
+	cases := []struct {
+		name    string
+		modify  func(cfg *GfSpConfig)
+		invalid string
+	}{
+		{name: "valid", modify: func(cfg *GfSpConfig) {}},
+		{name: "negative parallel", modify: func(cfg *GfSpConfig) {
+			cfg.Parallel.GlobalSealObjectParallel = -1
+		}, invalid: "Parallel.GlobalSealObjectParallel"},
+		{name: "negative task", modify: func(cfg *GfSpConfig) {
+			cfg.Task.ReplicateTaskRetry = -1
+		}, invalid: "Task.ReplicateTaskRetry"},
+		{name: "negative bandwidth", modify: func(cfg *GfSpConfig) {
+			cfg.Rcmgr.BandwidthLimitPerSp = -1
+		}, invalid: "Rcmgr.BandwidthLimitPerSp"},
+		{name: "negative service limit", modify: func(cfg *GfSpConfig) {
+			cfg.Rcmgr.GfSpLimiter = &gfsplimit.GfSpLimiter{
+				ServiceLimit: map[string]*gfsplimit.GfSpLimit{"uploader": {Tasks: -1}}}
+		}, invalid: "Rcmgr.GfSpLimiter.ServiceLimit.uploader.Tasks"},
+		{name: "negative rate limit", modify: func(cfg *GfSpConfig) {
+			cfg.APIRateLimiter.PathPattern = []localhttp.RateLimiterCell{{Key: "/", RateLimit: -1, RatePeriod: "S"}}
+		}, invalid: "APIRateLimiter.PathPattern.0.RateLimit"},
+		{name: "unknown log level", modify: func(cfg *GfSpConfig) {
+			cfg.Log.Level = "verbose"
+		}, invalid: "Log.Level"},
+		{name: "restart field is not validated", modify: func(cfg *GfSpConfig) {
+			cfg.Parallel.GlobalBatchGcObjectTimeInterval = -1
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &GfSpConfig{}
+			cfg.Parallel.GlobalSealObjectParallel = 10
+			cfg.Log.Level = "info"
+			c.modify(cfg)
+			err := ValidateReload(cfg)
+			if c.invalid == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), c.invalid)
+		})
+	}
+}
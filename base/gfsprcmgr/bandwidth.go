@@ -0,0 +1,158 @@
+package gfsprcmgr
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+)
+
+var _ corercmgr.BandwidthLimiter = &bandwidthLimiter{}
+
+// bandwidthLimiter shapes the outbound traffic by token buckets, the burst of bucket
+// is the limit of one second.
+type bandwidthLimiter struct {
+	global         *rate.Limiter
+	globalLimit    int64
+	perDestination int64
+	destinations   map[string]*rate.Limiter
+	overrides      map[string]int64
+	mux            sync.RWMutex
+}
+
+func newBandwidthLimiter(global int64, perDestination int64) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		global:         newRateLimiter(global),
+		globalLimit:    global,
+		perDestination: perDestination,
+		destinations:   make(map[string]*rate.Limiter),
+		overrides:      make(map[string]int64),
+	}
+}
+
+func newRateLimiter(limit int64) *rate.Limiter {
+	l := rate.NewLimiter(rate.Inf, 0)
+	setRateLimit(l, limit)
+	return l
+}
+
+// setRateLimit adjusts the limiter in place, keeps the tokens that have been borrowed.
+func setRateLimit(l *rate.Limiter, limit int64) {
+	if limit <= 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	burst := limit
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	l.SetBurst(int(burst))
+	l.SetLimit(rate.Limit(limit))
+}
+
+// WaitN blocks until n bytes can be sent to the destination at the priority. The high
+// priority traffic reserves the tokens from global bucket without waiting.
+func (b *bandwidthLimiter) WaitN(ctx context.Context, dest string, n int, prio corercmgr.ReserveTaskPriority) error {
+	global := b.global
+	if prio == corercmgr.ReserveTaskPriorityHigh {
+		for _, chunk := range splitChunks(n, global.Burst()) {
+			global.ReserveN(time.Now(), chunk)
+		}
+		return nil
+	}
+	var destLimiter *rate.Limiter
+	if dest != "" {
+		destLimiter = b.destination(dest)
+	}
+	for _, chunk := range splitChunks(n, global.Burst()) {
+		if destLimiter != nil {
+			for _, destChunk := range splitChunks(chunk, destLimiter.Burst()) {
+				if err := destLimiter.WaitN(ctx, destChunk); err != nil {
+					return err
+				}
+			}
+		}
+		if err := global.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitChunks splits n into the chunks not exceeding burst, the limiter rejects the
+// tokens exceeding its burst at once.
+func splitChunks(n int, burst int) []int {
+	if n <= 0 {
+		return nil
+	}
+	if burst <= 0 || n <= burst {
+		return []int{n}
+	}
+	chunks := make([]int, 0, n/burst+1)
+	for n > burst {
+		chunks = append(chunks, burst)
+		n -= burst
+	}
+	return append(chunks, n)
+}
+
+func (b *bandwidthLimiter) destination(dest string) *rate.Limiter {
+	b.mux.RLock()
+	l, ok := b.destinations[dest]
+	b.mux.RUnlock()
+	if ok {
+		return l
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if l, ok = b.destinations[dest]; ok {
+		return l
+	}
+	limit, ok := b.overrides[dest]
+	if !ok {
+		limit = b.perDestination
+	}
+	l = newRateLimiter(limit)
+	b.destinations[dest] = l
+	return l
+}
+
+// SetLimit adjusts the global limit and the default limit per destination at runtime.
+func (b *bandwidthLimiter) SetLimit(global int64, perDestination int64) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.globalLimit = global
+	b.perDestination = perDestination
+	setRateLimit(b.global, global)
+	for dest, l := range b.destinations {
+		if _, ok := b.overrides[dest]; !ok {
+			setRateLimit(l, perDestination)
+		}
+	}
+}
+
+// SetDestinationLimit overrides the limit of the destination at runtime.
+func (b *bandwidthLimiter) SetDestinationLimit(dest string, limit int64) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if limit < 0 {
+		delete(b.overrides, dest)
+		limit = b.perDestination
+	} else {
+		b.overrides[dest] = limit
+	}
+	if l, ok := b.destinations[dest]; ok {
+		setRateLimit(l, limit)
+	}
+}
+
+// Limit returns the global limit and the default limit per destination.
+func (b *bandwidthLimiter) Limit() (int64, int64) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.globalLimit, b.perDestination
+}
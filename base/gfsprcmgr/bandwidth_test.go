@@ -0,0 +1,45 @@
+package gfsprcmgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+)
+
+func TestSplitChunks(t *testing.T) {
+	require.Nil(t, splitChunks(0, 10))
+	require.Equal(t, []int{5}, splitChunks(5, 0))
+	require.Equal(t, []int{10, 10, 5}, splitChunks(25, 10))
+}
+
+func TestBandwidthLimiter(t *testing.T) {
+	limiter := newBandwidthLimiter(0, 0)
+	ctx := context.Background()
+	// unlimited by default
+	require.NoError(t, limiter.WaitN(ctx, "sp", 1<<30, corercmgr.ReserveTaskPriorityLow))
+
+	limiter.SetLimit(1000, 0)
+	global, perDestination := limiter.Limit()
+	require.Equal(t, int64(1000), global)
+	require.Equal(t, int64(0), perDestination)
+	// the high priority traffic borrows the tokens without waiting
+	start := time.Now()
+	require.NoError(t, limiter.WaitN(ctx, "", 2000, corercmgr.ReserveTaskPriorityHigh))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	// the low priority traffic waits until the borrowed tokens are refilled
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.Error(t, limiter.WaitN(timeoutCtx, "sp", 100, corercmgr.ReserveTaskPriorityLow))
+
+	limiter.SetLimit(0, 100)
+	limiter.SetDestinationLimit("fast", 0)
+	require.NoError(t, limiter.WaitN(ctx, "fast", 1000, corercmgr.ReserveTaskPriorityLow))
+	require.NoError(t, limiter.WaitN(ctx, "slow", 100, corercmgr.ReserveTaskPriorityLow))
+	timeoutCtx, cancel = context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.Error(t, limiter.WaitN(timeoutCtx, "slow", 100, corercmgr.ReserveTaskPriorityLow))
+}
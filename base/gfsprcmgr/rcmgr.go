@@ -12,6 +12,7 @@ type resourceManager struct {
 	limits    corercmgr.Limiter
 	system    *resourceScope
 	transient *resourceScope
+	bandwidth *bandwidthLimiter
 
 	svc map[string]*resourceScope
 	mux sync.Mutex
@@ -21,8 +22,9 @@ var _ corercmgr.ResourceManager = &resourceManager{}
 
 func NewResourceManager(limits corercmgr.Limiter) corercmgr.ResourceManager {
 	r := &resourceManager{
-		limits: limits,
-		svc:    make(map[string]*resourceScope),
+		limits:    limits,
+		bandwidth: newBandwidthLimiter(0, 0),
+		svc:       make(map[string]*resourceScope),
 	}
 	r.system = newResourceScope(limits.GetSystemLimits(), nil, "system")
 	// TODO:: support transient resource scope
@@ -45,6 +47,35 @@ func (r *resourceManager) OpenService(name string) (corercmgr.ResourceScope, err
 	return scope, nil
 }
 
+// BandwidthLimiter returns the limiter to shape the outbound traffic between SPs,
+// it is unlimited by default.
+func (r *resourceManager) BandwidthLimiter() corercmgr.BandwidthLimiter {
+	return r.bandwidth
+}
+
+// SetLimiter replaces the limits of the system and service scopes, the service that has
+// no own limits shares the system limits.
+func (r *resourceManager) SetLimiter(limits corercmgr.Limiter) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.limits = limits
+	system := limits.GetSystemLimits()
+	r.system.setLimit(system)
+	for name, scope := range r.svc {
+		limit := limits.GetServiceLimits(name)
+		if limit == nil {
+			limit = system
+		}
+		scope.setLimit(limit)
+	}
+}
+
+func (r *resourceManager) currentLimits() corercmgr.Limiter {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.limits
+}
+
 // Close closes the resource manager
 func (r *resourceManager) Close() error {
 	return nil
@@ -75,14 +106,14 @@ func (r *resourceManager) ViewService(name string, f func(corercmgr.ResourceScop
 // SystemState output the system resource scope and limit readable
 func (r *resourceManager) SystemState() string {
 	state := r.system.Stat().String()
-	limit := r.limits.GetSystemLimits().String()
+	limit := r.currentLimits().GetSystemLimits().String()
 	return "use: " + state + "limit: " + limit
 }
 
 // TransientState output the transient (DMZ)  resource scope and limit readable
 func (r *resourceManager) TransientState() string {
 	state := r.transient.Stat().String()
-	limit := r.limits.GetTransientLimits().String()
+	limit := r.currentLimits().GetTransientLimits().String()
 	return "use: " + state + "limit: " + limit
 }
 
@@ -93,10 +124,11 @@ func (r *resourceManager) ServiceState(name string) string {
 		return ""
 	}
 	state := scop.Stat().String()
-	limit := r.limits.GetServiceLimits(state)
+	limits := r.currentLimits()
+	limit := limits.GetServiceLimits(state)
 	var limitState string
 	if limit == nil {
-		limitState = r.limits.GetSystemLimits().String()
+		limitState = limits.GetSystemLimits().String()
 	} else {
 		limitState = limit.String()
 	}
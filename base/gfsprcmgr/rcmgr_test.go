@@ -0,0 +1,49 @@
+package gfsprcmgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+)
+
+func TestResourceManager_SetLimiter(t *testing.T) {
+	rcmgr := NewResourceManager(&gfsplimit.GfSpLimiter{
+		System: &gfsplimit.GfSpLimit{Tasks: 2, TasksLowPriority: 2},
+		ServiceLimit: map[string]*gfsplimit.GfSpLimit{
+			"limited": {Tasks: 1, TasksLowPriority: 1},
+		},
+	})
+	limited, err := rcmgr.OpenService("limited")
+	require.NoError(t, err)
+	shared, err := rcmgr.OpenService("shared")
+	require.NoError(t, err)
+
+	require.NoError(t, limited.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.Error(t, limited.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.NoError(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	// the system limit is exhausted by the two services
+	require.Error(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+
+	// the reserved tasks are kept, and the new limits apply to the later reservations
+	rcmgr.SetLimiter(&gfsplimit.GfSpLimiter{
+		System: &gfsplimit.GfSpLimit{Tasks: 4, TasksLowPriority: 4},
+		ServiceLimit: map[string]*gfsplimit.GfSpLimit{
+			"limited": {Tasks: 2, TasksLowPriority: 2},
+		},
+	})
+	require.NoError(t, limited.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.Error(t, limited.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.NoError(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.Error(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+
+	// the service without own limits falls back to the system limits after the limits are removed
+	rcmgr.SetLimiter(&gfsplimit.GfSpLimiter{
+		System: &gfsplimit.GfSpLimit{Tasks: 6, TasksLowPriority: 6},
+	})
+	require.NoError(t, limited.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.NoError(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+	require.Error(t, shared.AddTask(1, corercmgr.ReserveTaskPriorityLow))
+}
@@ -56,6 +56,13 @@ func newResourceScopeSpan(owner *resourceScope, id int, name string) *resourceSc
 	return r
 }
 
+// setLimit replaces the limit of the scope, the reserved resources are kept.
+func (s *resourceScope) setLimit(limit corercmgr.Limit) {
+	s.Lock()
+	defer s.Unlock()
+	s.rc.limit = limit
+}
+
 // BeginSpan creates a new span scope rooted at this scope.
 func (s *resourceScope) BeginSpan() (corercmgr.ResourceScopeSpan, error) {
 	s.Lock()
@@ -55,9 +55,19 @@ func (t *GfSpTQueue) Len() int {
 
 // Cap returns the capacity of queue.
 func (t *GfSpTQueue) Cap() int {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
 	return t.cap
 }
 
+// SetCap adjusts the capacity of queue.
+func (t *GfSpTQueue) SetCap(cap int) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.cap = cap
+	metrics.QueueCapGauge.WithLabelValues(t.name).Set(float64(cap))
+}
+
 // Has returns an indicator whether the task in queue.
 func (t *GfSpTQueue) Has(key coretask.TKey) bool {
 	t.mux.Lock()
@@ -192,6 +202,7 @@ func (t *GfSpTQueue) top() coretask.Task {
 	if len(backupTasks) == 0 {
 		return nil
 	}
+	backupTasks = highestPriorityTasks(backupTasks)
 	sort.Slice(backupTasks, func(i, j int) bool {
 		return backupTasks[i].GetCreateTime() < backupTasks[j].GetCreateTime()
 	})
@@ -205,6 +216,25 @@ func (t *GfSpTQueue) top() coretask.Task {
 	return backupTasks[index]
 }
 
+// highestPriorityTasks returns the tasks of the highest priority, so the task whose priority
+// is raised by the admin is dispatched before the others, the tasks of the same priority are
+// dispatched in the order of the create time.
+func highestPriorityTasks(tasks []coretask.Task) []coretask.Task {
+	highest := tasks[0].GetPriority()
+	for _, task := range tasks[1:] {
+		if task.GetPriority() > highest {
+			highest = task.GetPriority()
+		}
+	}
+	result := make([]coretask.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.GetPriority() == highest {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
 // SetFilterTaskStrategy sets the callback func to filter task for popping or topping.
 func (t *GfSpTQueue) SetFilterTaskStrategy(filter func(coretask.Task) bool) {
 	t.mux.Lock()
@@ -228,3 +258,15 @@ func (t *GfSpTQueue) ScanTask(scan func(coretask.Task)) {
 		scan(task)
 	}
 }
+
+// UpdateTask calls the func with the task of the key while holding the queue lock.
+func (t *GfSpTQueue) UpdateTask(key coretask.TKey, update func(coretask.Task)) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	task, ok := t.tasks[key]
+	if !ok {
+		return false
+	}
+	update(task)
+	return true
+}
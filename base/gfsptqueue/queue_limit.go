@@ -45,9 +45,19 @@ func (t *GfSpTQueueWithLimit) Len() int {
 
 // Cap returns the capacity of queue.
 func (t *GfSpTQueueWithLimit) Cap() int {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
 	return t.cap
 }
 
+// SetCap adjusts the capacity of queue.
+func (t *GfSpTQueueWithLimit) SetCap(cap int) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.cap = cap
+	metrics.QueueCapGauge.WithLabelValues(t.name).Set(float64(cap))
+}
+
 // Has returns an indicator whether the task in queue.
 func (t *GfSpTQueueWithLimit) Has(key coretask.TKey) bool {
 	// maybe gc task, need RWLock, not RLock
@@ -185,6 +195,7 @@ func (t *GfSpTQueueWithLimit) topByLimit(limit corercmgr.Limit) coretask.Task {
 	if len(backupTasks) == 0 {
 		return nil
 	}
+	backupTasks = highestPriorityTasks(backupTasks)
 	sort.Slice(backupTasks, func(i, j int) bool {
 		return backupTasks[i].GetCreateTime() < backupTasks[j].GetCreateTime()
 	})
@@ -221,3 +232,15 @@ func (t *GfSpTQueueWithLimit) ScanTask(scan func(coretask.Task)) {
 		scan(task)
 	}
 }
+
+// UpdateTask calls the func with the task of the key while holding the queue lock.
+func (t *GfSpTQueueWithLimit) UpdateTask(key coretask.TKey, update func(coretask.Task)) bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	task, ok := t.tasks[key]
+	if !ok {
+		return false
+	}
+	update(task)
+	return true
+}
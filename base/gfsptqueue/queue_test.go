@@ -6,6 +6,7 @@ import (
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
 	"github.com/stretchr/testify/require"
 
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/task"
 )
@@ -183,3 +184,72 @@ func TestReplicateTaskRetireByExpired(t *testing.T) {
 		})
 	}
 }
+
+func TestTQueueSetCap(t *testing.T) {
+	newTask := func(name string) task.Task {
+		return &gfsptask.GfSpCreateObjectApprovalTask{
+			CreateObjectInfo: &storagetypes.MsgCreateObject{ObjectName: name},
+		}
+	}
+	queues := []struct {
+		name  string
+		queue interface {
+			Push(task.Task) error
+			Len() int
+			Cap() int
+			SetCap(int)
+			PopByKey(task.TKey) task.Task
+		}
+	}{
+		{name: "queue", queue: NewGfSpTQueue("test", 1)},
+		{name: "queue with limit", queue: NewGfSpTQueueWithLimit("test", 1)},
+	}
+	for _, q := range queues {
+		t.Run(q.name, func(t *testing.T) {
+			task1, task2, task3 := newTask("task1"), newTask("task2"), newTask("task3")
+			require.NoError(t, q.queue.Push(task1))
+			require.Error(t, q.queue.Push(task2))
+
+			q.queue.SetCap(3)
+			require.Equal(t, 3, q.queue.Cap())
+			require.NoError(t, q.queue.Push(task2))
+			require.NoError(t, q.queue.Push(task3))
+
+			// the tasks exceeding the smaller capacity are kept until popped
+			q.queue.SetCap(1)
+			require.Equal(t, 3, q.queue.Len())
+			require.Error(t, q.queue.Push(newTask("task4")))
+			q.queue.PopByKey(task1.Key())
+			q.queue.PopByKey(task2.Key())
+			require.Error(t, q.queue.Push(newTask("task4")))
+			q.queue.PopByKey(task3.Key())
+			require.NoError(t, q.queue.Push(newTask("task4")))
+		})
+	}
+}
+
+func TestTQueuePopByPriority(t *testing.T) {
+	newTask := func(name string, createTime int64, priority task.TPriority) task.Task {
+		return &gfsptask.GfSpReplicatePieceTask{
+			ObjectInfo:    &storagetypes.ObjectInfo{ObjectName: name},
+			StorageParams: &storagetypes.Params{},
+			Task:          &gfsptask.GfSpTask{CreateTime: createTime, TaskPriority: int32(priority)},
+		}
+	}
+	limit := &gfsplimit.GfSpLimit{Memory: 1 << 30, Tasks: 100, TasksHighPriority: 100,
+		TasksMediumPriority: 100, TasksLowPriority: 100, Fd: 100, Conns: 100, ConnsInbound: 100, ConnsOutbound: 100}
+
+	queue := NewGfSpTQueue("test_priority_queue", 3)
+	limitQueue := NewGfSpTQueueWithLimit("test_priority_limit_queue", 3)
+	early, late, latest := newTask("early", 1, 10), newTask("late", 2, 20), newTask("latest", 3, 10)
+	for _, qTask := range []task.Task{early, late, latest} {
+		require.NoError(t, queue.Push(qTask))
+		require.NoError(t, limitQueue.Push(qTask))
+	}
+
+	// the task of higher priority is popped first even if it is created later
+	require.Equal(t, late.Key(), queue.Pop().Key())
+	require.Equal(t, late.Key(), limitQueue.PopByLimit(limit).Key())
+	require.Equal(t, 2, queue.Len())
+	require.Equal(t, 2, limitQueue.Len())
+}
@@ -46,6 +46,12 @@ var _ consensus.Consensus = &Gnfd{}
 type GnfdChainConfig struct {
 	ChainID      string
 	ChainAddress []string
+	// DisableEventSubscription disables the object event subscription, the seal
+	// and reject seal object confirmation falls back to polling.
+	DisableEventSubscription bool
+	// MaxEventWaiters defines the max number of objects waiting for events at the
+	// same time, the exceeded waiters fall back to polling.
+	MaxEventWaiters int
 }
 
 type Gnfd struct {
@@ -53,6 +59,7 @@ type Gnfd struct {
 	backUpClients   []*GreenfieldClient
 	wsClient        *chttp.HTTP
 	backUpWsClients []*chttp.HTTP
+	eventSubscriber *objectEventSubscriber
 	stopCh          chan struct{}
 	mutex           sync.RWMutex
 }
@@ -89,6 +96,11 @@ func NewGnfd(cfg *GnfdChainConfig) (*Gnfd, error) {
 	}
 
 	go greenfield.updateClient()
+	if !cfg.DisableEventSubscription {
+		greenfield.eventSubscriber = newObjectEventSubscriber(cfg.MaxEventWaiters,
+			greenfield.getCurrentWsClient, greenfield.stopCh)
+		go greenfield.eventSubscriber.run()
+	}
 	return greenfield, nil
 }
 
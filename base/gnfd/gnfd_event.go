@@ -0,0 +1,303 @@
+package gnfd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chttp "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// DefaultMaxEventWaiters defines the default max number of objects that can wait
+	// for seal or reject events at the same time, the exceeded waiters fall back to polling.
+	DefaultMaxEventWaiters = 10240
+	// EventSubscriber defines the subscriber name of the object event subscription.
+	EventSubscriber = "gfsp-object-event-subscriber"
+	// EventSubscribeCapacity defines the buffer size of the subscription channel.
+	EventSubscribeCapacity = 1024
+	// EventResubscribeInterval defines the interval of re-subscribing after the
+	// subscription is broken.
+	EventResubscribeInterval = 5
+	// EventHealthCheckBlocks defines the number of expected blocks without any new block
+	// header event after which the subscription is considered unhealthy.
+	EventHealthCheckBlocks = 5
+
+	// NewBlockHeaderQuery is used to check the subscription liveness.
+	NewBlockHeaderQuery = "tm.event='NewBlockHeader'"
+	// SealObjectEventQuery is used to subscribe the seal object events.
+	SealObjectEventQuery = "tm.event='Tx' AND greenfield.storage.EventSealObject.object_id EXISTS"
+	// RejectSealObjectEventQuery is used to subscribe the reject seal object events.
+	RejectSealObjectEventQuery = "tm.event='Tx' AND greenfield.storage.EventRejectSealObject.object_id EXISTS"
+
+	sealObjectIDEventKey       = "greenfield.storage.EventSealObject.object_id"
+	rejectSealObjectIDEventKey = "greenfield.storage.EventRejectSealObject.object_id"
+)
+
+// ObjectEventType defines the type of the object event that the waiter is interested in.
+type ObjectEventType int32
+
+const (
+	// ObjectEventSealed is emitted after the object is sealed on chain.
+	ObjectEventSealed ObjectEventType = iota + 1
+	// ObjectEventRejected is emitted after the object seal is rejected on chain.
+	ObjectEventRejected
+)
+
+// objectEventWaiters is the bounded registry of waiters keyed by object id.
+type objectEventWaiters struct {
+	mux      sync.Mutex
+	waiters  map[uint64][]chan ObjectEventType
+	size     int
+	capacity int
+}
+
+func newObjectEventWaiters(capacity int) *objectEventWaiters {
+	if capacity <= 0 {
+		capacity = DefaultMaxEventWaiters
+	}
+	return &objectEventWaiters{
+		waiters:  make(map[uint64][]chan ObjectEventType),
+		capacity: capacity,
+	}
+}
+
+// register adds a waiter for the object, returns false if the registry is full.
+func (w *objectEventWaiters) register(objectID uint64) (chan ObjectEventType, bool) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.size >= w.capacity {
+		return nil, false
+	}
+	ch := make(chan ObjectEventType, 1)
+	w.waiters[objectID] = append(w.waiters[objectID], ch)
+	w.size++
+	return ch, true
+}
+
+// unregister removes the waiter from the registry.
+func (w *objectEventWaiters) unregister(objectID uint64, ch chan ObjectEventType) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	chs := w.waiters[objectID]
+	for i, c := range chs {
+		if c == ch {
+			chs = append(chs[:i], chs[i+1:]...)
+			w.size--
+			break
+		}
+	}
+	if len(chs) == 0 {
+		delete(w.waiters, objectID)
+		return
+	}
+	w.waiters[objectID] = chs
+}
+
+// notify wakes up all the waiters of the object.
+func (w *objectEventWaiters) notify(objectID uint64, event ObjectEventType) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	for _, ch := range w.waiters[objectID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// objectEventSubscriber subscribes the seal and reject seal object events from
+// the greenfield websocket, and dispatches them to the waiters.
+type objectEventSubscriber struct {
+	waiters *objectEventWaiters
+	mux     sync.RWMutex
+	healthy bool
+	// brokenCh is closed once the subscription turns to unhealthy, it lets the
+	// waiters fall back to polling immediately.
+	brokenCh      chan struct{}
+	lastHeaderAt  time.Time
+	getWsClient   func() *chttp.HTTP
+	currentClient *chttp.HTTP
+	stopCh        chan struct{}
+}
+
+func newObjectEventSubscriber(maxWaiters int, getWsClient func() *chttp.HTTP,
+	stopCh chan struct{}) *objectEventSubscriber {
+	brokenCh := make(chan struct{})
+	close(brokenCh)
+	return &objectEventSubscriber{
+		waiters:     newObjectEventWaiters(maxWaiters),
+		brokenCh:    brokenCh,
+		getWsClient: getWsClient,
+		stopCh:      stopCh,
+	}
+}
+
+// isHealthy returns the subscription status and the channel that will be closed
+// when the subscription is broken.
+func (s *objectEventSubscriber) isHealthy() (bool, <-chan struct{}) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.healthy, s.brokenCh
+}
+
+func (s *objectEventSubscriber) setHealthy() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.lastHeaderAt = time.Now()
+	if s.healthy {
+		return
+	}
+	s.healthy = true
+	s.brokenCh = make(chan struct{})
+}
+
+func (s *objectEventSubscriber) setUnhealthy() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.healthy {
+		return
+	}
+	s.healthy = false
+	close(s.brokenCh)
+}
+
+// run keeps the subscription alive until stopCh is closed.
+func (s *objectEventSubscriber) run() {
+	for {
+		if err := s.subscribe(); err != nil {
+			log.Errorw("failed to subscribe object events, fall back to polling", "error", err)
+		}
+		s.setUnhealthy()
+		s.unsubscribe()
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(EventResubscribeInterval * time.Second):
+		}
+	}
+}
+
+// subscribe subscribes the events and dispatches them, it returns once the
+// subscription is considered broken.
+func (s *objectEventSubscriber) subscribe() error {
+	client := s.getWsClient()
+	s.currentClient = client
+	if !client.IsRunning() {
+		if err := client.Start(); err != nil {
+			return err
+		}
+	}
+	ctx := context.Background()
+	headerCh, err := client.Subscribe(ctx, EventSubscriber, NewBlockHeaderQuery, EventSubscribeCapacity)
+	if err != nil {
+		return err
+	}
+	sealCh, err := client.Subscribe(ctx, EventSubscriber, SealObjectEventQuery, EventSubscribeCapacity)
+	if err != nil {
+		return err
+	}
+	rejectCh, err := client.Subscribe(ctx, EventSubscriber, RejectSealObjectEventQuery, EventSubscribeCapacity)
+	if err != nil {
+		return err
+	}
+	log.Infow("succeed to subscribe object events", "node_addr", client.Remote())
+	s.setHealthy()
+
+	healthTimeout := EventHealthCheckBlocks * ExpectedOutputBlockInternal * time.Second
+	ticker := time.NewTicker(healthTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return nil
+		case <-headerCh:
+			s.setHealthy()
+		case event := <-sealCh:
+			s.dispatch(event, sealObjectIDEventKey, ObjectEventSealed)
+		case event := <-rejectCh:
+			s.dispatch(event, rejectSealObjectIDEventKey, ObjectEventRejected)
+		case <-ticker.C:
+			s.mux.RLock()
+			lastHeaderAt := s.lastHeaderAt
+			s.mux.RUnlock()
+			if time.Since(lastHeaderAt) > healthTimeout {
+				log.Warnw("no block header event received, object event subscription is unhealthy",
+					"node_addr", client.Remote(), "last_header_at", lastHeaderAt)
+				return nil
+			}
+			if !client.IsRunning() {
+				log.Warnw("websocket client is stopped, object event subscription is unhealthy",
+					"node_addr", client.Remote())
+				return nil
+			}
+		}
+	}
+}
+
+func (s *objectEventSubscriber) unsubscribe() {
+	if s.currentClient == nil || !s.currentClient.IsRunning() {
+		return
+	}
+	if err := s.currentClient.UnsubscribeAll(context.Background(), EventSubscriber); err != nil {
+		log.Warnw("failed to unsubscribe object events", "node_addr", s.currentClient.Remote(), "error", err)
+	}
+}
+
+func (s *objectEventSubscriber) dispatch(event ctypes.ResultEvent, key string, eventType ObjectEventType) {
+	for _, val := range event.Events[key] {
+		objectID, err := parseEventObjectID(val)
+		if err != nil {
+			log.Warnw("failed to parse object id from event", "key", key, "value", val, "error", err)
+			continue
+		}
+		s.waiters.notify(objectID, eventType)
+	}
+}
+
+// waitObjectEvent waits the object event, the first return value indicates
+// whether the event subscription is usable, if not, the caller should fall
+// back to polling.
+func (s *objectEventSubscriber) waitObjectEvent(ctx context.Context, objectID uint64, timeout time.Duration,
+	check func() (ObjectEventType, bool)) (bool, ObjectEventType, bool) {
+	healthy, brokenCh := s.isHealthy()
+	if !healthy {
+		return false, 0, false
+	}
+	ch, ok := s.waiters.register(objectID)
+	if !ok {
+		log.CtxWarnw(ctx, "too many object event waiters, fall back to polling", "object_id", objectID)
+		return false, 0, false
+	}
+	defer s.waiters.unregister(objectID, ch)
+	// the event may be emitted before registering, check the state once.
+	if event, done := check(); done {
+		return true, event, true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case event := <-ch:
+		return true, event, true
+	case <-timer.C:
+		return true, 0, false
+	case <-ctx.Done():
+		return true, 0, false
+	case <-brokenCh:
+		return false, 0, false
+	}
+}
+
+// parseEventObjectID parses the object id from typed event attribute value,
+// the typed event value is json encoded, e.g. "\"100\"".
+func parseEventObjectID(val string) (uint64, error) {
+	if unquoted, err := strconv.Unquote(val); err == nil {
+		val = unquoted
+	}
+	return strconv.ParseUint(strings.Trim(val, "\""), 10, 64)
+}
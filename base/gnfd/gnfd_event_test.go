@@ -0,0 +1,69 @@
+package gnfd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectEventWaitersCapacity(t *testing.T) {
+	waiters := newObjectEventWaiters(2)
+	ch1, ok := waiters.register(1)
+	require.True(t, ok)
+	ch2, ok := waiters.register(1)
+	require.True(t, ok)
+	_, ok = waiters.register(2)
+	require.False(t, ok)
+
+	waiters.notify(1, ObjectEventSealed)
+	require.Equal(t, ObjectEventSealed, <-ch1)
+	require.Equal(t, ObjectEventSealed, <-ch2)
+
+	waiters.unregister(1, ch1)
+	waiters.unregister(1, ch2)
+	require.Equal(t, 0, waiters.size)
+	require.Equal(t, 0, len(waiters.waiters))
+	_, ok = waiters.register(2)
+	require.True(t, ok)
+}
+
+func TestWaitObjectEvent(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	subscriber := newObjectEventSubscriber(10, nil, stopCh)
+	notChecked := func() (ObjectEventType, bool) { return 0, false }
+
+	// unhealthy subscription should fall back to polling
+	usable, _, _ := subscriber.waitObjectEvent(context.Background(), 1, time.Second, notChecked)
+	require.False(t, usable)
+
+	subscriber.setHealthy()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		subscriber.waiters.notify(1, ObjectEventRejected)
+	}()
+	usable, event, done := subscriber.waitObjectEvent(context.Background(), 1, time.Second, notChecked)
+	require.True(t, usable)
+	require.True(t, done)
+	require.Equal(t, ObjectEventRejected, event)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		subscriber.setUnhealthy()
+	}()
+	usable, _, _ = subscriber.waitObjectEvent(context.Background(), 1, time.Second, notChecked)
+	require.False(t, usable)
+}
+
+func TestParseEventObjectID(t *testing.T) {
+	id, err := parseEventObjectID(`"100"`)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), id)
+	id, err = parseEventObjectID("101")
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), id)
+	_, err = parseEventObjectID("abc")
+	require.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/cosmos/cosmos-sdk/types/query"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
@@ -23,6 +24,8 @@ import (
 func (g *Gnfd) CurrentHeight(ctx context.Context) (uint64, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_height").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_height")
+	defer span.End()
 	resp, err := g.getCurrentWsClient().ABCIInfo(ctx)
 	if err != nil {
 		log.CtxErrorw(ctx, "get latest block height failed", "node_addr",
@@ -36,6 +39,8 @@ func (g *Gnfd) CurrentHeight(ctx context.Context) (uint64, error) {
 func (g *Gnfd) HasAccount(ctx context.Context, address string) (bool, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_account").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_account")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.Account(ctx, &authtypes.QueryAccountRequest{Address: address})
 	if err != nil {
@@ -49,6 +54,8 @@ func (g *Gnfd) HasAccount(ctx context.Context, address string) (bool, error) {
 func (g *Gnfd) ListSPs(ctx context.Context) ([]*sptypes.StorageProvider, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("list_sps").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.list_sps")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	var spInfos []*sptypes.StorageProvider
 	resp, err := client.StorageProviders(ctx, &sptypes.QueryStorageProvidersRequest{
@@ -71,6 +78,8 @@ func (g *Gnfd) ListSPs(ctx context.Context) ([]*sptypes.StorageProvider, error)
 func (g *Gnfd) ListBondedValidators(ctx context.Context) ([]stakingtypes.Validator, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("list_bonded_validators").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.list_bonded_validators")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	var validators []stakingtypes.Validator
 	resp, err := client.Validators(ctx, &stakingtypes.QueryValidatorsRequest{Status: "BOND_STATUS_BONDED"})
@@ -88,6 +97,8 @@ func (g *Gnfd) ListBondedValidators(ctx context.Context) ([]stakingtypes.Validat
 func (g *Gnfd) QueryStorageParams(ctx context.Context) (params *storagetypes.Params, err error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_storage_params").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_storage_params")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.StorageQueryClient.Params(ctx, &storagetypes.QueryParamsRequest{})
 	if err != nil {
@@ -101,6 +112,8 @@ func (g *Gnfd) QueryStorageParams(ctx context.Context) (params *storagetypes.Par
 func (g *Gnfd) QueryStorageParamsByTimestamp(ctx context.Context, timestamp int64) (params *storagetypes.Params, err error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_storage_params_by_timestamp").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_storage_params_by_timestamp")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.StorageQueryClient.QueryParamsByTimestamp(ctx,
 		&storagetypes.QueryParamsByTimestampRequest{Timestamp: timestamp})
@@ -115,6 +128,8 @@ func (g *Gnfd) QueryStorageParamsByTimestamp(ctx context.Context, timestamp int6
 func (g *Gnfd) QueryBucketInfo(ctx context.Context, bucket string) (*storagetypes.BucketInfo, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_bucket").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_bucket")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.HeadBucket(ctx, &storagetypes.QueryHeadBucketRequest{BucketName: bucket})
 	if err != nil {
@@ -128,6 +143,8 @@ func (g *Gnfd) QueryBucketInfo(ctx context.Context, bucket string) (*storagetype
 func (g *Gnfd) QueryObjectInfo(ctx context.Context, bucket, object string) (*storagetypes.ObjectInfo, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_object").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_object")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.HeadObject(ctx, &storagetypes.QueryHeadObjectRequest{
 		BucketName: bucket,
@@ -144,6 +161,8 @@ func (g *Gnfd) QueryObjectInfo(ctx context.Context, bucket, object string) (*sto
 func (g *Gnfd) QueryObjectInfoByID(ctx context.Context, objectID string) (*storagetypes.ObjectInfo, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_object_by_id").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_object_by_id")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.HeadObjectById(ctx, &storagetypes.QueryHeadObjectByIdRequest{
 		ObjectId: objectID,
@@ -169,11 +188,42 @@ func (g *Gnfd) QueryBucketInfoAndObjectInfo(ctx context.Context, bucket, object
 	return bucketInfo, objectInfo, nil
 }
 
-// ListenObjectSeal returns an indication of the object is sealed.
-// TODO:: retrieve service support seal event subscription
+// ListenObjectSeal returns an indication of the object is sealed. It waits the seal
+// event from the subscription, and falls back to polling if the subscription is unhealthy.
 func (g *Gnfd) ListenObjectSeal(ctx context.Context, objectID uint64, timeoutHeight int) (bool, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("wait_object_seal").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.wait_object_seal")
+	defer span.End()
+	if g.eventSubscriber != nil {
+		check := func() (ObjectEventType, bool) {
+			objectInfo, err := g.QueryObjectInfoByID(ctx, strconv.FormatUint(objectID, 10))
+			if err == nil && objectInfo.GetObjectStatus() == storagetypes.OBJECT_STATUS_SEALED {
+				return ObjectEventSealed, true
+			}
+			return 0, false
+		}
+		usable, event, done := g.eventSubscriber.waitObjectEvent(ctx, objectID,
+			time.Duration(timeoutHeight)*ExpectedOutputBlockInternal*time.Second, check)
+		if usable {
+			if !done {
+				log.CtxErrorw(ctx, "seal object timeout", "object_id", objectID)
+				return false, ErrSealTimeout
+			}
+			if event == ObjectEventRejected {
+				log.CtxErrorw(ctx, "object seal is rejected", "object_id", objectID)
+				return false, nil
+			}
+			log.CtxDebugw(ctx, "succeed to listen object seal event")
+			return true, nil
+		}
+		log.CtxWarnw(ctx, "object event subscription is unusable, fall back to polling", "object_id", objectID)
+	}
+	return g.pollObjectSeal(ctx, objectID, timeoutHeight)
+}
+
+// pollObjectSeal polls the object status height by height until sealed or timeout.
+func (g *Gnfd) pollObjectSeal(ctx context.Context, objectID uint64, timeoutHeight int) (bool, error) {
 	var (
 		objectInfo *storagetypes.ObjectInfo
 		err        error
@@ -198,11 +248,39 @@ func (g *Gnfd) ListenObjectSeal(ctx context.Context, objectID uint64, timeoutHei
 	return false, err
 }
 
-// ListenRejectUnSealObject returns an indication of the object is rejected.
-// TODO:: retrieve service support reject unseal event subscription
+// ListenRejectUnSealObject returns an indication of the object is rejected. It waits the
+// reject seal event from the subscription, and falls back to polling if the subscription
+// is unhealthy.
 func (g *Gnfd) ListenRejectUnSealObject(ctx context.Context, objectID uint64, timeoutHeight int) (bool, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("wait_reject_unseal_object").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.wait_reject_unseal_object")
+	defer span.End()
+	if g.eventSubscriber != nil {
+		check := func() (ObjectEventType, bool) {
+			_, err := g.QueryObjectInfoByID(ctx, strconv.FormatUint(objectID, 10))
+			if err != nil && strings.Contains(err.Error(), "No such object") {
+				return ObjectEventRejected, true
+			}
+			return 0, false
+		}
+		usable, event, done := g.eventSubscriber.waitObjectEvent(ctx, objectID,
+			time.Duration(timeoutHeight)*ExpectedOutputBlockInternal*time.Second, check)
+		if usable {
+			if !done || event != ObjectEventRejected {
+				log.CtxErrorw(ctx, "reject unseal object timeout", "object_id", objectID, "event", event)
+				return false, ErrRejectUnSealTimeout
+			}
+			log.CtxDebugw(ctx, "succeed to listen reject unseal object event")
+			return true, nil
+		}
+		log.CtxWarnw(ctx, "object event subscription is unusable, fall back to polling", "object_id", objectID)
+	}
+	return g.pollRejectUnSealObject(ctx, objectID, timeoutHeight)
+}
+
+// pollRejectUnSealObject polls the object height by height until it is rejected or timeout.
+func (g *Gnfd) pollRejectUnSealObject(ctx context.Context, objectID uint64, timeoutHeight int) (bool, error) {
 	var err error
 	for i := 0; i < timeoutHeight; i++ {
 		_, err = g.QueryObjectInfoByID(ctx, strconv.FormatUint(objectID, 10))
@@ -225,6 +303,8 @@ func (g *Gnfd) ListenRejectUnSealObject(ctx context.Context, objectID uint64, ti
 func (g *Gnfd) QueryPaymentStreamRecord(ctx context.Context, account string) (*paymenttypes.StreamRecord, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("query_payment_stream_record").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.query_payment_stream_record")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.StreamRecord(ctx, &paymenttypes.QueryGetStreamRecordRequest{
 		Account: account,
@@ -240,6 +320,8 @@ func (g *Gnfd) QueryPaymentStreamRecord(ctx context.Context, account string) (*p
 func (g *Gnfd) VerifyGetObjectPermission(ctx context.Context, account, bucket, object string) (bool, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("verify_get_object_permission").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.verify_get_object_permission")
+	defer span.End()
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.VerifyPermission(ctx, &storagetypes.QueryVerifyPermissionRequest{
 		Operator:   account,
@@ -261,6 +343,8 @@ func (g *Gnfd) VerifyGetObjectPermission(ctx context.Context, account, bucket, o
 func (g *Gnfd) VerifyPutObjectPermission(ctx context.Context, account, bucket, object string) (bool, error) {
 	startTime := time.Now()
 	defer metrics.GnfdChainHistogram.WithLabelValues("verify_put_object_permission").Observe(time.Since(startTime).Seconds())
+	ctx, span := tracing.StartSpan(ctx, "gnfd.verify_put_object_permission")
+	defer span.End()
 	_ = object
 	client := g.getCurrentClient().GnfdClient()
 	resp, err := client.VerifyPermission(ctx, &storagetypes.QueryVerifyPermissionRequest{
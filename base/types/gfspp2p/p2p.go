@@ -29,9 +29,12 @@ func (m *GfSpPing) GetSignBytes() []byte {
 }
 
 // GetSignBytes returns the pong message bytes to sign over.
+// The status is excluded, the sp of old version drops the unknown status field
+// and can not verify the signature if it is signed.
 func (m *GfSpPong) GetSignBytes() []byte {
 	fakeMsg := proto.Clone(m).(*GfSpPong)
 	fakeMsg.Signature = []byte{}
+	fakeMsg.Status = nil
 	bz := ModuleCdc.MustMarshalJSON(fakeMsg)
 	return sdk.MustSortJSON(bz)
 }
@@ -7,6 +7,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspp2p"
 	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
@@ -48,6 +49,14 @@ func (m *GfSpCreateBucketApprovalTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpCreateBucketApprovalTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpCreateBucketApprovalTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpCreateBucketApprovalTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -174,6 +183,14 @@ func (m *GfSpCreateObjectApprovalTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpCreateObjectApprovalTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpCreateObjectApprovalTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpCreateObjectApprovalTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -312,6 +329,14 @@ func (m *GfSpReplicatePieceApprovalTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpReplicatePieceApprovalTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpReplicatePieceApprovalTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpReplicatePieceApprovalTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -425,3 +450,7 @@ func (m *GfSpReplicatePieceApprovalTask) SetApprovedSpEndpoint(endpoint string)
 func (m *GfSpReplicatePieceApprovalTask) SetApprovedSpApprovalAddress(address string) {
 	m.ApprovedSpApprovalAddress = address
 }
+
+func (m *GfSpReplicatePieceApprovalTask) SetApprovedSpStatus(status *gfspp2p.GfSpSecondaryStatus) {
+	m.ApprovedSpStatus = status
+}
@@ -58,6 +58,14 @@ func (m *GfSpDownloadObjectTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpDownloadObjectTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpDownloadObjectTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpDownloadObjectTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -207,6 +215,14 @@ func (m *GfSpDownloadPieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpDownloadPieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpDownloadPieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpDownloadPieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -351,6 +367,14 @@ func (m *GfSpChallengePieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpChallengePieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpChallengePieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpChallengePieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -50,6 +50,14 @@ func (m *GfSpGCObjectTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpGCObjectTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpGCObjectTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpGCObjectTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -173,6 +181,14 @@ func (m *GfSpGCZombiePieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpGCZombiePieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpGCZombiePieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpGCZombiePieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -280,6 +296,14 @@ func (m *GfSpGCMetaTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpGCMetaTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpGCMetaTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpGCMetaTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
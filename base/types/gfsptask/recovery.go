@@ -43,9 +43,9 @@ func (m *GfSpRecoverPieceTask) Type() coretask.TType {
 }
 
 func (m *GfSpRecoverPieceTask) Info() string {
-	return fmt.Sprintf("key[%s], type[%s], priority[%d], piece index[%d], %s",
+	return fmt.Sprintf("key[%s], type[%s], priority[%d], piece index[%d], recover sources%v, %s",
 		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(),
-		m.GetSegmentIdx(), m.GetTask().Info())
+		m.GetSegmentIdx(), m.GetRecoverSources(), m.GetTask().Info())
 }
 
 func (m *GfSpRecoverPieceTask) GetAddress() string {
@@ -56,6 +56,14 @@ func (m *GfSpRecoverPieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpRecoverPieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpRecoverPieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpRecoverPieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -162,6 +170,10 @@ func (m *GfSpRecoverPieceTask) SetRecoverDone() {
 	m.Recovered = true
 }
 
+func (m *GfSpRecoverPieceTask) SetRecoverSources(sources []string) {
+	m.RecoverSources = sources
+}
+
 func (m *GfSpRecoverPieceTask) GetSignBytes() []byte {
 	fakeMsg := &GfSpRecoverPieceTask{
 		ObjectInfo:    m.GetObjectInfo(),
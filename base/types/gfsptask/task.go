@@ -31,6 +31,10 @@ func (m *GfSpTask) SetAddress(address string) {
 	m.Address = address
 }
 
+func (m *GfSpTask) SetTraceParent(traceParent string) {
+	m.TraceParent = traceParent
+}
+
 func (m *GfSpTask) SetCreateTime(time int64) {
 	m.CreateTime = time
 }
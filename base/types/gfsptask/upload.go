@@ -55,6 +55,14 @@ func (m *GfSpUploadObjectTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpUploadObjectTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpUploadObjectTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpUploadObjectTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -146,6 +154,10 @@ func (m *GfSpUploadObjectTask) SetStorageParams(param *storagetypes.Params) {
 	m.StorageParams = param
 }
 
+func (m *GfSpUploadObjectTask) SetSecondaryApprovals(approvals []*GfSpReplicatePieceApprovalTask) {
+	m.SecondaryApprovals = approvals
+}
+
 func (m *GfSpResumableUploadObjectTask) InitResumableUploadObjectTask(object *storagetypes.ObjectInfo, params *storagetypes.Params,
 	timeout int64, complete bool, offset uint64) {
 	m.Reset()
@@ -196,6 +208,14 @@ func (m *GfSpResumableUploadObjectTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpResumableUploadObjectTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpResumableUploadObjectTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpResumableUploadObjectTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -325,6 +345,14 @@ func (m *GfSpReplicatePieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpReplicatePieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpReplicatePieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpReplicatePieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -429,6 +457,10 @@ func (m *GfSpReplicatePieceTask) SetSecondaryAddresses(addresses []string) {
 	m.SecondaryAddresses = addresses
 }
 
+func (m *GfSpReplicatePieceTask) SetSecondaryApprovals(approvals []*GfSpReplicatePieceApprovalTask) {
+	m.SecondaryApprovals = approvals
+}
+
 func (m *GfSpReplicatePieceTask) SetObjectInfo(object *storagetypes.ObjectInfo) {
 	m.ObjectInfo = object
 }
@@ -485,6 +517,14 @@ func (m *GfSpSealObjectTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpSealObjectTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpSealObjectTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpSealObjectTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
@@ -634,6 +674,14 @@ func (m *GfSpReceivePieceTask) SetAddress(address string) {
 	m.GetTask().SetAddress(address)
 }
 
+func (m *GfSpReceivePieceTask) GetTraceParent() string {
+	return m.GetTask().GetTraceParent()
+}
+
+func (m *GfSpReceivePieceTask) SetTraceParent(traceParent string) {
+	m.GetTask().SetTraceParent(traceParent)
+}
+
 func (m *GfSpReceivePieceTask) GetCreateTime() int64 {
 	return m.GetTask().GetCreateTime()
 }
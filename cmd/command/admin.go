@@ -0,0 +1,292 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var drainTimeoutFlag = &cli.Int64Flag{
+	Name:  "timeout",
+	Usage: "The deadline in seconds of draining, zero means the configured drain timeout",
+	Value: 0,
+}
+
+var DrainCmd = &cli.Command{
+	Action: drainAction,
+	Name:   "drain",
+	Usage:  "Drain the modules of the machine and exit gracefully",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		endpointFlag,
+		adminTokenFlag,
+		drainTimeoutFlag,
+	},
+	Category: "ADMIN COMMANDS",
+	Description: `The drain command asks the machine to enter drain mode: the gater and
+uploader reject new uploads with a retryable 503, the executor stops asking tasks, the
+in-flight tasks finish or are reported back to manager for reassignment, and the process
+exits when all the modules are drained or the deadline is exceeded. The request carries
+the admin token.`,
+}
+
+// grpcEndpoint returns the gRPC address of the machine, the endpoint flag overrides the
+// address in the config file.
+func grpcEndpoint(ctx *cli.Context) (string, error) {
+	endpoint, _, err := lifecycleTarget(ctx)
+	return endpoint, err
+}
+
+// lifecycleTarget returns the gRPC address and the admin token of the machine, the flags
+// override the values in the config file.
+func lifecycleTarget(ctx *cli.Context) (string, string, error) {
+	endpoint := gfspapp.DefaultGRPCAddress
+	var token string
+	if ctx.IsSet(utils.ConfigFileFlag.Name) {
+		cfg := &gfspconfig.GfSpConfig{}
+		err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg)
+		if err != nil {
+			log.Errorw("failed to load config file", "error", err)
+			return "", "", err
+		}
+		endpoint = cfg.GRPCAddress
+		token = cfg.Manager.AdminToken
+	}
+	if ctx.IsSet(endpointFlag.Name) {
+		endpoint = ctx.String(endpointFlag.Name)
+	}
+	if ctx.IsSet(adminTokenFlag.Name) {
+		token = ctx.String(adminTokenFlag.Name)
+	}
+	return endpoint, token, nil
+}
+
+func drainAction(ctx *cli.Context) error {
+	endpoint, token, err := lifecycleTarget(ctx)
+	if err != nil {
+		return err
+	}
+	client := &gfspclient.GfSpClient{}
+	draining, err := client.Drain(context.Background(), endpoint, token, ctx.Int64(drainTimeoutFlag.Name))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("endpoint: %s\ndraining: %t\n", endpoint, draining)
+	return nil
+}
+
+var ConfigReloadCmd = &cli.Command{
+	Action: configReloadAction,
+	Name:   "config.reload",
+	Usage:  "Reload the runtime changeable config of the machine",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		endpointFlag,
+		adminTokenFlag,
+	},
+	Category: "ADMIN COMMANDS",
+	Description: `The config.reload command asks the machine to reload the config file,
+the same as sending SIGHUP to the process. The queue capacities, parallelism, task
+timeouts and retries, api rate limits, resource manager limits and log level are applied
+at runtime, the reloading is rejected if any other field is changed, which requires a
+restart, or any value is invalid. The request carries the admin token.`,
+}
+
+func configReloadAction(ctx *cli.Context) error {
+	endpoint, token, err := lifecycleTarget(ctx)
+	if err != nil {
+		return err
+	}
+	client := &gfspclient.GfSpClient{}
+	changed, err := client.ReloadConfig(context.Background(), endpoint, token)
+	if len(changed) != 0 {
+		fmt.Printf("changed fields: %s\n", strings.Join(changed, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("endpoint: %s\nreloaded: %d fields\n", endpoint, len(changed))
+	return nil
+}
+
+var adminTokenFlag = &cli.StringFlag{
+	Name:  "token",
+	Usage: "The admin token, it overrides the admin token in the config file",
+}
+
+var adminTaskKeyFlag = &cli.StringFlag{
+	Name:     "key",
+	Aliases:  []string{"k"},
+	Usage:    "The key of the task",
+	Required: true,
+}
+
+var adminTaskTypeFlag = &cli.StringFlag{
+	Name:     "type",
+	Aliases:  []string{"t"},
+	Usage:    "The type of the task, such as ReplicatePiece, SealObject, GCObject, GCZombiePiece, GCMeta, ReceivePiece and RecoverPiece",
+	Required: true,
+}
+
+var adminTaskPriorityFlag = &cli.UintFlag{
+	Name:     "priority",
+	Aliases:  []string{"p"},
+	Usage:    "The priority of the task, in the range of [0, 255]",
+	Required: true,
+}
+
+var TaskListCmd = &cli.Command{
+	Action: taskListAction,
+	Name:   "task.list",
+	Usage:  "List the tasks that hold on manager by filters",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		adminTokenFlag,
+		&cli.StringFlag{
+			Name:    "type",
+			Aliases: []string{"t"},
+			Usage:   "The type of the tasks, empty means all types",
+		},
+		&cli.StringFlag{
+			Name:    "key",
+			Aliases: []string{"k"},
+			Usage:   "The sub key that the task key contains",
+		},
+		&cli.Int64Flag{
+			Name:  "min-retry",
+			Usage: "The min retry counter of the tasks",
+		},
+		&cli.BoolFlag{
+			Name:  "only-timeout",
+			Usage: "Only list the tasks that exceed timeout",
+		},
+		&cli.Int64Flag{
+			Name:  "limit",
+			Usage: "The max number of the tasks",
+			Value: 100,
+		},
+	},
+	Category: "ADMIN COMMANDS",
+	Description: `The task.list command lists the tasks that hold on manager by the task
+type, sub key, min retry counter and timeout, and shows the task types whose dispatching
+is paused.`,
+}
+
+var TaskCancelCmd = &cli.Command{
+	Action:   taskControlAction(gfspapp.AdminTaskActionCancel),
+	Name:     "task.cancel",
+	Usage:    "Cancel the task that holds on manager",
+	Flags:    []cli.Flag{utils.ConfigFileFlag, adminTokenFlag, adminTaskKeyFlag},
+	Category: "ADMIN COMMANDS",
+	Description: `The task.cancel command removes the task from the queue of manager, the
+success result reported later by the running executor is dropped, and the upload progress
+of the uploading object is marked as failed.`,
+}
+
+var TaskRetryCmd = &cli.Command{
+	Action:   taskControlAction(gfspapp.AdminTaskActionRetry),
+	Name:     "task.retry",
+	Usage:    "Retry the failed or timeout task immediately",
+	Flags:    []cli.Flag{utils.ConfigFileFlag, adminTokenFlag, adminTaskKeyFlag},
+	Category: "ADMIN COMMANDS",
+	Description: `The task.retry command resets the retry counter of the task, so the
+task is dispatched to executor again immediately.`,
+}
+
+var TaskPriorityCmd = &cli.Command{
+	Action:   taskControlAction(gfspapp.AdminTaskActionPriority),
+	Name:     "task.priority",
+	Usage:    "Set the priority of the task",
+	Flags:    []cli.Flag{utils.ConfigFileFlag, adminTokenFlag, adminTaskKeyFlag, adminTaskPriorityFlag},
+	Category: "ADMIN COMMANDS",
+	Description: `The task.priority command adjusts the priority of the task, the higher
+priority task is picked up more likely when dispatching.`,
+}
+
+var TaskPauseCmd = &cli.Command{
+	Action:   taskControlAction(gfspapp.AdminTaskActionPause),
+	Name:     "task.pause",
+	Usage:    "Pause dispatching the type of tasks",
+	Flags:    []cli.Flag{utils.ConfigFileFlag, adminTokenFlag, adminTaskTypeFlag},
+	Category: "ADMIN COMMANDS",
+	Description: `The task.pause command pauses dispatching the type of tasks to executor,
+the paused tasks are still held on manager until resumed. The pause is not persisted and
+is reset after manager restarts.`,
+}
+
+var TaskResumeCmd = &cli.Command{
+	Action:      taskControlAction(gfspapp.AdminTaskActionResume),
+	Name:        "task.resume",
+	Usage:       "Resume dispatching the type of tasks",
+	Flags:       []cli.Flag{utils.ConfigFileFlag, adminTokenFlag, adminTaskTypeFlag},
+	Category:    "ADMIN COMMANDS",
+	Description: `The task.resume command resumes dispatching the paused type of tasks.`,
+}
+
+// makeAdminClient returns the client to manager and the admin token.
+func makeAdminClient(ctx *cli.Context) (*gfspclient.GfSpClient, string, error) {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	token := cfg.Manager.AdminToken
+	if ctx.IsSet(adminTokenFlag.Name) {
+		token = ctx.String(adminTokenFlag.Name)
+	}
+	return utils.MakeGfSpClient(cfg), token, nil
+}
+
+func taskListAction(ctx *cli.Context) error {
+	client, token, err := makeAdminClient(ctx)
+	if err != nil {
+		return err
+	}
+	tasks, paused, err := client.ListTasks(context.Background(), token, &gfspserver.GfSpListTasksRequest{
+		TaskType:    ctx.String("type"),
+		SubKey:      ctx.String("key"),
+		MinRetry:    ctx.Int64("min-retry"),
+		OnlyTimeout: ctx.Bool("only-timeout"),
+		Limit:       ctx.Int64("limit"),
+	})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		fmt.Printf("key: %s\ntype: %s\npriority: %d\nretry: %d/%d\nexceed_timeout: %t\naddress: %s\n"+
+			"create_time: %s\nupdate_time: %s\n\n", task.GetKey(), task.GetType(), task.GetPriority(),
+			task.GetRetry(), task.GetMaxRetry(), task.GetExceedTimeout(), task.GetAddress(),
+			time.Unix(task.GetCreateTime(), 0).String(), time.Unix(task.GetUpdateTime(), 0).String())
+	}
+	fmt.Printf("total: %d\npaused_task_types: %v\n", len(tasks), paused)
+	return nil
+}
+
+func taskControlAction(action string) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		client, token, err := makeAdminClient(ctx)
+		if err != nil {
+			return err
+		}
+		req := &gfspserver.GfSpControlTaskRequest{
+			Action:   action,
+			TaskKey:  ctx.String(adminTaskKeyFlag.Name),
+			TaskType: ctx.String(adminTaskTypeFlag.Name),
+			Priority: uint32(ctx.Uint(adminTaskPriorityFlag.Name)),
+		}
+		if err = client.ControlTask(context.Background(), token, req); err != nil {
+			return err
+		}
+		fmt.Printf("succeed to %s task\n", action)
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
+)
+
+var auditFileFlag = &cli.StringFlag{
+	Name:  "file",
+	Usage: "The path of the audit log file, the rotated files of it are verified too",
+}
+
+var AuditVerifyCmd = &cli.Command{
+	Action:    auditVerifyAction,
+	Name:      "audit.verify",
+	Usage:     "Verify the hash chain of the audit log",
+	ArgsUsage: "[audit log files...]",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		auditFileFlag,
+	},
+	Category: "ADMIN COMMANDS",
+	Description: `The audit.verify command checks every record of the audit log is not modified,
+removed or reordered. The files given as arguments are verified in order, otherwise the
+rotated files and the current file of the audit log are verified, the path is read from
+the file flag or the config file. The HMAC key of the records is read from the config file.
+The last hash is printed to be kept out of the sp as the anchor of the next verification.`,
+}
+
+func auditVerifyAction(ctx *cli.Context) error {
+	cfg := &gfspconfig.GfSpConfig{}
+	if ctx.IsSet(utils.ConfigFileFlag.Name) {
+		if err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg); err != nil {
+			return err
+		}
+	}
+	files := ctx.Args().Slice()
+	if len(files) == 0 {
+		filePath := audit.DefaultFilePath
+		if cfg.Audit.FilePath != "" {
+			filePath = cfg.Audit.FilePath
+		}
+		if ctx.IsSet(auditFileFlag.Name) {
+			filePath = ctx.String(auditFileFlag.Name)
+		}
+		backups, err := audit.Backups(filePath)
+		if err != nil {
+			return err
+		}
+		files = append(backups, filePath)
+	}
+	var state audit.ChainState
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		next, err := audit.Verify(f, state, []byte(cfg.Audit.HMACKey))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		state = next
+	}
+	fmt.Printf("verified %d files, last seq: %d, last hash: %s\n", len(files), state.Seq, state.Hash)
+	return nil
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
+	"github.com/bnb-chain/greenfield-storage-provider/store/sqldb"
+)
+
+var migrateDBFlag = &cli.StringFlag{
+	Name:  "db",
+	Usage: "The database to migrate, spdb, bsdb or bsdb-backup",
+	Value: sqldb.MigrationDBName,
+}
+
+var migrateTargetFlag = &cli.Uint64Flag{
+	Name:  "target",
+	Usage: "The target schema version, up defaults to the latest version and down defaults to the previous version",
+}
+
+var migrateDryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "Print the migrations to apply without changing the database",
+}
+
+var DBMigrateCmd = &cli.Command{
+	Name:     "db.migrate",
+	Usage:    "Show or migrate the schema version of the database",
+	Category: "ADMIN COMMANDS",
+	Description: `The db.migrate command manages the versioned schema migrations of spdb and bsdb.
+The status subcommand lists the migrations and whether they are applied, the up subcommand
+applies the pending migrations, the down subcommand reverts the applied migrations. The
+migrations run under the migration lock, so it is safe to run with the starting services.
+The migration that fails halfway is listed as dirty, it is applied again by the up subcommand.`,
+	Subcommands: []*cli.Command{
+		{
+			Action: migrateStatusAction,
+			Name:   "status",
+			Usage:  "List the migrations and whether they are applied",
+			Flags:  []cli.Flag{utils.ConfigFileFlag, migrateDBFlag},
+		},
+		{
+			Action: migrateUpAction,
+			Name:   "up",
+			Usage:  "Apply the pending migrations until the target version",
+			Flags:  []cli.Flag{utils.ConfigFileFlag, migrateDBFlag, migrateTargetFlag, migrateDryRunFlag},
+		},
+		{
+			Action: migrateDownAction,
+			Name:   "down",
+			Usage:  "Revert the applied migrations after the target version",
+			Flags:  []cli.Flag{utils.ConfigFileFlag, migrateDBFlag, migrateTargetFlag, migrateDryRunFlag},
+		},
+	},
+}
+
+// newMigrator opens the database by the config file and returns its migrator.
+func newMigrator(ctx *cli.Context) (*migrate.Migrator, error) {
+	cfg := &gfspconfig.GfSpConfig{}
+	if err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg); err != nil {
+		return nil, err
+	}
+	var (
+		db         *gorm.DB
+		name       string
+		migrations []*migrate.Migration
+		err        error
+	)
+	switch ctx.String(migrateDBFlag.Name) {
+	case sqldb.MigrationDBName:
+		sqldb.LoadDBConfigFromEnv(&cfg.SpDB)
+		sqldb.OverrideConfigVacancy(&cfg.SpDB)
+		db, err = sqldb.OpenDB(&cfg.SpDB)
+		name, migrations = sqldb.MigrationDBName, sqldb.Migrations()
+	case bsdb.MigrationDBName:
+		db, err = bsdb.OpenDB(&cfg.BsDB)
+		name, migrations = bsdb.MigrationDBName, bsdb.Migrations()
+	case bsdb.MigrationDBName + "-backup":
+		db, err = bsdb.OpenDB(&cfg.BsDBBackup)
+		name, migrations = bsdb.MigrationDBName, bsdb.Migrations()
+	default:
+		return nil, fmt.Errorf("unknown database %s", ctx.String(migrateDBFlag.Name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewMigrator(db, name, migrations)
+}
+
+func migrateStatusAction(ctx *cli.Context) error {
+	migrator, err := newMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	version, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("schema version: %d, binary version: %d\n", version, migrator.Latest())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range status {
+		state, appliedAt := "pending", "-"
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if s.Dirty {
+			state = "dirty"
+		}
+		if s.Unknown {
+			state = "unknown"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+func migrateUpAction(ctx *cli.Context) error {
+	migrator, err := newMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx.Uint64(migrateTargetFlag.Name), ctx.Bool(migrateDryRunFlag.Name))
+	printMigrations(ctx, "applied", "to apply", applied)
+	return err
+}
+
+func migrateDownAction(ctx *cli.Context) error {
+	migrator, err := newMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	target := ctx.Uint64(migrateTargetFlag.Name)
+	if !ctx.IsSet(migrateTargetFlag.Name) {
+		version, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		if version > 0 {
+			target = version - 1
+		}
+	}
+	reverted, err := migrator.Down(target, ctx.Bool(migrateDryRunFlag.Name))
+	printMigrations(ctx, "reverted", "to revert", reverted)
+	return err
+}
+
+func printMigrations(ctx *cli.Context, done, todo string, migrations []*migrate.Migration) {
+	prefix := done
+	if ctx.Bool(migrateDryRunFlag.Name) {
+		prefix = todo
+	}
+	if len(migrations) == 0 {
+		fmt.Printf("no migration %s\n", prefix)
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s: %d %s\n", prefix, migration.Version, migration.Name)
+	}
+}
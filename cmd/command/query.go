@@ -17,9 +17,7 @@ import (
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
-	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
-	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
 
 const (
@@ -28,7 +26,7 @@ const (
 
 var endpointFlag = &cli.StringFlag{
 	Name:  "n",
-	Usage: "The address of machine that to query tasks",
+	Usage: "The gRPC address of machine, e.g. the machine to query tasks",
 	Value: "",
 }
 
@@ -141,18 +139,9 @@ func listErrorsAction(ctx *cli.Context) error {
 }
 
 func queryTasksAction(ctx *cli.Context) error {
-	endpoint := gfspapp.DefaultGRPCAddress
-	if ctx.IsSet(utils.ConfigFileFlag.Name) {
-		cfg := &gfspconfig.GfSpConfig{}
-		err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg)
-		if err != nil {
-			log.Errorw("failed to load config file", "error", err)
-			return err
-		}
-		endpoint = cfg.GRPCAddress
-	}
-	if ctx.IsSet(endpointFlag.Name) {
-		endpoint = ctx.String(endpointFlag.Name)
+	endpoint, err := grpcEndpoint(ctx)
+	if err != nil {
+		return err
 	}
 	if !ctx.IsSet(keyFlag.Name) {
 		return fmt.Errorf("query key should be set")
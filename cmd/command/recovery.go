@@ -8,6 +8,7 @@ import (
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/urfave/cli/v2"
 )
@@ -24,6 +25,12 @@ var objectFlag = &cli.StringFlag{
 	Required: true,
 }
 
+var recoverActionFlag = &cli.StringFlag{
+	Name:  "action",
+	Usage: "The action of recover sp job, one of start, pause, resume and query",
+	Value: module.RecoverSpActionQuery,
+}
+
 var RecoverObjectCmd = &cli.Command{
 	Action: recoverObjectAction,
 	Name:   "recover.object",
@@ -114,6 +121,52 @@ func recoverObjectAction(ctx *cli.Context) error {
 	return nil
 }
 
+var RecoverSpCmd = &cli.Command{
+	Action: recoverSpAction,
+	Name:   "recover.sp",
+	Usage:  "Recover all the objects whose primary or secondary sp is the sp",
+
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		recoverActionFlag,
+	},
+
+	Category: "RECOVERY COMMANDS",
+	Description: `The recover.sp command starts, pauses, resumes or queries the job
+that recovers all the objects stored by the sp. The job scans the objects from the
+metadata, skips the pieces existing in piece store and generates the recover piece
+tasks in batches, it is resumed from the persisted progress after manager restarts.`,
+}
+
+func recoverSpAction(ctx *cli.Context) error {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client := utils.MakeGfSpClient(cfg)
+
+	action := ctx.String(recoverActionFlag.Name)
+	switch action {
+	case module.RecoverSpActionStart, module.RecoverSpActionPause, module.RecoverSpActionResume, module.RecoverSpActionQuery:
+	default:
+		return fmt.Errorf("invalid action %s, it should be one of start, pause, resume and query", action)
+	}
+	job, err := client.RecoverSp(context.Background(), action)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		fmt.Println("no recover sp job")
+		return nil
+	}
+	fmt.Printf("job_id: %d\nstatus: %s\ncursor: %d\nscanned_objects: %d\ngenerated_tasks: %d\n"+
+		"skipped_pieces: %d\nsucceed_pieces: %d\nfailed_pieces: %d\ncreate_time: %s\nupdate_time: %s\n",
+		job.GetJobId(), job.GetStatus(), job.GetCursor(), job.GetScannedObjects(), job.GetGeneratedTasks(),
+		job.GetSkippedPieces(), job.GetSucceedPieces(), job.GetFailedPieces(),
+		time.Unix(job.GetCreateTimestampSecond(), 0).String(), time.Unix(job.GetUpdateTimestampSecond(), 0).String())
+	return nil
+}
+
 func segmentPieceCount(payloadSize uint64, maxSegmentSize uint64) uint32 {
 	count := payloadSize / maxSegmentSize
 	if payloadSize%maxSegmentSize > 0 {
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+var snapshotDBFlag = &cli.StringFlag{
+	Name:  "db",
+	Usage: "The database to export or import, bsdb or bsdb-backup",
+	Value: bsdb.MigrationDBName,
+}
+
+var snapshotDirFlag = &cli.StringFlag{
+	Name:     "dir",
+	Usage:    "The directory of the snapshot",
+	Required: true,
+}
+
+var snapshotHeightFlag = &cli.Int64Flag{
+	Name:  "height",
+	Usage: "The expected block height of the snapshot, the export fails if the block syncer is not at the height",
+}
+
+var snapshotOverwriteFlag = &cli.BoolFlag{
+	Name:  "overwrite",
+	Usage: "Drop the existing tables of the snapshot after the snapshot is verified and decoded",
+}
+
+var BsDBSnapshotCmd = &cli.Command{
+	Name:     "bsdb.snapshot",
+	Usage:    "Export, import or verify the snapshot of the block syncer database",
+	Category: "ADMIN COMMANDS",
+	Description: `The bsdb.snapshot command bootstraps the block syncer database of a new sp from
+the snapshot of another sp instead of syncing from the genesis block. The export subcommand
+exports the tables, the epoch checkpoint and the prefix tree at the current block height in a
+consistent read, the import subcommand verifies the checksums and imports the snapshot, and the
+block syncer resumes syncing from the next block. The verify subcommand only verifies the
+checksums of the snapshot.`,
+	Subcommands: []*cli.Command{
+		{
+			Action: snapshotExportAction,
+			Name:   "export",
+			Usage:  "Export the snapshot of the database into the directory",
+			Flags:  []cli.Flag{utils.ConfigFileFlag, snapshotDBFlag, snapshotDirFlag, snapshotHeightFlag},
+		},
+		{
+			Action: snapshotImportAction,
+			Name:   "import",
+			Usage:  "Verify and import the snapshot of the directory into the database",
+			Flags:  []cli.Flag{utils.ConfigFileFlag, snapshotDBFlag, snapshotDirFlag, snapshotOverwriteFlag},
+		},
+		{
+			Action: snapshotVerifyAction,
+			Name:   "verify",
+			Usage:  "Verify the checksums of the snapshot of the directory",
+			Flags:  []cli.Flag{snapshotDirFlag},
+		},
+	},
+}
+
+// snapshotDBConfig returns the config of the database to export or import.
+func snapshotDBConfig(ctx *cli.Context) (*config.SQLDBConfig, error) {
+	cfg := &gfspconfig.GfSpConfig{}
+	if err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg); err != nil {
+		return nil, err
+	}
+	switch ctx.String(snapshotDBFlag.Name) {
+	case bsdb.MigrationDBName:
+		return &cfg.BsDB, nil
+	case bsdb.MigrationDBName + "-backup":
+		return &cfg.BsDBBackup, nil
+	default:
+		return nil, fmt.Errorf("unknown database %s", ctx.String(snapshotDBFlag.Name))
+	}
+}
+
+func snapshotExportAction(ctx *cli.Context) error {
+	dbConfig, err := snapshotDBConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := bsdb.InitDB(dbConfig)
+	if err != nil {
+		return err
+	}
+	manifest, err := bsdb.ExportSnapshot(db, ctx.String(snapshotDirFlag.Name), ctx.Int64(snapshotHeightFlag.Name))
+	if err != nil {
+		return err
+	}
+	return printSnapshot("exported", manifest)
+}
+
+func snapshotImportAction(ctx *cli.Context) error {
+	dbConfig, err := snapshotDBConfig(ctx)
+	if err != nil {
+		return err
+	}
+	// the target database may be empty, the schema is created by the snapshot
+	db, err := bsdb.OpenDB(dbConfig)
+	if err != nil {
+		return err
+	}
+	manifest, err := bsdb.ImportSnapshot(db, ctx.String(snapshotDirFlag.Name), ctx.Bool(snapshotOverwriteFlag.Name))
+	if err != nil {
+		return err
+	}
+	return printSnapshot("imported", manifest)
+}
+
+func snapshotVerifyAction(ctx *cli.Context) error {
+	manifest, err := bsdb.VerifySnapshot(ctx.String(snapshotDirFlag.Name))
+	if err != nil {
+		return err
+	}
+	return printSnapshot("verified", manifest)
+}
+
+func printSnapshot(action string, manifest *bsdb.SnapshotManifest) error {
+	fmt.Printf("%s snapshot at block height %d, block hash: %s, schema version: %d\n",
+		action, manifest.BlockHeight, manifest.BlockHash, manifest.SchemaVersion)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tCHECKSUM")
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", table.Name, table.Rows, table.Checksum)
+	}
+	return w.Flush()
+}
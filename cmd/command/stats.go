@@ -0,0 +1,84 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+var statsDBFlag = &cli.StringFlag{
+	Name:  "db",
+	Usage: "The database to reconcile, bsdb or bsdb-backup",
+	Value: bsdb.MigrationDBName,
+}
+
+var statsFixFlag = &cli.BoolFlag{
+	Name:  "fix",
+	Usage: "Replace the counted stats with the recomputed stats if they mismatch",
+}
+
+var StatsReconcileCmd = &cli.Command{
+	Action:   statsReconcileAction,
+	Name:     "stats.reconcile",
+	Usage:    "Recompute the bucket and account storage stats and compare with the counted stats",
+	Category: "ADMIN COMMANDS",
+	Flags:    []cli.Flag{utils.ConfigFileFlag, statsDBFlag, statsFixFlag},
+	Description: `The stats.reconcile command recomputes the object count, the payload size and the
+sealed and unsealed counts of every bucket and account from the objects table, and prints the
+stats counted by the block syncer that mismatch. The --fix flag replaces the counted stats with
+the recomputed stats. The block syncer only counts the objects changed after the stats tables
+are created, run it with --fix once after the upgrade to count the existing objects.`,
+}
+
+func statsReconcileAction(ctx *cli.Context) error {
+	cfg := &gfspconfig.GfSpConfig{}
+	if err := utils.LoadConfig(ctx.String(utils.ConfigFileFlag.Name), cfg); err != nil {
+		return err
+	}
+	var (
+		db  *gorm.DB
+		err error
+	)
+	switch ctx.String(statsDBFlag.Name) {
+	case bsdb.MigrationDBName:
+		db, err = bsdb.InitDB(&cfg.BsDB)
+	case bsdb.MigrationDBName + "-backup":
+		db, err = bsdb.InitDB(&cfg.BsDBBackup)
+	default:
+		return fmt.Errorf("unknown database %s", ctx.String(statsDBFlag.Name))
+	}
+	if err != nil {
+		return err
+	}
+	mismatches, err := bsdb.ReconcileStorageStats(db, ctx.Bool(statsFixFlag.Name))
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("the storage stats are consistent")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tKEY\tNAME\tCOUNTED (OBJECTS/SIZE/SEALED/UNSEALED)\tRECOMPUTED (OBJECTS/SIZE/SEALED/UNSEALED)")
+	for _, m := range mismatches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d/%d/%d\t%d/%d/%d/%d\n", m.Kind, m.Key, m.Name,
+			m.Counted.ObjectCount, m.Counted.PayloadSize, m.Counted.SealedCount, m.Counted.UnsealedCount,
+			m.Recomputed.ObjectCount, m.Recomputed.PayloadSize, m.Recomputed.SealedCount, m.Recomputed.UnsealedCount)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if ctx.Bool(statsFixFlag.Name) {
+		fmt.Printf("fixed %d mismatched stats\n", len(mismatches))
+	} else {
+		fmt.Printf("found %d mismatched stats, run with --fix to replace them\n", len(mismatches))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/cmd/command"
 	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -115,6 +116,20 @@ func init() {
 		command.DebugPutObjectCmd,
 		// recovery commands
 		command.RecoverObjectCmd,
+		command.RecoverSpCmd,
+		// admin commands
+		command.DrainCmd,
+		command.TaskListCmd,
+		command.TaskCancelCmd,
+		command.TaskRetryCmd,
+		command.TaskPriorityCmd,
+		command.TaskPauseCmd,
+		command.TaskResumeCmd,
+		command.ConfigReloadCmd,
+		command.AuditVerifyCmd,
+		command.DBMigrateCmd,
+		command.StatsReconcileCmd,
+		command.BsDBSnapshotCmd,
 	}
 	registerModular()
 }
@@ -141,7 +156,11 @@ func storageProvider(ctx *cli.Context) error {
 		log.Errorw("failed to make gf-sp env", "error", err)
 		return nil
 	}
-	gfsp, err := gfspapp.NewGfSpBaseApp(cfg)
+	// the config is loaded again from the command line and config file for reloading
+	loader := gfspconfig.CustomizeConfigLoader(func() (*gfspconfig.GfSpConfig, error) {
+		return utils.MakeConfig(ctx)
+	})
+	gfsp, err := gfspapp.NewGfSpBaseApp(cfg, loader)
 	if err != nil {
 		log.Errorw("failed to init gf-sp app", "error", err)
 		return err
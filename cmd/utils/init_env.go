@@ -147,9 +147,11 @@ func MakeGnfd(cfg *gfspconfig.GfSpConfig) (*gnfd.Gnfd, error) {
 	if len(cfg.Chain.ChainAddress) == 0 {
 		cfg.Chain.ChainAddress = []string{gfspapp.DefaultChainAddress}
 	}
+	// the command line tools only query the chain, no need to subscribe events.
 	gnfdCfg := &gnfd.GnfdChainConfig{
-		ChainID:      cfg.Chain.ChainID,
-		ChainAddress: cfg.Chain.ChainAddress,
+		ChainID:                  cfg.Chain.ChainID,
+		ChainAddress:             cfg.Chain.ChainAddress,
+		DisableEventSubscription: true,
 	}
 	return gnfd.NewGnfd(gnfdCfg)
 }
@@ -16,6 +16,14 @@ type Service interface {
 	Stop(ctx context.Context) error
 }
 
+// Drainable is an optional interface of Service, the service stops accepting new work
+// and finishes or hands off the in-flight work before it is stopped.
+type Drainable interface {
+	// Drain blocks until the in-flight work is finished or the context is done, the work
+	// unfinished at the deadline should be handed off to the other instances.
+	Drain(ctx context.Context) error
+}
+
 // Lifecycle is an interface to describe how service is managed.
 // The Lifecycle tracks the Service lifecycle, listens for signals from
 // the process to ensure a graceful shutdown.
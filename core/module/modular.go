@@ -195,8 +195,54 @@ type Manager interface {
 	HandleChallengePieceTask(ctx context.Context, task task.ChallengePieceTask) error
 	// HandleRecoverPieceTask handles the result of recovering piece task, the request comes from TaskExecutor.
 	HandleRecoverPieceTask(ctx context.Context, task task.RecoveryPieceTask) error
+	// HandleRecoverSpJob starts, pauses, resumes or queries the job that recovers all the objects
+	// of the SP, it returns the latest recover job, the request comes from the recover.sp command.
+	HandleRecoverSpJob(ctx context.Context, action string) (*spdb.RecoverJob, error)
+	// ListTasks lists the tasks that hold on manager by the filter, the request comes from admin.
+	ListTasks(ctx context.Context, filter *TaskFilter) ([]task.Task, error)
+	// CancelTask removes the task from the queue of manager, the success result reported by the
+	// running TaskExecutor is dropped because the task is no longer in the queue.
+	CancelTask(ctx context.Context, key task.TKey) error
+	// RetryTask resets the retry counter and the updated time of the task, so the failed or
+	// timeout task can be dispatched again immediately. The failed replicate piece and seal
+	// object tasks that have been removed from the queue are generated again by SPDB.
+	RetryTask(ctx context.Context, key task.TKey) error
+	// SetTaskPriority adjusts the priority of the task, the higher priority task is picked up
+	// more likely when dispatching.
+	SetTaskPriority(ctx context.Context, key task.TKey, priority task.TPriority) error
+	// PauseTaskDispatch pauses or resumes dispatching the type of tasks to TaskExecutor, the
+	// paused tasks are still held on manager.
+	PauseTaskDispatch(ctx context.Context, taskType task.TType, pause bool) error
+	// PausedTaskTypes returns the types of tasks whose dispatching is paused.
+	PausedTaskTypes(ctx context.Context) []task.TType
 }
 
+// TaskFilter defines the conditions of listing tasks that hold on manager, the zero value
+// of the field means no condition on it.
+type TaskFilter struct {
+	// TaskType defines the type of the tasks.
+	TaskType task.TType
+	// SubKey defines the sub-key that the task key contains.
+	SubKey task.TKey
+	// MinRetry defines the min retry counter of the tasks, used to find the failed tasks.
+	MinRetry int64
+	// OnlyTimeout defines whether only list the tasks that exceed timeout.
+	OnlyTimeout bool
+	// Limit defines the max number of the tasks.
+	Limit int
+}
+
+const (
+	// RecoverSpActionStart defines the action that starts a new full SP recover job.
+	RecoverSpActionStart = "start"
+	// RecoverSpActionPause defines the action that pauses the running recover job.
+	RecoverSpActionPause = "pause"
+	// RecoverSpActionResume defines the action that resumes the paused or interrupted recover job.
+	RecoverSpActionResume = "resume"
+	// RecoverSpActionQuery defines the action that queries the progress of the latest recover job.
+	RecoverSpActionQuery = "query"
+)
+
 // P2P is an abstract interface to the to do replicate piece approvals between SPs.
 type P2P interface {
 	Modular
@@ -219,6 +265,9 @@ type Receiver interface {
 	// HandleDoneReceivePieceTask calculates the integrity hash of the object and sign it, returns to the primary
 	// SP for sealed object.
 	HandleDoneReceivePieceTask(ctx context.Context, task task.ReceivePieceTask) ([]byte, []byte, error)
+	// HandleDiscardReceivePieceTask discards the received pieces of the replicate index, it is
+	// called by the primary SP to clean up the partial pieces if the upload is aborted.
+	HandleDiscardReceivePieceTask(ctx context.Context, task task.ReceivePieceTask) error
 	// QueryTasks queries replicate piece tasks that running on receiver by task sub-key.
 	QueryTasks(ctx context.Context, subKey task.TKey) ([]task.Task, error)
 }
@@ -58,6 +58,19 @@ func (*NullModular) HandleReplicatePieceApproval(context.Context, task.ApprovalR
 func (*NullModular) HandleRecoverPieceTask(ctx context.Context, task task.RecoveryPieceTask) error {
 	return ErrNilModular
 }
+func (*NullModular) HandleRecoverSpJob(context.Context, string) (*corespdb.RecoverJob, error) {
+	return nil, ErrNilModular
+}
+func (*NullModular) ListTasks(context.Context, *TaskFilter) ([]task.Task, error) {
+	return nil, ErrNilModular
+}
+func (*NullModular) CancelTask(context.Context, task.TKey) error { return ErrNilModular }
+func (*NullModular) RetryTask(context.Context, task.TKey) error  { return ErrNilModular }
+func (*NullModular) SetTaskPriority(context.Context, task.TKey, task.TPriority) error {
+	return ErrNilModular
+}
+func (*NullModular) PauseTaskDispatch(context.Context, task.TType, bool) error                   { return ErrNilModular }
+func (*NullModular) PausedTaskTypes(context.Context) []task.TType                                { return nil }
 func (*NullModular) PostReplicatePieceApproval(context.Context, task.ApprovalReplicatePieceTask) {}
 func (*NullModular) PreUploadObject(ctx context.Context, task task.UploadObjectTask) error {
 	return ErrNilModular
@@ -238,3 +251,6 @@ func (*NullReceiveModular) HandleReceivePieceTask(context.Context, task.ReceiveP
 func (*NullReceiveModular) HandleDoneReceivePieceTask(context.Context, task.ReceivePieceTask) ([]byte, []byte, error) {
 	return nil, nil, ErrNilModular
 }
+func (*NullReceiveModular) HandleDiscardReceivePieceTask(context.Context, task.ReceivePieceTask) error {
+	return ErrNilModular
+}
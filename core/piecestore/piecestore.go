@@ -2,6 +2,16 @@ package piecestore
 
 import (
 	"context"
+	"time"
+)
+
+const (
+	// StaleStagedPieceDuration defines the duration after which the staged piece is treated
+	// as left by the crashed writer, the recently staged pieces may belong to the running
+	// writers sharing the piece store.
+	StaleStagedPieceDuration = 10 * time.Minute
+	// CleanStagedPiecesInterval defines the interval of cleaning the stale staged pieces.
+	CleanStagedPiecesInterval = 10 * time.Minute
 )
 
 // PieceOp is a helper interface for piece key operator and piece size calculate.
@@ -42,4 +52,41 @@ type PieceStore interface {
 	// DeletePiece deletes the piece data from piece store, it can delete
 	// segment or ec piece data.
 	DeletePiece(ctx context.Context, key string) error
+	// HeadPiece returns the size of the piece without reading the piece data, it returns
+	// error if the piece does not exist.
+	HeadPiece(ctx context.Context, key string) (int64, error)
+	// StagePiece puts the piece data to the staging area of piece store, the staged piece
+	// is invisible to GetPiece until it is promoted. If the piece store can not rename the
+	// staged piece to the final key, e.g. s3, the piece is put to the final key directly.
+	StagePiece(ctx context.Context, key string, value []byte) error
+	// PromotePiece renames the staged piece to the final piece key, the piece is synced
+	// before renaming. It does nothing if the piece is put to the final key directly.
+	PromotePiece(ctx context.Context, key string) error
+	// DeleteStagedPiece deletes the staged piece that is not promoted. If the piece is put
+	// to the final key directly, the piece of the final key is deleted.
+	DeleteStagedPiece(ctx context.Context, key string) error
+	// CleanStagedPieces deletes the staged pieces that are staged before the time, it is
+	// used to clean up the pieces left by the crashed writers, returns the deleted number.
+	CleanStagedPieces(ctx context.Context, before time.Time) (int, error)
+}
+
+// CommitPiece writes the piece in two phases, the piece is staged and promoted to the
+// final key, then the commit is called to write the meta of the piece, e.g. checksum.
+// A crash in the middle leaves a staged piece that is cleaned up later, but never leaves
+// a meta pointing at a missing or truncated piece. The promotion is an atomic rename only
+// if the piece store supports renaming, otherwise the piece store relies on the object
+// storage that never exposes a partially put object. The commit can be nil.
+func CommitPiece(ctx context.Context, store PieceStore, key string, value []byte, commit func() error) error {
+	if err := store.StagePiece(ctx, key, value); err != nil {
+		_ = store.DeleteStagedPiece(ctx, key)
+		return err
+	}
+	if err := store.PromotePiece(ctx, key); err != nil {
+		_ = store.DeleteStagedPiece(ctx, key)
+		return err
+	}
+	if commit == nil {
+		return nil
+	}
+	return commit()
 }
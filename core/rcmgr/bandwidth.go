@@ -0,0 +1,38 @@
+package rcmgr
+
+import (
+	"context"
+)
+
+// BandwidthLimiter is the interface to shape the outbound traffic between SPs, such as
+// replicating and recovering pieces, by token bucket. It has a global limit and a limit
+// per destination SP, the limit is bytes per second and zero means unlimited.
+//
+// The traffic with ReserveTaskPriorityHigh, such as user downloads, borrows capacity
+// from the global bucket without waiting, the following lower priority traffic waits
+// until the borrowed tokens are refilled. So the priority traffic is never blocked and
+// the background traffic yields bandwidth to it.
+type BandwidthLimiter interface {
+	// WaitN blocks until n bytes can be sent to the destination at the priority, or the
+	// context is done. The empty destination is only limited by the global limit.
+	WaitN(ctx context.Context, dest string, n int, prio ReserveTaskPriority) error
+	// SetLimit adjusts the global limit and the default limit per destination at runtime.
+	SetLimit(global int64, perDestination int64)
+	// SetDestinationLimit overrides the limit of the destination at runtime, the negative
+	// limit resets the destination to the default limit per destination.
+	SetDestinationLimit(dest string, limit int64)
+	// Limit returns the global limit and the default limit per destination.
+	Limit() (int64, int64)
+}
+
+var _ BandwidthLimiter = (*NullBandwidthLimiter)(nil)
+
+// NullBandwidthLimiter is a stub for tests and initialization of default values
+type NullBandwidthLimiter struct{}
+
+func (*NullBandwidthLimiter) WaitN(context.Context, string, int, ReserveTaskPriority) error {
+	return nil
+}
+func (*NullBandwidthLimiter) SetLimit(int64, int64)             {}
+func (*NullBandwidthLimiter) SetDestinationLimit(string, int64) {}
+func (*NullBandwidthLimiter) Limit() (int64, int64)             { return 0, 0 }
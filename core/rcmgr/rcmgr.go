@@ -69,6 +69,11 @@ type ResourceManager interface {
 	// The caller owns the returned scope and is responsible for calling Done in order
 	// to signify the end of the scope's span.
 	OpenService(svc string) (ResourceScope, error)
+	// BandwidthLimiter returns the limiter to shape the outbound traffic between SPs.
+	BandwidthLimiter() BandwidthLimiter
+	// SetLimiter replaces the limits of the system and service scopes at runtime, the
+	// reserved resources are kept and the new limits apply to the following reservations.
+	SetLimiter(Limiter)
 	// Close closes the resource manager
 	Close() error
 }
@@ -248,6 +253,10 @@ func (n *NullResourceManager) ServiceState(string) string { return "" }
 func (n *NullResourceManager) OpenService(svc string) (ResourceScope, error) {
 	return &NullScope{}, nil
 }
+func (n *NullResourceManager) SetLimiter(Limiter) {}
+func (n *NullResourceManager) BandwidthLimiter() BandwidthLimiter {
+	return &NullBandwidthLimiter{}
+}
 func (n *NullResourceManager) Close() error {
 	return nil
 }
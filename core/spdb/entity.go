@@ -68,6 +68,15 @@ type BucketTraffic struct {
 	ModifyTime       int64
 }
 
+// BucketReadUsage is the read usage of bucket in one day.
+type BucketReadUsage struct {
+	BucketID   uint64
+	BucketName string
+	Day        string // Day is usage's day, format "2023-02-01".
+	ReadSize   uint64
+	ReadCount  uint64
+}
+
 // TrafficTimeRange is used by query, return records in [StartTimestampUs, EndTimestampUs).
 type TrafficTimeRange struct {
 	StartTimestampUs int64
@@ -87,3 +96,95 @@ type OffChainAuthKey struct {
 	CreatedTime  time.Time
 	ModifiedTime time.Time
 }
+
+// SecondarySpStats defines the history of replicating pieces to the secondary sp,
+// it is used by primary sp to rank the candidate secondary sps.
+type SecondarySpStats struct {
+	OperatorAddress string
+	SucceedCount    uint64
+	FailedCount     uint64
+	// AvgLatencyMs is the exponential moving average of the latency that replicates
+	// one piece to the secondary sp, only the succeed replications are counted.
+	AvgLatencyMs          int64
+	UpdateTimestampSecond int64
+}
+
+// ReplicatePieceProgress records the piece that has been replicated to the secondary sp,
+// it is used to resume the replication.
+type ReplicatePieceProgress struct {
+	ObjectID              uint64
+	ReplicateIdx          uint32
+	PieceIdx              uint32
+	SpOperatorAddress     string
+	UpdateTimestampSecond int64
+}
+
+const (
+	// RecoverJobRunning defines the recover job is generating or waiting recover piece tasks.
+	RecoverJobRunning = "RUNNING"
+	// RecoverJobPaused defines the recover job is paused by the operator.
+	RecoverJobPaused = "PAUSED"
+	// RecoverJobFinished defines all the objects of the sp have been scanned by the recover job.
+	RecoverJobFinished = "FINISHED"
+)
+
+// RecoverJob records the progress of recovering all the objects of the sp, the Cursor
+// is the db id of the last scanned object in block syncer db.
+type RecoverJob struct {
+	JobID                 uint64
+	Status                string
+	Cursor                uint64
+	ScannedObjects        uint64
+	GeneratedTasks        uint64
+	SkippedPieces         uint64
+	SucceedPieces         uint64
+	FailedPieces          uint64
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+}
+
+// define the webhook event types that can be subscribed by the bucket owner.
+const (
+	// WebhookEventObjectSealed defines the object is sealed on greenfield.
+	WebhookEventObjectSealed = "object_sealed"
+	// WebhookEventObjectSealRejected defines the sealing object is rejected on greenfield.
+	WebhookEventObjectSealRejected = "object_seal_rejected"
+	// WebhookEventObjectDeleted defines the object is deleted on greenfield.
+	WebhookEventObjectDeleted = "object_deleted"
+	// WebhookEventBucketQuotaNearExhausted defines the consumed read quota of the bucket in
+	// the month reaches the threshold of the subscription.
+	WebhookEventBucketQuotaNearExhausted = "bucket_quota_near_exhausted"
+)
+
+// WebhookSubscription defines the webhook that the events of the bucket are delivered to,
+// the payloads are signed by the Secret in HMAC-SHA256.
+type WebhookSubscription struct {
+	SubscriptionID uint64
+	BucketID       uint64
+	BucketName     string
+	Owner          string
+	URL            string
+	Secret         string
+	Events         []string
+	// QuotaThreshold is the percentage of the consumed read quota to trigger the
+	// WebhookEventBucketQuotaNearExhausted event.
+	QuotaThreshold uint32
+	// QuotaNotifiedMonth is the last month that the quota event is triggered, the event
+	// is triggered at most once a month, the format is "2023-02".
+	QuotaNotifiedMonth    string
+	CreateTimestampSecond int64
+}
+
+// WebhookDelivery defines the pending delivery of the event to the subscription, the
+// EventID is unique in the subscription to avoid delivering the event repeatedly.
+type WebhookDelivery struct {
+	DeliveryID                 uint64
+	SubscriptionID             uint64
+	EventID                    string
+	EventType                  string
+	Payload                    string
+	Attempts                   uint32
+	NextAttemptTimestampSecond int64
+	LastError                  string
+	CreateTimestampSecond      int64
+}
@@ -1,6 +1,7 @@
 package spdb
 
 import (
+	"context"
 	"time"
 
 	storetypes "github.com/bnb-chain/greenfield-storage-provider/store/types"
@@ -40,6 +41,9 @@ type UploadObjectProgressDB interface {
 	UpdateUploadProgress(uploadMeta *UploadObjectMeta) error
 	// GetUploadState queries the task state by object id.
 	GetUploadState(objectID uint64) (storetypes.TaskState, error)
+	// GetUploadMeta queries the upload object progress by object id, it is used to re-create
+	// the failed task.
+	GetUploadMeta(objectID uint64) (*UploadObjectMeta, error)
 	// GetUploadMetasToReplicate queries the latest upload_done/replicate_doing object to continue replicate.
 	// It is only used in startup.
 	GetUploadMetasToReplicate(limit int) ([]*UploadObjectMeta, error)
@@ -50,6 +54,20 @@ type UploadObjectProgressDB interface {
 	InsertUploadEvent(objectID uint64, state string, description string) error
 }
 
+// ReplicateProgressDB interface which records the pieces that have been replicated to
+// secondary sps, it is used to resume the replication and only resend the missing pieces.
+type ReplicateProgressDB interface {
+	// SetReplicatePieceProgress records the piece has been replicated to the secondary sp.
+	SetReplicatePieceProgress(progress *ReplicatePieceProgress) error
+	// GetReplicatePieceProgress returns all the replicated pieces of the object.
+	GetReplicatePieceProgress(objectID uint64) ([]*ReplicatePieceProgress, error)
+	// DeleteReplicatePieceProgress deletes the replicated pieces of the replicate index,
+	// it is used when switching the secondary sp of the replicate index.
+	DeleteReplicatePieceProgress(objectID uint64, replicateIdx uint32) error
+	// DeleteAllReplicatePieceProgress deletes all the replicated pieces of the object.
+	DeleteAllReplicatePieceProgress(objectID uint64) error
+}
+
 // GCObjectProgressDB interface which records gc object related progress.
 type GCObjectProgressDB interface {
 	// InsertGCObjectProgress inserts a new gc object progress.
@@ -104,6 +122,9 @@ type TrafficDB interface {
 	GetObjectReadRecord(objectID uint64, timeRange *TrafficTimeRange) ([]*ReadRecord, error)
 	// GetUserReadRecord return user record list by time range.
 	GetUserReadRecord(userAddress string, timeRange *TrafficTimeRange) ([]*ReadRecord, error)
+	// GetBucketReadUsage return the daily read usage of bucket in [startDay, endDay),
+	// the day format is "2023-02-01".
+	GetBucketReadUsage(bucketID uint64, startDay, endDay string) ([]*BucketReadUsage, error)
 }
 
 // SPInfoDB defines a series of sp interfaces.
@@ -132,11 +153,89 @@ type OffChainAuthKeyDB interface {
 	InsertAuthKey(newRecord *OffChainAuthKey) error
 }
 
+// SecondarySpStatsDB defines a series of secondary sp replicate stats interfaces.
+type SecondarySpStatsDB interface {
+	// UpdateSecondarySpStats accumulates the result of replicating pieces to the secondary sp,
+	// the latency is the average cost of replicating one piece.
+	UpdateSecondarySpStats(operatorAddress string, succeed bool, latency time.Duration) error
+	// GetSecondarySpStats returns the stats of the secondary sps by operator address,
+	// the sp that has no history is absent in the result.
+	GetSecondarySpStats(operatorAddresses []string) (map[string]*SecondarySpStats, error)
+}
+
+// RecoverJobDB defines a series of full sp recover job interfaces.
+type RecoverJobDB interface {
+	// InsertRecoverJob inserts a new recover job, the job id is assigned by db.
+	InsertRecoverJob(job *RecoverJob) (uint64, error)
+	// GetLatestRecoverJob returns the latest recover job, it returns nil if there is no job.
+	GetLatestRecoverJob() (*RecoverJob, error)
+	// UpdateRecoverJobStatus updates the status of the recover job.
+	UpdateRecoverJobStatus(jobID uint64, status string) error
+	// UpdateRecoverJobProgress moves the cursor of the recover job forward and accumulates
+	// the numbers of the scanned objects, generated tasks and skipped pieces.
+	UpdateRecoverJobProgress(jobID uint64, cursor uint64, scanned uint64, generated uint64, skipped uint64) error
+	// IncreaseRecoverJobPieces accumulates the number of the succeed or failed recovered pieces.
+	IncreaseRecoverJobPieces(jobID uint64, succeed bool) error
+}
+
+// WebhookDB defines a series of webhook subscription and delivery interfaces.
+type WebhookDB interface {
+	// InsertWebhookSubscription inserts a new webhook subscription, the subscription id is assigned by db.
+	InsertWebhookSubscription(sub *WebhookSubscription) (uint64, error)
+	// DeleteWebhookSubscription deletes the webhook subscription of the bucket and its pending deliveries,
+	// it returns false if there is no such subscription.
+	DeleteWebhookSubscription(bucketID uint64, subscriptionID uint64) (bool, error)
+	// DeleteBucketWebhookSubscriptions deletes all the webhook subscriptions of the deleted bucket
+	// and their pending deliveries.
+	DeleteBucketWebhookSubscriptions(bucketID uint64) error
+	// ListWebhookSubscriptions returns the webhook subscriptions of the bucket.
+	ListWebhookSubscriptions(bucketID uint64) ([]*WebhookSubscription, error)
+	// ListAllWebhookSubscriptions returns the webhook subscriptions of all the buckets.
+	ListAllWebhookSubscriptions() ([]*WebhookSubscription, error)
+	// GetWebhookCursor returns the cursor of the last change event that has been dispatched
+	// to the deliveries, it returns 0 if no change event is dispatched.
+	GetWebhookCursor() (uint64, error)
+	// InsertWebhookDeliveries inserts the pending deliveries of the change events and moves
+	// the cursor forward in a transaction, the repeated deliveries are ignored.
+	InsertWebhookDeliveries(deliveries []*WebhookDelivery, cursor uint64) error
+	// InsertQuotaWebhookDelivery inserts the pending delivery of the bucket quota event if the
+	// subscription is not notified in the month, it returns false if it has been notified.
+	InsertQuotaWebhookDelivery(delivery *WebhookDelivery, yearMonth string) (bool, error)
+	// ListDueWebhookDeliveries returns the pending deliveries whose next attempt time is not after now.
+	ListDueWebhookDeliveries(now int64, limit int) ([]*WebhookDelivery, error)
+	// DeleteWebhookDelivery deletes the delivery that has been delivered.
+	DeleteWebhookDelivery(deliveryID uint64) error
+	// UpdateWebhookDeliveryRetry records the failed attempt and the next attempt time of the delivery.
+	UpdateWebhookDeliveryRetry(deliveryID uint64, nextAttemptTime int64, lastError string) error
+	// MoveWebhookDeliveryToDeadLetter moves the delivery that exhausts the attempts to the dead letter table.
+	MoveWebhookDeliveryToDeadLetter(delivery *WebhookDelivery, lastError string) error
+}
+
 type SPDB interface {
 	UploadObjectProgressDB
+	ReplicateProgressDB
 	GCObjectProgressDB
 	SignatureDB
 	TrafficDB
 	SPInfoDB
 	OffChainAuthKeyDB
+	SecondarySpStatsDB
+	RecoverJobDB
+	WebhookDB
+}
+
+// ContextBinder is implemented by the SPDB that binds the db calls to the context, so the
+// calls are traced as the children of the span in the context.
+type ContextBinder interface {
+	// WithContext returns the SPDB whose db calls are made with the context.
+	WithContext(ctx context.Context) SPDB
+}
+
+// WithContext returns the SPDB whose db calls are made with the context if the db supports
+// binding the context, otherwise it returns the db itself.
+func WithContext(ctx context.Context, db SPDB) SPDB {
+	if binder, ok := db.(ContextBinder); ok {
+		return binder.WithContext(ctx)
+	}
+	return db
 }
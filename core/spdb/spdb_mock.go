@@ -8,10 +8,8 @@ import (
 	reflect "reflect"
 	time "time"
 
-	task "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	types "github.com/bnb-chain/greenfield-storage-provider/store/types"
 	types0 "github.com/bnb-chain/greenfield/x/sp/types"
-	types1 "github.com/bnb-chain/greenfield/x/storage/types"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -38,175 +36,379 @@ func (m *MockUploadObjectProgressDB) EXPECT() *MockUploadObjectProgressDBMockRec
 	return m.recorder
 }
 
-// CreateUploadProgress mocks base method.
-func (m *MockUploadObjectProgressDB) CreateUploadProgress(objectID uint64) error {
+// DeleteUploadProgress mocks base method.
+func (m *MockUploadObjectProgressDB) DeleteUploadProgress(objectID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUploadProgress", objectID)
+	ret := m.ctrl.Call(m, "DeleteUploadProgress", objectID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateUploadProgress indicates an expected call of CreateUploadProgress.
-func (mr *MockUploadObjectProgressDBMockRecorder) CreateUploadProgress(objectID interface{}) *gomock.Call {
+// DeleteUploadProgress indicates an expected call of DeleteUploadProgress.
+func (mr *MockUploadObjectProgressDBMockRecorder) DeleteUploadProgress(objectID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).CreateUploadProgress), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).DeleteUploadProgress), objectID)
 }
 
-// QueryUploadState mocks base method.
-func (m *MockUploadObjectProgressDB) QueryUploadState(objectID uint64) (types.TaskState, error) {
+// GetUploadMeta mocks base method.
+func (m *MockUploadObjectProgressDB) GetUploadMeta(objectID uint64) (*UploadObjectMeta, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUploadState", objectID)
+	ret := m.ctrl.Call(m, "GetUploadMeta", objectID)
+	ret0, _ := ret[0].(*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMeta indicates an expected call of GetUploadMeta.
+func (mr *MockUploadObjectProgressDBMockRecorder) GetUploadMeta(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMeta", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).GetUploadMeta), objectID)
+}
+
+// GetUploadMetasToReplicate mocks base method.
+func (m *MockUploadObjectProgressDB) GetUploadMetasToReplicate(limit int) ([]*UploadObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadMetasToReplicate", limit)
+	ret0, _ := ret[0].([]*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMetasToReplicate indicates an expected call of GetUploadMetasToReplicate.
+func (mr *MockUploadObjectProgressDBMockRecorder) GetUploadMetasToReplicate(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMetasToReplicate", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).GetUploadMetasToReplicate), limit)
+}
+
+// GetUploadMetasToSeal mocks base method.
+func (m *MockUploadObjectProgressDB) GetUploadMetasToSeal(limit int) ([]*UploadObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadMetasToSeal", limit)
+	ret0, _ := ret[0].([]*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMetasToSeal indicates an expected call of GetUploadMetasToSeal.
+func (mr *MockUploadObjectProgressDBMockRecorder) GetUploadMetasToSeal(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMetasToSeal", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).GetUploadMetasToSeal), limit)
+}
+
+// GetUploadState mocks base method.
+func (m *MockUploadObjectProgressDB) GetUploadState(objectID uint64) (types.TaskState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadState", objectID)
 	ret0, _ := ret[0].(types.TaskState)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// QueryUploadState indicates an expected call of QueryUploadState.
-func (mr *MockUploadObjectProgressDBMockRecorder) QueryUploadState(objectID interface{}) *gomock.Call {
+// GetUploadState indicates an expected call of GetUploadState.
+func (mr *MockUploadObjectProgressDBMockRecorder) GetUploadState(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadState", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).GetUploadState), objectID)
+}
+
+// InsertUploadEvent mocks base method.
+func (m *MockUploadObjectProgressDB) InsertUploadEvent(objectID uint64, state, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUploadEvent", objectID, state, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUploadEvent indicates an expected call of InsertUploadEvent.
+func (mr *MockUploadObjectProgressDBMockRecorder) InsertUploadEvent(objectID, state, description interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUploadState", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).QueryUploadState), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUploadEvent", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).InsertUploadEvent), objectID, state, description)
+}
+
+// InsertUploadProgress mocks base method.
+func (m *MockUploadObjectProgressDB) InsertUploadProgress(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUploadProgress", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUploadProgress indicates an expected call of InsertUploadProgress.
+func (mr *MockUploadObjectProgressDBMockRecorder) InsertUploadProgress(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).InsertUploadProgress), objectID)
 }
 
 // UpdateUploadProgress mocks base method.
-func (m *MockUploadObjectProgressDB) UpdateUploadProgress(objectID uint64, taskState types.TaskState, errorDescription string) error {
+func (m *MockUploadObjectProgressDB) UpdateUploadProgress(uploadMeta *UploadObjectMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUploadProgress", objectID, taskState, errorDescription)
+	ret := m.ctrl.Call(m, "UpdateUploadProgress", uploadMeta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUploadProgress indicates an expected call of UpdateUploadProgress.
-func (mr *MockUploadObjectProgressDBMockRecorder) UpdateUploadProgress(objectID, taskState, errorDescription interface{}) *gomock.Call {
+func (mr *MockUploadObjectProgressDBMockRecorder) UpdateUploadProgress(uploadMeta interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).UpdateUploadProgress), objectID, taskState, errorDescription)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).UpdateUploadProgress), uploadMeta)
 }
 
-// MockObjectIntegrityDB is a mock of ObjectIntegrityDB interface.
-type MockObjectIntegrityDB struct {
+// MockReplicateProgressDB is a mock of ReplicateProgressDB interface.
+type MockReplicateProgressDB struct {
 	ctrl     *gomock.Controller
-	recorder *MockObjectIntegrityDBMockRecorder
+	recorder *MockReplicateProgressDBMockRecorder
 }
 
-// MockObjectIntegrityDBMockRecorder is the mock recorder for MockObjectIntegrityDB.
-type MockObjectIntegrityDBMockRecorder struct {
-	mock *MockObjectIntegrityDB
+// MockReplicateProgressDBMockRecorder is the mock recorder for MockReplicateProgressDB.
+type MockReplicateProgressDBMockRecorder struct {
+	mock *MockReplicateProgressDB
 }
 
-// NewMockObjectIntegrityDB creates a new mock instance.
-func NewMockObjectIntegrityDB(ctrl *gomock.Controller) *MockObjectIntegrityDB {
-	mock := &MockObjectIntegrityDB{ctrl: ctrl}
-	mock.recorder = &MockObjectIntegrityDBMockRecorder{mock}
+// NewMockReplicateProgressDB creates a new mock instance.
+func NewMockReplicateProgressDB(ctrl *gomock.Controller) *MockReplicateProgressDB {
+	mock := &MockReplicateProgressDB{ctrl: ctrl}
+	mock.recorder = &MockReplicateProgressDBMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockObjectIntegrityDB) EXPECT() *MockObjectIntegrityDBMockRecorder {
+func (m *MockReplicateProgressDB) EXPECT() *MockReplicateProgressDBMockRecorder {
 	return m.recorder
 }
 
-// DeleteAllReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) DeleteAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32) error {
+// DeleteAllReplicatePieceProgress mocks base method.
+func (m *MockReplicateProgressDB) DeleteAllReplicatePieceProgress(objectID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount)
+	ret := m.ctrl.Call(m, "DeleteAllReplicatePieceProgress", objectID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAllReplicatePieceChecksum indicates an expected call of DeleteAllReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) DeleteAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount interface{}) *gomock.Call {
+// DeleteAllReplicatePieceProgress indicates an expected call of DeleteAllReplicatePieceProgress.
+func (mr *MockReplicateProgressDBMockRecorder) DeleteAllReplicatePieceProgress(objectID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).DeleteAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceProgress", reflect.TypeOf((*MockReplicateProgressDB)(nil).DeleteAllReplicatePieceProgress), objectID)
 }
 
-// DeleteObjectIntegrity mocks base method.
-func (m *MockObjectIntegrityDB) DeleteObjectIntegrity(objectID uint64) error {
+// DeleteReplicatePieceProgress mocks base method.
+func (m *MockReplicateProgressDB) DeleteReplicatePieceProgress(objectID uint64, replicateIdx uint32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteObjectIntegrity", objectID)
+	ret := m.ctrl.Call(m, "DeleteReplicatePieceProgress", objectID, replicateIdx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteObjectIntegrity indicates an expected call of DeleteObjectIntegrity.
-func (mr *MockObjectIntegrityDBMockRecorder) DeleteObjectIntegrity(objectID interface{}) *gomock.Call {
+// DeleteReplicatePieceProgress indicates an expected call of DeleteReplicatePieceProgress.
+func (mr *MockReplicateProgressDBMockRecorder) DeleteReplicatePieceProgress(objectID, replicateIdx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceProgress", reflect.TypeOf((*MockReplicateProgressDB)(nil).DeleteReplicatePieceProgress), objectID, replicateIdx)
+}
+
+// GetReplicatePieceProgress mocks base method.
+func (m *MockReplicateProgressDB) GetReplicatePieceProgress(objectID uint64) ([]*ReplicatePieceProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicatePieceProgress", objectID)
+	ret0, _ := ret[0].([]*ReplicatePieceProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicatePieceProgress indicates an expected call of GetReplicatePieceProgress.
+func (mr *MockReplicateProgressDBMockRecorder) GetReplicatePieceProgress(objectID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectIntegrity", reflect.TypeOf((*MockObjectIntegrityDB)(nil).DeleteObjectIntegrity), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicatePieceProgress", reflect.TypeOf((*MockReplicateProgressDB)(nil).GetReplicatePieceProgress), objectID)
 }
 
-// DeleteReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) DeleteReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32) error {
+// SetReplicatePieceProgress mocks base method.
+func (m *MockReplicateProgressDB) SetReplicatePieceProgress(progress *ReplicatePieceProgress) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteReplicatePieceChecksum", objectID, replicateIdx, pieceIdx)
+	ret := m.ctrl.Call(m, "SetReplicatePieceProgress", progress)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteReplicatePieceChecksum indicates an expected call of DeleteReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) DeleteReplicatePieceChecksum(objectID, replicateIdx, pieceIdx interface{}) *gomock.Call {
+// SetReplicatePieceProgress indicates an expected call of SetReplicatePieceProgress.
+func (mr *MockReplicateProgressDBMockRecorder) SetReplicatePieceProgress(progress interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).DeleteReplicatePieceChecksum), objectID, replicateIdx, pieceIdx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceProgress", reflect.TypeOf((*MockReplicateProgressDB)(nil).SetReplicatePieceProgress), progress)
 }
 
-// GetAllReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) GetAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32) ([][]byte, error) {
+// MockGCObjectProgressDB is a mock of GCObjectProgressDB interface.
+type MockGCObjectProgressDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockGCObjectProgressDBMockRecorder
+}
+
+// MockGCObjectProgressDBMockRecorder is the mock recorder for MockGCObjectProgressDB.
+type MockGCObjectProgressDBMockRecorder struct {
+	mock *MockGCObjectProgressDB
+}
+
+// NewMockGCObjectProgressDB creates a new mock instance.
+func NewMockGCObjectProgressDB(ctrl *gomock.Controller) *MockGCObjectProgressDB {
+	mock := &MockGCObjectProgressDB{ctrl: ctrl}
+	mock.recorder = &MockGCObjectProgressDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGCObjectProgressDB) EXPECT() *MockGCObjectProgressDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteGCObjectProgress mocks base method.
+func (m *MockGCObjectProgressDB) DeleteGCObjectProgress(taskKey string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount)
-	ret0, _ := ret[0].([][]byte)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "DeleteGCObjectProgress", taskKey)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetAllReplicatePieceChecksum indicates an expected call of GetAllReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) GetAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount interface{}) *gomock.Call {
+// DeleteGCObjectProgress indicates an expected call of DeleteGCObjectProgress.
+func (mr *MockGCObjectProgressDBMockRecorder) DeleteGCObjectProgress(taskKey interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).GetAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).DeleteGCObjectProgress), taskKey)
 }
 
-// GetObjectIntegrity mocks base method.
-func (m *MockObjectIntegrityDB) GetObjectIntegrity(objectID uint64) (*IntegrityMeta, error) {
+// GetGCMetasToGC mocks base method.
+func (m *MockGCObjectProgressDB) GetGCMetasToGC(limit int) ([]*GCObjectMeta, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetObjectIntegrity", objectID)
-	ret0, _ := ret[0].(*IntegrityMeta)
+	ret := m.ctrl.Call(m, "GetGCMetasToGC", limit)
+	ret0, _ := ret[0].([]*GCObjectMeta)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetObjectIntegrity indicates an expected call of GetObjectIntegrity.
-func (mr *MockObjectIntegrityDBMockRecorder) GetObjectIntegrity(objectID interface{}) *gomock.Call {
+// GetGCMetasToGC indicates an expected call of GetGCMetasToGC.
+func (mr *MockGCObjectProgressDBMockRecorder) GetGCMetasToGC(limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectIntegrity", reflect.TypeOf((*MockObjectIntegrityDB)(nil).GetObjectIntegrity), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGCMetasToGC", reflect.TypeOf((*MockGCObjectProgressDB)(nil).GetGCMetasToGC), limit)
 }
 
-// GetReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) GetReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32) ([]byte, error) {
+// InsertGCObjectProgress mocks base method.
+func (m *MockGCObjectProgressDB) InsertGCObjectProgress(taskKey string, gcMeta *GCObjectMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReplicatePieceChecksum", objectID, replicateIdx, pieceIdx)
-	ret0, _ := ret[0].([]byte)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "InsertGCObjectProgress", taskKey, gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertGCObjectProgress indicates an expected call of InsertGCObjectProgress.
+func (mr *MockGCObjectProgressDBMockRecorder) InsertGCObjectProgress(taskKey, gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).InsertGCObjectProgress), taskKey, gcMeta)
+}
+
+// UpdateGCObjectProgress mocks base method.
+func (m *MockGCObjectProgressDB) UpdateGCObjectProgress(gcMeta *GCObjectMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCObjectProgress", gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCObjectProgress indicates an expected call of UpdateGCObjectProgress.
+func (mr *MockGCObjectProgressDBMockRecorder) UpdateGCObjectProgress(gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).UpdateGCObjectProgress), gcMeta)
+}
+
+// MockSignatureDB is a mock of SignatureDB interface.
+type MockSignatureDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignatureDBMockRecorder
+}
+
+// MockSignatureDBMockRecorder is the mock recorder for MockSignatureDB.
+type MockSignatureDBMockRecorder struct {
+	mock *MockSignatureDB
+}
+
+// NewMockSignatureDB creates a new mock instance.
+func NewMockSignatureDB(ctrl *gomock.Controller) *MockSignatureDB {
+	mock := &MockSignatureDB{ctrl: ctrl}
+	mock.recorder = &MockSignatureDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSignatureDB) EXPECT() *MockSignatureDBMockRecorder {
+	return m.recorder
+}
+
+// AppendObjectChecksumIntegrity mocks base method.
+func (m *MockSignatureDB) AppendObjectChecksumIntegrity(objectID uint64, checksum []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendObjectChecksumIntegrity", objectID, checksum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendObjectChecksumIntegrity indicates an expected call of AppendObjectChecksumIntegrity.
+func (mr *MockSignatureDBMockRecorder) AppendObjectChecksumIntegrity(objectID, checksum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendObjectChecksumIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).AppendObjectChecksumIntegrity), objectID, checksum)
+}
+
+// DeleteAllReplicatePieceChecksum mocks base method.
+func (m *MockSignatureDB) DeleteAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetReplicatePieceChecksum indicates an expected call of GetReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) GetReplicatePieceChecksum(objectID, replicateIdx, pieceIdx interface{}) *gomock.Call {
+// DeleteAllReplicatePieceChecksum indicates an expected call of DeleteAllReplicatePieceChecksum.
+func (mr *MockSignatureDBMockRecorder) DeleteAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).GetReplicatePieceChecksum), objectID, replicateIdx, pieceIdx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).DeleteAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount)
 }
 
-// SetAllReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) SetAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32, checksum [][]byte) error {
+// DeleteObjectIntegrity mocks base method.
+func (m *MockSignatureDB) DeleteObjectIntegrity(objectID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount, checksum)
+	ret := m.ctrl.Call(m, "DeleteObjectIntegrity", objectID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetAllReplicatePieceChecksum indicates an expected call of SetAllReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) SetAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount, checksum interface{}) *gomock.Call {
+// DeleteObjectIntegrity indicates an expected call of DeleteObjectIntegrity.
+func (mr *MockSignatureDBMockRecorder) DeleteObjectIntegrity(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).DeleteObjectIntegrity), objectID)
+}
+
+// GetAllReplicatePieceChecksum mocks base method.
+func (m *MockSignatureDB) GetAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllReplicatePieceChecksum indicates an expected call of GetAllReplicatePieceChecksum.
+func (mr *MockSignatureDBMockRecorder) GetAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReplicatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).GetAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount)
+}
+
+// GetObjectIntegrity mocks base method.
+func (m *MockSignatureDB) GetObjectIntegrity(objectID uint64) (*IntegrityMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectIntegrity", objectID)
+	ret0, _ := ret[0].(*IntegrityMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectIntegrity indicates an expected call of GetObjectIntegrity.
+func (mr *MockSignatureDBMockRecorder) GetObjectIntegrity(objectID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).SetAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount, checksum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).GetObjectIntegrity), objectID)
 }
 
 // SetObjectIntegrity mocks base method.
-func (m *MockObjectIntegrityDB) SetObjectIntegrity(integrity *IntegrityMeta) error {
+func (m *MockSignatureDB) SetObjectIntegrity(integrity *IntegrityMeta) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetObjectIntegrity", integrity)
 	ret0, _ := ret[0].(error)
@@ -214,13 +416,13 @@ func (m *MockObjectIntegrityDB) SetObjectIntegrity(integrity *IntegrityMeta) err
 }
 
 // SetObjectIntegrity indicates an expected call of SetObjectIntegrity.
-func (mr *MockObjectIntegrityDBMockRecorder) SetObjectIntegrity(integrity interface{}) *gomock.Call {
+func (mr *MockSignatureDBMockRecorder) SetObjectIntegrity(integrity interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetObjectIntegrity", reflect.TypeOf((*MockObjectIntegrityDB)(nil).SetObjectIntegrity), integrity)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetObjectIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).SetObjectIntegrity), integrity)
 }
 
 // SetReplicatePieceChecksum mocks base method.
-func (m *MockObjectIntegrityDB) SetReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32, checksum []byte) error {
+func (m *MockSignatureDB) SetReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32, checksum []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SetReplicatePieceChecksum", objectID, replicateIdx, pieceIdx, checksum)
 	ret0, _ := ret[0].(error)
@@ -228,9 +430,9 @@ func (m *MockObjectIntegrityDB) SetReplicatePieceChecksum(objectID uint64, repli
 }
 
 // SetReplicatePieceChecksum indicates an expected call of SetReplicatePieceChecksum.
-func (mr *MockObjectIntegrityDBMockRecorder) SetReplicatePieceChecksum(objectID, replicateIdx, pieceIdx, checksum interface{}) *gomock.Call {
+func (mr *MockSignatureDBMockRecorder) SetReplicatePieceChecksum(objectID, replicateIdx, pieceIdx, checksum interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceChecksum", reflect.TypeOf((*MockObjectIntegrityDB)(nil).SetReplicatePieceChecksum), objectID, replicateIdx, pieceIdx, checksum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).SetReplicatePieceChecksum), objectID, replicateIdx, pieceIdx, checksum)
 }
 
 // MockTrafficDB is a mock of TrafficDB interface.
@@ -285,6 +487,21 @@ func (mr *MockTrafficDBMockRecorder) GetBucketReadRecord(bucketID, timeRange int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).GetBucketReadRecord), bucketID, timeRange)
 }
 
+// GetBucketReadUsage mocks base method.
+func (m *MockTrafficDB) GetBucketReadUsage(bucketID uint64, startDay, endDay string) ([]*BucketReadUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketReadUsage", bucketID, startDay, endDay)
+	ret0, _ := ret[0].([]*BucketReadUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketReadUsage indicates an expected call of GetBucketReadUsage.
+func (mr *MockTrafficDBMockRecorder) GetBucketReadUsage(bucketID, startDay, endDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketReadUsage", reflect.TypeOf((*MockTrafficDB)(nil).GetBucketReadUsage), bucketID, startDay, endDay)
+}
+
 // GetBucketTraffic mocks base method.
 func (m *MockTrafficDB) GetBucketTraffic(bucketID uint64, yearMonth string) (*BucketTraffic, error) {
 	m.ctrl.T.Helper()
@@ -479,187 +696,415 @@ func (mr *MockSPInfoDBMockRecorder) UpdateAllSp(spList interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAllSp", reflect.TypeOf((*MockSPInfoDB)(nil).UpdateAllSp), spList)
 }
 
-// MockGCObjectProgressDB is a mock of GCObjectProgressDB interface.
-type MockGCObjectProgressDB struct {
+// MockOffChainAuthKeyDB is a mock of OffChainAuthKeyDB interface.
+type MockOffChainAuthKeyDB struct {
 	ctrl     *gomock.Controller
-	recorder *MockGCObjectProgressDBMockRecorder
+	recorder *MockOffChainAuthKeyDBMockRecorder
 }
 
-// MockGCObjectProgressDBMockRecorder is the mock recorder for MockGCObjectProgressDB.
-type MockGCObjectProgressDBMockRecorder struct {
-	mock *MockGCObjectProgressDB
+// MockOffChainAuthKeyDBMockRecorder is the mock recorder for MockOffChainAuthKeyDB.
+type MockOffChainAuthKeyDBMockRecorder struct {
+	mock *MockOffChainAuthKeyDB
 }
 
-// NewMockGCObjectProgressDB creates a new mock instance.
-func NewMockGCObjectProgressDB(ctrl *gomock.Controller) *MockGCObjectProgressDB {
-	mock := &MockGCObjectProgressDB{ctrl: ctrl}
-	mock.recorder = &MockGCObjectProgressDBMockRecorder{mock}
+// NewMockOffChainAuthKeyDB creates a new mock instance.
+func NewMockOffChainAuthKeyDB(ctrl *gomock.Controller) *MockOffChainAuthKeyDB {
+	mock := &MockOffChainAuthKeyDB{ctrl: ctrl}
+	mock.recorder = &MockOffChainAuthKeyDBMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockGCObjectProgressDB) EXPECT() *MockGCObjectProgressDBMockRecorder {
+func (m *MockOffChainAuthKeyDB) EXPECT() *MockOffChainAuthKeyDBMockRecorder {
 	return m.recorder
 }
 
-// DeleteGCObjectProgress mocks base method.
-func (m *MockGCObjectProgressDB) DeleteGCObjectProgress(taskKey string) error {
+// GetAuthKey mocks base method.
+func (m *MockOffChainAuthKeyDB) GetAuthKey(userAddress, domain string) (*OffChainAuthKey, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteGCObjectProgress", taskKey)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetAuthKey", userAddress, domain)
+	ret0, _ := ret[0].(*OffChainAuthKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteGCObjectProgress indicates an expected call of DeleteGCObjectProgress.
-func (mr *MockGCObjectProgressDBMockRecorder) DeleteGCObjectProgress(taskKey interface{}) *gomock.Call {
+// GetAuthKey indicates an expected call of GetAuthKey.
+func (mr *MockOffChainAuthKeyDBMockRecorder) GetAuthKey(userAddress, domain interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).DeleteGCObjectProgress), taskKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).GetAuthKey), userAddress, domain)
 }
 
-// GetAllGCObjectTask mocks base method.
-func (m *MockGCObjectProgressDB) GetAllGCObjectTask(taskKey string) []task.GCObjectTask {
+// InsertAuthKey mocks base method.
+func (m *MockOffChainAuthKeyDB) InsertAuthKey(newRecord *OffChainAuthKey) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllGCObjectTask", taskKey)
-	ret0, _ := ret[0].([]task.GCObjectTask)
+	ret := m.ctrl.Call(m, "InsertAuthKey", newRecord)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// GetAllGCObjectTask indicates an expected call of GetAllGCObjectTask.
-func (mr *MockGCObjectProgressDBMockRecorder) GetAllGCObjectTask(taskKey interface{}) *gomock.Call {
+// InsertAuthKey indicates an expected call of InsertAuthKey.
+func (mr *MockOffChainAuthKeyDBMockRecorder) InsertAuthKey(newRecord interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGCObjectTask", reflect.TypeOf((*MockGCObjectProgressDB)(nil).GetAllGCObjectTask), taskKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).InsertAuthKey), newRecord)
 }
 
-// SetGCObjectProgress mocks base method.
-func (m *MockGCObjectProgressDB) SetGCObjectProgress(taskKey string, deletingBlockID, deletedObjectID uint64) error {
+// UpdateAuthKey mocks base method.
+func (m *MockOffChainAuthKeyDB) UpdateAuthKey(userAddress, domain string, oldNonce, newNonce int32, newPublicKey string, newExpiryDate time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetGCObjectProgress", taskKey, deletingBlockID, deletedObjectID)
+	ret := m.ctrl.Call(m, "UpdateAuthKey", userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetGCObjectProgress indicates an expected call of SetGCObjectProgress.
-func (mr *MockGCObjectProgressDBMockRecorder) SetGCObjectProgress(taskKey, deletingBlockID, deletedObjectID interface{}) *gomock.Call {
+// UpdateAuthKey indicates an expected call of UpdateAuthKey.
+func (mr *MockOffChainAuthKeyDBMockRecorder) UpdateAuthKey(userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).SetGCObjectProgress), taskKey, deletingBlockID, deletedObjectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).UpdateAuthKey), userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
 }
 
-// MockStorageParamDB is a mock of StorageParamDB interface.
-type MockStorageParamDB struct {
+// MockSecondarySpStatsDB is a mock of SecondarySpStatsDB interface.
+type MockSecondarySpStatsDB struct {
 	ctrl     *gomock.Controller
-	recorder *MockStorageParamDBMockRecorder
+	recorder *MockSecondarySpStatsDBMockRecorder
 }
 
-// MockStorageParamDBMockRecorder is the mock recorder for MockStorageParamDB.
-type MockStorageParamDBMockRecorder struct {
-	mock *MockStorageParamDB
+// MockSecondarySpStatsDBMockRecorder is the mock recorder for MockSecondarySpStatsDB.
+type MockSecondarySpStatsDBMockRecorder struct {
+	mock *MockSecondarySpStatsDB
 }
 
-// NewMockStorageParamDB creates a new mock instance.
-func NewMockStorageParamDB(ctrl *gomock.Controller) *MockStorageParamDB {
-	mock := &MockStorageParamDB{ctrl: ctrl}
-	mock.recorder = &MockStorageParamDBMockRecorder{mock}
+// NewMockSecondarySpStatsDB creates a new mock instance.
+func NewMockSecondarySpStatsDB(ctrl *gomock.Controller) *MockSecondarySpStatsDB {
+	mock := &MockSecondarySpStatsDB{ctrl: ctrl}
+	mock.recorder = &MockSecondarySpStatsDBMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockStorageParamDB) EXPECT() *MockStorageParamDBMockRecorder {
+func (m *MockSecondarySpStatsDB) EXPECT() *MockSecondarySpStatsDBMockRecorder {
 	return m.recorder
 }
 
-// GetStorageParams mocks base method.
-func (m *MockStorageParamDB) GetStorageParams() (*types1.Params, error) {
+// GetSecondarySpStats mocks base method.
+func (m *MockSecondarySpStatsDB) GetSecondarySpStats(operatorAddresses []string) (map[string]*SecondarySpStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStorageParams")
-	ret0, _ := ret[0].(*types1.Params)
+	ret := m.ctrl.Call(m, "GetSecondarySpStats", operatorAddresses)
+	ret0, _ := ret[0].(map[string]*SecondarySpStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetStorageParams indicates an expected call of GetStorageParams.
-func (mr *MockStorageParamDBMockRecorder) GetStorageParams() *gomock.Call {
+// GetSecondarySpStats indicates an expected call of GetSecondarySpStats.
+func (mr *MockSecondarySpStatsDBMockRecorder) GetSecondarySpStats(operatorAddresses interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorageParams", reflect.TypeOf((*MockStorageParamDB)(nil).GetStorageParams))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecondarySpStats", reflect.TypeOf((*MockSecondarySpStatsDB)(nil).GetSecondarySpStats), operatorAddresses)
 }
 
-// SetStorageParams mocks base method.
-func (m *MockStorageParamDB) SetStorageParams(params *types1.Params) error {
+// UpdateSecondarySpStats mocks base method.
+func (m *MockSecondarySpStatsDB) UpdateSecondarySpStats(operatorAddress string, succeed bool, latency time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetStorageParams", params)
+	ret := m.ctrl.Call(m, "UpdateSecondarySpStats", operatorAddress, succeed, latency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetStorageParams indicates an expected call of SetStorageParams.
-func (mr *MockStorageParamDBMockRecorder) SetStorageParams(params interface{}) *gomock.Call {
+// UpdateSecondarySpStats indicates an expected call of UpdateSecondarySpStats.
+func (mr *MockSecondarySpStatsDBMockRecorder) UpdateSecondarySpStats(operatorAddress, succeed, latency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStorageParams", reflect.TypeOf((*MockStorageParamDB)(nil).SetStorageParams), params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecondarySpStats", reflect.TypeOf((*MockSecondarySpStatsDB)(nil).UpdateSecondarySpStats), operatorAddress, succeed, latency)
 }
 
-// MockOffChainAuthKeyDB is a mock of OffChainAuthKeyDB interface.
-type MockOffChainAuthKeyDB struct {
+// MockRecoverJobDB is a mock of RecoverJobDB interface.
+type MockRecoverJobDB struct {
 	ctrl     *gomock.Controller
-	recorder *MockOffChainAuthKeyDBMockRecorder
+	recorder *MockRecoverJobDBMockRecorder
 }
 
-// MockOffChainAuthKeyDBMockRecorder is the mock recorder for MockOffChainAuthKeyDB.
-type MockOffChainAuthKeyDBMockRecorder struct {
-	mock *MockOffChainAuthKeyDB
+// MockRecoverJobDBMockRecorder is the mock recorder for MockRecoverJobDB.
+type MockRecoverJobDBMockRecorder struct {
+	mock *MockRecoverJobDB
 }
 
-// NewMockOffChainAuthKeyDB creates a new mock instance.
-func NewMockOffChainAuthKeyDB(ctrl *gomock.Controller) *MockOffChainAuthKeyDB {
-	mock := &MockOffChainAuthKeyDB{ctrl: ctrl}
-	mock.recorder = &MockOffChainAuthKeyDBMockRecorder{mock}
+// NewMockRecoverJobDB creates a new mock instance.
+func NewMockRecoverJobDB(ctrl *gomock.Controller) *MockRecoverJobDB {
+	mock := &MockRecoverJobDB{ctrl: ctrl}
+	mock.recorder = &MockRecoverJobDBMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockOffChainAuthKeyDB) EXPECT() *MockOffChainAuthKeyDBMockRecorder {
+func (m *MockRecoverJobDB) EXPECT() *MockRecoverJobDBMockRecorder {
 	return m.recorder
 }
 
-// GetAuthKey mocks base method.
-func (m *MockOffChainAuthKeyDB) GetAuthKey(userAddress, domain string) (*OffChainAuthKey, error) {
+// GetLatestRecoverJob mocks base method.
+func (m *MockRecoverJobDB) GetLatestRecoverJob() (*RecoverJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAuthKey", userAddress, domain)
-	ret0, _ := ret[0].(*OffChainAuthKey)
+	ret := m.ctrl.Call(m, "GetLatestRecoverJob")
+	ret0, _ := ret[0].(*RecoverJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAuthKey indicates an expected call of GetAuthKey.
-func (mr *MockOffChainAuthKeyDBMockRecorder) GetAuthKey(userAddress, domain interface{}) *gomock.Call {
+// GetLatestRecoverJob indicates an expected call of GetLatestRecoverJob.
+func (mr *MockRecoverJobDBMockRecorder) GetLatestRecoverJob() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).GetAuthKey), userAddress, domain)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestRecoverJob", reflect.TypeOf((*MockRecoverJobDB)(nil).GetLatestRecoverJob))
 }
 
-// InsertAuthKey mocks base method.
-func (m *MockOffChainAuthKeyDB) InsertAuthKey(newRecord *OffChainAuthKey) error {
+// IncreaseRecoverJobPieces mocks base method.
+func (m *MockRecoverJobDB) IncreaseRecoverJobPieces(jobID uint64, succeed bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertAuthKey", newRecord)
+	ret := m.ctrl.Call(m, "IncreaseRecoverJobPieces", jobID, succeed)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertAuthKey indicates an expected call of InsertAuthKey.
-func (mr *MockOffChainAuthKeyDBMockRecorder) InsertAuthKey(newRecord interface{}) *gomock.Call {
+// IncreaseRecoverJobPieces indicates an expected call of IncreaseRecoverJobPieces.
+func (mr *MockRecoverJobDBMockRecorder) IncreaseRecoverJobPieces(jobID, succeed interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).InsertAuthKey), newRecord)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseRecoverJobPieces", reflect.TypeOf((*MockRecoverJobDB)(nil).IncreaseRecoverJobPieces), jobID, succeed)
 }
 
-// UpdateAuthKey mocks base method.
-func (m *MockOffChainAuthKeyDB) UpdateAuthKey(userAddress, domain string, oldNonce, newNonce int32, newPublicKey string, newExpiryDate time.Time) error {
+// InsertRecoverJob mocks base method.
+func (m *MockRecoverJobDB) InsertRecoverJob(job *RecoverJob) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAuthKey", userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "InsertRecoverJob", job)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateAuthKey indicates an expected call of UpdateAuthKey.
-func (mr *MockOffChainAuthKeyDBMockRecorder) UpdateAuthKey(userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate interface{}) *gomock.Call {
+// InsertRecoverJob indicates an expected call of InsertRecoverJob.
+func (mr *MockRecoverJobDBMockRecorder) InsertRecoverJob(job interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthKey", reflect.TypeOf((*MockOffChainAuthKeyDB)(nil).UpdateAuthKey), userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecoverJob", reflect.TypeOf((*MockRecoverJobDB)(nil).InsertRecoverJob), job)
+}
+
+// UpdateRecoverJobProgress mocks base method.
+func (m *MockRecoverJobDB) UpdateRecoverJobProgress(jobID, cursor, scanned, generated, skipped uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoverJobProgress", jobID, cursor, scanned, generated, skipped)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoverJobProgress indicates an expected call of UpdateRecoverJobProgress.
+func (mr *MockRecoverJobDBMockRecorder) UpdateRecoverJobProgress(jobID, cursor, scanned, generated, skipped interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoverJobProgress", reflect.TypeOf((*MockRecoverJobDB)(nil).UpdateRecoverJobProgress), jobID, cursor, scanned, generated, skipped)
+}
+
+// UpdateRecoverJobStatus mocks base method.
+func (m *MockRecoverJobDB) UpdateRecoverJobStatus(jobID uint64, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoverJobStatus", jobID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoverJobStatus indicates an expected call of UpdateRecoverJobStatus.
+func (mr *MockRecoverJobDBMockRecorder) UpdateRecoverJobStatus(jobID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoverJobStatus", reflect.TypeOf((*MockRecoverJobDB)(nil).UpdateRecoverJobStatus), jobID, status)
+}
+
+// MockWebhookDB is a mock of WebhookDB interface.
+type MockWebhookDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDBMockRecorder
+}
+
+// MockWebhookDBMockRecorder is the mock recorder for MockWebhookDB.
+type MockWebhookDBMockRecorder struct {
+	mock *MockWebhookDB
+}
+
+// NewMockWebhookDB creates a new mock instance.
+func NewMockWebhookDB(ctrl *gomock.Controller) *MockWebhookDB {
+	mock := &MockWebhookDB{ctrl: ctrl}
+	mock.recorder = &MockWebhookDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDB) EXPECT() *MockWebhookDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteBucketWebhookSubscriptions mocks base method.
+func (m *MockWebhookDB) DeleteBucketWebhookSubscriptions(bucketID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketWebhookSubscriptions", bucketID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketWebhookSubscriptions indicates an expected call of DeleteBucketWebhookSubscriptions.
+func (mr *MockWebhookDBMockRecorder) DeleteBucketWebhookSubscriptions(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketWebhookSubscriptions", reflect.TypeOf((*MockWebhookDB)(nil).DeleteBucketWebhookSubscriptions), bucketID)
+}
+
+// DeleteWebhookDelivery mocks base method.
+func (m *MockWebhookDB) DeleteWebhookDelivery(deliveryID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDelivery", deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookDelivery indicates an expected call of DeleteWebhookDelivery.
+func (mr *MockWebhookDBMockRecorder) DeleteWebhookDelivery(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDelivery", reflect.TypeOf((*MockWebhookDB)(nil).DeleteWebhookDelivery), deliveryID)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockWebhookDB) DeleteWebhookSubscription(bucketID, subscriptionID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", bucketID, subscriptionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockWebhookDBMockRecorder) DeleteWebhookSubscription(bucketID, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockWebhookDB)(nil).DeleteWebhookSubscription), bucketID, subscriptionID)
+}
+
+// GetWebhookCursor mocks base method.
+func (m *MockWebhookDB) GetWebhookCursor() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookCursor")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookCursor indicates an expected call of GetWebhookCursor.
+func (mr *MockWebhookDBMockRecorder) GetWebhookCursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookCursor", reflect.TypeOf((*MockWebhookDB)(nil).GetWebhookCursor))
+}
+
+// InsertQuotaWebhookDelivery mocks base method.
+func (m *MockWebhookDB) InsertQuotaWebhookDelivery(delivery *WebhookDelivery, yearMonth string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertQuotaWebhookDelivery", delivery, yearMonth)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertQuotaWebhookDelivery indicates an expected call of InsertQuotaWebhookDelivery.
+func (mr *MockWebhookDBMockRecorder) InsertQuotaWebhookDelivery(delivery, yearMonth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertQuotaWebhookDelivery", reflect.TypeOf((*MockWebhookDB)(nil).InsertQuotaWebhookDelivery), delivery, yearMonth)
+}
+
+// InsertWebhookDeliveries mocks base method.
+func (m *MockWebhookDB) InsertWebhookDeliveries(deliveries []*WebhookDelivery, cursor uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", deliveries, cursor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockWebhookDBMockRecorder) InsertWebhookDeliveries(deliveries, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockWebhookDB)(nil).InsertWebhookDeliveries), deliveries, cursor)
+}
+
+// InsertWebhookSubscription mocks base method.
+func (m *MockWebhookDB) InsertWebhookSubscription(sub *WebhookSubscription) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookSubscription", sub)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookSubscription indicates an expected call of InsertWebhookSubscription.
+func (mr *MockWebhookDBMockRecorder) InsertWebhookSubscription(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookSubscription", reflect.TypeOf((*MockWebhookDB)(nil).InsertWebhookSubscription), sub)
+}
+
+// ListAllWebhookSubscriptions mocks base method.
+func (m *MockWebhookDB) ListAllWebhookSubscriptions() ([]*WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllWebhookSubscriptions")
+	ret0, _ := ret[0].([]*WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllWebhookSubscriptions indicates an expected call of ListAllWebhookSubscriptions.
+func (mr *MockWebhookDBMockRecorder) ListAllWebhookSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllWebhookSubscriptions", reflect.TypeOf((*MockWebhookDB)(nil).ListAllWebhookSubscriptions))
+}
+
+// ListDueWebhookDeliveries mocks base method.
+func (m *MockWebhookDB) ListDueWebhookDeliveries(now int64, limit int) ([]*WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueWebhookDeliveries", now, limit)
+	ret0, _ := ret[0].([]*WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueWebhookDeliveries indicates an expected call of ListDueWebhookDeliveries.
+func (mr *MockWebhookDBMockRecorder) ListDueWebhookDeliveries(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueWebhookDeliveries", reflect.TypeOf((*MockWebhookDB)(nil).ListDueWebhookDeliveries), now, limit)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockWebhookDB) ListWebhookSubscriptions(bucketID uint64) ([]*WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", bucketID)
+	ret0, _ := ret[0].([]*WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockWebhookDBMockRecorder) ListWebhookSubscriptions(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockWebhookDB)(nil).ListWebhookSubscriptions), bucketID)
+}
+
+// MoveWebhookDeliveryToDeadLetter mocks base method.
+func (m *MockWebhookDB) MoveWebhookDeliveryToDeadLetter(delivery *WebhookDelivery, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveWebhookDeliveryToDeadLetter", delivery, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveWebhookDeliveryToDeadLetter indicates an expected call of MoveWebhookDeliveryToDeadLetter.
+func (mr *MockWebhookDBMockRecorder) MoveWebhookDeliveryToDeadLetter(delivery, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveWebhookDeliveryToDeadLetter", reflect.TypeOf((*MockWebhookDB)(nil).MoveWebhookDeliveryToDeadLetter), delivery, lastError)
+}
+
+// UpdateWebhookDeliveryRetry mocks base method.
+func (m *MockWebhookDB) UpdateWebhookDeliveryRetry(deliveryID uint64, nextAttemptTime int64, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryRetry", deliveryID, nextAttemptTime, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryRetry indicates an expected call of UpdateWebhookDeliveryRetry.
+func (mr *MockWebhookDBMockRecorder) UpdateWebhookDeliveryRetry(deliveryID, nextAttemptTime, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryRetry", reflect.TypeOf((*MockWebhookDB)(nil).UpdateWebhookDeliveryRetry), deliveryID, nextAttemptTime, lastError)
 }
 
 // MockSPDB is a mock of SPDB interface.
@@ -685,32 +1130,32 @@ func (m *MockSPDB) EXPECT() *MockSPDBMockRecorder {
 	return m.recorder
 }
 
-// CheckQuotaAndAddReadRecord mocks base method.
-func (m *MockSPDB) CheckQuotaAndAddReadRecord(record *ReadRecord, quota *BucketQuota) error {
+// AppendObjectChecksumIntegrity mocks base method.
+func (m *MockSPDB) AppendObjectChecksumIntegrity(objectID uint64, checksum []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckQuotaAndAddReadRecord", record, quota)
+	ret := m.ctrl.Call(m, "AppendObjectChecksumIntegrity", objectID, checksum)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CheckQuotaAndAddReadRecord indicates an expected call of CheckQuotaAndAddReadRecord.
-func (mr *MockSPDBMockRecorder) CheckQuotaAndAddReadRecord(record, quota interface{}) *gomock.Call {
+// AppendObjectChecksumIntegrity indicates an expected call of AppendObjectChecksumIntegrity.
+func (mr *MockSPDBMockRecorder) AppendObjectChecksumIntegrity(objectID, checksum interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckQuotaAndAddReadRecord", reflect.TypeOf((*MockSPDB)(nil).CheckQuotaAndAddReadRecord), record, quota)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendObjectChecksumIntegrity", reflect.TypeOf((*MockSPDB)(nil).AppendObjectChecksumIntegrity), objectID, checksum)
 }
 
-// CreateUploadProgress mocks base method.
-func (m *MockSPDB) CreateUploadProgress(objectID uint64) error {
+// CheckQuotaAndAddReadRecord mocks base method.
+func (m *MockSPDB) CheckQuotaAndAddReadRecord(record *ReadRecord, quota *BucketQuota) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUploadProgress", objectID)
+	ret := m.ctrl.Call(m, "CheckQuotaAndAddReadRecord", record, quota)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateUploadProgress indicates an expected call of CreateUploadProgress.
-func (mr *MockSPDBMockRecorder) CreateUploadProgress(objectID interface{}) *gomock.Call {
+// CheckQuotaAndAddReadRecord indicates an expected call of CheckQuotaAndAddReadRecord.
+func (mr *MockSPDBMockRecorder) CheckQuotaAndAddReadRecord(record, quota interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadProgress", reflect.TypeOf((*MockSPDB)(nil).CreateUploadProgress), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckQuotaAndAddReadRecord", reflect.TypeOf((*MockSPDB)(nil).CheckQuotaAndAddReadRecord), record, quota)
 }
 
 // DeleteAllReplicatePieceChecksum mocks base method.
@@ -727,6 +1172,34 @@ func (mr *MockSPDBMockRecorder) DeleteAllReplicatePieceChecksum(objectID, replic
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).DeleteAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount)
 }
 
+// DeleteAllReplicatePieceProgress mocks base method.
+func (m *MockSPDB) DeleteAllReplicatePieceProgress(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllReplicatePieceProgress", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAllReplicatePieceProgress indicates an expected call of DeleteAllReplicatePieceProgress.
+func (mr *MockSPDBMockRecorder) DeleteAllReplicatePieceProgress(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceProgress", reflect.TypeOf((*MockSPDB)(nil).DeleteAllReplicatePieceProgress), objectID)
+}
+
+// DeleteBucketWebhookSubscriptions mocks base method.
+func (m *MockSPDB) DeleteBucketWebhookSubscriptions(bucketID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketWebhookSubscriptions", bucketID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketWebhookSubscriptions indicates an expected call of DeleteBucketWebhookSubscriptions.
+func (mr *MockSPDBMockRecorder) DeleteBucketWebhookSubscriptions(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketWebhookSubscriptions", reflect.TypeOf((*MockSPDB)(nil).DeleteBucketWebhookSubscriptions), bucketID)
+}
+
 // DeleteGCObjectProgress mocks base method.
 func (m *MockSPDB) DeleteGCObjectProgress(taskKey string) error {
 	m.ctrl.T.Helper()
@@ -755,18 +1228,61 @@ func (mr *MockSPDBMockRecorder) DeleteObjectIntegrity(objectID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectIntegrity", reflect.TypeOf((*MockSPDB)(nil).DeleteObjectIntegrity), objectID)
 }
 
-// DeleteReplicatePieceChecksum mocks base method.
-func (m *MockSPDB) DeleteReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32) error {
+// DeleteReplicatePieceProgress mocks base method.
+func (m *MockSPDB) DeleteReplicatePieceProgress(objectID uint64, replicateIdx uint32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteReplicatePieceChecksum", objectID, replicateIdx, pieceIdx)
+	ret := m.ctrl.Call(m, "DeleteReplicatePieceProgress", objectID, replicateIdx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteReplicatePieceChecksum indicates an expected call of DeleteReplicatePieceChecksum.
-func (mr *MockSPDBMockRecorder) DeleteReplicatePieceChecksum(objectID, replicateIdx, pieceIdx interface{}) *gomock.Call {
+// DeleteReplicatePieceProgress indicates an expected call of DeleteReplicatePieceProgress.
+func (mr *MockSPDBMockRecorder) DeleteReplicatePieceProgress(objectID, replicateIdx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).DeleteReplicatePieceChecksum), objectID, replicateIdx, pieceIdx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceProgress", reflect.TypeOf((*MockSPDB)(nil).DeleteReplicatePieceProgress), objectID, replicateIdx)
+}
+
+// DeleteUploadProgress mocks base method.
+func (m *MockSPDB) DeleteUploadProgress(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadProgress", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUploadProgress indicates an expected call of DeleteUploadProgress.
+func (mr *MockSPDBMockRecorder) DeleteUploadProgress(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadProgress", reflect.TypeOf((*MockSPDB)(nil).DeleteUploadProgress), objectID)
+}
+
+// DeleteWebhookDelivery mocks base method.
+func (m *MockSPDB) DeleteWebhookDelivery(deliveryID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookDelivery", deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookDelivery indicates an expected call of DeleteWebhookDelivery.
+func (mr *MockSPDBMockRecorder) DeleteWebhookDelivery(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookDelivery", reflect.TypeOf((*MockSPDB)(nil).DeleteWebhookDelivery), deliveryID)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockSPDB) DeleteWebhookSubscription(bucketID, subscriptionID uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", bucketID, subscriptionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockSPDBMockRecorder) DeleteWebhookSubscription(bucketID, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockSPDB)(nil).DeleteWebhookSubscription), bucketID, subscriptionID)
 }
 
 // FetchAllSp mocks base method.
@@ -807,20 +1323,6 @@ func (mr *MockSPDBMockRecorder) FetchAllSpWithoutOwnSp(status ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAllSpWithoutOwnSp", reflect.TypeOf((*MockSPDB)(nil).FetchAllSpWithoutOwnSp), status...)
 }
 
-// GetAllGCObjectTask mocks base method.
-func (m *MockSPDB) GetAllGCObjectTask(taskKey string) []task.GCObjectTask {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllGCObjectTask", taskKey)
-	ret0, _ := ret[0].([]task.GCObjectTask)
-	return ret0
-}
-
-// GetAllGCObjectTask indicates an expected call of GetAllGCObjectTask.
-func (mr *MockSPDBMockRecorder) GetAllGCObjectTask(taskKey interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGCObjectTask", reflect.TypeOf((*MockSPDB)(nil).GetAllGCObjectTask), taskKey)
-}
-
 // GetAllReplicatePieceChecksum mocks base method.
 func (m *MockSPDB) GetAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -866,6 +1368,21 @@ func (mr *MockSPDBMockRecorder) GetBucketReadRecord(bucketID, timeRange interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketReadRecord", reflect.TypeOf((*MockSPDB)(nil).GetBucketReadRecord), bucketID, timeRange)
 }
 
+// GetBucketReadUsage mocks base method.
+func (m *MockSPDB) GetBucketReadUsage(bucketID uint64, startDay, endDay string) ([]*BucketReadUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketReadUsage", bucketID, startDay, endDay)
+	ret0, _ := ret[0].([]*BucketReadUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketReadUsage indicates an expected call of GetBucketReadUsage.
+func (mr *MockSPDBMockRecorder) GetBucketReadUsage(bucketID, startDay, endDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketReadUsage", reflect.TypeOf((*MockSPDB)(nil).GetBucketReadUsage), bucketID, startDay, endDay)
+}
+
 // GetBucketTraffic mocks base method.
 func (m *MockSPDB) GetBucketTraffic(bucketID uint64, yearMonth string) (*BucketTraffic, error) {
 	m.ctrl.T.Helper()
@@ -881,6 +1398,36 @@ func (mr *MockSPDBMockRecorder) GetBucketTraffic(bucketID, yearMonth interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketTraffic", reflect.TypeOf((*MockSPDB)(nil).GetBucketTraffic), bucketID, yearMonth)
 }
 
+// GetGCMetasToGC mocks base method.
+func (m *MockSPDB) GetGCMetasToGC(limit int) ([]*GCObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGCMetasToGC", limit)
+	ret0, _ := ret[0].([]*GCObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGCMetasToGC indicates an expected call of GetGCMetasToGC.
+func (mr *MockSPDBMockRecorder) GetGCMetasToGC(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGCMetasToGC", reflect.TypeOf((*MockSPDB)(nil).GetGCMetasToGC), limit)
+}
+
+// GetLatestRecoverJob mocks base method.
+func (m *MockSPDB) GetLatestRecoverJob() (*RecoverJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestRecoverJob")
+	ret0, _ := ret[0].(*RecoverJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestRecoverJob indicates an expected call of GetLatestRecoverJob.
+func (mr *MockSPDBMockRecorder) GetLatestRecoverJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestRecoverJob", reflect.TypeOf((*MockSPDB)(nil).GetLatestRecoverJob))
+}
+
 // GetObjectIntegrity mocks base method.
 func (m *MockSPDB) GetObjectIntegrity(objectID uint64) (*IntegrityMeta, error) {
 	m.ctrl.T.Helper()
@@ -941,19 +1488,34 @@ func (mr *MockSPDBMockRecorder) GetReadRecord(timeRange interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadRecord", reflect.TypeOf((*MockSPDB)(nil).GetReadRecord), timeRange)
 }
 
-// GetReplicatePieceChecksum mocks base method.
-func (m *MockSPDB) GetReplicatePieceChecksum(objectID uint64, replicateIdx, pieceIdx uint32) ([]byte, error) {
+// GetReplicatePieceProgress mocks base method.
+func (m *MockSPDB) GetReplicatePieceProgress(objectID uint64) ([]*ReplicatePieceProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplicatePieceProgress", objectID)
+	ret0, _ := ret[0].([]*ReplicatePieceProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplicatePieceProgress indicates an expected call of GetReplicatePieceProgress.
+func (mr *MockSPDBMockRecorder) GetReplicatePieceProgress(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicatePieceProgress", reflect.TypeOf((*MockSPDB)(nil).GetReplicatePieceProgress), objectID)
+}
+
+// GetSecondarySpStats mocks base method.
+func (m *MockSPDB) GetSecondarySpStats(operatorAddresses []string) (map[string]*SecondarySpStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReplicatePieceChecksum", objectID, replicateIdx, pieceIdx)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "GetSecondarySpStats", operatorAddresses)
+	ret0, _ := ret[0].(map[string]*SecondarySpStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetReplicatePieceChecksum indicates an expected call of GetReplicatePieceChecksum.
-func (mr *MockSPDBMockRecorder) GetReplicatePieceChecksum(objectID, replicateIdx, pieceIdx interface{}) *gomock.Call {
+// GetSecondarySpStats indicates an expected call of GetSecondarySpStats.
+func (mr *MockSPDBMockRecorder) GetSecondarySpStats(operatorAddresses interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).GetReplicatePieceChecksum), objectID, replicateIdx, pieceIdx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecondarySpStats", reflect.TypeOf((*MockSPDB)(nil).GetSecondarySpStats), operatorAddresses)
 }
 
 // GetSpByAddress mocks base method.
@@ -986,6 +1548,66 @@ func (mr *MockSPDBMockRecorder) GetSpByEndpoint(endpoint interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpByEndpoint", reflect.TypeOf((*MockSPDB)(nil).GetSpByEndpoint), endpoint)
 }
 
+// GetUploadMeta mocks base method.
+func (m *MockSPDB) GetUploadMeta(objectID uint64) (*UploadObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadMeta", objectID)
+	ret0, _ := ret[0].(*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMeta indicates an expected call of GetUploadMeta.
+func (mr *MockSPDBMockRecorder) GetUploadMeta(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMeta", reflect.TypeOf((*MockSPDB)(nil).GetUploadMeta), objectID)
+}
+
+// GetUploadMetasToReplicate mocks base method.
+func (m *MockSPDB) GetUploadMetasToReplicate(limit int) ([]*UploadObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadMetasToReplicate", limit)
+	ret0, _ := ret[0].([]*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMetasToReplicate indicates an expected call of GetUploadMetasToReplicate.
+func (mr *MockSPDBMockRecorder) GetUploadMetasToReplicate(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMetasToReplicate", reflect.TypeOf((*MockSPDB)(nil).GetUploadMetasToReplicate), limit)
+}
+
+// GetUploadMetasToSeal mocks base method.
+func (m *MockSPDB) GetUploadMetasToSeal(limit int) ([]*UploadObjectMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadMetasToSeal", limit)
+	ret0, _ := ret[0].([]*UploadObjectMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadMetasToSeal indicates an expected call of GetUploadMetasToSeal.
+func (mr *MockSPDBMockRecorder) GetUploadMetasToSeal(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadMetasToSeal", reflect.TypeOf((*MockSPDB)(nil).GetUploadMetasToSeal), limit)
+}
+
+// GetUploadState mocks base method.
+func (m *MockSPDB) GetUploadState(objectID uint64) (types.TaskState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadState", objectID)
+	ret0, _ := ret[0].(types.TaskState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadState indicates an expected call of GetUploadState.
+func (mr *MockSPDBMockRecorder) GetUploadState(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadState", reflect.TypeOf((*MockSPDB)(nil).GetUploadState), objectID)
+}
+
 // GetUserReadRecord mocks base method.
 func (m *MockSPDB) GetUserReadRecord(userAddress string, timeRange *TrafficTimeRange) ([]*ReadRecord, error) {
 	m.ctrl.T.Helper()
@@ -1001,6 +1623,35 @@ func (mr *MockSPDBMockRecorder) GetUserReadRecord(userAddress, timeRange interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReadRecord", reflect.TypeOf((*MockSPDB)(nil).GetUserReadRecord), userAddress, timeRange)
 }
 
+// GetWebhookCursor mocks base method.
+func (m *MockSPDB) GetWebhookCursor() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookCursor")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookCursor indicates an expected call of GetWebhookCursor.
+func (mr *MockSPDBMockRecorder) GetWebhookCursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookCursor", reflect.TypeOf((*MockSPDB)(nil).GetWebhookCursor))
+}
+
+// IncreaseRecoverJobPieces mocks base method.
+func (m *MockSPDB) IncreaseRecoverJobPieces(jobID uint64, succeed bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseRecoverJobPieces", jobID, succeed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseRecoverJobPieces indicates an expected call of IncreaseRecoverJobPieces.
+func (mr *MockSPDBMockRecorder) IncreaseRecoverJobPieces(jobID, succeed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseRecoverJobPieces", reflect.TypeOf((*MockSPDB)(nil).IncreaseRecoverJobPieces), jobID, succeed)
+}
+
 // InsertAuthKey mocks base method.
 func (m *MockSPDB) InsertAuthKey(newRecord *OffChainAuthKey) error {
 	m.ctrl.T.Helper()
@@ -1015,47 +1666,164 @@ func (mr *MockSPDBMockRecorder) InsertAuthKey(newRecord interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuthKey", reflect.TypeOf((*MockSPDB)(nil).InsertAuthKey), newRecord)
 }
 
-// QueryUploadState mocks base method.
-func (m *MockSPDB) QueryUploadState(objectID uint64) (types.TaskState, error) {
+// InsertGCObjectProgress mocks base method.
+func (m *MockSPDB) InsertGCObjectProgress(taskKey string, gcMeta *GCObjectMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUploadState", objectID)
-	ret0, _ := ret[0].(types.TaskState)
+	ret := m.ctrl.Call(m, "InsertGCObjectProgress", taskKey, gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertGCObjectProgress indicates an expected call of InsertGCObjectProgress.
+func (mr *MockSPDBMockRecorder) InsertGCObjectProgress(taskKey, gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGCObjectProgress", reflect.TypeOf((*MockSPDB)(nil).InsertGCObjectProgress), taskKey, gcMeta)
+}
+
+// InsertQuotaWebhookDelivery mocks base method.
+func (m *MockSPDB) InsertQuotaWebhookDelivery(delivery *WebhookDelivery, yearMonth string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertQuotaWebhookDelivery", delivery, yearMonth)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// QueryUploadState indicates an expected call of QueryUploadState.
-func (mr *MockSPDBMockRecorder) QueryUploadState(objectID interface{}) *gomock.Call {
+// InsertQuotaWebhookDelivery indicates an expected call of InsertQuotaWebhookDelivery.
+func (mr *MockSPDBMockRecorder) InsertQuotaWebhookDelivery(delivery, yearMonth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertQuotaWebhookDelivery", reflect.TypeOf((*MockSPDB)(nil).InsertQuotaWebhookDelivery), delivery, yearMonth)
+}
+
+// InsertRecoverJob mocks base method.
+func (m *MockSPDB) InsertRecoverJob(job *RecoverJob) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRecoverJob", job)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRecoverJob indicates an expected call of InsertRecoverJob.
+func (mr *MockSPDBMockRecorder) InsertRecoverJob(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecoverJob", reflect.TypeOf((*MockSPDB)(nil).InsertRecoverJob), job)
+}
+
+// InsertUploadEvent mocks base method.
+func (m *MockSPDB) InsertUploadEvent(objectID uint64, state, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUploadEvent", objectID, state, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUploadEvent indicates an expected call of InsertUploadEvent.
+func (mr *MockSPDBMockRecorder) InsertUploadEvent(objectID, state, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUploadEvent", reflect.TypeOf((*MockSPDB)(nil).InsertUploadEvent), objectID, state, description)
+}
+
+// InsertUploadProgress mocks base method.
+func (m *MockSPDB) InsertUploadProgress(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUploadProgress", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUploadProgress indicates an expected call of InsertUploadProgress.
+func (mr *MockSPDBMockRecorder) InsertUploadProgress(objectID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUploadState", reflect.TypeOf((*MockSPDB)(nil).QueryUploadState), objectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUploadProgress", reflect.TypeOf((*MockSPDB)(nil).InsertUploadProgress), objectID)
 }
 
-// SetAllReplicatePieceChecksum mocks base method.
-func (m *MockSPDB) SetAllReplicatePieceChecksum(objectID uint64, replicateIdx, pieceCount uint32, checksum [][]byte) error {
+// InsertWebhookDeliveries mocks base method.
+func (m *MockSPDB) InsertWebhookDeliveries(deliveries []*WebhookDelivery, cursor uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetAllReplicatePieceChecksum", objectID, replicateIdx, pieceCount, checksum)
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", deliveries, cursor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetAllReplicatePieceChecksum indicates an expected call of SetAllReplicatePieceChecksum.
-func (mr *MockSPDBMockRecorder) SetAllReplicatePieceChecksum(objectID, replicateIdx, pieceCount, checksum interface{}) *gomock.Call {
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockSPDBMockRecorder) InsertWebhookDeliveries(deliveries, cursor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockSPDB)(nil).InsertWebhookDeliveries), deliveries, cursor)
+}
+
+// InsertWebhookSubscription mocks base method.
+func (m *MockSPDB) InsertWebhookSubscription(sub *WebhookSubscription) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookSubscription", sub)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookSubscription indicates an expected call of InsertWebhookSubscription.
+func (mr *MockSPDBMockRecorder) InsertWebhookSubscription(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookSubscription", reflect.TypeOf((*MockSPDB)(nil).InsertWebhookSubscription), sub)
+}
+
+// ListAllWebhookSubscriptions mocks base method.
+func (m *MockSPDB) ListAllWebhookSubscriptions() ([]*WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllWebhookSubscriptions")
+	ret0, _ := ret[0].([]*WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllWebhookSubscriptions indicates an expected call of ListAllWebhookSubscriptions.
+func (mr *MockSPDBMockRecorder) ListAllWebhookSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllWebhookSubscriptions", reflect.TypeOf((*MockSPDB)(nil).ListAllWebhookSubscriptions))
+}
+
+// ListDueWebhookDeliveries mocks base method.
+func (m *MockSPDB) ListDueWebhookDeliveries(now int64, limit int) ([]*WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueWebhookDeliveries", now, limit)
+	ret0, _ := ret[0].([]*WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueWebhookDeliveries indicates an expected call of ListDueWebhookDeliveries.
+func (mr *MockSPDBMockRecorder) ListDueWebhookDeliveries(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueWebhookDeliveries", reflect.TypeOf((*MockSPDB)(nil).ListDueWebhookDeliveries), now, limit)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockSPDB) ListWebhookSubscriptions(bucketID uint64) ([]*WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", bucketID)
+	ret0, _ := ret[0].([]*WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockSPDBMockRecorder) ListWebhookSubscriptions(bucketID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAllReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).SetAllReplicatePieceChecksum), objectID, replicateIdx, pieceCount, checksum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockSPDB)(nil).ListWebhookSubscriptions), bucketID)
 }
 
-// SetGCObjectProgress mocks base method.
-func (m *MockSPDB) SetGCObjectProgress(taskKey string, deletingBlockID, deletedObjectID uint64) error {
+// MoveWebhookDeliveryToDeadLetter mocks base method.
+func (m *MockSPDB) MoveWebhookDeliveryToDeadLetter(delivery *WebhookDelivery, lastError string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetGCObjectProgress", taskKey, deletingBlockID, deletedObjectID)
+	ret := m.ctrl.Call(m, "MoveWebhookDeliveryToDeadLetter", delivery, lastError)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetGCObjectProgress indicates an expected call of SetGCObjectProgress.
-func (mr *MockSPDBMockRecorder) SetGCObjectProgress(taskKey, deletingBlockID, deletedObjectID interface{}) *gomock.Call {
+// MoveWebhookDeliveryToDeadLetter indicates an expected call of MoveWebhookDeliveryToDeadLetter.
+func (mr *MockSPDBMockRecorder) MoveWebhookDeliveryToDeadLetter(delivery, lastError interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGCObjectProgress", reflect.TypeOf((*MockSPDB)(nil).SetGCObjectProgress), taskKey, deletingBlockID, deletedObjectID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveWebhookDeliveryToDeadLetter", reflect.TypeOf((*MockSPDB)(nil).MoveWebhookDeliveryToDeadLetter), delivery, lastError)
 }
 
 // SetObjectIntegrity mocks base method.
@@ -1100,6 +1868,20 @@ func (mr *MockSPDBMockRecorder) SetReplicatePieceChecksum(objectID, replicateIdx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).SetReplicatePieceChecksum), objectID, replicateIdx, pieceIdx, checksum)
 }
 
+// SetReplicatePieceProgress mocks base method.
+func (m *MockSPDB) SetReplicatePieceProgress(progress *ReplicatePieceProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReplicatePieceProgress", progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReplicatePieceProgress indicates an expected call of SetReplicatePieceProgress.
+func (mr *MockSPDBMockRecorder) SetReplicatePieceProgress(progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceProgress", reflect.TypeOf((*MockSPDB)(nil).SetReplicatePieceProgress), progress)
+}
+
 // UpdateAllSp mocks base method.
 func (m *MockSPDB) UpdateAllSp(spList []*types0.StorageProvider) error {
 	m.ctrl.T.Helper()
@@ -1128,16 +1910,86 @@ func (mr *MockSPDBMockRecorder) UpdateAuthKey(userAddress, domain, oldNonce, new
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthKey", reflect.TypeOf((*MockSPDB)(nil).UpdateAuthKey), userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
 }
 
+// UpdateGCObjectProgress mocks base method.
+func (m *MockSPDB) UpdateGCObjectProgress(gcMeta *GCObjectMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCObjectProgress", gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCObjectProgress indicates an expected call of UpdateGCObjectProgress.
+func (mr *MockSPDBMockRecorder) UpdateGCObjectProgress(gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCObjectProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateGCObjectProgress), gcMeta)
+}
+
+// UpdateRecoverJobProgress mocks base method.
+func (m *MockSPDB) UpdateRecoverJobProgress(jobID, cursor, scanned, generated, skipped uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoverJobProgress", jobID, cursor, scanned, generated, skipped)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoverJobProgress indicates an expected call of UpdateRecoverJobProgress.
+func (mr *MockSPDBMockRecorder) UpdateRecoverJobProgress(jobID, cursor, scanned, generated, skipped interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoverJobProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateRecoverJobProgress), jobID, cursor, scanned, generated, skipped)
+}
+
+// UpdateRecoverJobStatus mocks base method.
+func (m *MockSPDB) UpdateRecoverJobStatus(jobID uint64, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecoverJobStatus", jobID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecoverJobStatus indicates an expected call of UpdateRecoverJobStatus.
+func (mr *MockSPDBMockRecorder) UpdateRecoverJobStatus(jobID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecoverJobStatus", reflect.TypeOf((*MockSPDB)(nil).UpdateRecoverJobStatus), jobID, status)
+}
+
+// UpdateSecondarySpStats mocks base method.
+func (m *MockSPDB) UpdateSecondarySpStats(operatorAddress string, succeed bool, latency time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSecondarySpStats", operatorAddress, succeed, latency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSecondarySpStats indicates an expected call of UpdateSecondarySpStats.
+func (mr *MockSPDBMockRecorder) UpdateSecondarySpStats(operatorAddress, succeed, latency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecondarySpStats", reflect.TypeOf((*MockSPDB)(nil).UpdateSecondarySpStats), operatorAddress, succeed, latency)
+}
+
 // UpdateUploadProgress mocks base method.
-func (m *MockSPDB) UpdateUploadProgress(objectID uint64, taskState types.TaskState, errorDescription string) error {
+func (m *MockSPDB) UpdateUploadProgress(uploadMeta *UploadObjectMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUploadProgress", objectID, taskState, errorDescription)
+	ret := m.ctrl.Call(m, "UpdateUploadProgress", uploadMeta)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUploadProgress indicates an expected call of UpdateUploadProgress.
-func (mr *MockSPDBMockRecorder) UpdateUploadProgress(objectID, taskState, errorDescription interface{}) *gomock.Call {
+func (mr *MockSPDBMockRecorder) UpdateUploadProgress(uploadMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateUploadProgress), uploadMeta)
+}
+
+// UpdateWebhookDeliveryRetry mocks base method.
+func (m *MockSPDB) UpdateWebhookDeliveryRetry(deliveryID uint64, nextAttemptTime int64, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryRetry", deliveryID, nextAttemptTime, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryRetry indicates an expected call of UpdateWebhookDeliveryRetry.
+func (mr *MockSPDBMockRecorder) UpdateWebhookDeliveryRetry(deliveryID, nextAttemptTime, lastError interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUploadProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateUploadProgress), objectID, taskState, errorDescription)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryRetry", reflect.TypeOf((*MockSPDB)(nil).UpdateWebhookDeliveryRetry), deliveryID, nextAttemptTime, lastError)
 }
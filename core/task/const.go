@@ -1,5 +1,7 @@
 package task
 
+import "strings"
+
 // TKey defines the type of task key that is the uniquely identify.
 type TKey string
 
@@ -71,6 +73,17 @@ func TaskTypeName(taskType TType) string {
 	return TypeTaskMap[taskType]
 }
 
+// TaskTypeFromName returns the task type by the name in TypeTaskMap, the "Task" suffix
+// of the name can be omitted and the name is case-insensitive.
+func TaskTypeFromName(name string) TType {
+	for taskType, typeName := range TypeTaskMap {
+		if strings.EqualFold(typeName, name) || strings.EqualFold(strings.TrimSuffix(typeName, "Task"), name) {
+			return taskType
+		}
+	}
+	return TypeTaskUnknown
+}
+
 // TPriority defines the type of task priority, the priority can be used as an important
 // basis for task scheduling within the SP. The higher the priority, the faster it is
 // expected to be executed, and the resources will be assigned priority for execution.
@@ -30,6 +30,8 @@ func (*NullTask) Type() TType
 func (*NullTask) Info() string                                                          { return "" }
 func (*NullTask) GetAddress() string                                                    { return "" }
 func (*NullTask) SetAddress(string)                                                     {}
+func (*NullTask) GetTraceParent() string                                                { return "" }
+func (*NullTask) SetTraceParent(string)                                                 {}
 func (*NullTask) GetCreateTime() int64                                                  { return 0 }
 func (*NullTask) SetCreateTime(int64)                                                   {}
 func (*NullTask) GetUpdateTime() int64                                                  { return 0 }
@@ -120,6 +122,8 @@ func (*NullTask) GetEcIdx() int32                        { return 0 }
 func (*NullTask) SetSegmentIdx(uint32)                   {}
 func (*NullTask) GetRecovered() bool                     { return false }
 func (*NullTask) SetRecoverDone()                        {}
+func (*NullTask) GetRecoverSources() []string            { return nil }
+func (*NullTask) SetRecoverSources([]string)             {}
 func (*NullTask) GetRedundancyIdx() int32                { return 0 }
 func (*NullTask) SetRedundancyIdx(idx int32)             {}
 func (*NullTask) GetIntegrityHash() []byte               { return nil }
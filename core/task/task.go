@@ -25,6 +25,11 @@ type Task interface {
 	GetAddress() string
 	// SetAddress sets the runner address to the task.
 	SetAddress(string)
+	// GetTraceParent returns the trace context of the request that creates the task,
+	// the task execution is traced as a part of the request.
+	GetTraceParent() string
+	// SetTraceParent sets the trace context to the task.
+	SetTraceParent(string)
 	// GetCreateTime returns the creation time of the task. The creation time used to
 	// judge task execution time.
 	GetCreateTime() int64
@@ -484,4 +489,8 @@ type RecoveryPieceTask interface {
 	GetRecovered() bool
 	// SetRecoverDone set the recovery status as finish
 	SetRecoverDone()
+	// GetRecoverSources returns the endpoints of the SPs that the recovered piece is fetched from.
+	GetRecoverSources() []string
+	// SetRecoverSources sets the endpoints of the SPs that the recovered piece is fetched from.
+	SetRecoverSources([]string)
 }
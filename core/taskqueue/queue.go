@@ -37,8 +37,14 @@ type TQueue interface {
 	Len() int
 	// Cap returns the capacity of queue.
 	Cap() int
+	// SetCap adjusts the capacity of queue at runtime, the tasks exceeding the new capacity
+	// are kept until they are popped or retired.
+	SetCap(int)
 	// ScanTask scans all tasks, and call the func one by one task.
 	ScanTask(func(task.Task))
+	// UpdateTask calls the func with the task of the key while holding the queue lock, so the
+	// task fields can be changed safely, returns false if the task does not exist.
+	UpdateTask(task.TKey, func(task.Task)) bool
 }
 
 // TQueueWithLimit is the interface task queue that takes resources into account. Only tasks with less
@@ -58,8 +64,14 @@ type TQueueWithLimit interface {
 	Len() int
 	// Cap returns the capacity of queue.
 	Cap() int
+	// SetCap adjusts the capacity of queue at runtime, the tasks exceeding the new capacity
+	// are kept until they are popped or retired.
+	SetCap(int)
 	// ScanTask scans all tasks, and call the func one by one task.
 	ScanTask(func(task.Task))
+	// UpdateTask calls the func with the task of the key while holding the queue lock, so the
+	// task fields can be changed safely, returns false if the task does not exist.
+	UpdateTask(task.TKey, func(task.Task)) bool
 }
 
 // TQueueOnStrategy is the interface to task queue and the queue supports customize strategies to filter
@@ -123,7 +135,9 @@ func (*NilQueue) Has(task.TKey) bool                         { return false }
 func (*NilQueue) Push(task.Task) error                       { return nil }
 func (*NilQueue) Len() int                                   { return 0 }
 func (*NilQueue) Cap() int                                   { return 0 }
+func (*NilQueue) SetCap(int)                                 {}
 func (*NilQueue) ScanTask(func(task.Task))                   {}
+func (*NilQueue) UpdateTask(task.TKey, func(task.Task)) bool { return false }
 func (*NilQueue) TopByLimit(rcmgr.Limit) task.Task           { return nil }
 func (*NilQueue) PopByLimit(rcmgr.Limit) task.Task           { return nil }
 func (*NilQueue) SetFilterTaskStrategy(func(task.Task) bool) {}
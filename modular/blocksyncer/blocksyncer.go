@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"strings"
-	"sync"
 	"sync/atomic"
 
 	"github.com/forbole/juno/v4/parser"
@@ -24,7 +23,6 @@ var (
 	// DsnBlockSyncerSwitched defines env variable name for block syncer backup dsn
 	DsnBlockSyncerSwitched = "BLOCK_SYNCER_DSN_SWITCHED"
 	ErrDSNNotSet           = errors.New("dsn config is not set in environment")
-	ErrBlockNotFound       = errors.New("failed to get block from map need retry")
 )
 
 const (
@@ -34,29 +32,23 @@ const (
 	DefaultBlockHeightDiff = 100
 	// DefaultCheckDiffPeriod defines check interval of block height diff
 	DefaultCheckDiffPeriod = 1
-	// MaxHeightGapFactor defines the gap coefficient between the block height in the Map and the processed block height
-	MaxHeightGapFactor = 4
 )
 
 type MigrateDBKey struct{}
 
 // BlockSyncerModular synchronizes storage,payment,permission data to db by handling related events
 type BlockSyncerModular struct {
-	config    *config.TomlConfig
-	name      string
-	parserCtx *parser.Context
-	running   atomic.Value
-	context   context.Context
-	scope     rcmgr.ResourceScope
-	baseApp   *gfspapp.GfSpBaseApp
+	config        *config.TomlConfig
+	catchUpConfig *CatchUpConfig
+	name          string
+	parserCtx     *parser.Context
+	running       atomic.Value
+	context       context.Context
+	scope         rcmgr.ResourceScope
+	baseApp       *gfspapp.GfSpBaseApp
 }
 
-// Read concurrency required global variables
 var (
-	blockMap *sync.Map
-	eventMap *sync.Map
-	txMap    *sync.Map
-
 	MainService   *BlockSyncerModular
 	BackupService *BlockSyncerModular
 
@@ -0,0 +1,364 @@
+package blocksyncer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/database/mysql"
+	"github.com/forbole/juno/v4/modules"
+	"github.com/forbole/juno/v4/modules/messages"
+	junoregistrar "github.com/forbole/juno/v4/modules/registrar"
+	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/types"
+	"github.com/forbole/juno/v4/types/config"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	db "github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+	registrar "github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+const (
+	// DefaultCatchUpConcurrency defines the default number of goroutines to fetch the blocks in the catch-up mode
+	DefaultCatchUpConcurrency = 16
+	// DefaultCatchUpPrefetch defines the default max number of the fetched blocks that are not applied yet
+	DefaultCatchUpPrefetch = 1000
+	// DefaultCatchUpBatchSize defines the default max number of the blocks that are applied in a db transaction
+	DefaultCatchUpBatchSize = 100
+	// DefaultCatchUpThreshold defines the default lag of blocks under which the block syncer follows the tip
+	DefaultCatchUpThreshold = 10
+)
+
+var (
+	// CatchUpRetryInterval defines the interval of retrying to fetch a block or apply a batch of blocks
+	CatchUpRetryInterval = time.Second
+	// CatchUpCheckInterval defines the interval of checking the lag when the block syncer follows the
+	// tip, the block syncer enters the catch-up mode again if the lag exceeds the threshold
+	CatchUpCheckInterval = 30 * time.Second
+)
+
+// CatchUpConfig defines the catch-up mode of the block syncer, the blocks are fetched concurrently
+// with a bounded prefetch window and applied in order by batches.
+type CatchUpConfig struct {
+	Enable      bool
+	Concurrency uint64
+	Prefetch    uint64
+	BatchSize   uint64
+	Threshold   uint64
+}
+
+// makeCatchUpConfig makes the catch-up config from StorageProviderConfig, the concurrency defaults
+// to the workers of the block syncer, the batch size and the concurrency are not more than the
+// prefetch window.
+func makeCatchUpConfig(cfg *gfspconfig.GfSpConfig) *CatchUpConfig {
+	catchUpCfg := &CatchUpConfig{
+		Enable:      cfg.BlockSyncer.EnableCatchUp,
+		Concurrency: uint64(cfg.BlockSyncer.CatchUpConcurrency),
+		Prefetch:    uint64(cfg.BlockSyncer.CatchUpPrefetch),
+		BatchSize:   uint64(cfg.BlockSyncer.CatchUpBatchSize),
+		Threshold:   uint64(cfg.BlockSyncer.CatchUpThreshold),
+	}
+	if catchUpCfg.Concurrency == 0 {
+		catchUpCfg.Concurrency = uint64(cfg.BlockSyncer.Workers)
+	}
+	if catchUpCfg.Concurrency == 0 {
+		catchUpCfg.Concurrency = DefaultCatchUpConcurrency
+	}
+	if catchUpCfg.Prefetch == 0 {
+		catchUpCfg.Prefetch = DefaultCatchUpPrefetch
+	}
+	if catchUpCfg.BatchSize == 0 {
+		catchUpCfg.BatchSize = DefaultCatchUpBatchSize
+	}
+	if catchUpCfg.Threshold == 0 {
+		catchUpCfg.Threshold = DefaultCatchUpThreshold
+	}
+	if catchUpCfg.Concurrency > catchUpCfg.Prefetch {
+		catchUpCfg.Concurrency = catchUpCfg.Prefetch
+	}
+	if catchUpCfg.BatchSize > catchUpCfg.Prefetch {
+		catchUpCfg.BatchSize = catchUpCfg.Prefetch
+	}
+	return catchUpCfg
+}
+
+// fetchedBlock is the block and its results that are fetched in the catch-up mode.
+type fetchedBlock struct {
+	height uint64
+	block  *coretypes.ResultBlock
+	events *coretypes.ResultBlockResults
+	txs    []*types.Tx
+}
+
+// catchUp fetches the blocks after the last height concurrently and applies them in order by
+// batches until the lag is under the threshold, it returns the last applied height. The fetched
+// blocks that are not applied are bounded by the prefetch window, the window is released after
+// the batch is committed, so the batch is retried from the memory if it fails to apply.
+func (b *BlockSyncerModular) catchUp(ctx context.Context, lastHeight uint64) uint64 {
+	cfg := b.catchUpConfig
+	if uint64(b.latestBlockHeight()) <= lastHeight+cfg.Threshold {
+		return lastHeight
+	}
+	log.Infow("block syncer starts catching up", "service", b.Name(), "height", lastHeight,
+		"latest_height", b.latestBlockHeight())
+	metrics.BlockSyncerCatchUpGauge.WithLabelValues(b.Name()).Set(1)
+	startHeight := lastHeight + 1
+	if err := b.saveSyncStatus(ctx, bsdb.BlockSyncerModeCatchUp, startHeight, 0); err != nil {
+		log.Errorw("failed to save block syncer status", "error", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	window := make(chan struct{}, cfg.Prefetch)
+	heights := make(chan uint64)
+	fetched := make(chan *fetchedBlock, cfg.Prefetch)
+
+	// enqueue the heights until the lag is under the threshold
+	go func() {
+		defer close(heights)
+		for height := startHeight; height+cfg.Threshold <= uint64(b.latestBlockHeight()); height++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case heights <- height:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := uint64(0); i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := range heights {
+				fb, ok := b.fetchBlock(ctx, height)
+				if !ok {
+					return
+				}
+				// never blocks, the fetched blocks are bounded by the window
+				fetched <- fb
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(fetched)
+	}()
+
+	var (
+		pending       = make(map[uint64]*fetchedBlock)
+		batch         = make([]*fetchedBlock, 0, cfg.BatchSize)
+		startTime     = time.Now()
+		speed         float64
+		indexer, txDB = b.newTxIndexer()
+	)
+	apply := func() bool {
+		if !b.applyBatch(ctx, indexer, txDB, batch, startHeight, speed) {
+			return false
+		}
+		lastHeight = batch[len(batch)-1].height
+		for range batch {
+			<-window
+		}
+		batch = batch[:0]
+		speed = float64(lastHeight-startHeight+1) / time.Since(startTime).Seconds()
+		lag := b.latestBlockHeight() - int64(lastHeight)
+		if lag < 0 {
+			lag = 0
+		}
+		metrics.BlockSyncerLagGauge.WithLabelValues(b.Name()).Set(float64(lag))
+		metrics.BlockSyncerCatchUpSpeedGauge.WithLabelValues(b.Name()).Set(speed)
+		if speed > 0 {
+			metrics.BlockSyncerCatchUpETAGauge.WithLabelValues(b.Name()).Set(float64(lag) / speed)
+		}
+		log.Infow("block syncer applied blocks in catch-up mode", "service", b.Name(), "height", lastHeight,
+			"lag", lag, "blocks_per_second", speed)
+		return true
+	}
+	for fb := range fetched {
+		pending[fb.height] = fb
+		for next := lastHeight + uint64(len(batch)) + 1; pending[next] != nil; next++ {
+			batch = append(batch, pending[next])
+			delete(pending, next)
+			if uint64(len(batch)) == cfg.BatchSize && !apply() {
+				return lastHeight
+			}
+		}
+	}
+	// all the enqueued heights are fetched unless the ctx is done
+	if ctx.Err() == nil && len(batch) > 0 {
+		apply()
+	}
+	log.Infow("block syncer finishes catching up", "service", b.Name(), "height", lastHeight,
+		"blocks", lastHeight-startHeight+1, "cost", time.Since(startTime))
+	return lastHeight
+}
+
+// fetchBlock fetches the block and its results of the height, it retries until the ctx is done.
+func (b *BlockSyncerModular) fetchBlock(ctx context.Context, height uint64) (*fetchedBlock, bool) {
+	for {
+		block, err := b.parserCtx.Node.Block(int64(height))
+		if err == nil {
+			var events *coretypes.ResultBlockResults
+			events, err = b.parserCtx.Node.BlockResults(int64(height))
+			if err == nil {
+				var txs []*types.Tx
+				txs, err = b.parserCtx.Node.Txs(block)
+				if err == nil {
+					return &fetchedBlock{height: height, block: block, events: events, txs: txs}, true
+				}
+			}
+		}
+		log.Warnw("failed to fetch block in catch-up mode", "height", height, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(CatchUpRetryInterval):
+		}
+	}
+}
+
+// applyBatch applies the events of the blocks and the epoch of the last block in a db transaction,
+// it retries the batch until it succeeds or the ctx is done. The indexer and its modules write into
+// txDB, which points at the transaction of the batch.
+func (b *BlockSyncerModular) applyBatch(ctx context.Context, indexer *Impl, txDB *db.DB, batch []*fetchedBlock,
+	startHeight uint64, speed float64) bool {
+	for {
+		startTime := time.Now()
+		err := db.Cast(b.parserCtx.Database).Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			txDB.Db = tx
+			for _, fb := range batch {
+				if err := indexer.ExportBlockEvents(ctx, fb.block, fb.events, fb.txs); err != nil {
+					return err
+				}
+			}
+			if err := indexer.ExportEpoch(batch[len(batch)-1].block); err != nil {
+				return err
+			}
+			return txDB.SaveBlockSyncerStatus(ctx, &bsdb.BlockSyncerStatus{
+				Mode:               bsdb.BlockSyncerModeCatchUp,
+				CatchUpStartHeight: int64(startHeight),
+				BlocksPerSecond:    speed,
+				UpdateTime:         time.Now().Unix(),
+			})
+		})
+		if err == nil {
+			metrics.BlockSyncerCatchUpBatchTimeHistogram.WithLabelValues(b.Name()).Observe(time.Since(startTime).Seconds())
+			return true
+		}
+		log.Errorw("failed to apply blocks in catch-up mode, retry the batch", "from", batch[0].height,
+			"to", batch[len(batch)-1].height, "error", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(CatchUpRetryInterval):
+		}
+	}
+}
+
+// newTxIndexer returns an indexer whose modules write into the returned db, the modules are the
+// same as the modules of the block syncer in the same order. The modules only hold the db, so they
+// are built once in the catch-up mode, and every batch points the db at its transaction instead of
+// building the modules again.
+func (b *BlockSyncerModular) newTxIndexer() (*Impl, *db.DB) {
+	txDB := &db.DB{
+		Database: &mysql.Database{
+			Impl: database.Impl{
+				Db:             db.Cast(b.parserCtx.Database).Db,
+				EncodingConfig: b.parserCtx.EncodingConfig,
+			},
+		},
+	}
+	txModules := make(map[string]modules.Module)
+	for _, module := range registrar.NewBlockSyncerRegistrar(messages.CosmosMessageAddressesParser).
+		BuildModules(junoregistrar.Context{Database: txDB}) {
+		txModules[module.Name()] = module
+	}
+	indexerModules := make([]modules.Module, 0, len(b.parserCtx.Modules))
+	for _, module := range b.parserCtx.Modules {
+		if txModule, ok := txModules[module.Name()]; ok {
+			indexerModules = append(indexerModules, txModule)
+		}
+	}
+	return Cast(NewIndexer(b.parserCtx.EncodingConfig.Marshaler, b.parserCtx.Node, txDB, indexerModules, b.Name())), txDB
+}
+
+// followTip follows the tip of the chain block by block until the ctx is done, or until the lag
+// exceeds the threshold if the catch-up mode is enabled, the lag is checked every
+// CatchUpCheckInterval. It returns the height of the epoch after the worker stops, and false if
+// the ctx is done.
+func (b *BlockSyncerModular) followTip(ctx context.Context, lastHeight uint64) (uint64, bool) {
+	Cast(b.parserCtx.Indexer).ProcessedHeight = lastHeight
+	metrics.BlockSyncerCatchUpGauge.WithLabelValues(b.Name()).Set(0)
+	metrics.BlockSyncerCatchUpSpeedGauge.WithLabelValues(b.Name()).Set(0)
+	metrics.BlockSyncerCatchUpETAGauge.WithLabelValues(b.Name()).Set(0)
+	if err := b.saveSyncStatus(ctx, bsdb.BlockSyncerModeFollowing, 0, 0); err != nil {
+		log.Errorw("failed to save block syncer status", "error", err)
+	}
+	log.Infow("block syncer follows the tip", "service", b.Name(), "height", lastHeight)
+
+	followCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Create a queue that will collect, aggregate, and export blocks and metadata
+	exportQueue := types.NewQueue(100)
+	worker := parser.NewWorker(b.parserCtx, exportQueue, 0, config.Cfg.Parser.ConcurrentSync)
+	worker.SetIndexer(b.parserCtx.Indexer)
+	go b.enqueueNewBlocks(followCtx, exportQueue, lastHeight+1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		worker.Start(followCtx)
+	}()
+
+	var check <-chan time.Time
+	if b.catchUpConfig.Enable {
+		ticker := time.NewTicker(CatchUpCheckInterval)
+		defer ticker.Stop()
+		check = ticker.C
+	}
+	for {
+		select {
+		case <-stopped:
+			return lastHeight, false
+		case <-check:
+			height, ok := b.epochHeight(ctx)
+			if !ok {
+				return lastHeight, false
+			}
+			if uint64(b.latestBlockHeight()) <= height+b.catchUpConfig.Threshold {
+				continue
+			}
+			log.Infow("block syncer falls behind the tip", "service", b.Name(), "height", height,
+				"latest_height", b.latestBlockHeight())
+			// the worker applies the blocks one by one, the epoch is stable after it stops
+			cancel()
+			<-stopped
+			return b.epochHeight(ctx)
+		}
+	}
+}
+
+func (b *BlockSyncerModular) saveSyncStatus(ctx context.Context, mode string, catchUpStartHeight uint64, speed float64) error {
+	return db.Cast(b.parserCtx.Database).SaveBlockSyncerStatus(ctx, &bsdb.BlockSyncerStatus{
+		Mode:               mode,
+		CatchUpStartHeight: int64(catchUpStartHeight),
+		BlocksPerSecond:    speed,
+		UpdateTime:         time.Now().Unix(),
+	})
+}
+
+func (b *BlockSyncerModular) latestBlockHeight() int64 {
+	latestBlockHeight, _ := Cast(b.parserCtx.Indexer).GetLatestBlockHeight().Load().(int64)
+	return latestBlockHeight
+}
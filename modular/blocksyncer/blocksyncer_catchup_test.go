@@ -0,0 +1,197 @@
+package blocksyncer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/greenfield/app/params"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	"github.com/forbole/juno/v4/models"
+	"github.com/forbole/juno/v4/node"
+	"github.com/forbole/juno/v4/parser"
+	"github.com/forbole/juno/v4/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/blocksyncertest"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+// fakeNode returns the empty blocks, the blocks are delayed by the delay of the height, so the
+// concurrent fetches return out of order, and the fetches of the heights in failures fail first.
+type fakeNode struct {
+	node.Node
+	mux      sync.Mutex
+	delay    func(height int64) time.Duration
+	failures map[int64]int
+	fetched  []int64
+}
+
+func (n *fakeNode) Block(height int64) (*coretypes.ResultBlock, error) {
+	time.Sleep(n.delay(height))
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.failures[height] > 0 {
+		n.failures[height]--
+		return nil, errors.New("mock node error")
+	}
+	n.fetched = append(n.fetched, height)
+	return &coretypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: time.Unix(height, 0)}}}, nil
+}
+
+func (n *fakeNode) BlockResults(height int64) (*coretypes.ResultBlockResults, error) {
+	return &coretypes.ResultBlockResults{Height: height}, nil
+}
+
+func (n *fakeNode) Txs(*coretypes.ResultBlock) ([]*types.Tx, error) {
+	return nil, nil
+}
+
+func (n *fakeNode) ChainID() (string, error) {
+	return "greenfield_9000-121", nil
+}
+
+// epochRecorder records the heights of the epochs that are saved, the saves of the heights in
+// failures fail first, which fails the transaction of the batch.
+type epochRecorder struct {
+	mux      sync.Mutex
+	failures map[int64]int
+	heights  []int64
+}
+
+func (r *epochRecorder) register(t *testing.T, gormDB *gorm.DB) {
+	require.NoError(t, gormDB.Callback().Create().Before("gorm:create").Register("test:epoch", func(tx *gorm.DB) {
+		epoch, ok := tx.Statement.Dest.(*models.Epoch)
+		if !ok {
+			return
+		}
+		r.mux.Lock()
+		defer r.mux.Unlock()
+		r.heights = append(r.heights, epoch.BlockHeight)
+		if r.failures[epoch.BlockHeight] > 0 {
+			r.failures[epoch.BlockHeight]--
+			_ = tx.AddError(errors.New("mock db error"))
+		}
+	}))
+}
+
+func (r *epochRecorder) recorded() []int64 {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]int64(nil), r.heights...)
+}
+
+func newTestBlockSyncer(t *testing.T, fake *fakeNode, latestHeight int64) (*BlockSyncerModular, *gorm.DB) {
+	bsDB := blocksyncertest.NewDB(t, &models.Epoch{}, &bsdb.BlockSyncerStatus{})
+	gormDB := bsDB.Db
+	encodingConfig := &params.EncodingConfig{}
+	b := &BlockSyncerModular{
+		name: BlockSyncerModularName,
+		catchUpConfig: &CatchUpConfig{Enable: true, Concurrency: 4, Prefetch: 8, BatchSize: 3,
+			Threshold: 2},
+		parserCtx: &parser.Context{
+			EncodingConfig: encodingConfig,
+			Node:           fake,
+			Database:       bsDB,
+			Indexer:        NewIndexer(encodingConfig.Marshaler, fake, bsDB, nil, BlockSyncerModularName),
+		},
+	}
+	Cast(b.parserCtx.Indexer).GetLatestBlockHeight().Store(latestHeight)
+
+	retryInterval, checkInterval := CatchUpRetryInterval, CatchUpCheckInterval
+	CatchUpRetryInterval, CatchUpCheckInterval = time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() {
+		CatchUpRetryInterval, CatchUpCheckInterval = retryInterval, checkInterval
+	})
+	return b, gormDB
+}
+
+func TestBlockSyncerModular_catchUp(t *testing.T) {
+	fake := &fakeNode{
+		// the later heights of every 4 heights return earlier
+		delay:    func(height int64) time.Duration { return time.Duration(4-height%4) * 5 * time.Millisecond },
+		failures: map[int64]int{5: 2, 13: 1},
+	}
+	b, gormDB := newTestBlockSyncer(t, fake, 22)
+	recorder := &epochRecorder{failures: map[int64]int{9: 1, 20: 1}}
+	recorder.register(t, gormDB)
+
+	// the blocks are applied until the lag is the threshold
+	height := b.catchUp(context.Background(), 0)
+	assert.Equal(t, uint64(20), height)
+	fake.mux.Lock()
+	assert.False(t, sort.SliceIsSorted(fake.fetched, func(i, j int) bool { return fake.fetched[i] < fake.fetched[j] }))
+	fake.mux.Unlock()
+	// the batches are applied in order, and the failed batches are retried
+	assert.Equal(t, []int64{3, 6, 9, 9, 12, 15, 18, 20, 20}, recorder.recorded())
+	epoch := &models.Epoch{}
+	require.NoError(t, gormDB.Take(epoch).Error)
+	assert.Equal(t, int64(20), epoch.BlockHeight)
+	status := &bsdb.BlockSyncerStatus{}
+	require.NoError(t, gormDB.Take(status).Error)
+	assert.Equal(t, bsdb.BlockSyncerModeCatchUp, status.Mode)
+	assert.Equal(t, int64(1), status.CatchUpStartHeight)
+
+	// the block syncer does not catch up if the lag is under the threshold
+	assert.Equal(t, uint64(20), b.catchUp(context.Background(), 20))
+	assert.Len(t, recorder.recorded(), 9)
+}
+
+func TestBlockSyncerModular_catchUpCanceled(t *testing.T) {
+	// the batch can not be applied, the catch-up stops when the ctx is done
+	fake := &fakeNode{delay: func(int64) time.Duration { return 0 }}
+	b, gormDB := newTestBlockSyncer(t, fake, 100)
+	recorder := &epochRecorder{failures: map[int64]int{3: 1 << 30}}
+	recorder.register(t, gormDB)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, uint64(0), b.catchUp(ctx, 0))
+}
+
+func TestBlockSyncerModular_followTip(t *testing.T) {
+	fake := &fakeNode{delay: func(int64) time.Duration { return 5 * time.Millisecond }}
+	b, gormDB := newTestBlockSyncer(t, fake, 10)
+	require.NoError(t, gormDB.Create(&models.Epoch{OneRowId: true, BlockHeight: 8}).Error)
+
+	type result struct {
+		height uint64
+		ok     bool
+	}
+	results := make(chan result)
+	go func() {
+		height, ok := b.followTip(context.Background(), 8)
+		results <- result{height: height, ok: ok}
+	}()
+	// the block syncer follows the tip until it falls behind
+	require.Eventually(t, func() bool {
+		height, ok := b.epochHeight(context.Background())
+		return ok && height == 10
+	}, time.Second, 5*time.Millisecond)
+	status := &bsdb.BlockSyncerStatus{}
+	require.NoError(t, gormDB.Take(status).Error)
+	assert.Equal(t, bsdb.BlockSyncerModeFollowing, status.Mode)
+	Cast(b.parserCtx.Indexer).GetLatestBlockHeight().Store(int64(10000))
+
+	select {
+	case r := <-results:
+		require.True(t, r.ok)
+		epoch := &models.Epoch{}
+		require.NoError(t, gormDB.Take(epoch).Error)
+		assert.Equal(t, uint64(epoch.BlockHeight), r.height)
+		assert.Less(t, r.height, uint64(10000))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the block syncer does not leave the following mode")
+	}
+
+	// the following mode stops when the ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok := b.followTip(ctx, 10)
+	assert.False(t, ok)
+}
@@ -3,7 +3,6 @@ package blocksyncer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"sync/atomic"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -41,7 +40,6 @@ type Impl struct {
 	DB      database.Database
 
 	LatestBlockHeight atomic.Value
-	CatchUpFlag       atomic.Value
 	ProcessedHeight   uint64
 
 	ServiceName string
@@ -71,54 +69,52 @@ func (i *Impl) HandleEvent(ctx context.Context, block *coretypes.ResultBlock, tx
 // It returns an error if any export process fails.
 func (i *Impl) Process(height uint64) error {
 	// log.Debugw("processing block", "height", height)
-	var block *coretypes.ResultBlock
-	var events *coretypes.ResultBlockResults
-	var txs []*types.Tx
-	var err error
-	flagAny := i.GetCatchUpFlag().Load()
-	flag := flagAny.(int64)
-	heightKey := fmt.Sprintf("%s-%d", i.GetServiceName(), height)
-	if flag == -1 || flag >= int64(height) {
-		blockAny, okb := blockMap.Load(heightKey)
-		eventsAny, oke := eventMap.Load(heightKey)
-		txsAny, okt := txMap.Load(heightKey)
-		block, _ = blockAny.(*coretypes.ResultBlock)
-		events, _ = eventsAny.(*coretypes.ResultBlockResults)
-		txs, _ = txsAny.([]*types.Tx)
-		if !okb || !oke || !okt {
-			log.Warnf("failed to get map data height: %d", height)
-			return ErrBlockNotFound
-		}
-	} else {
-		// get block info
-		block, err = i.Node.Block(int64(height))
-		if err != nil {
-			log.Errorf("failed to get block from node: %s", err)
-			return err
-		}
+	// get block info
+	block, err := i.Node.Block(int64(height))
+	if err != nil {
+		log.Errorf("failed to get block from node: %s", err)
+		return err
+	}
 
-		// get txs
-		txs, err = i.Node.Txs(block)
-		if err != nil {
-			log.Errorf("failed to get transactions for block: %s", err)
-			return err
-		}
+	// get txs
+	txs, err := i.Node.Txs(block)
+	if err != nil {
+		log.Errorf("failed to get transactions for block: %s", err)
+		return err
+	}
 
-		// get block results
-		events, err = i.Node.BlockResults(int64(height))
-		if err != nil {
-			log.Errorf("failed to get block results from node: %s", err)
-			return err
-		}
+	// get block results
+	events, err := i.Node.BlockResults(int64(height))
+	if err != nil {
+		log.Errorf("failed to get block results from node: %s", err)
+		return err
+	}
+
+	if err = i.ExportBlockEvents(context.Background(), block, events, txs); err != nil {
+		return err
 	}
 
+	err = i.ExportEpoch(block)
+	if err != nil {
+		log.Errorf("failed to export epoch: %s", err)
+		return err
+	}
+
+	i.ProcessedHeight = height
+
+	return nil
+}
+
+// ExportBlockEvents handles the events of the block in order, the events in startBlock, the events
+// in txs and the events in endBlock.
+func (i *Impl) ExportBlockEvents(ctx context.Context, block *coretypes.ResultBlock, events *coretypes.ResultBlockResults, txs []*types.Tx) error {
+	var err error
 	beginBlockEvents := events.BeginBlockEvents
 	endBlockEvents := events.EndBlockEvents
 
 	// 1. handle events in startBlock
-
 	if len(beginBlockEvents) > 0 {
-		err = i.ExportEventsWithoutTx(context.Background(), block, beginBlockEvents)
+		err = i.ExportEventsWithoutTx(ctx, block, beginBlockEvents)
 		if err != nil {
 			log.Errorf("failed to export events without tx: %s", err)
 			return err
@@ -126,7 +122,7 @@ func (i *Impl) Process(height uint64) error {
 	}
 
 	// 2. handle events in txs
-	err = i.ExportEventsInTxs(context.Background(), block, txs)
+	err = i.ExportEventsInTxs(ctx, block, txs)
 	if err != nil {
 		log.Errorf("failed to export events in txs: %s", err)
 		return err
@@ -134,24 +130,12 @@ func (i *Impl) Process(height uint64) error {
 
 	// 3. handle events in endBlock
 	if len(endBlockEvents) > 0 {
-		err = i.ExportEventsWithoutTx(context.Background(), block, endBlockEvents)
+		err = i.ExportEventsWithoutTx(ctx, block, endBlockEvents)
 		if err != nil {
 			log.Errorf("failed to export events without tx: %s", err)
 			return err
 		}
 	}
-
-	err = i.ExportEpoch(block)
-	if err != nil {
-		log.Errorf("failed to export epoch: %s", err)
-		return err
-	}
-
-	blockMap.Delete(heightKey)
-	eventMap.Delete(heightKey)
-	txMap.Delete(heightKey)
-	i.ProcessedHeight = height
-
 	return nil
 }
 
@@ -170,6 +154,9 @@ func (i *Impl) ExportEpoch(block *coretypes.ResultBlock) error {
 	}
 
 	metrics.BlockHeightLagGauge.WithLabelValues("blocksyncer").Set(float64(block.Block.Height))
+	if latestBlockHeight, ok := i.GetLatestBlockHeight().Load().(int64); ok {
+		metrics.BlockSyncerLagGauge.WithLabelValues(i.GetServiceName()).Set(float64(latestBlockHeight - block.Block.Height))
+	}
 
 	return nil
 }
@@ -273,12 +260,6 @@ func (i *Impl) Processed(ctx context.Context, height uint64) (bool, error) {
 		return false, err
 	}
 	// log.Infof("epoch height:%d, cur height: %d", ep.BlockHeight, height)
-	if ep.BlockHeight > int64(height) {
-		heightKey := fmt.Sprintf("%s-%d", i.GetServiceName(), height)
-		blockMap.Delete(heightKey)
-		eventMap.Delete(heightKey)
-		txMap.Delete(heightKey)
-	}
 	return ep.BlockHeight > int64(height), nil
 }
 
@@ -304,10 +285,6 @@ func (i *Impl) GetLatestBlockHeight() *atomic.Value {
 
 }
 
-func (i *Impl) GetCatchUpFlag() *atomic.Value {
-	return &(i.CatchUpFlag)
-}
-
 func (i *Impl) CreateMasterTable() error {
 
 	return nil
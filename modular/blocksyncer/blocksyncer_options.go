@@ -4,9 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/gob"
-	"fmt"
 	"os"
-	"sync"
 	"time"
 
 	"github.com/forbole/juno/v4/cmd"
@@ -29,18 +27,17 @@ import (
 	registrar "github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
 )
 
 func NewBlockSyncerModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
 	junoCfg := makeBlockSyncerConfig(cfg)
 	MainService = &BlockSyncerModular{
-		config:  junoCfg,
-		name:    BlockSyncerModularName,
-		baseApp: app,
+		config:        junoCfg,
+		catchUpConfig: makeCatchUpConfig(cfg),
+		name:          BlockSyncerModularName,
+		baseApp:       app,
 	}
-	blockMap = new(sync.Map)
-	eventMap = new(sync.Map)
-	txMap = new(sync.Map)
 	NeedBackup = junoCfg.EnableDualDB
 	if err := MainService.initClient(); err != nil {
 		return nil, err
@@ -63,6 +60,7 @@ func NewBlockSyncerModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig)
 		if blockSyncerBackup, err := newBackupBlockSyncerService(junoCfg, mainDBIsMaster); err != nil {
 			return nil, err
 		} else {
+			blockSyncerBackup.catchUpConfig = MainService.catchUpConfig
 			BackupService = blockSyncerBackup
 		}
 	}
@@ -138,6 +136,15 @@ func (b *BlockSyncerModular) initDB(useMigrate bool) error {
 			}
 		}
 	}
+	migrator, err := migrate.NewMigrator(db.Cast(b.parserCtx.Database).Db, bsdb.MigrationDBName, bsdb.Migrations())
+	if err != nil {
+		log.Errorw("failed to new bsdb migrator", "error", err)
+		return err
+	}
+	if err = migrator.Startup(true); err != nil {
+		log.Errorw("failed to migrate bsdb", "error", err)
+		return err
+	}
 	return nil
 }
 
@@ -151,47 +158,55 @@ func (b *BlockSyncerModular) serve(ctx context.Context) {
 			return
 		}
 	}
-	// Create a queue that will collect, aggregate, and export blocks and metadata
-	exportQueue := types.NewQueue(100)
-
-	// Create workers
-	worker := parser.NewWorker(b.parserCtx, exportQueue, 0, config.Cfg.Parser.ConcurrentSync)
-	worker.SetIndexer(b.parserCtx.Indexer)
-
 	latestBlockHeight := mustGetLatestHeight(b.parserCtx)
 	Cast(b.parserCtx.Indexer).GetLatestBlockHeight().Store(int64(latestBlockHeight))
-	Cast(b.parserCtx.Indexer).GetCatchUpFlag().Store(int64(-1))
 	go b.getLatestBlockHeight(ctx)
 
-	lastDbBlockHeight := uint64(0)
+	lastDbBlockHeight, ok := b.epochHeight(ctx)
+	if !ok {
+		return
+	}
+	// catch up with the chain by the concurrent fetch and the batched apply, then follow the tip
+	// until the block syncer falls behind again
 	for {
-		epoch, err := b.parserCtx.Database.GetEpoch(context.TODO())
-		if err != nil {
-			log.Errorw("failed to get last block height from database", "error", err)
-			continue
+		if b.catchUpConfig.Enable {
+			lastDbBlockHeight = b.catchUp(ctx, lastDbBlockHeight)
+			if ctx.Err() != nil {
+				log.Infof("Receive cancel signal, block syncer stops catching up")
+				return
+			}
+		}
+		if lastDbBlockHeight, ok = b.followTip(ctx, lastDbBlockHeight); !ok {
+			return
 		}
-		lastDbBlockHeight = uint64(epoch.BlockHeight)
-		break
 	}
+}
 
-	// fetch block data
-	go b.quickFetchBlockData(lastDbBlockHeight + 1)
-
-	go b.enqueueNewBlocks(ctx, exportQueue, lastDbBlockHeight+1)
-
-	// Start each blocking worker in a go-routine where the worker consumes jobs
-	go worker.Start(ctx)
+// epochHeight returns the block height of the epoch, it retries until the ctx is done.
+func (b *BlockSyncerModular) epochHeight(ctx context.Context) (uint64, bool) {
+	for {
+		epoch, err := b.parserCtx.Database.GetEpoch(ctx)
+		if err == nil {
+			return uint64(epoch.BlockHeight), true
+		}
+		log.Errorw("failed to get last block height from database", "error", err)
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-time.After(CatchUpRetryInterval):
+		}
+	}
 }
 
-// enqueueNewBlocks enqueues new block heights onto the provided queue.
+// enqueueNewBlocks enqueues new block heights onto the provided queue. The queue is not closed
+// when the ctx is done, the worker stops by the same ctx, and the failed heights that are
+// re-enqueued by the concurrent worker would panic on the closed queue.
 func (b *BlockSyncerModular) enqueueNewBlocks(context context.Context, exportQueue types.HeightQueue, currHeight uint64) {
 	// Enqueue upcoming heights
 	for {
 		select {
 		case <-context.Done():
 			log.Infof("Receive cancel signal, enqueueNewBlocks routine will stop")
-			// close channel
-			close(exportQueue)
 			return
 		default:
 			{
@@ -200,7 +215,12 @@ func (b *BlockSyncerModular) enqueueNewBlocks(context context.Context, exportQue
 				// Enqueue all heights from the current height up to the latest height
 				for ; currHeight <= uint64(latestBlockHeight); currHeight++ {
 					// log.Debugw("enqueueing new block", "height", currHeight)
-					exportQueue <- currHeight
+					select {
+					case exportQueue <- currHeight:
+					case <-context.Done():
+						log.Infof("Receive cancel signal, enqueueNewBlocks routine will stop")
+						return
+					}
 				}
 			}
 		}
@@ -230,65 +250,6 @@ func (b *BlockSyncerModular) getLatestBlockHeight(ctx context.Context) {
 	}
 }
 
-func (b *BlockSyncerModular) quickFetchBlockData(startHeight uint64) {
-	count := uint64(b.config.Parser.Workers)
-	cycle := uint64(0)
-	for {
-		latestBlockHeightAny := Cast(b.parserCtx.Indexer).GetLatestBlockHeight().Load()
-		latestBlockHeight := latestBlockHeightAny.(int64)
-		if latestBlockHeight < int64(count*(cycle+1)+startHeight-1) {
-			log.Infof("quick fetch ended latestBlockHeight: %d", latestBlockHeight)
-			Cast(b.parserCtx.Indexer).GetCatchUpFlag().Store(int64(count*cycle + startHeight - 1))
-			break
-		}
-		processedHeight := Cast(b.parserCtx.Indexer).ProcessedHeight
-		if processedHeight != 0 && count*cycle+startHeight-processedHeight > MaxHeightGapFactor*count {
-			time.Sleep(time.Second)
-			continue
-		}
-		b.fetchData(count, cycle, startHeight, latestBlockHeight)
-		cycle++
-	}
-}
-
-func (b *BlockSyncerModular) fetchData(count, cycle, startHeight uint64, latestBlockHeight int64) {
-	wg := &sync.WaitGroup{}
-	wg.Add(int(count))
-	for i := uint64(0); i < count; i++ {
-		go func(idx, c uint64) {
-			defer wg.Done()
-			height := idx + count*c + startHeight
-			if height > uint64(latestBlockHeight) {
-				return
-			}
-			for {
-				block, err := b.parserCtx.Node.Block(int64(height))
-				if err != nil {
-					log.Warnf("failed to get block from node: %s", err)
-					continue
-				}
-
-				events, err := b.parserCtx.Node.BlockResults(int64(height))
-				if err != nil {
-					log.Warnf("failed to get block results from node: %s", err)
-					continue
-				}
-				txs, err := b.parserCtx.Node.Txs(block)
-				if err != nil {
-					log.Warnf("failed to get block results from node: %s", err)
-					continue
-				}
-				heightKey := fmt.Sprintf("%s-%d", b.Name(), height)
-				blockMap.Store(heightKey, block)
-				eventMap.Store(heightKey, events)
-				txMap.Store(heightKey, txs)
-				break
-			}
-		}(i, cycle)
-	}
-	wg.Wait()
-}
-
 func (b *BlockSyncerModular) prepareMasterFlagTable() error {
 	if err := FlagDB.
 		PrepareTables(context.TODO(), []schema.Tabler{&bsdb.MasterDB{}}); err != nil {
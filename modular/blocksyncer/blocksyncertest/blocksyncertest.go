@@ -0,0 +1,41 @@
+// Package blocksyncertest provides the db and the event helpers for the tests of the block
+// syncer modules.
+package blocksyncertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/forbole/juno/v4/common"
+	junodatabase "github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/database/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb/bsdbtest"
+)
+
+// EventHandler is the block syncer module that handles the events.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error
+}
+
+// NewDB returns the block syncer db on a sqlite db that has the tables migrated.
+func NewDB(t testing.TB, tables ...interface{}) *database.DB {
+	return &database.DB{Database: &mysql.Database{Impl: junodatabase.Impl{Db: bsdbtest.NewDB(t, tables...)}}}
+}
+
+// HandleEvent converts the typed event and handles it by the module, the event is in the
+// block of the height that is timed 1000+height seconds.
+func HandleEvent(t testing.TB, m EventHandler, height int64, txHash common.Hash, event proto.Message) {
+	typedEvent, err := sdk.TypedEventToEvent(event)
+	require.NoError(t, err)
+	block := &tmctypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: height, Time: time.Unix(1000+height, 0)}}}
+	require.NoError(t, m.HandleEvent(context.Background(), block, txHash, sdk.Event(abci.Event(typedEvent))))
+}
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+// SaveBlockSyncerStatus save the sync mode and speed of the block syncer
+func (db *DB) SaveBlockSyncerStatus(ctx context.Context, status *bsdb.BlockSyncerStatus) error {
+	status.OneRowID = true
+	return db.Db.WithContext(ctx).Table((&bsdb.BlockSyncerStatus{}).TableName()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "one_row_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "catch_up_start_height", "blocks_per_second", "update_time"}),
+	}).Create(status).Error
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/forbole/juno/v4/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+// AppendChangeEvent appends the change event to the log, the event that is appended already by
+// the replayed block is ignored
+func (db *DB) AppendChangeEvent(ctx context.Context, event *bsdb.ChangeEvent) error {
+	return db.Db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+// GetObjectNameByID get the bucket name and the object name by object id, they are empty if the object does not exist
+func (db *DB) GetObjectNameByID(ctx context.Context, objectID common.Hash) (string, string, error) {
+	var object bsdb.Object
+	err := db.Db.WithContext(ctx).Table((&bsdb.Object{}).TableName()).
+		Select("bucket_name, object_name").
+		Where("object_id = ?", objectID).
+		Take(&object).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", nil
+	}
+	return object.BucketName, object.ObjectName, err
+}
+
+// GetBucketNameByID get the bucket name by bucket id, it is empty if the bucket does not exist
+func (db *DB) GetBucketNameByID(ctx context.Context, bucketID common.Hash) (string, error) {
+	var bucket bsdb.Bucket
+	err := db.Db.WithContext(ctx).Table((&bsdb.Bucket{}).TableName()).
+		Select("bucket_name").
+		Where("bucket_id = ?", bucketID).
+		Take(&bucket).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return bucket.BucketName, err
+}
+
+// GetGroupNameByID get the group name by group id, it is empty if the group does not exist
+func (db *DB) GetGroupNameByID(ctx context.Context, groupID common.Hash) (string, error) {
+	var group bsdb.Group
+	err := db.Db.WithContext(ctx).Table((&bsdb.Group{}).TableName()).
+		Select("group_name").
+		Where("group_id = ?", groupID).
+		Take(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return group.GroupName, err
+}
+
+// GetPolicyResource get the resource type and the resource id of the policy including the
+// removed policy, the resource type is empty if the policy does not exist
+func (db *DB) GetPolicyResource(ctx context.Context, policyID common.Hash) (string, common.Hash, error) {
+	var permission bsdb.Permission
+	err := db.Db.WithContext(ctx).Table((&bsdb.Permission{}).TableName()).
+		Select("resource_type, resource_id").
+		Where("policy_id = ?", policyID).
+		Take(&permission).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", common.Hash{}, nil
+	}
+	return permission.ResourceType, permission.ResourceID, err
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/forbole/juno/v4/database/mysql"
 	"github.com/forbole/juno/v4/database/sqlclient"
 	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 )
 
 var _ database.Database = &DB{}
@@ -25,6 +27,9 @@ func BlockSyncerDBBuilder(ctx *database.Context) (database.Database, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = db.Use(tracing.NewGormPlugin("bsdb")); err != nil {
+		return nil, err
+	}
 	return &DB{
 		Database: &mysql.Database{
 			Impl: database.Impl{
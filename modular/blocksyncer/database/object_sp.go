@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/forbole/juno/v4/common"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// SaveObjectSps maps the sealed object to its primary sp and secondary sps, it does nothing if the
+// object is not sealed, and the replayed event does not map the object twice
+func (db *DB) SaveObjectSps(ctx context.Context, objectID common.Hash) error {
+	var objects []*bsdb.Object
+	err := db.Db.WithContext(ctx).Table((&bsdb.Object{}).TableName()).
+		Where("object_id = ? AND removed = false", objectID).
+		Limit(1).
+		Find(&objects).Error
+	if err != nil || len(objects) == 0 {
+		return err
+	}
+	object := objects[0]
+	if object.ObjectStatus != storagetypes.OBJECT_STATUS_SEALED.String() {
+		return nil
+	}
+	var bucket bsdb.Bucket
+	err = db.Db.WithContext(ctx).Table((&bsdb.Bucket{}).TableName()).
+		Select("primary_sp_address").
+		Where("bucket_id = ?", object.BucketID).
+		Take(&bucket).Error
+	if err != nil {
+		return err
+	}
+	objectSps := bsdb.NewObjectSps(object.ID, object.ObjectID, bucket.PrimarySpAddress, object.SecondarySpAddresses)
+	return db.Db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&objectSps).Error
+}
+
+// DeleteObjectSps deletes the mapping of the removed object
+func (db *DB) DeleteObjectSps(ctx context.Context, objectID common.Hash) error {
+	return db.Db.WithContext(ctx).Where("object_id = ?", objectID).Delete(&bsdb.ObjectSp{}).Error
+}
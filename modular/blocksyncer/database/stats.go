@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+
+	"github.com/forbole/juno/v4/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+// GetObjectStats get the counted state of object by object id, it returns nil if the object is not counted
+func (db *DB) GetObjectStats(ctx context.Context, objectID common.Hash) (*bsdb.ObjectStats, error) {
+	var objects []*bsdb.ObjectStats
+	err := db.Db.WithContext(ctx).Where("object_id = ?", objectID).Limit(1).Find(&objects).Error
+	if err != nil || len(objects) == 0 {
+		return nil, err
+	}
+	return objects[0], nil
+}
+
+// GetBucketIDByName get the id of the bucket that is not removed by bucket name
+func (db *DB) GetBucketIDByName(ctx context.Context, bucketName string) (common.Hash, error) {
+	var bucket bsdb.Bucket
+	err := db.Db.WithContext(ctx).Table((&bsdb.Bucket{}).TableName()).
+		Select("bucket_id").
+		Where("bucket_name = ? AND removed = false", bucketName).
+		Take(&bucket).Error
+	return bucket.BucketID, err
+}
+
+// UpdateObjectStats replaces the counted state of object, nil state means the object is not counted
+// any more, and adds the difference to the stats of the bucket and the owner in one transaction.
+// The replayed event does not change the state, so it is not counted twice.
+func (db *DB) UpdateObjectStats(ctx context.Context, objectID common.Hash, state *bsdb.ObjectStats, height int64) error {
+	if state != nil {
+		state.ObjectID = objectID
+	}
+	return db.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prevs []*bsdb.ObjectStats
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("object_id = ?", objectID).Limit(1).Find(&prevs).Error
+		if err != nil {
+			return err
+		}
+		var prev *bsdb.ObjectStats
+		if len(prevs) > 0 {
+			prev = prevs[0]
+		}
+		if prev == nil && state == nil {
+			return nil
+		}
+		if prev != nil && state != nil && *prev == *state {
+			return nil
+		}
+
+		if prev != nil {
+			if err = tx.Where("object_id = ?", objectID).Delete(&bsdb.ObjectStats{}).Error; err != nil {
+				return err
+			}
+			if err = addBucketStats(tx, prev.BucketID, prev.BucketName, prev.Stats(), -1, height); err != nil {
+				return err
+			}
+			if err = addAccountStats(tx, prev.Owner, prev.Stats(), -1, height); err != nil {
+				return err
+			}
+		}
+		if state != nil {
+			if err = tx.Create(state).Error; err != nil {
+				return err
+			}
+			if err = addBucketStats(tx, state.BucketID, state.BucketName, state.Stats(), 1, height); err != nil {
+				return err
+			}
+			if err = addAccountStats(tx, state.Owner, state.Stats(), 1, height); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// statsAssignments returns the assignments to add the stats to the existing row.
+func statsAssignments(stats bsdb.StorageStats, sign int64, height int64) []clause.Assignment {
+	return clause.Assignments(map[string]interface{}{
+		"object_count":   gorm.Expr("object_count + ?", sign*stats.ObjectCount),
+		"payload_size":   gorm.Expr("payload_size + ?", sign*stats.PayloadSize),
+		"sealed_count":   gorm.Expr("sealed_count + ?", sign*stats.SealedCount),
+		"unsealed_count": gorm.Expr("unsealed_count + ?", sign*stats.UnsealedCount),
+		"update_at":      height,
+	})
+}
+
+func addBucketStats(tx *gorm.DB, bucketID common.Hash, bucketName string, stats bsdb.StorageStats, sign int64, height int64) error {
+	row := &bsdb.BucketStats{BucketID: bucketID, BucketName: bucketName, UpdateAt: height}
+	row.Add(stats, sign)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bucket_id"}},
+		DoUpdates: statsAssignments(stats, sign, height),
+	}).Create(row).Error
+}
+
+func addAccountStats(tx *gorm.DB, owner common.Address, stats bsdb.StorageStats, sign int64, height int64) error {
+	row := &bsdb.AccountStats{Owner: owner, UpdateAt: height}
+	row.Add(stats, sign)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "owner"}},
+		DoUpdates: statsAssignments(stats, sign, height),
+	}).Create(row).Error
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/database"
+	"github.com/forbole/juno/v4/database/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb/bsdbtest"
+)
+
+func newTestDB(t *testing.T) *DB {
+	db := bsdbtest.NewDB(t, &bsdb.ObjectStats{}, &bsdb.BucketStats{}, &bsdb.AccountStats{})
+	return &DB{Database: &mysql.Database{Impl: database.Impl{Db: db}}}
+}
+
+func TestUpdateObjectStats(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	bucketID, owner := common.HexToHash("0x11"), common.HexToAddress("0x01")
+	objectID, otherID := common.HexToHash("0x21"), common.HexToHash("0x22")
+	created := &bsdb.ObjectStats{BucketID: bucketID, BucketName: "bucket", Owner: owner, PayloadSize: 10,
+		ObjectStatus: bsdb.ObjectStatusCreated}
+	assertStats := func(bucket, account bsdb.StorageStats) {
+		var bucketStats bsdb.BucketStats
+		require.NoError(t, db.Db.Where("bucket_id = ?", bucketID).Take(&bucketStats).Error)
+		assert.Equal(t, bucket, bucketStats.StorageStats)
+		var accountStats bsdb.AccountStats
+		require.NoError(t, db.Db.Where("owner = ?", owner).Take(&accountStats).Error)
+		assert.Equal(t, account, accountStats.StorageStats)
+	}
+
+	// the replayed create event is not counted twice
+	for i := 0; i < 2; i++ {
+		state := *created
+		require.NoError(t, db.UpdateObjectStats(ctx, objectID, &state, 1))
+	}
+	assertStats(bsdb.StorageStats{ObjectCount: 1, PayloadSize: 10, UnsealedCount: 1},
+		bsdb.StorageStats{ObjectCount: 1, PayloadSize: 10, UnsealedCount: 1})
+	other := *created
+	other.PayloadSize = 5
+	require.NoError(t, db.UpdateObjectStats(ctx, otherID, &other, 2))
+	assertStats(bsdb.StorageStats{ObjectCount: 2, PayloadSize: 15, UnsealedCount: 2},
+		bsdb.StorageStats{ObjectCount: 2, PayloadSize: 15, UnsealedCount: 2})
+
+	// the status change moves the object from unsealed to sealed
+	state, err := db.GetObjectStats(ctx, objectID)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	state.ObjectStatus = bsdb.ObjectStatusSealed
+	require.NoError(t, db.UpdateObjectStats(ctx, objectID, state, 3))
+	assertStats(bsdb.StorageStats{ObjectCount: 2, PayloadSize: 15, SealedCount: 1, UnsealedCount: 1},
+		bsdb.StorageStats{ObjectCount: 2, PayloadSize: 15, SealedCount: 1, UnsealedCount: 1})
+
+	// the removed objects are subtracted, removing the uncounted object is a no-op
+	for i := 0; i < 2; i++ {
+		require.NoError(t, db.UpdateObjectStats(ctx, objectID, nil, 4))
+	}
+	assertStats(bsdb.StorageStats{ObjectCount: 1, PayloadSize: 5, UnsealedCount: 1},
+		bsdb.StorageStats{ObjectCount: 1, PayloadSize: 5, UnsealedCount: 1})
+	require.NoError(t, db.UpdateObjectStats(ctx, otherID, nil, 5))
+	assertStats(bsdb.StorageStats{}, bsdb.StorageStats{})
+	state, err = db.GetObjectStats(ctx, objectID)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	var bucketStats bsdb.BucketStats
+	require.NoError(t, db.Db.Where("bucket_id = ?", bucketID).Take(&bucketStats).Error)
+	assert.Equal(t, int64(5), bucketStats.UpdateAt)
+}
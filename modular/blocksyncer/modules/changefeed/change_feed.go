@@ -0,0 +1,200 @@
+package changefeed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield/types/resource"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// changeEventTypes maps the chain event types to the normalized change event types.
+var changeEventTypes = map[string]string{
+	proto.MessageName(&storagetypes.EventCreateBucket{}):       bsdb.ChangeBucketCreated,
+	proto.MessageName(&storagetypes.EventUpdateBucketInfo{}):   bsdb.ChangeBucketUpdated,
+	proto.MessageName(&storagetypes.EventDeleteBucket{}):       bsdb.ChangeBucketDeleted,
+	proto.MessageName(&storagetypes.EventDiscontinueBucket{}):  bsdb.ChangeBucketDiscontinued,
+	proto.MessageName(&storagetypes.EventCreateObject{}):       bsdb.ChangeObjectCreated,
+	proto.MessageName(&storagetypes.EventSealObject{}):         bsdb.ChangeObjectSealed,
+	proto.MessageName(&storagetypes.EventRejectSealObject{}):   bsdb.ChangeObjectSealRejected,
+	proto.MessageName(&storagetypes.EventCancelCreateObject{}): bsdb.ChangeObjectCreateCanceled,
+	proto.MessageName(&storagetypes.EventCopyObject{}):         bsdb.ChangeObjectCopied,
+	proto.MessageName(&storagetypes.EventUpdateObjectInfo{}):   bsdb.ChangeObjectUpdated,
+	proto.MessageName(&storagetypes.EventDeleteObject{}):       bsdb.ChangeObjectDeleted,
+	proto.MessageName(&storagetypes.EventDiscontinueObject{}):  bsdb.ChangeObjectDiscontinued,
+	proto.MessageName(&storagetypes.EventCreateGroup{}):        bsdb.ChangeGroupCreated,
+	proto.MessageName(&storagetypes.EventUpdateGroupMember{}):  bsdb.ChangeGroupMemberUpdated,
+	proto.MessageName(&storagetypes.EventLeaveGroup{}):         bsdb.ChangeGroupMemberLeft,
+	proto.MessageName(&storagetypes.EventDeleteGroup{}):        bsdb.ChangeGroupDeleted,
+	proto.MessageName(&permissiontypes.EventPutPolicy{}):       bsdb.ChangePolicyPut,
+	proto.MessageName(&permissiontypes.EventDeletePolicy{}):    bsdb.ChangePolicyDeleted,
+}
+
+// HandleEvent handles the events of buckets, objects, groups and policies.
+// It normalizes the event and appends it to the change event log.
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	eventType, ok := changeEventTypes[event.Type]
+	if !ok {
+		return nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	if err != nil {
+		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+
+	change := &bsdb.ChangeEvent{
+		BlockHeight: block.Block.Height,
+		Timestamp:   block.Block.Time.Unix(),
+		TxHash:      txHash,
+		EventType:   eventType,
+	}
+	if err = m.normalize(ctx, change, typedEvent); err != nil {
+		log.Errorw("failed to normalize change event", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+	if change.Attributes, err = encodeAttributes(event); err != nil {
+		log.Errorw("failed to encode event attributes", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+	change.EventKey = eventKey(block.Block.Height, txHash, event.Type, change.Attributes)
+	return m.db.AppendChangeEvent(ctx, change)
+}
+
+// normalize fills the changed resource and the operator of the typed event.
+func (m *Module) normalize(ctx context.Context, change *bsdb.ChangeEvent, typedEvent proto.Message) error {
+	var err error
+	switch e := typedEvent.(type) {
+	case *storagetypes.EventCreateBucket:
+		setBucket(change, e.BucketId, e.BucketName, e.Owner)
+	case *storagetypes.EventUpdateBucketInfo:
+		setBucket(change, e.BucketId, e.BucketName, e.Operator)
+	case *storagetypes.EventDeleteBucket:
+		setBucket(change, e.BucketId, e.BucketName, e.Operator)
+	case *storagetypes.EventDiscontinueBucket:
+		setBucket(change, e.BucketId, e.BucketName, "")
+	case *storagetypes.EventCreateObject:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Creator)
+	case *storagetypes.EventSealObject:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Operator)
+	case *storagetypes.EventRejectSealObject:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Operator)
+	case *storagetypes.EventCancelCreateObject:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Operator)
+	case *storagetypes.EventCopyObject:
+		setObject(change, e.DstObjectId, e.DstBucketName, e.DstObjectName, e.Operator)
+	case *storagetypes.EventUpdateObjectInfo:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Operator)
+	case *storagetypes.EventDeleteObject:
+		setObject(change, e.ObjectId, e.BucketName, e.ObjectName, e.Operator)
+	case *storagetypes.EventDiscontinueObject:
+		// the event has no object name, it is found by the object id
+		setObject(change, e.ObjectId, e.BucketName, "", "")
+		_, change.ObjectName, err = m.db.GetObjectNameByID(ctx, change.ResourceID)
+	case *storagetypes.EventCreateGroup:
+		setGroup(change, e.GroupId, e.GroupName, e.Owner)
+	case *storagetypes.EventUpdateGroupMember:
+		setGroup(change, e.GroupId, e.GroupName, e.Operator)
+	case *storagetypes.EventLeaveGroup:
+		setGroup(change, e.GroupId, e.GroupName, e.MemberAddress)
+	case *storagetypes.EventDeleteGroup:
+		setGroup(change, e.GroupId, e.GroupName, e.Owner)
+	case *permissiontypes.EventPutPolicy:
+		err = m.setPolicyResource(ctx, change, e.ResourceType, common.BigToHash(e.ResourceId.BigInt()))
+	case *permissiontypes.EventDeletePolicy:
+		// the policy is marked as removed by the permission module, its resource is still found
+		var (
+			resourceType string
+			resourceID   common.Hash
+		)
+		resourceType, resourceID, err = m.db.GetPolicyResource(ctx, common.BigToHash(e.PolicyId.BigInt()))
+		if err == nil && resourceType != "" {
+			err = m.setPolicyResource(ctx, change, resource.ResourceType(resource.ResourceType_value[resourceType]), resourceID)
+		}
+	default:
+		return fmt.Errorf("unexpected event %s", proto.MessageName(typedEvent))
+	}
+	return err
+}
+
+// setPolicyResource fills the bucket, the object or the group that the policy is attached to.
+func (m *Module) setPolicyResource(ctx context.Context, change *bsdb.ChangeEvent, resourceType resource.ResourceType, resourceID common.Hash) error {
+	var err error
+	change.ResourceID = resourceID
+	switch resourceType {
+	case resource.RESOURCE_TYPE_BUCKET:
+		change.ResourceType = bsdb.ChangeResourceBucket
+		change.BucketName, err = m.db.GetBucketNameByID(ctx, resourceID)
+	case resource.RESOURCE_TYPE_OBJECT:
+		change.ResourceType = bsdb.ChangeResourceObject
+		change.BucketName, change.ObjectName, err = m.db.GetObjectNameByID(ctx, resourceID)
+	case resource.RESOURCE_TYPE_GROUP:
+		change.ResourceType = bsdb.ChangeResourceGroup
+		change.GroupName, err = m.db.GetGroupNameByID(ctx, resourceID)
+	}
+	return err
+}
+
+func setBucket(change *bsdb.ChangeEvent, bucketID storagetypes.Uint, bucketName, operator string) {
+	change.ResourceType = bsdb.ChangeResourceBucket
+	change.ResourceID = common.BigToHash(bucketID.BigInt())
+	change.BucketName = bucketName
+	change.Operator = operator
+}
+
+func setObject(change *bsdb.ChangeEvent, objectID storagetypes.Uint, bucketName, objectName, operator string) {
+	change.ResourceType = bsdb.ChangeResourceObject
+	change.ResourceID = common.BigToHash(objectID.BigInt())
+	change.BucketName = bucketName
+	change.ObjectName = objectName
+	change.Operator = operator
+}
+
+func setGroup(change *bsdb.ChangeEvent, groupID storagetypes.Uint, groupName, operator string) {
+	change.ResourceType = bsdb.ChangeResourceGroup
+	change.ResourceID = common.BigToHash(groupID.BigInt())
+	change.GroupName = groupName
+	change.Operator = operator
+}
+
+// encodeAttributes encodes the attributes of the event to a json object, the values of the
+// typed event attributes are json already.
+func encodeAttributes(event sdk.Event) (string, error) {
+	attributes := make(map[string]json.RawMessage, len(event.Attributes))
+	for _, attribute := range event.Attributes {
+		if json.Valid([]byte(attribute.Value)) {
+			attributes[attribute.Key] = json.RawMessage(attribute.Value)
+			continue
+		}
+		value, err := json.Marshal(attribute.Value)
+		if err != nil {
+			return "", err
+		}
+		attributes[attribute.Key] = value
+	}
+	data, err := json.Marshal(attributes)
+	return string(data), err
+}
+
+// eventKey returns the hash that identifies the event of the block, so the replayed event is not appended twice.
+func eventKey(height int64, txHash common.Hash, eventType, attributes string) common.Hash {
+	hash := sha256.New()
+	_ = binary.Write(hash, binary.BigEndian, height)
+	hash.Write(txHash.Bytes())
+	hash.Write([]byte(eventType))
+	hash.Write([]byte(attributes))
+	return common.BytesToHash(hash.Sum(nil))
+}
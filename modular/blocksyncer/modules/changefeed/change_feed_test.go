@@ -0,0 +1,146 @@
+package changefeed
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/blocksyncertest"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield/types/resource"
+	permissiontypes "github.com/bnb-chain/greenfield/x/permission/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	testOperator = "0x0000000000000000000000000000000000000001"
+	testOwner    = "0x0000000000000000000000000000000000000002"
+)
+
+func newTestModule(t *testing.T) *Module {
+	return NewModule(blocksyncertest.NewDB(t, &bsdb.ChangeEvent{}, &bsdb.Object{}, &bsdb.Bucket{}, &bsdb.Group{}, &bsdb.Permission{}))
+}
+
+func listChangeEvents(t *testing.T, m *Module) []*bsdb.ChangeEvent {
+	var events []*bsdb.ChangeEvent
+	require.NoError(t, m.db.Db.Order("id").Find(&events).Error)
+	return events
+}
+
+func TestModule_Normalize(t *testing.T) {
+	m := newTestModule(t)
+	bucketID, objectID, groupID := math.NewUint(1), math.NewUint(2), math.NewUint(3)
+	require.NoError(t, m.db.Db.Create(&bsdb.Bucket{BucketID: common.BigToHash(bucketID.BigInt()), BucketName: "bucket"}).Error)
+	require.NoError(t, m.db.Db.Create(&bsdb.Object{ObjectID: common.BigToHash(objectID.BigInt()), BucketName: "bucket",
+		ObjectName: "object"}).Error)
+	require.NoError(t, m.db.Db.Create(&bsdb.Group{GroupID: common.BigToHash(groupID.BigInt()), GroupName: "group"}).Error)
+	require.NoError(t, m.db.Db.Create(&bsdb.Permission{PolicyID: common.BigToHash(math.NewUint(4).BigInt()),
+		ResourceType: resource.RESOURCE_TYPE_OBJECT.String(), ResourceID: common.BigToHash(objectID.BigInt()), Removed: true}).Error)
+
+	bucket := bsdb.ChangeEvent{ResourceType: bsdb.ChangeResourceBucket, ResourceID: common.BigToHash(bucketID.BigInt()),
+		BucketName: "bucket", Operator: testOperator}
+	object := bsdb.ChangeEvent{ResourceType: bsdb.ChangeResourceObject, ResourceID: common.BigToHash(objectID.BigInt()),
+		BucketName: "bucket", ObjectName: "object", Operator: testOperator}
+	group := bsdb.ChangeEvent{ResourceType: bsdb.ChangeResourceGroup, ResourceID: common.BigToHash(groupID.BigInt()),
+		GroupName: "group", Operator: testOperator}
+	with := func(change bsdb.ChangeEvent, eventType string, set func(*bsdb.ChangeEvent)) bsdb.ChangeEvent {
+		change.EventType = eventType
+		if set != nil {
+			set(&change)
+		}
+		return change
+	}
+	noOperator := func(change *bsdb.ChangeEvent) { change.Operator = "" }
+	cases := []struct {
+		event  proto.Message
+		wanted bsdb.ChangeEvent
+	}{
+		{&storagetypes.EventCreateBucket{Owner: testOperator, BucketName: "bucket", BucketId: bucketID},
+			with(bucket, bsdb.ChangeBucketCreated, nil)},
+		{&storagetypes.EventUpdateBucketInfo{Operator: testOperator, BucketName: "bucket", BucketId: bucketID},
+			with(bucket, bsdb.ChangeBucketUpdated, nil)},
+		{&storagetypes.EventDeleteBucket{Operator: testOperator, BucketName: "bucket", BucketId: bucketID},
+			with(bucket, bsdb.ChangeBucketDeleted, nil)},
+		{&storagetypes.EventDiscontinueBucket{BucketName: "bucket", BucketId: bucketID},
+			with(bucket, bsdb.ChangeBucketDiscontinued, noOperator)},
+		{&storagetypes.EventCreateObject{Creator: testOperator, Owner: testOwner, BucketName: "bucket", ObjectName: "object",
+			BucketId: bucketID, ObjectId: objectID}, with(object, bsdb.ChangeObjectCreated, nil)},
+		{&storagetypes.EventSealObject{Operator: testOperator, BucketName: "bucket", ObjectName: "object", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectSealed, nil)},
+		{&storagetypes.EventRejectSealObject{Operator: testOperator, BucketName: "bucket", ObjectName: "object", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectSealRejected, nil)},
+		{&storagetypes.EventCancelCreateObject{Operator: testOperator, BucketName: "bucket", ObjectName: "object", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectCreateCanceled, nil)},
+		{&storagetypes.EventCopyObject{Operator: testOperator, SrcBucketName: "src", SrcObjectName: "src", DstBucketName: "bucket",
+			DstObjectName: "object", SrcObjectId: math.NewUint(10), DstObjectId: objectID},
+			with(object, bsdb.ChangeObjectCopied, nil)},
+		{&storagetypes.EventUpdateObjectInfo{Operator: testOperator, BucketName: "bucket", ObjectName: "object", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectUpdated, nil)},
+		{&storagetypes.EventDeleteObject{Operator: testOperator, BucketName: "bucket", ObjectName: "object", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectDeleted, nil)},
+		// the object name is found by the object id
+		{&storagetypes.EventDiscontinueObject{BucketName: "bucket", ObjectId: objectID},
+			with(object, bsdb.ChangeObjectDiscontinued, noOperator)},
+		{&storagetypes.EventCreateGroup{Owner: testOperator, GroupName: "group", GroupId: groupID},
+			with(group, bsdb.ChangeGroupCreated, nil)},
+		{&storagetypes.EventUpdateGroupMember{Operator: testOperator, Owner: testOwner, GroupName: "group", GroupId: groupID},
+			with(group, bsdb.ChangeGroupMemberUpdated, nil)},
+		{&storagetypes.EventLeaveGroup{MemberAddress: testOperator, Owner: testOwner, GroupName: "group", GroupId: groupID},
+			with(group, bsdb.ChangeGroupMemberLeft, nil)},
+		{&storagetypes.EventDeleteGroup{Owner: testOperator, GroupName: "group", GroupId: groupID},
+			with(group, bsdb.ChangeGroupDeleted, nil)},
+		// the policy events are attached to the resource of the policy
+		{&permissiontypes.EventPutPolicy{Principal: &permissiontypes.Principal{}, ResourceType: resource.RESOURCE_TYPE_BUCKET,
+			ResourceId: bucketID, PolicyId: math.NewUint(5)}, with(bucket, bsdb.ChangePolicyPut, noOperator)},
+		{&permissiontypes.EventPutPolicy{Principal: &permissiontypes.Principal{}, ResourceType: resource.RESOURCE_TYPE_GROUP,
+			ResourceId: groupID, PolicyId: math.NewUint(6)}, with(group, bsdb.ChangePolicyPut, noOperator)},
+		{&permissiontypes.EventDeletePolicy{PolicyId: math.NewUint(4)},
+			with(object, bsdb.ChangePolicyDeleted, noOperator)},
+	}
+	for i, c := range cases {
+		blocksyncertest.HandleEvent(t, m, int64(i+1), common.BigToHash(math.NewUint(uint64(i+1)).BigInt()), c.event)
+	}
+	// the events that are not changes are ignored
+	blocksyncertest.HandleEvent(t, m, 100, common.Hash{}, &storagetypes.EventMirrorBucket{BucketName: "bucket", BucketId: bucketID})
+
+	events := listChangeEvents(t, m)
+	require.Len(t, events, len(cases))
+	for i, c := range cases {
+		t.Run(c.wanted.EventType, func(t *testing.T) {
+			event := events[i]
+			assert.Equal(t, int64(i+1), event.BlockHeight)
+			assert.Equal(t, int64(1000+i+1), event.Timestamp)
+			assert.Equal(t, common.BigToHash(math.NewUint(uint64(i+1)).BigInt()), event.TxHash)
+			assert.NotEmpty(t, event.Attributes)
+			event.ID, event.EventKey, event.BlockHeight, event.Timestamp, event.TxHash, event.Attributes = 0, common.Hash{}, 0, 0, common.Hash{}, ""
+			assert.Equal(t, c.wanted, *event)
+		})
+	}
+}
+
+func TestModule_ReplayedBlock(t *testing.T) {
+	m := newTestModule(t)
+	txHash := common.HexToHash("0x01")
+	create := func(name string) proto.Message {
+		return &storagetypes.EventCreateBucket{Owner: testOperator, BucketName: name, BucketId: math.NewUint(1)}
+	}
+	// the block is replayed after the restart, its events are appended only once
+	for i := 0; i < 2; i++ {
+		blocksyncertest.HandleEvent(t, m, 1, txHash, create("bucket1"))
+		blocksyncertest.HandleEvent(t, m, 1, txHash, create("bucket2"))
+	}
+	// the same event of the other block or tx is a new change
+	blocksyncertest.HandleEvent(t, m, 2, txHash, create("bucket1"))
+	blocksyncertest.HandleEvent(t, m, 1, common.HexToHash("0x02"), create("bucket1"))
+
+	events := listChangeEvents(t, m)
+	require.Len(t, events, 4)
+	assert.Equal(t, "bucket1", events[0].BucketName)
+	assert.Equal(t, "bucket2", events[1].BucketName)
+	assert.Equal(t, eventKey(1, txHash, proto.MessageName(create("")), events[0].Attributes), events[0].EventKey)
+	assert.NotEqual(t, events[0].EventKey, events[1].EventKey)
+}
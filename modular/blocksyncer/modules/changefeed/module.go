@@ -0,0 +1,34 @@
+package changefeed
+
+import (
+	"github.com/forbole/juno/v4/modules"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+)
+
+const (
+	ModuleName = "change_feed"
+)
+
+var (
+	_ modules.Module      = &Module{}
+	_ modules.EventModule = &Module{}
+)
+
+// Module represents the change feed module, it appends the normalized changes of buckets,
+// objects, groups and policies to the change event log
+type Module struct {
+	db *database.DB
+}
+
+// NewModule builds a new Module instance
+func NewModule(db *database.DB) *Module {
+	return &Module{
+		db: db,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
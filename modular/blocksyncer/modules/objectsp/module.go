@@ -0,0 +1,34 @@
+package objectsp
+
+import (
+	"github.com/forbole/juno/v4/modules"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+)
+
+const (
+	ModuleName = "object_sp"
+)
+
+var (
+	_ modules.Module      = &Module{}
+	_ modules.EventModule = &Module{}
+)
+
+// Module represents the object sp module, it maps the sealed objects to their primary sp and
+// secondary sps, so the objects of a sp are listed by the index
+type Module struct {
+	db *database.DB
+}
+
+// NewModule builds a new Module instance
+func NewModule(db *database.DB) *Module {
+	return &Module{
+		db: db,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
@@ -0,0 +1,67 @@
+package objectsp
+
+import (
+	"context"
+	"errors"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+var (
+	EventSealObject   = proto.MessageName(&storagetypes.EventSealObject{})
+	EventCopyObject   = proto.MessageName(&storagetypes.EventCopyObject{})
+	EventDeleteObject = proto.MessageName(&storagetypes.EventDeleteObject{})
+)
+
+// objectSpEvents maps event types that seal or remove objects.
+var objectSpEvents = map[string]bool{
+	EventSealObject:   true,
+	EventCopyObject:   true,
+	EventDeleteObject: true,
+}
+
+// HandleEvent handles the events relevant to the object sps, the object is mapped after the
+// object module has saved the sealed object.
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if !objectSpEvents[event.Type] {
+		return nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	if err != nil {
+		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+
+	switch event.Type {
+	case EventSealObject:
+		sealObject, ok := typedEvent.(*storagetypes.EventSealObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventSealObject", "event", typedEvent)
+			return errors.New("seal object event assert error")
+		}
+		return m.db.SaveObjectSps(ctx, common.BigToHash(sealObject.ObjectId.BigInt()))
+	case EventCopyObject:
+		copyObject, ok := typedEvent.(*storagetypes.EventCopyObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCopyObject", "event", typedEvent)
+			return errors.New("copy object event assert error")
+		}
+		return m.db.SaveObjectSps(ctx, common.BigToHash(copyObject.DstObjectId.BigInt()))
+	case EventDeleteObject:
+		deleteObject, ok := typedEvent.(*storagetypes.EventDeleteObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventDeleteObject", "event", typedEvent)
+			return errors.New("delete object event assert error")
+		}
+		return m.db.DeleteObjectSps(ctx, common.BigToHash(deleteObject.ObjectId.BigInt()))
+	}
+	return nil
+}
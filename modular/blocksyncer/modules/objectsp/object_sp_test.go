@@ -0,0 +1,69 @@
+package objectsp
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/blocksyncertest"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	testPrimarySp   = "0x0000000000000000000000000000000000000001"
+	testSecondarySp = "0x0000000000000000000000000000000000000002"
+)
+
+func newTestModule(t *testing.T) *Module {
+	return NewModule(blocksyncertest.NewDB(t, &bsdb.Object{}, &bsdb.Bucket{}, &bsdb.ObjectSp{}))
+}
+
+func listObjectSps(t *testing.T, m *Module, objectID math.Uint) []*bsdb.ObjectSp {
+	var objectSps []*bsdb.ObjectSp
+	require.NoError(t, m.db.Db.Where("object_id = ?", common.BigToHash(objectID.BigInt())).
+		Order("sp_address").Find(&objectSps).Error)
+	return objectSps
+}
+
+func TestModule_HandleEvent(t *testing.T) {
+	m := newTestModule(t)
+	bucketID := common.BigToHash(math.NewUint(1).BigInt())
+	sealed, created, copied := math.NewUint(2), math.NewUint(3), math.NewUint(4)
+	require.NoError(t, m.db.Db.Create(&bsdb.Bucket{BucketID: bucketID, BucketName: "bucket",
+		PrimarySpAddress: common.HexToAddress(testPrimarySp)}).Error)
+	for _, object := range []*bsdb.Object{
+		{ID: 1, ObjectID: common.BigToHash(sealed.BigInt()), BucketID: bucketID, ObjectName: "sealed",
+			ObjectStatus:         storagetypes.OBJECT_STATUS_SEALED.String(),
+			SecondarySpAddresses: []string{testPrimarySp, testSecondarySp}},
+		{ID: 2, ObjectID: common.BigToHash(created.BigInt()), BucketID: bucketID, ObjectName: "created",
+			ObjectStatus: storagetypes.OBJECT_STATUS_CREATED.String()},
+		{ID: 3, ObjectID: common.BigToHash(copied.BigInt()), BucketID: bucketID, ObjectName: "copied",
+			ObjectStatus: storagetypes.OBJECT_STATUS_SEALED.String(), SecondarySpAddresses: []string{testSecondarySp}},
+	} {
+		require.NoError(t, m.db.Db.Create(object).Error)
+	}
+
+	// the sealed object is mapped to its primary sp and the other secondary sp once
+	blocksyncertest.HandleEvent(t, m, 0, common.Hash{}, &storagetypes.EventSealObject{ObjectId: sealed})
+	blocksyncertest.HandleEvent(t, m, 0, common.Hash{}, &storagetypes.EventSealObject{ObjectId: sealed})
+	objectSps := listObjectSps(t, m, sealed)
+	require.Len(t, objectSps, 2)
+	assert.Equal(t, common.HexToAddress(testPrimarySp), objectSps[0].SpAddress)
+	assert.Equal(t, common.HexToAddress(testSecondarySp), objectSps[1].SpAddress)
+	assert.Equal(t, uint64(1), objectSps[0].ObjectDBID)
+
+	// the object not sealed yet is not mapped
+	blocksyncertest.HandleEvent(t, m, 0, common.Hash{}, &storagetypes.EventSealObject{ObjectId: created})
+	assert.Empty(t, listObjectSps(t, m, created))
+
+	blocksyncertest.HandleEvent(t, m, 0, common.Hash{}, &storagetypes.EventCopyObject{DstObjectId: copied})
+	assert.Len(t, listObjectSps(t, m, copied), 2)
+
+	blocksyncertest.HandleEvent(t, m, 0, common.Hash{}, &storagetypes.EventDeleteObject{ObjectId: sealed})
+	assert.Empty(t, listObjectSps(t, m, sealed))
+	assert.Len(t, listObjectSps(t, m, copied), 2)
+}
@@ -14,7 +14,10 @@ import (
 	sp "github.com/forbole/juno/v4/modules/storage_provider"
 
 	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules/changefeed"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules/objectsp"
 	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules/prefixtree"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules/storagestats"
 )
 
 var (
@@ -47,5 +50,8 @@ func (r *BlockSyncerRegistrar) BuildModules(ctx registrar.Context) modules.Modul
 		group.NewModule(db),
 		sp.NewModule(db),
 		prefixtree.NewModule(db),
+		storagestats.NewModule(db),
+		changefeed.NewModule(db),
+		objectsp.NewModule(db),
 	}
 }
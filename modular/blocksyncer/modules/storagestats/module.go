@@ -0,0 +1,33 @@
+package storagestats
+
+import (
+	"github.com/forbole/juno/v4/modules"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/database"
+)
+
+const (
+	ModuleName = "storage_stats"
+)
+
+var (
+	_ modules.Module      = &Module{}
+	_ modules.EventModule = &Module{}
+)
+
+// Module represents the storage stats module, it counts the objects of buckets and accounts
+type Module struct {
+	db *database.DB
+}
+
+// NewModule builds a new Module instance
+func NewModule(db *database.DB) *Module {
+	return &Module{
+		db: db,
+	}
+}
+
+// Name implements modules.Module
+func (m *Module) Name() string {
+	return ModuleName
+}
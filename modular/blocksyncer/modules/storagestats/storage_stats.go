@@ -0,0 +1,154 @@
+package storagestats
+
+import (
+	"context"
+	"errors"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/forbole/juno/v4/common"
+	"github.com/forbole/juno/v4/log"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+var (
+	EventCreateObject       = proto.MessageName(&storagetypes.EventCreateObject{})
+	EventSealObject         = proto.MessageName(&storagetypes.EventSealObject{})
+	EventCopyObject         = proto.MessageName(&storagetypes.EventCopyObject{})
+	EventDeleteObject       = proto.MessageName(&storagetypes.EventDeleteObject{})
+	EventCancelCreateObject = proto.MessageName(&storagetypes.EventCancelCreateObject{})
+	EventRejectSealObject   = proto.MessageName(&storagetypes.EventRejectSealObject{})
+	EventDiscontinueObject  = proto.MessageName(&storagetypes.EventDiscontinueObject{})
+)
+
+// storageStatsEvents maps event types that change the object count, the payload size or the object status.
+var storageStatsEvents = map[string]bool{
+	EventCreateObject:       true,
+	EventSealObject:         true,
+	EventCopyObject:         true,
+	EventDeleteObject:       true,
+	EventCancelCreateObject: true,
+	EventRejectSealObject:   true,
+	EventDiscontinueObject:  true,
+}
+
+// HandleEvent handles the events relevant to the storage stats.
+// It computes the new state of the object and updates the stats by the difference.
+func (m *Module) HandleEvent(ctx context.Context, block *tmctypes.ResultBlock, txHash common.Hash, event sdk.Event) error {
+	if !storageStatsEvents[event.Type] {
+		return nil
+	}
+
+	typedEvent, err := sdk.ParseTypedEvent(abci.Event(event))
+	if err != nil {
+		log.Errorw("parse typed events error", "module", m.Name(), "event", event, "err", err)
+		return err
+	}
+
+	height := block.Block.Height
+	switch event.Type {
+	case EventCreateObject:
+		createObject, ok := typedEvent.(*storagetypes.EventCreateObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCreateObject", "event", typedEvent)
+			return errors.New("create object event assert error")
+		}
+		return m.db.UpdateObjectStats(ctx, common.BigToHash(createObject.ObjectId.BigInt()), &bsdb.ObjectStats{
+			BucketID:     common.BigToHash(createObject.BucketId.BigInt()),
+			BucketName:   createObject.BucketName,
+			Owner:        common.HexToAddress(createObject.Owner),
+			PayloadSize:  createObject.PayloadSize,
+			ObjectStatus: createObject.Status.String(),
+		}, height)
+	case EventSealObject:
+		sealObject, ok := typedEvent.(*storagetypes.EventSealObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventSealObject", "event", typedEvent)
+			return errors.New("seal object event assert error")
+		}
+		return m.updateObjectStatus(ctx, common.BigToHash(sealObject.ObjectId.BigInt()), sealObject.Status.String(), height)
+	case EventDiscontinueObject:
+		discontinueObject, ok := typedEvent.(*storagetypes.EventDiscontinueObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventDiscontinueObject", "event", typedEvent)
+			return errors.New("discontinue object event assert error")
+		}
+		return m.updateObjectStatus(ctx, common.BigToHash(discontinueObject.ObjectId.BigInt()),
+			storagetypes.OBJECT_STATUS_DISCONTINUED.String(), height)
+	case EventCopyObject:
+		copyObject, ok := typedEvent.(*storagetypes.EventCopyObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCopyObject", "event", typedEvent)
+			return errors.New("copy object event assert error")
+		}
+		return m.handleCopyObject(ctx, copyObject, height)
+	case EventDeleteObject:
+		deleteObject, ok := typedEvent.(*storagetypes.EventDeleteObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventDeleteObject", "event", typedEvent)
+			return errors.New("delete object event assert error")
+		}
+		return m.db.UpdateObjectStats(ctx, common.BigToHash(deleteObject.ObjectId.BigInt()), nil, height)
+	case EventCancelCreateObject:
+		cancelObject, ok := typedEvent.(*storagetypes.EventCancelCreateObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventCancelCreateObject", "event", typedEvent)
+			return errors.New("cancel create object event assert error")
+		}
+		return m.db.UpdateObjectStats(ctx, common.BigToHash(cancelObject.ObjectId.BigInt()), nil, height)
+	case EventRejectSealObject:
+		rejectSealObject, ok := typedEvent.(*storagetypes.EventRejectSealObject)
+		if !ok {
+			log.Errorw("type assert error", "type", "EventRejectSealObject", "event", typedEvent)
+			return errors.New("reject seal object event assert error")
+		}
+		return m.db.UpdateObjectStats(ctx, common.BigToHash(rejectSealObject.ObjectId.BigInt()), nil, height)
+	default:
+		return nil
+	}
+}
+
+// updateObjectStatus updates the status of the counted object. The object that is not counted
+// is skipped, it is counted by the reconciliation.
+func (m *Module) updateObjectStatus(ctx context.Context, objectID common.Hash, status string, height int64) error {
+	state, err := m.db.GetObjectStats(ctx, objectID)
+	if err != nil {
+		log.Errorw("failed to get object stats", "object_id", objectID.String(), "error", err)
+		return err
+	}
+	if state == nil {
+		log.Warnw("object is not counted in the storage stats", "object_id", objectID.String())
+		return nil
+	}
+	state.ObjectStatus = status
+	return m.db.UpdateObjectStats(ctx, objectID, state, height)
+}
+
+// handleCopyObject counts the copied object in the destination bucket, it is owned by the operator.
+func (m *Module) handleCopyObject(ctx context.Context, copyObject *storagetypes.EventCopyObject, height int64) error {
+	srcState, err := m.db.GetObjectStats(ctx, common.BigToHash(copyObject.SrcObjectId.BigInt()))
+	if err != nil {
+		log.Errorw("failed to get object stats", "object_id", copyObject.SrcObjectId.String(), "error", err)
+		return err
+	}
+	if srcState == nil {
+		log.Warnw("source object is not counted in the storage stats", "object_id", copyObject.SrcObjectId.String())
+		return nil
+	}
+	bucketID, err := m.db.GetBucketIDByName(ctx, copyObject.DstBucketName)
+	if err != nil {
+		log.Errorw("failed to get bucket id", "bucket_name", copyObject.DstBucketName, "error", err)
+		return err
+	}
+	return m.db.UpdateObjectStats(ctx, common.BigToHash(copyObject.DstObjectId.BigInt()), &bsdb.ObjectStats{
+		BucketID:     bucketID,
+		BucketName:   copyObject.DstBucketName,
+		Owner:        common.HexToAddress(copyObject.Operator),
+		PayloadSize:  srcState.PayloadSize,
+		ObjectStatus: srcState.ObjectStatus,
+	}, height)
+}
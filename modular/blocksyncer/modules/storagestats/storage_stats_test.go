@@ -0,0 +1,77 @@
+package storagestats
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/blocksyncertest"
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func newTestModule(t *testing.T) *Module {
+	return NewModule(blocksyncertest.NewDB(t, &bsdb.Bucket{}, &bsdb.ObjectStats{}, &bsdb.BucketStats{}, &bsdb.AccountStats{}))
+}
+
+func TestModule_HandleEvent(t *testing.T) {
+	m := newTestModule(t)
+	owner, operator := "0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"
+	bucketID, dstBucketID := math.NewUint(1), math.NewUint(2)
+	require.NoError(t, m.db.Db.Create(&bsdb.Bucket{BucketID: common.BigToHash(dstBucketID.BigInt()), BucketName: "dst"}).Error)
+	bucketStats := func(id math.Uint) bsdb.StorageStats {
+		var stats []*bsdb.BucketStats
+		require.NoError(t, m.db.Db.Where("bucket_id = ?", common.BigToHash(id.BigInt())).Find(&stats).Error)
+		if len(stats) == 0 {
+			return bsdb.StorageStats{}
+		}
+		return stats[0].StorageStats
+	}
+	accountStats := func(addr string) bsdb.StorageStats {
+		var stats []*bsdb.AccountStats
+		require.NoError(t, m.db.Db.Where("owner = ?", common.HexToAddress(addr)).Find(&stats).Error)
+		if len(stats) == 0 {
+			return bsdb.StorageStats{}
+		}
+		return stats[0].StorageStats
+	}
+
+	for i, size := range []uint64{10, 20, 30} {
+		blocksyncertest.HandleEvent(t, m, 1, common.Hash{}, &storagetypes.EventCreateObject{Owner: owner, BucketName: "src", BucketId: bucketID,
+			ObjectId: math.NewUint(uint64(i + 1)), PayloadSize: size, Status: storagetypes.OBJECT_STATUS_CREATED})
+	}
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 3, PayloadSize: 60, UnsealedCount: 3}, bucketStats(bucketID))
+
+	blocksyncertest.HandleEvent(t, m, 2, common.Hash{}, &storagetypes.EventSealObject{BucketName: "src", ObjectId: math.NewUint(1),
+		Status: storagetypes.OBJECT_STATUS_SEALED})
+	blocksyncertest.HandleEvent(t, m, 2, common.Hash{}, &storagetypes.EventSealObject{BucketName: "src", ObjectId: math.NewUint(2),
+		Status: storagetypes.OBJECT_STATUS_SEALED})
+	// sealing the object that is not counted is skipped
+	blocksyncertest.HandleEvent(t, m, 2, common.Hash{}, &storagetypes.EventSealObject{BucketName: "src", ObjectId: math.NewUint(100),
+		Status: storagetypes.OBJECT_STATUS_SEALED})
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 3, PayloadSize: 60, SealedCount: 2, UnsealedCount: 1}, bucketStats(bucketID))
+
+	// the discontinued object is counted but neither sealed nor unsealed
+	blocksyncertest.HandleEvent(t, m, 3, common.Hash{}, &storagetypes.EventDiscontinueObject{BucketName: "src", ObjectId: math.NewUint(2)})
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 3, PayloadSize: 60, SealedCount: 1, UnsealedCount: 1}, bucketStats(bucketID))
+
+	// the copied object is counted in the destination bucket and owned by the operator
+	blocksyncertest.HandleEvent(t, m, 4, common.Hash{}, &storagetypes.EventCopyObject{Operator: operator, SrcBucketName: "src", DstBucketName: "dst",
+		SrcObjectId: math.NewUint(1), DstObjectId: math.NewUint(4)})
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 1, PayloadSize: 10, SealedCount: 1}, bucketStats(dstBucketID))
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 1, PayloadSize: 10, SealedCount: 1}, accountStats(operator))
+
+	blocksyncertest.HandleEvent(t, m, 5, common.Hash{}, &storagetypes.EventDeleteObject{BucketName: "src", ObjectId: math.NewUint(1)})
+	blocksyncertest.HandleEvent(t, m, 5, common.Hash{}, &storagetypes.EventCancelCreateObject{BucketName: "src", ObjectId: math.NewUint(3)})
+	blocksyncertest.HandleEvent(t, m, 5, common.Hash{}, &storagetypes.EventRejectSealObject{BucketName: "src", ObjectId: math.NewUint(2)})
+	assert.Equal(t, bsdb.StorageStats{}, bucketStats(bucketID))
+	assert.Equal(t, bsdb.StorageStats{}, accountStats(owner))
+	assert.Equal(t, bsdb.StorageStats{ObjectCount: 1, PayloadSize: 10, SealedCount: 1}, bucketStats(dstBucketID))
+
+	// the other events are ignored
+	blocksyncertest.HandleEvent(t, m, 6, common.Hash{}, &storagetypes.EventCreateBucket{BucketName: "other", BucketId: math.NewUint(3)})
+	assert.Equal(t, bsdb.StorageStats{}, bucketStats(math.NewUint(3)))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
+	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/core/task"
@@ -42,7 +43,7 @@ func (d *DownloadModular) PreDownloadObject(ctx context.Context, downloadObjectT
 		return ErrObjectUnsealed
 	}
 	// TODO:: spilt check and add record steps, check in pre download, add record in post download
-	if err := d.baseApp.GfSpDB().CheckQuotaAndAddReadRecord(
+	if err := d.baseApp.GfSpDBWithContext(ctx).CheckQuotaAndAddReadRecord(
 		&spdb.ReadRecord{
 			BucketID:        downloadObjectTask.GetBucketInfo().Id.Uint64(),
 			ObjectID:        downloadObjectTask.GetObjectInfo().Id.Uint64(),
@@ -104,6 +105,13 @@ func (d *DownloadModular) HandleDownloadObjectTask(ctx context.Context, download
 		d.pieceCache.Add(key, piece)
 		data = append(data, piece...)
 	}
+	// user downloads borrow the bandwidth without waiting, the replication traffic yields
+	if d.bandwidthBorrowing {
+		if err := d.baseApp.ResourceManager().BandwidthLimiter().WaitN(ctx, "", len(data), rcmgr.ReserveTaskPriorityHigh); err != nil {
+			// the download is not failed by the shaping, only the replication traffic yields less
+			log.CtxWarnw(ctx, "failed to borrow download bandwidth", "size", len(data), "error", err)
+		}
+	}
 	return data, nil
 }
 
@@ -198,7 +206,7 @@ func (d *DownloadModular) PreDownloadPiece(ctx context.Context, downloadPieceTas
 		if d.baseApp.OperatorAddress() == primarySP {
 			log.CtxDebugw(ctx, "downloading from primary SP, checking quota")
 			checkQuotaTime := time.Now()
-			if err := d.baseApp.GfSpDB().CheckQuotaAndAddReadRecord(
+			if err := d.baseApp.GfSpDBWithContext(ctx).CheckQuotaAndAddReadRecord(
 				&spdb.ReadRecord{
 					BucketID:        downloadPieceTask.GetBucketInfo().Id.Uint64(),
 					ObjectID:        downloadPieceTask.GetObjectInfo().Id.Uint64(),
@@ -236,7 +244,7 @@ func (d *DownloadModular) PreDownloadPiece(ctx context.Context, downloadPieceTas
 			// check free quota
 			log.CtxDebugw(ctx, "downloading piece from secondary SP, checking quota")
 			// TODO traffic db add read type to indicate secondary
-			if err := d.baseApp.GfSpDB().CheckQuotaAndAddReadRecord(
+			if err := d.baseApp.GfSpDBWithContext(ctx).CheckQuotaAndAddReadRecord(
 				&spdb.ReadRecord{
 					BucketID:        downloadPieceTask.GetBucketInfo().Id.Uint64(),
 					ObjectID:        downloadPieceTask.GetObjectInfo().Id.Uint64(),
@@ -345,7 +353,7 @@ func (d *DownloadModular) HandleChallengePiece(ctx context.Context, downloadPiec
 		downloadPieceTask.GetSegmentIdx(),
 		downloadPieceTask.GetRedundancyIdx())
 	getIntegrityTime := time.Now()
-	integrity, err = d.baseApp.GfSpDB().GetObjectIntegrity(downloadPieceTask.GetObjectInfo().Id.Uint64())
+	integrity, err = d.baseApp.GfSpDBWithContext(ctx).GetObjectIntegrity(downloadPieceTask.GetObjectInfo().Id.Uint64())
 	metrics.PerfChallengeTimeHistogram.WithLabelValues("challenge_get_integrity_time").Observe(time.Since(getIntegrityTime).Seconds())
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to get integrity hash", "error", err)
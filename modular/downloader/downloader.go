@@ -3,15 +3,18 @@ package downloader
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 )
 
 var _ module.Downloader = &DownloadModular{}
+var _ gfspapp.Reloadable = &DownloadModular{}
 
 type DownloadModular struct {
 	baseApp           *gfspapp.GfSpBaseApp
@@ -25,6 +28,9 @@ type DownloadModular struct {
 	// bucketFreeQuota defines the free read quota per bucket, if exceed
 	// the quota, the account should buy traffic.
 	bucketFreeQuota uint64
+	// bandwidthBorrowing indicates whether the downloads borrow the bandwidth limit of the
+	// replicating traffic.
+	bandwidthBorrowing bool
 }
 
 func (d *DownloadModular) Name() string {
@@ -40,6 +46,20 @@ func (d *DownloadModular) Start(ctx context.Context) error {
 	return nil
 }
 
+// Reload applies the changed max parallel of downloading and challenging, the piece cache
+// keeps the size at the start.
+func (d *DownloadModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if cfg.Parallel.DownloadObjectParallelPerNode == 0 {
+		cfg.Parallel.DownloadObjectParallelPerNode = DefaultDownloadObjectParallelPerNode
+	}
+	if cfg.Parallel.ChallengePieceParallelPerNode == 0 {
+		cfg.Parallel.ChallengePieceParallelPerNode = DefaultChallengePieceParallelPerNode
+	}
+	atomic.StoreInt64(&d.downloadParallel, int64(cfg.Parallel.DownloadObjectParallelPerNode))
+	atomic.StoreInt64(&d.challengeParallel, int64(cfg.Parallel.ChallengePieceParallelPerNode))
+	return nil
+}
+
 func (d *DownloadModular) Stop(ctx context.Context) error {
 	d.scope.Release()
 	return nil
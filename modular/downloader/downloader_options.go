@@ -45,5 +45,6 @@ func DefaultDownloaderOptions(downloader *DownloadModular, cfg *gfspconfig.GfSpC
 	downloader.downloadParallel = int64(cfg.Parallel.DownloadObjectParallelPerNode)
 	downloader.challengeParallel = int64(cfg.Parallel.ChallengePieceParallelPerNode)
 	downloader.bucketFreeQuota = cfg.Bucket.FreeQuotaPerBucket
+	downloader.bandwidthBorrowing = gfspapp.DownloadBorrowingEnabled(cfg)
 	return nil
 }
@@ -0,0 +1,19 @@
+package downloader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+)
+
+func TestDownloadModular_Reload(t *testing.T) {
+	d := &DownloadModular{}
+	cfg := &gfspconfig.GfSpConfig{}
+	cfg.Parallel.DownloadObjectParallelPerNode = 10
+	assert.NoError(t, d.Reload(context.Background(), cfg))
+	assert.Equal(t, int64(10), d.downloadParallel)
+	assert.Equal(t, int64(DefaultChallengePieceParallelPerNode), d.challengeParallel)
+}
@@ -0,0 +1,283 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/bnb-chain/greenfield-common/go/redundancy"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+const (
+	// primaryRecoveryEcIdx defines the ec index of the recovery task that recovers the
+	// segment of primary sp.
+	primaryRecoveryEcIdx int32 = -1
+
+	recoverySourcePrimary   = "primary"
+	recoverySourceSecondary = "secondary"
+)
+
+// recoverySource is the sp that holds the ec piece of the ec index.
+type recoverySource struct {
+	ecIdx    int
+	endpoint string
+}
+
+type recoveryResult struct {
+	data    []byte
+	sources []string
+	err     error
+}
+
+// recoveryFetcher fetches the piece from the sp endpoint.
+type recoveryFetcher func(ctx context.Context, endpoint string) ([]byte, error)
+
+// recoverSecondaryPiece recovers the ec piece of secondary sp, it gets the piece from primary
+// sp first, if primary sp fails or responds slowly, the piece is reconstructed by the ec
+// pieces of the other secondary sps at the same time, the first succeed one is used.
+func (e *ExecuteModular) recoverSecondaryPiece(ctx context.Context, task coretask.RecoveryPieceTask) ([]byte, []string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	primaryCh := make(chan *recoveryResult, 1)
+	go func() {
+		endpoint, err := e.getObjectPrimarySPEndpoint(ctx, task.GetObjectInfo().GetBucketName())
+		if err != nil {
+			primaryCh <- &recoveryResult{err: err}
+			return
+		}
+		data, err := e.doRecoveryPiece(ctx, task, endpoint)
+		primaryCh <- &recoveryResult{data: data, sources: []string{endpoint}, err: err}
+	}()
+
+	var (
+		fallbackCh   chan *recoveryResult
+		hedge        = time.NewTimer(e.recoveryHedgeDelay)
+		hedgeCh      = hedge.C
+		primaryDone  bool
+		fallbackDone bool
+		lastErr      error
+	)
+	defer hedge.Stop()
+	startFallback := func() {
+		if fallbackCh != nil || fallbackDone {
+			return
+		}
+		fallbackCh = make(chan *recoveryResult, 1)
+		go func() {
+			data, sources, err := e.reconstructSecondaryPiece(ctx, task)
+			fallbackCh <- &recoveryResult{data: data, sources: sources, err: err}
+		}()
+	}
+	for !primaryDone || !fallbackDone {
+		select {
+		case r := <-primaryCh:
+			primaryCh, primaryDone = nil, true
+			if r.err == nil {
+				metrics.RecoverPieceSourceCounter.WithLabelValues(recoverySourcePrimary).Inc()
+				return r.data, r.sources, nil
+			}
+			log.CtxWarnw(ctx, "failed to recover piece from primary sp, fallback to secondary sps", "error", r.err)
+			lastErr = r.err
+			startFallback()
+		case r := <-fallbackCh:
+			fallbackCh, fallbackDone = nil, true
+			if r.err == nil {
+				metrics.RecoverPieceSourceCounter.WithLabelValues(recoverySourceSecondary).Inc()
+				return r.data, r.sources, nil
+			}
+			log.CtxWarnw(ctx, "failed to reconstruct piece from secondary sps", "error", r.err)
+			lastErr = r.err
+		case <-hedgeCh:
+			hedgeCh = nil
+			log.CtxDebugw(ctx, "primary sp responds slowly, send hedged requests to secondary sps")
+			startFallback()
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	return nil, nil, lastErr
+}
+
+// reconstructSecondaryPiece decodes the segment by the ec pieces of the other secondary sps,
+// and encodes the segment to get the ec piece of the task.
+func (e *ExecuteModular) reconstructSecondaryPiece(ctx context.Context, task coretask.RecoveryPieceTask) ([]byte, []string, error) {
+	var (
+		params       = task.GetStorageParams().VersionedParams
+		dataShards   = int(params.GetRedundantDataChunkNum())
+		parityShards = int(params.GetRedundantParityChunkNum())
+		ecIdx        = int(task.GetEcIdx())
+	)
+	secondaryEndpoints, err := e.getObjectSecondaryEndpoints(ctx, task.GetObjectInfo())
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := make([]recoverySource, 0, len(secondaryEndpoints))
+	for idx, endpoint := range secondaryEndpoints {
+		if idx == ecIdx || endpoint == "" {
+			continue
+		}
+		sources = append(sources, recoverySource{ecIdx: idx, endpoint: endpoint})
+	}
+	pieceSize := e.baseApp.PieceOp().ECPieceSize(task.GetObjectInfo().GetPayloadSize(),
+		task.GetSegmentIdx(), params.GetMaxSegmentSize(), uint32(dataShards))
+	pieces, used, err := fetchRecoveryPieces(ctx, sources, dataShards, dataShards+parityShards,
+		e.recoveryHedgeDelay, checkRecoveryPieceSize(e.recoveryFetcher(task), pieceSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	segmentSize := e.baseApp.PieceOp().SegmentPieceSize(task.GetObjectInfo().GetPayloadSize(),
+		task.GetSegmentIdx(), params.GetMaxSegmentSize())
+	segmentData, err := redundancy.DecodeRawSegment(pieces, segmentSize, dataShards, parityShards)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to decode segment by secondary pieces", "error", err)
+		return nil, nil, ErrRecoveryDecode
+	}
+	ecData, err := redundancy.EncodeRawSegment(segmentData, dataShards, parityShards)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to encode recovered segment", "error", err)
+		return nil, nil, ErrRecoveryDecode
+	}
+	return ecData[ecIdx], used, nil
+}
+
+// recoverPrimarySegment decodes the segment of primary sp by the ec pieces of any data shards
+// secondary sps.
+func (e *ExecuteModular) recoverPrimarySegment(ctx context.Context, task coretask.RecoveryPieceTask) ([]byte, []string, error) {
+	var (
+		params       = task.GetStorageParams().VersionedParams
+		dataShards   = int(params.GetRedundantDataChunkNum())
+		parityShards = int(params.GetRedundantParityChunkNum())
+	)
+	secondaryEndpoints, err := e.getObjectSecondaryEndpoints(ctx, task.GetObjectInfo())
+	if err != nil {
+		return nil, nil, err
+	}
+	sources := make([]recoverySource, 0, len(secondaryEndpoints))
+	for idx, endpoint := range secondaryEndpoints {
+		if endpoint == "" {
+			continue
+		}
+		sources = append(sources, recoverySource{ecIdx: idx, endpoint: endpoint})
+	}
+	pieceSize := e.baseApp.PieceOp().ECPieceSize(task.GetObjectInfo().GetPayloadSize(),
+		task.GetSegmentIdx(), params.GetMaxSegmentSize(), uint32(dataShards))
+	pieces, used, err := fetchRecoveryPieces(ctx, sources, dataShards, dataShards+parityShards,
+		e.recoveryHedgeDelay, checkRecoveryPieceSize(e.recoveryFetcher(task), pieceSize))
+	if err != nil {
+		log.CtxErrorw(ctx, "get piece from secondary not enough", "error", err)
+		return nil, nil, err
+	}
+	segmentSize := e.baseApp.PieceOp().SegmentPieceSize(task.GetObjectInfo().GetPayloadSize(),
+		task.GetSegmentIdx(), params.GetMaxSegmentSize())
+	segmentData, err := redundancy.DecodeRawSegment(pieces, segmentSize, dataShards, parityShards)
+	if err != nil {
+		log.CtxErrorw(ctx, "EC decode error when recovery", "objectName:", task.GetObjectInfo().ObjectName,
+			"segIndex:", task.GetSegmentIdx(), "error", err)
+		return nil, nil, ErrRecoveryDecode
+	}
+	metrics.RecoverPieceSourceCounter.WithLabelValues(recoverySourceSecondary).Inc()
+	return segmentData, used, nil
+}
+
+// reportRecoverySources logs and counts the sp endpoints that served the finished recovery task,
+// the operator can tell which sps the recovered pieces come from without the task info.
+func reportRecoverySources(ctx context.Context, task coretask.RecoveryPieceTask) {
+	for _, endpoint := range task.GetRecoverSources() {
+		metrics.RecoverPieceEndpointCounter.WithLabelValues(endpoint).Inc()
+	}
+	log.CtxInfow(ctx, "recovered piece sources", "object_id", task.GetObjectInfo().Id.Uint64(),
+		"segment_idx", task.GetSegmentIdx(), "ec_idx", task.GetEcIdx(), "sources", task.GetRecoverSources())
+}
+
+func (e *ExecuteModular) recoveryFetcher(task coretask.RecoveryPieceTask) recoveryFetcher {
+	return func(ctx context.Context, endpoint string) ([]byte, error) {
+		return e.doRecoveryPiece(ctx, task, endpoint)
+	}
+}
+
+// checkRecoveryPieceSize drops the fetched piece whose length is not the ec piece size of the
+// segment, the fetch fails and the next source is tried, so the piece is never decoded.
+func checkRecoveryPieceSize(fetch recoveryFetcher, pieceSize int64) recoveryFetcher {
+	return func(ctx context.Context, endpoint string) ([]byte, error) {
+		data, err := fetch(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != pieceSize {
+			log.CtxErrorw(ctx, "drop the recovery piece with mismatched length", "endpoint", endpoint,
+				"expect", pieceSize, "actual", len(data))
+			return nil, ErrRecoveryPieceLength
+		}
+		return data, nil
+	}
+}
+
+// fetchRecoveryPieces fetches the ec pieces from the sources until need pieces are got, it
+// starts with need requests, and sends the request to the next source when a request fails.
+// If no request finishes within the hedge delay, a hedged request is sent to the next source.
+// It returns the pieces indexed by ec index and the endpoints that the pieces are got from.
+// The hedge delay falls back to the default if it is not positive.
+func fetchRecoveryPieces(ctx context.Context, sources []recoverySource, need int, total int,
+	hedgeDelay time.Duration, fetch recoveryFetcher) ([][]byte, []string, error) {
+	type result struct {
+		source recoverySource
+		data   []byte
+		err    error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if hedgeDelay <= 0 {
+		hedgeDelay = time.Duration(DefaultRecoveryHedgeDelay) * time.Second
+	}
+
+	var (
+		pieces   = make([][]byte, total)
+		used     = make([]string, 0, need)
+		results  = make(chan *result, len(sources))
+		next     int
+		inflight int
+	)
+	launch := func() bool {
+		if next >= len(sources) {
+			return false
+		}
+		source := sources[next]
+		next++
+		inflight++
+		go func() {
+			data, err := fetch(ctx, source.endpoint)
+			results <- &result{source: source, data: data, err: err}
+		}()
+		return true
+	}
+	for i := 0; i < need; i++ {
+		launch()
+	}
+	hedge := time.NewTicker(hedgeDelay)
+	defer hedge.Stop()
+	for len(used) < need {
+		if inflight == 0 {
+			return nil, nil, ErrRecoveryPieceNotEnough
+		}
+		select {
+		case r := <-results:
+			inflight--
+			if r.err != nil || len(r.data) == 0 {
+				launch()
+				continue
+			}
+			pieces[r.source.ecIdx] = r.data
+			used = append(used, r.source.endpoint)
+		case <-hedge.C:
+			if launch() {
+				log.CtxDebugw(ctx, "send hedged recovery request", "endpoint", sources[next-1].endpoint)
+			}
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	return pieces, used, nil
+}
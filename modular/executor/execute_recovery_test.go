@@ -0,0 +1,104 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestFetchRecoveryPieces(t *testing.T) {
+	sources := []recoverySource{
+		{ecIdx: 0, endpoint: "failed"},
+		{ecIdx: 1, endpoint: "slow"},
+		{ecIdx: 2, endpoint: "fast"},
+		{ecIdx: 3, endpoint: "fast"},
+	}
+	fetch := func(ctx context.Context, endpoint string) ([]byte, error) {
+		switch endpoint {
+		case "failed":
+			return nil, errors.New("mock error")
+		case "slow":
+			select {
+			case <-time.After(time.Minute):
+				return []byte(endpoint), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		return []byte(endpoint), nil
+	}
+
+	// the failed source is replaced by the next source, and the slow source is hedged
+	pieces, used, err := fetchRecoveryPieces(context.Background(), sources, 2, 4, 10*time.Millisecond, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fast", "fast"}, used)
+	require.Nil(t, pieces[0])
+	require.Nil(t, pieces[1])
+	require.NotNil(t, pieces[2])
+	require.NotNil(t, pieces[3])
+
+	// not enough sources succeed
+	_, _, err = fetchRecoveryPieces(context.Background(), sources[:1], 1, 4, 10*time.Millisecond, fetch)
+	require.Equal(t, ErrRecoveryPieceNotEnough, err)
+
+	// the non-positive hedge delay falls back to the default instead of panicking
+	pieces, used, err = fetchRecoveryPieces(context.Background(), sources[2:], 2, 4, -time.Second, fetch)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fast", "fast"}, used)
+	require.NotNil(t, pieces[2])
+	require.NotNil(t, pieces[3])
+}
+
+func TestCheckRecoveryPieceSize(t *testing.T) {
+	sources := []recoverySource{
+		{ecIdx: 0, endpoint: "short"},
+		{ecIdx: 1, endpoint: "long"},
+		{ecIdx: 2, endpoint: "good"},
+		{ecIdx: 3, endpoint: "good"},
+	}
+	fetch := func(ctx context.Context, endpoint string) ([]byte, error) {
+		switch endpoint {
+		case "short":
+			return make([]byte, 3), nil
+		case "long":
+			return make([]byte, 5), nil
+		}
+		return make([]byte, 4), nil
+	}
+
+	// the pieces of mismatched length are dropped, and the next sources are tried
+	pieces, used, err := fetchRecoveryPieces(context.Background(), sources, 2, 4, time.Minute,
+		checkRecoveryPieceSize(fetch, 4))
+	require.NoError(t, err)
+	require.Equal(t, []string{"good", "good"}, used)
+	require.Nil(t, pieces[0])
+	require.Nil(t, pieces[1])
+	require.Len(t, pieces[2], 4)
+	require.Len(t, pieces[3], 4)
+
+	// not enough pieces of the expected length
+	_, _, err = fetchRecoveryPieces(context.Background(), sources[:3], 2, 4, time.Minute,
+		checkRecoveryPieceSize(fetch, 4))
+	require.Equal(t, ErrRecoveryPieceNotEnough, err)
+}
+
+func TestReportRecoverySources(t *testing.T) {
+	task := &gfsptask.GfSpRecoverPieceTask{}
+	task.InitRecoverPieceTask(&storagetypes.ObjectInfo{Id: sdkmath.NewUint(1)}, &storagetypes.Params{}, 0, 0, 1, 0, 0, 0)
+	task.SetRecoverSources([]string{"https://sp1", "https://sp2"})
+	reportRecoverySources(context.Background(), task)
+	reportRecoverySources(context.Background(), task)
+	// each endpoint is counted once per finished task
+	for _, endpoint := range []string{"https://sp1", "https://sp2"} {
+		require.Equal(t, float64(2), testutil.ToFloat64(metrics.RecoverPieceEndpointCounter.WithLabelValues(endpoint)))
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-common/go/redundancy"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
@@ -48,28 +49,37 @@ func (e *ExecuteModular) HandleReplicatePieceTask(ctx context.Context, task core
 	rAppTask.InitApprovalReplicatePieceTask(task.GetObjectInfo(), task.GetStorageParams(),
 		e.baseApp.TaskPriority(rAppTask), e.baseApp.OperatorAddress())
 	askReplicateApprovalTime := time.Now()
-	e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginP2P, task.Key().String())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginP2P, task.Key().String())
 	approvals, err = e.AskReplicatePieceApproval(ctx, rAppTask, int(low),
 		int(high), e.askReplicateApprovalTimeout)
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_ask_p2p_approval_time").Observe(time.Since(askReplicateApprovalTime).Seconds())
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_task_p2p_end_time").Observe(time.Since(startReplicateTime).Seconds())
+	if pipelineTask, ok := task.(*gfsptask.GfSpReplicatePieceTask); ok && len(pipelineTask.GetSecondaryApprovals()) > 0 {
+		approvals = mergePipelineApprovals(approvals, pipelineTask.GetSecondaryApprovals())
+		if err != nil && len(approvals) >= int(low) {
+			log.CtxWarnw(ctx, "failed to get approvals, continue with pipeline approvals", "error", err)
+			err = nil
+		}
+	}
 	if err != nil {
-		e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndP2P, task.Key().String()+":"+err.Error())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndP2P, task.Key().String()+":"+err.Error())
 		log.CtxErrorw(ctx, "failed get approvals", "error", err)
+		e.clearReplicateProgress(ctx, task)
 		return
 	}
-	e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndP2P, task.Key().String())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndP2P, task.Key().String())
 	replicatePieceTotalTime := time.Now()
-	e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginReplicateAllPiece, task.Key().String())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginReplicateAllPiece, task.Key().String())
 	err = e.handleReplicatePiece(ctx, task, approvals)
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_object_time").Observe(time.Since(replicatePieceTotalTime).Seconds())
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_task_replicate_object_end_time").Observe(time.Since(startReplicateTime).Seconds())
 	if err != nil {
-		e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateAllPiece, task.Key().String()+":"+err.Error())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateAllPiece, task.Key().String()+":"+err.Error())
 		log.CtxErrorw(ctx, "failed to replicate piece", "error", err)
+		e.clearReplicateProgress(ctx, task)
 		return
 	}
-	e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateAllPiece, task.Key().String())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateAllPiece, task.Key().String())
 	log.CtxDebugw(ctx, "succeed to replicate all pieces")
 	// combine seal object
 	sealMsg := &storagetypes.MsgSealObject{
@@ -89,6 +99,17 @@ func (e *ExecuteModular) HandleReplicatePieceTask(ctx context.Context, task core
 	log.CtxDebugw(ctx, "finish combine seal object", "error", sealErr)
 }
 
+// clearReplicateProgress deletes the replicated pieces of the object if the task fails for
+// the last time, the manager does not retry it and the progress is never restored.
+func (e *ExecuteModular) clearReplicateProgress(ctx context.Context, task coretask.ReplicatePieceTask) {
+	if !task.ExceedRetry() {
+		return
+	}
+	if err := e.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceProgress(task.GetObjectInfo().Id.Uint64()); err != nil {
+		log.CtxWarnw(ctx, "failed to delete replicate piece progress", "error", err)
+	}
+}
+
 func (e *ExecuteModular) AskReplicatePieceApproval(ctx context.Context, task coretask.ApprovalReplicatePieceTask,
 	low, high int, timeout int64) (
 	[]*gfsptask.GfSpReplicatePieceApprovalTask, error) {
@@ -110,7 +131,7 @@ func (e *ExecuteModular) AskReplicatePieceApproval(ctx context.Context, task cor
 	}
 	spDBTime := time.Now()
 	for _, approval := range approvals {
-		spInfo, err = e.baseApp.GfSpDB().GetSpByAddress(
+		spInfo, err = e.baseApp.GfSpDBWithContext(ctx).GetSpByAddress(
 			approval.GetApprovedSpOperatorAddress(),
 			spdb.OperatorAddressType)
 		if err != nil {
@@ -126,136 +147,286 @@ func (e *ExecuteModular) AskReplicatePieceApproval(ctx context.Context, task cor
 		log.CtxErrorw(ctx, "failed to get sufficient sp info from db")
 		return nil, ErrGfSpDB
 	}
+	approvals = e.rankSecondaryApprovals(ctx, task, approvals)
+	if len(approvals) < low {
+		log.CtxErrorw(ctx, "failed to get sufficient eligible sp approvals", "eligible", len(approvals))
+		return nil, ErrInsufficientApproval
+	}
 	return approvals, nil
 }
 
+// rankSecondaryApprovals ranks the approvals by secondary scorer, the higher ranked
+// approvals are picked up as secondary sps first, and the rest are backups.
+func (e *ExecuteModular) rankSecondaryApprovals(ctx context.Context, task coretask.ApprovalReplicatePieceTask,
+	approvals []*gfsptask.GfSpReplicatePieceApprovalTask) []*gfsptask.GfSpReplicatePieceApprovalTask {
+	if e.secondaryScorer == nil {
+		return approvals
+	}
+	addresses := make([]string, 0, len(approvals))
+	for _, approval := range approvals {
+		addresses = append(addresses, approval.GetApprovedSpOperatorAddress())
+	}
+	stats, err := e.baseApp.GfSpDBWithContext(ctx).GetSecondarySpStats(addresses)
+	if err != nil {
+		// the replicate history is only a hint, rank by the advertised status
+		log.CtxWarnw(ctx, "failed to get secondary sp stats", "error", err)
+	}
+	candidates := make([]*SecondaryCandidate, 0, len(approvals))
+	for _, approval := range approvals {
+		candidates = append(candidates, &SecondaryCandidate{
+			Approval: approval,
+			Stats:    stats[approval.GetApprovedSpOperatorAddress()],
+		})
+	}
+	candidates = rankSecondaryCandidates(e.secondaryScorer, candidates, task.GetObjectInfo(), task.GetStorageParams())
+	ranked := make([]*gfsptask.GfSpReplicatePieceApprovalTask, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, candidate.Approval)
+	}
+	log.CtxDebugw(ctx, "succeed to rank secondary sp approvals", "approvals", len(approvals),
+		"eligible", len(ranked))
+	return ranked
+}
+
+// mergePipelineApprovals appends the approvals used by pipelined replication during
+// uploading to the asked approvals, so that the replicate index keeps its secondary sp
+// and reuses the replicated pieces. The asked approval is preferred for the same sp.
+func mergePipelineApprovals(approvals, pipelineApprovals []*gfsptask.GfSpReplicatePieceApprovalTask) []*gfsptask.GfSpReplicatePieceApprovalTask {
+	asked := make(map[string]bool, len(approvals))
+	for _, approval := range approvals {
+		asked[approval.GetApprovedSpOperatorAddress()] = true
+	}
+	for _, approval := range pipelineApprovals {
+		if approval == nil || asked[approval.GetApprovedSpOperatorAddress()] {
+			continue
+		}
+		asked[approval.GetApprovedSpOperatorAddress()] = true
+		approvals = append(approvals, approval)
+	}
+	return approvals
+}
+
 func (e *ExecuteModular) handleReplicatePiece(ctx context.Context, rTask coretask.ReplicatePieceTask,
 	backUpApprovals []*gfsptask.GfSpReplicatePieceApprovalTask) (err error) {
 	var (
-		wg       sync.WaitGroup
-		pieceKey string
+		objectID = rTask.GetObjectInfo().Id.Uint64()
 		segCount = e.baseApp.PieceOp().SegmentPieceCount(
 			rTask.GetObjectInfo().GetPayloadSize(),
 			rTask.GetStorageParams().VersionedParams.GetMaxSegmentSize())
 		replCount = rTask.GetStorageParams().VersionedParams.GetRedundantDataChunkNum() +
 			rTask.GetStorageParams().VersionedParams.GetRedundantParityChunkNum()
-		record              = make([]bool, replCount)
 		secondaryAddresses  = make([]string, replCount)
 		secondarySignatures = make([][]byte, replCount)
 		approvals           = make([]coretask.ApprovalReplicatePieceTask, replCount)
-		finish              bool
+		progress            = newReplicateProgress(int(replCount), segCount)
 	)
-	resetApprovals := func() (bool, error) {
-		doneAll := true
-		for rIdx, done := range record {
-			if !done {
-				doneAll = false
-				if len(backUpApprovals) == 0 {
-					return finish, ErrExhaustedApproval
-				}
-				approvals[rIdx] = backUpApprovals[0]
-				backUpApprovals = backUpApprovals[1:]
+	backUpApprovals = e.restoreReplicateProgress(ctx, rTask, progress, approvals, backUpApprovals)
+	for {
+		// pick up the secondary sp for the unfinished replicate index without secondary sp
+		finish := true
+		for rIdx := range approvals {
+			if progress.isDone(rIdx) {
+				continue
+			}
+			finish = false
+			if approvals[rIdx] != nil {
+				continue
 			}
+			if len(backUpApprovals) == 0 {
+				log.CtxErrorw(ctx, "failed to pick up sp", "error", ErrExhaustedApproval)
+				return ErrExhaustedApproval
+			}
+			approvals[rIdx] = backUpApprovals[0]
+			backUpApprovals = backUpApprovals[1:]
 		}
-		return doneAll, nil
-	}
-	doReplicateECPiece := func(pieceIdx uint32, data [][]byte) {
-		for rIdx, done := range record {
-			if !done {
-				wg.Add(1)
-				go e.doReplicatePiece(ctx, &wg, rTask, approvals[rIdx],
-					uint32(rIdx), pieceIdx, data[rIdx])
+		if finish {
+			rTask.SetSecondaryAddresses(secondaryAddresses)
+			rTask.SetSecondarySignatures(secondarySignatures)
+			if err = e.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceProgress(objectID); err != nil {
+				log.CtxWarnw(ctx, "failed to delete replicate piece progress", "error", err)
 			}
+			log.CtxDebugw(ctx, "success to replicate all pieces")
+			return nil
 		}
-		wg.Wait()
-	}
-	doReplicateSegmentPiece := func(pieceIdx uint32, data []byte) {
-		for rIdx, done := range record {
-			if !done {
-				wg.Add(1)
-				go e.doReplicatePiece(ctx, &wg, rTask, approvals[rIdx],
-					uint32(rIdx), pieceIdx, data)
+		progress.resetCosts()
+		pieceTime := time.Now()
+		// only the missing pieces are resent to the same secondary sp
+		for retry := int64(0); retry <= e.maxReplicatePieceRetry && progress.hasMissing(); retry++ {
+			if err = e.replicateMissingPieces(ctx, rTask, approvals, progress, segCount); err != nil {
+				return err
 			}
 		}
-		wg.Wait()
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_all_piece_time").Observe(time.Since(pieceTime).Seconds())
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_all_piece_end_time").Observe(time.Since(pieceTime).Seconds())
+		doneTime := time.Now()
+		for rIdx := range approvals {
+			if progress.isDone(rIdx) {
+				continue
+			}
+			var (
+				signature []byte
+				innerErr  error = ErrReplicateUnfinished
+			)
+			if !progress.missing(rIdx) {
+				_, signature, innerErr = e.doneReplicatePiece(ctx, rTask, approvals[rIdx], uint32(rIdx))
+			}
+			if statsErr := e.baseApp.GfSpDBWithContext(ctx).UpdateSecondarySpStats(approvals[rIdx].GetApprovedSpOperatorAddress(),
+				innerErr == nil, progress.avgCost(rIdx)); statsErr != nil {
+				log.CtxWarnw(ctx, "failed to update secondary sp stats", "replicate_idx", rIdx, "error", statsErr)
+			}
+			if innerErr == nil {
+				secondaryAddresses[rIdx] = approvals[rIdx].GetApprovedSpOperatorAddress()
+				secondarySignatures[rIdx] = signature
+				progress.setDone(rIdx)
+				metrics.ReplicateSucceedCounter.WithLabelValues(e.Name()).Inc()
+				continue
+			}
+			metrics.ReplicateFailedCounter.WithLabelValues(e.Name()).Inc()
+			// switch to a backup secondary sp, only the pieces of this replicate index are resent
+			log.CtxWarnw(ctx, "failed to replicate to secondary sp, switch to backup",
+				"replicate_idx", rIdx, "sp", approvals[rIdx].GetApprovedSpOperatorAddress(), "error", innerErr)
+			approvals[rIdx] = nil
+			progress.reset(rIdx)
+			if err = e.baseApp.GfSpDBWithContext(ctx).DeleteReplicatePieceProgress(objectID, uint32(rIdx)); err != nil {
+				log.CtxWarnw(ctx, "failed to delete replicate piece progress", "replicate_idx", rIdx, "error", err)
+			}
+		}
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_replicate_time").Observe(time.Since(doneTime).Seconds())
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_replicate_piece_end_time").Observe(time.Since(pieceTime).Seconds())
 	}
-	doneReplicate := func() {
-		for rIdx, done := range record {
-			if !done {
-				_, signature, innerErr := e.doneReplicatePiece(ctx, rTask, approvals[rIdx], uint32(rIdx))
-				if innerErr == nil {
-					secondaryAddresses[rIdx] = approvals[rIdx].GetApprovedSpOperatorAddress()
-					secondarySignatures[rIdx] = signature
-					record[rIdx] = true
-					metrics.ReplicateSucceedCounter.WithLabelValues(e.Name()).Inc()
-				} else {
-					metrics.ReplicateFailedCounter.WithLabelValues(e.Name()).Inc()
-				}
+}
+
+// restoreReplicateProgress restores the replicated pieces from db, the replicate index
+// keeps its secondary sp if the sp approves again, otherwise the replicated pieces of the
+// replicate index are discarded. It returns the remaining backup approvals.
+func (e *ExecuteModular) restoreReplicateProgress(ctx context.Context, rTask coretask.ReplicatePieceTask,
+	progress *replicateProgress, approvals []coretask.ApprovalReplicatePieceTask,
+	backUpApprovals []*gfsptask.GfSpReplicatePieceApprovalTask) []*gfsptask.GfSpReplicatePieceApprovalTask {
+	objectID := rTask.GetObjectInfo().Id.Uint64()
+	records, err := e.baseApp.GfSpDBWithContext(ctx).GetReplicatePieceProgress(objectID)
+	if err != nil {
+		log.CtxWarnw(ctx, "failed to get replicate piece progress, replicate from scratch", "error", err)
+		return backUpApprovals
+	}
+	if len(records) == 0 {
+		return backUpApprovals
+	}
+	idxSp := make(map[uint32]string)
+	for _, record := range records {
+		if int(record.ReplicateIdx) < len(approvals) {
+			idxSp[record.ReplicateIdx] = record.SpOperatorAddress
+		}
+	}
+	for rIdx, sp := range idxSp {
+		found := -1
+		for i, approval := range backUpApprovals {
+			if approval.GetApprovedSpOperatorAddress() == sp {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			if err = e.baseApp.GfSpDBWithContext(ctx).DeleteReplicatePieceProgress(objectID, rIdx); err != nil {
+				log.CtxWarnw(ctx, "failed to delete replicate piece progress", "replicate_idx", rIdx, "error", err)
 			}
+			continue
 		}
+		approvals[rIdx] = backUpApprovals[found]
+		remaining := make([]*gfsptask.GfSpReplicatePieceApprovalTask, 0, len(backUpApprovals)-1)
+		remaining = append(remaining, backUpApprovals[:found]...)
+		backUpApprovals = append(remaining, backUpApprovals[found+1:]...)
 	}
-	for {
-		finish, err = resetApprovals()
+	restored := 0
+	for _, record := range records {
+		if int(record.ReplicateIdx) >= len(approvals) || approvals[record.ReplicateIdx] == nil ||
+			approvals[record.ReplicateIdx].GetApprovedSpOperatorAddress() != record.SpOperatorAddress {
+			continue
+		}
+		progress.setPiece(int(record.ReplicateIdx), record.PieceIdx)
+		restored++
+	}
+	log.CtxDebugw(ctx, "succeed to restore replicate piece progress", "records", len(records), "restored", restored)
+	return backUpApprovals
+}
+
+// replicateMissingPieces replicates the missing pieces to the secondary sps, the segment
+// is skipped without reading and encoding if no replicate index misses its pieces.
+func (e *ExecuteModular) replicateMissingPieces(ctx context.Context, rTask coretask.ReplicatePieceTask,
+	approvals []coretask.ApprovalReplicatePieceTask, progress *replicateProgress, segCount uint32) error {
+	var wg sync.WaitGroup
+	doReplicatePiece := func(rIdx int, pIdx uint32, data []byte) {
+		defer wg.Done()
+		startTime := time.Now()
+		err := e.doReplicatePiece(ctx, rTask, approvals[rIdx], uint32(rIdx), pIdx, data)
+		progress.addCost(rIdx, time.Since(startTime))
 		if err != nil {
-			log.CtxErrorw(ctx, "failed to pick up sp", "error", err)
-			return err
+			return
 		}
-		if finish {
-			rTask.SetSecondaryAddresses(secondaryAddresses)
-			rTask.SetSecondarySignatures(secondarySignatures)
-			log.CtxDebugw(ctx, "success to replicate all pieces")
-			return nil
+		progress.setPiece(rIdx, pIdx)
+		if err = e.baseApp.GfSpDBWithContext(ctx).SetReplicatePieceProgress(&spdb.ReplicatePieceProgress{
+			ObjectID:          rTask.GetObjectInfo().Id.Uint64(),
+			ReplicateIdx:      uint32(rIdx),
+			PieceIdx:          pIdx,
+			SpOperatorAddress: approvals[rIdx].GetApprovedSpOperatorAddress(),
+		}); err != nil {
+			log.CtxWarnw(ctx, "failed to set replicate piece progress", "replicate_idx", rIdx,
+				"piece_idx", pIdx, "error", err)
 		}
+	}
+	for pIdx := uint32(0); pIdx < segCount; pIdx++ {
+		rIdxes := progress.missingReplicateIdx(pIdx)
+		if len(rIdxes) == 0 {
+			continue
+		}
+		pieceKey := e.baseApp.PieceOp().SegmentPieceKey(rTask.GetObjectInfo().Id.Uint64(), pIdx)
 		pieceTime := time.Now()
-		for pIdx := uint32(0); pIdx < segCount; pIdx++ {
-			pieceKey = e.baseApp.PieceOp().SegmentPieceKey(rTask.GetObjectInfo().Id.Uint64(), pIdx)
-			pieceTime := time.Now()
-			segData, err := e.baseApp.PieceStore().GetPiece(ctx, pieceKey, 0, -1)
-			metrics.PerfUploadTimeHistogram.WithLabelValues("background_get_piece_time").Observe(time.Since(pieceTime).Seconds())
-			metrics.PerfUploadTimeHistogram.WithLabelValues("background_get_piece_end_time").Observe(time.Since(time.Unix(rTask.GetCreateTime(), 0)).Seconds())
+		segData, err := e.baseApp.PieceStore().GetPiece(ctx, pieceKey, 0, -1)
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_get_piece_time").Observe(time.Since(pieceTime).Seconds())
+		metrics.PerfUploadTimeHistogram.WithLabelValues("background_get_piece_end_time").Observe(time.Since(time.Unix(rTask.GetCreateTime(), 0)).Seconds())
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to get segment data form piece store", "error", err)
+			rTask.SetError(err)
+			return err
+		}
+		var ecData [][]byte
+		if rTask.GetObjectInfo().GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE {
+			ecTime := time.Now()
+			ecData, err = redundancy.EncodeRawSegment(segData,
+				int(rTask.GetStorageParams().VersionedParams.GetRedundantDataChunkNum()),
+				int(rTask.GetStorageParams().VersionedParams.GetRedundantParityChunkNum()))
+			metrics.PerfUploadTimeHistogram.WithLabelValues("background_ec_time").Observe(time.Since(ecTime).Seconds())
+			metrics.PerfUploadTimeHistogram.WithLabelValues("background_ec_end_time").Observe(time.Since(time.Unix(rTask.GetCreateTime(), 0)).Seconds())
 			if err != nil {
-				log.CtxErrorw(ctx, "failed to get segment data form piece store", "error", err)
+				log.CtxErrorw(ctx, "failed to ec encode data", "error", err)
 				rTask.SetError(err)
 				return err
 			}
-			if rTask.GetObjectInfo().GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE {
-				ecTime := time.Now()
-				ecData, err := redundancy.EncodeRawSegment(segData,
-					int(rTask.GetStorageParams().VersionedParams.GetRedundantDataChunkNum()),
-					int(rTask.GetStorageParams().VersionedParams.GetRedundantParityChunkNum()))
-				metrics.PerfUploadTimeHistogram.WithLabelValues("background_ec_time").Observe(time.Since(ecTime).Seconds())
-				metrics.PerfUploadTimeHistogram.WithLabelValues("background_ec_end_time").Observe(time.Since(time.Unix(rTask.GetCreateTime(), 0)).Seconds())
-				if err != nil {
-					log.CtxErrorw(ctx, "failed to ec encode data", "error", err)
-					rTask.SetError(err)
-					return err
-				}
-				doReplicateECPiece(pIdx, ecData)
-			} else {
-				doReplicateSegmentPiece(pIdx, segData)
+		}
+		for _, rIdx := range rIdxes {
+			data := segData
+			if ecData != nil {
+				data = ecData[rIdx]
 			}
+			wg.Add(1)
+			go doReplicatePiece(rIdx, pIdx, data)
 		}
-		metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_all_piece_time").Observe(time.Since(pieceTime).Seconds())
-		metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_all_piece_end_time").Observe(time.Since(pieceTime).Seconds())
-		doneTime := time.Now()
-		doneReplicate()
-		metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_replicate_time").Observe(time.Since(doneTime).Seconds())
-		metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_replicate_piece_end_time").Observe(time.Since(pieceTime).Seconds())
+		wg.Wait()
 	}
+	return nil
 }
 
-func (e *ExecuteModular) doReplicatePiece(ctx context.Context, waitGroup *sync.WaitGroup, rTask coretask.ReplicatePieceTask,
+func (e *ExecuteModular) doReplicatePiece(ctx context.Context, rTask coretask.ReplicatePieceTask,
 	approval coretask.ApprovalReplicatePieceTask, replicateIdx uint32, pieceIdx uint32, data []byte) (err error) {
 	var signature []byte
 	metrics.ReplicatePieceSizeCounter.WithLabelValues(e.Name()).Add(float64(len(data)))
 	startTime := time.Now()
 	defer func() {
 		metrics.ReplicatePieceTimeHistogram.WithLabelValues(e.Name()).Observe(time.Since(startTime).Seconds())
-		waitGroup.Done()
 	}()
 	receive := &gfsptask.GfSpReceivePieceTask{}
 	receive.InitReceivePieceTask(rTask.GetObjectInfo(), rTask.GetStorageParams(),
 		e.baseApp.TaskPriority(rTask), replicateIdx, int32(pieceIdx), int64(len(data)))
+	receive.SetTraceParent(rTask.GetTraceParent())
 	receive.SetPieceChecksum(hash.GenerateChecksum(data))
 	ctx = log.WithValue(ctx, log.CtxKeyTask, receive.Key().String())
 	signTime := time.Now()
@@ -268,19 +439,25 @@ func (e *ExecuteModular) doReplicatePiece(ctx context.Context, waitGroup *sync.W
 		return
 	}
 	receive.SetSignature(signature)
+	if err = e.baseApp.ResourceManager().BandwidthLimiter().WaitN(ctx, approval.GetApprovedSpOperatorAddress(),
+		len(data), corercmgr.ReserveTaskPriorityLow); err != nil {
+		log.CtxErrorw(ctx, "failed to wait replicate bandwidth", "replicate_idx", replicateIdx,
+			"piece_idx", pieceIdx, "error", err)
+		return
+	}
 	replicateOnePieceTime := time.Now()
-	e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginReplicateOnePiece, receive.Info())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginReplicateOnePiece, receive.Info())
 	err = e.baseApp.GfSpClient().ReplicatePieceToSecondary(ctx,
 		approval.GetApprovedSpEndpoint(), approval, receive, data)
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_one_piece_time").Observe(time.Since(replicateOnePieceTime).Seconds())
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_replicate_one_piece_end_time").Observe(time.Since(startTime).Seconds())
 	if err != nil {
-		e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateOnePiece, receive.Info()+":"+err.Error())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateOnePiece, receive.Info()+":"+err.Error())
 		log.CtxErrorw(ctx, "failed to replicate piece", "replicate_idx", replicateIdx,
 			"piece_idx", pieceIdx, "error", err)
 		return
 	}
-	e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateOnePiece, receive.Info())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndReplicateOnePiece, receive.Info())
 	log.CtxDebugw(ctx, "success to replicate piece", "replicate_idx", replicateIdx,
 		"piece_idx", pieceIdx)
 	return
@@ -297,6 +474,7 @@ func (e *ExecuteModular) doneReplicatePiece(ctx context.Context, rTask coretask.
 	receive := &gfsptask.GfSpReceivePieceTask{}
 	receive.InitReceivePieceTask(rTask.GetObjectInfo(), rTask.GetStorageParams(),
 		e.baseApp.TaskPriority(rTask), replicateIdx, -1, 0)
+	receive.SetTraceParent(rTask.GetTraceParent())
 	signTime := time.Now()
 	taskSignature, err = e.baseApp.GfSpClient().SignReceiveTask(ctx, receive)
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_sign_receive_time").Observe(time.Since(signTime).Seconds())
@@ -308,19 +486,19 @@ func (e *ExecuteModular) doneReplicatePiece(ctx context.Context, rTask coretask.
 	}
 	receive.SetSignature(taskSignature)
 	doneReplicateTime := time.Now()
-	e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginDoneReplicatePiece, receive.Info())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginDoneReplicatePiece, receive.Info())
 	integrity, signature, err = e.baseApp.GfSpClient().DoneReplicatePieceToSecondary(ctx,
 		approval.GetApprovedSpEndpoint(), approval, receive)
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_receive_http_time").Observe(time.Since(doneReplicateTime).Seconds())
 	metrics.PerfUploadTimeHistogram.WithLabelValues("background_done_receive_http_end_time").Observe(time.Since(signTime).Seconds())
 	if err != nil {
-		e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndDoneReplicatePiece, receive.Info()+":"+err.Error())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndDoneReplicatePiece, receive.Info()+":"+err.Error())
 		log.CtxErrorw(ctx, "failed to done replicate piece",
 			"endpoint", approval.GetApprovedSpEndpoint(),
 			"replicate_idx", replicateIdx, "error", err)
 		return nil, nil, err
 	}
-	e.baseApp.GfSpDB().InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndDoneReplicatePiece, receive.Info())
+	e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(rTask.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndDoneReplicatePiece, receive.Info())
 	if int(replicateIdx+1) >= len(rTask.GetObjectInfo().GetChecksums()) {
 		log.CtxErrorw(ctx, "failed to done replicate piece, replicate idx out of bounds",
 			"replicate_idx", replicateIdx,
@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+)
+
+func TestMergePipelineApprovals(t *testing.T) {
+	newApproval := func(sp string, expiredHeight uint64) *gfsptask.GfSpReplicatePieceApprovalTask {
+		return &gfsptask.GfSpReplicatePieceApprovalTask{ApprovedSpOperatorAddress: sp, ExpiredHeight: expiredHeight}
+	}
+	asked := []*gfsptask.GfSpReplicatePieceApprovalTask{newApproval("sp1", 10), newApproval("sp2", 10)}
+	pipeline := []*gfsptask.GfSpReplicatePieceApprovalTask{newApproval("sp2", 5), newApproval("sp3", 5), nil}
+
+	merged := mergePipelineApprovals(asked, pipeline)
+	require.Len(t, merged, 3)
+	require.Equal(t, "sp1", merged[0].GetApprovedSpOperatorAddress())
+	// the asked approval is preferred for the same sp
+	require.Equal(t, uint64(10), merged[1].GetExpiredHeight())
+	require.Equal(t, "sp3", merged[2].GetApprovedSpOperatorAddress())
+
+	merged = mergePipelineApprovals(nil, pipeline)
+	require.Len(t, merged, 2)
+}
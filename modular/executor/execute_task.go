@@ -8,11 +8,9 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"sync/atomic"
 	"time"
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
-	"github.com/bnb-chain/greenfield-common/go/redundancy"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
@@ -32,6 +30,8 @@ var (
 	ErrInvalidIntegrity        = gfsperrors.Register(module.ExecuteModularName, http.StatusNotAcceptable, 40005, "secondary integrity hash verification failed")
 	ErrSecondaryMismatch       = gfsperrors.Register(module.ExecuteModularName, http.StatusNotAcceptable, 40006, "secondary sp mismatch")
 	ErrReplicateIdsOutOfBounds = gfsperrors.Register(module.ExecuteModularName, http.StatusNotAcceptable, 40007, "replicate idx out of bounds")
+	ErrReplicateUnfinished     = gfsperrors.Register(module.ExecuteModularName, http.StatusInternalServerError, 40008, "pieces are not fully replicated to secondary sp")
+	ErrExecutorDraining        = gfsperrors.Register(module.ExecuteModularName, http.StatusServiceUnavailable, 40009, "executor is draining")
 	ErrGfSpDB                  = gfsperrors.Register(module.ExecuteModularName, http.StatusInternalServerError, 45201, "server slipped away, try again later")
 	ErrRecoveryRedundancyType  = gfsperrors.Register(module.ExecuteModularName, http.StatusInternalServerError, 45202, "recovery only support EC redundancy type")
 	ErrRecoveryPieceNotEnough  = gfsperrors.Register(module.ExecuteModularName, http.StatusInternalServerError, 45203, "fail to get enough piece data to recovery")
@@ -63,15 +63,15 @@ func (e *ExecuteModular) HandleSealObjectTask(ctx context.Context, task coretask
 func (e *ExecuteModular) sealObject(ctx context.Context, task coretask.ObjectTask, sealMsg *storagetypes.MsgSealObject) error {
 	var err error
 	for retry := int64(0); retry <= task.GetMaxRetry(); retry++ {
-		e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginSealTx, task.Key().String())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorBeginSealTx, task.Key().String())
 		err = e.baseApp.GfSpClient().SealObject(ctx, sealMsg)
 		if err != nil {
 			log.CtxErrorw(ctx, "failed to seal object", "retry", retry,
 				"max_retry", task.GetMaxRetry(), "error", err)
-			e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndSealTx, task.Key().String()+":"+err.Error())
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndSealTx, task.Key().String()+":"+err.Error())
 			time.Sleep(time.Duration(e.listenSealRetryTimeout) * time.Second)
 		} else {
-			e.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndSealTx, task.Key().String())
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), spdb.ExecutorEndSealTx, task.Key().String())
 			break
 		}
 	}
@@ -87,11 +87,11 @@ func (e *ExecuteModular) sealObject(ctx context.Context, task coretask.ObjectTas
 func (e *ExecuteModular) listenSealObject(ctx context.Context, object *storagetypes.ObjectInfo) error {
 	var err error
 	for retry := 0; retry < e.maxListenSealRetry; retry++ {
-		e.baseApp.GfSpDB().InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorBeginConfirmSeal, "")
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorBeginConfirmSeal, "")
 		sealed, innerErr := e.baseApp.Consensus().ListenObjectSeal(ctx,
 			object.Id.Uint64(), e.listenSealTimeoutHeight)
 		if innerErr != nil {
-			e.baseApp.GfSpDB().InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "err:"+innerErr.Error())
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "err:"+innerErr.Error())
 			log.CtxErrorw(ctx, "failed to listen object seal", "retry", retry,
 				"max_retry", e.maxListenSealRetry, "error", err)
 			time.Sleep(time.Duration(e.listenSealRetryTimeout) * time.Second)
@@ -99,13 +99,13 @@ func (e *ExecuteModular) listenSealObject(ctx context.Context, object *storagety
 			continue
 		}
 		if !sealed {
-			e.baseApp.GfSpDB().InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "unsealed")
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "unsealed")
 			log.CtxErrorw(ctx, "failed to seal object on chain", "retry", retry,
 				"max_retry", e.maxListenSealRetry, "error", err)
 			err = ErrUnsealed
 			continue
 		}
-		e.baseApp.GfSpDB().InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "sealed")
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(object.Id.Uint64(), spdb.ExecutorEndConfirmSeal, "sealed")
 		err = nil
 		break
 	}
@@ -156,7 +156,7 @@ func (e *ExecuteModular) HandleReceivePieceTask(ctx context.Context, task coreta
 			"current", e.baseApp.OperatorAddress())
 		task.SetError(ErrSecondaryMismatch)
 		// TODO:: gc zombie task will gc the zombie piece, it is a conservative plan
-		err = e.baseApp.GfSpDB().DeleteObjectIntegrity(task.GetObjectInfo().Id.Uint64())
+		err = e.baseApp.GfSpDBWithContext(ctx).DeleteObjectIntegrity(task.GetObjectInfo().Id.Uint64())
 		if err != nil {
 			log.CtxErrorw(ctx, "failed to delete integrity")
 		}
@@ -272,8 +272,10 @@ func (e *ExecuteModular) HandleGCObjectTask(ctx context.Context, task coretask.G
 			}
 		}
 		// ignore this delete api error, TODO: refine gc workflow by enrich metadata index.
-		deleteErr := e.baseApp.GfSpDB().DeleteObjectIntegrity(objectInfo.Id.Uint64())
+		deleteErr := e.baseApp.GfSpDBWithContext(ctx).DeleteObjectIntegrity(objectInfo.Id.Uint64())
 		log.CtxDebugw(ctx, "delete the object integrity meta", "object_info", objectInfo, "error", deleteErr)
+		deleteErr = e.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceProgress(objectInfo.Id.Uint64())
+		log.CtxDebugw(ctx, "delete the replicate piece progress", "object_info", objectInfo, "error", deleteErr)
 		task.SetCurrentBlockNumber(currentGCBlockID)
 		task.SetLastDeletedObjectId(currentGCObjectID)
 		metrics.GCObjectCounter.WithLabelValues(e.Name()).Inc()
@@ -299,16 +301,11 @@ func (e *ExecuteModular) HandleGCMetaTask(ctx context.Context, task coretask.GCM
 // recovery the original data, and write the recovered data to piece store
 func (e *ExecuteModular) HandleRecoverPieceTask(ctx context.Context, task coretask.RecoveryPieceTask) {
 	var (
-		dataShards         = task.GetStorageParams().VersionedParams.GetRedundantDataChunkNum()
-		maxSegmentSize     = task.GetStorageParams().VersionedParams.GetMaxSegmentSize()
-		parityShards       = task.GetStorageParams().VersionedParams.GetRedundantParityChunkNum()
-		minRecoveryPieces  = dataShards
-		ecPieceCount       = dataShards + parityShards
-		doneTaskNum        = uint32(0)
-		recoveryKey        string
-		recoveryMinEcIndex = -1
-		err                error
-		finishRecovery     = false
+		recoveryKey    string
+		recoveryData   []byte
+		sources        []string
+		err            error
+		finishRecovery = false
 	)
 	defer func() {
 		if err != nil {
@@ -318,7 +315,9 @@ func (e *ExecuteModular) HandleRecoverPieceTask(ctx context.Context, task coreta
 			log.CtxErrorw(ctx, "recovery task failed", "error", task.Error())
 		}
 		if finishRecovery {
+			task.SetRecoverSources(sources)
 			task.SetRecoverDone()
+			reportRecoverySources(ctx, task)
 		}
 	}()
 
@@ -332,127 +331,54 @@ func (e *ExecuteModular) HandleRecoverPieceTask(ctx context.Context, task coreta
 		return
 	}
 
+	ecPieceCount := task.GetStorageParams().VersionedParams.GetRedundantDataChunkNum() +
+		task.GetStorageParams().VersionedParams.GetRedundantParityChunkNum()
 	ecIndex := task.GetEcIdx()
-	maxRedundancyIndex := int32(ecPieceCount) - 1
-
-	if ecIndex < int32(recoveryMinEcIndex) || ecIndex > maxRedundancyIndex {
+	if ecIndex < primaryRecoveryEcIdx || ecIndex > int32(ecPieceCount)-1 {
 		err = ErrRecoveryPieceIndex
 		return
 	}
 
-	// recovery secondary SP
-	if ecIndex >= 0 {
-		objectId := task.GetObjectInfo().Id.Uint64()
-		segmentIdx := task.GetSegmentIdx()
-		primarySPEndpoint, err := e.getObjectPrimarySPEndpoint(ctx, task.GetObjectInfo().BucketName)
-		if err != nil {
-			task.SetError(err)
-			return
-		}
-		// TODO if get piece from primary SP fail ,send request to other secondary SP
-		pieceData, err := e.doRecoveryPiece(ctx, task, primarySPEndpoint)
-		if err != nil {
-			task.SetError(err)
-			return
-		}
-		// compare integrity hash
-		if err = e.checkRecoveryCheckSum(ctx, task, hash.GenerateChecksum(pieceData)); err != nil {
-			return
-		}
+	// the signature is the same for all the sources, sign once before fetching concurrently
+	signature, err := e.baseApp.GfSpClient().SignRecoveryTask(ctx, task)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to sign recovery task", "object", task.GetObjectInfo().GetObjectName(), "error", err)
+		return
+	}
+	task.SetSignature(signature)
 
-		recoveryKey = e.baseApp.PieceOp().ECPieceKey(objectId, segmentIdx, uint32(ecIndex))
-		// write the recovery segment key to keystore
-		err = e.baseApp.PieceStore().PutPiece(ctx, recoveryKey, pieceData)
+	objectID := task.GetObjectInfo().Id.Uint64()
+	if ecIndex >= 0 {
+		// recovery secondary SP
+		recoveryData, sources, err = e.recoverSecondaryPiece(ctx, task)
 		if err != nil {
-			log.CtxErrorw(ctx, "EC recover data write piece fail", "pieceKey:", recoveryKey, "error", err)
-			task.SetError(err)
 			return
 		}
-		log.CtxDebugw(ctx, "secondary SP recovery successfully", "pieceKey:", recoveryKey)
-		finishRecovery = true
-		return
+		recoveryKey = e.baseApp.PieceOp().ECPieceKey(objectID, task.GetSegmentIdx(), uint32(ecIndex))
 	} else {
 		// recovery primary SP
-		// TODO(leo) Encapsulate the following code into a function recoveryPrimarySP()
 		log.CtxDebugw(ctx, "begin to recovery primary SP object")
-		secondaryEndpoints, err := e.getObjectSecondaryEndpoints(ctx, task.GetObjectInfo())
-		if err != nil {
-			return
-		}
-
-		var recoveryDataSources = make([][]byte, len(secondaryEndpoints))
-		doneCh := make(chan bool, len(secondaryEndpoints))
-		quitCh := make(chan bool)
-
-		totalTaskNum := int32(ecPieceCount)
-		downLoadPieceSize := 0
-		segmentSize := e.baseApp.PieceOp().SegmentPieceSize(task.GetObjectInfo().PayloadSize, task.GetSegmentIdx(), maxSegmentSize)
-		for ecIdx := 0; ecIdx < int(ecPieceCount); ecIdx++ {
-			recoveryDataSources[ecIdx] = nil
-			go func(secondaryIndex int) {
-				pieceData, err := e.doRecoveryPiece(ctx, task, secondaryEndpoints[secondaryIndex])
-				if err == nil {
-					recoveryDataSources[secondaryIndex] = pieceData
-					log.Debugf("get one piece from ", "piece length:%d ", len(pieceData), "secondary sp:", secondaryEndpoints[secondaryIndex])
-					doneCh <- true
-				}
-				// finish all the task, send signal to quitCh
-				if atomic.AddInt32(&totalTaskNum, -1) == 0 {
-					downLoadPieceSize = len(pieceData)
-					quitCh <- true
-				}
-			}(ecIdx)
-		}
-
-	loop:
-		for {
-			select {
-			case <-doneCh:
-				doneTaskNum++
-				// it is enough to recovery data with minRecoveryPieces EC data, no need to wait
-				if doneTaskNum >= minRecoveryPieces {
-					break loop
-				}
-			case <-quitCh: // all the task finish
-				if doneTaskNum < minRecoveryPieces { // finish task num not enough
-					log.CtxErrorw(ctx, "get piece from secondary not enough", "get secondary piece num:", doneTaskNum, "error", ErrRecoveryPieceNotEnough)
-					task.SetError(ErrRecoveryPieceNotEnough)
-					return
-				}
-				ecTotalSize := int64(uint32(downLoadPieceSize) * dataShards)
-				if ecTotalSize < segmentSize || ecTotalSize > segmentSize+int64(dataShards) {
-					log.CtxErrorw(ctx, "get secondary piece data length error")
-					task.SetError(ErrRecoveryPieceLength)
-					return
-				}
-			}
-		}
-		recoverySegData, err := redundancy.DecodeRawSegment(recoveryDataSources, segmentSize, int(dataShards), int(parityShards))
-		if err != nil {
-			log.CtxErrorw(ctx, "EC decode error when recovery", "objectName:", task.GetObjectInfo().ObjectName, "segIndex:", task.GetSegmentIdx(), "error", err)
-			task.SetError(ErrRecoveryDecode)
-			return
-		}
-		// compare integrity hash
-		if err = e.checkRecoveryCheckSum(ctx, task, hash.GenerateChecksum(recoverySegData)); err != nil {
-			return
-		}
-
-		recoveryKey = e.baseApp.PieceOp().SegmentPieceKey(task.GetObjectInfo().Id.Uint64(), task.GetSegmentIdx())
-		// write the recovery segment key to keystore
-		err = e.baseApp.PieceStore().PutPiece(ctx, recoveryKey, recoverySegData)
+		recoveryData, sources, err = e.recoverPrimarySegment(ctx, task)
 		if err != nil {
-			log.CtxErrorw(ctx, "EC decode data write piece fail", "pieceKey:", recoveryKey, "error", err)
 			return
 		}
-		finishRecovery = true
+		recoveryKey = e.baseApp.PieceOp().SegmentPieceKey(objectID, task.GetSegmentIdx())
 	}
-
-	log.CtxDebugw(ctx, "primary SP recovery successfully", "pieceKey:", recoveryKey)
+	// compare integrity hash
+	if err = e.checkRecoveryCheckSum(ctx, task, hash.GenerateChecksum(recoveryData)); err != nil {
+		return
+	}
+	// write the recovery piece key to keystore
+	if err = e.baseApp.PieceStore().PutPiece(ctx, recoveryKey, recoveryData); err != nil {
+		log.CtxErrorw(ctx, "recover data write piece fail", "pieceKey:", recoveryKey, "error", err)
+		return
+	}
+	finishRecovery = true
+	log.CtxDebugw(ctx, "succeed to recovery piece", "pieceKey:", recoveryKey)
 }
 
 func (e *ExecuteModular) checkRecoveryCheckSum(ctx context.Context, task coretask.RecoveryPieceTask, recoveryChecksum []byte) error {
-	integrityMeta, err := e.baseApp.GfSpDB().GetObjectIntegrity(task.GetObjectInfo().Id.Uint64())
+	integrityMeta, err := e.baseApp.GfSpDBWithContext(ctx).GetObjectIntegrity(task.GetObjectInfo().Id.Uint64())
 	if err != nil {
 		log.CtxErrorw(ctx, "search integrity hash in db error when recovery", "objectName:", task.GetObjectInfo().ObjectName, "error", err)
 		task.SetError(ErrGfSpDB)
@@ -468,22 +394,14 @@ func (e *ExecuteModular) checkRecoveryCheckSum(ctx context.Context, task coretas
 	return nil
 }
 
+// doRecoveryPiece gets the piece from the sp, the task should be signed before.
 func (e *ExecuteModular) doRecoveryPiece(ctx context.Context, rTask coretask.RecoveryPieceTask, endpoint string) (data []byte, err error) {
-	var (
-		signature []byte
-		pieceData []byte
-	)
+	var pieceData []byte
 	startTime := time.Now()
 	defer func() {
 		metrics.RecoverPieceTimeHistogram.WithLabelValues(e.Name()).Observe(time.Since(startTime).Seconds())
 	}()
 
-	signature, err = e.baseApp.GfSpClient().SignRecoveryTask(ctx, rTask)
-	if err != nil {
-		log.CtxErrorw(ctx, "failed to sign recovery task", "object", rTask.GetObjectInfo().GetObjectName(), "error", err)
-		return
-	}
-	rTask.SetSignature(signature)
 	// recovery primary sp segment pr secondary piece
 	respBody, err := e.baseApp.GfSpClient().GetPieceFromECChunks(ctx, endpoint, rTask)
 	if err != nil {
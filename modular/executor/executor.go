@@ -4,21 +4,28 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 )
 
 var _ module.TaskExecutor = &ExecuteModular{}
+var _ corelifecycle.Drainable = &ExecuteModular{}
 
 type ExecuteModular struct {
 	baseApp *gfspapp.GfSpBaseApp
@@ -31,6 +38,9 @@ type ExecuteModular struct {
 
 	askReplicateApprovalTimeout  int64
 	askReplicateApprovalExFactor float64
+	secondaryScorer              SecondaryScorer
+	maxReplicatePieceRetry       int64
+	recoveryHedgeDelay           time.Duration
 
 	listenSealTimeoutHeight int
 	listenSealRetryTimeout  int
@@ -44,6 +54,13 @@ type ExecuteModular struct {
 	doingGCZombiePieceTaskCnt  int64
 	doingGCGCMetaTaskCnt       int64
 	doingRecoveryPieceTaskCnt  int64
+
+	// runningTasks records the cancel functions of the executing tasks, the tasks
+	// unfinished at the drain deadline are canceled and reported back to manager.
+	runningTasks map[coretask.TKey]context.CancelFunc
+	runningMux   sync.Mutex
+	// drainStarted is set when the executor starts draining, no task is asked after it
+	drainStarted int32
 }
 
 func (e *ExecuteModular) Name() string {
@@ -67,6 +84,10 @@ func (e *ExecuteModular) eventLoop(ctx context.Context) {
 				select {
 				case <-ctx.Done():
 				default:
+					if e.draining() {
+						time.Sleep(DefaultSleepInterval * time.Millisecond)
+						continue
+					}
 					err := e.AskTask(ctx)
 					if err != nil {
 						rand.New(rand.NewSource(time.Now().Unix()))
@@ -102,6 +123,10 @@ func (e *ExecuteModular) omitError(err error) bool {
 func (e *ExecuteModular) AskTask(ctx context.Context) error {
 	atomic.AddInt64(&e.executingNum, 1)
 	defer atomic.AddInt64(&e.executingNum, -1)
+	// the draining is checked after counting, so the asking is either skipped or waited by draining
+	if e.draining() {
+		return ErrExecutorDraining
+	}
 
 	limit, err := e.scope.RemainingResource()
 	if err != nil {
@@ -142,29 +167,36 @@ func (e *ExecuteModular) AskTask(ctx context.Context) error {
 	metrics.MaxTaskNumberGauge.WithLabelValues("max_task_num").Set(float64(atomic.LoadInt64(&e.executingNum)))
 	defer e.ReleaseResource(ctx, span)
 	defer e.ReportTask(ctx, askTask)
-	ctx = log.WithValue(ctx, log.CtxKeyTask, askTask.Key().String())
+	ctx, cancel := context.WithCancel(log.WithValue(ctx, log.CtxKeyTask, askTask.Key().String()))
+	// the task is traced as a part of the request that creates it
+	ctx, traceSpan := tracing.StartSpan(tracing.ContextWithTraceParent(ctx, askTask.GetTraceParent()),
+		"executor."+coretask.TaskTypeName(askTask.Type()),
+		trace.WithAttributes(attribute.String("task_key", askTask.Key().String())))
+	defer func() { tracing.EndSpan(traceSpan, askTask.Error()) }()
+	e.trackTask(askTask.Key(), cancel)
+	defer e.untrackTask(askTask.Key())
 	switch t := askTask.(type) {
 	case *gfsptask.GfSpReplicatePieceTask:
 		metrics.ExecutorReplicatePieceTaskCounter.WithLabelValues(e.Name()).Inc()
 		atomic.AddInt64(&e.doingReplicatePieceTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingReplicatePieceTaskCnt, -1)
 		metrics.PerfUploadTimeHistogram.WithLabelValues("background_schedule_replicate_time").Observe(time.Since(time.Unix(t.GetCreateTime(), 0)).Seconds())
-		e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorBeginTask, t.Key().String())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorBeginTask, t.Key().String())
 		e.HandleReplicatePieceTask(ctx, t)
 		if t.Error() != nil {
-			e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String()+":"+t.Error().Error())
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String()+":"+t.Error().Error())
 		}
-		e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String())
 	case *gfsptask.GfSpSealObjectTask:
 		metrics.ExecutorSealObjectTaskCounter.WithLabelValues(e.Name()).Inc()
 		atomic.AddInt64(&e.doingSpSealObjectTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingSpSealObjectTaskCnt, -1)
-		e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorBeginTask, t.Key().String())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorBeginTask, t.Key().String())
 		e.HandleSealObjectTask(ctx, t)
 		if t.Error() != nil {
-			e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String()+":"+t.Error().Error())
+			e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String()+":"+t.Error().Error())
 		}
-		e.baseApp.GfSpDB().InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String())
+		e.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(t.GetObjectInfo().Id.Uint64(), corespdb.ExecutorEndTask, t.Key().String())
 	case *gfsptask.GfSpReceivePieceTask:
 		metrics.ExecutorReceiveTaskCounter.WithLabelValues(e.Name()).Inc()
 		atomic.AddInt64(&e.doingReceivePieceTaskCnt, 1)
@@ -205,6 +237,70 @@ func (e *ExecuteModular) ReportTask(
 	return err
 }
 
+func (e *ExecuteModular) trackTask(key coretask.TKey, cancel context.CancelFunc) {
+	e.runningMux.Lock()
+	defer e.runningMux.Unlock()
+	e.runningTasks[key] = cancel
+}
+
+func (e *ExecuteModular) untrackTask(key coretask.TKey) {
+	e.runningMux.Lock()
+	defer e.runningMux.Unlock()
+	if cancel, ok := e.runningTasks[key]; ok {
+		cancel()
+		delete(e.runningTasks, key)
+	}
+}
+
+func (e *ExecuteModular) runningTaskNum() int {
+	e.runningMux.Lock()
+	defer e.runningMux.Unlock()
+	return len(e.runningTasks)
+}
+
+// draining returns an indicator whether the executor stops asking tasks.
+func (e *ExecuteModular) draining() bool {
+	return atomic.LoadInt32(&e.drainStarted) == 1 || e.baseApp.Draining()
+}
+
+// busy returns an indicator whether any task is being asked or executed.
+func (e *ExecuteModular) busy() bool {
+	return atomic.LoadInt64(&e.executingNum) > 0 || e.runningTaskNum() > 0
+}
+
+// Drain stops asking new tasks and waits for the asking and executing tasks to finish.
+// The tasks unfinished at the deadline are canceled, and the failed results are reported
+// to manager, which reassigns the tasks to the other executors by retrying.
+func (e *ExecuteModular) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&e.drainStarted, 1)
+	ticker := time.NewTicker(DefaultDrainCheckInterval)
+	defer ticker.Stop()
+	for e.busy() {
+		select {
+		case <-ctx.Done():
+			e.runningMux.Lock()
+			log.CtxWarnw(ctx, "drain deadline exceeded, cancel the executing tasks", "tasks", len(e.runningTasks))
+			for _, cancel := range e.runningTasks {
+				cancel()
+			}
+			e.runningMux.Unlock()
+			// wait for the canceled tasks to be reported
+			timer := time.NewTimer(DefaultDrainReportTimeout)
+			defer timer.Stop()
+			for e.busy() {
+				select {
+				case <-timer.C:
+					return ctx.Err()
+				case <-ticker.C:
+				}
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
 func (e *ExecuteModular) Stop(ctx context.Context) error {
 	e.scope.Release()
 	return nil
@@ -1,9 +1,13 @@
 package executor
 
 import (
+	"context"
+	"time"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 )
 
 const (
@@ -31,16 +35,43 @@ const (
 	// DefaultExecutorMaxListenSealRetry defines the default max retry number for listening
 	// object.
 	DefaultExecutorMaxListenSealRetry int = 3
+	// DefaultExecutorMaxReplicatePieceRetry defines the default max retry number for resending
+	// the missing pieces to the same secondary sp before switching to a backup secondary sp.
+	DefaultExecutorMaxReplicatePieceRetry int64 = 2
 	// DefaultStatisticsOutputInterval defines the default interval for output statistics info,
 	// it is used to log and debug.
 	DefaultStatisticsOutputInterval int = 60
 	// DefaultSleepInterval defines the sleep interval when failed to ask task
 	// it is millisecond level
 	DefaultSleepInterval = 100
+	// DefaultDrainCheckInterval defines the interval of checking the executing tasks
+	// when draining.
+	DefaultDrainCheckInterval = 500 * time.Millisecond
+	// DefaultDrainReportTimeout defines the timeout of waiting for the canceled tasks
+	// to be reported after the drain deadline.
+	DefaultDrainReportTimeout = 5 * time.Second
+	// DefaultSecondaryScoreStrategy defines the default strategy to rank the secondary
+	// sp approvals.
+	DefaultSecondaryScoreStrategy = WeightedScoreStrategy
+	// DefaultSecondaryCapacityWeight defines the default weight of the free capacity ratio
+	// of secondary sp.
+	DefaultSecondaryCapacityWeight float64 = 1.0
+	// DefaultSecondaryLoadWeight defines the default weight of the idle task ratio of
+	// secondary sp.
+	DefaultSecondaryLoadWeight float64 = 1.0
+	// DefaultSecondarySuccessWeight defines the default weight of the historical replicate
+	// success rate of secondary sp.
+	DefaultSecondarySuccessWeight float64 = 2.0
+	// DefaultSecondaryLatencyWeight defines the default weight of the historical replicate
+	// latency of secondary sp.
+	DefaultSecondaryLatencyWeight float64 = 1.0
+	// DefaultRecoveryHedgeDelay defines the default seconds to wait for the slow recovery
+	// source before sending the hedged request to the other sources.
+	DefaultRecoveryHedgeDelay int64 = 3
 )
 
 func NewExecuteModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
-	executor := &ExecuteModular{baseApp: app}
+	executor := &ExecuteModular{baseApp: app, runningTasks: make(map[coretask.TKey]context.CancelFunc)}
 	if err := DefaultExecutorOptions(executor, cfg); err != nil {
 		return nil, err
 	}
@@ -77,6 +108,34 @@ func DefaultExecutorOptions(executor *ExecuteModular, cfg *gfspconfig.GfSpConfig
 		cfg.Executor.MaxListenSealRetry = DefaultExecutorMaxListenSealRetry
 	}
 	executor.maxListenSealRetry = cfg.Executor.MaxListenSealRetry
+	if cfg.Executor.MaxReplicatePieceRetry == 0 {
+		cfg.Executor.MaxReplicatePieceRetry = DefaultExecutorMaxReplicatePieceRetry
+	}
+	executor.maxReplicatePieceRetry = cfg.Executor.MaxReplicatePieceRetry
+	if cfg.Executor.RecoveryHedgeDelay <= 0 {
+		cfg.Executor.RecoveryHedgeDelay = DefaultRecoveryHedgeDelay
+	}
+	executor.recoveryHedgeDelay = time.Duration(cfg.Executor.RecoveryHedgeDelay) * time.Second
 	executor.statisticsOutputInterval = DefaultStatisticsOutputInterval
+	if cfg.Executor.SecondaryScoreStrategy == "" {
+		cfg.Executor.SecondaryScoreStrategy = DefaultSecondaryScoreStrategy
+	}
+	if cfg.Executor.SecondaryCapacityWeight == 0 && cfg.Executor.SecondaryLoadWeight == 0 &&
+		cfg.Executor.SecondarySuccessWeight == 0 && cfg.Executor.SecondaryLatencyWeight == 0 {
+		cfg.Executor.SecondaryCapacityWeight = DefaultSecondaryCapacityWeight
+		cfg.Executor.SecondaryLoadWeight = DefaultSecondaryLoadWeight
+		cfg.Executor.SecondarySuccessWeight = DefaultSecondarySuccessWeight
+		cfg.Executor.SecondaryLatencyWeight = DefaultSecondaryLatencyWeight
+	}
+	scorer, err := NewSecondaryScorer(cfg.Executor.SecondaryScoreStrategy, SecondaryScoreWeights{
+		Capacity: cfg.Executor.SecondaryCapacityWeight,
+		Load:     cfg.Executor.SecondaryLoadWeight,
+		Success:  cfg.Executor.SecondarySuccessWeight,
+		Latency:  cfg.Executor.SecondaryLatencyWeight,
+	})
+	if err != nil {
+		return err
+	}
+	executor.secondaryScorer = scorer
 	return nil
 }
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+)
+
+func TestExecuteModular_Drain(t *testing.T) {
+	e := &ExecuteModular{runningTasks: make(map[coretask.TKey]context.CancelFunc)}
+	require.NoError(t, e.Drain(context.Background()))
+
+	// the task finishes before the deadline
+	_, cancel := context.WithCancel(context.Background())
+	e.trackTask("finished", cancel)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		e.untrackTask("finished")
+	}()
+	require.NoError(t, e.Drain(context.Background()))
+
+	// the task is canceled at the deadline and reported
+	taskCtx, cancel := context.WithCancel(context.Background())
+	e.trackTask("stuck", cancel)
+	go func() {
+		<-taskCtx.Done()
+		e.untrackTask("stuck")
+	}()
+	ctx, drainCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer drainCancel()
+	require.Equal(t, context.DeadlineExceeded, e.Drain(ctx))
+	require.Equal(t, 0, e.runningTaskNum())
+
+	// the task being asked is waited, and no task is asked after draining
+	atomic.AddInt64(&e.executingNum, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		atomic.AddInt64(&e.executingNum, -1)
+	}()
+	require.NoError(t, e.Drain(context.Background()))
+	require.Equal(t, ErrExecutorDraining, e.AskTask(context.Background()))
+}
+
+func TestDefaultExecutorOptions_RecoveryHedgeDelay(t *testing.T) {
+	cases := []struct {
+		name  string
+		delay int64
+		want  time.Duration
+	}{
+		{name: "unset", delay: 0, want: time.Duration(DefaultRecoveryHedgeDelay) * time.Second},
+		{name: "negative", delay: -1, want: time.Duration(DefaultRecoveryHedgeDelay) * time.Second},
+		{name: "positive", delay: 5, want: 5 * time.Second},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &gfspconfig.GfSpConfig{}
+			cfg.Executor.RecoveryHedgeDelay = c.delay
+			e := &ExecuteModular{}
+			require.NoError(t, DefaultExecutorOptions(e, cfg))
+			require.Equal(t, c.want, e.recoveryHedgeDelay)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"sync"
+	"time"
+)
+
+// replicateProgress tracks the pieces that have been replicated to the secondary sp of
+// every replicate index, only the missing pieces are resent in the following rounds.
+type replicateProgress struct {
+	mux    sync.RWMutex
+	pieces [][]bool
+	done   []bool
+	// costs and counts record the time cost and number of the piece replications in
+	// the current round, it is used to compute the average latency of secondary sp.
+	costs  []time.Duration
+	counts []int64
+}
+
+func newReplicateProgress(replCount int, segCount uint32) *replicateProgress {
+	progress := &replicateProgress{
+		pieces: make([][]bool, replCount),
+		done:   make([]bool, replCount),
+		costs:  make([]time.Duration, replCount),
+		counts: make([]int64, replCount),
+	}
+	for rIdx := range progress.pieces {
+		progress.pieces[rIdx] = make([]bool, segCount)
+	}
+	return progress
+}
+
+// setPiece marks the piece of the replicate index has been replicated.
+func (p *replicateProgress) setPiece(rIdx int, pIdx uint32) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if rIdx >= len(p.pieces) || int(pIdx) >= len(p.pieces[rIdx]) {
+		return
+	}
+	p.pieces[rIdx][pIdx] = true
+}
+
+// setDone marks the replicate index has got the secondary signature.
+func (p *replicateProgress) setDone(rIdx int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.done[rIdx] = true
+}
+
+// isDone returns an indicator whether the replicate index has got the secondary signature.
+func (p *replicateProgress) isDone(rIdx int) bool {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	return p.done[rIdx]
+}
+
+// reset discards the replicated pieces of the replicate index, it is called when
+// switching the secondary sp of the replicate index.
+func (p *replicateProgress) reset(rIdx int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	for pIdx := range p.pieces[rIdx] {
+		p.pieces[rIdx][pIdx] = false
+	}
+	p.done[rIdx] = false
+}
+
+// missing returns an indicator whether the replicate index has missing pieces.
+func (p *replicateProgress) missing(rIdx int) bool {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	for _, replicated := range p.pieces[rIdx] {
+		if !replicated {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMissing returns an indicator whether any unfinished replicate index has missing pieces.
+func (p *replicateProgress) hasMissing() bool {
+	for rIdx := range p.pieces {
+		if !p.isDone(rIdx) && p.missing(rIdx) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingReplicateIdx returns the unfinished replicate indexes that miss the piece.
+func (p *replicateProgress) missingReplicateIdx(pIdx uint32) []int {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	var rIdxes []int
+	for rIdx := range p.pieces {
+		if !p.done[rIdx] && !p.pieces[rIdx][pIdx] {
+			rIdxes = append(rIdxes, rIdx)
+		}
+	}
+	return rIdxes
+}
+
+// addCost records the time cost of one piece replication of the replicate index.
+func (p *replicateProgress) addCost(rIdx int, cost time.Duration) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.costs[rIdx] += cost
+	p.counts[rIdx]++
+}
+
+// avgCost returns the average time cost of the piece replications of the replicate
+// index in the current round.
+func (p *replicateProgress) avgCost(rIdx int) time.Duration {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	if p.counts[rIdx] == 0 {
+		return 0
+	}
+	return p.costs[rIdx] / time.Duration(p.counts[rIdx])
+}
+
+// resetCosts clears the time costs at the beginning of a new round.
+func (p *replicateProgress) resetCosts() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	for rIdx := range p.costs {
+		p.costs[rIdx] = 0
+		p.counts[rIdx] = 0
+	}
+}
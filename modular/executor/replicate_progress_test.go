@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicateProgress(t *testing.T) {
+	progress := newReplicateProgress(3, 2)
+	require.True(t, progress.hasMissing())
+	require.Equal(t, []int{0, 1, 2}, progress.missingReplicateIdx(0))
+
+	progress.setPiece(0, 0)
+	progress.setPiece(0, 1)
+	progress.setPiece(1, 0)
+	require.False(t, progress.missing(0))
+	require.True(t, progress.missing(1))
+	require.Equal(t, []int{2}, progress.missingReplicateIdx(0))
+	require.Equal(t, []int{1, 2}, progress.missingReplicateIdx(1))
+
+	// the done replicate index never misses pieces
+	progress.setDone(0)
+	progress.setDone(1)
+	progress.setDone(2)
+	require.False(t, progress.hasMissing())
+	require.Empty(t, progress.missingReplicateIdx(1))
+
+	// switching secondary sp discards the replicated pieces
+	progress.reset(0)
+	require.True(t, progress.missing(0))
+	require.Equal(t, []int{0}, progress.missingReplicateIdx(0))
+
+	progress.addCost(1, 2*time.Second)
+	progress.addCost(1, 4*time.Second)
+	require.Equal(t, 3*time.Second, progress.avgCost(1))
+	progress.resetCosts()
+	require.Equal(t, time.Duration(0), progress.avgCost(1))
+}
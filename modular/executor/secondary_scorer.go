@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspp2p"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	// FirstComeScoreStrategy defines the strategy that picks up the secondary sps
+	// by the order of approvals arriving.
+	FirstComeScoreStrategy = "FirstCome"
+	// WeightedScoreStrategy defines the strategy that picks up the secondary sps by
+	// the weighted score of free capacity, load, success rate and latency.
+	WeightedScoreStrategy = "Weighted"
+
+	// unknownScore defines the score of the dimension that has no information.
+	unknownScore = 0.5
+)
+
+// SecondaryCandidate defines the candidate secondary sp that approves the replicate
+// piece request.
+type SecondaryCandidate struct {
+	Approval *gfsptask.GfSpReplicatePieceApprovalTask
+	// Stats is the replicate history of the secondary sp, it is nil if there is no history.
+	Stats *spdb.SecondarySpStats
+}
+
+// SecondaryScoreWeights defines the weights of the score dimensions.
+type SecondaryScoreWeights struct {
+	Capacity float64
+	Load     float64
+	Success  float64
+	Latency  float64
+}
+
+// SecondaryScorer scores the candidate secondary sps, the higher score the earlier
+// the sp is picked up. The returned scores are in the same order as candidates.
+type SecondaryScorer func(candidates []*SecondaryCandidate) []float64
+
+// NewSecondaryScorer returns the secondary scorer by strategy name.
+func NewSecondaryScorer(strategy string, weights SecondaryScoreWeights) (SecondaryScorer, error) {
+	switch strategy {
+	case FirstComeScoreStrategy:
+		return FirstComeScorer, nil
+	case WeightedScoreStrategy:
+		return NewWeightedScorer(weights), nil
+	default:
+		return nil, fmt.Errorf("unknown secondary score strategy '%s'", strategy)
+	}
+}
+
+// FirstComeScorer scores all candidates equally, keeps the order of approvals arriving.
+func FirstComeScorer(candidates []*SecondaryCandidate) []float64 {
+	return make([]float64, len(candidates))
+}
+
+// NewWeightedScorer returns the scorer that sums the weighted scores of free capacity
+// ratio, idle task ratio, success rate and relative latency, each score is in [0, 1].
+func NewWeightedScorer(weights SecondaryScoreWeights) SecondaryScorer {
+	return func(candidates []*SecondaryCandidate) []float64 {
+		var minLatency int64
+		for _, candidate := range candidates {
+			if candidate.Stats == nil || candidate.Stats.SucceedCount == 0 || candidate.Stats.AvgLatencyMs <= 0 {
+				continue
+			}
+			if minLatency == 0 || candidate.Stats.AvgLatencyMs < minLatency {
+				minLatency = candidate.Stats.AvgLatencyMs
+			}
+		}
+		scores := make([]float64, len(candidates))
+		for i, candidate := range candidates {
+			status := candidate.Approval.GetApprovedSpStatus()
+			capacityScore, loadScore := unknownScore, unknownScore
+			if status.GetTotalCapacity() > 0 {
+				capacityScore = float64(status.GetFreeCapacity()) / float64(status.GetTotalCapacity())
+			}
+			if total := status.GetRunningTasks() + status.GetRemainingTasks(); total > 0 {
+				loadScore = float64(status.GetRemainingTasks()) / float64(total)
+			}
+			successScore, latencyScore := unknownScore, unknownScore
+			if stats := candidate.Stats; stats != nil {
+				// laplace smoothing, avoids the few samples dominate the success rate
+				successScore = float64(stats.SucceedCount+1) / float64(stats.SucceedCount+stats.FailedCount+2)
+				if minLatency > 0 && stats.SucceedCount > 0 && stats.AvgLatencyMs > 0 {
+					latencyScore = float64(minLatency) / float64(stats.AvgLatencyMs)
+				}
+			}
+			scores[i] = weights.Capacity*capacityScore + weights.Load*loadScore +
+				weights.Success*successScore + weights.Latency*latencyScore
+		}
+		return scores
+	}
+}
+
+// eligibleSecondary returns an indicator whether the secondary sp can store the object
+// according to its advertised status, the sp without status is always eligible.
+func eligibleSecondary(status *gfspp2p.GfSpSecondaryStatus, object *storagetypes.ObjectInfo,
+	params *storagetypes.Params) bool {
+	if status == nil {
+		return true
+	}
+	if len(status.GetSupportedRedundancy()) > 0 {
+		supported := false
+		for _, redundancy := range status.GetSupportedRedundancy() {
+			if redundancy == object.GetRedundancyType() {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return false
+		}
+	}
+	if status.GetTotalCapacity() > 0 {
+		need := object.GetPayloadSize()
+		if object.GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE && params != nil &&
+			params.VersionedParams.GetRedundantDataChunkNum() > 0 {
+			need = need / uint64(params.VersionedParams.GetRedundantDataChunkNum())
+		}
+		if status.GetFreeCapacity() < need {
+			return false
+		}
+	}
+	return true
+}
+
+// rankSecondaryCandidates filters out the ineligible candidates and sorts the rest
+// by score in descending order, the candidates with the same score keep the order.
+func rankSecondaryCandidates(scorer SecondaryScorer, candidates []*SecondaryCandidate,
+	object *storagetypes.ObjectInfo, params *storagetypes.Params) []*SecondaryCandidate {
+	eligible := make([]*SecondaryCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if eligibleSecondary(candidate.Approval.GetApprovedSpStatus(), object, params) {
+			eligible = append(eligible, candidate)
+		}
+	}
+	scores := scorer(eligible)
+	indexes := make([]int, len(eligible))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return scores[indexes[i]] > scores[indexes[j]]
+	})
+	ranked := make([]*SecondaryCandidate, len(eligible))
+	for i, idx := range indexes {
+		ranked[i] = eligible[idx]
+	}
+	return ranked
+}
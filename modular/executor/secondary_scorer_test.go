@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspp2p"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func newTestCandidate(sp string, status *gfspp2p.GfSpSecondaryStatus, stats *spdb.SecondarySpStats) *SecondaryCandidate {
+	return &SecondaryCandidate{
+		Approval: &gfsptask.GfSpReplicatePieceApprovalTask{
+			ApprovedSpOperatorAddress: sp,
+			ApprovedSpStatus:          status,
+		},
+		Stats: stats,
+	}
+}
+
+func candidateAddresses(candidates []*SecondaryCandidate) []string {
+	var addresses []string
+	for _, candidate := range candidates {
+		addresses = append(addresses, candidate.Approval.GetApprovedSpOperatorAddress())
+	}
+	return addresses
+}
+
+func TestRankSecondaryCandidates(t *testing.T) {
+	object := &storagetypes.ObjectInfo{PayloadSize: 100, RedundancyType: storagetypes.REDUNDANCY_EC_TYPE}
+	params := &storagetypes.Params{VersionedParams: storagetypes.VersionedParams{RedundantDataChunkNum: 4}}
+	candidates := []*SecondaryCandidate{
+		newTestCandidate("busy", &gfspp2p.GfSpSecondaryStatus{RunningTasks: 9, RemainingTasks: 1}, nil),
+		newTestCandidate("unknown", nil, nil),
+		newTestCandidate("full", &gfspp2p.GfSpSecondaryStatus{TotalCapacity: 1000, FreeCapacity: 10}, nil),
+		newTestCandidate("replica-only", &gfspp2p.GfSpSecondaryStatus{
+			SupportedRedundancy: []storagetypes.RedundancyType{storagetypes.REDUNDANCY_REPLICA_TYPE}}, nil),
+		newTestCandidate("reliable", nil, &spdb.SecondarySpStats{SucceedCount: 100, AvgLatencyMs: 10}),
+		newTestCandidate("slow", nil, &spdb.SecondarySpStats{SucceedCount: 100, AvgLatencyMs: 100}),
+	}
+
+	ranked := rankSecondaryCandidates(FirstComeScorer, candidates, object, params)
+	require.Equal(t, []string{"busy", "unknown", "reliable", "slow"}, candidateAddresses(ranked))
+
+	scorer := NewWeightedScorer(SecondaryScoreWeights{Capacity: 1, Load: 1, Success: 2, Latency: 1})
+	ranked = rankSecondaryCandidates(scorer, candidates, object, params)
+	require.Equal(t, []string{"reliable", "slow", "unknown", "busy"}, candidateAddresses(ranked))
+}
+
+func TestNewSecondaryScorer(t *testing.T) {
+	_, err := NewSecondaryScorer(FirstComeScoreStrategy, SecondaryScoreWeights{})
+	require.NoError(t, err)
+	_, err = NewSecondaryScorer(WeightedScoreStrategy, SecondaryScoreWeights{})
+	require.NoError(t, err)
+	_, err = NewSecondaryScorer("unknown", SecondaryScoreWeights{})
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/modular/downloader"
 	"github.com/bnb-chain/greenfield-storage-provider/modular/executor"
@@ -296,7 +297,8 @@ func (g *GateModular) getChallengeInfoHandler(w http.ResponseWriter, r *http.Req
 
 // replicateHandler handles the replicate piece from primary SP request. The Primary
 // replicates the piece data one by one, and will ask the integrity hash and the
-// signature to seal object on greenfield.
+// signature to seal object on greenfield. The DELETE method discards the received
+// pieces if the primary SP aborts the replication.
 func (g *GateModular) replicateHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		err           error
@@ -390,6 +392,33 @@ func (g *GateModular) replicateHandler(w http.ResponseWriter, r *http.Request) {
 		err = ErrInvalidHeader
 		return
 	}
+	if r.Method == http.MethodDelete {
+		// the discard deletes the pieces, only the primary SP of the object can discard them
+		if err = verifyReceiveTaskObject(&approval, &receiveTask); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "the receive task mismatches the object of the approval",
+				"approval_object_id", approval.GetObjectInfo().Id.String(),
+				"receive_object_id", receiveTask.GetObjectInfo().Id.String())
+			return
+		}
+		var bucketInfo *storagetypes.BucketInfo
+		bucketInfo, err = g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), approval.GetObjectInfo().GetBucketName())
+		if err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+			err = ErrConsensus
+			return
+		}
+		if err = verifyReceiveTaskSignature(bucketInfo.GetPrimarySpAddress(), &receiveTask); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to verify discard receive task signature",
+				"primary_sp", bucketInfo.GetPrimarySpAddress())
+			return
+		}
+		if err = g.baseApp.GfSpClient().DiscardReplicatePiece(reqCtx.Context(), &receiveTask); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to discard receive piece", "error", err)
+			return
+		}
+		log.CtxDebugw(reqCtx.Context(), "succeed to discard replicate piece")
+		return
+	}
 	readDataTime := time.Now()
 	data, err = io.ReadAll(r.Body)
 	metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_read_piece_time").Observe(time.Since(readDataTime).Seconds())
@@ -510,7 +539,13 @@ func (g *GateModular) recoverPrimaryHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	var pieceData []byte
-	if redundancyIdx >= 0 {
+	if redundancyIdx >= 0 && bucketInfo.PrimarySpAddress != g.baseApp.OperatorAddress() {
+		// recovery secondary SP by the ec pieces of the other secondary SPs
+		pieceData, err = g.recoverSecondaryECPiece(reqCtx.Context(), chainObjectInfo, bucketInfo, recoveryTask, params, signatureAddr)
+		if err != nil {
+			return
+		}
+	} else if redundancyIdx >= 0 {
 		// recovery secondary SP
 		pieceData, err = g.recoverECPiece(reqCtx.Context(), chainObjectInfo, bucketInfo, recoveryTask, params, signatureAddr)
 		if err != nil {
@@ -524,6 +559,13 @@ func (g *GateModular) recoverPrimaryHandler(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	// the recovery traffic is shaped by the sender before the piece is sent to the recovering sp
+	if err = g.baseApp.ResourceManager().BandwidthLimiter().WaitN(reqCtx.Context(), signatureAddr.String(),
+		len(pieceData), rcmgr.ReserveTaskPriorityLow); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to wait recovery bandwidth", "sp", signatureAddr.String(), "error", err)
+		err = ErrRecoveryTimeout
+		return
+	}
 	w.Write(pieceData)
 	log.CtxDebugw(reqCtx.Context(), "succeed to get one ec piece data")
 }
@@ -550,7 +592,38 @@ func (g *GateModular) recoverSegmentPiece(ctx context.Context, objectInfo *stora
 		err = ErrRecoverySP
 		return nil, err
 	}
+	return g.getLocalECPiece(ctx, objectInfo, bucketInfo, recoveryTask, params, ECIndex)
+}
+
+// recoverSecondaryECPiece returns the ec piece of the handler secondary SP to the other
+// secondary SP, which reconstructs its ec piece when the primary SP is unavailable.
+func (g *GateModular) recoverSecondaryECPiece(ctx context.Context, objectInfo *storagetypes.ObjectInfo,
+	bucketInfo *storagetypes.BucketInfo, recoveryTask gfsptask.GfSpRecoverPieceTask, params *storagetypes.Params, signatureAddr sdktypes.AccAddress) ([]byte, error) {
+	var (
+		handlerIdx = -1
+		senderIdx  = -1
+	)
+	for idx, spAddr := range objectInfo.SecondarySpAddresses {
+		if spAddr == g.baseApp.OperatorAddress() {
+			handlerIdx = idx
+		}
+		if spAddr == signatureAddr.String() {
+			senderIdx = idx
+		}
+	}
+	// both the handler and the sender should be the secondary SPs of the object, and the
+	// sender can only recover its own ec piece
+	if handlerIdx < 0 || senderIdx < 0 || handlerIdx == senderIdx || int32(senderIdx) != recoveryTask.EcIdx {
+		log.CtxErrorw(ctx, "it is not the right secondary SP to handle secondary SP recovery",
+			"handler_idx", handlerIdx, "sender_idx", senderIdx, "ec_idx", recoveryTask.EcIdx)
+		return nil, ErrRecoverySP
+	}
+	return g.getLocalECPiece(ctx, objectInfo, bucketInfo, recoveryTask, params, handlerIdx)
+}
 
+// getLocalECPiece returns the ec piece of the segment stored by the handler secondary SP.
+func (g *GateModular) getLocalECPiece(ctx context.Context, objectInfo *storagetypes.ObjectInfo,
+	bucketInfo *storagetypes.BucketInfo, recoveryTask gfsptask.GfSpRecoverPieceTask, params *storagetypes.Params, ECIndex int) ([]byte, error) {
 	// init download piece task, get piece data and return the data
 	ECPieceSize := g.baseApp.PieceOp().ECPieceSize(objectInfo.PayloadSize, recoveryTask.GetSegmentIdx(),
 		params.GetMaxSegmentSize(), params.GetRedundantDataChunkNum())
@@ -639,3 +712,26 @@ func (g *GateModular) recoverECPiece(ctx context.Context, objectInfo *storagetyp
 
 	return ecData[redundancyIdx], nil
 }
+
+// verifyReceiveTaskObject checks the receive task is for the object of the replicate piece
+// approval, the object of the approval is signed by the SP itself. It is only checked before
+// discarding the replicated pieces.
+func verifyReceiveTaskObject(approval *gfsptask.GfSpReplicatePieceApprovalTask, receiveTask *gfsptask.GfSpReceivePieceTask) error {
+	if approval.GetObjectInfo() == nil || receiveTask.GetObjectInfo() == nil ||
+		approval.GetObjectInfo().Id.IsNil() || receiveTask.GetObjectInfo().Id.IsNil() ||
+		!approval.GetObjectInfo().Id.Equal(receiveTask.GetObjectInfo().Id) {
+		return ErrMismatchObject
+	}
+	return nil
+}
+
+// verifyReceiveTaskSignature checks the receive task is signed by the primary SP operator.
+func verifyReceiveTaskSignature(primarySpAddress string, receiveTask *gfsptask.GfSpReceivePieceTask) error {
+	// VerifySignature normalizes the recovery id of the signature in place
+	signature := make([]byte, len(receiveTask.GetSignature()))
+	copy(signature, receiveTask.GetSignature())
+	if err := p2pnode.VerifySignature(primarySpAddress, receiveTask.GetSignBytes(), signature); err != nil {
+		return ErrSignature
+	}
+	return nil
+}
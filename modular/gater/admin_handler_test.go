@@ -0,0 +1,71 @@
+package gater
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield/sdk/keys"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestVerifyReceiveTaskObject(t *testing.T) {
+	approval := &gfsptask.GfSpReplicatePieceApprovalTask{ObjectInfo: &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1)}}
+	cases := []struct {
+		name        string
+		receiveTask *gfsptask.GfSpReceivePieceTask
+		wantErr     error
+	}{
+		{"same object", &gfsptask.GfSpReceivePieceTask{ObjectInfo: &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1)}}, nil},
+		{"mismatched object", &gfsptask.GfSpReceivePieceTask{ObjectInfo: &storagetypes.ObjectInfo{Id: sdkmath.NewUint(2)}}, ErrMismatchObject},
+		{"missing object id", &gfsptask.GfSpReceivePieceTask{ObjectInfo: &storagetypes.ObjectInfo{}}, ErrMismatchObject},
+		{"missing object", &gfsptask.GfSpReceivePieceTask{}, ErrMismatchObject},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.wantErr, verifyReceiveTaskObject(approval, c.receiveTask))
+		})
+	}
+}
+
+func TestVerifyReceiveTaskSignature(t *testing.T) {
+	// the private keys come from greenfield/sdk/keys/ unit test
+	primary, err := keys.NewPrivateKeyManager("ab463aca3d2965233da3d1d6108aa521274c5ddc2369ff72970a52a451863fbf")
+	assert.NoError(t, err)
+	other, err := keys.NewPrivateKeyManager("c3b6a5a3f6b4d7cb1e1bf1a4ab4e1ea1b1d4d8c3c9b5b2e6f8f0e2c6d4b8a9e7")
+	assert.NoError(t, err)
+	newTask := func() *gfsptask.GfSpReceivePieceTask {
+		return &gfsptask.GfSpReceivePieceTask{
+			ObjectInfo:    &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1)},
+			StorageParams: &storagetypes.Params{},
+			ReplicateIdx:  1,
+			PieceIdx:      -1,
+		}
+	}
+
+	signed := newTask()
+	sig, err := primary.Sign(signed.GetSignBytes())
+	assert.NoError(t, err)
+	signed.SetSignature(sig)
+	assert.NoError(t, verifyReceiveTaskSignature(primary.GetAddr().String(), signed))
+	// the signature is not modified by the verification
+	assert.NoError(t, verifyReceiveTaskSignature(primary.GetAddr().String(), signed))
+
+	otherSigned := newTask()
+	sig, err = other.Sign(otherSigned.GetSignBytes())
+	assert.NoError(t, err)
+	otherSigned.SetSignature(sig)
+	assert.Equal(t, ErrSignature, verifyReceiveTaskSignature(primary.GetAddr().String(), otherSigned))
+
+	tampered := newTask()
+	sig, err = primary.Sign(tampered.GetSignBytes())
+	assert.NoError(t, err)
+	tampered.SetSignature(sig)
+	tampered.ReplicateIdx = 2
+	assert.Equal(t, ErrSignature, verifyReceiveTaskSignature(primary.GetAddr().String(), tampered))
+
+	unsigned := newTask()
+	assert.Equal(t, ErrSignature, verifyReceiveTaskSignature(primary.GetAddr().String(), unsigned))
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/accounts"
@@ -78,6 +79,7 @@ func (g *GateModular) updateUserPublicKeyHandler(w http.ResponseWriter, r *http.
 		origin        string
 		nonce         string
 		expiryDateStr string
+		keyChange     bool
 	)
 
 	defer func() {
@@ -87,6 +89,15 @@ func (g *GateModular) updateUserPublicKeyHandler(w http.ResponseWriter, r *http.
 			log.CtxErrorw(reqCtx.Context(), "failed to updateUserPublicKey", "req_info", reqCtx.String())
 			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
 		}
+		if keyChange {
+			result := audit.ResultSuccess
+			detail := map[string]string{"domain": domain, "nonce": nonce, "expiry": expiryDateStr, "public_key": userPublicKey}
+			if err != nil {
+				result = audit.ResultFailure
+				detail["error"] = err.Error()
+			}
+			reqCtx.emitAudit(audit.EventAuthKeyChange, result, detail)
+		}
 	}()
 
 	reqCtx, err = NewRequestContext(r, g)
@@ -99,10 +110,13 @@ func (g *GateModular) updateUserPublicKeyHandler(w http.ResponseWriter, r *http.
 			accAddress, err := verifyPersonalSignatureFromHeader(requestSignature[len(personalSignSignaturePrefix):])
 			if err != nil {
 				log.CtxErrorw(reqCtx.Context(), "failed to verify signature", "error", err)
+				reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultFailure,
+					map[string]string{"error": err.Error()})
 				return
 			}
 			account = accAddress.String()
 			reqCtx.account = account
+			reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultSuccess, nil)
 		} else {
 			return
 		}
@@ -113,6 +127,7 @@ func (g *GateModular) updateUserPublicKeyHandler(w http.ResponseWriter, r *http.
 	nonce = reqCtx.request.Header.Get(GnfdOffChainAuthAppRegNonceHeader)
 	userPublicKey = reqCtx.request.Header.Get(GnfdOffChainAuthAppRegPublicKeyHeader)
 	expiryDateStr = reqCtx.request.Header.Get(GnfdOffChainAuthAppRegExpiryDateHeader)
+	keyChange = true
 
 	// validate headers
 	if domain == "" || domain != origin {
@@ -3,6 +3,7 @@ package gater
 import (
 	"encoding/xml"
 	"net/http"
+	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -94,6 +95,124 @@ func (g *GateModular) getBucketReadQuotaHandler(w http.ResponseWriter, r *http.R
 	log.CtxDebugw(reqCtx.Context(), "succeed to get bucket quota", "xml_info", xmlInfo)
 }
 
+// readUsageDayLayout is the day format of the bucket read usage query.
+const readUsageDayLayout = "2006-01-02"
+
+// parseReadUsageDays checks the [startDay, endDay) range of the bucket read usage query.
+func parseReadUsageDays(startDay, endDay string) error {
+	start, err := time.Parse(readUsageDayLayout, startDay)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(readUsageDayLayout, endDay)
+	if err != nil {
+		return err
+	}
+	if !start.Before(end) {
+		return ErrInvalidQuery
+	}
+	return nil
+}
+
+// getBucketReadUsageHandler handles the get bucket daily read usage request, the usage is
+// served by the daily rollups instead of the read records.
+func (g *GateModular) getBucketReadUsageHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err           error
+		reqCtx        *RequestContext
+		authenticated bool
+		bucketInfo    *storagetypes.BucketInfo
+		usages        []*metadatatypes.BucketReadUsage
+	)
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if reqCtx.NeedVerifyAuthentication() {
+		authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
+			coremodule.AuthOpTypeGetBucketQuota, reqCtx.Account(), reqCtx.bucketName, "")
+		if err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to verify authentication", "error", err)
+			return
+		}
+		if !authenticated {
+			log.CtxErrorw(reqCtx.Context(), "no permission to operate")
+			err = ErrNoPermission
+			return
+		}
+	}
+
+	startDay, endDay := reqCtx.vars["start_day"], reqCtx.vars["end_day"]
+	if err = parseReadUsageDays(startDay, endDay); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to parse read usage days", "start_day", startDay,
+			"end_day", endDay, "error", err)
+		err = ErrInvalidQuery
+		return
+	}
+	bucketInfo, err = g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+		err = ErrConsensus
+		return
+	}
+	usages, err = g.baseApp.GfSpClient().GetBucketReadUsage(reqCtx.Context(), bucketInfo, startDay, endDay)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket read usage", "error", err)
+		return
+	}
+
+	type ReadUsage struct {
+		XMLName   xml.Name `xml:"ReadUsage"`
+		Day       string   `xml:"Day"`
+		ReadSize  uint64   `xml:"ReadSize"`
+		ReadCount uint64   `xml:"ReadCount"`
+	}
+	xmlUsages := make([]ReadUsage, 0, len(usages))
+	for _, usage := range usages {
+		xmlUsages = append(xmlUsages, ReadUsage{
+			Day:       usage.GetDay(),
+			ReadSize:  usage.GetReadSize(),
+			ReadCount: usage.GetReadCount(),
+		})
+	}
+	var xmlInfo = struct {
+		XMLName    xml.Name    `xml:"GetReadUsageResult"`
+		Version    string      `xml:"version,attr"`
+		BucketName string      `xml:"BucketName"`
+		BucketID   string      `xml:"BucketID"`
+		ReadUsages []ReadUsage `xml:"ReadUsage"`
+	}{
+		Version:    GnfdResponseXMLVersion,
+		BucketName: bucketInfo.GetBucketName(),
+		BucketID:   util.Uint64ToString(bucketInfo.Id.Uint64()),
+		ReadUsages: xmlUsages,
+	}
+	xmlBody, err := xml.Marshal(&xmlInfo)
+	if err != nil {
+		log.Errorw("failed to marshal xml", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+	if _, err = w.Write(xmlBody); err != nil {
+		log.Errorw("failed to write body", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+}
+
 // listBucketReadRecordHandler handles list bucket read record request.
 func (g *GateModular) listBucketReadRecordHandler(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -0,0 +1,33 @@
+package gater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseReadUsageDays(t *testing.T) {
+	cases := []struct {
+		name       string
+		startDay   string
+		endDay     string
+		wantedFail bool
+	}{
+		{name: "one day", startDay: "2023-03-01", endDay: "2023-03-02"},
+		{name: "one month", startDay: "2023-03-01", endDay: "2023-04-01"},
+		{name: "empty range", startDay: "2023-03-01", endDay: "2023-03-01", wantedFail: true},
+		{name: "reversed range", startDay: "2023-03-02", endDay: "2023-03-01", wantedFail: true},
+		{name: "invalid start day", startDay: "2023-3-1", endDay: "2023-03-02", wantedFail: true},
+		{name: "invalid end day", startDay: "2023-03-01", endDay: "2023/03/02", wantedFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := parseReadUsageDays(c.startDay, c.endDay)
+			if c.wantedFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -6,6 +6,10 @@ const (
 	ContentTypeHeader = "Content-Type"
 	// ContentLengthHeader indicates the size of the message body, in bytes
 	ContentLengthHeader = "Content-Length"
+	// RetryAfterHeader indicates how long in seconds the client ought to wait before retrying
+	RetryAfterHeader = "Retry-After"
+	// DrainingRetryAfter defines the retry after seconds of the requests rejected in draining
+	DrainingRetryAfter = "5"
 	// RangeHeader asks the server to send only a portion of an HTTP message back to a client
 	RangeHeader = "Range"
 	// ContentRangeHeader response HTTP header indicates where in a full body message a partial message belongs
@@ -16,6 +20,12 @@ const (
 	ContentTypeJSONHeaderValue = "application/json"
 	// ContentTypeXMLHeaderValue is used to indicate xml
 	ContentTypeXMLHeaderValue = "application/xml"
+	// ContentTypeEventStreamHeaderValue is used to indicate server-sent events
+	ContentTypeEventStreamHeaderValue = "text/event-stream"
+	// CacheControlHeader is used to indicate the caching directives
+	CacheControlHeader = "Cache-Control"
+	// LastEventIDHeader is sent by the server-sent events client to resume from the last received event
+	LastEventIDHeader = "Last-Event-ID"
 	// ContentDispositionHeader is used to indicate the media disposition of the resource
 	ContentDispositionHeader = "Content-Disposition"
 	// ContentDispositionAttachmentValue is used to indicate attachment
@@ -53,6 +63,12 @@ const (
 	GetBucketReadQuotaQuery = "read-quota"
 	// GetBucketReadQuotaMonthQuery defines bucket read quota query month
 	GetBucketReadQuotaMonthQuery = "year-month"
+	// GetBucketReadUsageQuery defines bucket daily read usage query, which is used to route request
+	GetBucketReadUsageQuery = "read-usage"
+	// GetBucketReadUsageStartDayQuery defines the first day of bucket read usage query, like "2023-03-01"
+	GetBucketReadUsageStartDayQuery = "start-day"
+	// GetBucketReadUsageEndDayQuery defines the day after the last day of bucket read usage query
+	GetBucketReadUsageEndDayQuery = "end-day"
 	// ListBucketReadRecordQuery defines list bucket read record query, which is used to route request
 	ListBucketReadRecordQuery = "list-read-record"
 	// ListBucketReadRecordMaxRecordsQuery defines list read record max num
@@ -67,10 +83,44 @@ const (
 	ListObjectsDelimiterQuery = "delimiter"
 	// ListObjectsPrefixQuery defines limits the response to keys that begin with the specified prefix
 	ListObjectsPrefixQuery = "prefix"
+	// ListObjectsContentTypeQuery limits the response to objects of the specified content type
+	ListObjectsContentTypeQuery = "content-type"
+	// ListObjectsMinSizeQuery limits the response to objects whose payload size is not less than it
+	ListObjectsMinSizeQuery = "min-size"
+	// ListObjectsMaxSizeQuery limits the response to objects whose payload size is not greater than it
+	ListObjectsMaxSizeQuery = "max-size"
+	// ListObjectsStartCreateTimeQuery limits the response to objects created at or after the unix timestamp in seconds
+	ListObjectsStartCreateTimeQuery = "start-create-time"
+	// ListObjectsEndCreateTimeQuery limits the response to objects created before the unix timestamp in seconds
+	ListObjectsEndCreateTimeQuery = "end-create-time"
+	// ListObjectsStatusQuery limits the response to objects of the specified status, e.g. OBJECT_STATUS_SEALED
+	ListObjectsStatusQuery = "status"
+	// ListObjectsOwnerQuery limits the response to objects owned by the account address
+	ListObjectsOwnerQuery = "owner"
+	// ListObjectsSortByQuery defines the order of the objects, e.g. object_name, create_time and payload_size
+	ListObjectsSortByQuery = "sort-by"
+	// ListObjectsSortDescQuery indicates the objects are sorted in descending order if it is true
+	ListObjectsSortDescQuery = "sort-desc"
 	// GetBucketMetaQuery defines get bucket metadata query, which is used to route request
 	GetBucketMetaQuery = "bucket-meta"
 	// GetObjectMetaQuery defines get object metadata query, which is used to route request
 	GetObjectMetaQuery = "object-meta"
+	// GetBucketStatsQuery defines get bucket storage stats query, which is used to route request
+	GetBucketStatsQuery = "bucket-stats"
+	// GetAccountStatsQuery defines get account storage stats query, which is used to route request
+	GetAccountStatsQuery = "account-stats"
+	// ChangeEventsQuery defines the stream change events query, which is used to route request
+	ChangeEventsQuery = "change-events"
+	// ChangeEventsCursorQuery defines the events after the cursor are streamed
+	ChangeEventsCursorQuery = "cursor"
+	// ChangeEventsFromLatestQuery defines the events after the latest event are streamed if there is no cursor
+	ChangeEventsFromLatestQuery = "from-latest"
+	// ChangeEventsPrefixQuery limits the events to the objects whose names begin with the prefix
+	ChangeEventsPrefixQuery = "prefix"
+	// WebhookQuery defines the bucket webhook subscription query, which is used to route request
+	WebhookQuery = "webhook"
+	// WebhookSubscriptionIDQuery defines the id of the webhook subscription to delete
+	WebhookSubscriptionIDQuery = "subscription-id"
 	// GetGroupListSourceTypeQuery defines get group list source type query, which is used to route request
 	GetGroupListSourceTypeQuery = "source-type"
 	// GetGroupListLimitQuery defines get group list limit query, which is used to route request
@@ -48,6 +48,13 @@ var (
 	ErrRecoverySP             = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50030, "The SP is not the correct SP to recovery")
 	ErrRecoveryRedundancyType = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50031, "The redundancy type of the recovering piece is not EC")
 	ErrRecoveryTimeout        = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50032, "System busy, try to request later")
+	ErrServiceDraining        = gfsperrors.Register(module.GateModularName, http.StatusServiceUnavailable, 50033, "server is draining, try to request later")
+	ErrTooManyRequests        = gfsperrors.Register(module.GateModularName, http.StatusTooManyRequests, 50034, "too many requests of the account, try to request later")
+	ErrInvalidWebhook         = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50035, "invalid webhook subscription")
+	ErrNoSuchWebhook          = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50036, "no such webhook subscription")
+	ErrExceedWebhookLimit     = gfsperrors.Register(module.GateModularName, http.StatusNotAcceptable, 50037, "the webhook subscriptions of the bucket exceed the limit")
+	ErrGfSpDB                 = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50038, "server slipped away, try again later")
+	ErrMismatchObject         = gfsperrors.Register(module.GateModularName, http.StatusNotAcceptable, 50039, "the receive task mismatches the object of the approval")
 )
 
 func MakeErrorResponse(w http.ResponseWriter, err error) {
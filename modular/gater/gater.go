@@ -7,13 +7,18 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 )
 
 var _ module.Modular = &GateModular{}
+var _ gfspapp.Reloadable = &GateModular{}
+var _ gfspapp.ReloadValidator = &GateModular{}
 
 type GateModular struct {
 	domain      string
@@ -42,6 +47,10 @@ func (g *GateModular) Start(ctx context.Context) error {
 
 func (g *GateModular) server(ctx context.Context) {
 	router := mux.NewRouter().SkipClean(true)
+	// the tracing middleware is the outermost so the metrics exemplars carry the trace id
+	if g.baseApp.EnableTracing() {
+		router.Use(tracing.HTTPMiddleware)
+	}
 	if g.baseApp.EnableMetrics() {
 		router.Use(metrics.DefaultHTTPServerMetrics.InstrumentationHandler)
 	}
@@ -57,12 +66,36 @@ func (g *GateModular) server(ctx context.Context) {
 	}
 }
 
+// ValidateReload checks the changed rate limit config before any field is reloaded.
+func (g *GateModular) ValidateReload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	return localhttp.ValidateAPILimiter(makeAPIRateLimitCfg(cfg.APIRateLimiter))
+}
+
+// Reload replaces the api rate limiter with the changed rate limit config.
+func (g *GateModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if err := localhttp.NewAPILimiter(makeAPIRateLimitCfg(cfg.APIRateLimiter)); err != nil {
+		log.CtxErrorw(ctx, "failed to reload api limiter", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (g *GateModular) Stop(ctx context.Context) error {
 	g.scope.Release()
 	g.httpServer.Shutdown(ctx)
 	return nil
 }
 
+// rejectDraining returns an indicator whether the upload request is rejected for the sp
+// draining, the client is asked to retry later and is routed to the other replicas.
+func (g *GateModular) rejectDraining(w http.ResponseWriter) bool {
+	if !g.baseApp.Draining() {
+		return false
+	}
+	w.Header().Set(RetryAfterHeader, DrainingRetryAfter)
+	return true
+}
+
 func (g *GateModular) ReserveResource(
 	ctx context.Context,
 	state *rcmgr.ScopeStat) (
@@ -74,5 +74,9 @@ func makeAPIRateLimitCfg(cfg localhttp.RateLimiterConfig) *localhttp.APILimiterC
 		HostPattern: patternMap,
 		APILimits:   apiLimitsMap,
 		IPLimitCfg:  cfg.IPLimitCfg,
+
+		StoreCfg:        cfg.StoreCfg,
+		AccountLimitCfg: cfg.AccountLimitCfg,
+		BucketLimitCfg:  cfg.BucketLimitCfg,
 	}
 }
@@ -0,0 +1,62 @@
+package gater
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+)
+
+func TestGateModular_ValidateReload(t *testing.T) {
+	cases := []struct {
+		name       string
+		cfg        localhttp.RateLimiterConfig
+		wantedFail bool
+	}{
+		{
+			name: "valid limits",
+			cfg: localhttp.RateLimiterConfig{
+				PathPattern: []localhttp.RateLimiterCell{{Key: "/mock/.*", RateLimit: 10, RatePeriod: "S"}},
+				HostPattern: []localhttp.RateLimiterCell{{Key: ".*mock.*", RateLimit: 10, RatePeriod: "M"}},
+				APILimits:   []localhttp.RateLimiterCell{{Key: "GetObject", RateLimit: 10, RatePeriod: "H"}},
+			},
+		},
+		{
+			name: "invalid api rate period",
+			cfg: localhttp.RateLimiterConfig{
+				APILimits: []localhttp.RateLimiterCell{{Key: "GetObject", RateLimit: 10, RatePeriod: "Y"}},
+			},
+			wantedFail: true,
+		},
+		{
+			name: "invalid path pattern",
+			cfg: localhttp.RateLimiterConfig{
+				PathPattern: []localhttp.RateLimiterCell{{Key: "/mock/[", RateLimit: 10, RatePeriod: "S"}},
+			},
+			wantedFail: true,
+		},
+		{
+			name: "invalid ip rate period",
+			cfg: localhttp.RateLimiterConfig{
+				IPLimitCfg: localhttp.IPLimitConfig{On: true, RateLimit: 10, RatePeriod: "Y"},
+			},
+			wantedFail: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := &GateModular{}
+			cfg := &gfspconfig.GfSpConfig{APIRateLimiter: c.cfg}
+			err := g.ValidateReload(context.Background(), cfg)
+			if c.wantedFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, g.Reload(context.Background(), cfg))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package gater
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -94,6 +96,7 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 		continuationToken        string
 		decodedContinuationToken []byte
 		queryParams              url.Values
+		filter                   *bsdb.ListObjectsFilter
 	)
 
 	defer func() {
@@ -124,6 +127,22 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	if filter, err = parseListObjectsFilter(queryParams); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to parse list objects filter", "error", err)
+		err = ErrInvalidQuery
+		return
+	}
+
+	// the filter and the order can not be used with the delimiter, and the objects can only
+	// start after the object name if they are sorted by object name
+	if filter != nil && (requestDelimiter != "" ||
+		(requestStartAfter != "" && filter.SortBy != "" && filter.SortBy != bsdb.SortByObjectName)) {
+		log.CtxErrorw(reqCtx.Context(), "failed to check list objects filter", "delimiter", requestDelimiter,
+			"start_after", requestStartAfter, "sort_by", filter.SortBy)
+		err = ErrInvalidQuery
+		return
+	}
+
 	if err = s3util.CheckValidBucketName(requestBucketName); err != nil {
 		log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", requestBucketName, "error", err)
 		return
@@ -154,13 +173,21 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 		}
 		continuationToken = string(decodedContinuationToken)
 
-		if err = s3util.CheckValidObjectName(continuationToken); err != nil {
+		// the token of the objects sorted by the other field is prefixed with the sort field and value
+		_, tokenObjectName, parseErr := filter.ParseContinuationToken(continuationToken)
+		if parseErr != nil {
+			log.Errorw("failed to parse requestContinuationToken", "continuation_token", continuationToken, "error", parseErr)
+			err = ErrInvalidQuery
+			return
+		}
+
+		if err = s3util.CheckValidObjectName(tokenObjectName); err != nil {
 			log.Errorw("failed to check requestContinuationToken", "continuation_token", continuationToken, "error", err)
 			err = ErrInvalidQuery
 			return
 		}
 
-		if !strings.HasPrefix(continuationToken, requestPrefix) {
+		if !strings.HasPrefix(tokenObjectName, requestPrefix) {
 			log.Errorw("failed to check requestContinuationToken", "continuation_token", continuationToken, "prefix", requestPrefix, "error", err)
 			err = ErrInvalidQuery
 			return
@@ -197,7 +224,8 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 			continuationToken,
 			requestDelimiter,
 			requestPrefix,
-			true)
+			true,
+			filter)
 	if err != nil {
 		log.Errorf("failed to list objects by bucket name", "error", err)
 		return
@@ -635,3 +663,209 @@ func (g *GateModular) listBucketsByBucketIDHandler(w http.ResponseWriter, r *htt
 	w.Header().Set(ContentTypeHeader, ContentTypeJSONHeaderValue)
 	w.Write(buf.Bytes())
 }
+
+// getBucketStatsHandler handle get bucket storage stats request
+func (g *GateModular) getBucketStatsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		b      bytes.Buffer
+		reqCtx *RequestContext
+	)
+
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			log.CtxErrorw(reqCtx.Context(), "failed to get bucket stats", reqCtx.String())
+			MakeErrorResponse(w, err)
+		}
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+
+	if err = s3util.CheckValidBucketName(reqCtx.bucketName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", reqCtx.bucketName, "error", err)
+		return
+	}
+
+	grpcResponse, err := g.baseApp.GfSpClient().GetBucketStats(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket stats", "error", err)
+		return
+	}
+
+	m := jsonpb.Marshaler{EmitDefaults: true, OrigName: true, EnumsAsInts: true}
+	if err = m.Marshal(&b, grpcResponse); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket stats", "error", err)
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ContentTypeJSONHeaderValue)
+	w.Write(b.Bytes())
+}
+
+// getAccountStatsHandler handle get account storage stats request
+func (g *GateModular) getAccountStatsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		b      bytes.Buffer
+		reqCtx *RequestContext
+	)
+
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			log.CtxErrorw(reqCtx.Context(), "failed to get account stats", reqCtx.String())
+			MakeErrorResponse(w, err)
+		}
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+
+	if ok := common.IsHexAddress(r.Header.Get(GnfdUserAddressHeader)); !ok {
+		log.CtxErrorw(reqCtx.Context(), "failed to check account address", "account_address", r.Header.Get(GnfdUserAddressHeader))
+		err = ErrInvalidHeader
+		return
+	}
+
+	grpcResponse, err := g.baseApp.GfSpClient().GetAccountStats(reqCtx.Context(), r.Header.Get(GnfdUserAddressHeader))
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get account stats", "error", err)
+		return
+	}
+
+	m := jsonpb.Marshaler{EmitDefaults: true, OrigName: true, EnumsAsInts: true}
+	if err = m.Marshal(&b, grpcResponse); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get account stats", "error", err)
+		return
+	}
+
+	w.Header().Set(ContentTypeHeader, ContentTypeJSONHeaderValue)
+	w.Write(b.Bytes())
+}
+
+// getChangeEventsHandler handle stream change events request, the events of the bucket or all
+// the events are sent as the server-sent events whose id is the cursor, so the client resumes
+// from the last received event by the cursor query or the Last-Event-ID header. The change feed
+// is one-way, so it is served by SSE only instead of WebSocket, which passes through the HTTP
+// proxies and the signature authentication of the plain GET request.
+func (g *GateModular) getChangeEventsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err           error
+		reqCtx        *RequestContext
+		requestCursor string
+		prefix        string
+		cursor        uint64
+		fromLatest    bool
+		flusher       http.Flusher
+		ok            bool
+	)
+
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			log.CtxErrorw(reqCtx.Context(), "failed to stream change events", reqCtx.String())
+			MakeErrorResponse(w, err)
+		}
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+
+	queryParams := reqCtx.request.URL.Query()
+	if reqCtx.bucketName != "" {
+		if err = s3util.CheckValidBucketName(reqCtx.bucketName); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", reqCtx.bucketName, "error", err)
+			return
+		}
+	}
+
+	prefix = queryParams.Get(ChangeEventsPrefixQuery)
+	if ok = checkValidObjectPrefix(prefix); !ok {
+		log.CtxErrorw(reqCtx.Context(), "failed to check prefix", "prefix", prefix)
+		err = ErrInvalidQuery
+		return
+	}
+
+	if requestCursor = queryParams.Get(ChangeEventsCursorQuery); requestCursor == "" {
+		requestCursor = r.Header.Get(LastEventIDHeader)
+	}
+	if requestCursor != "" {
+		if cursor, err = util.StringToUint64(requestCursor); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to parse cursor", "cursor", requestCursor, "error", err)
+			err = ErrInvalidQuery
+			return
+		}
+	}
+
+	if requestFromLatest := queryParams.Get(ChangeEventsFromLatestQuery); requestFromLatest != "" {
+		if fromLatest, err = strconv.ParseBool(requestFromLatest); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to parse from latest", "from_latest", requestFromLatest, "error", err)
+			err = ErrInvalidQuery
+			return
+		}
+	}
+
+	if flusher, ok = w.(http.Flusher); !ok {
+		log.CtxError(reqCtx.Context(), "failed to stream change events, the response writer does not support flush")
+		err = ErrEncodeResponse
+		return
+	}
+
+	// the request context is detached from the http request, so the stream is closed by the
+	// disconnected client explicitly
+	ctx, cancel := context.WithCancel(reqCtx.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.Context().Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	w.Header().Set(ContentTypeHeader, ContentTypeEventStreamHeaderValue)
+	w.Header().Set(CacheControlHeader, "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	m := jsonpb.Marshaler{EmitDefaults: true, OrigName: true}
+	streamErr := g.baseApp.GfSpClient().StreamChangeEvents(ctx, &types.GfSpStreamChangeEventsRequest{
+		Cursor:     cursor,
+		FromLatest: fromLatest,
+		BucketName: reqCtx.bucketName,
+		Prefix:     prefix,
+	}, func(resp *types.GfSpStreamChangeEventsResponse) error {
+		if len(resp.GetEvents()) == 0 {
+			// the comment line keeps the idle connection alive
+			if _, writeErr := w.Write([]byte(": heartbeat\n\n")); writeErr != nil {
+				return writeErr
+			}
+		}
+		for _, event := range resp.GetEvents() {
+			data, marshalErr := m.MarshalToString(event)
+			if marshalErr != nil {
+				return marshalErr
+			}
+			if _, writeErr := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.GetCursor(), event.GetEventType(), data); writeErr != nil {
+				return writeErr
+			}
+		}
+		flusher.Flush()
+		return nil
+	})
+	// the response is started, the error is not sent to the client
+	if streamErr != nil {
+		log.CtxErrorw(reqCtx.Context(), "change events stream is broken", "cursor", cursor, "error", streamErr)
+	}
+}
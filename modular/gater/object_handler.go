@@ -20,7 +20,9 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
 	servicetypes "github.com/bnb-chain/greenfield-storage-provider/store/types"
 	"github.com/bnb-chain/greenfield-storage-provider/util"
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
@@ -54,6 +56,10 @@ func (g *GateModular) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if g.rejectDraining(w) {
+		err = ErrServiceDraining
+		return
+	}
 	if reqCtx.NeedVerifyAuthentication() {
 		startAuthenticationTime := time.Now()
 		authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
@@ -94,13 +100,13 @@ func (g *GateModular) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 	task := &gfsptask.GfSpUploadObjectTask{}
 	task.InitUploadObjectTask(objectInfo, params, g.baseApp.TaskTimeout(task, objectInfo.GetPayloadSize()))
 	ctx := log.WithValue(reqCtx.Context(), log.CtxKeyTask, task.Key().String())
-	g.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayBeginReceiveUpload, task.Key().String())
+	g.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayBeginReceiveUpload, task.Key().String())
 	err = g.baseApp.GfSpClient().UploadObject(ctx, task, r.Body)
 	if err != nil {
-		g.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayEndReceiveUpload, task.Key().String()+":"+err.Error())
+		g.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayEndReceiveUpload, task.Key().String()+":"+err.Error())
 		log.CtxErrorw(ctx, "failed to upload payload data", "error", err)
 	}
-	g.baseApp.GfSpDB().InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayEndReceiveUpload, task.Key().String())
+	g.baseApp.GfSpDBWithContext(ctx).InsertUploadEvent(task.GetObjectInfo().Id.Uint64(), corespdb.GatewayEndReceiveUpload, task.Key().String())
 	log.CtxDebugw(ctx, "succeed to upload payload data")
 }
 
@@ -166,6 +172,10 @@ func (g *GateModular) resumablePutObjectHandler(w http.ResponseWriter, r *http.R
 	if err != nil {
 		return
 	}
+	if g.rejectDraining(w) {
+		err = ErrServiceDraining
+		return
+	}
 	if reqCtx.NeedVerifyAuthentication() {
 		startAuthirzerTime := time.Now()
 		authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
@@ -337,16 +347,17 @@ func (g *GateModular) queryResumeOffsetHandler(w http.ResponseWriter, r *http.Re
 // getObjectHandler handles the download object request.
 func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 	var (
-		err           error
-		reqCtxErr     error
-		reqCtx        *RequestContext
-		authenticated bool
-		objectInfo    *storagetypes.ObjectInfo
-		bucketInfo    *storagetypes.BucketInfo
-		params        *storagetypes.Params
-		lowOffset     int64
-		highOffset    int64
-		pieceInfos    []*downloader.SegmentPieceInfo
+		err             error
+		reqCtxErr       error
+		reqCtx          *RequestContext
+		authenticated   bool
+		privateDownload bool
+		objectInfo      *storagetypes.ObjectInfo
+		bucketInfo      *storagetypes.BucketInfo
+		params          *storagetypes.Params
+		lowOffset       int64
+		highOffset      int64
+		pieceInfos      []*downloader.SegmentPieceInfo
 	)
 	getObjectStartTime := time.Now()
 	defer func() {
@@ -359,10 +370,18 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			reqCtx.SetHttpCode(http.StatusOK)
 		}
+		if privateDownload {
+			auditPrivateDownload(reqCtx, lowOffset, highOffset, err)
+		}
 		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
 		metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_total_time").Observe(time.Since(getObjectStartTime).Seconds())
 	}()
 	reqCtx, reqCtxErr = NewRequestContext(r, g)
+	if reqCtxErr == ErrTooManyRequests {
+		// the limited account is rejected even if the object is public
+		err = reqCtxErr
+		return
+	}
 	// check the object permission whether allow public read.
 	verifyObjectPermissionTime := time.Now()
 	if authenticated, err = g.baseApp.Consensus().VerifyGetObjectPermission(reqCtx.Context(), sdk.AccAddress{}.String(),
@@ -394,6 +413,7 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+		privateDownload = true
 	} // else anonymous users can get public object.
 
 	getObjectTime := time.Now()
@@ -508,10 +528,18 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 				reoverData = reoverData[:pInfo.Length]
 				log.CtxErrorw(reqCtx.Context(), "adjust the piece data", "len:", len(reoverData))
 			}
+			if err = localhttp.WaitDownload(reqCtx.Context(), reqCtx.Account(), reqCtx.bucketName, len(reoverData)); err != nil {
+				log.CtxErrorw(reqCtx.Context(), "failed to wait download bandwidth", "error", err)
+				return
+			}
 			w.Write(reoverData)
 			continue
 		}
 
+		if err = localhttp.WaitDownload(reqCtx.Context(), reqCtx.Account(), reqCtx.bucketName, len(pieceData)); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to wait download bandwidth", "error", err)
+			return
+		}
 		writeTime := time.Now()
 		w.Write(pieceData)
 		metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_write_time").Observe(time.Since(writeTime).Seconds())
@@ -566,6 +594,11 @@ func (g *GateModular) getRecoveryPieceHandler(w http.ResponseWriter, r *http.Req
 		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
 	}()
 	reqCtx, reqCtxErr = NewRequestContext(r, g)
+	if reqCtxErr == ErrTooManyRequests {
+		// the limited account is rejected even if the object is public
+		err = reqCtxErr
+		return
+	}
 	// check the object permission whether allow public read.
 	if authenticated, err = g.baseApp.Consensus().VerifyGetObjectPermission(reqCtx.Context(), sdk.AccAddress{}.String(),
 		reqCtx.bucketName, reqCtx.objectName); err != nil {
@@ -726,9 +759,15 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 		params               *storagetypes.Params
 		escapedObjectName    string
 		isRequestFromBrowser bool
+		privateDownload      bool
+		low                  int64
+		high                 int64
 	)
 	defer func() {
 		reqCtx.Cancel()
+		if privateDownload {
+			auditPrivateDownload(reqCtx, low, high, err)
+		}
 		if err != nil {
 			if isRequestFromBrowser {
 				reqCtx.SetHttpCode(http.StatusOK)
@@ -859,10 +898,13 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 			accAddress, verifySigErr := VerifyPersonalSignature(signedMsg, signature)
 			if verifySigErr != nil {
 				log.CtxErrorw(reqCtx.Context(), "failed to verify signature", "error", verifySigErr)
+				reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultFailure,
+					map[string]string{"error": verifySigErr.Error()})
 				err = verifySigErr
 				return
 			}
 			reqCtx.account = accAddress.String()
+			reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultSuccess, nil)
 
 			// 2. check permission
 			authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
@@ -874,10 +916,11 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 			}
 			if !authenticated {
 				log.CtxErrorw(reqCtx.Context(), "no permission to operate")
+				reqCtx.emitAudit(audit.EventPermissionDenied, audit.ResultDenied, nil)
 				err = ErrForbidden
 				return
 			}
-
+			privateDownload = true
 		} else {
 			if !isRequestFromBrowser {
 				err = ErrForbidden
@@ -913,8 +956,6 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 		return
 	}
 
-	var low int64
-	var high int64
 	if isRange {
 		low = rangeStart
 		high = rangeEnd
@@ -945,10 +986,25 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 	} else {
 		w.Header().Set(ContentLengthHeader, util.Uint64ToString(getObjectInfoRes.GetObjectInfo().GetPayloadSize()))
 	}
+	if err = localhttp.WaitDownload(reqCtx.Context(), reqCtx.Account(), reqCtx.bucketName, len(data)); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to wait download bandwidth", "error", err)
+		return
+	}
 	w.Write(data)
 	log.CtxDebugw(reqCtx.Context(), "succeed to download object for universal endpoint")
 }
 
+// auditPrivateDownload records the download of the private object to the audit log.
+func auditPrivateDownload(reqCtx *RequestContext, low, high int64, err error) {
+	result := audit.ResultSuccess
+	detail := map[string]string{"range": fmt.Sprintf("%d-%d", low, high)}
+	if err != nil {
+		result = audit.ResultFailure
+		detail["error"] = err.Error()
+	}
+	reqCtx.emitAudit(audit.EventPrivateDownload, result, detail)
+}
+
 func isPrivateObject(bucket *storagetypes.BucketInfo, object *storagetypes.ObjectInfo) bool {
 	return object.GetVisibility() == storagetypes.VISIBILITY_TYPE_PRIVATE ||
 		(object.GetVisibility() == storagetypes.VISIBILITY_TYPE_INHERIT &&
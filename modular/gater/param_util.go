@@ -1,9 +1,13 @@
 package gater
 
 import (
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
 )
 
 // hasInvalidPath checks if the given path contains "." or ".." as path segments.
@@ -38,3 +42,47 @@ func checkValidObjectPrefix(prefix string) bool {
 	}
 	return true
 }
+
+// parseListObjectsFilter parses the filter and the order of list objects from the query, the
+// filter is nil if no filter or order is specified.
+func parseListObjectsFilter(queryParams url.Values) (*bsdb.ListObjectsFilter, error) {
+	var err error
+	filter := &bsdb.ListObjectsFilter{
+		ContentType:  queryParams.Get(ListObjectsContentTypeQuery),
+		ObjectStatus: queryParams.Get(ListObjectsStatusQuery),
+		Owner:        queryParams.Get(ListObjectsOwnerQuery),
+		SortBy:       queryParams.Get(ListObjectsSortByQuery),
+	}
+	if value := queryParams.Get(ListObjectsMinSizeQuery); value != "" {
+		if filter.MinPayloadSize, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	if value := queryParams.Get(ListObjectsMaxSizeQuery); value != "" {
+		if filter.MaxPayloadSize, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	if value := queryParams.Get(ListObjectsStartCreateTimeQuery); value != "" {
+		if filter.StartCreateTime, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	if value := queryParams.Get(ListObjectsEndCreateTimeQuery); value != "" {
+		if filter.EndCreateTime, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	if value := queryParams.Get(ListObjectsSortDescQuery); value != "" {
+		if filter.SortDesc, err = strconv.ParseBool(value); err != nil {
+			return nil, err
+		}
+	}
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+	if err = filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,55 @@
+package gater
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+func Test_parseListObjectsFilter(t *testing.T) {
+	cases := []struct {
+		name         string
+		query        string
+		wantedFilter *bsdb.ListObjectsFilter
+		wantedFail   bool
+	}{
+		{name: "no filter", query: "", wantedFilter: nil},
+		{name: "false sort desc is no filter", query: ListObjectsSortDescQuery + "=false", wantedFilter: nil},
+		{
+			name: "all conditions",
+			query: ListObjectsContentTypeQuery + "=text/plain&" + ListObjectsMinSizeQuery + "=1&" +
+				ListObjectsMaxSizeQuery + "=10&" + ListObjectsStartCreateTimeQuery + "=100&" +
+				ListObjectsEndCreateTimeQuery + "=200&" + ListObjectsStatusQuery + "=OBJECT_STATUS_SEALED&" +
+				ListObjectsOwnerQuery + "=0x0000000000000000000000000000000000000001&" +
+				ListObjectsSortByQuery + "=payload_size&" + ListObjectsSortDescQuery + "=true",
+			wantedFilter: &bsdb.ListObjectsFilter{ContentType: "text/plain", MinPayloadSize: 1, MaxPayloadSize: 10,
+				StartCreateTime: 100, EndCreateTime: 200, ObjectStatus: "OBJECT_STATUS_SEALED",
+				Owner: "0x0000000000000000000000000000000000000001", SortBy: bsdb.SortByPayloadSize, SortDesc: true},
+		},
+		{name: "invalid min size", query: ListObjectsMinSizeQuery + "=-1", wantedFail: true},
+		{name: "invalid max size", query: ListObjectsMaxSizeQuery + "=ten", wantedFail: true},
+		{name: "invalid start time", query: ListObjectsStartCreateTimeQuery + "=now", wantedFail: true},
+		{name: "invalid end time", query: ListObjectsEndCreateTimeQuery + "=1.5", wantedFail: true},
+		{name: "invalid sort desc", query: ListObjectsSortDescQuery + "=yes", wantedFail: true},
+		{name: "unknown sort field", query: ListObjectsSortByQuery + "=owner", wantedFail: true},
+		{name: "min size greater than max size", query: ListObjectsMinSizeQuery + "=2&" + ListObjectsMaxSizeQuery + "=1", wantedFail: true},
+		{name: "unknown status", query: ListObjectsStatusQuery + "=SEALED", wantedFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			queryParams, err := url.ParseQuery(c.query)
+			require.NoError(t, err)
+			filter, err := parseListObjectsFilter(queryParams)
+			if c.wantedFail {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.wantedFilter, filter)
+		})
+	}
+}
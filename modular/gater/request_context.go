@@ -15,7 +15,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"github.com/gorilla/mux"
 
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/audit"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 )
 
 // RequestContext generates from http request, it records the common info
@@ -48,7 +52,8 @@ func NewRequestContext(r *http.Request, g *GateModular) (*RequestContext, error)
 	if mux.CurrentRoute(r) != nil {
 		routerName = mux.CurrentRoute(r).GetName()
 	}
-	ctx, cancel := context.WithCancel(context.Background())
+	// the request context outlives the http request context, it only inherits the span
+	ctx, cancel := context.WithCancel(tracing.DetachContext(r.Context()))
 	reqCtx := &RequestContext{
 		g:          g,
 		ctx:        ctx,
@@ -63,9 +68,19 @@ func NewRequestContext(r *http.Request, g *GateModular) (*RequestContext, error)
 	}
 	account, err := reqCtx.VerifySignature()
 	if err != nil {
+		// the request without the supported signature is anonymous, it is not a failure
+		if err != ErrUnsupportedSignType {
+			reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultFailure,
+				map[string]string{"error": err.Error()})
+		}
 		return reqCtx, err
 	}
 	reqCtx.account = account
+	reqCtx.emitAudit(audit.EventSignatureVerify, audit.ResultSuccess, nil)
+	if !localhttp.AccountAllow(ctx, account) {
+		log.CtxWarnw(ctx, "account exceeds the rate limit", "account", account)
+		return reqCtx, ErrTooManyRequests
+	}
 	return reqCtx, nil
 }
 
@@ -89,9 +104,44 @@ func (r *RequestContext) SetHttpCode(code int) {
 	r.httpCode = code
 }
 
-// SetError sets the request err to RequestContext for logging and debugging.
+// SetError sets the request err to RequestContext for logging and debugging, the
+// permission denials are recorded to the audit log.
 func (r *RequestContext) SetError(err error) {
 	r.err = err
+	if err != nil && gfsperrors.MakeGfSpError(err).GetInnerCode() == ErrNoPermission.GetInnerCode() {
+		r.emitAudit(audit.EventPermissionDenied, audit.ResultDenied, nil)
+	}
+}
+
+// emitAudit records the security relevant event of the request to the audit log.
+func (r *RequestContext) emitAudit(eventType audit.EventType, result string, detail map[string]string) {
+	resource := r.bucketName
+	if r.objectName != "" {
+		resource += "/" + r.objectName
+	}
+	audit.Emit(r.ctx, &audit.Event{
+		Type:     eventType,
+		Actor:    r.account,
+		Remote:   getRequestIP(r.request),
+		Action:   r.routerName,
+		Resource: resource,
+		Result:   result,
+		Detail:   detail,
+	})
+}
+
+func getRequestIP(r *http.Request) string {
+	IPAddress := r.Header.Get("X-Real-Ip")
+	if IPAddress == "" {
+		IPAddress = r.Header.Get("X-Forwarded-For")
+	}
+	if IPAddress == "" {
+		IPAddress = r.RemoteAddr
+	}
+	if ok := strings.Contains(IPAddress, ":"); ok {
+		IPAddress = strings.Split(IPAddress, ":")[0]
+	}
+	return IPAddress
 }
 
 // String shows the detail result of the request for logging and debugging.
@@ -109,19 +159,6 @@ func (r *RequestContext) String() string {
 		}
 		return "{" + sb.String() + "}"
 	}
-	var getRequestIP = func(r *http.Request) string {
-		IPAddress := r.Header.Get("X-Real-Ip")
-		if IPAddress == "" {
-			IPAddress = r.Header.Get("X-Forwarded-For")
-		}
-		if IPAddress == "" {
-			IPAddress = r.RemoteAddr
-		}
-		if ok := strings.Contains(IPAddress, ":"); ok {
-			IPAddress = strings.Split(IPAddress, ":")[0]
-		}
-		return IPAddress
-	}
 	return fmt.Sprintf("HttpStatusCode[%d] action[%s] host[%v] method[%v] url[%v] header[%v] remote[%v] cost[%v] error[%v]",
 		r.httpCode, r.routerName, r.request.Host, r.request.Method, r.request.URL.String(), headerToString(r.request.Header),
 		getRequestIP(r.request), time.Since(r.startTime), r.err)
@@ -18,10 +18,12 @@ const (
 	getObjectRouterName                   = "GetObject"
 	getChallengeInfoRouterName            = "GetChallengeInfo"
 	replicateObjectPieceRouterName        = "ReplicateObjectPiece"
+	discardObjectPieceRouterName          = "DiscardObjectPiece"
 	getUserBucketsRouterName              = "GetUserBuckets"
 	listObjectsByBucketRouterName         = "ListObjectsByBucketName"
 	verifyPermissionRouterName            = "VerifyPermission"
 	getBucketReadQuotaRouterName          = "GetBucketReadQuota"
+	getBucketReadUsageRouterName          = "GetBucketReadUsage"
 	listBucketReadRecordRouterName        = "ListBucketReadRecord"
 	requestNonceName                      = "RequestNonce"
 	updateUserPublicKey                   = "UpdateUserPublicKey"
@@ -30,6 +32,13 @@ const (
 	viewObjectByUniversalEndpointName     = "ViewObjectByUniversalEndpoint"
 	getObjectMetaRouterName               = "GetObjectMeta"
 	getBucketMetaRouterName               = "GetBucketMeta"
+	getBucketStatsRouterName              = "GetBucketStats"
+	getAccountStatsRouterName             = "GetAccountStats"
+	getBucketChangeEventsRouterName       = "GetBucketChangeEvents"
+	getChangeEventsRouterName             = "GetChangeEvents"
+	putBucketWebhookRouterName            = "PutBucketWebhook"
+	listBucketWebhooksRouterName          = "ListBucketWebhooks"
+	deleteBucketWebhookRouterName         = "DeleteBucketWebhook"
 	getGroupListRouterName                = "GetGroupList"
 	listBucketsByBucketIDRouterName       = "ListBucketsByBucketID"
 	listObjectsByObjectIDRouterName       = "ListObjectsByObjectID"
@@ -66,6 +75,7 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 
 	// replicate piece to receiver
 	router.Path(ReplicateObjectPiecePath).Name(replicateObjectPieceRouterName).Methods(http.MethodPut).HandlerFunc(g.replicateHandler)
+	router.Path(ReplicateObjectPiecePath).Name(discardObjectPieceRouterName).Methods(http.MethodDelete).HandlerFunc(g.replicateHandler)
 	router.Path(RecoverObjectPiecePath).Name(recoveryPieceRouterName).Methods(http.MethodGet).HandlerFunc(g.recoverPrimaryHandler)
 	// universal endpoint download
 	router.Path("/download/{bucket:[^/]*}/{object:.+}").Name(downloadObjectByUniversalEndpointName).Methods(http.MethodGet).
@@ -96,6 +106,16 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 		r.NewRoute().Name(getPieceFromSecondaryRouterName).Methods(http.MethodGet).Path("/{object:.+}").Queries(GetSecondaryPieceData, "").HandlerFunc(g.getRecoveryPieceHandler)
 		// Get Bucket Meta
 		r.NewRoute().Name(getBucketMetaRouterName).Methods(http.MethodGet).Queries(GetBucketMetaQuery, "").HandlerFunc(g.getBucketMetaHandler)
+		// Get Bucket Stats
+		r.NewRoute().Name(getBucketStatsRouterName).Methods(http.MethodGet).Queries(GetBucketStatsQuery, "").HandlerFunc(g.getBucketStatsHandler)
+		// Stream Bucket Change Events
+		r.NewRoute().Name(getBucketChangeEventsRouterName).Methods(http.MethodGet).Queries(ChangeEventsQuery, "").HandlerFunc(g.getChangeEventsHandler)
+		// Bucket Webhook Subscriptions
+		r.NewRoute().Name(putBucketWebhookRouterName).Methods(http.MethodPut).Queries(WebhookQuery, "").HandlerFunc(g.putBucketWebhookHandler)
+		r.NewRoute().Name(listBucketWebhooksRouterName).Methods(http.MethodGet).Queries(WebhookQuery, "").HandlerFunc(g.listBucketWebhooksHandler)
+		r.NewRoute().Name(deleteBucketWebhookRouterName).Methods(http.MethodDelete).Queries(
+			WebhookQuery, "",
+			WebhookSubscriptionIDQuery, "{subscription_id}").HandlerFunc(g.deleteBucketWebhookHandler)
 
 		// Get Object Meta
 		r.NewRoute().Name(getObjectMetaRouterName).Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(g.getObjectMetaHandler).Queries(
@@ -109,6 +129,12 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 			GetBucketReadQuotaQuery, "",
 			GetBucketReadQuotaMonthQuery, "{year_month}")
 
+		// Get Bucket Read Usage
+		r.NewRoute().Name(getBucketReadUsageRouterName).Methods(http.MethodGet).HandlerFunc(g.getBucketReadUsageHandler).Queries(
+			GetBucketReadUsageQuery, "",
+			GetBucketReadUsageStartDayQuery, "{start_day}",
+			GetBucketReadUsageEndDayQuery, "{end_day}")
+
 		// List Bucket Read Record
 		r.NewRoute().Name(listBucketReadRecordRouterName).Methods(http.MethodGet).HandlerFunc(g.listBucketReadRecordHandler).Queries(
 			ListBucketReadRecordQuery, "",
@@ -139,6 +165,16 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 		Methods(http.MethodPost).
 		Queries(ListBucketsByBucketID, "").
 		HandlerFunc(g.listBucketsByBucketIDHandler)
+	router.Path("/").
+		Name(getAccountStatsRouterName).
+		Methods(http.MethodGet).
+		Queries(GetAccountStatsQuery, "").
+		HandlerFunc(g.getAccountStatsHandler)
+	router.Path("/").
+		Name(getChangeEventsRouterName).
+		Methods(http.MethodGet).
+		Queries(ChangeEventsQuery, "").
+		HandlerFunc(g.getChangeEventsHandler)
 	router.Path("/").
 		Name(getUserBucketsRouterName).
 		Methods(http.MethodGet).
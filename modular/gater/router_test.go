@@ -149,6 +149,24 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: getBucketReadQuotaRouterName,
 		},
+		{
+			name:   "Get bucket read usage router, virtual host style",
+			router: gwRouter,
+			method: http.MethodGet,
+			url: scheme + bucketName + "." + testDomain + "/?" + GetBucketReadUsageQuery +
+				"&" + GetBucketReadUsageStartDayQuery + "&" + GetBucketReadUsageEndDayQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketReadUsageRouterName,
+		},
+		{
+			name:   "Get bucket read usage router, path style",
+			router: gwRouter,
+			method: http.MethodGet,
+			url: scheme + testDomain + "/" + bucketName + "?" + GetBucketReadUsageQuery +
+				"&" + GetBucketReadUsageStartDayQuery + "&" + GetBucketReadUsageEndDayQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketReadUsageRouterName,
+		},
 		{
 			name:   "List bucket read records router, virtual host style",
 			router: gwRouter,
@@ -185,6 +203,15 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: listObjectsByBucketRouterName,
 		},
+		{
+			name:   "List bucket objects router with filter, virtual host style",
+			router: gwRouter,
+			method: http.MethodGet,
+			url: scheme + bucketName + "." + testDomain + "/?" + ListObjectsStatusQuery + "=OBJECT_STATUS_SEALED&" +
+				ListObjectsSortByQuery + "=create_time&" + ListObjectsSortDescQuery + "=true",
+			shouldMatch:      true,
+			wantedRouterName: listObjectsByBucketRouterName,
+		},
 		{
 			name:             "Get user buckets router",
 			router:           gwRouter,
@@ -225,6 +252,78 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: getBucketMetaRouterName,
 		},
+		{
+			name:             "Get bucket stats router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + bucketName + "." + testDomain + "?" + GetBucketStatsQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketStatsRouterName,
+		},
+		{
+			name:             "Get bucket stats router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + GetBucketStatsQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketStatsRouterName,
+		},
+		{
+			name:             "Get account stats router",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/?" + GetAccountStatsQuery,
+			shouldMatch:      true,
+			wantedRouterName: getAccountStatsRouterName,
+		},
+		{
+			name:             "Get bucket change events router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + bucketName + "." + testDomain + "?" + ChangeEventsQuery + "&" + ChangeEventsCursorQuery + "=10",
+			shouldMatch:      true,
+			wantedRouterName: getBucketChangeEventsRouterName,
+		},
+		{
+			name:             "Get bucket change events router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + ChangeEventsQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketChangeEventsRouterName,
+		},
+		{
+			name:             "Get change events router",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/?" + ChangeEventsQuery,
+			shouldMatch:      true,
+			wantedRouterName: getChangeEventsRouterName,
+		},
+		{
+			name:             "Put bucket webhook router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodPut,
+			url:              scheme + bucketName + "." + testDomain + "?" + WebhookQuery,
+			shouldMatch:      true,
+			wantedRouterName: putBucketWebhookRouterName,
+		},
+		{
+			name:             "List bucket webhooks router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + WebhookQuery,
+			shouldMatch:      true,
+			wantedRouterName: listBucketWebhooksRouterName,
+		},
+		{
+			name:             "Delete bucket webhook router, path style",
+			router:           gwRouter,
+			method:           http.MethodDelete,
+			url:              scheme + testDomain + "/" + bucketName + "?" + WebhookQuery + "&" + WebhookSubscriptionIDQuery + "=1",
+			shouldMatch:      true,
+			wantedRouterName: deleteBucketWebhookRouterName,
+		},
 		{
 			name:             "Challenge router",
 			router:           gwRouter,
@@ -241,6 +340,14 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: replicateObjectPieceRouterName,
 		},
+		{
+			name:             "Discard replicate router",
+			router:           gwRouter,
+			method:           http.MethodDelete,
+			url:              scheme + testDomain + ReplicateObjectPiecePath,
+			shouldMatch:      true,
+			wantedRouterName: discardObjectPieceRouterName,
+		},
 		{
 			name:             "Recovery router",
 			router:           gwRouter,
@@ -0,0 +1,302 @@
+package gater
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	// MaxWebhookSubscriptionsPerBucket defines the max number of the webhook subscriptions of a bucket.
+	MaxWebhookSubscriptionsPerBucket = 10
+	// MaxWebhookRequestBodySize defines the max size of the put webhook request body.
+	MaxWebhookRequestBodySize = 16 * 1024
+	// DefaultWebhookQuotaThreshold defines the default percentage of the consumed read quota
+	// to trigger the bucket quota event.
+	DefaultWebhookQuotaThreshold = 90
+	// webhookSecretLength defines the bytes of the generated secret if it is not specified.
+	webhookSecretLength = 32
+)
+
+// webhookEvents defines the events that can be subscribed.
+var webhookEvents = map[string]bool{
+	spdb.WebhookEventObjectSealed:             true,
+	spdb.WebhookEventObjectSealRejected:       true,
+	spdb.WebhookEventObjectDeleted:            true,
+	spdb.WebhookEventBucketQuotaNearExhausted: true,
+}
+
+// WebhookSubscription defines the json body of putting and listing the bucket webhooks, the
+// secret is only returned in the response of putting webhook.
+type WebhookSubscription struct {
+	SubscriptionID string   `json:"subscription_id,omitempty"`
+	BucketName     string   `json:"bucket_name,omitempty"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	Secret         string   `json:"secret,omitempty"`
+	QuotaThreshold uint32   `json:"quota_threshold,omitempty"`
+	CreateTime     int64    `json:"create_time,omitempty"`
+}
+
+// validate checks the url and events, and fills the default quota threshold. The url must be
+// https and its host must resolve to the public addresses, so the deliveries from the sp can
+// not reach the services inside the sp network.
+func (s *WebhookSubscription) validate(ctx context.Context) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("invalid webhook url %s", s.URL)
+	}
+	if err = util.CheckPublicHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+	if len(s.Events) == 0 {
+		return errors.New("no webhook event")
+	}
+	for _, event := range s.Events {
+		if !webhookEvents[event] {
+			return fmt.Errorf("unknown webhook event %s", event)
+		}
+	}
+	if s.QuotaThreshold == 0 {
+		s.QuotaThreshold = DefaultWebhookQuotaThreshold
+	}
+	if s.QuotaThreshold > 100 {
+		return fmt.Errorf("invalid quota threshold %d", s.QuotaThreshold)
+	}
+	return nil
+}
+
+// checkBucketOwner returns the bucket info if the request is signed by the owner of the bucket
+// and the bucket is served by the sp.
+func (g *GateModular) checkBucketOwner(reqCtx *RequestContext) (*storagetypes.BucketInfo, error) {
+	if reqCtx.Account() == "" {
+		return nil, ErrNoPermission
+	}
+	bucketInfo, err := g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+		return nil, ErrConsensus
+	}
+	if !strings.EqualFold(bucketInfo.GetOwner(), reqCtx.Account()) {
+		log.CtxErrorw(reqCtx.Context(), "only the bucket owner can operate webhooks", "owner", bucketInfo.GetOwner())
+		return nil, ErrNoPermission
+	}
+	if !strings.EqualFold(bucketInfo.GetPrimarySpAddress(), g.baseApp.OperatorAddress()) {
+		log.CtxErrorw(reqCtx.Context(), "the bucket is not served by the sp", "primary_sp", bucketInfo.GetPrimarySpAddress())
+		return nil, ErrMismatchSp
+	}
+	return bucketInfo, nil
+}
+
+// putBucketWebhookHandler handles the put bucket webhook request, it subscribes the events of
+// the bucket and returns the subscription with the secret to verify the deliveries.
+func (g *GateModular) putBucketWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err            error
+		reqCtx         *RequestContext
+		bucketInfo     *storagetypes.BucketInfo
+		body           []byte
+		subs           []*spdb.WebhookSubscription
+		subscriptionID uint64
+		sub            = &WebhookSubscription{}
+	)
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if bucketInfo, err = g.checkBucketOwner(reqCtx); err != nil {
+		return
+	}
+	if body, err = io.ReadAll(io.LimitReader(r.Body, MaxWebhookRequestBodySize)); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to read webhook request body", "error", err)
+		err = ErrExceptionStream
+		return
+	}
+	if err = json.Unmarshal(body, sub); err == nil {
+		err = sub.validate(reqCtx.Context())
+	}
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "invalid webhook subscription", "url", sub.URL, "events", sub.Events, "error", err)
+		err = ErrInvalidWebhook
+		return
+	}
+	if sub.Secret == "" {
+		secret := make([]byte, webhookSecretLength)
+		if _, err = rand.Read(secret); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to generate webhook secret", "error", err)
+			return
+		}
+		sub.Secret = hex.EncodeToString(secret)
+	}
+
+	if subs, err = g.baseApp.GfSpDB().ListWebhookSubscriptions(bucketInfo.Id.Uint64()); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to list webhook subscriptions", "error", err)
+		err = ErrGfSpDB
+		return
+	}
+	if len(subs) >= MaxWebhookSubscriptionsPerBucket {
+		err = ErrExceedWebhookLimit
+		return
+	}
+	subscriptionID, err = g.baseApp.GfSpDB().InsertWebhookSubscription(&spdb.WebhookSubscription{
+		BucketID:       bucketInfo.Id.Uint64(),
+		BucketName:     bucketInfo.GetBucketName(),
+		Owner:          bucketInfo.GetOwner(),
+		URL:            sub.URL,
+		Secret:         sub.Secret,
+		Events:         sub.Events,
+		QuotaThreshold: sub.QuotaThreshold,
+	})
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to insert webhook subscription", "error", err)
+		err = ErrGfSpDB
+		return
+	}
+	sub.SubscriptionID = util.Uint64ToString(subscriptionID)
+	sub.BucketName = bucketInfo.GetBucketName()
+	if body, err = json.Marshal(sub); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to marshal webhook subscription", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeJSONHeaderValue)
+	if _, err = w.Write(body); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to write body", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	log.CtxInfow(reqCtx.Context(), "succeed to put bucket webhook", "subscription_id", subscriptionID)
+}
+
+// listBucketWebhooksHandler handles the list bucket webhooks request, the secrets are omitted.
+func (g *GateModular) listBucketWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err        error
+		reqCtx     *RequestContext
+		bucketInfo *storagetypes.BucketInfo
+		subs       []*spdb.WebhookSubscription
+		body       []byte
+	)
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if bucketInfo, err = g.checkBucketOwner(reqCtx); err != nil {
+		return
+	}
+	if subs, err = g.baseApp.GfSpDB().ListWebhookSubscriptions(bucketInfo.Id.Uint64()); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to list webhook subscriptions", "error", err)
+		err = ErrGfSpDB
+		return
+	}
+	resp := make([]*WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, &WebhookSubscription{
+			SubscriptionID: util.Uint64ToString(sub.SubscriptionID),
+			BucketName:     sub.BucketName,
+			URL:            sub.URL,
+			Events:         sub.Events,
+			QuotaThreshold: sub.QuotaThreshold,
+			CreateTime:     sub.CreateTimestampSecond,
+		})
+	}
+	if body, err = json.Marshal(resp); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to marshal webhook subscriptions", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeJSONHeaderValue)
+	if _, err = w.Write(body); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to write body", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+}
+
+// deleteBucketWebhookHandler handles the delete bucket webhook request, the pending deliveries
+// of the subscription are dropped.
+func (g *GateModular) deleteBucketWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err            error
+		reqCtx         *RequestContext
+		bucketInfo     *storagetypes.BucketInfo
+		subscriptionID uint64
+		deleted        bool
+	)
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if subscriptionID, err = strconv.ParseUint(reqCtx.vars["subscription_id"], 10, 64); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to parse subscription id", "error", err)
+		err = ErrInvalidQuery
+		return
+	}
+	if bucketInfo, err = g.checkBucketOwner(reqCtx); err != nil {
+		return
+	}
+	if deleted, err = g.baseApp.GfSpDB().DeleteWebhookSubscription(bucketInfo.Id.Uint64(), subscriptionID); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to delete webhook subscription", "error", err)
+		err = ErrGfSpDB
+		return
+	}
+	if !deleted {
+		err = ErrNoSuchWebhook
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	log.CtxInfow(reqCtx.Context(), "succeed to delete bucket webhook", "subscription_id", subscriptionID)
+}
@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/cosmos/gogoproto/jsonpb"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	adminListTasksPath   = "/admin/v1/tasks"
+	adminControlTaskPath = "/admin/v1/tasks/{action}"
+)
+
+// serveAdminHTTP serves the admin api in http, the requests are handled by the same
+// handlers as grpc, the authorization header is passed as the grpc metadata.
+func (m *ManageModular) serveAdminHTTP() {
+	router := mux.NewRouter()
+	router.Path(adminListTasksPath).Methods(http.MethodGet).HandlerFunc(m.listTasksHandler)
+	router.Path(adminControlTaskPath).Methods(http.MethodPost).HandlerFunc(m.controlTaskHandler)
+	m.adminHTTPServer = &http.Server{
+		Addr:    m.adminHTTPAddress,
+		Handler: router,
+	}
+	go func() {
+		if err := m.adminHTTPServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorw("failed to listen and serve admin http", "error", err)
+		}
+	}()
+}
+
+func adminContext(r *http.Request) context.Context {
+	ctx := metadata.NewIncomingContext(r.Context(),
+		metadata.Pairs(gfspclient.AdminTokenMetadataKey, r.Header.Get("Authorization")))
+	// the remote address is recorded in the audit log as the grpc peer
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	return ctx
+}
+
+func (m *ManageModular) listTasksHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &gfspserver.GfSpListTasksRequest{
+		TaskType: query.Get("task_type"),
+		SubKey:   query.Get("sub_key"),
+	}
+	req.MinRetry, _ = strconv.ParseInt(query.Get("min_retry"), 10, 64)
+	req.OnlyTimeout, _ = strconv.ParseBool(query.Get("only_timeout"))
+	req.Limit, _ = strconv.ParseInt(query.Get("limit"), 10, 64)
+	resp, _ := m.baseApp.GfSpListTasks(adminContext(r), req)
+	writeAdminResponse(w, resp.GetErr(), resp)
+}
+
+func (m *ManageModular) controlTaskHandler(w http.ResponseWriter, r *http.Request) {
+	req := &gfspserver.GfSpControlTaskRequest{}
+	if r.ContentLength != 0 {
+		if err := jsonpb.Unmarshal(r.Body, req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	req.Action = mux.Vars(r)["action"]
+	resp, _ := m.baseApp.GfSpControlTask(adminContext(r), req)
+	writeAdminResponse(w, resp.GetErr(), resp)
+}
+
+func writeAdminResponse(w http.ResponseWriter, respErr *gfsperrors.GfSpError, resp proto.Message) {
+	var b bytes.Buffer
+	marshaler := jsonpb.Marshaler{EmitDefaults: true, OrigName: true, EnumsAsInts: true}
+	if err := marshaler.Marshal(&b, resp); err != nil {
+		log.Errorw("failed to marshal admin response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if respErr != nil && respErr.GetHttpStatusCode() != 0 {
+		w.WriteHeader(int(respErr.GetHttpStatusCode()))
+	}
+	w.Write(b.Bytes())
+}
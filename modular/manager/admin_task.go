@@ -0,0 +1,315 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/types"
+)
+
+var (
+	ErrTaskNotFound     = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60009, "task not found")
+	ErrInvalidTaskType  = gfsperrors.Register(module.ManageModularName, http.StatusBadRequest, 60010, "invalid task type")
+	ErrTaskNotRetryable = gfsperrors.Register(module.ManageModularName, http.StatusNotAcceptable, 60012,
+		"only the failed replicate piece and seal object tasks of the created object can be retried")
+)
+
+const (
+	// DefaultListTaskLimit defines the default max number of tasks returned by listing.
+	DefaultListTaskLimit = 100
+	// AdminCanceledDescription defines the error description of the upload progress that
+	// is canceled by admin.
+	AdminCanceledDescription = "canceled by admin"
+	// CanceledTaskKeepTime defines the seconds that the key of the canceled task is kept, the
+	// result of the canceled task reported in this time is dropped.
+	CanceledTaskKeepTime = 3600
+)
+
+// adminQueue is the common part of the task queues that the admin operations work on.
+type adminQueue interface {
+	PopByKey(task.TKey) task.Task
+	ScanTask(func(task.Task))
+	UpdateTask(task.TKey, func(task.Task)) bool
+}
+
+// adminQueues returns all the task queues that hold on manager.
+func (m *ManageModular) adminQueues() []adminQueue {
+	return []adminQueue{m.uploadQueue, m.resumeableUploadQueue, m.replicateQueue, m.sealQueue,
+		m.receiveQueue, m.gcObjectQueue, m.gcZombieQueue, m.gcMetaQueue, m.downloadQueue,
+		m.challengeQueue, m.recoveryQueue}
+}
+
+// dispatchTaskTypes returns the types of tasks that are dispatched to TaskExecutor, only
+// these types can be paused.
+func dispatchTaskTypes() []task.TType {
+	return []task.TType{task.TypeTaskReplicatePiece, task.TypeTaskSealObject, task.TypeTaskGCObject,
+		task.TypeTaskGCZombiePiece, task.TypeTaskGCMeta, task.TypeTaskReceivePiece, task.TypeTaskRecoverPiece}
+}
+
+func (m *ManageModular) ListTasks(ctx context.Context, filter *module.TaskFilter) ([]task.Task, error) {
+	if filter == nil {
+		filter = &module.TaskFilter{}
+	}
+	var tasks []task.Task
+	scan := func(t task.Task) {
+		if filter.TaskType != task.TypeTaskUnknown && t.Type() != filter.TaskType {
+			return
+		}
+		if filter.SubKey != "" && !strings.Contains(t.Key().String(), filter.SubKey.String()) {
+			return
+		}
+		if t.GetRetry() < filter.MinRetry {
+			return
+		}
+		if filter.OnlyTimeout && !t.ExceedTimeout() {
+			return
+		}
+		tasks = append(tasks, t)
+	}
+	for _, queue := range m.adminQueues() {
+		queue.ScanTask(scan)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].GetCreateTime() < tasks[j].GetCreateTime()
+	})
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultListTaskLimit
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+// findTask returns the task and the queue that holds it.
+func (m *ManageModular) findTask(key task.TKey) (task.Task, adminQueue) {
+	for _, queue := range m.adminQueues() {
+		var found task.Task
+		queue.ScanTask(func(t task.Task) {
+			if t.Key() == key {
+				found = t
+			}
+		})
+		if found != nil {
+			return found, queue
+		}
+	}
+	return nil, nil
+}
+
+func (m *ManageModular) CancelTask(ctx context.Context, key task.TKey) error {
+	t, queue := m.findTask(key)
+	if t == nil {
+		return ErrTaskNotFound
+	}
+	m.markCanceledTask(key)
+	if queue.PopByKey(key) == nil {
+		m.takeCanceledTask(key)
+		return ErrTaskNotFound
+	}
+	if t.Type() == task.TypeTaskRecoverPiece {
+		m.doneRecoverSpTask(key, false)
+	}
+	var state types.TaskState
+	switch t.Type() {
+	case task.TypeTaskUpload:
+		state = types.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR
+	case task.TypeTaskReplicatePiece:
+		state = types.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR
+	case task.TypeTaskSealObject:
+		state = types.TaskState_TASK_STATE_SEAL_OBJECT_ERROR
+	}
+	if objectTask, ok := t.(task.ObjectTask); ok && state != types.TaskState_TASK_STATE_INIT_UNSPECIFIED {
+		if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+			ObjectID:         objectTask.GetObjectInfo().Id.Uint64(),
+			TaskState:        state,
+			ErrorDescription: AdminCanceledDescription,
+		}); err != nil {
+			log.CtxErrorw(ctx, "failed to update canceled task state", "task_key", key.String(), "error", err)
+			return ErrGfSpDB
+		}
+	}
+	if replicateTask, ok := t.(task.ReplicatePieceTask); ok {
+		if err := m.baseApp.GfSpDB().DeleteAllReplicatePieceProgress(replicateTask.GetObjectInfo().Id.Uint64()); err != nil {
+			log.CtxWarnw(ctx, "failed to delete replicate piece progress", "task_key", key.String(), "error", err)
+		}
+	}
+	log.CtxInfow(ctx, "succeed to cancel task by admin", "task_info", t.Info())
+	return nil
+}
+
+// markCanceledTask records the key of the task that is canceled by admin, and forgets the
+// keys that are kept longer than CanceledTaskKeepTime.
+func (m *ManageModular) markCanceledTask(key task.TKey) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	now := time.Now().Unix()
+	for canceledKey, cancelTime := range m.canceledTasks {
+		if now-cancelTime > CanceledTaskKeepTime {
+			delete(m.canceledTasks, canceledKey)
+		}
+	}
+	m.canceledTasks[key] = now
+}
+
+// takeCanceledTask returns whether the task is canceled by admin and forgets the key, the
+// task that is missing in the queue for other reasons, e.g. retired by the queue gc or lost
+// by restarting, is not canceled.
+func (m *ManageModular) takeCanceledTask(key task.TKey) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if _, ok := m.canceledTasks[key]; !ok {
+		return false
+	}
+	delete(m.canceledTasks, key)
+	return true
+}
+
+// updateTask changes the task of the key while holding the lock of the queue that holds it,
+// returns the task info after changing.
+func (m *ManageModular) updateTask(key task.TKey, update func(task.Task)) (string, error) {
+	var info string
+	for _, queue := range m.adminQueues() {
+		if queue.UpdateTask(key, func(t task.Task) {
+			update(t)
+			info = t.Info()
+		}) {
+			return info, nil
+		}
+	}
+	return "", ErrTaskNotFound
+}
+
+// RetryTask resets the retry counter of the task in the queue, the failed or expired replicate
+// piece and seal object tasks that have been removed from the queue are generated again by the
+// upload progress. The failed upload task can not be retried, the data is uploaded by the user.
+func (m *ManageModular) RetryTask(ctx context.Context, key task.TKey) error {
+	info, err := m.updateTask(key, func(t task.Task) {
+		t.SetRetry(0)
+		t.SetUpdateTime(time.Now().Unix())
+	})
+	if err == ErrTaskNotFound {
+		info, err = m.regenerateFailedTask(ctx, key)
+	}
+	if err != nil {
+		return err
+	}
+	log.CtxInfow(ctx, "succeed to retry task by admin", "task_info", info)
+	return nil
+}
+
+// regenerateFailedTask generates the replicate piece or seal object task of the key again by
+// the upload progress of the object, and pushes it into the queue.
+func (m *ManageModular) regenerateFailedTask(ctx context.Context, key task.TKey) (string, error) {
+	objectID, ok := parseUploadingTaskObjectID(key)
+	if !ok {
+		return "", ErrTaskNotFound
+	}
+	meta, err := m.baseApp.GfSpDB().GetUploadMeta(objectID)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get upload progress", "task_key", key.String(), "error", err)
+		return "", ErrTaskNotFound
+	}
+	var (
+		newTask task.Task
+		queue   interface{ Push(task.Task) error }
+	)
+	switch meta.TaskState {
+	case types.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR:
+		newTask, err = m.newReplicateTaskFromMeta(ctx, meta)
+		queue = m.replicateQueue
+	case types.TaskState_TASK_STATE_SEAL_OBJECT_ERROR:
+		newTask, err = m.newSealTaskFromMeta(ctx, meta)
+		queue = m.sealQueue
+	default:
+		return "", ErrTaskNotRetryable
+	}
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to generate the failed task", "task_key", key.String(), "error", err)
+		return "", ErrTaskNotRetryable
+	}
+	if newTask.Key() != key {
+		return "", ErrTaskNotFound
+	}
+	// the result of the canceled task is no longer dropped once it is retried
+	m.takeCanceledTask(key)
+	if err = queue.Push(newTask); err != nil {
+		log.CtxErrorw(ctx, "failed to push the failed task", "task_key", key.String(), "error", err)
+		return "", err
+	}
+	return newTask.Info(), nil
+}
+
+// parseUploadingTaskObjectID returns the object id of the key of the uploading tasks, the id is
+// the last field of the key because the bucket and object names may contain the delimiter.
+func parseUploadingTaskObjectID(key task.TKey) (uint64, bool) {
+	prefix := gfsptask.KeyPrefixGfSpReplicatePieceTask + gfsptask.Delimiter + "bucket:"
+	if !strings.HasPrefix(key.String(), prefix) {
+		return 0, false
+	}
+	idx := strings.LastIndex(key.String(), gfsptask.Delimiter+"id:")
+	if idx < 0 {
+		return 0, false
+	}
+	objectID, err := strconv.ParseUint(key.String()[idx+len(gfsptask.Delimiter+"id:"):], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return objectID, true
+}
+
+func (m *ManageModular) SetTaskPriority(ctx context.Context, key task.TKey, priority task.TPriority) error {
+	info, err := m.updateTask(key, func(t task.Task) {
+		t.SetPriority(priority)
+	})
+	if err != nil {
+		return err
+	}
+	log.CtxInfow(ctx, "succeed to set task priority by admin", "task_info", info)
+	return nil
+}
+
+func (m *ManageModular) PauseTaskDispatch(ctx context.Context, taskType task.TType, pause bool) error {
+	valid := false
+	for _, dispatchType := range dispatchTaskTypes() {
+		if dispatchType == taskType {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrInvalidTaskType
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if pause {
+		m.pausedTaskTypes[taskType] = struct{}{}
+	} else {
+		delete(m.pausedTaskTypes, taskType)
+	}
+	log.CtxInfow(ctx, "succeed to change task dispatch by admin", "task_type", task.TaskTypeName(taskType),
+		"pause", pause)
+	return nil
+}
+
+func (m *ManageModular) PausedTaskTypes(ctx context.Context) []task.TType {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	paused := make([]task.TType, 0, len(m.pausedTaskTypes))
+	for taskType := range m.pausedTaskTypes {
+		paused = append(paused, taskType)
+	}
+	sort.Slice(paused, func(i, j int) bool { return paused[i] < paused[j] })
+	return paused
+}
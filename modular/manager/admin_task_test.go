@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/store/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func setupAdminManager(t *testing.T) (*ManageModular, *spdb.MockSPDB) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	app := &gfspapp.GfSpBaseApp{}
+	require.NoError(t, gfspapp.DefaultGfSpDBOption(app, &gfspconfig.GfSpConfig{
+		Customize: &gfspconfig.Customize{GfSpDB: db},
+	}))
+	m := &ManageModular{
+		baseApp:               app,
+		uploadQueue:           gfsptqueue.NewGfSpTQueue("upload", 10),
+		resumeableUploadQueue: gfsptqueue.NewGfSpTQueue("resumable-upload", 10),
+		replicateQueue:        gfsptqueue.NewGfSpTQueueWithLimit("replicate", 10),
+		sealQueue:             gfsptqueue.NewGfSpTQueueWithLimit("seal", 10),
+		receiveQueue:          gfsptqueue.NewGfSpTQueueWithLimit("receive", 10),
+		gcObjectQueue:         gfsptqueue.NewGfSpTQueueWithLimit("gc-object", 10),
+		gcZombieQueue:         gfsptqueue.NewGfSpTQueueWithLimit("gc-zombie", 10),
+		gcMetaQueue:           gfsptqueue.NewGfSpTQueueWithLimit("gc-meta", 10),
+		downloadQueue:         gfsptqueue.NewGfSpTQueue("download", 10),
+		challengeQueue:        gfsptqueue.NewGfSpTQueue("challenge", 10),
+		recoveryQueue:         gfsptqueue.NewGfSpTQueueWithLimit("recovery", 10),
+		pausedTaskTypes:       make(map[task.TType]struct{}),
+		canceledTasks:         make(map[task.TKey]int64),
+	}
+	return m, db
+}
+
+func mockAdminObject(id uint64, name string) (*storagetypes.ObjectInfo, *storagetypes.Params) {
+	return &storagetypes.ObjectInfo{
+		Id:         sdkmath.NewUint(id),
+		BucketName: "mock-bucket",
+		ObjectName: name,
+	}, &storagetypes.Params{}
+}
+
+func mockUploadTask(id uint64, name string) *gfsptask.GfSpUploadObjectTask {
+	object, params := mockAdminObject(id, name)
+	uploadTask := &gfsptask.GfSpUploadObjectTask{}
+	uploadTask.InitUploadObjectTask(object, params, 100)
+	return uploadTask
+}
+
+func mockReplicateTask(id uint64, name string) *gfsptask.GfSpReplicatePieceTask {
+	object, params := mockAdminObject(id, name)
+	replicateTask := &gfsptask.GfSpReplicatePieceTask{}
+	replicateTask.InitReplicatePieceTask(object, params, 1, 100, 3)
+	return replicateTask
+}
+
+func TestManageModular_ListTasks(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	uploadTask := mockUploadTask(1, "object1")
+	replicateTask := mockReplicateTask(2, "object2")
+	replicateTask.SetRetry(2)
+	require.NoError(t, m.uploadQueue.Push(uploadTask))
+	require.NoError(t, m.replicateQueue.Push(replicateTask))
+
+	cases := []struct {
+		name   string
+		filter *module.TaskFilter
+		want   []task.TKey
+	}{
+		{name: "nil filter", filter: nil, want: []task.TKey{uploadTask.Key(), replicateTask.Key()}},
+		{name: "task type", filter: &module.TaskFilter{TaskType: task.TypeTaskReplicatePiece},
+			want: []task.TKey{replicateTask.Key()}},
+		{name: "sub key", filter: &module.TaskFilter{SubKey: "object1"}, want: []task.TKey{uploadTask.Key()}},
+		{name: "min retry", filter: &module.TaskFilter{MinRetry: 1}, want: []task.TKey{replicateTask.Key()}},
+		{name: "limit", filter: &module.TaskFilter{Limit: 1}, want: []task.TKey{uploadTask.Key()}},
+		{name: "no match", filter: &module.TaskFilter{TaskType: task.TypeTaskSealObject}, want: nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tasks, err := m.ListTasks(context.Background(), c.filter)
+			require.NoError(t, err)
+			var keys []task.TKey
+			for _, listed := range tasks {
+				keys = append(keys, listed.Key())
+			}
+			require.ElementsMatch(t, c.want, keys)
+		})
+	}
+}
+
+func TestManageModular_CancelTask(t *testing.T) {
+	m, db := setupAdminManager(t)
+	ctx := context.Background()
+	require.Equal(t, ErrTaskNotFound, m.CancelTask(ctx, "unknown"))
+
+	uploadTask := mockUploadTask(1, "object1")
+	require.NoError(t, m.uploadQueue.Push(uploadTask))
+	db.EXPECT().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		ObjectID:         1,
+		TaskState:        types.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR,
+		ErrorDescription: AdminCanceledDescription,
+	}).Return(nil)
+	require.NoError(t, m.CancelTask(ctx, uploadTask.Key()))
+	require.False(t, m.uploadQueue.Has(uploadTask.Key()))
+	require.Equal(t, ErrTaskNotFound, m.CancelTask(ctx, uploadTask.Key()))
+
+	// the success reported by the executor after canceling is dropped
+	require.Equal(t, ErrCanceledTask, m.HandleDoneUploadObjectTask(ctx, uploadTask))
+	require.Equal(t, 0, m.replicateQueue.Len())
+
+	replicateTask := mockReplicateTask(2, "object2")
+	require.NoError(t, m.replicateQueue.Push(replicateTask))
+	db.EXPECT().UpdateUploadProgress(gomock.Any()).Return(nil)
+	db.EXPECT().DeleteAllReplicatePieceProgress(uint64(2)).Return(nil)
+	require.NoError(t, m.CancelTask(ctx, replicateTask.Key()))
+	require.Equal(t, ErrCanceledTask, m.HandleReplicatePieceTask(ctx, replicateTask))
+	require.Equal(t, 0, m.sealQueue.Len())
+
+	sealTask := &gfsptask.GfSpSealObjectTask{}
+	object, params := mockAdminObject(3, "object3")
+	sealTask.InitSealObjectTask(object, params, 1, nil, nil, 100, 3)
+	require.NoError(t, m.sealQueue.Push(sealTask))
+	db.EXPECT().UpdateUploadProgress(gomock.Any()).Return(nil)
+	require.NoError(t, m.CancelTask(ctx, sealTask.Key()))
+	require.Equal(t, ErrCanceledTask, m.HandleSealObjectTask(ctx, sealTask))
+
+	// the result of the task that is not in the queue but not canceled is still handled
+	db.EXPECT().UpdateUploadProgress(gomock.Any()).Return(nil).AnyTimes()
+	require.NoError(t, m.HandleDoneUploadObjectTask(ctx, uploadTask))
+	require.Equal(t, 1, m.replicateQueue.Len())
+	require.NoError(t, m.HandleSealObjectTask(ctx, sealTask))
+}
+
+func TestManageModular_RetryTaskAndSetTaskPriority(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	ctx := context.Background()
+	require.Equal(t, ErrTaskNotFound, m.RetryTask(ctx, "unknown"))
+	require.Equal(t, ErrTaskNotFound, m.SetTaskPriority(ctx, "unknown", 1))
+
+	replicateTask := mockReplicateTask(1, "object1")
+	replicateTask.SetRetry(3)
+	replicateTask.SetUpdateTime(0)
+	require.NoError(t, m.replicateQueue.Push(replicateTask))
+
+	require.NoError(t, m.RetryTask(ctx, replicateTask.Key()))
+	require.Equal(t, int64(0), replicateTask.GetRetry())
+	require.NotZero(t, replicateTask.GetUpdateTime())
+
+	require.NoError(t, m.SetTaskPriority(ctx, replicateTask.Key(), 200))
+	require.Equal(t, task.TPriority(200), replicateTask.GetPriority())
+}
+
+type mockRetryConsensus struct {
+	consensus.NullConsensus
+}
+
+func (*mockRetryConsensus) QueryObjectInfoByID(_ context.Context, objectID string) (*storagetypes.ObjectInfo, error) {
+	object, _ := mockAdminObject(sdkmath.NewUintFromString(objectID).Uint64(), "object"+objectID)
+	object.ObjectStatus = storagetypes.OBJECT_STATUS_CREATED
+	return object, nil
+}
+
+func (*mockRetryConsensus) QueryStorageParamsByTimestamp(context.Context, int64) (*storagetypes.Params, error) {
+	return &storagetypes.Params{}, nil
+}
+
+func TestManageModular_RetryFailedTask(t *testing.T) {
+	m, db := setupAdminManager(t)
+	require.NoError(t, gfspapp.DefaultGfSpConsensusOption(m.baseApp, &gfspconfig.GfSpConfig{
+		Customize: &gfspconfig.Customize{Consensus: &mockRetryConsensus{}},
+	}))
+	ctx := context.Background()
+
+	// the failed replicate task is generated again by the upload progress
+	replicateTask := mockReplicateTask(1, "object1")
+	db.EXPECT().GetUploadMeta(uint64(1)).Return(&spdb.UploadObjectMeta{
+		ObjectID: 1, TaskState: types.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR}, nil)
+	require.NoError(t, m.RetryTask(ctx, replicateTask.Key()))
+	require.True(t, m.replicateQueue.Has(replicateTask.Key()))
+
+	// the failed seal task keeps the secondary sps of the upload progress
+	sealKey := gfsptask.GfSpSealObjectTaskKey("mock-bucket", "object2", "2")
+	db.EXPECT().GetUploadMeta(uint64(2)).Return(&spdb.UploadObjectMeta{
+		ObjectID: 2, TaskState: types.TaskState_TASK_STATE_SEAL_OBJECT_ERROR, SecondaryAddresses: []string{"sp"}}, nil)
+	require.NoError(t, m.RetryTask(ctx, sealKey))
+	require.True(t, m.sealQueue.Has(sealKey))
+
+	// the failed upload task can not be retried
+	uploadTask := mockUploadTask(3, "object3")
+	db.EXPECT().GetUploadMeta(uint64(3)).Return(&spdb.UploadObjectMeta{
+		ObjectID: 3, TaskState: types.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR}, nil)
+	require.Equal(t, ErrTaskNotRetryable, m.RetryTask(ctx, uploadTask.Key()))
+
+	// the key of other tasks is not found
+	require.Equal(t, ErrTaskNotFound, m.RetryTask(ctx, "Uploading-bucket:mock-bucket-object:object4-id:x"))
+}
+
+func TestManageModular_PickUpTaskOfZeroPriority(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	ctx := context.Background()
+	task1, task2 := mockReplicateTask(1, "object1"), mockReplicateTask(2, "object2")
+	task1.SetPriority(task.UnSchedulingPriority)
+	task2.SetPriority(task.UnSchedulingPriority)
+	require.Nil(t, m.PickUpTask(ctx, []task.Task{task1, task2}))
+
+	task2.SetPriority(task.DefaultSmallerPriority)
+	require.Equal(t, task2.Key(), m.PickUpTask(ctx, []task.Task{task1, task2}).Key())
+}
+
+func TestManageModular_PauseTaskDispatch(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	ctx := context.Background()
+	require.Equal(t, ErrInvalidTaskType, m.PauseTaskDispatch(ctx, task.TypeTaskUpload, true))
+
+	require.NoError(t, m.PauseTaskDispatch(ctx, task.TypeTaskSealObject, true))
+	require.NoError(t, m.PauseTaskDispatch(ctx, task.TypeTaskReplicatePiece, true))
+	require.Equal(t, []task.TType{task.TypeTaskReplicatePiece, task.TypeTaskSealObject}, m.PausedTaskTypes(ctx))
+
+	require.NoError(t, m.PauseTaskDispatch(ctx, task.TypeTaskSealObject, false))
+	require.Equal(t, []task.TType{task.TypeTaskReplicatePiece}, m.PausedTaskTypes(ctx))
+}
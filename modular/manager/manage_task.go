@@ -2,8 +2,9 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
@@ -15,6 +16,7 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/store/sqldb"
 	"github.com/bnb-chain/greenfield-storage-provider/store/types"
 )
 
@@ -24,65 +26,46 @@ var (
 	ErrExceedTask    = gfsperrors.Register(module.ManageModularName, http.StatusNotAcceptable, 60003, "OoooH... request exceed, try again later")
 	ErrCanceledTask  = gfsperrors.Register(module.ManageModularName, http.StatusBadRequest, 60004, "task canceled")
 	ErrFutureSupport = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60005, "future support")
+	ErrDraining      = gfsperrors.Register(module.ManageModularName, http.StatusServiceUnavailable, 60013, "manager is draining, try again later")
 	ErrGfSpDB        = gfsperrors.Register(module.DownloadModularName, http.StatusInternalServerError, 65201, "server slipped away, try again later")
 )
 
 func (m *ManageModular) DispatchTask(ctx context.Context, limit rcmgr.Limit) (task.Task, error) {
-	var (
-		backupTasks []task.Task
-		task        task.Task
-	)
+	if m.draining() {
+		log.CtxDebugw(ctx, "manager is draining, stop dispatching task")
+		return nil, nil
+	}
+	var backupTasks []task.Task
 	m.mux.Lock()
 	defer m.mux.Unlock()
-	task = m.replicateQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add replicate piece task to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-	task = m.sealQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add seal object task to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-	task = m.gcObjectQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add gc object task to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-	task = m.gcZombieQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add gc zombie piece task to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-	task = m.gcMetaQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add gc meta task to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-	task = m.receiveQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add confirm receive piece to backup set", "task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-
-	task = m.recoveryQueue.TopByLimit(limit)
-	if task != nil {
-		log.CtxDebugw(ctx, "add confirm recovery piece to backup set", "recovery task_key", task.Key().String(),
-			"task_limit", task.EstimateLimit().String())
-		backupTasks = append(backupTasks, task)
-	}
-
-	task = m.PickUpTask(ctx, backupTasks)
-	if task == nil {
+	for _, dispatch := range []struct {
+		taskType task.TType
+		queue    taskqueue.TQueueOnStrategyWithLimit
+		name     string
+	}{
+		{task.TypeTaskReplicatePiece, m.replicateQueue, "replicate piece task"},
+		{task.TypeTaskSealObject, m.sealQueue, "seal object task"},
+		{task.TypeTaskGCObject, m.gcObjectQueue, "gc object task"},
+		{task.TypeTaskGCZombiePiece, m.gcZombieQueue, "gc zombie piece task"},
+		{task.TypeTaskGCMeta, m.gcMetaQueue, "gc meta task"},
+		{task.TypeTaskReceivePiece, m.receiveQueue, "confirm receive piece"},
+		{task.TypeTaskRecoverPiece, m.recoveryQueue, "confirm recovery piece"},
+	} {
+		if _, paused := m.pausedTaskTypes[dispatch.taskType]; paused {
+			continue
+		}
+		backupTask := dispatch.queue.TopByLimit(limit)
+		if backupTask != nil {
+			log.CtxDebugw(ctx, "add "+dispatch.name+" to backup set", "task_key", backupTask.Key().String(),
+				"task_limit", backupTask.EstimateLimit().String())
+			backupTasks = append(backupTasks, backupTask)
+		}
+	}
+	dispatchTask := m.PickUpTask(ctx, backupTasks)
+	if dispatchTask == nil {
 		return nil, nil
 	}
-	return task, nil
+	return dispatchTask, nil
 }
 
 func (m *ManageModular) HandleCreateUploadObjectTask(ctx context.Context, task task.UploadObjectTask) error {
@@ -90,7 +73,11 @@ func (m *ManageModular) HandleCreateUploadObjectTask(ctx context.Context, task t
 		log.CtxErrorw(ctx, "failed to handle begin upload object due to task pointer dangling")
 		return ErrDanglingTask
 	}
-	if m.UploadingObjectNumber() >= m.maxUploadObjectNumber {
+	if m.draining() {
+		log.CtxErrorw(ctx, "failed to handle begin upload object due to draining", "task_info", task.Info())
+		return ErrDraining
+	}
+	if int64(m.UploadingObjectNumber()) >= atomic.LoadInt64(&m.maxUploadObjectNumber) {
 		log.CtxErrorw(ctx, "uploading object exceed", "uploading", m.uploadQueue.Len(),
 			"replicating", m.replicateQueue.Len(), "sealing", m.sealQueue.Len())
 		return ErrExceedTask
@@ -103,7 +90,7 @@ func (m *ManageModular) HandleCreateUploadObjectTask(ctx context.Context, task t
 		log.CtxErrorw(ctx, "failed to push upload object task to queue", "task_info", task.Info(), "error", err)
 		return err
 	}
-	if err := m.baseApp.GfSpDB().InsertUploadProgress(task.GetObjectInfo().Id.Uint64()); err != nil {
+	if err := m.baseApp.GfSpDBWithContext(ctx).InsertUploadProgress(task.GetObjectInfo().Id.Uint64()); err != nil {
 		log.CtxErrorw(ctx, "failed to create upload object progress", "task_info", task.Info(), "error", err)
 		return ErrGfSpDB
 	}
@@ -116,6 +103,7 @@ func (m *ManageModular) HandleDoneUploadObjectTask(ctx context.Context, task tas
 		return ErrDanglingTask
 	}
 	m.uploadQueue.PopByKey(task.Key())
+	canceled := m.takeCanceledTask(task.Key())
 
 	startCheckUploadingTime := time.Now()
 	uploading := m.TaskUploading(ctx, task)
@@ -128,7 +116,7 @@ func (m *ManageModular) HandleDoneUploadObjectTask(ctx context.Context, task tas
 	if task.Error() != nil {
 		go func() error {
 			startUpdateSPDBTime := time.Now()
-			err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+			err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 				ObjectID:         task.GetObjectInfo().Id.Uint64(),
 				TaskState:        types.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR,
 				ErrorDescription: task.Error().Error(),
@@ -144,11 +132,20 @@ func (m *ManageModular) HandleDoneUploadObjectTask(ctx context.Context, task tas
 		}()
 		return nil
 	}
+	if canceled {
+		log.CtxErrorw(ctx, "task has been canceled", "task_info", task.Info())
+		return ErrCanceledTask
+	}
 	replicateTask := &gfsptask.GfSpReplicatePieceTask{}
 	replicateTask.InitReplicatePieceTask(task.GetObjectInfo(), task.GetStorageParams(),
 		m.baseApp.TaskPriority(replicateTask),
 		m.baseApp.TaskTimeout(replicateTask, task.GetObjectInfo().GetPayloadSize()),
 		m.baseApp.TaskMaxRetry(replicateTask))
+	replicateTask.SetTraceParent(task.GetTraceParent())
+	// the pieces replicated during uploading in pipelined mode are reused by replicate task
+	if uploadTask, ok := task.(*gfsptask.GfSpUploadObjectTask); ok {
+		replicateTask.SetSecondaryApprovals(uploadTask.GetSecondaryApprovals())
+	}
 
 	startPushReplicateQueueTime := time.Now()
 	err := m.replicateQueue.Push(replicateTask)
@@ -160,7 +157,7 @@ func (m *ManageModular) HandleDoneUploadObjectTask(ctx context.Context, task tas
 	}
 	go func() error {
 		startUpdateSPDBTime := time.Now()
-		err = m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		err = m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:  task.GetObjectInfo().Id.Uint64(),
 			TaskState: types.TaskState_TASK_STATE_REPLICATE_OBJECT_DOING,
 		})
@@ -181,7 +178,11 @@ func (m *ManageModular) HandleCreateResumableUploadObjectTask(ctx context.Contex
 		log.CtxErrorw(ctx, "failed to handle begin upload object due to task pointer dangling")
 		return ErrDanglingTask
 	}
-	if m.UploadingObjectNumber() >= m.maxUploadObjectNumber {
+	if m.draining() {
+		log.CtxErrorw(ctx, "failed to handle begin resumable upload object due to draining", "task_info", task.Info())
+		return ErrDraining
+	}
+	if int64(m.UploadingObjectNumber()) >= atomic.LoadInt64(&m.maxUploadObjectNumber) {
 		log.CtxErrorw(ctx, "uploading object exceed", "uploading", m.uploadQueue.Len(),
 			"replicating", m.replicateQueue.Len(), "sealing", m.sealQueue.Len(), "resumable uploading", m.resumeableUploadQueue.Len())
 		return ErrExceedTask
@@ -194,10 +195,9 @@ func (m *ManageModular) HandleCreateResumableUploadObjectTask(ctx context.Contex
 		log.CtxErrorw(ctx, "failed to push resumable upload object task to queue", "task_info", task.Info(), "error", err)
 		return err
 	}
-	if err := m.baseApp.GfSpDB().InsertUploadProgress(task.GetObjectInfo().Id.Uint64()); err != nil {
+	if err := m.baseApp.GfSpDBWithContext(ctx).InsertUploadProgress(task.GetObjectInfo().Id.Uint64()); err != nil {
 		log.CtxErrorw(ctx, "failed to create resumable upload object progress", "task_info", task.Info(), "error", err)
-		// TODO(chris)
-		if strings.Contains(err.Error(), "Duplicate entry") {
+		if errors.Is(err, sqldb.ErrDuplicateEntry) {
 			return nil
 		} else {
 			return ErrGfSpDB
@@ -212,6 +212,7 @@ func (m *ManageModular) HandleDoneResumableUploadObjectTask(ctx context.Context,
 		return ErrDanglingTask
 	}
 	m.resumeableUploadQueue.PopByKey(task.Key())
+	canceled := m.takeCanceledTask(task.Key())
 
 	startCheckUploadingTime := time.Now()
 	uploading := m.TaskUploading(ctx, task)
@@ -224,7 +225,7 @@ func (m *ManageModular) HandleDoneResumableUploadObjectTask(ctx context.Context,
 	if task.Error() != nil {
 		go func() error {
 			startUpdateSPDBTime := time.Now()
-			err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+			err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 				ObjectID:         task.GetObjectInfo().Id.Uint64(),
 				TaskState:        types.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR,
 				ErrorDescription: task.Error().Error(),
@@ -239,11 +240,16 @@ func (m *ManageModular) HandleDoneResumableUploadObjectTask(ctx context.Context,
 		}()
 		return nil
 	}
+	if canceled {
+		log.CtxErrorw(ctx, "task has been canceled", "task_info", task.Info())
+		return ErrCanceledTask
+	}
 	replicateTask := &gfsptask.GfSpReplicatePieceTask{}
 	replicateTask.InitReplicatePieceTask(task.GetObjectInfo(), task.GetStorageParams(),
 		m.baseApp.TaskPriority(replicateTask),
 		m.baseApp.TaskTimeout(replicateTask, task.GetObjectInfo().GetPayloadSize()),
 		m.baseApp.TaskMaxRetry(replicateTask))
+	replicateTask.SetTraceParent(task.GetTraceParent())
 
 	startPushReplicateQueueTime := time.Now()
 	err := m.replicateQueue.Push(replicateTask)
@@ -255,7 +261,7 @@ func (m *ManageModular) HandleDoneResumableUploadObjectTask(ctx context.Context,
 	}
 	go func() error {
 		startUpdateSPDBTime := time.Now()
-		err = m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		err = m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:  task.GetObjectInfo().Id.Uint64(),
 			TaskState: types.TaskState_TASK_STATE_REPLICATE_OBJECT_DOING,
 		})
@@ -282,15 +288,20 @@ func (m *ManageModular) HandleReplicatePieceTask(ctx context.Context, task task.
 		return nil
 	}
 	m.replicateQueue.PopByKey(task.Key())
+	canceled := m.takeCanceledTask(task.Key())
 	if m.TaskUploading(ctx, task) {
 		log.CtxErrorw(ctx, "replicate piece object task repeated")
 		return ErrRepeatedTask
 	}
+	if canceled {
+		log.CtxErrorw(ctx, "task has been canceled", "task_info", task.Info())
+		return ErrCanceledTask
+	}
 	if task.GetSealed() {
 		go func() error {
 			metrics.SealObjectSucceedCounter.WithLabelValues(m.Name()).Inc()
 			log.CtxDebugw(ctx, "replicate piece object task has combined seal object task")
-			if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+			if err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 				ObjectID:  task.GetObjectInfo().Id.Uint64(),
 				TaskState: types.TaskState_TASK_STATE_SEAL_OBJECT_DONE,
 			}); err != nil {
@@ -308,13 +319,14 @@ func (m *ManageModular) HandleReplicatePieceTask(ctx context.Context, task task.
 	sealObject.InitSealObjectTask(task.GetObjectInfo(), task.GetStorageParams(),
 		m.baseApp.TaskPriority(sealObject), task.GetSecondaryAddresses(), task.GetSecondarySignatures(),
 		m.baseApp.TaskTimeout(sealObject, 0), m.baseApp.TaskMaxRetry(sealObject))
+	sealObject.SetTraceParent(task.GetTraceParent())
 	err := m.sealQueue.Push(sealObject)
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to push seal object task to queue", "task_info", task.Info(), "error", err)
 		return err
 	}
 	go func() error {
-		if err = m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		if err = m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:            task.GetObjectInfo().Id.Uint64(),
 			TaskState:           types.TaskState_TASK_STATE_SEAL_OBJECT_DOING,
 			SecondaryAddresses:  task.GetSecondaryAddresses(),
@@ -346,7 +358,7 @@ func (m *ManageModular) handleFailedReplicatePieceTask(ctx context.Context, hand
 		err := m.replicateQueue.Push(handleTask)
 		log.CtxDebugw(ctx, "push task again to retry", "task_info", handleTask.Info(), "error", err)
 	} else {
-		if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		if err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:         handleTask.GetObjectInfo().Id.Uint64(),
 			TaskState:        types.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR,
 			ErrorDescription: "exceed_retry",
@@ -354,6 +366,9 @@ func (m *ManageModular) handleFailedReplicatePieceTask(ctx context.Context, hand
 			log.CtxErrorw(ctx, "failed to update object task state", "task_info", handleTask.Info(), "error", err)
 			return ErrGfSpDB
 		}
+		if err := m.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceProgress(handleTask.GetObjectInfo().Id.Uint64()); err != nil {
+			log.CtxWarnw(ctx, "failed to delete replicate piece progress", "task_info", handleTask.Info(), "error", err)
+		}
 		log.CtxWarnw(ctx, "delete expired replicate piece task", "task_info", handleTask.Info())
 	}
 	return nil
@@ -369,10 +384,14 @@ func (m *ManageModular) HandleSealObjectTask(ctx context.Context, task task.Seal
 		go m.handleFailedSealObjectTask(ctx, task)
 		return nil
 	}
+	m.sealQueue.PopByKey(task.Key())
+	if m.takeCanceledTask(task.Key()) {
+		log.CtxErrorw(ctx, "task has been canceled", "task_info", task.Info())
+		return ErrCanceledTask
+	}
 	go func() error {
 		metrics.SealObjectSucceedCounter.WithLabelValues(m.Name()).Inc()
-		m.sealQueue.PopByKey(task.Key())
-		if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		if err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:  task.GetObjectInfo().Id.Uint64(),
 			TaskState: types.TaskState_TASK_STATE_SEAL_OBJECT_DONE,
 		}); err != nil {
@@ -404,7 +423,7 @@ func (m *ManageModular) handleFailedSealObjectTask(ctx context.Context, handleTa
 		log.CtxDebugw(ctx, "push task again to retry", "task_info", handleTask.Info(), "error", err)
 		return nil
 	} else {
-		if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
+		if err := m.baseApp.GfSpDBWithContext(ctx).UpdateUploadProgress(&spdb.UploadObjectMeta{
 			ObjectID:         handleTask.GetObjectInfo().Id.Uint64(),
 			TaskState:        types.TaskState_TASK_STATE_SEAL_OBJECT_ERROR,
 			ErrorDescription: "exceed_retry",
@@ -467,7 +486,7 @@ func (m *ManageModular) HandleGCObjectTask(ctx context.Context, gcTask task.GCOb
 	if gcTask.GetCurrentBlockNumber() > gcTask.GetEndBlockNumber() {
 		log.CtxInfow(ctx, "succeed to finish the gc object task", "task_info", gcTask.Info())
 		m.gcObjectQueue.PopByKey(gcTask.Key())
-		m.baseApp.GfSpDB().DeleteGCObjectProgress(gcTask.Key().String())
+		m.baseApp.GfSpDBWithContext(ctx).DeleteGCObjectProgress(gcTask.Key().String())
 		return nil
 	}
 	gcTask.SetUpdateTime(time.Now().Unix())
@@ -487,7 +506,7 @@ func (m *ManageModular) HandleGCObjectTask(ctx context.Context, gcTask task.GCOb
 	err := m.gcObjectQueue.Push(gcTask)
 	log.CtxInfow(ctx, "push gc object task to queue again", "from", oldTask, "to", gcTask, "error", err)
 	currentGCBlockID, deletedObjectID := gcTask.GetGCObjectProgress()
-	err = m.baseApp.GfSpDB().UpdateGCObjectProgress(&spdb.GCObjectMeta{
+	err = m.baseApp.GfSpDBWithContext(ctx).UpdateGCObjectProgress(&spdb.GCObjectMeta{
 		TaskKey:             gcTask.Key().String(),
 		CurrentBlockHeight:  currentGCBlockID,
 		LastDeletedObjectID: deletedObjectID,
@@ -523,6 +542,14 @@ func (m *ManageModular) HandleRecoverPieceTask(ctx context.Context, task task.Re
 	}
 
 	if task.GetRecovered() {
+		if m.takeCanceledTask(task.Key()) {
+			m.recoveryQueue.PopByKey(task.Key())
+			log.CtxErrorw(ctx, "task has been canceled", "task_info", task.Info())
+			return ErrCanceledTask
+		}
+		// the recover sp job treats the task retired from the queue as failed, so the result
+		// is accumulated before popping the task
+		m.doneRecoverSpTask(task.Key(), true)
 		m.recoveryQueue.PopByKey(task.Key())
 		log.CtxErrorw(ctx, "finished recovery", "task_info", task.Info())
 		return nil
@@ -559,6 +586,7 @@ func (m *ManageModular) handleFailedRecoverPieceTask(ctx context.Context, handle
 		err := m.recoveryQueue.Push(handleTask)
 		log.CtxDebugw(ctx, "push task again to retry", "task_info", handleTask.Info(), "error", err)
 	} else {
+		m.doneRecoverSpTask(handleTask.Key(), false)
 		log.CtxErrorw(ctx, "delete expired confirm recovery piece task", "task_info", handleTask.Info())
 	}
 	return nil
@@ -4,13 +4,17 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
@@ -23,6 +27,9 @@ import (
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
 )
 
+var _ gfspapp.Reloadable = &ManageModular{}
+var _ corelifecycle.Drainable = &ManageModular{}
+
 const (
 	// DiscontinueBucketReason defines the reason for stop serving
 	DiscontinueBucketReason = "testnet cleanup"
@@ -61,8 +68,13 @@ type ManageModular struct {
 	downloadQueue         taskqueue.TQueueOnStrategy
 	challengeQueue        taskqueue.TQueueOnStrategy
 	recoveryQueue         taskqueue.TQueueOnStrategyWithLimit
+	// pausedTaskTypes records the types of tasks that are paused dispatching by admin.
+	pausedTaskTypes map[task.TType]struct{}
+	// canceledTasks records the keys of tasks that are canceled by admin and the cancel time,
+	// the results reported for these tasks are dropped.
+	canceledTasks map[task.TKey]int64
 
-	maxUploadObjectNumber int
+	maxUploadObjectNumber int64
 
 	gcObjectTimeInterval  int
 	gcBlockHeight         uint64
@@ -75,6 +87,31 @@ type ManageModular struct {
 	discontinueBucketEnabled       bool
 	discontinueBucketTimeInterval  int
 	discontinueBucketKeepAliveDays int
+
+	recoverSpBatchSize int
+	recoverSpInterval  int
+	recoverSp          *recoverSpJob
+	recoverSpMux       sync.Mutex
+	// recoverSpJobMux serializes the status transitions of the recover sp job between the
+	// admin actions and the finishing job, it is locked before recoverSpMux.
+	recoverSpJobMux sync.Mutex
+	recoverSpLister recoverSpObjectLister
+	// objectSpIndexed indicates whether the block syncer maintains the object sp mapping that
+	// the full sp recover job lists the objects by, it is read from the block syncer modules
+	// of the config.
+	objectSpIndexed bool
+
+	adminHTTPAddress string
+	adminHTTPServer  *http.Server
+
+	webhookEnabled       bool
+	webhookMaxAttempts   int
+	webhookRetryInterval int
+	webhookCancel        context.CancelFunc
+
+	// drainStarted is set when the manager starts draining, the tasks are not dispatched
+	// and the new uploads are rejected
+	drainStarted int32
 }
 
 func (m *ManageModular) Name() string {
@@ -102,12 +139,20 @@ func (m *ManageModular) Start(ctx context.Context) error {
 		return err
 	}
 	m.scope = scope
-	//err = m.LoadTaskFromDB()
-	//if err != nil {
-	//	return err
-	//}
+	// the replicate, seal and gc tasks left in the queues by the last stop are re-derived
+	// from the progress in sp db
+	if err = m.LoadTaskFromDB(); err != nil {
+		return err
+	}
 
 	go m.eventLoop(ctx)
+	m.loadRecoverSpJob()
+	if m.webhookEnabled {
+		m.startWebhook()
+	}
+	if m.adminHTTPAddress != "" {
+		m.serveAdminHTTP()
+	}
 	return nil
 }
 
@@ -195,11 +240,65 @@ func (m *ManageModular) discontinueBuckets(ctx context.Context) {
 	}
 }
 
+// draining returns an indicator whether the tasks are not dispatched.
+func (m *ManageModular) draining() bool {
+	return atomic.LoadInt32(&m.drainStarted) == 1 || m.baseApp.Draining()
+}
+
+// Drain stops dispatching the tasks and rejects the new uploads, then waits for the uploading
+// objects to finish. The queued replicate, seal and gc tasks are not persisted, their progress
+// is already in sp db and they are loaded by the next start if the EnableLoadTask is set.
+func (m *ManageModular) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&m.drainStarted, 1)
+	ticker := time.NewTicker(DefaultDrainCheckInterval)
+	defer ticker.Stop()
+	for m.uploadQueue.Len()+m.resumeableUploadQueue.Len() > 0 {
+		select {
+		case <-ctx.Done():
+			log.CtxWarnw(ctx, "drain deadline exceeded, cut the uploading objects",
+				"uploading", m.uploadQueue.Len(), "resumable_uploading", m.resumeableUploadQueue.Len())
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	log.CtxInfow(ctx, "succeed to drain manager, the queued tasks are loaded from sp db by the next start",
+		"replicating", m.replicateQueue.Len(), "sealing", m.sealQueue.Len(), "gc_object", m.gcObjectQueue.Len())
+	return nil
+}
+
 func (m *ManageModular) Stop(ctx context.Context) error {
+	m.stopRecoverSpJob()
+	if m.webhookCancel != nil {
+		m.webhookCancel()
+	}
+	if m.adminHTTPServer != nil {
+		if err := m.adminHTTPServer.Shutdown(ctx); err != nil {
+			log.CtxErrorw(ctx, "failed to shutdown admin http server", "error", err)
+		}
+	}
 	m.scope.Release()
 	return nil
 }
 
+// Reload applies the changed capacities of the task queues and the max uploading number.
+func (m *ManageModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	defaultManagerConfig(cfg)
+	atomic.StoreInt64(&m.maxUploadObjectNumber, int64(cfg.Parallel.GlobalMaxUploadingParallel))
+	m.uploadQueue.SetCap(cfg.Parallel.GlobalUploadObjectParallel)
+	m.resumeableUploadQueue.SetCap(cfg.Parallel.GlobalUploadObjectParallel)
+	m.replicateQueue.SetCap(cfg.Parallel.GlobalReplicatePieceParallel)
+	m.recoveryQueue.SetCap(cfg.Parallel.GlobalRecoveryPieceParallel)
+	m.sealQueue.SetCap(cfg.Parallel.GlobalSealObjectParallel)
+	m.receiveQueue.SetCap(cfg.Parallel.GlobalReceiveObjectParallel)
+	m.gcObjectQueue.SetCap(cfg.Parallel.GlobalGCObjectParallel)
+	m.gcZombieQueue.SetCap(cfg.Parallel.GlobalGCZombieParallel)
+	m.gcMetaQueue.SetCap(cfg.Parallel.GlobalGCMetaParallel)
+	m.downloadQueue.SetCap(cfg.Parallel.GlobalDownloadObjectTaskCacheSize)
+	m.challengeQueue.SetCap(cfg.Parallel.GlobalChallengePieceTaskCacheSize)
+	log.CtxInfow(ctx, "succeed to reload manager", "max_uploading", cfg.Parallel.GlobalMaxUploadingParallel)
+	return nil
+}
+
 func (m *ManageModular) ReserveResource(ctx context.Context, state *rcmgr.ScopeStat) (rcmgr.ResourceScopeSpan, error) {
 	span, err := m.scope.BeginSpan()
 	if err != nil {
@@ -242,27 +341,14 @@ func (m *ManageModular) LoadTaskFromDB() error {
 		return err
 	}
 	for _, meta := range replicateMetas {
-		objectInfo, queryErr := m.baseApp.Consensus().QueryObjectInfoByID(context.Background(), util.Uint64ToString(meta.ObjectID))
-		if queryErr != nil {
-			log.Errorw("failed to query object info and continue", "object_id", meta.ObjectID, "error", queryErr)
-			continue
-		}
-
-		if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
-			log.Infow("object is not in create status and continue", "object_info", objectInfo)
+		replicateTask, newErr := m.newReplicateTaskFromMeta(context.Background(), meta)
+		if newErr != nil {
+			log.Errorw("failed to generate replicate piece task and continue", "object_id", meta.ObjectID, "error", newErr)
 			continue
 		}
-		storageParams, queryErr := m.baseApp.Consensus().QueryStorageParamsByTimestamp(context.Background(), objectInfo.GetCreateAt())
-		if queryErr != nil {
-			log.Errorw("failed to query storage param and continue", "object_id", meta.ObjectID, "error", queryErr)
-			continue
-		}
-		replicateTask := &gfsptask.GfSpReplicatePieceTask{}
-		replicateTask.InitReplicatePieceTask(objectInfo, storageParams, m.baseApp.TaskPriority(replicateTask),
-			m.baseApp.TaskTimeout(replicateTask, objectInfo.GetPayloadSize()), m.baseApp.TaskMaxRetry(replicateTask))
 		pushErr := m.replicateQueue.Push(replicateTask)
 		if pushErr != nil {
-			log.Errorw("failed to push replicate piece task to queue", "object_info", objectInfo, "error", pushErr)
+			log.Errorw("failed to push replicate piece task to queue", "object_info", replicateTask.GetObjectInfo(), "error", pushErr)
 			continue
 		}
 		generateReplicateTaskCounter++
@@ -274,26 +360,14 @@ func (m *ManageModular) LoadTaskFromDB() error {
 		return err
 	}
 	for _, meta := range sealMetas {
-		objectInfo, queryErr := m.baseApp.Consensus().QueryObjectInfoByID(context.Background(), util.Uint64ToString(meta.ObjectID))
-		if queryErr != nil {
-			log.Errorw("failed to query object info and continue", "object_id", meta.ObjectID, "error", queryErr)
-			continue
-		}
-		if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
-			log.Infow("object is not in create status and continue", "object_info", objectInfo)
+		sealTask, newErr := m.newSealTaskFromMeta(context.Background(), meta)
+		if newErr != nil {
+			log.Errorw("failed to generate seal object task and continue", "object_id", meta.ObjectID, "error", newErr)
 			continue
 		}
-		storageParams, queryErr := m.baseApp.Consensus().QueryStorageParamsByTimestamp(context.Background(), objectInfo.GetCreateAt())
-		if queryErr != nil {
-			log.Errorw("failed to query storage param and continue", "object_id", meta.ObjectID, "error", queryErr)
-			continue
-		}
-		sealTask := &gfsptask.GfSpSealObjectTask{}
-		sealTask.InitSealObjectTask(objectInfo, storageParams, m.baseApp.TaskPriority(sealTask), meta.SecondaryAddresses,
-			meta.SecondarySignatures, m.baseApp.TaskTimeout(sealTask, 0), m.baseApp.TaskMaxRetry(sealTask))
 		pushErr := m.sealQueue.Push(sealTask)
 		if pushErr != nil {
-			log.Errorw("failed to push seal object task to queue", "object_info", objectInfo, "error", pushErr)
+			log.Errorw("failed to push seal object task to queue", "object_info", sealTask.GetObjectInfo(), "error", pushErr)
 			continue
 		}
 		generateSealTaskCounter++
@@ -324,6 +398,51 @@ func (m *ManageModular) LoadTaskFromDB() error {
 	return nil
 }
 
+// queryCreatedObject queries the object and the storage params of the object that is still
+// in the created status, the task of the sealed or deleted object is not generated.
+func (m *ManageModular) queryCreatedObject(ctx context.Context, objectID uint64) (
+	*storagetypes.ObjectInfo, *storagetypes.Params, error) {
+	objectInfo, err := m.baseApp.Consensus().QueryObjectInfoByID(ctx, util.Uint64ToString(objectID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query object info: %w", err)
+	}
+	if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
+		return nil, nil, fmt.Errorf("object is not in create status: %s", objectInfo.GetObjectStatus().String())
+	}
+	storageParams, err := m.baseApp.Consensus().QueryStorageParamsByTimestamp(ctx, objectInfo.GetCreateAt())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query storage params: %w", err)
+	}
+	return objectInfo, storageParams, nil
+}
+
+// newReplicateTaskFromMeta generates the replicate piece task by the upload progress.
+func (m *ManageModular) newReplicateTaskFromMeta(ctx context.Context, meta *spdb.UploadObjectMeta) (
+	*gfsptask.GfSpReplicatePieceTask, error) {
+	objectInfo, storageParams, err := m.queryCreatedObject(ctx, meta.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	replicateTask := &gfsptask.GfSpReplicatePieceTask{}
+	replicateTask.InitReplicatePieceTask(objectInfo, storageParams, m.baseApp.TaskPriority(replicateTask),
+		m.baseApp.TaskTimeout(replicateTask, objectInfo.GetPayloadSize()), m.baseApp.TaskMaxRetry(replicateTask))
+	return replicateTask, nil
+}
+
+// newSealTaskFromMeta generates the seal object task by the upload progress, the secondary sps
+// and their signatures are recorded by the replicate piece task.
+func (m *ManageModular) newSealTaskFromMeta(ctx context.Context, meta *spdb.UploadObjectMeta) (
+	*gfsptask.GfSpSealObjectTask, error) {
+	objectInfo, storageParams, err := m.queryCreatedObject(ctx, meta.ObjectID)
+	if err != nil {
+		return nil, err
+	}
+	sealTask := &gfsptask.GfSpSealObjectTask{}
+	sealTask.InitSealObjectTask(objectInfo, storageParams, m.baseApp.TaskPriority(sealTask), meta.SecondaryAddresses,
+		meta.SecondarySignatures, m.baseApp.TaskTimeout(sealTask, 0), m.baseApp.TaskMaxRetry(sealTask))
+	return sealTask, nil
+}
+
 func (m *ManageModular) TaskUploading(ctx context.Context, task task.Task) bool {
 	if m.uploadQueue.Has(task.Key()) {
 		log.CtxDebugw(ctx, "uploading object repeated")
@@ -480,13 +599,18 @@ func (m *ManageModular) PickUpTask(ctx context.Context, tasks []task.Task) task.
 	for _, task := range tasks {
 		totalPriority += int(task.GetPriority())
 	}
+	if totalPriority == 0 {
+		log.CtxDebugw(ctx, "no task of schedulable priority for picking")
+		return nil
+	}
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	randPriority := r.Intn(totalPriority)
 	totalPriority = 0
 
 	for _, task := range tasks {
 		totalPriority += int(task.GetPriority())
-		if totalPriority >= randPriority {
+		// randPriority is in [0, totalPriority), the task of zero priority is never picked
+		if totalPriority > randPriority {
 			return task
 		}
 	}
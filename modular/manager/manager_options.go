@@ -1,9 +1,13 @@
 package manager
 
 import (
+	"time"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/blocksyncer/modules/objectsp"
 )
 
 const (
@@ -67,10 +71,28 @@ const (
 	// DefaultDiscontinueBucketKeepAliveDays defines the default bucket keep alive days, after
 	// the interval, buckets will be discontinued, used for test net.
 	DefaultDiscontinueBucketKeepAliveDays = 7
+
+	// DefaultRecoverSpBatchSize defines the default number of objects scanned in one batch
+	// by the full sp recover job.
+	DefaultRecoverSpBatchSize = 100
+	// DefaultRecoverSpInterval defines the default interval in seconds for the full sp
+	// recover job to check the recover tasks of the batch.
+	DefaultRecoverSpInterval = 5
+
+	// DefaultWebhookMaxAttempts defines the default max attempts of delivering a webhook event.
+	DefaultWebhookMaxAttempts = 8
+	// DefaultWebhookRetryInterval defines the default interval in seconds before the first retry
+	// of a failed webhook delivery.
+	DefaultWebhookRetryInterval = 30
+
+	// DefaultDrainCheckInterval defines the interval of checking the uploading objects
+	// when draining
+	DefaultDrainCheckInterval = 500 * time.Millisecond
 )
 
 func NewManageModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
-	manager := &ManageModular{baseApp: app}
+	manager := &ManageModular{baseApp: app, pausedTaskTypes: make(map[task.TType]struct{}),
+		canceledTasks: make(map[task.TKey]int64)}
 	if err := DefaultManagerOptions(manager, cfg); err != nil {
 		return nil, err
 	}
@@ -78,6 +100,61 @@ func NewManageModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (cor
 }
 
 func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) error {
+	defaultManagerConfig(cfg)
+
+	manager.enableLoadTask = cfg.Manager.EnableLoadTask
+	manager.recoverSpBatchSize = cfg.Manager.RecoverSpBatchSize
+	manager.recoverSpInterval = cfg.Manager.RecoverSpInterval
+	manager.recoverSpLister = manager.baseApp.GfSpClient()
+	for _, name := range cfg.BlockSyncer.Modules {
+		if name == objectsp.ModuleName {
+			manager.objectSpIndexed = true
+		}
+	}
+	manager.adminHTTPAddress = cfg.Manager.AdminHTTPAddress
+	manager.webhookEnabled = cfg.Manager.EnableWebhook
+	manager.webhookMaxAttempts = cfg.Manager.WebhookMaxAttempts
+	manager.webhookRetryInterval = cfg.Manager.WebhookRetryInterval
+	manager.loadTaskLimitToReplicate = cfg.Parallel.GlobalReplicatePieceParallel
+	manager.loadTaskLimitToSeal = cfg.Parallel.GlobalSealObjectParallel
+	manager.loadTaskLimitToGC = cfg.Parallel.GlobalGCObjectParallel
+
+	manager.statisticsOutputInterval = DefaultStatisticsOutputInterval
+	manager.maxUploadObjectNumber = int64(cfg.Parallel.GlobalMaxUploadingParallel)
+	manager.gcObjectTimeInterval = cfg.Parallel.GlobalBatchGcObjectTimeInterval
+	manager.gcObjectBlockInterval = cfg.Parallel.GlobalGcObjectBlockInterval
+	manager.gcSafeBlockDistance = cfg.Parallel.GlobalGcObjectSafeBlockDistance
+	manager.syncConsensusInfoInterval = cfg.Parallel.GlobalSyncConsensusInfoInterval
+	manager.discontinueBucketEnabled = cfg.Parallel.DiscontinueBucketEnabled
+	manager.discontinueBucketTimeInterval = cfg.Parallel.DiscontinueBucketTimeInterval
+	manager.discontinueBucketKeepAliveDays = cfg.Parallel.DiscontinueBucketKeepAliveDays
+	manager.uploadQueue = cfg.Customize.NewStrategyTQueueFunc(
+		manager.Name()+"-upload-object", cfg.Parallel.GlobalUploadObjectParallel)
+	manager.resumeableUploadQueue = cfg.Customize.NewStrategyTQueueFunc(
+		manager.Name()+"-resumeable-upload-object", cfg.Parallel.GlobalUploadObjectParallel)
+	manager.replicateQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-replicate-piece", cfg.Parallel.GlobalReplicatePieceParallel)
+	manager.recoveryQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-recovery-piece", cfg.Parallel.GlobalRecoveryPieceParallel)
+	manager.sealQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-seal-object", cfg.Parallel.GlobalSealObjectParallel)
+	manager.receiveQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-confirm-receive-piece", cfg.Parallel.GlobalReceiveObjectParallel)
+	manager.gcObjectQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-gc-object", cfg.Parallel.GlobalGCObjectParallel)
+	manager.gcZombieQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-gc-zombie", cfg.Parallel.GlobalGCZombieParallel)
+	manager.gcMetaQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-gc-meta", cfg.Parallel.GlobalGCMetaParallel)
+	manager.downloadQueue = cfg.Customize.NewStrategyTQueueFunc(
+		manager.Name()+"-cache-download-object", cfg.Parallel.GlobalDownloadObjectTaskCacheSize)
+	manager.challengeQueue = cfg.Customize.NewStrategyTQueueFunc(
+		manager.Name()+"-cache-challenge-piece", cfg.Parallel.GlobalChallengePieceTaskCacheSize)
+	return nil
+}
+
+// defaultManagerConfig fills the default values of the config that manager uses.
+func defaultManagerConfig(cfg *gfspconfig.GfSpConfig) {
 	if cfg.Parallel.GlobalMaxUploadingParallel == 0 {
 		cfg.Parallel.GlobalMaxUploadingParallel = DefaultGlobalMaxUploadingNumber
 	}
@@ -131,41 +208,16 @@ func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) e
 		cfg.Parallel.GlobalRecoveryPieceParallel = DefaultGlobalRecoveryPieceParallel
 	}
 
-	manager.enableLoadTask = cfg.Manager.EnableLoadTask
-	manager.loadTaskLimitToReplicate = cfg.Parallel.GlobalReplicatePieceParallel
-	manager.loadTaskLimitToSeal = cfg.Parallel.GlobalSealObjectParallel
-	manager.loadTaskLimitToGC = cfg.Parallel.GlobalGCObjectParallel
-
-	manager.statisticsOutputInterval = DefaultStatisticsOutputInterval
-	manager.maxUploadObjectNumber = cfg.Parallel.GlobalMaxUploadingParallel
-	manager.gcObjectTimeInterval = cfg.Parallel.GlobalBatchGcObjectTimeInterval
-	manager.gcObjectBlockInterval = cfg.Parallel.GlobalGcObjectBlockInterval
-	manager.gcSafeBlockDistance = cfg.Parallel.GlobalGcObjectSafeBlockDistance
-	manager.syncConsensusInfoInterval = cfg.Parallel.GlobalSyncConsensusInfoInterval
-	manager.discontinueBucketEnabled = cfg.Parallel.DiscontinueBucketEnabled
-	manager.discontinueBucketTimeInterval = cfg.Parallel.DiscontinueBucketTimeInterval
-	manager.discontinueBucketKeepAliveDays = cfg.Parallel.DiscontinueBucketKeepAliveDays
-	manager.uploadQueue = cfg.Customize.NewStrategyTQueueFunc(
-		manager.Name()+"-upload-object", cfg.Parallel.GlobalUploadObjectParallel)
-	manager.resumeableUploadQueue = cfg.Customize.NewStrategyTQueueFunc(
-		manager.Name()+"-resumeable-upload-object", cfg.Parallel.GlobalUploadObjectParallel)
-	manager.replicateQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-replicate-piece", cfg.Parallel.GlobalReplicatePieceParallel)
-	manager.recoveryQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-recovery-piece", cfg.Parallel.GlobalRecoveryPieceParallel)
-	manager.sealQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-seal-object", cfg.Parallel.GlobalSealObjectParallel)
-	manager.receiveQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-confirm-receive-piece", cfg.Parallel.GlobalReceiveObjectParallel)
-	manager.gcObjectQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-gc-object", cfg.Parallel.GlobalGCObjectParallel)
-	manager.gcZombieQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-gc-zombie", cfg.Parallel.GlobalGCZombieParallel)
-	manager.gcMetaQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
-		manager.Name()+"-gc-meta", cfg.Parallel.GlobalGCMetaParallel)
-	manager.downloadQueue = cfg.Customize.NewStrategyTQueueFunc(
-		manager.Name()+"-cache-download-object", cfg.Parallel.GlobalDownloadObjectTaskCacheSize)
-	manager.challengeQueue = cfg.Customize.NewStrategyTQueueFunc(
-		manager.Name()+"-cache-challenge-piece", cfg.Parallel.GlobalChallengePieceTaskCacheSize)
-	return nil
+	if cfg.Manager.RecoverSpBatchSize == 0 {
+		cfg.Manager.RecoverSpBatchSize = DefaultRecoverSpBatchSize
+	}
+	if cfg.Manager.RecoverSpInterval == 0 {
+		cfg.Manager.RecoverSpInterval = DefaultRecoverSpInterval
+	}
+	if cfg.Manager.WebhookMaxAttempts == 0 {
+		cfg.Manager.WebhookMaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if cfg.Manager.WebhookRetryInterval == 0 {
+		cfg.Manager.WebhookRetryInterval = DefaultWebhookRetryInterval
+	}
 }
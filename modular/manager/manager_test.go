@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+)
+
+func TestManageModular_Reload(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	cfg := &gfspconfig.GfSpConfig{}
+	cfg.Parallel.GlobalMaxUploadingParallel = 100
+	cfg.Parallel.GlobalUploadObjectParallel = 20
+	cfg.Parallel.GlobalReplicatePieceParallel = 30
+	cfg.Parallel.GlobalSealObjectParallel = 40
+	assert.NoError(t, m.Reload(context.Background(), cfg))
+
+	assert.Equal(t, int64(100), m.maxUploadObjectNumber)
+	assert.Equal(t, 20, m.uploadQueue.Cap())
+	assert.Equal(t, 20, m.resumeableUploadQueue.Cap())
+	assert.Equal(t, 30, m.replicateQueue.Cap())
+	assert.Equal(t, 40, m.sealQueue.Cap())
+	// the unset capacities fall back to the defaults
+	assert.Equal(t, DefaultGlobalRecoveryPieceParallel, m.recoveryQueue.Cap())
+	assert.Equal(t, DefaultGlobalDownloadObjectTaskCacheSize, m.downloadQueue.Cap())
+}
+
+func TestManageModular_Drain(t *testing.T) {
+	m, _ := setupAdminManager(t)
+	uploadTask := mockUploadTask(1, "uploading")
+	assert.NoError(t, m.uploadQueue.Push(uploadTask))
+	assert.NoError(t, m.replicateQueue.Push(mockReplicateTask(2, "replicating")))
+
+	// the drain waits for the uploading object until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Drain(ctx), context.DeadlineExceeded)
+
+	// the queued task is not dispatched and the new upload is rejected
+	dispatched, err := m.DispatchTask(context.Background(), &rcmgr.Unlimited{})
+	assert.NoError(t, err)
+	assert.Nil(t, dispatched)
+	assert.Equal(t, ErrDraining, m.HandleCreateUploadObjectTask(context.Background(), mockUploadTask(3, "new")))
+	assert.Equal(t, 1, m.replicateQueue.Len())
+
+	m.uploadQueue.PopByKey(uploadTask.Key())
+	assert.NoError(t, m.Drain(context.Background()))
+}
@@ -0,0 +1,328 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+var (
+	ErrRecoverSpJobRunning    = gfsperrors.Register(module.ManageModularName, http.StatusNotAcceptable, 60006, "recover sp job is running")
+	ErrNoRecoverSpJob         = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60007, "no recover sp job to pause or resume")
+	ErrInvalidRecoverSpAction = gfsperrors.Register(module.ManageModularName, http.StatusBadRequest, 60008, "invalid recover sp action")
+	ErrObjectSpNotIndexed     = gfsperrors.Register(module.ManageModularName, http.StatusNotAcceptable, 60011,
+		"the object sp mapping is not maintained, enable the object_sp module of the block syncer")
+)
+
+// recoverSpObjectLister lists the objects of the sp for the full sp recover job, it is
+// implemented by the sp client.
+type recoverSpObjectLister interface {
+	ListObjectsBySp(ctx context.Context, spAddress string, startID uint64, limit int64,
+		opts ...grpc.DialOption) ([]*metadatatypes.Object, uint64, error)
+}
+
+// recoverSpJob is the running full sp recover job, pending records the recover piece
+// tasks generated by the job and not finished yet.
+type recoverSpJob struct {
+	jobID   uint64
+	cancel  context.CancelFunc
+	pending map[task.TKey]struct{}
+}
+
+// HandleRecoverSpJob starts, pauses, resumes or queries the full sp recover job. The job lists
+// the objects of the sp by the object sp mapping, it can not be started or resumed unless the
+// object_sp module of the block syncer maintains the mapping.
+func (m *ManageModular) HandleRecoverSpJob(ctx context.Context, action string) (*spdb.RecoverJob, error) {
+	if (action == module.RecoverSpActionStart || action == module.RecoverSpActionResume) && !m.objectSpIndexed {
+		log.CtxErrorw(ctx, "failed to handle recover sp job, the object sp mapping is not maintained", "action", action)
+		return nil, ErrObjectSpNotIndexed
+	}
+	m.recoverSpJobMux.Lock()
+	defer m.recoverSpJobMux.Unlock()
+	latest, err := m.baseApp.GfSpDB().GetLatestRecoverJob()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get latest recover sp job", "error", err)
+		return nil, ErrGfSpDB
+	}
+	switch action {
+	case module.RecoverSpActionQuery:
+		return latest, nil
+	case module.RecoverSpActionStart:
+		if m.recoveringSp() || (latest != nil && latest.Status == spdb.RecoverJobRunning) {
+			return nil, ErrRecoverSpJobRunning
+		}
+		jobID, err := m.baseApp.GfSpDB().InsertRecoverJob(&spdb.RecoverJob{Status: spdb.RecoverJobRunning})
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to insert recover sp job", "error", err)
+			return nil, ErrGfSpDB
+		}
+		m.startRecoverSpJob(jobID, 0)
+	case module.RecoverSpActionPause:
+		if latest == nil || latest.Status != spdb.RecoverJobRunning {
+			return nil, ErrNoRecoverSpJob
+		}
+		if err = m.baseApp.GfSpDB().UpdateRecoverJobStatus(latest.JobID, spdb.RecoverJobPaused); err != nil {
+			log.CtxErrorw(ctx, "failed to pause recover sp job", "error", err)
+			return nil, ErrGfSpDB
+		}
+		m.stopRecoverSpJob()
+	case module.RecoverSpActionResume:
+		if latest == nil || latest.Status == spdb.RecoverJobFinished {
+			return nil, ErrNoRecoverSpJob
+		}
+		if m.recoveringSp() {
+			return nil, ErrRecoverSpJobRunning
+		}
+		if err = m.baseApp.GfSpDB().UpdateRecoverJobStatus(latest.JobID, spdb.RecoverJobRunning); err != nil {
+			log.CtxErrorw(ctx, "failed to resume recover sp job", "error", err)
+			return nil, ErrGfSpDB
+		}
+		m.startRecoverSpJob(latest.JobID, latest.Cursor)
+	default:
+		return nil, ErrInvalidRecoverSpAction
+	}
+	log.CtxInfow(ctx, "succeed to handle recover sp job", "action", action)
+	return m.baseApp.GfSpDB().GetLatestRecoverJob()
+}
+
+// loadRecoverSpJob resumes the running recover job that is interrupted by restarting.
+func (m *ManageModular) loadRecoverSpJob() {
+	job, err := m.baseApp.GfSpDB().GetLatestRecoverJob()
+	if err != nil {
+		log.Errorw("failed to load recover sp job", "error", err)
+		return
+	}
+	if job == nil || job.Status != spdb.RecoverJobRunning {
+		return
+	}
+	if !m.objectSpIndexed {
+		log.Errorw("failed to resume the interrupted recover sp job, the object sp mapping is not maintained",
+			"job_id", job.JobID)
+		return
+	}
+	log.Infow("resume the interrupted recover sp job", "job_id", job.JobID, "cursor", job.Cursor)
+	m.startRecoverSpJob(job.JobID, job.Cursor)
+}
+
+func (m *ManageModular) recoveringSp() bool {
+	m.recoverSpMux.Lock()
+	defer m.recoverSpMux.Unlock()
+	return m.recoverSp != nil
+}
+
+func (m *ManageModular) startRecoverSpJob(jobID uint64, cursor uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &recoverSpJob{
+		jobID:   jobID,
+		cancel:  cancel,
+		pending: make(map[task.TKey]struct{}),
+	}
+	m.recoverSpMux.Lock()
+	m.recoverSp = job
+	m.recoverSpMux.Unlock()
+	go m.runRecoverSpJob(ctx, job, cursor)
+}
+
+func (m *ManageModular) stopRecoverSpJob() {
+	m.recoverSpMux.Lock()
+	defer m.recoverSpMux.Unlock()
+	if m.recoverSp != nil {
+		m.recoverSp.cancel()
+		m.recoverSp = nil
+	}
+}
+
+// runRecoverSpJob scans the objects of the sp page by page, the cursor is persisted after
+// all the recover tasks of the page are finished, so the interrupted page is scanned again
+// after resuming, and the recovered pieces are skipped.
+func (m *ManageModular) runRecoverSpJob(ctx context.Context, job *recoverSpJob, cursor uint64) {
+	defer func() {
+		m.recoverSpMux.Lock()
+		if m.recoverSp == job {
+			m.recoverSp = nil
+		}
+		m.recoverSpMux.Unlock()
+	}()
+	ctx = log.WithValue(ctx, log.CtxKeyTask, "recover-sp-job")
+	interval := time.Duration(m.recoverSpInterval) * time.Second
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		objects, endID, err := m.recoverSpLister.ListObjectsBySp(ctx, m.baseApp.OperatorAddress(),
+			cursor, int64(m.recoverSpBatchSize))
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to list objects by sp, try again later", "cursor", cursor, "error", err)
+			waitRecoverSpInterval(ctx, interval)
+			continue
+		}
+		if len(objects) == 0 {
+			m.finishRecoverSpJob(ctx, job)
+			return
+		}
+		var generated, skipped uint64
+		for _, object := range objects {
+			g, s := m.generateRecoverObjectTasks(ctx, job, object)
+			generated += g
+			skipped += s
+		}
+		// wait for the tasks of the page to be finished before moving the cursor
+		for m.recoverSpPending(ctx, job) {
+			waitRecoverSpInterval(ctx, interval)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err = m.baseApp.GfSpDB().UpdateRecoverJobProgress(job.jobID, endID, uint64(len(objects)),
+			generated, skipped); err != nil {
+			log.CtxErrorw(ctx, "failed to update recover sp job progress", "error", err)
+		}
+		log.CtxInfow(ctx, "succeed to recover a batch of objects", "job_id", job.jobID, "cursor", cursor,
+			"end_id", endID, "objects", len(objects), "generated", generated, "skipped", skipped)
+		cursor = endID
+	}
+}
+
+// finishRecoverSpJob marks the job finished unless it is paused or stopped, the admin actions
+// hold the same lock, so the paused job is never marked finished and vice versa.
+func (m *ManageModular) finishRecoverSpJob(ctx context.Context, job *recoverSpJob) {
+	m.recoverSpJobMux.Lock()
+	defer m.recoverSpJobMux.Unlock()
+	if ctx.Err() != nil {
+		log.CtxInfow(ctx, "recover sp job is stopped before finishing", "job_id", job.jobID)
+		return
+	}
+	if err := m.baseApp.GfSpDB().UpdateRecoverJobStatus(job.jobID, spdb.RecoverJobFinished); err != nil {
+		log.CtxErrorw(ctx, "failed to finish recover sp job", "error", err)
+	}
+	log.CtxInfow(ctx, "finish to recover sp job", "job_id", job.jobID)
+}
+
+// waitRecoverSpInterval waits for the interval or the job to be stopped.
+func waitRecoverSpInterval(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// generateRecoverObjectTasks generates the recover piece tasks of the pieces that are missing
+// or truncated in piece store, it returns the number of generated tasks and skipped pieces.
+func (m *ManageModular) generateRecoverObjectTasks(ctx context.Context, job *recoverSpJob,
+	object *metadatatypes.Object) (generated uint64, skipped uint64) {
+	objectInfo := object.GetObjectInfo()
+	if objectInfo == nil {
+		return 0, 0
+	}
+	if objectInfo.GetRedundancyType() != storagetypes.REDUNDANCY_EC_TYPE {
+		log.CtxDebugw(ctx, "skip to recover the object of unsupported redundancy type",
+			"object_id", objectInfo.Id.Uint64())
+		return 0, 0
+	}
+	ecIdx := int32(-1)
+	for i, addr := range objectInfo.GetSecondarySpAddresses() {
+		if strings.EqualFold(addr, m.baseApp.OperatorAddress()) {
+			ecIdx = int32(i)
+			break
+		}
+	}
+	params, err := m.baseApp.Consensus().QueryStorageParamsByTimestamp(ctx, objectInfo.GetCreateAt())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to query storage params", "object_id", objectInfo.Id.Uint64(), "error", err)
+		return 0, 0
+	}
+	maxSegmentSize := params.VersionedParams.GetMaxSegmentSize()
+	segmentCount := m.baseApp.PieceOp().SegmentPieceCount(objectInfo.GetPayloadSize(), maxSegmentSize)
+	for segIdx := uint32(0); segIdx < segmentCount; segIdx++ {
+		if ctx.Err() != nil {
+			return generated, skipped
+		}
+		var (
+			pieceKey  string
+			pieceSize int64
+		)
+		if ecIdx < 0 {
+			pieceKey = m.baseApp.PieceOp().SegmentPieceKey(objectInfo.Id.Uint64(), segIdx)
+			pieceSize = m.baseApp.PieceOp().SegmentPieceSize(objectInfo.GetPayloadSize(), segIdx, maxSegmentSize)
+		} else {
+			pieceKey = m.baseApp.PieceOp().ECPieceKey(objectInfo.Id.Uint64(), segIdx, uint32(ecIdx))
+			pieceSize = m.baseApp.PieceOp().ECPieceSize(objectInfo.GetPayloadSize(), segIdx, maxSegmentSize,
+				params.VersionedParams.GetRedundantDataChunkNum())
+		}
+		if size, headErr := m.baseApp.PieceStore().HeadPiece(ctx, pieceKey); headErr == nil && size == pieceSize {
+			skipped++
+			continue
+		}
+		recoveryTask := &gfsptask.GfSpRecoverPieceTask{}
+		recoveryTask.InitRecoverPieceTask(objectInfo, params, m.baseApp.TaskPriority(recoveryTask), segIdx, ecIdx,
+			maxSegmentSize, m.baseApp.TaskTimeout(recoveryTask, maxSegmentSize), m.baseApp.TaskMaxRetry(recoveryTask))
+		// throttle the generation by the capacity of recovery queue
+		for m.recoveryQueue.Len() >= m.recoveryQueue.Cap() {
+			if ctx.Err() != nil {
+				return generated, skipped
+			}
+			time.Sleep(time.Second)
+		}
+		// the task of the interrupted page may be still queued after resuming, it is tracked
+		// as the generated one so the page is not finished before the task
+		if err = m.HandleRecoverPieceTask(ctx, recoveryTask); err != nil && err != ErrRepeatedTask {
+			log.CtxErrorw(ctx, "failed to push recover piece task", "task_info", recoveryTask.Info(), "error", err)
+			continue
+		}
+		m.recoverSpMux.Lock()
+		job.pending[recoveryTask.Key()] = struct{}{}
+		m.recoverSpMux.Unlock()
+		generated++
+	}
+	return generated, skipped
+}
+
+// recoverSpPending returns an indicator whether the job has unfinished tasks, the tasks
+// retired from recovery queue are treated as failed.
+func (m *ManageModular) recoverSpPending(ctx context.Context, job *recoverSpJob) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	m.recoverSpMux.Lock()
+	defer m.recoverSpMux.Unlock()
+	for key := range job.pending {
+		if !m.recoveryQueue.Has(key) {
+			delete(job.pending, key)
+			if err := m.baseApp.GfSpDB().IncreaseRecoverJobPieces(job.jobID, false); err != nil {
+				log.CtxErrorw(ctx, "failed to increase recover sp job failed pieces", "error", err)
+			}
+		}
+	}
+	return len(job.pending) > 0
+}
+
+// doneRecoverSpTask accumulates the result of the recover piece task if it is generated by
+// the running recover job.
+func (m *ManageModular) doneRecoverSpTask(key task.TKey, succeed bool) {
+	m.recoverSpMux.Lock()
+	defer m.recoverSpMux.Unlock()
+	if m.recoverSp == nil {
+		return
+	}
+	if _, ok := m.recoverSp.pending[key]; !ok {
+		return
+	}
+	delete(m.recoverSp.pending, key)
+	if err := m.baseApp.GfSpDB().IncreaseRecoverJobPieces(m.recoverSp.jobID, succeed); err != nil {
+		log.Errorw("failed to increase recover sp job pieces", "error", err)
+	}
+}
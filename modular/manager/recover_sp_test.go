@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	recoverSpOperator = "0x0000000000000000000000000000000000000001"
+	recoverSpOther    = "0x0000000000000000000000000000000000000002"
+)
+
+type mockRecoverSpLister struct {
+	// pages maps the start id to the listed objects
+	pages  map[uint64][]*metadatatypes.Object
+	starts chan uint64
+}
+
+func (l *mockRecoverSpLister) ListObjectsBySp(_ context.Context, _ string, startID uint64, _ int64,
+	_ ...grpc.DialOption) ([]*metadatatypes.Object, uint64, error) {
+	l.starts <- startID
+	objects := l.pages[startID]
+	if len(objects) == 0 {
+		return nil, startID, nil
+	}
+	return objects, objects[len(objects)-1].GetObjectInfo().Id.Uint64(), nil
+}
+
+type mockRecoverSpConsensus struct {
+	consensus.NullConsensus
+}
+
+func (*mockRecoverSpConsensus) QueryStorageParamsByTimestamp(context.Context, int64) (*storagetypes.Params, error) {
+	return &storagetypes.Params{VersionedParams: storagetypes.VersionedParams{
+		MaxSegmentSize:          16,
+		RedundantDataChunkNum:   2,
+		RedundantParityChunkNum: 1,
+	}}, nil
+}
+
+func setupRecoverSpManager(t *testing.T) (*ManageModular, *spdb.MockSPDB, *mockRecoverSpLister) {
+	m, db := setupAdminManager(t)
+	cfg := &gfspconfig.GfSpConfig{Customize: &gfspconfig.Customize{Consensus: &mockRecoverSpConsensus{}}}
+	cfg.SpAccount.SpOperatorAddress = recoverSpOperator
+	cfg.PieceStore.Store = storage.ObjectStorageConfig{Storage: storage.MemoryStore, BucketURL: "recover"}
+	require.NoError(t, gfspapp.DefaultStaticOption(m.baseApp, cfg))
+	require.NoError(t, gfspapp.DefaultGfSpConsensusOption(m.baseApp, cfg))
+	require.NoError(t, gfspapp.DefaultGfSpPieceStoreOption(m.baseApp, cfg))
+	require.NoError(t, gfspapp.DefaultGfSpPieceOpOption(m.baseApp, cfg))
+	lister := &mockRecoverSpLister{pages: make(map[uint64][]*metadatatypes.Object), starts: make(chan uint64, 10)}
+	m.recoverSpLister = lister
+	m.recoverSpBatchSize = 10
+	m.objectSpIndexed = true
+	return m, db, lister
+}
+
+func TestManageModular_HandleRecoverSpJob(t *testing.T) {
+	m, db, lister := setupRecoverSpManager(t)
+	ctx := context.Background()
+	running := &spdb.RecoverJob{JobID: 1, Status: spdb.RecoverJobRunning, Cursor: 3}
+	paused := &spdb.RecoverJob{JobID: 1, Status: spdb.RecoverJobPaused, Cursor: 3}
+	finished := &spdb.RecoverJob{JobID: 1, Status: spdb.RecoverJobFinished, Cursor: 3}
+
+	// the job can not be started without the object sp mapping
+	m.objectSpIndexed = false
+	_, err := m.HandleRecoverSpJob(ctx, module.RecoverSpActionStart)
+	require.Equal(t, ErrObjectSpNotIndexed, err)
+	m.objectSpIndexed = true
+
+	db.EXPECT().GetLatestRecoverJob().Return(running, nil)
+	_, err = m.HandleRecoverSpJob(ctx, module.RecoverSpActionStart)
+	require.Equal(t, ErrRecoverSpJobRunning, err)
+
+	db.EXPECT().GetLatestRecoverJob().Return(running, nil)
+	db.EXPECT().UpdateRecoverJobStatus(uint64(1), spdb.RecoverJobPaused).Return(nil)
+	db.EXPECT().GetLatestRecoverJob().Return(paused, nil)
+	job, err := m.HandleRecoverSpJob(ctx, module.RecoverSpActionPause)
+	require.NoError(t, err)
+	require.Equal(t, spdb.RecoverJobPaused, job.Status)
+	require.False(t, m.recoveringSp())
+
+	db.EXPECT().GetLatestRecoverJob().Return(paused, nil)
+	_, err = m.HandleRecoverSpJob(ctx, module.RecoverSpActionPause)
+	require.Equal(t, ErrNoRecoverSpJob, err)
+
+	// the paused job is resumed from its cursor and finished with no object left
+	done := make(chan struct{})
+	db.EXPECT().GetLatestRecoverJob().Return(paused, nil)
+	db.EXPECT().UpdateRecoverJobStatus(uint64(1), spdb.RecoverJobRunning).Return(nil)
+	db.EXPECT().UpdateRecoverJobStatus(uint64(1), spdb.RecoverJobFinished).DoAndReturn(func(uint64, string) error {
+		close(done)
+		return nil
+	})
+	db.EXPECT().GetLatestRecoverJob().Return(running, nil)
+	_, err = m.HandleRecoverSpJob(ctx, module.RecoverSpActionResume)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), <-lister.starts)
+	<-done
+	require.Eventually(t, func() bool { return !m.recoveringSp() }, time.Second, 10*time.Millisecond)
+
+	db.EXPECT().GetLatestRecoverJob().Return(finished, nil).Times(3)
+	_, err = m.HandleRecoverSpJob(ctx, module.RecoverSpActionResume)
+	require.Equal(t, ErrNoRecoverSpJob, err)
+	job, err = m.HandleRecoverSpJob(ctx, module.RecoverSpActionQuery)
+	require.NoError(t, err)
+	require.Equal(t, finished, job)
+	_, err = m.HandleRecoverSpJob(ctx, "unknown")
+	require.Equal(t, ErrInvalidRecoverSpAction, err)
+}
+
+func TestManageModular_ResumeInterruptedRecoverSpJob(t *testing.T) {
+	m, db, lister := setupRecoverSpManager(t)
+	ctx := context.Background()
+	object := &storagetypes.ObjectInfo{
+		Id:                   sdkmath.NewUint(6),
+		BucketName:           "mock-bucket",
+		ObjectName:           "mock-object",
+		PayloadSize:          32,
+		RedundancyType:       storagetypes.REDUNDANCY_EC_TYPE,
+		SecondarySpAddresses: []string{recoverSpOther, recoverSpOperator},
+	}
+	lister.pages[5] = []*metadatatypes.Object{{ObjectInfo: object}}
+	// the first ec piece is recovered before the interruption, the second one is truncated
+	params, _ := (&mockRecoverSpConsensus{}).QueryStorageParamsByTimestamp(ctx, 0)
+	pieceSize := m.baseApp.PieceOp().ECPieceSize(object.GetPayloadSize(), 0,
+		params.VersionedParams.GetMaxSegmentSize(), params.VersionedParams.GetRedundantDataChunkNum())
+	require.NoError(t, m.baseApp.PieceStore().PutPiece(ctx, m.baseApp.PieceOp().ECPieceKey(6, 0, 1), make([]byte, pieceSize)))
+	require.NoError(t, m.baseApp.PieceStore().PutPiece(ctx, m.baseApp.PieceOp().ECPieceKey(6, 1, 1), make([]byte, 1)))
+
+	done := make(chan struct{})
+	db.EXPECT().GetLatestRecoverJob().Return(&spdb.RecoverJob{JobID: 7, Status: spdb.RecoverJobRunning, Cursor: 5}, nil)
+	db.EXPECT().IncreaseRecoverJobPieces(uint64(7), true).Return(nil)
+	db.EXPECT().UpdateRecoverJobProgress(uint64(7), uint64(6), uint64(1), uint64(1), uint64(1)).Return(nil)
+	db.EXPECT().UpdateRecoverJobStatus(uint64(7), spdb.RecoverJobFinished).DoAndReturn(func(uint64, string) error {
+		close(done)
+		return nil
+	})
+	m.loadRecoverSpJob()
+	require.Equal(t, uint64(5), <-lister.starts)
+
+	// only the truncated piece is recovered, the cursor is moved after the task is finished
+	var tasks []task.Task
+	require.Eventually(t, func() bool {
+		tasks, _ = taskqueue.ScanTQueueWithLimitBySubKey(m.recoveryQueue, "")
+		return len(tasks) == 1
+	}, time.Second, 10*time.Millisecond)
+	recoveryTask := tasks[0].(task.RecoveryPieceTask)
+	require.Equal(t, uint32(1), recoveryTask.GetSegmentIdx())
+	require.Equal(t, int32(1), recoveryTask.GetEcIdx())
+	recoveryTask.SetRecoverDone()
+	require.NoError(t, m.HandleRecoverPieceTask(ctx, recoveryTask))
+
+	require.Equal(t, uint64(6), <-lister.starts)
+	<-done
+	require.Eventually(t, func() bool { return !m.recoveringSp() }, time.Second, 10*time.Millisecond)
+}
+
+func TestManageModular_RecoverSpJobTracksQueuedTask(t *testing.T) {
+	m, db, lister := setupRecoverSpManager(t)
+	ctx := context.Background()
+	object := &storagetypes.ObjectInfo{
+		Id:                   sdkmath.NewUint(6),
+		BucketName:           "mock-bucket",
+		ObjectName:           "mock-object",
+		PayloadSize:          16,
+		RedundancyType:       storagetypes.REDUNDANCY_EC_TYPE,
+		SecondarySpAddresses: []string{recoverSpOther, recoverSpOperator},
+	}
+	lister.pages[5] = []*metadatatypes.Object{{ObjectInfo: object}}
+	// the task of the interrupted page is still queued when the job is resumed
+	params, _ := (&mockRecoverSpConsensus{}).QueryStorageParamsByTimestamp(ctx, 0)
+	queued := &gfsptask.GfSpRecoverPieceTask{}
+	queued.InitRecoverPieceTask(object, params, m.baseApp.TaskPriority(queued), 0, 1,
+		params.VersionedParams.GetMaxSegmentSize(), 0, 0)
+	require.NoError(t, m.recoveryQueue.Push(queued))
+
+	done := make(chan struct{})
+	db.EXPECT().GetLatestRecoverJob().Return(&spdb.RecoverJob{JobID: 7, Status: spdb.RecoverJobRunning, Cursor: 5}, nil)
+	db.EXPECT().IncreaseRecoverJobPieces(uint64(7), true).Return(nil)
+	db.EXPECT().UpdateRecoverJobProgress(uint64(7), uint64(6), uint64(1), uint64(1), uint64(0)).Return(nil)
+	db.EXPECT().UpdateRecoverJobStatus(uint64(7), spdb.RecoverJobFinished).DoAndReturn(func(uint64, string) error {
+		close(done)
+		return nil
+	})
+	m.loadRecoverSpJob()
+	require.Equal(t, uint64(5), <-lister.starts)
+
+	// the page is not finished before the queued task
+	require.Eventually(t, func() bool {
+		m.recoverSpMux.Lock()
+		defer m.recoverSpMux.Unlock()
+		_, ok := m.recoverSp.pending[queued.Key()]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case <-lister.starts:
+		t.Fatal("the cursor is moved before the queued task is finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	queued.SetRecoverDone()
+	require.NoError(t, m.HandleRecoverPieceTask(ctx, queued))
+
+	require.Equal(t, uint64(6), <-lister.starts)
+	<-done
+}
@@ -0,0 +1,442 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+)
+
+const (
+	// WebhookSignatureHeader defines the header of the hex encoded HMAC-SHA256 signature of
+	// the delivery, the signed message is "{timestamp}.{body}" and the key is the secret of
+	// the subscription.
+	WebhookSignatureHeader = "X-Gnfd-Webhook-Signature"
+	// WebhookTimestampHeader defines the header of the unix timestamp in seconds when the
+	// delivery is signed, the subscriber can reject the stale deliveries by it.
+	WebhookTimestampHeader = "X-Gnfd-Webhook-Timestamp"
+	// WebhookEventHeader defines the header of the webhook event type.
+	WebhookEventHeader = "X-Gnfd-Webhook-Event"
+	// WebhookEventIDHeader defines the header of the event id, it is unique in the subscription,
+	// the subscriber can deduplicate the retried deliveries by it.
+	WebhookEventIDHeader = "X-Gnfd-Webhook-Event-Id"
+
+	// DefaultWebhookDeliveryInterval defines the interval to deliver the due webhook deliveries.
+	DefaultWebhookDeliveryInterval = time.Second
+	// DefaultWebhookDeliveryBatchSize defines the max number of deliveries in one round.
+	DefaultWebhookDeliveryBatchSize = 100
+	// DefaultWebhookDeliveryParallel defines the max number of the concurrent deliveries.
+	DefaultWebhookDeliveryParallel = 8
+	// DefaultWebhookDeliveryTimeout defines the timeout of a delivery request.
+	DefaultWebhookDeliveryTimeout = 10 * time.Second
+	// DefaultWebhookMaxRetryInterval defines the upper bound of the retry interval.
+	DefaultWebhookMaxRetryInterval = time.Hour
+	// DefaultWebhookQuotaCheckInterval defines the interval to check the read quota of the
+	// subscribed buckets.
+	DefaultWebhookQuotaCheckInterval = time.Minute
+	// DefaultWebhookReconnectInterval defines the interval to reconnect the change feed.
+	DefaultWebhookReconnectInterval = 5 * time.Second
+)
+
+// webhookChangeEvents maps the change events of the change feed to the webhook events.
+var webhookChangeEvents = map[string]string{
+	model.ChangeObjectSealed:       spdb.WebhookEventObjectSealed,
+	model.ChangeObjectSealRejected: spdb.WebhookEventObjectSealRejected,
+	model.ChangeObjectDeleted:      spdb.WebhookEventObjectDeleted,
+}
+
+// WebhookPayload defines the json body of the webhook delivery.
+type WebhookPayload struct {
+	EventID     string `json:"event_id"`
+	EventType   string `json:"event_type"`
+	BucketName  string `json:"bucket_name"`
+	ObjectName  string `json:"object_name,omitempty"`
+	ObjectID    string `json:"object_id,omitempty"`
+	BlockHeight int64  `json:"block_height,omitempty"`
+	TxHash      string `json:"tx_hash,omitempty"`
+	// Timestamp is the block time of the change event or the time when the quota event is
+	// triggered, in seconds.
+	Timestamp        int64  `json:"timestamp"`
+	YearMonth        string `json:"year_month,omitempty"`
+	ReadConsumedSize uint64 `json:"read_consumed_size,omitempty"`
+	ReadQuotaSize    uint64 `json:"read_quota_size,omitempty"`
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 signature of the delivery.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookSubscribed returns true if the subscription subscribes the event.
+func webhookSubscribed(sub *spdb.WebhookSubscription, eventType string) bool {
+	for _, event := range sub.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// startWebhook starts to dispatch the events to the pending deliveries and deliver them, the
+// deliveries are persisted in spdb, so they are resumed after the manager restarts.
+func (m *ManageModular) startWebhook() {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = log.WithValue(ctx, log.CtxKeyTask, "webhook")
+	m.webhookCancel = cancel
+	go m.dispatchWebhookChangeEvents(ctx)
+	go m.deliverWebhookLoop(ctx)
+}
+
+// dispatchWebhookChangeEvents streams the change feed after the persisted cursor, and generates
+// the deliveries of the subscribed events. The feed starts from the latest event at the first
+// time, the history events are not delivered to the new subscriptions.
+func (m *ManageModular) dispatchWebhookChangeEvents(ctx context.Context) {
+	for {
+		cursor, err := m.baseApp.GfSpDB().GetWebhookCursor()
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to get webhook cursor", "error", err)
+		} else {
+			req := &metadatatypes.GfSpStreamChangeEventsRequest{Cursor: cursor, FromLatest: cursor == 0}
+			err = m.baseApp.GfSpClient().StreamChangeEvents(ctx, req, func(resp *metadatatypes.GfSpStreamChangeEventsResponse) error {
+				if len(resp.GetEvents()) == 0 {
+					return nil
+				}
+				return m.dispatchWebhookEvents(ctx, resp.GetEvents(), resp.GetCursor())
+			})
+			if err != nil {
+				log.CtxErrorw(ctx, "failed to stream change events for webhook", "cursor", cursor, "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(DefaultWebhookReconnectInterval):
+		}
+	}
+}
+
+// dispatchWebhookEvents generates the deliveries of the change events and moves the cursor.
+// The subscriptions of the deleted buckets are deleted before the cursor moves, so the bucket
+// that is created again with the same name does not inherit them, and their deliveries in the
+// same batch are dropped.
+func (m *ManageModular) dispatchWebhookEvents(ctx context.Context, events []*metadatatypes.ChangeEvent, cursor uint64) error {
+	subs, err := m.baseApp.GfSpDB().ListAllWebhookSubscriptions()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list webhook subscriptions", "error", err)
+		return err
+	}
+	bucketSubs := make(map[string][]*spdb.WebhookSubscription)
+	for _, sub := range subs {
+		bucketSubs[sub.BucketName] = append(bucketSubs[sub.BucketName], sub)
+	}
+
+	var (
+		deliveries       []*spdb.WebhookDelivery
+		deletedBucketIDs []uint64
+	)
+	for _, event := range events {
+		if event.GetEventType() == model.ChangeBucketDeleted && len(bucketSubs[event.GetBucketName()]) != 0 {
+			bucketID, err := util.StringToUint64(event.GetResourceId())
+			if err != nil {
+				log.CtxErrorw(ctx, "failed to parse deleted bucket id", "bucket_id", event.GetResourceId(), "error", err)
+				continue
+			}
+			deletedBucketIDs = append(deletedBucketIDs, bucketID)
+			continue
+		}
+		eventType, ok := webhookChangeEvents[event.GetEventType()]
+		if !ok {
+			continue
+		}
+		for _, sub := range bucketSubs[event.GetBucketName()] {
+			if !webhookSubscribed(sub, eventType) {
+				continue
+			}
+			payload := &WebhookPayload{
+				EventID:     "change-" + util.Uint64ToString(event.GetCursor()),
+				EventType:   eventType,
+				BucketName:  event.GetBucketName(),
+				ObjectName:  event.GetObjectName(),
+				ObjectID:    event.GetResourceId(),
+				BlockHeight: event.GetBlockHeight(),
+				TxHash:      event.GetTxHash(),
+				Timestamp:   event.GetTimestamp(),
+			}
+			delivery, err := newWebhookDelivery(sub, payload)
+			if err != nil {
+				log.CtxErrorw(ctx, "failed to encode webhook payload", "event_id", payload.EventID, "error", err)
+				continue
+			}
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	// the deletion is idempotent, it is retried with the replayed events if it fails
+	deletedSubs := make(map[uint64]bool)
+	for _, bucketID := range deletedBucketIDs {
+		if err = m.baseApp.GfSpDB().DeleteBucketWebhookSubscriptions(bucketID); err != nil {
+			log.CtxErrorw(ctx, "failed to delete webhook subscriptions of deleted bucket", "bucket_id", bucketID, "error", err)
+			return err
+		}
+		for _, sub := range subs {
+			if sub.BucketID == bucketID {
+				deletedSubs[sub.SubscriptionID] = true
+			}
+		}
+	}
+	if len(deletedSubs) != 0 {
+		remained := deliveries[:0]
+		for _, delivery := range deliveries {
+			if !deletedSubs[delivery.SubscriptionID] {
+				remained = append(remained, delivery)
+			}
+		}
+		deliveries = remained
+	}
+	if err = m.baseApp.GfSpDB().InsertWebhookDeliveries(deliveries, cursor); err != nil {
+		log.CtxErrorw(ctx, "failed to insert webhook deliveries", "cursor", cursor, "error", err)
+		return err
+	}
+	for _, delivery := range deliveries {
+		metrics.WebhookEventCounter.WithLabelValues(delivery.EventType).Inc()
+	}
+	return nil
+}
+
+func newWebhookDelivery(sub *spdb.WebhookSubscription, payload *WebhookPayload) (*spdb.WebhookDelivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &spdb.WebhookDelivery{
+		SubscriptionID: sub.SubscriptionID,
+		EventID:        payload.EventID,
+		EventType:      payload.EventType,
+		Payload:        string(body),
+	}, nil
+}
+
+// newWebhookClient returns the client of the deliveries, it only connects the public addresses
+// and does not follow the redirects, so the subscriber can not make the sp request the services
+// inside the sp network. The proxy of the environment is not used for the same reason.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   DefaultWebhookDeliveryTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   util.PublicDialControl,
+	}
+	return &http.Client{
+		Timeout: DefaultWebhookDeliveryTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: DefaultWebhookDeliveryParallel,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: DefaultWebhookDeliveryTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// deliverWebhookLoop delivers the due deliveries and checks the read quota of the subscribed
+// buckets periodically.
+func (m *ManageModular) deliverWebhookLoop(ctx context.Context) {
+	client := newWebhookClient()
+	deliveryTicker := time.NewTicker(DefaultWebhookDeliveryInterval)
+	defer deliveryTicker.Stop()
+	quotaTicker := time.NewTicker(DefaultWebhookQuotaCheckInterval)
+	defer quotaTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deliveryTicker.C:
+			m.deliverWebhooks(ctx, client)
+		case <-quotaTicker.C:
+			m.checkWebhookQuota(ctx)
+		}
+	}
+}
+
+// deliverWebhooks delivers a batch of the due deliveries concurrently.
+func (m *ManageModular) deliverWebhooks(ctx context.Context, client *http.Client) {
+	deliveries, err := m.baseApp.GfSpDB().ListDueWebhookDeliveries(time.Now().Unix(), DefaultWebhookDeliveryBatchSize)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list due webhook deliveries", "error", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	subs, err := m.baseApp.GfSpDB().ListAllWebhookSubscriptions()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list webhook subscriptions", "error", err)
+		return
+	}
+	subMap := make(map[uint64]*spdb.WebhookSubscription, len(subs))
+	for _, sub := range subs {
+		subMap[sub.SubscriptionID] = sub
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, DefaultWebhookDeliveryParallel)
+	for _, delivery := range deliveries {
+		sub, ok := subMap[delivery.SubscriptionID]
+		if !ok {
+			// the subscription is deleted after the deliveries are listed
+			continue
+		}
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(delivery *spdb.WebhookDelivery) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			m.deliverWebhook(ctx, client, sub, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// deliverWebhook posts the payload to the subscription, the failed delivery is retried with
+// the exponential backoff, and is moved to the dead letter table if the attempts are exhausted.
+func (m *ManageModular) deliverWebhook(ctx context.Context, client *http.Client,
+	sub *spdb.WebhookSubscription, delivery *spdb.WebhookDelivery) {
+	startTime := time.Now()
+	err := postWebhook(ctx, client, sub, delivery)
+	metrics.WebhookDeliveryTimeHistogram.WithLabelValues(delivery.EventType).Observe(time.Since(startTime).Seconds())
+	if err == nil {
+		metrics.WebhookDeliveryCounter.WithLabelValues(delivery.EventType, "succeed").Inc()
+		if err = m.baseApp.GfSpDB().DeleteWebhookDelivery(delivery.DeliveryID); err != nil {
+			log.CtxErrorw(ctx, "failed to delete webhook delivery", "delivery_id", delivery.DeliveryID, "error", err)
+		}
+		return
+	}
+
+	attempts := int(delivery.Attempts) + 1
+	log.CtxWarnw(ctx, "failed to deliver webhook", "subscription_id", sub.SubscriptionID,
+		"event_id", delivery.EventID, "attempts", attempts, "error", err)
+	if attempts >= m.webhookMaxAttempts {
+		metrics.WebhookDeliveryCounter.WithLabelValues(delivery.EventType, "dead_letter").Inc()
+		if err = m.baseApp.GfSpDB().MoveWebhookDeliveryToDeadLetter(delivery, err.Error()); err != nil {
+			log.CtxErrorw(ctx, "failed to move webhook delivery to dead letter", "delivery_id", delivery.DeliveryID, "error", err)
+		}
+		return
+	}
+	metrics.WebhookDeliveryCounter.WithLabelValues(delivery.EventType, "retry").Inc()
+	nextAttemptTime := time.Now().Add(m.webhookRetryBackoff(attempts)).Unix()
+	if err = m.baseApp.GfSpDB().UpdateWebhookDeliveryRetry(delivery.DeliveryID, nextAttemptTime, err.Error()); err != nil {
+		log.CtxErrorw(ctx, "failed to update webhook delivery retry", "delivery_id", delivery.DeliveryID, "error", err)
+	}
+}
+
+// webhookRetryBackoff returns the interval before the next attempt, it doubles at every retry.
+func (m *ManageModular) webhookRetryBackoff(attempts int) time.Duration {
+	backoff := time.Duration(m.webhookRetryInterval) * time.Second
+	for i := 1; i < attempts && backoff < DefaultWebhookMaxRetryInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > DefaultWebhookMaxRetryInterval {
+		backoff = DefaultWebhookMaxRetryInterval
+	}
+	return backoff
+}
+
+func postWebhook(ctx context.Context, client *http.Client, sub *spdb.WebhookSubscription, delivery *spdb.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookEventIDHeader, delivery.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, timestamp, body))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// checkWebhookQuota generates the bucket quota events of the subscribed buckets whose consumed
+// read quota of the month reaches the threshold, the event is generated once a month.
+func (m *ManageModular) checkWebhookQuota(ctx context.Context) {
+	subs, err := m.baseApp.GfSpDB().ListAllWebhookSubscriptions()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list webhook subscriptions", "error", err)
+		return
+	}
+	now := time.Now()
+	yearMonth := now.Format("2006-01")
+	for _, sub := range subs {
+		if sub.QuotaNotifiedMonth == yearMonth || !webhookSubscribed(sub, spdb.WebhookEventBucketQuotaNearExhausted) {
+			continue
+		}
+		traffic, err := m.baseApp.GfSpDB().GetBucketTraffic(sub.BucketID, yearMonth)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the bucket has no read traffic in this month
+			continue
+		}
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to get bucket traffic", "bucket_id", sub.BucketID, "error", err)
+			continue
+		}
+		if traffic.ReadQuotaSize == 0 ||
+			traffic.ReadConsumedSize*100 < traffic.ReadQuotaSize*uint64(sub.QuotaThreshold) {
+			continue
+		}
+		payload := &WebhookPayload{
+			EventID:          "quota-" + yearMonth,
+			EventType:        spdb.WebhookEventBucketQuotaNearExhausted,
+			BucketName:       sub.BucketName,
+			Timestamp:        now.Unix(),
+			YearMonth:        yearMonth,
+			ReadConsumedSize: traffic.ReadConsumedSize,
+			ReadQuotaSize:    traffic.ReadQuotaSize,
+		}
+		delivery, err := newWebhookDelivery(sub, payload)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to encode webhook payload", "event_id", payload.EventID, "error", err)
+			continue
+		}
+		inserted, err := m.baseApp.GfSpDB().InsertQuotaWebhookDelivery(delivery, yearMonth)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to insert quota webhook delivery", "subscription_id", sub.SubscriptionID, "error", err)
+			continue
+		}
+		if inserted {
+			metrics.WebhookEventCounter.WithLabelValues(delivery.EventType).Inc()
+		}
+	}
+}
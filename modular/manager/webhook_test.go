@@ -0,0 +1,191 @@
+package manager
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event_id":"change-1"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(`1700000000.{"event_id":"change-1"}`))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), SignWebhookPayload("secret", 1700000000, body))
+	assert.NotEqual(t, SignWebhookPayload("secret", 1700000000, body), SignWebhookPayload("secret", 1700000001, body))
+	assert.NotEqual(t, SignWebhookPayload("secret", 1700000000, body), SignWebhookPayload("other", 1700000000, body))
+}
+
+func TestWebhookRetryBackoff(t *testing.T) {
+	m := &ManageModular{webhookRetryInterval: 60}
+	assert.Equal(t, time.Minute, m.webhookRetryBackoff(1))
+	assert.Equal(t, 2*time.Minute, m.webhookRetryBackoff(2))
+	assert.Equal(t, 32*time.Minute, m.webhookRetryBackoff(6))
+	assert.Equal(t, DefaultWebhookMaxRetryInterval, m.webhookRetryBackoff(7))
+	assert.Equal(t, DefaultWebhookMaxRetryInterval, m.webhookRetryBackoff(1000))
+}
+
+func TestDispatchWebhookEvents(t *testing.T) {
+	m, db := setupAdminManager(t)
+	subs := []*spdb.WebhookSubscription{
+		{SubscriptionID: 1, BucketID: 10, BucketName: "bucket", Events: []string{spdb.WebhookEventObjectSealed}},
+		{SubscriptionID: 2, BucketID: 10, BucketName: "bucket", Events: []string{spdb.WebhookEventObjectDeleted}},
+		{SubscriptionID: 3, BucketID: 20, BucketName: "deleted", Events: []string{spdb.WebhookEventObjectSealed}},
+	}
+	events := []*metadatatypes.ChangeEvent{
+		{Cursor: 1, EventType: model.ChangeObjectSealed, BucketName: "bucket", ObjectName: "a", ResourceId: "100"},
+		{Cursor: 2, EventType: model.ChangeObjectDeleted, BucketName: "bucket", ObjectName: "b", ResourceId: "101"},
+		{Cursor: 3, EventType: model.ChangeObjectCreated, BucketName: "bucket", ObjectName: "c", ResourceId: "102"},
+		{Cursor: 4, EventType: model.ChangeObjectSealed, BucketName: "unsubscribed", ObjectName: "d", ResourceId: "103"},
+		{Cursor: 5, EventType: model.ChangeObjectSealed, BucketName: "deleted", ObjectName: "e", ResourceId: "104"},
+		{Cursor: 6, EventType: model.ChangeBucketDeleted, BucketName: "deleted", ResourceId: "20"},
+	}
+	db.EXPECT().ListAllWebhookSubscriptions().Return(subs, nil)
+	gomock.InOrder(
+		db.EXPECT().DeleteBucketWebhookSubscriptions(uint64(20)).Return(nil),
+		db.EXPECT().InsertWebhookDeliveries(gomock.Any(), uint64(6)).DoAndReturn(
+			func(deliveries []*spdb.WebhookDelivery, cursor uint64) error {
+				require.Len(t, deliveries, 2)
+				assert.Equal(t, uint64(1), deliveries[0].SubscriptionID)
+				assert.Equal(t, "change-1", deliveries[0].EventID)
+				assert.Equal(t, spdb.WebhookEventObjectSealed, deliveries[0].EventType)
+				assert.Equal(t, uint64(2), deliveries[1].SubscriptionID)
+				assert.Equal(t, "change-2", deliveries[1].EventID)
+				assert.Equal(t, spdb.WebhookEventObjectDeleted, deliveries[1].EventType)
+				var payload WebhookPayload
+				require.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+				assert.Equal(t, "bucket", payload.BucketName)
+				assert.Equal(t, "a", payload.ObjectName)
+				assert.Equal(t, "100", payload.ObjectID)
+				return nil
+			}),
+	)
+	require.NoError(t, m.dispatchWebhookEvents(context.Background(), events, 6))
+
+	// the cursor does not move if the subscriptions of the deleted bucket fail to be deleted
+	db.EXPECT().ListAllWebhookSubscriptions().Return(subs, nil)
+	db.EXPECT().DeleteBucketWebhookSubscriptions(uint64(20)).Return(errors.New("mock error"))
+	require.Error(t, m.dispatchWebhookEvents(context.Background(), events, 6))
+}
+
+func TestCheckWebhookQuota(t *testing.T) {
+	m, db := setupAdminManager(t)
+	yearMonth := time.Now().Format("2006-01")
+	quotaEvents := []string{spdb.WebhookEventBucketQuotaNearExhausted}
+	db.EXPECT().ListAllWebhookSubscriptions().Return([]*spdb.WebhookSubscription{
+		{SubscriptionID: 1, BucketID: 1, BucketName: "reached", Events: quotaEvents, QuotaThreshold: 90},
+		{SubscriptionID: 2, BucketID: 2, BucketName: "below", Events: quotaEvents, QuotaThreshold: 90},
+		{SubscriptionID: 3, BucketID: 3, BucketName: "notified", Events: quotaEvents, QuotaThreshold: 90, QuotaNotifiedMonth: yearMonth},
+		{SubscriptionID: 4, BucketID: 4, BucketName: "unsubscribed", Events: []string{spdb.WebhookEventObjectSealed}, QuotaThreshold: 90},
+		{SubscriptionID: 5, BucketID: 5, BucketName: "no-quota", Events: quotaEvents, QuotaThreshold: 90},
+		{SubscriptionID: 6, BucketID: 6, BucketName: "no-traffic", Events: quotaEvents, QuotaThreshold: 90},
+	}, nil)
+	db.EXPECT().GetBucketTraffic(uint64(1), yearMonth).Return(&spdb.BucketTraffic{ReadConsumedSize: 90, ReadQuotaSize: 100}, nil)
+	db.EXPECT().GetBucketTraffic(uint64(2), yearMonth).Return(&spdb.BucketTraffic{ReadConsumedSize: 89, ReadQuotaSize: 100}, nil)
+	db.EXPECT().GetBucketTraffic(uint64(5), yearMonth).Return(&spdb.BucketTraffic{ReadConsumedSize: 10}, nil)
+	db.EXPECT().GetBucketTraffic(uint64(6), yearMonth).Return(nil, gorm.ErrRecordNotFound)
+	db.EXPECT().InsertQuotaWebhookDelivery(gomock.Any(), yearMonth).DoAndReturn(
+		func(delivery *spdb.WebhookDelivery, yearMonth string) (bool, error) {
+			assert.Equal(t, uint64(1), delivery.SubscriptionID)
+			assert.Equal(t, "quota-"+yearMonth, delivery.EventID)
+			var payload WebhookPayload
+			require.NoError(t, json.Unmarshal([]byte(delivery.Payload), &payload))
+			assert.Equal(t, "reached", payload.BucketName)
+			assert.Equal(t, uint64(90), payload.ReadConsumedSize)
+			assert.Equal(t, uint64(100), payload.ReadQuotaSize)
+			return true, nil
+		})
+	m.checkWebhookQuota(context.Background())
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	m, db := setupAdminManager(t)
+	m.webhookMaxAttempts = 3
+	m.webhookRetryInterval = 60
+	sub := &spdb.WebhookSubscription{SubscriptionID: 1, Secret: "secret"}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		timestamp, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, SignWebhookPayload("secret", timestamp, body), r.Header.Get(WebhookSignatureHeader))
+		assert.Equal(t, spdb.WebhookEventObjectSealed, r.Header.Get(WebhookEventHeader))
+		assert.Equal(t, "change-1", r.Header.Get(WebhookEventIDHeader))
+		assert.Equal(t, `{"event_id":"change-1"}`, string(body))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	sub.URL = server.URL
+	newDelivery := func(attempts uint32) *spdb.WebhookDelivery {
+		return &spdb.WebhookDelivery{DeliveryID: 7, SubscriptionID: 1, EventID: "change-1",
+			EventType: spdb.WebhookEventObjectSealed, Payload: `{"event_id":"change-1"}`, Attempts: attempts}
+	}
+
+	// the delivered delivery is deleted
+	db.EXPECT().DeleteWebhookDelivery(uint64(7)).Return(nil)
+	m.deliverWebhook(context.Background(), server.Client(), sub, newDelivery(0))
+
+	// the failed delivery is retried with the backoff
+	status = http.StatusInternalServerError
+	db.EXPECT().UpdateWebhookDeliveryRetry(uint64(7), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(deliveryID uint64, nextAttemptTime int64, lastError string) error {
+			assert.InDelta(t, time.Now().Add(2*time.Minute).Unix(), nextAttemptTime, 2)
+			assert.Contains(t, lastError, "500")
+			return nil
+		})
+	m.deliverWebhook(context.Background(), server.Client(), sub, newDelivery(1))
+
+	// the delivery that exhausts the attempts is moved to the dead letter table
+	db.EXPECT().MoveWebhookDeliveryToDeadLetter(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(delivery *spdb.WebhookDelivery, lastError string) error {
+			assert.Equal(t, uint64(7), delivery.DeliveryID)
+			assert.Contains(t, lastError, "500")
+			return nil
+		})
+	m.deliverWebhook(context.Background(), server.Client(), sub, newDelivery(2))
+}
+
+func TestNewWebhookClient(t *testing.T) {
+	var posted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/hook", http.StatusTemporaryRedirect)
+			return
+		}
+		posted++
+	}))
+	defer server.Close()
+	delivery := &spdb.WebhookDelivery{EventID: "change-1", EventType: spdb.WebhookEventObjectSealed, Payload: "{}"}
+
+	// the loopback address of the sp network is refused at dial time
+	err := postWebhook(context.Background(), newWebhookClient(), &spdb.WebhookSubscription{URL: server.URL + "/hook"}, delivery)
+	assert.ErrorIs(t, err, util.ErrNonPublicAddress)
+	assert.Equal(t, 0, posted)
+
+	// the redirect is not followed, it fails the delivery
+	client := newWebhookClient()
+	client.Transport = http.DefaultTransport
+	err = postWebhook(context.Background(), client, &spdb.WebhookSubscription{URL: server.URL + "/redirect"}, delivery)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "307")
+	assert.Equal(t, 0, posted)
+}
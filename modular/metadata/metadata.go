@@ -3,8 +3,10 @@ package metadata
 import (
 	"context"
 	"strings"
+	"sync/atomic"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 )
@@ -19,6 +21,7 @@ const (
 )
 
 var _ module.Modular = &MetadataModular{}
+var _ gfspapp.Reloadable = &MetadataModular{}
 
 type MetadataModular struct {
 	baseApp *gfspapp.GfSpBaseApp
@@ -47,6 +50,15 @@ func (r *MetadataModular) Start(ctx context.Context) error {
 	return nil
 }
 
+// Reload applies the changed max handling metadata request number.
+func (r *MetadataModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if cfg.Parallel.QuerySPParallelPerNode == 0 {
+		cfg.Parallel.QuerySPParallelPerNode = DefaultQuerySPParallelPerNode
+	}
+	atomic.StoreInt64(&r.maxMetadataRequest, cfg.Parallel.QuerySPParallelPerNode)
+	return nil
+}
+
 func (r *MetadataModular) Stop(ctx context.Context) error {
 	r.scope.Release()
 	//r.dbSwitchTicker.Stop()
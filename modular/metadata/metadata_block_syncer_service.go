@@ -0,0 +1,55 @@
+package metadata
+
+import (
+	"context"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+// GfSpGetBlockSyncerStatus get the sync progress, mode and speed of the block syncer, the lag is
+// counted by the latest block height of the chain, and the eta is estimated in the catch-up mode
+func (r *MetadataModular) GfSpGetBlockSyncerStatus(ctx context.Context, req *types.GfSpGetBlockSyncerStatusRequest) (resp *types.GfSpGetBlockSyncerStatusResponse, err error) {
+	var (
+		blockHeight       int64
+		latestBlockHeight uint64
+		status            *model.BlockSyncerStatus
+	)
+
+	ctx = log.Context(ctx, req)
+	blockHeight, err = r.baseApp.GfBsDB().GetLatestBlockNumber()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get latest block number", "error", err)
+		return nil, err
+	}
+	status, err = r.baseApp.GfBsDB().GetBlockSyncerStatus()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get block syncer status", "error", err)
+		return nil, err
+	}
+	latestBlockHeight, err = r.baseApp.Consensus().CurrentHeight(ctx)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get current height", "error", err)
+		return nil, err
+	}
+
+	resp = &types.GfSpGetBlockSyncerStatusResponse{
+		BlockHeight:        blockHeight,
+		LatestBlockHeight:  int64(latestBlockHeight),
+		Mode:               status.Mode,
+		CatchUpStartHeight: status.CatchUpStartHeight,
+		UpdateTime:         status.UpdateTime,
+	}
+	if resp.LatestBlockHeight > blockHeight {
+		resp.Lag = resp.LatestBlockHeight - blockHeight
+	}
+	if status.Mode == model.BlockSyncerModeCatchUp {
+		resp.BlocksPerSecond = status.BlocksPerSecond
+		if status.BlocksPerSecond > 0 {
+			resp.EtaSeconds = int64(float64(resp.Lag) / status.BlocksPerSecond)
+		}
+	}
+	log.CtxInfow(ctx, "succeed to get block syncer status", "block_height", blockHeight, "mode", status.Mode)
+	return resp, nil
+}
@@ -25,6 +25,9 @@ var (
 	ErrDanglingPointer = gfsperrors.Register(MetadataModularName, http.StatusBadRequest, 90001, "OoooH... request lost, try again later")
 	ErrExceedRequest   = gfsperrors.Register(MetadataModularName, http.StatusNotAcceptable, 90002, "request exceed")
 	ErrNoRecord        = gfsperrors.Register(MetadataModularName, http.StatusNotFound, 90003, "no uploading record")
+	ErrBucketNotFound  = gfsperrors.Register(MetadataModularName, http.StatusNotFound, 90004, "the specified bucket does not exist")
+	ErrInvalidAccount  = gfsperrors.Register(MetadataModularName, http.StatusBadRequest, 90005, "invalid account address")
+	ErrInvalidFilter   = gfsperrors.Register(MetadataModularName, http.StatusBadRequest, 90006, "invalid list objects filter")
 	ErrGfSpDB          = gfsperrors.Register(MetadataModularName, http.StatusInternalServerError, 95202, "server slipped away, try again later")
 )
 
@@ -392,6 +395,40 @@ func (r *MetadataModular) GfSpListBucketReadRecord(
 	return resp, nil
 }
 
+// GfSpGetBucketReadUsage returns the daily read usage of the bucket, it is served by the daily
+// rollups, so the read records are never scanned.
+func (r *MetadataModular) GfSpGetBucketReadUsage(
+	ctx context.Context,
+	req *types.GfSpGetBucketReadUsageRequest) (
+	*types.GfSpGetBucketReadUsageResponse,
+	error) {
+	if req.GetBucketInfo() == nil {
+		return nil, ErrDanglingPointer
+	}
+	defer atomic.AddInt64(&r.retrievingRequest, -1)
+	if atomic.AddInt64(&r.retrievingRequest, 1) >
+		atomic.LoadInt64(&r.maxMetadataRequest) {
+		return nil, ErrExceedRequest
+	}
+	usages, err := r.baseApp.GfSpDB().GetBucketReadUsage(req.GetBucketInfo().Id.Uint64(),
+		req.GetStartDay(), req.GetEndDay())
+	if err != nil {
+		log.Errorw("failed to get bucket read usage",
+			"bucket_name", req.GetBucketInfo().GetBucketName(),
+			"bucket_id", req.GetBucketInfo().Id.String(), "error", err)
+		return &types.GfSpGetBucketReadUsageResponse{Err: ErrGfSpDB}, nil
+	}
+	readUsages := make([]*types.BucketReadUsage, 0, len(usages))
+	for _, usage := range usages {
+		readUsages = append(readUsages, &types.BucketReadUsage{
+			Day:       usage.Day,
+			ReadSize:  usage.ReadSize,
+			ReadCount: usage.ReadCount,
+		})
+	}
+	return &types.GfSpGetBucketReadUsageResponse{Usages: readUsages}, nil
+}
+
 // GfSpListBucketsByBucketID list buckets by bucket ids
 func (r *MetadataModular) GfSpListBucketsByBucketID(ctx context.Context, req *types.GfSpListBucketsByBucketIDRequest) (resp *types.GfSpListBucketsByBucketIDResponse, err error) {
 	var (
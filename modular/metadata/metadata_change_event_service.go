@@ -0,0 +1,99 @@
+package metadata
+
+import (
+	"time"
+
+	"cosmossdk.io/math"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+const (
+	// DefaultChangeEventsPollInterval defines the interval to query the new change events if
+	// the stream has caught up with the change feed.
+	DefaultChangeEventsPollInterval = time.Second
+	// DefaultChangeEventsHeartbeatInterval defines the interval to send the heartbeat if there
+	// is no new change event, so the idle stream is not closed by the proxies.
+	DefaultChangeEventsHeartbeatInterval = 15 * time.Second
+)
+
+// the intervals of the change events stream, they are shortened by the tests.
+var (
+	changeEventsPollInterval      = DefaultChangeEventsPollInterval
+	changeEventsHeartbeatInterval = DefaultChangeEventsHeartbeatInterval
+)
+
+// GfSpStreamChangeEvents streams the change events after the cursor until the client cancels
+// the stream, the events are appended by the block syncer.
+func (r *MetadataModular) GfSpStreamChangeEvents(req *types.GfSpStreamChangeEventsRequest,
+	stream types.GfSpMetadataService_GfSpStreamChangeEventsServer) (err error) {
+	var (
+		events   []*model.ChangeEvent
+		cursor   = req.Cursor
+		lastSent = time.Now()
+	)
+
+	ctx := log.Context(stream.Context(), req)
+	if cursor == 0 && req.FromLatest {
+		if cursor, err = r.baseApp.GfBsDB().GetLatestChangeEventCursor(); err != nil {
+			log.CtxErrorw(ctx, "failed to get latest change event cursor", "error", err)
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(changeEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		events, err = r.baseApp.GfBsDB().ListChangeEvents(cursor, req.BucketName, req.Prefix, model.ChangeEventsDefaultSize)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to list change events", "cursor", cursor, "error", err)
+			return err
+		}
+		if len(events) > 0 || time.Since(lastSent) >= changeEventsHeartbeatInterval {
+			if len(events) > 0 {
+				cursor = events[len(events)-1].ID
+			}
+			if err = stream.Send(&types.GfSpStreamChangeEventsResponse{
+				Events: toChangeEvents(events),
+				Cursor: cursor,
+			}); err != nil {
+				log.CtxErrorw(ctx, "failed to send change events", "cursor", cursor, "error", err)
+				return err
+			}
+			lastSent = time.Now()
+		}
+		// continue immediately if the stream has not caught up with the change feed
+		if len(events) == model.ChangeEventsDefaultSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			log.CtxInfow(ctx, "change events stream is closed", "cursor", cursor)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func toChangeEvents(events []*model.ChangeEvent) []*types.ChangeEvent {
+	res := make([]*types.ChangeEvent, 0, len(events))
+	for _, event := range events {
+		res = append(res, &types.ChangeEvent{
+			Cursor:       event.ID,
+			BlockHeight:  event.BlockHeight,
+			Timestamp:    event.Timestamp,
+			TxHash:       event.TxHash.String(),
+			EventType:    event.EventType,
+			ResourceType: event.ResourceType,
+			ResourceId:   math.NewUintFromBigInt(event.ResourceID.Big()).String(),
+			BucketName:   event.BucketName,
+			ObjectName:   event.ObjectName,
+			GroupName:    event.GroupName,
+			Operator:     event.Operator,
+			Attributes:   event.Attributes,
+		})
+	}
+	return res
+}
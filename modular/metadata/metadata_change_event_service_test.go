@@ -0,0 +1,109 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+)
+
+type mockChangeEventsStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sent    chan *types.GfSpStreamChangeEventsResponse
+	sendErr error
+}
+
+func (s *mockChangeEventsStream) Context() context.Context { return s.ctx }
+
+func (s *mockChangeEventsStream) Send(resp *types.GfSpStreamChangeEventsResponse) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent <- resp
+	return nil
+}
+
+func setupStreamChangeEvents(t *testing.T) (*MetadataModular, *model.MockBSDB) {
+	pollInterval, heartbeatInterval := changeEventsPollInterval, changeEventsHeartbeatInterval
+	changeEventsPollInterval, changeEventsHeartbeatInterval = 10*time.Millisecond, 100*time.Millisecond
+	t.Cleanup(func() {
+		changeEventsPollInterval, changeEventsHeartbeatInterval = pollInterval, heartbeatInterval
+	})
+	bsDB := model.NewMockBSDB(gomock.NewController(t))
+	r := &MetadataModular{baseApp: &gfspapp.GfSpBaseApp{}}
+	r.baseApp.SetGfBsDB(bsDB)
+	return r, bsDB
+}
+
+func changeEvents(from, to uint64) []*model.ChangeEvent {
+	var events []*model.ChangeEvent
+	for id := from; id <= to; id++ {
+		events = append(events, &model.ChangeEvent{ID: id, EventType: model.ChangeObjectCreated})
+	}
+	return events
+}
+
+func TestMetadataModular_GfSpStreamChangeEvents(t *testing.T) {
+	r, bsDB := setupStreamChangeEvents(t)
+	full := changeEvents(6, 5+model.ChangeEventsDefaultSize)
+	last := full[len(full)-1].ID
+	gomock.InOrder(
+		bsDB.EXPECT().GetLatestChangeEventCursor().Return(uint64(5), nil),
+		// the full page is followed by the next query immediately
+		bsDB.EXPECT().ListChangeEvents(uint64(5), "bucket", "dir/", model.ChangeEventsDefaultSize).Return(full, nil),
+		bsDB.EXPECT().ListChangeEvents(last, "bucket", "dir/", model.ChangeEventsDefaultSize).Return(changeEvents(last+1, last+1), nil),
+		bsDB.EXPECT().ListChangeEvents(last+1, "bucket", "dir/", model.ChangeEventsDefaultSize).Return(nil, nil).AnyTimes(),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockChangeEventsStream{ctx: ctx, sent: make(chan *types.GfSpStreamChangeEventsResponse, 10)}
+	done := make(chan error)
+	start := time.Now()
+	go func() {
+		done <- r.GfSpStreamChangeEvents(&types.GfSpStreamChangeEventsRequest{BucketName: "bucket", Prefix: "dir/",
+			FromLatest: true}, stream)
+	}()
+
+	resp := <-stream.sent
+	assert.Len(t, resp.GetEvents(), model.ChangeEventsDefaultSize)
+	assert.Equal(t, last, resp.GetCursor())
+	assert.Equal(t, uint64(6), resp.GetEvents()[0].GetCursor())
+	resp = <-stream.sent
+	require.Len(t, resp.GetEvents(), 1)
+	assert.Equal(t, last+1, resp.GetCursor())
+	// the idle stream sends the heartbeat with the same cursor
+	resp = <-stream.sent
+	assert.Empty(t, resp.GetEvents())
+	assert.Equal(t, last+1, resp.GetCursor())
+	assert.GreaterOrEqual(t, time.Since(start), changeEventsHeartbeatInterval)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestMetadataModular_GfSpStreamChangeEventsFailure(t *testing.T) {
+	r, bsDB := setupStreamChangeEvents(t)
+	// the cursor of the request is used even if it is from the latest
+	bsDB.EXPECT().ListChangeEvents(uint64(3), "", "", model.ChangeEventsDefaultSize).Return(nil, errors.New("mock error"))
+	stream := &mockChangeEventsStream{ctx: context.Background(), sent: make(chan *types.GfSpStreamChangeEventsResponse, 1)}
+	err := r.GfSpStreamChangeEvents(&types.GfSpStreamChangeEventsRequest{Cursor: 3, FromLatest: true}, stream)
+	assert.Error(t, err)
+
+	bsDB.EXPECT().GetLatestChangeEventCursor().Return(uint64(0), errors.New("mock error"))
+	err = r.GfSpStreamChangeEvents(&types.GfSpStreamChangeEventsRequest{FromLatest: true}, stream)
+	assert.Error(t, err)
+
+	bsDB.EXPECT().ListChangeEvents(uint64(0), "", "", model.ChangeEventsDefaultSize).Return(changeEvents(1, 1), nil)
+	stream.sendErr = errors.New("mock error")
+	err = r.GfSpStreamChangeEvents(&types.GfSpStreamChangeEventsRequest{}, stream)
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 
 	"cosmossdk.io/math"
 	"github.com/bnb-chain/greenfield/types/s3util"
@@ -14,6 +15,13 @@ import (
 	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
 )
 
+// listObjectsSortBy maps the sort field of the request to the column of bsdb
+var listObjectsSortBy = map[types.ListObjectsSortBy]string{
+	types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_OBJECT_NAME:  model.SortByObjectName,
+	types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_CREATE_TIME:  model.SortByCreateTime,
+	types.ListObjectsSortBy_LIST_OBJECTS_SORT_BY_PAYLOAD_SIZE: model.SortByPayloadSize,
+}
+
 // GfSpListObjectsByBucketName list objects info by a bucket name
 func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *types.GfSpListObjectsByBucketNameRequest) (resp *types.GfSpListObjectsByBucketNameResponse, err error) {
 	var (
@@ -24,6 +32,7 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 		maxKeys               uint64
 		commonPrefixes        []string
 		res                   []*types.Object
+		filter                *model.ListObjectsFilter
 	)
 
 	maxKeys = req.MaxKeys
@@ -37,10 +46,30 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 		maxKeys = model.ListObjectsLimitSize
 	}
 
+	sortBy, ok := listObjectsSortBy[req.SortBy]
+	if !ok {
+		log.CtxErrorw(ctx, "failed to check sort by", "sort_by", req.SortBy)
+		return nil, ErrInvalidFilter
+	}
+	filter = &model.ListObjectsFilter{
+		ContentType:     req.ContentType,
+		MinPayloadSize:  req.MinPayloadSize,
+		MaxPayloadSize:  req.MaxPayloadSize,
+		StartCreateTime: req.StartCreateTime,
+		EndCreateTime:   req.EndCreateTime,
+		ObjectStatus:    req.ObjectStatus,
+		Owner:           req.Owner,
+		SortBy:          sortBy,
+		SortDesc:        req.SortDesc,
+	}
+
 	ctx = log.Context(ctx, req)
-	results, err = r.baseApp.GfBsDB().ListObjectsByBucketName(req.BucketName, req.ContinuationToken, req.Prefix, req.Delimiter, int(maxKeys), req.IncludeRemoved)
+	results, err = r.baseApp.GfBsDB().ListObjectsByBucketName(req.BucketName, req.ContinuationToken, req.Prefix, req.Delimiter, int(maxKeys), req.IncludeRemoved, filter)
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to list objects by bucket name", "error", err)
+		if errors.Is(err, model.ErrInvalidListObjectsFilter) {
+			err = ErrInvalidFilter
+		}
 		return
 	}
 
@@ -53,7 +82,7 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 		keyCount -= 1
 		nextContinuationToken = results[len(results)-1].PathName
 		if req.Delimiter == "" {
-			nextContinuationToken = results[len(results)-1].ObjectName
+			nextContinuationToken = filter.ContinuationToken(results[len(results)-1].Object)
 		}
 		results = results[:len(results)-1]
 	}
@@ -271,3 +300,51 @@ func (r *MetadataModular) GfSpListObjectsByObjectID(ctx context.Context, req *ty
 	log.CtxInfo(ctx, "succeed to list objects by object ids")
 	return resp, nil
 }
+
+// GfSpListObjectsBySp list the sealed objects whose primary or secondary sp is the sp
+func (r *MetadataModular) GfSpListObjectsBySp(ctx context.Context, req *types.GfSpListObjectsBySpRequest) (resp *types.GfSpListObjectsBySpResponse, err error) {
+	var (
+		objects []*model.Object
+		res     []*types.Object
+		endID   = req.StartId
+	)
+
+	objects, err = r.baseApp.GfBsDB().ListObjectsBySp(req.SpAddress, req.StartId, int(req.Limit))
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list objects by sp", "error", err)
+		return nil, err
+	}
+
+	res = make([]*types.Object, 0, len(objects))
+	for _, object := range objects {
+		res = append(res, &types.Object{
+			ObjectInfo: &storage_types.ObjectInfo{
+				Owner:                object.Owner.String(),
+				BucketName:           object.BucketName,
+				ObjectName:           object.ObjectName,
+				Id:                   math.NewUintFromBigInt(object.ObjectID.Big()),
+				PayloadSize:          object.PayloadSize,
+				ContentType:          object.ContentType,
+				CreateAt:             object.CreateTime,
+				ObjectStatus:         storage_types.ObjectStatus(storage_types.ObjectStatus_value[object.ObjectStatus]),
+				RedundancyType:       storage_types.RedundancyType(storage_types.RedundancyType_value[object.RedundancyType]),
+				SourceType:           storage_types.SourceType(storage_types.SourceType_value[object.SourceType]),
+				Checksums:            object.Checksums,
+				SecondarySpAddresses: object.SecondarySpAddresses,
+				Visibility:           storage_types.VisibilityType(storage_types.VisibilityType_value[object.Visibility]),
+			},
+			LockedBalance: object.LockedBalance.String(),
+			Removed:       object.Removed,
+			DeleteAt:      object.DeleteAt,
+			DeleteReason:  object.DeleteReason,
+			Operator:      object.Operator.String(),
+			CreateTxHash:  object.CreateTxHash.String(),
+			UpdateTxHash:  object.UpdateTxHash.String(),
+			SealTxHash:    object.SealTxHash.String(),
+		})
+		endID = object.ID
+	}
+	resp = &types.GfSpListObjectsBySpResponse{Objects: res, EndId: endID}
+	log.CtxInfow(ctx, "succeed to list objects by sp", "start_id", req.StartId, "end_id", endID, "count", len(res))
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+package metadata
+
+import (
+	"context"
+
+	"cosmossdk.io/math"
+	"github.com/forbole/juno/v4/common"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	model "github.com/bnb-chain/greenfield-storage-provider/store/bsdb"
+	"github.com/bnb-chain/greenfield/types/s3util"
+)
+
+// GfSpGetBucketStats get the storage stats of a bucket, they are counted by the block syncer
+func (r *MetadataModular) GfSpGetBucketStats(ctx context.Context, req *types.GfSpGetBucketStatsRequest) (resp *types.GfSpGetBucketStatsResponse, err error) {
+	var (
+		bucket *model.Bucket
+		stats  *model.BucketStats
+	)
+
+	ctx = log.Context(ctx, req)
+	if err = s3util.CheckValidBucketName(req.BucketName); err != nil {
+		log.CtxErrorw(ctx, "failed to check bucket name", "bucket_name", req.BucketName, "error", err)
+		return nil, err
+	}
+
+	bucket, err = r.baseApp.GfBsDB().GetBucketByName(req.BucketName, true)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get bucket by bucket name", "error", err)
+		return nil, err
+	}
+	if bucket == nil {
+		return nil, ErrBucketNotFound
+	}
+
+	stats, err = r.baseApp.GfBsDB().GetBucketStats(bucket.BucketID)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get bucket stats", "error", err)
+		return nil, err
+	}
+
+	resp = &types.GfSpGetBucketStatsResponse{
+		BucketName: bucket.BucketName,
+		BucketId:   math.NewUintFromBigInt(bucket.BucketID.Big()).String(),
+		Stats:      toStorageStats(&stats.StorageStats, stats.UpdateAt),
+	}
+	log.CtxInfo(ctx, "succeed to get bucket stats")
+	return resp, nil
+}
+
+// GfSpGetAccountStats get the storage stats of the objects owned by an account, they are counted by the block syncer
+func (r *MetadataModular) GfSpGetAccountStats(ctx context.Context, req *types.GfSpGetAccountStatsRequest) (resp *types.GfSpGetAccountStatsResponse, err error) {
+	var stats *model.AccountStats
+
+	ctx = log.Context(ctx, req)
+	if !common.IsHexAddress(req.AccountAddress) {
+		log.CtxErrorw(ctx, "failed to check account address", "account_address", req.AccountAddress)
+		return nil, ErrInvalidAccount
+	}
+
+	stats, err = r.baseApp.GfBsDB().GetAccountStats(common.HexToAddress(req.AccountAddress))
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get account stats", "error", err)
+		return nil, err
+	}
+
+	resp = &types.GfSpGetAccountStatsResponse{
+		AccountAddress: stats.Owner.String(),
+		Stats:          toStorageStats(&stats.StorageStats, stats.UpdateAt),
+	}
+	log.CtxInfo(ctx, "succeed to get account stats")
+	return resp, nil
+}
+
+func toStorageStats(stats *model.StorageStats, updateAt int64) *types.StorageStats {
+	return &types.StorageStats{
+		ObjectCount:   uint64(stats.ObjectCount),
+		PayloadSize:   uint64(stats.PayloadSize),
+		SealedCount:   uint64(stats.SealedCount),
+		UnsealedCount: uint64(stats.UnsealedCount),
+		UpdateAt:      updateAt,
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
@@ -18,6 +19,7 @@ import (
 const UpdateSPDuration = 2
 
 var _ module.P2P = &P2PModular{}
+var _ gfspapp.Reloadable = &P2PModular{}
 
 type P2PModular struct {
 	baseApp                *gfspapp.GfSpBaseApp
@@ -43,6 +45,15 @@ func (p *P2PModular) Start(ctx context.Context) error {
 	return nil
 }
 
+// Reload applies the changed capacity of the asking replicate approval queue.
+func (p *P2PModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if cfg.Parallel.AskReplicateApprovalParallelPerNode == 0 {
+		cfg.Parallel.AskReplicateApprovalParallelPerNode = DefaultAskReplicateApprovalParallelPerNode
+	}
+	p.replicateApprovalQueue.SetCap(cfg.Parallel.AskReplicateApprovalParallelPerNode)
+	return nil
+}
+
 func (p *P2PModular) Stop(ctx context.Context) error {
 	p.node.Stop(ctx)
 	p.scope.Release()
@@ -1,12 +1,15 @@
 package p2p
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/modular/p2p/p2pnode"
+	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
 )
 
 const (
@@ -45,6 +48,20 @@ func DefaultP2POptions(p2p *P2PModular, cfg *gfspconfig.GfSpConfig) error {
 	if err != nil {
 		return err
 	}
+	var supportedRedundancy []storagetypes.RedundancyType
+	for _, redundancy := range cfg.P2P.SupportedRedundancy {
+		val, ok := storagetypes.RedundancyType_value[redundancy]
+		if !ok {
+			return fmt.Errorf("unknown supported redundancy type '%s'", redundancy)
+		}
+		supportedRedundancy = append(supportedRedundancy, storagetypes.RedundancyType(val))
+	}
+	// only the capacity of disk file piece store can be measured
+	var capacityPath string
+	if cfg.PieceStore.Store.Storage == storage.DiskFileStore {
+		capacityPath = cfg.PieceStore.Store.BucketURL
+	}
+	node.SetSecondaryStatusOptions(capacityPath, supportedRedundancy)
 	p2p.node = node
 	return nil
 }
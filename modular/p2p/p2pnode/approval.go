@@ -152,6 +152,7 @@ func (a *ApprovalProtocol) onGetApprovalRequest(s network.Stream) {
 	}
 	req.SetApprovedSignature(signature)
 	req.SetApprovedSpOperatorAddress(a.node.baseApp.OperatorAddress())
+	req.SetApprovedSpStatus(a.node.localStatus())
 	err = a.node.sendToPeer(ctx, s.Conn().RemotePeer(), GetApprovalResponse, req)
 	log.Infof("%s response to %s approval request, task_key: %s, error: %v",
 		s.Conn().LocalPeer(), s.Conn().RemotePeer(), req.Key().String(), err)
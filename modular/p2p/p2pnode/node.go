@@ -36,6 +36,8 @@ type Node struct {
 	peers        *PeerProvider
 	persistentDB ds.Batching
 	approval     *ApprovalProtocol
+	status       *secondaryStatusProvider
+	peersStatus  *secondaryStatusCache
 	stopCh       chan struct{}
 
 	p2pPrivateKey                  crypto.PrivKey
@@ -124,6 +126,7 @@ func NewNode(baseApp *gfspapp.GfSpBaseApp, privateKey string, address string,
 		baseApp:                        baseApp,
 		node:                           host,
 		peers:                          NewPeerProvider(store),
+		peersStatus:                    newSecondaryStatusCache(),
 		persistentDB:                   ds,
 		p2pPrivateKey:                  privKey,
 		p2pProtocolAddress:             hostAddr,
@@ -213,6 +216,12 @@ func (n *Node) GetSecondaryReplicatePieceApproval(
 			}
 			log.CtxDebugw(ctx, "append replicate approval",
 				"approval_op_address", approval.GetStorageParams())
+			// the sp of old version does not carry status in approval response,
+			// use the status received from pong instead.
+			if gfspApproval, ok := approval.(*gfsptask.GfSpReplicatePieceApprovalTask); ok &&
+				gfspApproval.GetApprovedSpStatus() == nil {
+				gfspApproval.SetApprovedSpStatus(n.peersStatus.get(approval.GetApprovedSpOperatorAddress()))
+			}
 			accept = append(accept, approval)
 			if len(accept) >= expectedAccept {
 				log.CtxErrorw(ctx, "succeed to get sufficient approvals",
@@ -85,6 +85,7 @@ func (n *Node) onPing(s network.Stream) {
 	}
 
 	pong.SpOperatorAddress = n.baseApp.OperatorAddress()
+	pong.Status = n.localStatus()
 	signature, err := n.baseApp.GfSpClient().SignP2PPongMsg(context.Background(), pong)
 	if err != nil {
 		log.Errorw("failed to sign pong msg", "local", s.Conn().LocalPeer(), "remote", s.Conn().RemotePeer(), "error", err)
@@ -129,6 +130,7 @@ func (n *Node) onPong(s network.Stream) {
 		return
 	}
 	n.peers.AddPeer(peerID, pong.SpOperatorAddress, s.Conn().RemoteMultiaddr())
+	n.peersStatus.put(pong.GetSpOperatorAddress(), pong.GetStatus())
 
 	for _, node := range pong.Nodes {
 		pID, err := peer.Decode(node.NodeId)
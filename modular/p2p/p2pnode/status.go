@@ -0,0 +1,120 @@
+package p2pnode
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspp2p"
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// SecondaryStatusCacheTime defines the cache time of local secondary status,
+// avoids to stat the disk and resource manager for every approval request.
+const SecondaryStatusCacheTime = 1
+
+// SecondaryStatusExpiredTime defines the expired time of remote secondary status
+// that is received from pong, unit is second.
+const SecondaryStatusExpiredTime = 60
+
+// secondaryStatusProvider generates the local status as secondary sp, and it
+// is advertised to other sps by pong and approval response.
+type secondaryStatusProvider struct {
+	rcmgr               corercmgr.ResourceManager
+	capacityPath        string
+	supportedRedundancy []storagetypes.RedundancyType
+
+	mux    sync.Mutex
+	status *gfspp2p.GfSpSecondaryStatus
+}
+
+// SetSecondaryStatusOptions sets the options of local secondary status, the capacity
+// path is the directory of piece store, it is empty if the piece store is not disk
+// file and the capacity is unknown; the supported redundancy is empty means all.
+func (n *Node) SetSecondaryStatusOptions(capacityPath string, supportedRedundancy []storagetypes.RedundancyType) {
+	n.status = &secondaryStatusProvider{
+		rcmgr:               n.baseApp.ResourceManager(),
+		capacityPath:        capacityPath,
+		supportedRedundancy: supportedRedundancy,
+	}
+}
+
+// localStatus returns the status of local sp as secondary sp, it returns nil
+// if the secondary status options are not set.
+func (n *Node) localStatus() *gfspp2p.GfSpSecondaryStatus {
+	if n.status == nil {
+		return nil
+	}
+	return n.status.get()
+}
+
+func (p *secondaryStatusProvider) get() *gfspp2p.GfSpSecondaryStatus {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	now := time.Now().Unix()
+	if p.status != nil && now-p.status.GetUpdateTime() < SecondaryStatusCacheTime {
+		return p.status
+	}
+	status := &gfspp2p.GfSpSecondaryStatus{
+		SupportedRedundancy: p.supportedRedundancy,
+		UpdateTime:          now,
+	}
+	if len(p.capacityPath) != 0 {
+		total, free, err := diskCapacity(p.capacityPath)
+		if err != nil {
+			log.Warnw("failed to stat piece store capacity", "path", p.capacityPath, "error", err)
+		} else {
+			status.TotalCapacity, status.FreeCapacity = total, free
+		}
+	}
+	if p.rcmgr != nil {
+		_ = p.rcmgr.ViewSystem(func(scope corercmgr.ResourceScope) error {
+			stat := scope.Stat()
+			status.RunningTasks = stat.NumTasksHigh + stat.NumTasksMedium + stat.NumTasksLow
+			remaining, err := scope.RemainingResource()
+			if err != nil {
+				return err
+			}
+			status.RemainingTasks = int64(remaining.GetTaskTotalLimit())
+			return nil
+		})
+	}
+	p.status = status
+	return status
+}
+
+type cachedSecondaryStatus struct {
+	status     *gfspp2p.GfSpSecondaryStatus
+	receivedAt int64
+}
+
+// secondaryStatusCache caches the secondary status of other sps received from pong.
+type secondaryStatusCache struct {
+	mux    sync.RWMutex
+	status map[string]*cachedSecondaryStatus
+}
+
+func newSecondaryStatusCache() *secondaryStatusCache {
+	return &secondaryStatusCache{status: make(map[string]*cachedSecondaryStatus)}
+}
+
+func (c *secondaryStatusCache) put(sp string, status *gfspp2p.GfSpSecondaryStatus) {
+	if status == nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.status[sp] = &cachedSecondaryStatus{status: status, receivedAt: time.Now().Unix()}
+}
+
+// get returns the unexpired secondary status of the sp, returns nil if not found.
+func (c *secondaryStatusCache) get(sp string) *gfspp2p.GfSpSecondaryStatus {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	cached, ok := c.status[sp]
+	if !ok || time.Now().Unix()-cached.receivedAt > SecondaryStatusExpiredTime {
+		return nil
+	}
+	return cached.status
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package p2pnode
+
+import (
+	"syscall"
+)
+
+// diskCapacity returns the total and available capacity of the file system
+// that the path belongs to.
+func diskCapacity(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package p2pnode
+
+import (
+	"errors"
+)
+
+// diskCapacity is not supported on windows, the capacity of the secondary status
+// is left empty.
+func diskCapacity(path string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk capacity is not supported on windows")
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corepiecestore "github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
@@ -22,6 +23,8 @@ var (
 	ErrRepeatedTask        = gfsperrors.Register(module.ReceiveModularName, http.StatusNotAcceptable, 80002, "request repeated")
 	ErrUnfinishedTask      = gfsperrors.Register(module.ReceiveModularName, http.StatusForbidden, 80003, "replicate piece unfinished")
 	ErrInvalidDataChecksum = gfsperrors.Register(module.ReceiveModularName, http.StatusNotAcceptable, 80004, "verify data checksum failed")
+	ErrDoneTask            = gfsperrors.Register(module.ReceiveModularName, http.StatusForbidden, 80005, "replicate piece has been done")
+	ErrReceiverDraining    = gfsperrors.Register(module.ReceiveModularName, http.StatusServiceUnavailable, 80006, "receiver is draining, try again later")
 	ErrPieceStore          = gfsperrors.Register(module.ReceiveModularName, http.StatusInternalServerError, 85101, "server slipped away, try again later")
 	ErrGfSpDB              = gfsperrors.Register(module.ReceiveModularName, http.StatusInternalServerError, 85201, "server slipped away, try again later")
 )
@@ -59,6 +62,12 @@ func (r *ReceiveModular) HandleReceivePieceTask(ctx context.Context, task task.R
 		return err
 	}
 	defer r.receiveQueue.PopByKey(task.Key())
+	// the draining is checked after pushing, so the piece is either rejected or waited by draining
+	if r.draining() {
+		log.CtxWarnw(ctx, "reject to receive piece in draining")
+		err = ErrReceiverDraining
+		return ErrReceiverDraining
+	}
 	checksum := hash.GenerateChecksum(data)
 	if !bytes.Equal(checksum, task.GetPieceChecksum()) {
 		err = ErrInvalidDataChecksum
@@ -72,23 +81,28 @@ func (r *ReceiveModular) HandleReceivePieceTask(ctx context.Context, task task.R
 		pieceKey = r.baseApp.PieceOp().SegmentPieceKey(task.GetObjectInfo().Id.Uint64(),
 			uint32(task.GetPieceIdx()))
 	}
-	setDBTime := time.Now()
-	if err = r.baseApp.GfSpDB().SetReplicatePieceChecksum(task.GetObjectInfo().Id.Uint64(),
-		task.GetReplicateIdx(), uint32(task.GetPieceIdx()), task.GetPieceChecksum()); err != nil {
+	// the piece is staged and promoted before the checksum is committed, the checksum
+	// never points at a missing or truncated piece after crashing
+	var dbErr error
+	setPieceTime := time.Now()
+	err = corepiecestore.CommitPiece(ctx, r.baseApp.PieceStore(), pieceKey, data, func() error {
+		metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_set_piece_time").Observe(time.Since(setPieceTime).Seconds())
+		setDBTime := time.Now()
+		dbErr = r.baseApp.GfSpDBWithContext(ctx).SetReplicatePieceChecksum(task.GetObjectInfo().Id.Uint64(),
+			task.GetReplicateIdx(), uint32(task.GetPieceIdx()), task.GetPieceChecksum())
 		metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_set_mysql_time").Observe(time.Since(setDBTime).Seconds())
-		log.CtxErrorw(ctx, "failed to set checksum to db", "error", err)
+		return dbErr
+	})
+	if dbErr != nil {
+		log.CtxErrorw(ctx, "failed to set checksum to db", "error", dbErr)
 		err = ErrGfSpDB
 		return ErrGfSpDB
 	}
-	metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_set_mysql_time").Observe(time.Since(setDBTime).Seconds())
-
-	setPieceTime := time.Now()
-	if err = r.baseApp.PieceStore().PutPiece(ctx, pieceKey, data); err != nil {
-		metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_set_piece_time").Observe(time.Since(setPieceTime).Seconds())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to commit piece to piece store", "piece_key", pieceKey, "error", err)
 		err = ErrPieceStore
 		return ErrPieceStore
 	}
-	metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_set_piece_time").Observe(time.Since(setPieceTime).Seconds())
 	log.CtxDebugw(ctx, "succeed to receive piece data")
 	return nil
 }
@@ -125,7 +139,7 @@ func (r *ReceiveModular) HandleDoneReceivePieceTask(ctx context.Context, task ta
 		task.GetStorageParams().VersionedParams.GetMaxSegmentSize())
 
 	getChecksumsTime := time.Now()
-	checksums, err := r.baseApp.GfSpDB().GetAllReplicatePieceChecksum(
+	checksums, err := r.baseApp.GfSpDBWithContext(ctx).GetAllReplicatePieceChecksum(
 		task.GetObjectInfo().Id.Uint64(), task.GetReplicateIdx(), segmentCount)
 	metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_done_get_checksums_time").Observe(time.Since(getChecksumsTime).Seconds())
 	if err != nil {
@@ -153,7 +167,7 @@ func (r *ReceiveModular) HandleDoneReceivePieceTask(ctx context.Context, task ta
 		Signature:         signature,
 	}
 	setIntegrityTime := time.Now()
-	err = r.baseApp.GfSpDB().SetObjectIntegrity(integrityMeta)
+	err = r.baseApp.GfSpDBWithContext(ctx).SetObjectIntegrity(integrityMeta)
 	metrics.PerfReceivePieceTimeHistogram.WithLabelValues("receive_piece_server_done_set_integrity_time").Observe(time.Since(setIntegrityTime).Seconds())
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to write integrity meta to db", "error", err)
@@ -161,7 +175,7 @@ func (r *ReceiveModular) HandleDoneReceivePieceTask(ctx context.Context, task ta
 		return nil, nil, ErrGfSpDB
 	}
 	deletePieceHashTime := time.Now()
-	if err = r.baseApp.GfSpDB().DeleteAllReplicatePieceChecksum(
+	if err = r.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceChecksum(
 		task.GetObjectInfo().Id.Uint64(), task.GetReplicateIdx(), segmentCount); err != nil {
 		log.CtxErrorw(ctx, "failed to delete all replicate piece checksum", "error", err)
 		// ignore the error,let the request go, the background task will gc the meta again later
@@ -182,6 +196,40 @@ func (r *ReceiveModular) HandleDoneReceivePieceTask(ctx context.Context, task ta
 	return integrity, signature, nil
 }
 
+func (r *ReceiveModular) HandleDiscardReceivePieceTask(ctx context.Context, task task.ReceivePieceTask) error {
+	if task == nil || task.GetObjectInfo() == nil || task.GetStorageParams() == nil {
+		log.CtxErrorw(ctx, "failed to discard receive piece due to pointer dangling")
+		return ErrDanglingTask
+	}
+	objectID := task.GetObjectInfo().Id.Uint64()
+	// the signed integrity hash may be used to seal object, the pieces are gc by the
+	// background task if the object is not sealed in the end.
+	if _, err := r.baseApp.GfSpDBWithContext(ctx).GetObjectIntegrity(objectID); err == nil {
+		log.CtxErrorw(ctx, "failed to discard receive piece, replicate piece has been done")
+		return ErrDoneTask
+	}
+	segmentCount := r.baseApp.PieceOp().SegmentPieceCount(task.GetObjectInfo().GetPayloadSize(),
+		task.GetStorageParams().VersionedParams.GetMaxSegmentSize())
+	for pieceIdx := uint32(0); pieceIdx < segmentCount; pieceIdx++ {
+		var pieceKey string
+		if task.GetObjectInfo().GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE {
+			pieceKey = r.baseApp.PieceOp().ECPieceKey(objectID, pieceIdx, task.GetReplicateIdx())
+		} else {
+			pieceKey = r.baseApp.PieceOp().SegmentPieceKey(objectID, pieceIdx)
+		}
+		if err := r.baseApp.PieceStore().DeletePiece(ctx, pieceKey); err != nil {
+			log.CtxErrorw(ctx, "failed to delete piece", "piece_key", pieceKey, "error", err)
+			return ErrPieceStore
+		}
+	}
+	if err := r.baseApp.GfSpDBWithContext(ctx).DeleteAllReplicatePieceChecksum(objectID, task.GetReplicateIdx(), segmentCount); err != nil {
+		log.CtxErrorw(ctx, "failed to delete all replicate piece checksum", "error", err)
+		return ErrGfSpDB
+	}
+	log.CtxDebugw(ctx, "succeed to discard receive piece", "segment_count", segmentCount)
+	return nil
+}
+
 func (r *ReceiveModular) QueryTasks(
 	ctx context.Context,
 	subKey task.TKey) (
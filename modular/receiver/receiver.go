@@ -2,19 +2,28 @@ package receiver
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
 
 var _ module.Receiver = &ReceiveModular{}
+var _ corelifecycle.Drainable = &ReceiveModular{}
+var _ gfspapp.Reloadable = &ReceiveModular{}
 
 type ReceiveModular struct {
 	baseApp      *gfspapp.GfSpBaseApp
 	scope        rcmgr.ResourceScope
 	receiveQueue taskqueue.TQueueOnStrategy
+	// drainStarted is set when the receiver starts draining, the new pieces are rejected
+	drainStarted int32
 }
 
 func (r *ReceiveModular) Name() string {
@@ -30,6 +39,37 @@ func (r *ReceiveModular) Start(ctx context.Context) error {
 	return nil
 }
 
+// draining returns an indicator whether the new pieces are rejected.
+func (r *ReceiveModular) draining() bool {
+	return atomic.LoadInt32(&r.drainStarted) == 1 || r.baseApp.Draining()
+}
+
+// Drain rejects the new pieces and waits for the receiving pieces to finish, the pieces
+// unfinished at the deadline are resent by the primary sp.
+func (r *ReceiveModular) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&r.drainStarted, 1)
+	ticker := time.NewTicker(DefaultDrainCheckInterval)
+	defer ticker.Stop()
+	for r.receiveQueue.Len() > 0 {
+		select {
+		case <-ctx.Done():
+			log.CtxWarnw(ctx, "drain deadline exceeded, cut the receiving pieces", "receiving", r.receiveQueue.Len())
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Reload applies the changed capacity of the receiving queue.
+func (r *ReceiveModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if cfg.Parallel.ReceivePieceParallelPerNode == 0 {
+		cfg.Parallel.ReceivePieceParallelPerNode = DefaultReceivePieceParallelPerNode
+	}
+	r.receiveQueue.SetCap(cfg.Parallel.ReceivePieceParallelPerNode)
+	return nil
+}
+
 func (r *ReceiveModular) Stop(ctx context.Context) error {
 	r.scope.Release()
 	return nil
@@ -1,6 +1,8 @@
 package receiver
 
 import (
+	"time"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -10,6 +12,9 @@ const (
 	// DefaultReceivePieceParallelPerNode defines the default max receive piece parallel
 	// per receiver
 	DefaultReceivePieceParallelPerNode = 10240
+	// DefaultDrainCheckInterval defines the interval of checking the receiving pieces
+	// when draining
+	DefaultDrainCheckInterval = 500 * time.Millisecond
 )
 
 func NewReceiveModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield/sdk/client"
 	"github.com/bnb-chain/greenfield/sdk/keys"
 	ctypes "github.com/bnb-chain/greenfield/sdk/types"
@@ -387,7 +388,9 @@ func (client *GreenfieldChainSignClient) getNonceOnChain(ctx context.Context, gn
 func (client *GreenfieldChainSignClient) broadcastTx(
 	ctx context.Context, gnfdClient *client.GreenfieldClient,
 	msgs []sdk.Msg, txOpt *ctypes.TxOption, opts ...grpc.CallOption,
-) ([]byte, error) {
+) (txHash []byte, err error) {
+	ctx, span := tracing.StartSpan(ctx, "gnfd.broadcast_tx")
+	defer func() { tracing.EndSpan(span, err) }()
 	resp, err := gnfdClient.BroadcastTx(ctx, msgs, txOpt, opts...)
 	if err != nil {
 		if strings.Contains(err.Error(), "account sequence mismatch") {
@@ -401,7 +404,7 @@ func (client *GreenfieldChainSignClient) broadcastTx(
 	if resp.TxResponse.Code != 0 {
 		return nil, fmt.Errorf("failed to broadcast tx, resp code: %d", resp.TxResponse.Code)
 	}
-	txHash, err := hex.DecodeString(resp.TxResponse.TxHash)
+	txHash, err = hex.DecodeString(resp.TxResponse.TxHash)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal tx hash")
 	}
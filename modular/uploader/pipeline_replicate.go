@@ -0,0 +1,288 @@
+package uploader
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-common/go/redundancy"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// pipelineSegment is the segment piece waiting to be replicated to secondary sps.
+type pipelineSegment struct {
+	segIdx uint32
+	data   []byte
+}
+
+const (
+	approvalPending int32 = iota
+	approvalGot
+	approvalAbandoned
+)
+
+// pipelineClient is the part of sp client used by pipeline replicating.
+type pipelineClient interface {
+	AskSecondaryReplicatePieceApproval(ctx context.Context, task coretask.ApprovalReplicatePieceTask,
+		low, high int, timeout int64) ([]*gfsptask.GfSpReplicatePieceApprovalTask, error)
+	SignReceiveTask(ctx context.Context, receiveTask coretask.ReceivePieceTask) ([]byte, error)
+	ReplicatePieceToSecondary(ctx context.Context, endpoint string, approval coretask.ApprovalReplicatePieceTask,
+		receive coretask.ReceivePieceTask, data []byte) error
+	DiscardReplicatePieceToSecondary(ctx context.Context, endpoint string,
+		approval coretask.ApprovalReplicatePieceTask, receive coretask.ReceivePieceTask) error
+}
+
+// pipelineReplicator replicates the segment pieces to secondary sps while uploading.
+// It is best-effort, the segments dropped for the full queue and the pieces failed
+// to replicate are resent by executor, which only replicates the missing pieces
+// according to the persisted replicate piece progress.
+type pipelineReplicator struct {
+	u        *UploadModular
+	client   pipelineClient
+	ctx      context.Context
+	cancel   context.CancelFunc
+	task     coretask.UploadObjectTask
+	segments chan *pipelineSegment
+	finished chan struct{}
+	// approval is changed once from approvalPending, to approvalGot by the replicating
+	// goroutine if the approvals are got, or to approvalAbandoned by finish, whichever
+	// is the first, so the approvals are either used by the replicate task or unused
+	approval int32
+
+	// approvals, sent and broken are written by the replicating goroutine, and read by
+	// finish or discard only after finished is closed, sent records the replicate indexes
+	// that pieces are sent to.
+	approvals []*gfsptask.GfSpReplicatePieceApprovalTask
+	sent      []bool
+	broken    []bool
+}
+
+func newPipelineReplicator(u *UploadModular, task coretask.UploadObjectTask) *pipelineReplicator {
+	// the replication may outlive the upload request, detach from the request context
+	ctx, cancel := context.WithCancel(log.WithValue(context.Background(), log.CtxKeyTask, task.Key().String()))
+	p := &pipelineReplicator{
+		u:        u,
+		client:   u.baseApp.GfSpClient(),
+		ctx:      ctx,
+		cancel:   cancel,
+		task:     task,
+		segments: make(chan *pipelineSegment, u.pipelineReplicateQueueSize),
+		finished: make(chan struct{}),
+	}
+	atomic.AddInt64(&u.pipelineReplicating, 1)
+	go p.run()
+	return p
+}
+
+// push queues the segment piece for replicating without blocking the upload, the
+// segment is dropped if the queue is full.
+func (p *pipelineReplicator) push(segIdx uint32, data []byte) {
+	// only the upload goroutine pushes, the length can only decrease concurrently
+	if len(p.segments) >= cap(p.segments) {
+		log.CtxDebugw(p.ctx, "pipeline replicate queue is full, drop segment", "segment_idx", segIdx)
+		return
+	}
+	segment := &pipelineSegment{segIdx: segIdx, data: make([]byte, len(data))}
+	copy(segment.data, data)
+	p.segments <- segment
+}
+
+// finish waits for the queued segments to be replicated and returns the approvals of
+// secondary sps that are used in pipeline, so the replicate task restores the complete
+// progress and never resends the pieces in flight. If the approvals are not got yet, it
+// stops replicating and returns nil, the object is replicated by executor as usual.
+func (p *pipelineReplicator) finish() []*gfsptask.GfSpReplicatePieceApprovalTask {
+	close(p.segments)
+	if atomic.CompareAndSwapInt32(&p.approval, approvalPending, approvalAbandoned) {
+		p.cancel()
+		return nil
+	}
+	<-p.finished
+	return p.approvals
+}
+
+// abort stops replicating and discards the pieces that have been replicated to the
+// secondary sps in background.
+func (p *pipelineReplicator) abort() {
+	p.cancel()
+	close(p.segments)
+	go func() {
+		<-p.finished
+		p.discard()
+	}()
+}
+
+func (p *pipelineReplicator) run() {
+	defer close(p.finished)
+	defer atomic.AddInt64(&p.u.pipelineReplicating, -1)
+	defer p.cancel()
+	defer func() {
+		// drain the queue to release the segment data
+		for range p.segments {
+		}
+	}()
+	if err := p.askApprovals(); err != nil {
+		log.CtxWarnw(p.ctx, "failed to get approvals for pipeline replicate, fallback to executor", "error", err)
+		p.approvals = nil
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&p.approval, approvalPending, approvalGot) {
+		log.CtxDebugw(p.ctx, "upload finished before getting approvals, fallback to executor")
+		return
+	}
+	for segment := range p.segments {
+		if p.ctx.Err() != nil {
+			return
+		}
+		p.replicateSegment(segment)
+	}
+}
+
+func (p *pipelineReplicator) askApprovals() error {
+	params := p.task.GetStorageParams()
+	replCount := int(params.VersionedParams.GetRedundantDataChunkNum() +
+		params.VersionedParams.GetRedundantParityChunkNum())
+	approvalTask := &gfsptask.GfSpReplicatePieceApprovalTask{}
+	approvalTask.InitApprovalReplicatePieceTask(p.task.GetObjectInfo(), params,
+		p.u.baseApp.TaskPriority(approvalTask), p.u.baseApp.OperatorAddress())
+	approvals, err := p.client.AskSecondaryReplicatePieceApproval(p.ctx, approvalTask,
+		replCount, replCount, p.u.askReplicateApprovalTimeout)
+	if err != nil {
+		return err
+	}
+	if len(approvals) < replCount {
+		return ErrInsufficientApproval
+	}
+	for _, approval := range approvals[:replCount] {
+		spInfo, err := p.u.baseApp.GfSpDB().GetSpByAddress(approval.GetApprovedSpOperatorAddress(), spdb.OperatorAddressType)
+		if err != nil {
+			return err
+		}
+		approval.SetApprovedSpEndpoint(spInfo.GetEndpoint())
+		approval.SetApprovedSpApprovalAddress(spInfo.GetApprovalAddress())
+	}
+	p.approvals = approvals[:replCount]
+	p.sent = make([]bool, replCount)
+	p.broken = make([]bool, replCount)
+	log.CtxDebugw(p.ctx, "succeed to get approvals for pipeline replicate", "approvals", replCount)
+	return nil
+}
+
+func (p *pipelineReplicator) replicateSegment(segment *pipelineSegment) {
+	var (
+		object = p.task.GetObjectInfo()
+		params = p.task.GetStorageParams()
+		ecData [][]byte
+		err    error
+	)
+	if object.GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE {
+		ecTime := time.Now()
+		ecData, err = redundancy.EncodeRawSegment(segment.data,
+			int(params.VersionedParams.GetRedundantDataChunkNum()),
+			int(params.VersionedParams.GetRedundantParityChunkNum()))
+		metrics.PerfUploadTimeHistogram.WithLabelValues("pipeline_ec_time").Observe(time.Since(ecTime).Seconds())
+		if err != nil {
+			log.CtxErrorw(p.ctx, "failed to ec encode segment", "segment_idx", segment.segIdx, "error", err)
+			return
+		}
+	}
+	var wg sync.WaitGroup
+	for rIdx := range p.approvals {
+		if p.broken[rIdx] {
+			continue
+		}
+		data := segment.data
+		if ecData != nil {
+			data = ecData[rIdx]
+		}
+		p.sent[rIdx] = true
+		wg.Add(1)
+		go func(rIdx int, data []byte) {
+			defer wg.Done()
+			if err := p.replicatePiece(uint32(rIdx), segment.segIdx, data); err != nil {
+				// stop replicating to the secondary sp, executor will take over the replicate index
+				p.broken[rIdx] = true
+			}
+		}(rIdx, data)
+	}
+	wg.Wait()
+}
+
+func (p *pipelineReplicator) replicatePiece(replicateIdx uint32, pieceIdx uint32, data []byte) error {
+	approval := p.approvals[replicateIdx]
+	receive := &gfsptask.GfSpReceivePieceTask{}
+	receive.InitReceivePieceTask(p.task.GetObjectInfo(), p.task.GetStorageParams(),
+		p.u.baseApp.TaskPriority(receive), replicateIdx, int32(pieceIdx), int64(len(data)))
+	receive.SetTraceParent(p.task.GetTraceParent())
+	receive.SetPieceChecksum(hash.GenerateChecksum(data))
+	signature, err := p.client.SignReceiveTask(p.ctx, receive)
+	if err != nil {
+		log.CtxErrorw(p.ctx, "failed to sign receive task", "replicate_idx", replicateIdx,
+			"piece_idx", pieceIdx, "error", err)
+		return err
+	}
+	receive.SetSignature(signature)
+	if err = p.u.baseApp.ResourceManager().BandwidthLimiter().WaitN(p.ctx, approval.GetApprovedSpOperatorAddress(),
+		len(data), corercmgr.ReserveTaskPriorityMedium); err != nil {
+		return err
+	}
+	replicateTime := time.Now()
+	err = p.client.ReplicatePieceToSecondary(p.ctx,
+		approval.GetApprovedSpEndpoint(), approval, receive, data)
+	metrics.PerfUploadTimeHistogram.WithLabelValues("pipeline_replicate_one_piece_time").Observe(time.Since(replicateTime).Seconds())
+	if err != nil {
+		log.CtxErrorw(p.ctx, "failed to pipeline replicate piece", "replicate_idx", replicateIdx,
+			"piece_idx", pieceIdx, "error", err)
+		return err
+	}
+	if err = p.u.baseApp.GfSpDB().SetReplicatePieceProgress(&spdb.ReplicatePieceProgress{
+		ObjectID:          p.task.GetObjectInfo().Id.Uint64(),
+		ReplicateIdx:      replicateIdx,
+		PieceIdx:          pieceIdx,
+		SpOperatorAddress: approval.GetApprovedSpOperatorAddress(),
+	}); err != nil {
+		// the piece will be resent by executor without the progress
+		log.CtxWarnw(p.ctx, "failed to set replicate piece progress", "replicate_idx", replicateIdx,
+			"piece_idx", pieceIdx, "error", err)
+	}
+	return nil
+}
+
+// discard asks the secondary sps to delete the replicated pieces and clears the
+// replicate piece progress.
+func (p *pipelineReplicator) discard() {
+	ctx := log.WithValue(context.Background(), log.CtxKeyTask, p.task.Key().String())
+	for rIdx, approval := range p.approvals {
+		if !p.sent[rIdx] {
+			continue
+		}
+		receive := &gfsptask.GfSpReceivePieceTask{}
+		receive.InitReceivePieceTask(p.task.GetObjectInfo(), p.task.GetStorageParams(),
+			p.u.baseApp.TaskPriority(receive), uint32(rIdx), -1, 0)
+		receive.SetTraceParent(p.task.GetTraceParent())
+		signature, err := p.client.SignReceiveTask(ctx, receive)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to sign discard receive task", "replicate_idx", rIdx, "error", err)
+			continue
+		}
+		receive.SetSignature(signature)
+		if err = p.client.DiscardReplicatePieceToSecondary(ctx,
+			approval.GetApprovedSpEndpoint(), approval, receive); err != nil {
+			// the pieces left on secondary sp are gc as zombie pieces
+			log.CtxErrorw(ctx, "failed to discard replicated pieces", "replicate_idx", rIdx,
+				"sp", approval.GetApprovedSpOperatorAddress(), "error", err)
+		}
+	}
+	if err := p.u.baseApp.GfSpDB().DeleteAllReplicatePieceProgress(p.task.GetObjectInfo().Id.Uint64()); err != nil {
+		log.CtxWarnw(ctx, "failed to delete replicate piece progress", "error", err)
+	}
+	log.CtxDebugw(ctx, "finish to discard pipeline replicated pieces")
+}
@@ -0,0 +1,179 @@
+package uploader
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+type mockPipelineClient struct {
+	mu sync.Mutex
+	// approve is closed to return the approvals, asking approvals blocks until then
+	approve    chan struct{}
+	replicated map[string][]int32
+	discarded  []string
+	// received is sent to after each piece is replicated
+	received chan struct{}
+}
+
+func newMockPipelineClient() *mockPipelineClient {
+	return &mockPipelineClient{
+		approve:    make(chan struct{}),
+		replicated: make(map[string][]int32),
+		received:   make(chan struct{}, 16),
+	}
+}
+
+func (c *mockPipelineClient) AskSecondaryReplicatePieceApproval(ctx context.Context, task coretask.ApprovalReplicatePieceTask,
+	low, high int, timeout int64) ([]*gfsptask.GfSpReplicatePieceApprovalTask, error) {
+	select {
+	case <-c.approve:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var approvals []*gfsptask.GfSpReplicatePieceApprovalTask
+	for _, sp := range []string{"sp1", "sp2"} {
+		approvals = append(approvals, &gfsptask.GfSpReplicatePieceApprovalTask{ApprovedSpOperatorAddress: sp})
+	}
+	return approvals, nil
+}
+
+func (c *mockPipelineClient) SignReceiveTask(ctx context.Context, receiveTask coretask.ReceivePieceTask) ([]byte, error) {
+	return []byte("signature"), nil
+}
+
+func (c *mockPipelineClient) ReplicatePieceToSecondary(ctx context.Context, endpoint string,
+	approval coretask.ApprovalReplicatePieceTask, receive coretask.ReceivePieceTask, data []byte) error {
+	c.mu.Lock()
+	c.replicated[endpoint] = append(c.replicated[endpoint], receive.GetPieceIdx())
+	c.mu.Unlock()
+	c.received <- struct{}{}
+	return nil
+}
+
+func (c *mockPipelineClient) DiscardReplicatePieceToSecondary(ctx context.Context, endpoint string,
+	approval coretask.ApprovalReplicatePieceTask, receive coretask.ReceivePieceTask) error {
+	c.mu.Lock()
+	c.discarded = append(c.discarded, endpoint)
+	c.mu.Unlock()
+	return nil
+}
+
+func setupPipelineReplicator(t *testing.T) (*pipelineReplicator, *mockPipelineClient, *spdb.MockSPDB) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetSpByAddress(gomock.Any(), spdb.OperatorAddressType).DoAndReturn(
+		func(address string, _ spdb.SpAddressType) (*sptypes.StorageProvider, error) {
+			return &sptypes.StorageProvider{OperatorAddress: address, Endpoint: "https://" + address}, nil
+		}).AnyTimes()
+	app := &gfspapp.GfSpBaseApp{}
+	cfg := &gfspconfig.GfSpConfig{Customize: &gfspconfig.Customize{GfSpDB: db}}
+	cfg.Rcmgr.DisableRcmgr = true
+	require.NoError(t, gfspapp.DefaultGfSpDBOption(app, cfg))
+	require.NoError(t, gfspapp.DefaultGfSpResourceManagerOption(app, cfg))
+
+	task := &gfsptask.GfSpUploadObjectTask{}
+	task.InitUploadObjectTask(&storagetypes.ObjectInfo{
+		Id:             sdkmath.NewUint(1),
+		BucketName:     "mock-bucket",
+		ObjectName:     "mock-object",
+		RedundancyType: storagetypes.REDUNDANCY_REPLICA_TYPE,
+	}, &storagetypes.Params{VersionedParams: storagetypes.VersionedParams{
+		RedundantDataChunkNum:   1,
+		RedundantParityChunkNum: 1,
+	}}, 100)
+	u := &UploadModular{baseApp: app, pipelineReplicateQueueSize: 2}
+	client := newMockPipelineClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pipelineReplicator{
+		u:        u,
+		client:   client,
+		ctx:      ctx,
+		cancel:   cancel,
+		task:     task,
+		segments: make(chan *pipelineSegment, u.pipelineReplicateQueueSize),
+		finished: make(chan struct{}),
+	}
+	u.pipelineReplicating++
+	go p.run()
+	return p, client, db
+}
+
+func TestPipelineReplicator_Push(t *testing.T) {
+	p, client, db := setupPipelineReplicator(t)
+	db.EXPECT().SetReplicatePieceProgress(gomock.Any()).Return(nil).Times(4)
+	close(client.approve)
+	p.push(0, []byte("segment0"))
+	// wait for the first segment to be replicated, the queue is not full
+	<-client.received
+	<-client.received
+	p.push(1, []byte("segment1"))
+
+	// finish returns the approvals after the queued segment is replicated
+	approvals := p.finish()
+	require.Len(t, approvals, 2)
+	require.Equal(t, "https://sp1", approvals[0].GetApprovedSpEndpoint())
+	for _, endpoint := range []string{"https://sp1", "https://sp2"} {
+		pieces := client.replicated[endpoint]
+		sort.Slice(pieces, func(i, j int) bool { return pieces[i] < pieces[j] })
+		require.Equal(t, []int32{0, 1}, pieces)
+	}
+	require.Equal(t, int64(0), atomic.LoadInt64(&p.u.pipelineReplicating))
+}
+
+func TestPipelineReplicator_FinishBeforeApproval(t *testing.T) {
+	p, client, _ := setupPipelineReplicator(t)
+	p.push(0, []byte("segment0"))
+
+	// the upload does not wait for the approvals, the object is replicated by executor
+	require.Nil(t, p.finish())
+	<-p.finished
+	require.Empty(t, client.replicated)
+}
+
+func TestPipelineReplicator_Abort(t *testing.T) {
+	p, client, db := setupPipelineReplicator(t)
+	db.EXPECT().SetReplicatePieceProgress(gomock.Any()).Return(nil).Times(2)
+	discarded := make(chan struct{})
+	db.EXPECT().DeleteAllReplicatePieceProgress(uint64(1)).DoAndReturn(func(uint64) error {
+		close(discarded)
+		return nil
+	})
+	close(client.approve)
+	p.push(0, []byte("segment0"))
+	<-client.received
+	<-client.received
+
+	// the replicated pieces are discarded on both secondary sps
+	p.abort()
+	<-discarded
+	sort.Strings(client.discarded)
+	require.Equal(t, []string{"https://sp1", "https://sp2"}, client.discarded)
+}
+
+func TestPipelineReplicator_PushFullQueue(t *testing.T) {
+	p, client, _ := setupPipelineReplicator(t)
+	// the replicating goroutine is blocked by asking approvals, the queue is full
+	// after two segments and the third segment is dropped
+	for i := uint32(0); i < 3; i++ {
+		p.push(i, []byte("segment"))
+	}
+	require.Len(t, p.segments, 2)
+	require.Nil(t, p.finish())
+	<-p.finished
+	require.Empty(t, client.replicated)
+}
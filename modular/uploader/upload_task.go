@@ -11,7 +11,9 @@ import (
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corepiecestore "github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
@@ -26,6 +28,7 @@ var (
 	ErrRepeatedTask         = gfsperrors.Register(module.UploadModularName, http.StatusNotAcceptable, 110003, "put object request repeated")
 	ErrInvalidIntegrity     = gfsperrors.Register(module.UploadModularName, http.StatusNotAcceptable, 110004, "invalid payload data integrity hash")
 	ErrClosedStream         = gfsperrors.Register(module.UploadModularName, http.StatusBadRequest, 110005, "upload payload data stream exception")
+	ErrInsufficientApproval = gfsperrors.Register(module.UploadModularName, http.StatusNotFound, 110006, "insufficient approvals from p2p")
 	ErrPieceStore           = gfsperrors.Register(module.UploadModularName, http.StatusInternalServerError, 115101, "server slipped away, try again later")
 	ErrGfSpDB               = gfsperrors.Register(module.UploadModularName, http.StatusInternalServerError, 115001, "server slipped away, try again later")
 )
@@ -70,11 +73,24 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 		readN     int
 		readSize  int
 		data      = make([]byte, segmentSize)
+		pipeline  *pipelineReplicator
 	)
+	if u.enablePipelineReplicate {
+		pipeline = newPipelineReplicator(u, uploadObjectTask)
+	}
 	defer func() {
 		if err != nil {
 			uploadObjectTask.SetError(err)
 		}
+		if pipeline != nil {
+			if err != nil {
+				pipeline.abort()
+			} else if approvals := pipeline.finish(); len(approvals) > 0 {
+				if task, ok := uploadObjectTask.(*gfsptask.GfSpUploadObjectTask); ok {
+					task.SetSecondaryApprovals(approvals)
+				}
+			}
+		}
 		log.CtxDebugw(ctx, "finish to read data from stream", "info", uploadObjectTask.Info(),
 			"read_size", readSize, "error", err)
 		startReportManager := time.Now()
@@ -99,13 +115,16 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 				pieceKey = u.baseApp.PieceOp().SegmentPieceKey(uploadObjectTask.GetObjectInfo().Id.Uint64(), segIdx)
 				checksums = append(checksums, hash.GenerateChecksum(data))
 				startPutPiece := time.Now()
-				if err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data); err != nil {
+				if err = corepiecestore.CommitPiece(ctx, u.baseApp.PieceStore(), pieceKey, data, nil); err != nil {
 					metrics.PerfUploadTimeHistogram.WithLabelValues("put_to_piecestore").Observe(time.Since(startPutPiece).Seconds())
 					log.CtxErrorw(ctx, "failed to put segment piece to piece store",
 						"piece_key", pieceKey, "error", err)
 					return ErrPieceStore
 				}
 				metrics.PerfUploadTimeHistogram.WithLabelValues("put_to_piecestore").Observe(time.Since(startPutPiece).Seconds())
+				if pipeline != nil {
+					pipeline.push(segIdx, data)
+				}
 			}
 			startSignSignature := time.Now()
 			if signature, integrity, err = u.baseApp.GfSpClient().SignIntegrityHash(ctx,
@@ -129,7 +148,7 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 				Signature:         signature,
 			}
 			startUpdateSignature := time.Now()
-			if err = u.baseApp.GfSpDB().SetObjectIntegrity(integrityMeta); err != nil {
+			if err = u.baseApp.GfSpDBWithContext(ctx).SetObjectIntegrity(integrityMeta); err != nil {
 				metrics.PerfUploadTimeHistogram.WithLabelValues("update_to_sqldb").Observe(time.Since(startUpdateSignature).Seconds())
 				log.CtxErrorw(ctx, "failed to write integrity hash to db", "error", err)
 				return ErrGfSpDB
@@ -145,13 +164,18 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 		pieceKey = u.baseApp.PieceOp().SegmentPieceKey(uploadObjectTask.GetObjectInfo().Id.Uint64(), segIdx)
 		checksums = append(checksums, hash.GenerateChecksum(data))
 		startPutPiece := time.Now()
-		err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data)
+		// the segment is staged and promoted, so a crashed upload never leaves a truncated
+		// segment piece at the final key, the integrity is written after all the segments
+		err = corepiecestore.CommitPiece(ctx, u.baseApp.PieceStore(), pieceKey, data, nil)
 		if err != nil {
 			metrics.PerfUploadTimeHistogram.WithLabelValues("put_to_piecestore").Observe(time.Since(startPutPiece).Seconds())
 			log.CtxErrorw(ctx, "failed to put segment piece to piece store", "error", err)
 			return ErrPieceStore
 		}
 		metrics.PerfUploadTimeHistogram.WithLabelValues("put_to_piecestore").Observe(time.Since(startPutPiece).Seconds())
+		if pipeline != nil {
+			pipeline.push(segIdx, data)
+		}
 		segIdx++
 	}
 }
@@ -256,20 +280,12 @@ func (u *UploadModular) HandleResumableUploadObjectTask(
 			err = nil
 			if readN != 0 {
 				pieceKey = u.baseApp.PieceOp().SegmentPieceKey(task.GetObjectInfo().Id.Uint64(), segIdx)
-				err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data)
-				if err != nil {
-					log.CtxErrorw(ctx, "put segment piece to piece store",
-						"piece_key", pieceKey, "error", err)
-					return ErrPieceStore
-				}
-				err = u.baseApp.GfSpDB().AppendObjectChecksumIntegrity(task.GetObjectInfo().Id.Uint64(), hash.GenerateChecksum(data))
-				if err != nil {
-					log.CtxErrorw(ctx, "failed to append integrity checksum to db", "error", err)
-					return ErrGfSpDB
+				if err = u.commitResumableSegment(ctx, task.GetObjectInfo().Id.Uint64(), pieceKey, data); err != nil {
+					return err
 				}
 			}
 			if task.GetCompleted() {
-				integrityMeta, err = u.baseApp.GfSpDB().GetObjectIntegrity(task.GetObjectInfo().Id.Uint64())
+				integrityMeta, err = u.baseApp.GfSpDBWithContext(ctx).GetObjectIntegrity(task.GetObjectInfo().Id.Uint64())
 				if err != nil {
 					log.CtxErrorw(ctx, "failed to get object integrity hash", "error", err)
 					return err
@@ -289,7 +305,7 @@ func (u *UploadModular) HandleResumableUploadObjectTask(
 				}
 				integrityMeta.IntegrityChecksum = integrity
 				integrityMeta.Signature = signature
-				err = u.baseApp.GfSpDB().SetObjectIntegrity(integrityMeta)
+				err = u.baseApp.GfSpDBWithContext(ctx).SetObjectIntegrity(integrityMeta)
 				if err != nil {
 					log.CtxErrorw(ctx, "failed to write integrity hash to db", "error", err)
 					return ErrGfSpDB
@@ -304,20 +320,32 @@ func (u *UploadModular) HandleResumableUploadObjectTask(
 			return ErrClosedStream
 		}
 		pieceKey = u.baseApp.PieceOp().SegmentPieceKey(task.GetObjectInfo().Id.Uint64(), segIdx)
-		err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data)
-		if err != nil {
-			log.CtxErrorw(ctx, "put segment piece to piece store", "error", err)
-			return ErrPieceStore
-		}
-		err = u.baseApp.GfSpDB().AppendObjectChecksumIntegrity(task.GetObjectInfo().Id.Uint64(), hash.GenerateChecksum(data))
-		if err != nil {
-			log.CtxErrorw(ctx, "failed to append integrity checksum to db", "error", err)
-			return ErrGfSpDB
+		if err = u.commitResumableSegment(ctx, task.GetObjectInfo().Id.Uint64(), pieceKey, data); err != nil {
+			return err
 		}
 		segIdx++
 	}
 
 }
 
+// commitResumableSegment stages and promotes the segment piece before appending its checksum,
+// so the appended checksums always match the segment pieces in piece store when resuming.
+func (u *UploadModular) commitResumableSegment(ctx context.Context, objectID uint64, pieceKey string, data []byte) error {
+	var dbErr error
+	err := corepiecestore.CommitPiece(ctx, u.baseApp.PieceStore(), pieceKey, data, func() error {
+		dbErr = u.baseApp.GfSpDBWithContext(ctx).AppendObjectChecksumIntegrity(objectID, hash.GenerateChecksum(data))
+		return dbErr
+	})
+	if dbErr != nil {
+		log.CtxErrorw(ctx, "failed to append integrity checksum to db", "error", dbErr)
+		return ErrGfSpDB
+	}
+	if err != nil {
+		log.CtxErrorw(ctx, "put segment piece to piece store", "piece_key", pieceKey, "error", err)
+		return ErrPieceStore
+	}
+	return nil
+}
+
 func (*UploadModular) PostResumableUploadObject(ctx context.Context, task coretask.ResumableUploadObjectTask) {
 }
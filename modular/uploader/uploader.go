@@ -2,20 +2,34 @@ package uploader
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
 
 var _ module.Uploader = &UploadModular{}
+var _ corelifecycle.Drainable = &UploadModular{}
+var _ gfspapp.Reloadable = &UploadModular{}
 
 type UploadModular struct {
 	baseApp               *gfspapp.GfSpBaseApp
 	scope                 rcmgr.ResourceScope
 	uploadQueue           taskqueue.TQueueOnStrategy
 	resumeableUploadQueue taskqueue.TQueueOnStrategy
+
+	enablePipelineReplicate     bool
+	pipelineReplicateQueueSize  int
+	askReplicateApprovalTimeout int64
+	// pipelineReplicating is the number of the objects replicated in pipelined mode, the
+	// abandoned or aborted replication winds down in background after the upload finishes
+	pipelineReplicating int64
 }
 
 func (u *UploadModular) Name() string {
@@ -31,6 +45,34 @@ func (u *UploadModular) Start(ctx context.Context) error {
 	return nil
 }
 
+// Drain waits for the uploading objects and the pipelined replication to finish, the new
+// uploads are rejected by the base app in draining.
+func (u *UploadModular) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(DefaultDrainCheckInterval)
+	defer ticker.Stop()
+	for u.uploadQueue.Len()+u.resumeableUploadQueue.Len() > 0 || atomic.LoadInt64(&u.pipelineReplicating) > 0 {
+		select {
+		case <-ctx.Done():
+			log.CtxWarnw(ctx, "drain deadline exceeded, cut the uploading objects",
+				"uploading", u.uploadQueue.Len(), "resumable_uploading", u.resumeableUploadQueue.Len(),
+				"pipeline_replicating", atomic.LoadInt64(&u.pipelineReplicating))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Reload applies the changed capacity of the uploading queues.
+func (u *UploadModular) Reload(ctx context.Context, cfg *gfspconfig.GfSpConfig) error {
+	if cfg.Parallel.UploadObjectParallelPerNode == 0 {
+		cfg.Parallel.UploadObjectParallelPerNode = DefaultUploadObjectParallelPerNode
+	}
+	u.uploadQueue.SetCap(cfg.Parallel.UploadObjectParallelPerNode)
+	u.resumeableUploadQueue.SetCap(cfg.Parallel.UploadObjectParallelPerNode)
+	return nil
+}
+
 func (u *UploadModular) Stop(ctx context.Context) error {
 	u.scope.Release()
 	return nil
@@ -1,6 +1,8 @@
 package uploader
 
 import (
+	"time"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -10,6 +12,15 @@ const (
 	// DefaultUploadObjectParallelPerNode defines the default max parallel of uploading
 	// object per uploader.
 	DefaultUploadObjectParallelPerNode = 10240
+	// DefaultPipelineReplicateQueueSize defines the default max number of segments waiting
+	// to be replicated in pipelined mode.
+	DefaultPipelineReplicateQueueSize = 2
+	// DefaultAskReplicateApprovalTimeout defines the default ask replicate piece approval
+	// timeout in pipelined mode, it shares the executor config if it is set.
+	DefaultAskReplicateApprovalTimeout int64 = 10
+	// DefaultDrainCheckInterval defines the interval of checking the uploading objects
+	// when draining
+	DefaultDrainCheckInterval = 500 * time.Millisecond
 )
 
 func NewUploadModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
@@ -28,5 +39,14 @@ func DefaultUploaderOptions(uploader *UploadModular, cfg *gfspconfig.GfSpConfig)
 		uploader.Name()+"-upload-object", cfg.Parallel.UploadObjectParallelPerNode)
 	uploader.resumeableUploadQueue = cfg.Customize.NewStrategyTQueueFunc(
 		uploader.Name()+"-upload-resumable-object", cfg.Parallel.UploadObjectParallelPerNode)
+	if cfg.Uploader.PipelineReplicateQueueSize == 0 {
+		cfg.Uploader.PipelineReplicateQueueSize = DefaultPipelineReplicateQueueSize
+	}
+	uploader.enablePipelineReplicate = cfg.Uploader.EnablePipelineReplicate
+	uploader.pipelineReplicateQueueSize = cfg.Uploader.PipelineReplicateQueueSize
+	uploader.askReplicateApprovalTimeout = cfg.Executor.AskReplicateApprovalTimeout
+	if uploader.askReplicateApprovalTimeout == 0 {
+		uploader.askReplicateApprovalTimeout = DefaultAskReplicateApprovalTimeout
+	}
 	return nil
 }
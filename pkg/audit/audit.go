@@ -0,0 +1,322 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// EventType defines the type of the security relevant event.
+type EventType string
+
+const (
+	// EventApproval records the create bucket and create object approvals signed by sp.
+	EventApproval EventType = "approval"
+	// EventSignatureVerify records the verification of the request signature.
+	EventSignatureVerify EventType = "signature_verify"
+	// EventPermissionDenied records the request that is denied for no permission.
+	EventPermissionDenied EventType = "permission_denied"
+	// EventPrivateDownload records the download of the object that is not public.
+	EventPrivateDownload EventType = "private_download"
+	// EventAuthKeyChange records the change of the off-chain auth key.
+	EventAuthKeyChange EventType = "auth_key_change"
+	// EventAdminCommand records the admin api call and the admin operation.
+	EventAdminCommand EventType = "admin_command"
+	// EventChainStart marks the start of a new chain, it is the only record that restarts
+	// the chain in the middle of a file.
+	EventChainStart EventType = "chain_start"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+)
+
+const (
+	// ExporterFile writes the records to the local file that is rotated by size.
+	ExporterFile = "file"
+	// ExporterSyslog sends the records to the syslog server in RFC 5424.
+	ExporterSyslog = "syslog"
+
+	// DefaultFilePath defines the default file path of the audit log.
+	DefaultFilePath = "./audit/audit.log"
+	// DefaultMaxFileSizeMB defines the default size to rotate the audit log file.
+	DefaultMaxFileSizeMB = 100
+	// DefaultSyslogNetwork defines the default network to connect the syslog server.
+	DefaultSyslogNetwork = "udp"
+	// DefaultSyslogTag defines the default app name of the syslog message.
+	DefaultSyslogTag = "gnfd-sp"
+)
+
+// AuditConfig defines the configuration of the audit log.
+type AuditConfig struct {
+	Enable bool
+	// Exporters defines where the records are written, file or syslog, default file.
+	Exporters []string
+	FilePath  string
+	// MaxFileSizeMB defines the size to rotate the file, the rotated file is renamed with
+	// the rotating time as the suffix.
+	MaxFileSizeMB int64
+	// MaxBackups defines the max number of the rotated files to keep, zero keeps all.
+	MaxBackups    int
+	SyslogNetwork string // udp or tcp, default udp
+	SyslogAddress string
+	SyslogTag     string
+	// HMACKey defines the key to sign the records by HMAC-SHA256, the records are hashed
+	// by SHA-256 without the key if it is empty, so anyone who can write the log is able
+	// to recompute the chain.
+	HMACKey string
+}
+
+// Event is the security relevant event emitted by the caller.
+type Event struct {
+	Type     EventType
+	Actor    string // the account or the admin who takes the action
+	Remote   string // the address of the client
+	Action   string
+	Resource string // the bucket, object or task that is operated
+	Result   string
+	Detail   map[string]string
+}
+
+// Record is one line of the audit log. The records are hash-chained, each record carries
+// the hash of the previous record and its own hash that covers all the other fields, so
+// any modified, removed or reordered record breaks the chain. The hash is the HMAC of the
+// record if the key is configured.
+type Record struct {
+	Seq      uint64            `json:"seq"`
+	Time     string            `json:"time"`
+	AppID    string            `json:"app_id"`
+	Type     EventType         `json:"type"`
+	Actor    string            `json:"actor,omitempty"`
+	Remote   string            `json:"remote,omitempty"`
+	Action   string            `json:"action"`
+	Resource string            `json:"resource,omitempty"`
+	Result   string            `json:"result"`
+	Detail   map[string]string `json:"detail,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+	PrevHash string            `json:"prev_hash"`
+	Hash     string            `json:"hash"`
+}
+
+// ComputeHash returns the hash of the record, the Hash field is excluded. The hash is the
+// HMAC-SHA256 of the record if the key is not empty.
+func (r *Record) ComputeHash(key []byte) (string, error) {
+	shadow := *r
+	shadow.Hash = ""
+	bz, err := json.Marshal(&shadow)
+	if err != nil {
+		return "", err
+	}
+	if len(key) == 0 {
+		sum := sha256.Sum256(bz)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(bz)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Sink writes the encoded records, the write is synchronous so the record is not lost
+// after Emit returns.
+type Sink interface {
+	Write(record []byte) error
+	Close() error
+}
+
+// chainRecoverer is implemented by the sink that persists the records, the chain is
+// continued from the last persisted record after restarting.
+type chainRecoverer interface {
+	LastRecord() (*Record, error)
+}
+
+// Auditor appends the events to the hash chain and writes the records to the sinks.
+type Auditor struct {
+	mux      sync.Mutex
+	appID    string
+	key      []byte
+	seq      uint64
+	prevHash string
+	sinks    []Sink
+}
+
+// NewAuditor creates the sinks by the config and recovers the chain from the persisted
+// records, a new chain is started by the chain start record if no record is persisted.
+func NewAuditor(cfg *AuditConfig, appID string) (*Auditor, error) {
+	exporters := cfg.Exporters
+	if len(exporters) == 0 {
+		exporters = []string{ExporterFile}
+	}
+	a := &Auditor{appID: appID, key: []byte(cfg.HMACKey)}
+	for _, exporter := range exporters {
+		var (
+			sink Sink
+			err  error
+		)
+		switch exporter {
+		case ExporterFile:
+			filePath := cfg.FilePath
+			if filePath == "" {
+				filePath = DefaultFilePath
+			}
+			maxSize := cfg.MaxFileSizeMB
+			if maxSize <= 0 {
+				maxSize = DefaultMaxFileSizeMB
+			}
+			sink, err = NewFileSink(filePath, maxSize*1024*1024, cfg.MaxBackups)
+		case ExporterSyslog:
+			network := cfg.SyslogNetwork
+			if network == "" {
+				network = DefaultSyslogNetwork
+			}
+			tag := cfg.SyslogTag
+			if tag == "" {
+				tag = DefaultSyslogTag
+			}
+			sink, err = NewSyslogSink(network, cfg.SyslogAddress, tag)
+		default:
+			err = fmt.Errorf("unknown audit exporter %s", exporter)
+		}
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		a.sinks = append(a.sinks, sink)
+	}
+	for _, sink := range a.sinks {
+		recoverer, ok := sink.(chainRecoverer)
+		if !ok {
+			continue
+		}
+		last, err := recoverer.LastRecord()
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+		if last != nil {
+			a.seq, a.prevHash = last.Seq, last.Hash
+		}
+		break
+	}
+	if a.seq == 0 {
+		a.Emit(context.Background(), &Event{Type: EventChainStart, Action: "start", Result: ResultSuccess})
+	}
+	return a, nil
+}
+
+// Emit appends the event to the chain. The failure of writing is logged but not returned,
+// the audit log never fails the request. The chain is advanced only if the record is written
+// by at least one sink, otherwise the next record reuses the seq so the chain has no gap.
+func (a *Auditor) Emit(ctx context.Context, event *Event) {
+	record := &Record{
+		Time:     time.Now().UTC().Format(time.RFC3339Nano),
+		AppID:    a.appID,
+		Type:     event.Type,
+		Actor:    event.Actor,
+		Remote:   event.Remote,
+		Action:   event.Action,
+		Resource: event.Resource,
+		Result:   event.Result,
+		Detail:   event.Detail,
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.TraceID = spanCtx.TraceID().String()
+	}
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	record.Seq = a.seq + 1
+	record.PrevHash = a.prevHash
+	hash, err := record.ComputeHash(a.key)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to compute audit record hash", "error", err)
+		return
+	}
+	record.Hash = hash
+	bz, err := json.Marshal(record)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to marshal audit record", "error", err)
+		return
+	}
+	written := 0
+	for _, sink := range a.sinks {
+		if err = sink.Write(bz); err != nil {
+			log.CtxErrorw(ctx, "failed to write audit record", "seq", record.Seq, "type", record.Type, "error", err)
+			continue
+		}
+		written++
+	}
+	if written == 0 {
+		log.CtxErrorw(ctx, "audit record is not written by any sink, chain is not advanced",
+			"seq", record.Seq, "type", record.Type)
+		return
+	}
+	a.seq, a.prevHash = record.Seq, record.Hash
+}
+
+// Close closes the sinks.
+func (a *Auditor) Close() error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	var lastErr error
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	a.sinks = nil
+	return lastErr
+}
+
+var (
+	defaultAuditor *Auditor
+	defaultMux     sync.RWMutex
+)
+
+// Init creates the auditor and replaces the default auditor, the events are dropped
+// before initializing.
+func Init(cfg *AuditConfig, appID string) error {
+	auditor, err := NewAuditor(cfg, appID)
+	if err != nil {
+		return err
+	}
+	defaultMux.Lock()
+	defer defaultMux.Unlock()
+	if defaultAuditor != nil {
+		defaultAuditor.Close()
+	}
+	defaultAuditor = auditor
+	return nil
+}
+
+// Close closes the default auditor.
+func Close() error {
+	defaultMux.Lock()
+	defer defaultMux.Unlock()
+	if defaultAuditor == nil {
+		return nil
+	}
+	err := defaultAuditor.Close()
+	defaultAuditor = nil
+	return err
+}
+
+// Emit appends the event to the default auditor, it does nothing if the audit log is
+// not enabled.
+func Emit(ctx context.Context, event *Event) {
+	defaultMux.RLock()
+	defer defaultMux.RUnlock()
+	if defaultAuditor == nil {
+		return
+	}
+	defaultAuditor.Emit(ctx, event)
+}
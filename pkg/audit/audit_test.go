@@ -0,0 +1,190 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func verifyFiles(t *testing.T, filePath string, key []byte) (ChainState, error) {
+	backups, err := Backups(filePath)
+	require.NoError(t, err)
+	var state ChainState
+	for _, file := range append(backups, filePath) {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		if state, err = Verify(bytes.NewReader(data), state, key); err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+func TestAuditorChain(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &AuditConfig{Enable: true, Exporters: []string{ExporterFile}, FilePath: filePath}
+	auditor, err := NewAuditor(cfg, "sp0")
+	require.NoError(t, err)
+	// rotate the file after every two records
+	auditor.sinks[0].(*FileSink).maxSize = 600
+	for i := 0; i < 5; i++ {
+		auditor.Emit(context.Background(), &Event{Type: EventAdminCommand, Actor: "admin", Action: "drain", Result: ResultSuccess})
+	}
+	require.NoError(t, auditor.Close())
+	backups, err := Backups(filePath)
+	require.NoError(t, err)
+	assert.NotEmpty(t, backups)
+
+	// the chain continues after reopening
+	auditor, err = NewAuditor(cfg, "sp0")
+	require.NoError(t, err)
+	auditor.Emit(context.Background(), &Event{Type: EventApproval, Action: "create_bucket", Result: ResultDenied})
+	require.NoError(t, auditor.Close())
+	state, err := verifyFiles(t, filePath, nil)
+	require.NoError(t, err)
+	// the chain starts with the chain start record
+	assert.Equal(t, uint64(7), state.Seq)
+
+	// the modified record breaks the chain
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, bytes.Replace(data, []byte("denied"), []byte("success"), 1), 0640))
+	_, err = verifyFiles(t, filePath, nil)
+	assert.Error(t, err)
+}
+
+func emitRecords(t *testing.T, key string, n int) [][]byte {
+	filePath := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := NewAuditor(&AuditConfig{Enable: true, FilePath: filePath, HMACKey: key}, "sp0")
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		auditor.Emit(context.Background(), &Event{Type: EventAdminCommand, Action: "drain", Result: ResultSuccess})
+	}
+	require.NoError(t, auditor.Close())
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	return bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+}
+
+func joinRecords(records ...[]byte) []byte {
+	return append(bytes.Join(records, []byte("\n")), '\n')
+}
+
+func TestVerifyRestart(t *testing.T) {
+	chain := emitRecords(t, "", 3)
+	restarted := emitRecords(t, "", 2)
+	require.Len(t, chain, 4)
+	plain := &Record{Type: EventAdminCommand, Seq: 1, Action: "drain", Result: ResultSuccess}
+	plainHash, err := plain.ComputeHash(nil)
+	require.NoError(t, err)
+	plain.Hash = plainHash
+	plainRecord, err := json.Marshal(plain)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		data       []byte
+		state      ChainState
+		wantedFail bool
+	}{
+		{
+			name:  "restart at the start of the file",
+			data:  joinRecords(plainRecord),
+			state: ChainState{Seq: 4, Hash: "mock"},
+		},
+		{
+			name: "restart by the chain start record",
+			data: joinRecords(append(chain, restarted...)...),
+		},
+		{
+			name:       "restart without the chain start record",
+			data:       joinRecords(append(chain[:2], plainRecord)...),
+			wantedFail: true,
+		},
+		{
+			name:       "removed record",
+			data:       joinRecords(chain[0], chain[2]),
+			wantedFail: true,
+		},
+		{
+			name:       "reordered record",
+			data:       joinRecords(chain[0], chain[2], chain[1]),
+			wantedFail: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Verify(bytes.NewReader(c.data), c.state, nil)
+			if c.wantedFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyHMACKey(t *testing.T) {
+	chain := emitRecords(t, "mock-key", 2)
+	state, err := Verify(bytes.NewReader(joinRecords(chain...)), ChainState{}, []byte("mock-key"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), state.Seq)
+
+	_, err = Verify(bytes.NewReader(joinRecords(chain...)), ChainState{}, []byte("wrong-key"))
+	assert.Error(t, err)
+	_, err = Verify(bytes.NewReader(joinRecords(chain...)), ChainState{}, nil)
+	assert.Error(t, err)
+
+	// the modified record can not be signed again without the key
+	record := &Record{}
+	require.NoError(t, json.Unmarshal(chain[1], record))
+	record.Result = ResultDenied
+	record.Hash, err = record.ComputeHash(nil)
+	require.NoError(t, err)
+	forged, err := json.Marshal(record)
+	require.NoError(t, err)
+	_, err = Verify(bytes.NewReader(joinRecords(chain[0], forged, chain[2])), ChainState{}, []byte("mock-key"))
+	assert.Error(t, err)
+}
+
+type mockSink struct {
+	fail    bool
+	records [][]byte
+}
+
+func (s *mockSink) Write(record []byte) error {
+	if s.fail {
+		return errors.New("mock write error")
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *mockSink) Close() error { return nil }
+
+func TestAuditorEmitWriteFailure(t *testing.T) {
+	failed, written := &mockSink{fail: true}, &mockSink{}
+	auditor := &Auditor{appID: "sp0", sinks: []Sink{failed}}
+	event := &Event{Type: EventAdminCommand, Action: "drain", Result: ResultSuccess}
+
+	// the chain is not advanced if no sink writes the record
+	auditor.Emit(context.Background(), event)
+	assert.Equal(t, uint64(0), auditor.seq)
+	assert.Empty(t, auditor.prevHash)
+
+	// the chain is advanced if any sink writes the record
+	auditor.sinks = []Sink{failed, written}
+	auditor.Emit(context.Background(), event)
+	auditor.Emit(context.Background(), event)
+	assert.Equal(t, uint64(2), auditor.seq)
+	state, err := Verify(bytes.NewReader(joinRecords(written.records...)), ChainState{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), state.Seq)
+}
@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000000000"
+
+var _ Sink = &FileSink{}
+
+// FileSink appends the records to the file, the file is rotated when it exceeds the max
+// size. The rotated files are named as <file path>.<rotating time>, so the lexical order
+// of the files is the order of the chain.
+type FileSink struct {
+	filePath   string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewFileSink opens the audit log file in append mode.
+func NewFileSink(filePath string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
+		return nil, err
+	}
+	s := &FileSink{filePath: filePath, maxSize: maxSize, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+// Write appends one record as a line, the file is rotated before writing if it is full.
+func (s *FileSink) Write(record []byte) error {
+	if s.size > 0 && s.size+int64(len(record))+1 > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(append(record, '\n'))
+	s.size += int64(n)
+	return err
+}
+
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	backup := s.filePath + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(s.filePath, backup); err != nil {
+		return err
+	}
+	if s.maxBackups > 0 {
+		backups, err := Backups(s.filePath)
+		if err != nil {
+			return err
+		}
+		for len(backups) > s.maxBackups {
+			if err = os.Remove(backups[0]); err != nil {
+				return err
+			}
+			backups = backups[1:]
+		}
+	}
+	return s.open()
+}
+
+// LastRecord returns the last record in the current file, or in the latest rotated file
+// if the current file is empty.
+func (s *FileSink) LastRecord() (*Record, error) {
+	backups, err := Backups(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+	files := append(backups, s.filePath)
+	for i := len(files) - 1; i >= 0; i-- {
+		record, err := lastRecordOfFile(files[i])
+		if err != nil || record != nil {
+			return record, err
+		}
+	}
+	return nil, nil
+}
+
+// Close syncs and closes the file.
+func (s *FileSink) Close() error {
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// Backups returns the rotated files of the audit log in the order of the chain.
+func Backups(filePath string) ([]string, error) {
+	backups, err := filepath.Glob(filePath + ".*")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func lastRecordOfFile(filePath string) (*Record, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var last []byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) != 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, nil
+	}
+	record := &Record{}
+	if err = json.Unmarshal(last, record); err != nil {
+		return nil, fmt.Errorf("failed to parse the last audit record of %s: %w", filePath, err)
+	}
+	return record, nil
+}
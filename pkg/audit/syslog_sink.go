@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// syslogPriority is the priority of facility authpriv(10) and severity info(6).
+	syslogPriority = 10*8 + 6
+	syslogTimeout  = 3 * time.Second
+)
+
+var _ Sink = &SyslogSink{}
+
+// SyslogSink sends the records to the syslog server in RFC 5424, the message over tcp is
+// framed by the octet counting in RFC 6587.
+type SyslogSink struct {
+	network  string
+	address  string
+	tag      string
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslogSink connects the syslog server.
+func NewSyslogSink(network, address, tag string) (*SyslogSink, error) {
+	if address == "" {
+		return nil, fmt.Errorf("syslog address of audit log is empty")
+	}
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported syslog network %s", network)
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogSink{network: network, address: address, tag: tag, hostname: hostname}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogSink) connect() error {
+	conn, err := net.DialTimeout(s.network, s.address, syslogTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *SyslogSink) format(record []byte) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d audit - %s", syslogPriority,
+		time.Now().UTC().Format(time.RFC3339Nano), s.hostname, s.tag, os.Getpid(), record)
+	if s.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg)
+}
+
+// Write sends one record, the connection is re-established once if it is broken.
+func (s *SyslogSink) Write(record []byte) error {
+	msg := s.format(record)
+	var err error
+	for i := 0; i < 2; i++ {
+		if s.conn == nil {
+			if err = s.connect(); err != nil {
+				continue
+			}
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+		if _, err = s.conn.Write(msg); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection.
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
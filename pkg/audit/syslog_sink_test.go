@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSyslogSink(t *testing.T) {
+	_, err := NewSyslogSink("udp", "", DefaultSyslogTag)
+	assert.Error(t, err)
+	_, err = NewSyslogSink("unix", "127.0.0.1:514", DefaultSyslogTag)
+	assert.Error(t, err)
+}
+
+func TestSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	sink, err := NewSyslogSink("udp", conn.LocalAddr().String(), DefaultSyslogTag)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write([]byte(`{"seq":1}`)))
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(syslogTimeout)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<86>1 "))
+	fields := strings.SplitN(msg, " ", 8)
+	require.Len(t, fields, 8)
+	assert.Equal(t, DefaultSyslogTag, fields[3])
+	assert.Equal(t, "audit", fields[5])
+	assert.Equal(t, `- {"seq":1}`, fields[6]+" "+fields[7])
+}
+
+func TestSyslogSinkTCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	messages := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					// the message is framed by the octet counting
+					length, err := reader.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSpace(length))
+					if err != nil {
+						return
+					}
+					msg := make([]byte, n)
+					if _, err = io.ReadFull(reader, msg); err != nil {
+						return
+					}
+					messages <- string(msg)
+				}
+			}()
+		}
+	}()
+	sink, err := NewSyslogSink("tcp", listener.Addr().String(), DefaultSyslogTag)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write([]byte(`{"seq":1}`)))
+	// the broken connection is re-established by the next write
+	sink.conn.Close()
+	require.NoError(t, sink.Write([]byte(`{"seq":2}`)))
+	var received []string
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-messages:
+			received = append(received, msg[strings.LastIndex(msg, " ")+1:])
+		case <-time.After(syslogTimeout):
+			t.Fatal("syslog message is not received")
+		}
+	}
+	// the messages are received by two connections in any order
+	assert.ElementsMatch(t, []string{`{"seq":1}`, `{"seq":2}`}, received)
+}
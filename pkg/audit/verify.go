@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ChainState is the position of the verified chain, it is passed to verify the next file.
+type ChainState struct {
+	Seq  uint64
+	Hash string
+}
+
+// Verify checks the records read from r continue the chain from the state, it returns
+// the state of the last record. The key must be the HMAC key of the auditor, or empty if
+// the records are not keyed.
+//
+// The chain only restarts from the seq 1 with the empty previous hash at the start of the
+// file, e.g. the log is rotated after the sp only exports to syslog, or by the chain start
+// record that the auditor writes when it can not recover the chain. The zero state accepts
+// any first record, because the oldest rotated files may be pruned.
+func Verify(r io.Reader, state ChainState, key []byte) (ChainState, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	first := true
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return state, fmt.Errorf("line %d: invalid record: %w", line, err)
+		}
+		hash, err := record.ComputeHash(key)
+		if err != nil {
+			return state, fmt.Errorf("line %d: %w", line, err)
+		}
+		if hash != record.Hash {
+			return state, fmt.Errorf("line %d: seq %d is modified, hash mismatch", line, record.Seq)
+		}
+		chainStart := record.Seq == 1 && record.PrevHash == ""
+		restart := (first && (chainStart || state == ChainState{})) ||
+			(chainStart && record.Type == EventChainStart)
+		if !restart && (record.Seq != state.Seq+1 || record.PrevHash != state.Hash) {
+			return state, fmt.Errorf("line %d: seq %d does not follow seq %d, records are removed or reordered",
+				line, record.Seq, state.Seq)
+		}
+		state = ChainState{Seq: record.Seq, Hash: record.Hash}
+		first = false
+	}
+	if err := scanner.Err(); err != nil {
+		return state, err
+	}
+	return state, nil
+}
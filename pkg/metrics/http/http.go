@@ -32,6 +32,13 @@ func (wd *responseWriterDelegator) WriteHeader(statusCode int) {
 	wd.w.WriteHeader(statusCode)
 }
 
+// Flush sends the buffered data to the client, it is required by the streaming responses.
+func (wd *responseWriterDelegator) Flush() {
+	if flusher, ok := wd.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func (wd *responseWriterDelegator) StatusCode() int {
 	if !wd.written {
 		return http.StatusOK
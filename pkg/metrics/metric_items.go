@@ -56,6 +56,8 @@ var MetricsItems = []prometheus.Collector{
 	ExecutorGCObjectTaskCounter,
 	ExecutorGCZombieTaskCounter,
 	ExecutorGCMetaTaskCounter,
+	RecoverPieceSourceCounter,
+	RecoverPieceEndpointCounter,
 	// Manager metrics category
 	UploadObjectTaskTimeHistogram,
 	ReplicateAndSealTaskTimeHistogram,
@@ -71,6 +73,9 @@ var MetricsItems = []prometheus.Collector{
 	DispatchSealObjectTaskCounter,
 	DispatchReceivePieceTaskCounter,
 	DispatchGcObjectTaskCounter,
+	WebhookDeliveryCounter,
+	WebhookDeliveryTimeHistogram,
+	WebhookEventCounter,
 	// Signer metrics category
 	SealObjectTimeHistogram,
 	SealObjectSucceedCounter,
@@ -85,6 +90,11 @@ var MetricsItems = []prometheus.Collector{
 	SPDBTimeHistogram,
 	// BlockSyncer metrics category
 	BlockHeightLagGauge,
+	BlockSyncerLagGauge,
+	BlockSyncerCatchUpGauge,
+	BlockSyncerCatchUpSpeedGauge,
+	BlockSyncerCatchUpETAGauge,
+	BlockSyncerCatchUpBatchTimeHistogram,
 	// the greenfield chain metrics.
 	GnfdChainHistogram,
 }
@@ -285,6 +295,14 @@ var (
 		Name: "gc_meta_task_count",
 		Help: "Track gc meta task number.",
 	}, []string{"gc_meta_task_count"})
+	RecoverPieceSourceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recover_piece_source_count",
+		Help: "Track the source type that the recovered pieces are fetched from.",
+	}, []string{"recover_piece_source"})
+	RecoverPieceEndpointCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recover_piece_endpoint_count",
+		Help: "Track the sp endpoints that serve the pieces of the finished recovery tasks.",
+	}, []string{"recover_piece_endpoint"})
 	ExecutorRecoveryTaskCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "recover_piece_task_count",
 		Help: "Track recovery task number.",
@@ -355,6 +373,19 @@ var (
 		Name: "dispatch_recovery_piece_task",
 		Help: "Track recovery task total number",
 	}, []string{"dispatch_recovery_piece_task"})
+	WebhookDeliveryCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_delivery_count",
+		Help: "Track the webhook delivery attempts by event type and result, e.g. succeed, retry and dead_letter.",
+	}, []string{"event_type", "result"})
+	WebhookDeliveryTimeHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "webhook_delivery_time",
+		Help:    "Track the time of delivering the webhook event to the subscriber.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"event_type"})
+	WebhookEventCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_event_count",
+		Help: "Track the number of the webhook deliveries generated by event type.",
+	}, []string{"event_type"})
 
 	// signer metrics
 	SealObjectTimeHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
@@ -409,6 +440,32 @@ var (
 		Name: "block_syncer_height",
 		Help: "Current block number of block syncer progress.",
 	}, []string{"block_syncer_height"})
+	// BlockSyncerLagGauge records the number of blocks that the block syncer lags behind the chain
+	BlockSyncerLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "block_syncer_lag",
+		Help: "Number of blocks that the block syncer lags behind the chain.",
+	}, []string{"service"})
+	// BlockSyncerCatchUpGauge records whether the block syncer is in the catch-up mode
+	BlockSyncerCatchUpGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "block_syncer_catch_up",
+		Help: "Whether the block syncer is in the catch-up mode, 1 is catching up and 0 is following the tip.",
+	}, []string{"service"})
+	// BlockSyncerCatchUpSpeedGauge records the applied blocks per second in the catch-up mode
+	BlockSyncerCatchUpSpeedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "block_syncer_catch_up_blocks_per_second",
+		Help: "Applied blocks per second of the block syncer in the catch-up mode.",
+	}, []string{"service"})
+	// BlockSyncerCatchUpETAGauge records the estimated seconds to catch up with the chain
+	BlockSyncerCatchUpETAGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "block_syncer_catch_up_eta_seconds",
+		Help: "Estimated seconds for the block syncer to catch up with the chain.",
+	}, []string{"service"})
+	// BlockSyncerCatchUpBatchTimeHistogram records the latency of applying a batch of blocks in the catch-up mode
+	BlockSyncerCatchUpBatchTimeHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "block_syncer_catch_up_batch_seconds",
+		Help:    "Track the latency of applying a batch of blocks in the catch-up mode.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service"})
 
 	// GnfdChainHistogram is used to record greenfield chain cost.
 	GnfdChainHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
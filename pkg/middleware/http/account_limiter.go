@@ -0,0 +1,178 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru"
+	slimiter "github.com/ulule/limiter/v3"
+	"golang.org/x/time/rate"
+)
+
+const (
+	accountKeyPrefix = "account_"
+	bucketKeyPrefix  = "bucket_"
+	// DefaultBandwidthLimiterCacheSize defines the max number of accounts and buckets whose
+	// download bandwidth limiters are kept in memory.
+	DefaultBandwidthLimiterCacheSize = 10240
+)
+
+// AccountClassConfig defines the limits of a class of accounts, e.g. the accounts that
+// paid for the higher tier.
+type AccountClassConfig struct {
+	Name         string
+	Accounts     []string
+	RateLimit    int
+	RatePeriod   string
+	DownloadRate int64 // bytes per second, zero means no limit
+}
+
+// AccountLimitConfig defines the limits keyed by the authenticated account, the accounts
+// not listed in any class share the default limits.
+type AccountLimitConfig struct {
+	On           bool
+	RateLimit    int
+	RatePeriod   string
+	DownloadRate int64 // bytes per second, zero means no limit
+	Classes      []AccountClassConfig
+}
+
+// BucketLimitConfig defines the limits keyed by the bucket name.
+type BucketLimitConfig struct {
+	On           bool
+	RateLimit    int
+	RatePeriod   string
+	DownloadRate int64 // bytes per second, zero means no limit
+}
+
+// limitTier is the parsed limits of a cell, nil rate means no request limit.
+type limitTier struct {
+	rate         *slimiter.Rate
+	downloadRate int64
+}
+
+func newLimitTier(rateLimit int, ratePeriod string, downloadRate int64) (*limitTier, error) {
+	tier := &limitTier{downloadRate: downloadRate}
+	if rateLimit > 0 {
+		r, err := slimiter.NewRateFromFormatted(fmt.Sprintf("%d-%s", rateLimit, ratePeriod))
+		if err != nil {
+			return nil, err
+		}
+		tier.rate = &r
+	}
+	return tier, nil
+}
+
+// initAccountLimits parses the account and bucket limits, the cells are disabled if the
+// config is off.
+func (a *apiLimiter) initAccountLimits(cfg *APILimiterConfig) error {
+	var err error
+	a.accountTiers = make(map[string]*limitTier)
+	if cfg.AccountLimitCfg.On {
+		if a.defaultAccountTier, err = newLimitTier(cfg.AccountLimitCfg.RateLimit,
+			cfg.AccountLimitCfg.RatePeriod, cfg.AccountLimitCfg.DownloadRate); err != nil {
+			return err
+		}
+		for _, class := range cfg.AccountLimitCfg.Classes {
+			tier, err := newLimitTier(class.RateLimit, class.RatePeriod, class.DownloadRate)
+			if err != nil {
+				return fmt.Errorf("invalid limits of account class %s: %w", class.Name, err)
+			}
+			for _, account := range class.Accounts {
+				a.accountTiers[strings.ToLower(account)] = tier
+			}
+		}
+	}
+	if cfg.BucketLimitCfg.On {
+		if a.bucketTier, err = newLimitTier(cfg.BucketLimitCfg.RateLimit,
+			cfg.BucketLimitCfg.RatePeriod, cfg.BucketLimitCfg.DownloadRate); err != nil {
+			return err
+		}
+	}
+	a.bandwidth, err = lru.New(DefaultBandwidthLimiterCacheSize)
+	return err
+}
+
+func (a *apiLimiter) accountTier(account string) *limitTier {
+	if tier, ok := a.accountTiers[account]; ok {
+		return tier
+	}
+	return a.defaultAccountTier
+}
+
+func (a *apiLimiter) allowCell(ctx context.Context, key string, tier *limitTier) bool {
+	if tier == nil || tier.rate == nil {
+		return true
+	}
+	limiterCtx, err := a.store.Increment(ctx, key, 1, *tier.rate)
+	if err != nil {
+		return true
+	}
+	return !limiterCtx.Reached
+}
+
+// BucketAllow limits the requests by the bucket name of the route.
+func (a *apiLimiter) BucketAllow(ctx context.Context, r *http.Request) bool {
+	bucket := mux.Vars(r)["bucket"]
+	if bucket == "" {
+		return true
+	}
+	return a.allowCell(ctx, bucketKeyPrefix+bucket, a.bucketTier)
+}
+
+func (a *apiLimiter) waitBandwidth(ctx context.Context, key string, tier *limitTier, n int) error {
+	if tier == nil || tier.downloadRate <= 0 {
+		return nil
+	}
+	limiter := rate.NewLimiter(rate.Limit(tier.downloadRate), int(tier.downloadRate))
+	if previous, ok, _ := a.bandwidth.PeekOrAdd(key, limiter); ok {
+		limiter = previous.(*rate.Limiter)
+	}
+	// WaitN fails if n exceeds the burst, the large data waits in several bursts
+	for n > 0 {
+		wait := n
+		if wait > limiter.Burst() {
+			wait = limiter.Burst()
+		}
+		if err := limiter.WaitN(ctx, wait); err != nil {
+			return err
+		}
+		n -= wait
+	}
+	return nil
+}
+
+// AccountAllow reports whether the request of the authenticated account is allowed by the
+// limits of the account's class.
+func AccountAllow(ctx context.Context, account string) bool {
+	l := limiter.Load()
+	if l == nil || account == "" {
+		return true
+	}
+	account = strings.ToLower(account)
+	return l.allowCell(ctx, accountKeyPrefix+account, l.accountTier(account))
+}
+
+// WaitDownload blocks until the download bandwidth of the account and the bucket allows
+// sending n bytes, the empty account or bucket is not limited.
+func WaitDownload(ctx context.Context, account, bucket string, n int) error {
+	l := limiter.Load()
+	if l == nil {
+		return nil
+	}
+	if account != "" {
+		account = strings.ToLower(account)
+		if err := l.waitBandwidth(ctx, accountKeyPrefix+account, l.accountTier(account), n); err != nil {
+			return err
+		}
+	}
+	if bucket != "" {
+		if err := l.waitBandwidth(ctx, bucketKeyPrefix+bucket, l.bucketTier, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
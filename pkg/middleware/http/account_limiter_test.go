@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountAllow(t *testing.T) {
+	err := NewAPILimiter(&APILimiterConfig{
+		AccountLimitCfg: AccountLimitConfig{
+			On:         true,
+			RateLimit:  1,
+			RatePeriod: "M",
+			Classes: []AccountClassConfig{{
+				Name:       "premium",
+				Accounts:   []string{"0xPremium"},
+				RateLimit:  3,
+				RatePeriod: "M",
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.True(t, AccountAllow(ctx, "0xfree"))
+	assert.False(t, AccountAllow(ctx, "0xFree"))
+	for i := 0; i < 3; i++ {
+		assert.True(t, AccountAllow(ctx, "0xpremium"))
+	}
+	assert.False(t, AccountAllow(ctx, "0xpremium"))
+	// anonymous requests are limited by ip and bucket
+	assert.True(t, AccountAllow(ctx, ""))
+	assert.True(t, AccountAllow(ctx, ""))
+}
+
+func TestWaitDownload(t *testing.T) {
+	err := NewAPILimiter(&APILimiterConfig{
+		BucketLimitCfg: BucketLimitConfig{On: true, DownloadRate: 1000},
+	})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	// the first burst is free, the next 500 bytes wait about half a second
+	assert.NoError(t, WaitDownload(ctx, "0xaccount", "bucket", 1500))
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	// the account and the other bucket are not limited
+	start = time.Now()
+	assert.NoError(t, WaitDownload(ctx, "0xaccount", "", 1<<20))
+	assert.NoError(t, WaitDownload(ctx, "", "other", 1000))
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	slimiter "github.com/ulule/limiter/v3"
+	smemory "github.com/ulule/limiter/v3/drivers/store/memory"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// LimiterStoreMemory keeps the counters in the memory of each gater replica.
+	LimiterStoreMemory = "memory"
+	// LimiterStoreRedis shares the counters across the gater replicas through a redis
+	// compatible server.
+	LimiterStoreRedis = "redis"
+
+	// DefaultRedisPoolSize defines the default max idle connections to the redis server.
+	DefaultRedisPoolSize = 16
+	// DefaultRedisTimeout defines the default timeout of dialing and each round trip.
+	DefaultRedisTimeout = 100 * time.Millisecond
+	// DefaultFallbackCooldown defines the default duration of using the local store after
+	// the shared store fails, the shared store is retried after the cooldown.
+	DefaultFallbackCooldown = 10 * time.Second
+
+	limiterStorePrefix = "sp_api_rate_limiter"
+)
+
+// LimiterStoreConfig defines where the rate limit counters are kept.
+type LimiterStoreConfig struct {
+	Type                string // memory or redis, default memory
+	Address             string
+	Password            string
+	DB                  int
+	PoolSize            int
+	TimeoutMs           int64
+	FallbackCooldownSec int64
+}
+
+func newLimiterStore(cfg *LimiterStoreConfig) (slimiter.Store, error) {
+	local := smemory.NewStoreWithOptions(slimiter.StoreOptions{
+		Prefix:          limiterStorePrefix,
+		CleanUpInterval: 5 * time.Second,
+	})
+	switch cfg.Type {
+	case "", LimiterStoreMemory:
+		return local, nil
+	case LimiterStoreRedis:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("redis address of rate limiter store is empty")
+		}
+		cooldown := time.Duration(cfg.FallbackCooldownSec) * time.Second
+		if cooldown <= 0 {
+			cooldown = DefaultFallbackCooldown
+		}
+		return &fallbackStore{
+			shared:   newRedisStore(cfg, limiterStorePrefix),
+			local:    local,
+			cooldown: cooldown,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown rate limiter store type %s", cfg.Type)
+	}
+}
+
+var _ slimiter.Store = &fallbackStore{}
+
+// fallbackStore uses the shared store and falls back to the local store when the shared
+// store is unavailable, the limits are enforced per replica until the shared store recovers,
+// so the requests are neither rejected nor slowed down by the broken shared store.
+type fallbackStore struct {
+	shared    slimiter.Store
+	local     slimiter.Store
+	cooldown  time.Duration
+	downUntil atomic.Int64 // unix nano
+}
+
+func (s *fallbackStore) call(ctx context.Context, op func(slimiter.Store) (slimiter.Context, error)) (
+	slimiter.Context, error) {
+	if time.Now().UnixNano() >= s.downUntil.Load() {
+		limiterCtx, err := op(s.shared)
+		if err == nil {
+			return limiterCtx, nil
+		}
+		log.CtxWarnw(ctx, "shared rate limiter store is unavailable, fall back to local store",
+			"cooldown", s.cooldown, "error", err)
+		s.downUntil.Store(time.Now().Add(s.cooldown).UnixNano())
+	}
+	return op(s.local)
+}
+
+func (s *fallbackStore) Get(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.call(ctx, func(store slimiter.Store) (slimiter.Context, error) {
+		return store.Get(ctx, key, rate)
+	})
+}
+
+func (s *fallbackStore) Peek(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.call(ctx, func(store slimiter.Store) (slimiter.Context, error) {
+		return store.Peek(ctx, key, rate)
+	})
+}
+
+func (s *fallbackStore) Reset(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.call(ctx, func(store slimiter.Store) (slimiter.Context, error) {
+		return store.Reset(ctx, key, rate)
+	})
+}
+
+func (s *fallbackStore) Increment(ctx context.Context, key string, count int64, rate slimiter.Rate) (
+	slimiter.Context, error) {
+	return s.call(ctx, func(store slimiter.Store) (slimiter.Context, error) {
+		return store.Increment(ctx, key, count, rate)
+	})
+}
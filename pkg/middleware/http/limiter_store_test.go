@@ -0,0 +1,243 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	slimiter "github.com/ulule/limiter/v3"
+)
+
+// fakeRedis is an in-process server speaking the subset of the redis protocol used by
+// redisStore.
+type fakeRedis struct {
+	listener net.Listener
+	mux      sync.Mutex
+	values   map[string]int64
+	expires  map[string]time.Time
+	// afterCommand is called with the lock held after every command is executed
+	afterCommand func(args []string)
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeRedis{listener: listener, values: make(map[string]int64), expires: make(map[string]time.Time)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var queued [][]string
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, arg.(string))
+		}
+		var result string
+		switch {
+		case strings.EqualFold(args[0], "MULTI"):
+			queued, result = [][]string{}, "+OK\r\n"
+		case strings.EqualFold(args[0], "EXEC"):
+			result = f.execAll(queued)
+			queued = nil
+		case queued != nil:
+			queued, result = append(queued, args), "+QUEUED\r\n"
+		default:
+			result = f.execAll([][]string{args})
+			result = result[strings.Index(result, "\n")+1:]
+		}
+		if _, err = conn.Write([]byte(result)); err != nil {
+			return
+		}
+	}
+}
+
+// execAll executes the commands atomically and returns the replies in an array.
+func (f *fakeRedis) execAll(cmds [][]string) string {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	result := fmt.Sprintf("*%d\r\n", len(cmds))
+	for _, args := range cmds {
+		result += f.exec(args)
+		if f.afterCommand != nil {
+			f.afterCommand(args)
+		}
+	}
+	return result
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	key := args[len(args)-1]
+	if len(args) > 1 {
+		key = args[1]
+	}
+	if expire, ok := f.expires[key]; ok && time.Now().After(expire) {
+		delete(f.values, key)
+		delete(f.expires, key)
+	}
+	_, exists := f.values[key]
+	switch strings.ToUpper(args[0]) {
+	case "SET":
+		if exists {
+			return "$-1\r\n"
+		}
+		f.values[key], _ = strconv.ParseInt(args[2], 10, 64)
+		ms, _ := strconv.ParseInt(args[4], 10, 64)
+		f.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		return "+OK\r\n"
+	case "INCRBY":
+		n, _ := strconv.ParseInt(args[2], 10, 64)
+		f.values[key] += n
+		return fmt.Sprintf(":%d\r\n", f.values[key])
+	case "GET":
+		if !exists {
+			return "$-1\r\n"
+		}
+		value := strconv.FormatInt(f.values[key], 10)
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "PTTL":
+		if !exists {
+			return ":-2\r\n"
+		}
+		expire, ok := f.expires[key]
+		if !ok {
+			return ":-1\r\n"
+		}
+		return fmt.Sprintf(":%d\r\n", time.Until(expire).Milliseconds())
+	case "PEXPIRE":
+		if !exists {
+			return ":0\r\n"
+		}
+		ms, _ := strconv.ParseInt(args[2], 10, 64)
+		f.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		return ":1\r\n"
+	case "DEL":
+		delete(f.values, key)
+		delete(f.expires, key)
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func TestRedisStoreSharedByReplicas(t *testing.T) {
+	fake := newFakeRedis(t)
+	defer fake.listener.Close()
+	cfg := &LimiterStoreConfig{Type: LimiterStoreRedis, Address: fake.listener.Addr().String()}
+	replica1, err := newLimiterStore(cfg)
+	require.NoError(t, err)
+	replica2, err := newLimiterStore(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	rate := slimiter.Rate{Period: time.Minute, Limit: 3}
+	for i := 0; i < 3; i++ {
+		store := replica1
+		if i%2 == 1 {
+			store = replica2
+		}
+		limiterCtx, err := store.Increment(ctx, "ip_1.2.3.4", 1, rate)
+		assert.NoError(t, err)
+		assert.False(t, limiterCtx.Reached)
+	}
+	limiterCtx, err := replica2.Increment(ctx, "ip_1.2.3.4", 1, rate)
+	assert.NoError(t, err)
+	assert.True(t, limiterCtx.Reached)
+	assert.Greater(t, limiterCtx.Reset, time.Now().Unix())
+
+	limiterCtx, err = replica1.Peek(ctx, "ip_1.2.3.4", rate)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), limiterCtx.Remaining)
+	_, err = replica1.Reset(ctx, "ip_1.2.3.4", rate)
+	assert.NoError(t, err)
+	limiterCtx, err = replica2.Peek(ctx, "ip_1.2.3.4", rate)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), limiterCtx.Remaining)
+}
+
+func TestRedisStoreExpiredInWindow(t *testing.T) {
+	fake := newFakeRedis(t)
+	defer fake.listener.Close()
+	// the key expires between SET and INCRBY, INCRBY creates the counter without expiration
+	expired := false
+	fake.afterCommand = func(args []string) {
+		if strings.EqualFold(args[0], "SET") && !expired {
+			expired = true
+			delete(fake.values, args[1])
+			delete(fake.expires, args[1])
+		}
+	}
+	store, err := newLimiterStore(&LimiterStoreConfig{Type: LimiterStoreRedis, Address: fake.listener.Addr().String()})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	rate := slimiter.Rate{Period: 100 * time.Millisecond, Limit: 1}
+	limiterCtx, err := store.Increment(ctx, "ip_1.2.3.4", 1, rate)
+	assert.NoError(t, err)
+	assert.False(t, limiterCtx.Reached)
+	fake.mux.Lock()
+	_, ok := fake.expires[limiterStorePrefix+":ip_1.2.3.4"]
+	fake.mux.Unlock()
+	assert.True(t, ok, "the counter without expiration is given the expiration again")
+	limiterCtx, err = store.Increment(ctx, "ip_1.2.3.4", 1, rate)
+	assert.NoError(t, err)
+	assert.True(t, limiterCtx.Reached)
+
+	// the window is reset after the period
+	time.Sleep(150 * time.Millisecond)
+	limiterCtx, err = store.Increment(ctx, "ip_1.2.3.4", 1, rate)
+	assert.NoError(t, err)
+	assert.False(t, limiterCtx.Reached)
+}
+
+func TestRedisStoreFallback(t *testing.T) {
+	fake := newFakeRedis(t)
+	cfg := &LimiterStoreConfig{Type: LimiterStoreRedis, Address: fake.listener.Addr().String()}
+	store, err := newLimiterStore(cfg)
+	require.NoError(t, err)
+	fake.listener.Close()
+
+	// the unavailable shared store neither rejects the requests nor returns the error
+	ctx := context.Background()
+	rate := slimiter.Rate{Period: time.Minute, Limit: 1}
+	limiterCtx, err := store.Increment(ctx, "bucket_test", 1, rate)
+	assert.NoError(t, err)
+	assert.False(t, limiterCtx.Reached)
+	// the limits are still enforced by the local store
+	limiterCtx, err = store.Increment(ctx, "bucket_test", 1, rate)
+	assert.NoError(t, err)
+	assert.True(t, limiterCtx.Reached)
+}
+
+func TestNewLimiterStore(t *testing.T) {
+	_, err := newLimiterStore(&LimiterStoreConfig{Type: LimiterStoreRedis})
+	assert.Error(t, err)
+	_, err = newLimiterStore(&LimiterStoreConfig{Type: "unknown"})
+	assert.Error(t, err)
+	store, err := newLimiterStore(&LimiterStoreConfig{})
+	assert.NoError(t, err)
+	assert.NotNil(t, store)
+}
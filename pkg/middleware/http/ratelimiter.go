@@ -7,10 +7,10 @@ import (
 	"regexp"
 	"strings"
 	"sync"
-	"time"
+	"sync/atomic"
 
+	lru "github.com/hashicorp/golang-lru"
 	slimiter "github.com/ulule/limiter/v3"
-	smemory "github.com/ulule/limiter/v3/drivers/store/memory"
 
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 )
@@ -28,10 +28,13 @@ type IPLimitConfig struct {
 }
 
 type RateLimiterConfig struct {
-	IPLimitCfg  IPLimitConfig
-	PathPattern []RateLimiterCell
-	HostPattern []RateLimiterCell
-	APILimits   []RateLimiterCell
+	StoreCfg        LimiterStoreConfig
+	IPLimitCfg      IPLimitConfig
+	AccountLimitCfg AccountLimitConfig
+	BucketLimitCfg  BucketLimitConfig
+	PathPattern     []RateLimiterCell
+	HostPattern     []RateLimiterCell
+	APILimits       []RateLimiterCell
 }
 
 type MemoryLimiterConfig struct {
@@ -40,44 +43,60 @@ type MemoryLimiterConfig struct {
 }
 
 type APILimiterConfig struct {
-	IPLimitCfg  IPLimitConfig
-	PathPattern map[string]MemoryLimiterConfig
-	APILimits   map[string]MemoryLimiterConfig // routePrefix-apiName  =>  limit config
-	HostPattern map[string]MemoryLimiterConfig
+	StoreCfg        LimiterStoreConfig
+	IPLimitCfg      IPLimitConfig
+	AccountLimitCfg AccountLimitConfig
+	BucketLimitCfg  BucketLimitConfig
+	PathPattern     map[string]MemoryLimiterConfig
+	APILimits       map[string]MemoryLimiterConfig // routePrefix-apiName  =>  limit config
+	HostPattern     map[string]MemoryLimiterConfig
 }
 
 type apiLimiter struct {
 	store      slimiter.Store
 	limiterMap sync.Map
 	cfg        APILimiterConfig
+
+	accountTiers       map[string]*limitTier // lower-cased account => limits of its class
+	defaultAccountTier *limitTier
+	bucketTier         *limitTier
+	bandwidth          *lru.Cache // account or bucket key => *rate.Limiter
 }
 
-var limiter *apiLimiter
+// limiter is swapped as a whole when the config is reloaded, the store is shared by the
+// limiters so the counters survive reloading unless the store config is changed.
+var limiter atomic.Pointer[apiLimiter]
 
 func NewAPILimiter(cfg *APILimiterConfig) error {
-	localStore := smemory.NewStoreWithOptions(slimiter.StoreOptions{
-		Prefix:          "sp_api_rate_limiter",
-		CleanUpInterval: 5 * time.Second,
-	})
-	limiter = &apiLimiter{
+	if err := ValidateAPILimiter(cfg); err != nil {
+		return err
+	}
+	var localStore slimiter.Store
+	var err error
+	if current := limiter.Load(); current != nil && current.cfg.StoreCfg == cfg.StoreCfg {
+		localStore = current.store
+	} else if localStore, err = newLimiterStore(&cfg.StoreCfg); err != nil {
+		return err
+	}
+	newLimiter := &apiLimiter{
 		store: localStore,
 		cfg: APILimiterConfig{
 			APILimits:   make(map[string]MemoryLimiterConfig),
 			PathPattern: make(map[string]MemoryLimiterConfig),
 			HostPattern: make(map[string]MemoryLimiterConfig),
 			IPLimitCfg:  cfg.IPLimitCfg,
+			StoreCfg:    cfg.StoreCfg,
 		},
 	}
 
-	var err error
 	var rate slimiter.Rate
 
 	for k, v := range cfg.PathPattern {
-		limiter.cfg.PathPattern[strings.ToLower(k)] = v
+		newLimiter.cfg.PathPattern[strings.ToLower(k)] = v
 	}
 
 	for k, v := range cfg.HostPattern {
-		limiter.cfg.HostPattern[strings.ToLower(k)] = v
+		newLimiter.cfg.HostPattern[strings.ToLower(k)] = v
 	}
 
 	for k, v := range cfg.APILimits {
@@ -86,12 +105,46 @@ func NewAPILimiter(cfg *APILimiterConfig) error {
 			return err
 		}
 
-		limiter.limiterMap.Store(strings.ToLower(k), slimiter.New(localStore, rate))
+		newLimiter.limiterMap.Store(strings.ToLower(k), slimiter.New(localStore, rate))
 	}
 
+	if err = newLimiter.initAccountLimits(cfg); err != nil {
+		return err
+	}
+	limiter.Store(newLimiter)
 	return nil
 }
 
+// ValidateAPILimiter checks the rates and the patterns of the config without applying it.
+func ValidateAPILimiter(cfg *APILimiterConfig) error {
+	formatRate := func(l MemoryLimiterConfig) error {
+		_, err := slimiter.NewRateFromFormatted(fmt.Sprintf("%d-%s", l.RateLimit, l.RatePeriod))
+		return err
+	}
+	for k, v := range cfg.APILimits {
+		if err := formatRate(v); err != nil {
+			return fmt.Errorf("invalid rate of api %s: %w", k, err)
+		}
+	}
+	for _, patterns := range []map[string]MemoryLimiterConfig{cfg.HostPattern, cfg.PathPattern} {
+		for p, v := range patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("invalid pattern %s: %w", p, err)
+			}
+			if err := formatRate(v); err != nil {
+				return fmt.Errorf("invalid rate of pattern %s: %w", p, err)
+			}
+		}
+	}
+	if cfg.IPLimitCfg.On {
+		if err := formatRate(MemoryLimiterConfig{RateLimit: cfg.IPLimitCfg.RateLimit,
+			RatePeriod: cfg.IPLimitCfg.RatePeriod}); err != nil {
+			return fmt.Errorf("invalid rate of ip limit: %w", err)
+		}
+	}
+	return (&apiLimiter{}).initAccountLimits(cfg)
+}
+
 func (a *apiLimiter) findLimiter(host, path, key string) *slimiter.Limiter {
 	newLimiter, ok := a.limiterMap.Load(key)
 	if ok {
@@ -172,13 +225,20 @@ func (t *apiLimiter) HTTPAllow(ctx context.Context, r *http.Request) bool {
 
 func Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !limiter.Allow(context.Background(), r) {
+		l := limiter.Load()
+		if !l.Allow(context.Background(), r) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		if !l.HTTPAllow(context.Background(), r) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusTooManyRequests)
 			return
 		}
 
-		if !limiter.HTTPAllow(context.Background(), r) {
+		if !l.BucketAllow(context.Background(), r) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusTooManyRequests)
 			return
@@ -0,0 +1,277 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	slimiter "github.com/ulule/limiter/v3"
+	"github.com/ulule/limiter/v3/drivers/store/common"
+)
+
+var _ slimiter.Store = &redisStore{}
+
+// redisError is the error reply of the redis server, the connection is still usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// redisStore is the limiter store that shares the fixed window counters across the gater
+// replicas through a server speaking the redis protocol. It only depends on the plain
+// commands SET, INCRBY, PTTL, PEXPIRE, GET, DEL and the MULTI/EXEC transaction, so any
+// redis compatible server works.
+type redisStore struct {
+	prefix   string
+	address  string
+	password string
+	db       int
+	timeout  time.Duration
+	pool     chan *redisConn
+}
+
+func newRedisStore(cfg *LimiterStoreConfig, prefix string) *redisStore {
+	poolSize := cfg.PoolSize
+	if poolSize <= 0 {
+		poolSize = DefaultRedisPoolSize
+	}
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = DefaultRedisTimeout
+	}
+	return &redisStore{
+		prefix:   prefix + ":",
+		address:  cfg.Address,
+		password: cfg.Password,
+		db:       cfg.DB,
+		timeout:  timeout,
+		pool:     make(chan *redisConn, poolSize),
+	}
+}
+
+func (s *redisStore) getConn() (*redisConn, error) {
+	select {
+	case c := <-s.pool:
+		return c, nil
+	default:
+	}
+	conn, err := net.DialTimeout("tcp", s.address, s.timeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn), writer: bufio.NewWriter(conn)}
+	var setup [][]string
+	if s.password != "" {
+		setup = append(setup, []string{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+	if len(setup) != 0 {
+		_ = conn.SetDeadline(time.Now().Add(s.timeout))
+		if _, err = c.pipeline(setup); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *redisStore) putConn(c *redisConn) {
+	select {
+	case s.pool <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// do sends the commands in a pipeline and returns the replies in order, the connection
+// is dropped on the network error.
+func (s *redisStore) do(ctx context.Context, cmds ...[]string) ([]interface{}, error) {
+	c, err := s.getConn()
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = c.conn.SetDeadline(deadline)
+	replies, err := c.pipeline(cmds)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.conn.Close()
+		return nil, err
+	}
+	s.putConn(c)
+	return replies, err
+}
+
+func (c *redisConn) pipeline(cmds [][]string) ([]interface{}, error) {
+	for _, cmd := range cmds {
+		fmt.Fprintf(c.writer, "*%d\r\n", len(cmd))
+		for _, arg := range cmd {
+			fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+	var (
+		replies  = make([]interface{}, len(cmds))
+		replyErr error
+	)
+	for i := range cmds {
+		reply, err := readReply(c.reader)
+		if err != nil {
+			var e redisError
+			if !errors.As(err, &e) {
+				return nil, err
+			}
+			// keep reading the remaining replies so the connection can be reused
+			if replyErr == nil {
+				replyErr = err
+			}
+		}
+		replies[i] = reply
+	}
+	return replies, replyErr
+}
+
+func readReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+	prefix, payload := line[0], line[1:len(line)-2]
+	switch prefix {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		var (
+			array    = make([]interface{}, size)
+			replyErr error
+		)
+		for i := range array {
+			if array[i], err = readReply(reader); err != nil {
+				var e redisError
+				if !errors.As(err, &e) {
+					return nil, err
+				}
+				// the error replies of the transaction are in the array, keep reading the rest
+				if replyErr == nil {
+					replyErr = err
+				}
+			}
+		}
+		return array, replyErr
+	default:
+		return nil, fmt.Errorf("unknown redis reply type %q", prefix)
+	}
+}
+
+// windowExpiration returns the end of the window by the pttl reply, the key without
+// expiration is treated as a new window.
+func windowExpiration(now time.Time, rate slimiter.Rate, pttl interface{}) time.Time {
+	if ttl, ok := pttl.(int64); ok && ttl > 0 {
+		return now.Add(time.Duration(ttl) * time.Millisecond)
+	}
+	return now.Add(rate.Period)
+}
+
+func (s *redisStore) Get(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.Increment(ctx, key, 1, rate)
+}
+
+// Increment creates the window with the expiration if it does not exist, then increases
+// the counter in one transaction, the expiration is kept by INCRBY so the window is fixed.
+// The counter left without expiration, e.g. by the client that failed in the middle or by
+// the server that does not run the transaction atomically, is given the expiration again,
+// otherwise it would never be reset.
+func (s *redisStore) Increment(ctx context.Context, key string, count int64, rate slimiter.Rate) (slimiter.Context, error) {
+	key = s.prefix + key
+	now := time.Now()
+	period := strconv.FormatInt(rate.Period.Milliseconds(), 10)
+	replies, err := s.do(ctx,
+		[]string{"MULTI"},
+		[]string{"SET", key, "0", "PX", period, "NX"},
+		[]string{"INCRBY", key, strconv.FormatInt(count, 10)},
+		[]string{"PTTL", key},
+		[]string{"EXEC"})
+	if err != nil {
+		return slimiter.Context{}, err
+	}
+	results, ok := replies[4].([]interface{})
+	if !ok || len(results) != 3 {
+		return slimiter.Context{}, fmt.Errorf("invalid redis transaction result %v", replies[4])
+	}
+	counter, ok := results[1].(int64)
+	if !ok {
+		return slimiter.Context{}, fmt.Errorf("invalid redis counter %v", results[1])
+	}
+	pttl := results[2]
+	if ttl, ok := pttl.(int64); !ok || ttl < 0 {
+		if _, err = s.do(ctx, []string{"PEXPIRE", key, period}); err != nil {
+			return slimiter.Context{}, err
+		}
+		pttl = rate.Period.Milliseconds()
+	}
+	return common.GetContextFromState(now, rate, windowExpiration(now, rate, pttl), counter), nil
+}
+
+func (s *redisStore) Peek(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	key = s.prefix + key
+	now := time.Now()
+	replies, err := s.do(ctx, []string{"GET", key}, []string{"PTTL", key})
+	if err != nil {
+		return slimiter.Context{}, err
+	}
+	var counter int64
+	if value, ok := replies[0].(string); ok {
+		if counter, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return slimiter.Context{}, err
+		}
+	}
+	return common.GetContextFromState(now, rate, windowExpiration(now, rate, replies[1]), counter), nil
+}
+
+func (s *redisStore) Reset(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	now := time.Now()
+	if _, err := s.do(ctx, []string{"DEL", s.prefix + key}); err != nil {
+		return slimiter.Context{}, err
+	}
+	return common.GetContextFromState(now, rate, now.Add(rate.Period), 0), nil
+}
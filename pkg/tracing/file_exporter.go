@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ sdktrace.SpanExporter = &FileExporter{}
+
+// FileExporter writes the spans to the local file, one json object per line. It is used
+// without the collector, e.g. in the local test environment.
+type FileExporter struct {
+	mux    sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// spanRecord is the json line of one span.
+type spanRecord struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	Name       string            `json:"name"`
+	Kind       string            `json:"kind"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Status     string            `json:"status"`
+	Message    string            `json:"message,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// NewFileExporter opens the file in append mode.
+func NewFileExporter(filePath string) (*FileExporter, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// ExportSpans writes the batch of spans and flushes the file.
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	encoder := json.NewEncoder(e.writer)
+	for _, span := range spans {
+		record := &spanRecord{
+			TraceID: span.SpanContext().TraceID().String(),
+			SpanID:  span.SpanContext().SpanID().String(),
+			Name:    span.Name(),
+			Kind:    span.SpanKind().String(),
+			Start:   span.StartTime(),
+			End:     span.EndTime(),
+			Status:  span.Status().Code.String(),
+			Message: span.Status().Description,
+		}
+		if span.Parent().IsValid() {
+			record.ParentID = span.Parent().SpanID().String()
+		}
+		if attrs := span.Attributes(); len(attrs) != 0 {
+			record.Attributes = make(map[string]string, len(attrs))
+			for _, attr := range attrs {
+				record.Attributes[string(attr.Key)] = attr.Value.Emit()
+			}
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return e.writer.Flush()
+}
+
+// Shutdown flushes and closes the file.
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+	return e.file.Close()
+}
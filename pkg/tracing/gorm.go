@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "sp:tracing_span"
+
+var _ gorm.Plugin = &gormPlugin{}
+
+// gormPlugin starts a client span for each statement, the span is the child of the span
+// in the statement context, so the db calls made with db.WithContext(ctx) are traced. The
+// statements without the parent span are skipped to avoid the orphan spans.
+type gormPlugin struct {
+	name string
+}
+
+// NewGormPlugin returns the gorm plugin tracing the statements, the name distinguishes
+// the databases, e.g. spdb and bsdb.
+func NewGormPlugin(name string) gorm.Plugin {
+	return &gormPlugin{name: name}
+}
+
+// Name returns the plugin name.
+func (p *gormPlugin) Name() string {
+	return "tracing:" + p.name
+}
+
+// Initialize registers the callbacks around all kinds of statements.
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	processors := []struct {
+		op     string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+	for _, processor := range processors {
+		if err := processor.before(p.Name()+":before_"+processor.op, p.before(processor.op)); err != nil {
+			return err
+		}
+		if err := processor.after(p.Name()+":after_"+processor.op, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *gormPlugin) before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil ||
+			!trace.SpanContextFromContext(db.Statement.Context).IsValid() {
+			return
+		}
+		ctx, span := StartSpan(db.Statement.Context, p.name+"."+op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", dbSystem(db))))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (p *gormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.table", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.RowsAffected))
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		EndSpan(span, db.Error)
+		return
+	}
+	span.End()
+}
+
+// dbSystem returns the name of the dialector, e.g. mysql and sqlite.
+func dbSystem(db *gorm.DB) string {
+	if db.Dialector == nil {
+		return "unknown"
+	}
+	return db.Dialector.Name()
+}
@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// TracerName defines the name of the tracer that creates all the spans of sp.
+	TracerName = "github.com/bnb-chain/greenfield-storage-provider"
+	// ExporterOTLP exports the spans to the OTLP collector by gRPC.
+	ExporterOTLP = "otlp"
+	// ExporterFile writes the spans to the local file in json lines, it is used offline.
+	ExporterFile = "file"
+
+	// DefaultServiceName defines the default service name of the spans.
+	DefaultServiceName = "greenfield-storage-provider"
+	// DefaultOTLPEndpoint defines the default address of the OTLP collector.
+	DefaultOTLPEndpoint = "localhost:4317"
+	// DefaultFilePath defines the default file that the spans are written to.
+	DefaultFilePath = "./trace.json"
+	// DefaultSampleRatio defines the default ratio of the sampled root spans.
+	DefaultSampleRatio = 1.0
+
+	traceParentKey = "traceparent"
+)
+
+// TracingConfig defines the configuration of tracing.
+type TracingConfig struct {
+	Enable       bool
+	ServiceName  string
+	Exporter     string // otlp or file, default otlp
+	OTLPEndpoint string
+	OTLPInsecure bool
+	FilePath     string
+	SampleRatio  float64
+}
+
+// Tracing installs the global tracer provider, the spans are exported in batch and flushed
+// at shutting down.
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+}
+
+// NewTracing creates the exporter and installs the global tracer provider, it should be
+// called before creating the services so all of their spans are recorded.
+func NewTracing(cfg *TracingConfig, modules []string) (*Tracing, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case "", ExporterOTLP:
+		endpoint := cfg.OTLPEndpoint
+		if endpoint == "" {
+			endpoint = DefaultOTLPEndpoint
+		}
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if cfg.OTLPInsecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(context.Background(), options...)
+	case ExporterFile:
+		filePath := cfg.FilePath
+		if filePath == "" {
+			filePath = DefaultFilePath
+		}
+		exporter, err = NewFileExporter(filePath)
+	default:
+		err = fmt.Errorf("unknown tracing exporter %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = DefaultSampleRatio
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+			attribute.StringSlice("sp.modules", modules))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return &Tracing{provider: provider}, nil
+}
+
+// Shutdown flushes the remaining spans and shuts down the exporter, it should be called
+// after all the services are stopped.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if err := t.provider.Shutdown(ctx); err != nil {
+		log.Errorw("failed to shutdown tracer provider", "error", err)
+		return err
+	}
+	return nil
+}
+
+// StartSpan starts a span with the global tracer, the span is a child of the span in ctx.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// EndSpan records the error if it is not nil and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// DetachContext returns a background context carrying the span of ctx, the work using it
+// outlives the canceling of ctx but is still traced under the span.
+func DetachContext(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, it is used to link the
+// background tasks to the originating request. It is empty if there is no sampled span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get(traceParentKey)
+}
+
+// ContextWithTraceParent returns the context carrying the remote span of the traceparent,
+// the spans started from it are children of the originating request.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
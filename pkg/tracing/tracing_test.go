@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTraceParentLinksTask(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "trace.json")
+	tracing, err := NewTracing(&TracingConfig{Enable: true, Exporter: ExporterFile, FilePath: filePath}, nil)
+	require.NoError(t, err)
+
+	ctx, upload := StartSpan(context.Background(), "upload")
+	traceParent := TraceParent(ctx)
+	assert.NotEmpty(t, traceParent)
+	upload.End()
+
+	// the background task continues the trace of the upload request by the traceparent
+	_, replicate := StartSpan(ContextWithTraceParent(context.Background(), traceParent), "replicate")
+	EndSpan(replicate, errors.New("mock error"))
+	require.NoError(t, tracing.Shutdown(context.Background()))
+
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer file.Close()
+	records := make(map[string]*spanRecord)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &spanRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		records[record.Name] = record
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, records["upload"].TraceID, records["replicate"].TraceID)
+	assert.Equal(t, records["upload"].SpanID, records["replicate"].ParentID)
+	assert.Equal(t, "Error", records["replicate"].Status)
+	assert.Empty(t, TraceParent(context.Background()))
+}
+
+func TestGormPluginDBSystem(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "trace.json")
+	tracing, err := NewTracing(&TracingConfig{Enable: true, Exporter: ExporterFile, FilePath: filePath}, nil)
+	require.NoError(t, err)
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormPlugin("spdb")))
+	ctx, root := StartSpan(context.Background(), "root")
+	require.NoError(t, db.WithContext(ctx).Exec("CREATE TABLE mock (id INTEGER)").Error)
+	root.End()
+	require.NoError(t, tracing.Shutdown(context.Background()))
+
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer file.Close()
+	records := make(map[string]*spanRecord)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &spanRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		records[record.Name] = record
+	}
+	require.Contains(t, records, "spdb.raw")
+	assert.Equal(t, "sqlite", records["spdb.raw"].Attributes["db.system"])
+}
@@ -10,3 +10,18 @@ func (b *BsDBImpl) GetLatestBlockNumber() (int64, error) {
 	err = b.db.Table((&Epoch{}).TableName()).Select("block_height").Take(&latestBlockNumber).Error
 	return latestBlockNumber, err
 }
+
+// GetBlockSyncerStatus get the sync mode and speed of the block syncer, it returns the following
+// mode if the block syncer has not written the status
+func (b *BsDBImpl) GetBlockSyncerStatus() (*BlockSyncerStatus, error) {
+	var statuses []*BlockSyncerStatus
+
+	err := b.db.Table((&BlockSyncerStatus{}).TableName()).Limit(1).Find(&statuses).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return &BlockSyncerStatus{OneRowID: true, Mode: BlockSyncerModeFollowing}, nil
+	}
+	return statuses[0], nil
+}
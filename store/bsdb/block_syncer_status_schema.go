@@ -0,0 +1,28 @@
+package bsdb
+
+const (
+	// BlockSyncerModeCatchUp defines the block syncer fetches the blocks concurrently and applies
+	// them by batches to catch up with the chain.
+	BlockSyncerModeCatchUp = "catch_up"
+	// BlockSyncerModeFollowing defines the block syncer follows the tip of the chain block by block.
+	BlockSyncerModeFollowing = "following"
+)
+
+// BlockSyncerStatus stores the sync mode and speed of the block syncer
+type BlockSyncerStatus struct {
+	// OneRowID defines if the table only has one row
+	OneRowID bool `gorm:"column:one_row_id;not null;default:true;primaryKey"`
+	// Mode defines the sync mode of the block syncer, catch_up or following
+	Mode string `gorm:"column:mode;type:varchar(16);not null"`
+	// CatchUpStartHeight defines the block height where the last catch-up started
+	CatchUpStartHeight int64 `gorm:"column:catch_up_start_height;type:bigint(64)"`
+	// BlocksPerSecond defines the apply speed of the blocks in the catch-up mode
+	BlocksPerSecond float64 `gorm:"column:blocks_per_second"`
+	// UpdateTime defines the unix time in seconds when the status is updated
+	UpdateTime int64 `gorm:"column:update_time;type:bigint(64)"`
+}
+
+// TableName is used to set BlockSyncerStatus table name in database
+func (s *BlockSyncerStatus) TableName() string {
+	return BlockSyncerStatusTableName
+}
@@ -0,0 +1,20 @@
+// Package bsdbtest provides the sqlite block syncer db for the tests of bsdb and the block
+// syncer modules.
+package bsdbtest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// NewDB opens a sqlite db in the temp dir of the test and migrates the tables.
+func NewDB(t testing.TB, tables ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "bsdb.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(tables...))
+	return db
+}
@@ -0,0 +1,44 @@
+package bsdb
+
+import (
+	"gorm.io/gorm"
+)
+
+// ListChangeEvents lists the change events whose cursor is greater than the cursor in the order
+// of the cursor, the events are filtered by the bucket name and the object name prefix if they
+// are not empty.
+func (b *BsDBImpl) ListChangeEvents(cursor uint64, bucketName, prefix string, limit int) ([]*ChangeEvent, error) {
+	var (
+		events  []*ChangeEvent
+		filters []func(*gorm.DB) *gorm.DB
+		err     error
+	)
+
+	if limit < 1 || limit > ChangeEventsDefaultSize {
+		limit = ChangeEventsDefaultSize
+	}
+	if bucketName != "" {
+		filters = append(filters, BucketNameFilter(bucketName))
+	}
+	if prefix != "" {
+		filters = append(filters, PrefixFilter(prefix))
+	}
+
+	err = b.db.Table((&ChangeEvent{}).TableName()).
+		Select("*").
+		Where("id > ?", cursor).
+		Scopes(filters...).
+		Order("id asc").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// GetLatestChangeEventCursor get the cursor of the latest change event, it is zero if there is no event
+func (b *BsDBImpl) GetLatestChangeEventCursor() (uint64, error) {
+	var cursor uint64
+	err := b.db.Table((&ChangeEvent{}).TableName()).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&cursor).Error
+	return cursor, err
+}
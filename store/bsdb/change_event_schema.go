@@ -0,0 +1,42 @@
+package bsdb
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// ChangeEvent is the normalized change of the bucket, object, group or policy, it is appended
+// by the block syncer and the events are ordered by the id which is the cursor of the change feed
+type ChangeEvent struct {
+	// ID defines db auto_increment id of event, it is the cursor of the change feed
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement;index:idx_bucket_name_id,priority:2"`
+	// EventKey is the hash of the block height, the tx hash, the type and the attributes of the
+	// chain event, so the event of the replayed block is appended only once
+	EventKey common.Hash `gorm:"column:event_key;type:BINARY(32);uniqueIndex:idx_event_key"`
+	// BlockHeight defines the block number of the event
+	BlockHeight int64 `gorm:"column:block_height"`
+	// Timestamp defines the block time of the event in seconds
+	Timestamp int64 `gorm:"column:timestamp"`
+	// TxHash defines the transaction hash of the event
+	TxHash common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+	// EventType defines the normalized type of the event, e.g. object_sealed
+	EventType string `gorm:"column:event_type;type:varchar(64)"`
+	// ResourceType defines the type of the changed resource, e.g. bucket, object and group
+	ResourceType string `gorm:"column:resource_type;type:varchar(16)"`
+	// ResourceID defines the id of the changed resource
+	ResourceID common.Hash `gorm:"column:resource_id;type:BINARY(32)"`
+	// BucketName defines the bucket of the changed bucket or object
+	BucketName string `gorm:"column:bucket_name;type:varchar(64);index:idx_bucket_name_id,priority:1"`
+	// ObjectName defines the name of the changed object
+	ObjectName string `gorm:"column:object_name;type:varchar(1024)"`
+	// GroupName defines the name of the changed group
+	GroupName string `gorm:"column:group_name;type:varchar(64)"`
+	// Operator defines the operator address of the event, it is empty if the event has no operator
+	Operator string `gorm:"column:operator;type:varchar(64)"`
+	// Attributes defines the attributes of the chain event in json
+	Attributes string `gorm:"column:attributes;type:text"`
+}
+
+// TableName is used to set ChangeEvent table name in database
+func (*ChangeEvent) TableName() string {
+	return ChangeEventTableName
+}
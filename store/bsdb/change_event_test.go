@@ -0,0 +1,67 @@
+package bsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListChangeEvents(t *testing.T) {
+	db := newTestBsDB(t)
+	require.NoError(t, db.AutoMigrate(&ChangeEvent{}))
+	bsDB := &BsDBImpl{db: db}
+	cursor, err := bsDB.GetLatestChangeEventCursor()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), cursor)
+
+	changes := []*ChangeEvent{
+		{BucketName: "bucket1", EventType: ChangeBucketCreated},
+		{BucketName: "bucket1", ObjectName: "dir/a", EventType: ChangeObjectCreated},
+		{BucketName: "bucket2", ObjectName: "dir/b", EventType: ChangeObjectCreated},
+		{BucketName: "bucket1", ObjectName: "other/c", EventType: ChangeObjectCreated},
+		{BucketName: "bucket1", ObjectName: "dir/d", EventType: ChangeObjectSealed},
+		{GroupName: "group", EventType: ChangeGroupCreated},
+	}
+	for i, change := range changes {
+		change.EventKey[0] = byte(i + 1)
+	}
+	require.NoError(t, db.Create(changes).Error)
+	cursor, err = bsDB.GetLatestChangeEventCursor()
+	require.NoError(t, err)
+	assert.Equal(t, changes[len(changes)-1].ID, cursor)
+
+	cases := []struct {
+		name       string
+		cursor     uint64
+		bucketName string
+		prefix     string
+		limit      int
+		wanted     []int
+	}{
+		{name: "all", wanted: []int{0, 1, 2, 3, 4, 5}},
+		{name: "after cursor", cursor: changes[2].ID, wanted: []int{3, 4, 5}},
+		{name: "latest cursor", cursor: cursor, wanted: nil},
+		{name: "limit", limit: 2, wanted: []int{0, 1}},
+		{name: "invalid limit is default", limit: -1, wanted: []int{0, 1, 2, 3, 4, 5}},
+		{name: "bucket", bucketName: "bucket1", wanted: []int{0, 1, 3, 4}},
+		{name: "bucket and prefix", bucketName: "bucket1", prefix: "dir/", wanted: []int{1, 4}},
+		{name: "bucket, prefix and cursor", cursor: changes[1].ID, bucketName: "bucket1", prefix: "dir/", wanted: []int{4}},
+		{name: "prefix of all buckets", prefix: "dir/", wanted: []int{1, 2, 4}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events, err := bsDB.ListChangeEvents(c.cursor, c.bucketName, c.prefix, c.limit)
+			require.NoError(t, err)
+			var ids []uint64
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			var wanted []uint64
+			for _, i := range c.wanted {
+				wanted = append(wanted, changes[i].ID)
+			}
+			assert.Equal(t, wanted, ids)
+		})
+	}
+}
@@ -6,12 +6,16 @@ const (
 	DeletedObjectsDefaultSize = 1000
 	// ExpiredBucketsDefaultSize defines the default size of ListExpiredBucketsBySp response
 	ExpiredBucketsDefaultSize = 1000
+	// ObjectsBySpDefaultSize defines the default size of ListObjectsBySp response
+	ObjectsBySpDefaultSize = 1000
 	// ListObjectsDefaultMaxKeys defines the default size of ListObjectsByBucketName response
 	ListObjectsDefaultMaxKeys = 50
 	// GetUserBucketsLimitSize defines the default limit for the number of buckets in any given account is 100
 	GetUserBucketsLimitSize = 100
 	// ListObjectsLimitSize defines the default limit of ListObjectsByBucketName response
 	ListObjectsLimitSize = 1000
+	// ChangeEventsDefaultSize defines the default size of ListChangeEvents response
+	ChangeEventsDefaultSize = 100
 )
 
 // define table name constant of block syncer db
@@ -30,8 +34,20 @@ const (
 	GroupTableName = "groups"
 	// MasterDBTableName defines the name of master db table
 	MasterDBTableName = "master_db"
+	// BlockSyncerStatusTableName defines the name of block syncer status table
+	BlockSyncerStatusTableName = "block_syncer_status"
 	// PrefixTreeTableName defines the name of prefix tree node table
 	PrefixTreeTableName = "slash_prefix_tree_nodes"
+	// BucketStatsTableName defines the name of bucket storage stats table
+	BucketStatsTableName = "bucket_stats"
+	// AccountStatsTableName defines the name of account storage stats table
+	AccountStatsTableName = "account_stats"
+	// ObjectStatsTableName defines the name of the table that records the counted state of objects
+	ObjectStatsTableName = "object_stats"
+	// ChangeEventTableName defines the name of change event table
+	ChangeEventTableName = "change_events"
+	// ObjectSpTableName defines the name of the table that maps the sealed objects to their sps
+	ObjectSpTableName = "object_sps"
 )
 
 // define the list objects const
@@ -40,3 +56,38 @@ const (
 	CommonPrefix = "common_prefix"
 	GroupAddress = "0x0000000000000000000000000000000000000000"
 )
+
+// define the object status that is counted in the storage stats
+const (
+	ObjectStatusCreated = "OBJECT_STATUS_CREATED"
+	ObjectStatusSealed  = "OBJECT_STATUS_SEALED"
+)
+
+// define the resource types of change events
+const (
+	ChangeResourceBucket = "bucket"
+	ChangeResourceObject = "object"
+	ChangeResourceGroup  = "group"
+)
+
+// define the normalized types of change events
+const (
+	ChangeBucketCreated        = "bucket_created"
+	ChangeBucketUpdated        = "bucket_updated"
+	ChangeBucketDeleted        = "bucket_deleted"
+	ChangeBucketDiscontinued   = "bucket_discontinued"
+	ChangeObjectCreated        = "object_created"
+	ChangeObjectSealed         = "object_sealed"
+	ChangeObjectSealRejected   = "object_seal_rejected"
+	ChangeObjectCreateCanceled = "object_create_canceled"
+	ChangeObjectCopied         = "object_copied"
+	ChangeObjectUpdated        = "object_updated"
+	ChangeObjectDeleted        = "object_deleted"
+	ChangeObjectDiscontinued   = "object_discontinued"
+	ChangeGroupCreated         = "group_created"
+	ChangeGroupMemberUpdated   = "group_member_updated"
+	ChangeGroupMemberLeft      = "group_member_left"
+	ChangeGroupDeleted         = "group_deleted"
+	ChangePolicyPut            = "policy_put"
+	ChangePolicyDeleted        = "policy_deleted"
+)
@@ -31,7 +31,7 @@ type Metadata interface {
 	// GetGroupsByGroupIDAndAccount get groups info by group id list and account id
 	GetGroupsByGroupIDAndAccount(groupIDList []common.Hash, account common.Address, includeRemoved bool) ([]*Group, error)
 	// ListObjectsByBucketName list objects info by a bucket name
-	ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error)
+	ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error)
 	// ListDeletedObjectsByBlockNumberRange list deleted objects info by a block number range
 	ListDeletedObjectsByBlockNumberRange(startBlockNumber int64, endBlockNumber int64, includePrivate bool) ([]*Object, error)
 	// ListExpiredBucketsBySp list expired buckets by sp
@@ -40,14 +40,26 @@ type Metadata interface {
 	GetObjectByName(objectName string, bucketName string, includePrivate bool) (*Object, error)
 	// GetSwitchDBSignal check if there is a signal to switch the database
 	GetSwitchDBSignal() (*MasterDB, error)
+	// GetBlockSyncerStatus get the sync mode and speed of the block syncer
+	GetBlockSyncerStatus() (*BlockSyncerStatus, error)
 	// GetBucketMetaByName get bucket info with its related info
 	GetBucketMetaByName(bucketName string, includePrivate bool) (*BucketFullMeta, error)
 	// ListGroupsByNameAndSourceType get groups list by specific parameters
 	ListGroupsByNameAndSourceType(name, prefix, sourceType string, limit, offset int, includeRemoved bool) ([]*Group, int64, error)
 	// ListObjectsByObjectID list objects by object ids
 	ListObjectsByObjectID(ids []common.Hash, includeRemoved bool) ([]*Object, error)
+	// ListObjectsBySp list the sealed objects whose primary or secondary sp is the sp, ordered by the db id
+	ListObjectsBySp(spAddress string, startID uint64, limit int) ([]*Object, error)
 	// ListBucketsByBucketID list buckets by bucket ids
 	ListBucketsByBucketID(ids []common.Hash, includeRemoved bool) ([]*Bucket, error)
+	// GetBucketStats get the storage stats of a bucket
+	GetBucketStats(bucketID common.Hash) (*BucketStats, error)
+	// GetAccountStats get the storage stats of the objects owned by an account
+	GetAccountStats(owner common.Address) (*AccountStats, error)
+	// ListChangeEvents list the change events after the cursor by a bucket name and an object name prefix
+	ListChangeEvents(cursor uint64, bucketName, prefix string, limit int) ([]*ChangeEvent, error)
+	// GetLatestChangeEventCursor get the cursor of the latest change event
+	GetLatestChangeEventCursor() (uint64, error)
 }
 
 // BSDB contains all the methods required by block syncer database
@@ -34,6 +34,36 @@ func (m *MockMetadata) EXPECT() *MockMetadataMockRecorder {
 	return m.recorder
 }
 
+// GetAccountStats mocks base method.
+func (m *MockMetadata) GetAccountStats(owner common.Address) (*AccountStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStats", owner)
+	ret0, _ := ret[0].(*AccountStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStats indicates an expected call of GetAccountStats.
+func (mr *MockMetadataMockRecorder) GetAccountStats(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStats", reflect.TypeOf((*MockMetadata)(nil).GetAccountStats), owner)
+}
+
+// GetBlockSyncerStatus mocks base method.
+func (m *MockMetadata) GetBlockSyncerStatus() (*BlockSyncerStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockSyncerStatus")
+	ret0, _ := ret[0].(*BlockSyncerStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockSyncerStatus indicates an expected call of GetBlockSyncerStatus.
+func (mr *MockMetadataMockRecorder) GetBlockSyncerStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockSyncerStatus", reflect.TypeOf((*MockMetadata)(nil).GetBlockSyncerStatus))
+}
+
 // GetBucketByID mocks base method.
 func (m *MockMetadata) GetBucketByID(bucketID int64, includePrivate bool) (*Bucket, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +109,21 @@ func (mr *MockMetadataMockRecorder) GetBucketMetaByName(bucketName, includePriva
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketMetaByName", reflect.TypeOf((*MockMetadata)(nil).GetBucketMetaByName), bucketName, includePrivate)
 }
 
+// GetBucketStats mocks base method.
+func (m *MockMetadata) GetBucketStats(bucketID common.Hash) (*BucketStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketStats", bucketID)
+	ret0, _ := ret[0].(*BucketStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketStats indicates an expected call of GetBucketStats.
+func (mr *MockMetadataMockRecorder) GetBucketStats(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketStats", reflect.TypeOf((*MockMetadata)(nil).GetBucketStats), bucketID)
+}
+
 // GetGroupsByGroupIDAndAccount mocks base method.
 func (m *MockMetadata) GetGroupsByGroupIDAndAccount(groupIDList []common.Hash, account common.Address, includeRemoved bool) ([]*Group, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +154,21 @@ func (mr *MockMetadataMockRecorder) GetLatestBlockNumber() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBlockNumber", reflect.TypeOf((*MockMetadata)(nil).GetLatestBlockNumber))
 }
 
+// GetLatestChangeEventCursor mocks base method.
+func (m *MockMetadata) GetLatestChangeEventCursor() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestChangeEventCursor")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestChangeEventCursor indicates an expected call of GetLatestChangeEventCursor.
+func (mr *MockMetadataMockRecorder) GetLatestChangeEventCursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestChangeEventCursor", reflect.TypeOf((*MockMetadata)(nil).GetLatestChangeEventCursor))
+}
+
 // GetObjectByName mocks base method.
 func (m *MockMetadata) GetObjectByName(objectName, bucketName string, includePrivate bool) (*Object, error) {
 	m.ctrl.T.Helper()
@@ -259,6 +319,36 @@ func (mr *MockMetadataMockRecorder) GetUserBucketsCount(accountID, includeRemove
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBucketsCount", reflect.TypeOf((*MockMetadata)(nil).GetUserBucketsCount), accountID, includeRemoved)
 }
 
+// ListBucketsByBucketID mocks base method.
+func (m *MockMetadata) ListBucketsByBucketID(ids []common.Hash, includeRemoved bool) ([]*Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBucketsByBucketID", ids, includeRemoved)
+	ret0, _ := ret[0].([]*Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBucketsByBucketID indicates an expected call of ListBucketsByBucketID.
+func (mr *MockMetadataMockRecorder) ListBucketsByBucketID(ids, includeRemoved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBucketsByBucketID", reflect.TypeOf((*MockMetadata)(nil).ListBucketsByBucketID), ids, includeRemoved)
+}
+
+// ListChangeEvents mocks base method.
+func (m *MockMetadata) ListChangeEvents(cursor uint64, bucketName, prefix string, limit int) ([]*ChangeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangeEvents", cursor, bucketName, prefix, limit)
+	ret0, _ := ret[0].([]*ChangeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangeEvents indicates an expected call of ListChangeEvents.
+func (mr *MockMetadataMockRecorder) ListChangeEvents(cursor, bucketName, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangeEvents", reflect.TypeOf((*MockMetadata)(nil).ListChangeEvents), cursor, bucketName, prefix, limit)
+}
+
 // ListDeletedObjectsByBlockNumberRange mocks base method.
 func (m *MockMetadata) ListDeletedObjectsByBlockNumberRange(startBlockNumber, endBlockNumber int64, includePrivate bool) ([]*Object, error) {
 	m.ctrl.T.Helper()
@@ -306,18 +396,48 @@ func (mr *MockMetadataMockRecorder) ListGroupsByNameAndSourceType(name, prefix,
 }
 
 // ListObjectsByBucketName mocks base method.
-func (m *MockMetadata) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (m *MockMetadata) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 	ret0, _ := ret[0].([]*ListObjectsResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListObjectsByBucketName indicates an expected call of ListObjectsByBucketName.
-func (mr *MockMetadataMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved interface{}) *gomock.Call {
+func (mr *MockMetadataMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockMetadata)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
+}
+
+// ListObjectsByObjectID mocks base method.
+func (m *MockMetadata) ListObjectsByObjectID(ids []common.Hash, includeRemoved bool) ([]*Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectsByObjectID", ids, includeRemoved)
+	ret0, _ := ret[0].([]*Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectsByObjectID indicates an expected call of ListObjectsByObjectID.
+func (mr *MockMetadataMockRecorder) ListObjectsByObjectID(ids, includeRemoved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByObjectID", reflect.TypeOf((*MockMetadata)(nil).ListObjectsByObjectID), ids, includeRemoved)
+}
+
+// ListObjectsBySp mocks base method.
+func (m *MockMetadata) ListObjectsBySp(spAddress string, startID uint64, limit int) ([]*Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectsBySp", spAddress, startID, limit)
+	ret0, _ := ret[0].([]*Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectsBySp indicates an expected call of ListObjectsBySp.
+func (mr *MockMetadataMockRecorder) ListObjectsBySp(spAddress, startID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockMetadata)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsBySp", reflect.TypeOf((*MockMetadata)(nil).ListObjectsBySp), spAddress, startID, limit)
 }
 
 // MockBSDB is a mock of BSDB interface.
@@ -343,6 +463,36 @@ func (m *MockBSDB) EXPECT() *MockBSDBMockRecorder {
 	return m.recorder
 }
 
+// GetAccountStats mocks base method.
+func (m *MockBSDB) GetAccountStats(owner common.Address) (*AccountStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStats", owner)
+	ret0, _ := ret[0].(*AccountStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStats indicates an expected call of GetAccountStats.
+func (mr *MockBSDBMockRecorder) GetAccountStats(owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStats", reflect.TypeOf((*MockBSDB)(nil).GetAccountStats), owner)
+}
+
+// GetBlockSyncerStatus mocks base method.
+func (m *MockBSDB) GetBlockSyncerStatus() (*BlockSyncerStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockSyncerStatus")
+	ret0, _ := ret[0].(*BlockSyncerStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockSyncerStatus indicates an expected call of GetBlockSyncerStatus.
+func (mr *MockBSDBMockRecorder) GetBlockSyncerStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockSyncerStatus", reflect.TypeOf((*MockBSDB)(nil).GetBlockSyncerStatus))
+}
+
 // GetBucketByID mocks base method.
 func (m *MockBSDB) GetBucketByID(bucketID int64, includePrivate bool) (*Bucket, error) {
 	m.ctrl.T.Helper()
@@ -388,6 +538,21 @@ func (mr *MockBSDBMockRecorder) GetBucketMetaByName(bucketName, includePrivate i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketMetaByName", reflect.TypeOf((*MockBSDB)(nil).GetBucketMetaByName), bucketName, includePrivate)
 }
 
+// GetBucketStats mocks base method.
+func (m *MockBSDB) GetBucketStats(bucketID common.Hash) (*BucketStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketStats", bucketID)
+	ret0, _ := ret[0].(*BucketStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketStats indicates an expected call of GetBucketStats.
+func (mr *MockBSDBMockRecorder) GetBucketStats(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketStats", reflect.TypeOf((*MockBSDB)(nil).GetBucketStats), bucketID)
+}
+
 // GetGroupsByGroupIDAndAccount mocks base method.
 func (m *MockBSDB) GetGroupsByGroupIDAndAccount(groupIDList []common.Hash, account common.Address, includeRemoved bool) ([]*Group, error) {
 	m.ctrl.T.Helper()
@@ -418,6 +583,21 @@ func (mr *MockBSDBMockRecorder) GetLatestBlockNumber() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBlockNumber", reflect.TypeOf((*MockBSDB)(nil).GetLatestBlockNumber))
 }
 
+// GetLatestChangeEventCursor mocks base method.
+func (m *MockBSDB) GetLatestChangeEventCursor() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestChangeEventCursor")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestChangeEventCursor indicates an expected call of GetLatestChangeEventCursor.
+func (mr *MockBSDBMockRecorder) GetLatestChangeEventCursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestChangeEventCursor", reflect.TypeOf((*MockBSDB)(nil).GetLatestChangeEventCursor))
+}
+
 // GetObjectByName mocks base method.
 func (m *MockBSDB) GetObjectByName(objectName, bucketName string, includePrivate bool) (*Object, error) {
 	m.ctrl.T.Helper()
@@ -568,6 +748,36 @@ func (mr *MockBSDBMockRecorder) GetUserBucketsCount(accountID, includeRemoved in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBucketsCount", reflect.TypeOf((*MockBSDB)(nil).GetUserBucketsCount), accountID, includeRemoved)
 }
 
+// ListBucketsByBucketID mocks base method.
+func (m *MockBSDB) ListBucketsByBucketID(ids []common.Hash, includeRemoved bool) ([]*Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBucketsByBucketID", ids, includeRemoved)
+	ret0, _ := ret[0].([]*Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBucketsByBucketID indicates an expected call of ListBucketsByBucketID.
+func (mr *MockBSDBMockRecorder) ListBucketsByBucketID(ids, includeRemoved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBucketsByBucketID", reflect.TypeOf((*MockBSDB)(nil).ListBucketsByBucketID), ids, includeRemoved)
+}
+
+// ListChangeEvents mocks base method.
+func (m *MockBSDB) ListChangeEvents(cursor uint64, bucketName, prefix string, limit int) ([]*ChangeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangeEvents", cursor, bucketName, prefix, limit)
+	ret0, _ := ret[0].([]*ChangeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangeEvents indicates an expected call of ListChangeEvents.
+func (mr *MockBSDBMockRecorder) ListChangeEvents(cursor, bucketName, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangeEvents", reflect.TypeOf((*MockBSDB)(nil).ListChangeEvents), cursor, bucketName, prefix, limit)
+}
+
 // ListDeletedObjectsByBlockNumberRange mocks base method.
 func (m *MockBSDB) ListDeletedObjectsByBlockNumberRange(startBlockNumber, endBlockNumber int64, includePrivate bool) ([]*Object, error) {
 	m.ctrl.T.Helper()
@@ -615,16 +825,46 @@ func (mr *MockBSDBMockRecorder) ListGroupsByNameAndSourceType(name, prefix, sour
 }
 
 // ListObjectsByBucketName mocks base method.
-func (m *MockBSDB) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (m *MockBSDB) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 	ret0, _ := ret[0].([]*ListObjectsResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListObjectsByBucketName indicates an expected call of ListObjectsByBucketName.
-func (mr *MockBSDBMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved interface{}) *gomock.Call {
+func (mr *MockBSDBMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockBSDB)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
+}
+
+// ListObjectsByObjectID mocks base method.
+func (m *MockBSDB) ListObjectsByObjectID(ids []common.Hash, includeRemoved bool) ([]*Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectsByObjectID", ids, includeRemoved)
+	ret0, _ := ret[0].([]*Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectsByObjectID indicates an expected call of ListObjectsByObjectID.
+func (mr *MockBSDBMockRecorder) ListObjectsByObjectID(ids, includeRemoved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByObjectID", reflect.TypeOf((*MockBSDB)(nil).ListObjectsByObjectID), ids, includeRemoved)
+}
+
+// ListObjectsBySp mocks base method.
+func (m *MockBSDB) ListObjectsBySp(spAddress string, startID uint64, limit int) ([]*Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObjectsBySp", spAddress, startID, limit)
+	ret0, _ := ret[0].([]*Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObjectsBySp indicates an expected call of ListObjectsBySp.
+func (mr *MockBSDBMockRecorder) ListObjectsBySp(spAddress, startID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockBSDB)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsBySp", reflect.TypeOf((*MockBSDB)(nil).ListObjectsBySp), spAddress, startID, limit)
 }
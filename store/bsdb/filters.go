@@ -1,6 +1,7 @@
 package bsdb
 
 import (
+	"github.com/forbole/juno/v4/common"
 	"gorm.io/gorm"
 )
 
@@ -10,12 +11,24 @@ func ContinuationTokenFilter(continuationToken string) func(db *gorm.DB) *gorm.D
 	}
 }
 
+func ReverseContinuationTokenFilter(continuationToken string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("object_name <= ?", continuationToken)
+	}
+}
+
 func PrefixFilter(prefix string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where("object_name LIKE ?", prefix+"%")
 	}
 }
 
+func BucketNameFilter(bucketName string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("bucket_name = ?", bucketName)
+	}
+}
+
 func PathNameFilter(pathName string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where("path_name = ?", pathName)
@@ -45,3 +58,56 @@ func RemovedFilter(removed bool) func(db *gorm.DB) *gorm.DB {
 		return db.Where("removed = ?", removed)
 	}
 }
+
+func ContentTypeFilter(contentType string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("content_type = ?", contentType)
+	}
+}
+
+func MinPayloadSizeFilter(size uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("payload_size >= ?", size)
+	}
+}
+
+func MaxPayloadSizeFilter(size uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("payload_size <= ?", size)
+	}
+}
+
+func StartCreateTimeFilter(createTime int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("create_time >= ?", createTime)
+	}
+}
+
+func EndCreateTimeFilter(createTime int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("create_time < ?", createTime)
+	}
+}
+
+func ObjectStatusFilter(status string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", status)
+	}
+}
+
+func OwnerFilter(owner common.Address) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("owner = ?", owner)
+	}
+}
+
+// SortedContinuationTokenFilter continues listing from the object of the sort value and the
+// object name, the objects of the same sort value are ordered by the object name.
+func SortedContinuationTokenFilter(column string, value interface{}, objectName string, desc bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if desc {
+			return db.Where("("+column+" < ? or ("+column+" = ? and object_name <= ?))", value, value, objectName)
+		}
+		return db.Where("("+column+" > ? or ("+column+" = ? and object_name >= ?))", value, value, objectName)
+	}
+}
@@ -0,0 +1,165 @@
+package bsdb
+
+import (
+	"fmt"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
+)
+
+// MigrationDBName defines the name of bsdb in the migration records.
+const MigrationDBName = "bsdb"
+
+// Migrations returns the ordered schema migrations of bsdb, they are applied by the block
+// syncer after the tables of its modules are prepared. The released migrations must not be
+// modified, the schema changes are appended as the new versions.
+func Migrations() []*migrate.Migration {
+	return []*migrate.Migration{
+		{
+			// the baseline of the tables that are created by the block syncer modules.
+			Version: 1,
+			Name:    "init",
+			Up:      func(db *gorm.DB) error { return nil },
+			Down:    func(db *gorm.DB) error { return nil },
+		},
+		{
+			// the later object events are counted by the storage stats module of the block
+			// syncer, the stats of the existing objects are recomputed offline by the
+			// stats.reconcile command, so the startup does not scan the objects table.
+			Version: 2,
+			Name:    "create_storage_stats",
+			Up: func(db *gorm.DB) error {
+				if err := db.AutoMigrate(&objectStatsV2{}, &bucketStatsV2{}, &accountStatsV2{}); err != nil {
+					return err
+				}
+				log.Warn("the storage stats of the existing objects are not counted, run stats.reconcile --fix to recompute them")
+				return nil
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&objectStatsV2{}, &bucketStatsV2{}, &accountStatsV2{})
+			},
+		},
+		{
+			// the indexes of sorting the listed objects of the bucket, the content type is not
+			// indexed because it is the text column without length.
+			Version: 3,
+			Name:    "create_list_objects_indexes",
+			Up: func(db *gorm.DB) error {
+				for name, columns := range listObjectsIndexes {
+					if db.Migrator().HasIndex(ObjectTableName, name) {
+						continue
+					}
+					if err := db.Exec(fmt.Sprintf("CREATE INDEX ? ON ? (%s)", columns),
+						clause.Column{Name: name}, clause.Table{Name: ObjectTableName}).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Down: func(db *gorm.DB) error {
+				for name := range listObjectsIndexes {
+					if !db.Migrator().HasIndex(ObjectTableName, name) {
+						continue
+					}
+					if err := db.Migrator().DropIndex(ObjectTableName, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// the change events are appended by the change feed module of the block syncer
+			// from the next synced block.
+			Version: 4,
+			Name:    "create_change_events",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&changeEventV4{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&changeEventV4{})
+			},
+		},
+		{
+			// the status is written by the block syncer when it switches between the catch-up
+			// mode and the following mode.
+			Version: 5,
+			Name:    "create_block_syncer_status",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&blockSyncerStatusV5{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&blockSyncerStatusV5{})
+			},
+		},
+		{
+			// the mapping of the existing sealed objects is backfilled by batches, the later sealed
+			// objects are mapped by the object sp module of the block syncer. Every batch is
+			// committed by itself, the inserted mappings are skipped when the backfill reruns.
+			Version:       6,
+			Name:          "create_object_sps",
+			NoTransaction: true,
+			Up: func(db *gorm.DB) error {
+				if err := db.AutoMigrate(&objectSpV6{}); err != nil {
+					return err
+				}
+				return backfillObjectSps(db)
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&objectSpV6{})
+			},
+		},
+	}
+}
+
+// listObjectsIndexes defines the index names and the columns of the objects table that are
+// used by ListObjectsByBucketName sorted by the create time or the payload size.
+var listObjectsIndexes = map[string]string{
+	"idx_bucket_name_create_time":  "bucket_name, create_time",
+	"idx_bucket_name_payload_size": "bucket_name, payload_size",
+}
+
+// objectSpBackfillBatchSize defines the number of the objects that are mapped in a batch.
+const objectSpBackfillBatchSize = 1000
+
+// backfillObjectSps maps the existing sealed objects to their sps by the batches of the object id.
+func backfillObjectSps(db *gorm.DB) error {
+	type sealedObject struct {
+		ID                   uint64
+		ObjectID             common.Hash
+		SecondarySpAddresses pq.StringArray
+		PrimarySpAddress     common.Address
+	}
+	var lastID uint64
+	for {
+		var objects []*sealedObject
+		err := db.Table(ObjectTableName).
+			Select("objects.id, objects.object_id, objects.secondary_sp_addresses, buckets.primary_sp_address").
+			Joins("join buckets on buckets.bucket_id = objects.bucket_id").
+			Where("objects.id > ? and objects.removed = false and objects.status = 'OBJECT_STATUS_SEALED'", lastID).
+			Order("objects.id").
+			Limit(objectSpBackfillBatchSize).
+			Find(&objects).Error
+		if err != nil {
+			return err
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+		var objectSps []*objectSpV6
+		for _, object := range objects {
+			for _, objectSp := range NewObjectSps(object.ID, object.ObjectID, object.PrimarySpAddress, object.SecondarySpAddresses) {
+				objectSps = append(objectSps, &objectSpV6{SpAddress: objectSp.SpAddress, ObjectDBID: objectSp.ObjectDBID, ObjectID: objectSp.ObjectID})
+			}
+		}
+		if err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&objectSps).Error; err != nil {
+			return err
+		}
+		lastID = objects[len(objects)-1].ID
+	}
+}
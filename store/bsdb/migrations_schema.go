@@ -0,0 +1,99 @@
+package bsdb
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// The schemas of the tables are frozen at the migration that creates them, so the released
+// migrations are not changed by the later changes of the table schemas. A schema change is
+// appended as the new migration with its own frozen schema.
+
+// storageStatsV2 is the schema of the storage counters in the migration v2.
+type storageStatsV2 struct {
+	ObjectCount   int64 `gorm:"column:object_count"`
+	PayloadSize   int64 `gorm:"column:payload_size"`
+	SealedCount   int64 `gorm:"column:sealed_count"`
+	UnsealedCount int64 `gorm:"column:unsealed_count"`
+}
+
+// objectStatsV2 is the schema of the object stats table in the migration v2.
+type objectStatsV2 struct {
+	ObjectID     common.Hash    `gorm:"column:object_id;type:BINARY(32);primaryKey"`
+	BucketID     common.Hash    `gorm:"column:bucket_id;type:BINARY(32)"`
+	BucketName   string         `gorm:"column:bucket_name;type:varchar(64)"`
+	Owner        common.Address `gorm:"column:owner;type:BINARY(20)"`
+	PayloadSize  uint64         `gorm:"column:payload_size"`
+	ObjectStatus string         `gorm:"column:status;type:varchar(64)"`
+}
+
+func (*objectStatsV2) TableName() string {
+	return ObjectStatsTableName
+}
+
+// bucketStatsV2 is the schema of the bucket stats table in the migration v2.
+type bucketStatsV2 struct {
+	BucketID       common.Hash `gorm:"column:bucket_id;type:BINARY(32);primaryKey"`
+	BucketName     string      `gorm:"column:bucket_name;type:varchar(64)"`
+	storageStatsV2 `gorm:"embedded"`
+	UpdateAt       int64 `gorm:"column:update_at"`
+}
+
+func (*bucketStatsV2) TableName() string {
+	return BucketStatsTableName
+}
+
+// accountStatsV2 is the schema of the account stats table in the migration v2.
+type accountStatsV2 struct {
+	Owner          common.Address `gorm:"column:owner;type:BINARY(20);primaryKey"`
+	storageStatsV2 `gorm:"embedded"`
+	UpdateAt       int64 `gorm:"column:update_at"`
+}
+
+func (*accountStatsV2) TableName() string {
+	return AccountStatsTableName
+}
+
+// changeEventV4 is the schema of the change events table in the migration v4.
+type changeEventV4 struct {
+	ID           uint64      `gorm:"column:id;primaryKey;autoIncrement;index:idx_bucket_name_id,priority:2"`
+	EventKey     common.Hash `gorm:"column:event_key;type:BINARY(32);uniqueIndex:idx_event_key"`
+	BlockHeight  int64       `gorm:"column:block_height"`
+	Timestamp    int64       `gorm:"column:timestamp"`
+	TxHash       common.Hash `gorm:"column:tx_hash;type:BINARY(32)"`
+	EventType    string      `gorm:"column:event_type;type:varchar(64)"`
+	ResourceType string      `gorm:"column:resource_type;type:varchar(16)"`
+	ResourceID   common.Hash `gorm:"column:resource_id;type:BINARY(32)"`
+	BucketName   string      `gorm:"column:bucket_name;type:varchar(64);index:idx_bucket_name_id,priority:1"`
+	ObjectName   string      `gorm:"column:object_name;type:varchar(1024)"`
+	GroupName    string      `gorm:"column:group_name;type:varchar(64)"`
+	Operator     string      `gorm:"column:operator;type:varchar(64)"`
+	Attributes   string      `gorm:"column:attributes;type:text"`
+}
+
+func (*changeEventV4) TableName() string {
+	return ChangeEventTableName
+}
+
+// blockSyncerStatusV5 is the schema of the block syncer status table in the migration v5.
+type blockSyncerStatusV5 struct {
+	OneRowID           bool    `gorm:"column:one_row_id;not null;default:true;primaryKey"`
+	Mode               string  `gorm:"column:mode;type:varchar(16);not null"`
+	CatchUpStartHeight int64   `gorm:"column:catch_up_start_height;type:bigint(64)"`
+	BlocksPerSecond    float64 `gorm:"column:blocks_per_second"`
+	UpdateTime         int64   `gorm:"column:update_time;type:bigint(64)"`
+}
+
+func (*blockSyncerStatusV5) TableName() string {
+	return BlockSyncerStatusTableName
+}
+
+// objectSpV6 is the schema of the object sps table in the migration v6.
+type objectSpV6 struct {
+	SpAddress  common.Address `gorm:"column:sp_address;type:BINARY(20);primaryKey"`
+	ObjectDBID uint64         `gorm:"column:object_db_id;primaryKey;autoIncrement:false"`
+	ObjectID   common.Hash    `gorm:"column:object_id;type:BINARY(32);index:idx_object_id"`
+}
+
+func (*objectSpV6) TableName() string {
+	return ObjectSpTableName
+}
@@ -1,6 +1,8 @@
 package bsdb
 
 import (
+	"fmt"
+
 	"github.com/forbole/juno/v4/common"
 	"gorm.io/gorm"
 )
@@ -12,11 +14,13 @@ import (
 // - prefix: A prefix to filter the objects by their object names.
 // - delimiter: A delimiter to group objects that share a common prefix. An empty delimiter means no grouping.
 // - maxKeys: The maximum number of objects to return in the result.
+// - filter: The optional conditions and order of the objects, it can not be used with the delimiter.
 //
 // The function returns a slice of ListObjectsResult, which contains information about the objects and their types (object or common_prefix).
 // If there is a delimiter specified, the function will group objects that share a common prefix and return them as common_prefix in the result.
 // If the delimiter is empty, the function will return all objects without grouping them by a common prefix.
-func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool,
+	filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	var (
 		err     error
 		limit   int
@@ -27,39 +31,44 @@ func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix
 	// return NextContinuationToken by adding 1 additionally
 	limit = maxKeys + 1
 
+	if err = filter.Validate(); err != nil {
+		return nil, err
+	}
+
 	// If delimiter is specified, execute a raw SQL query to:
 	// 1. Retrieve objects from the given bucket with matching prefix and continuationToken
 	// 2. Find common prefixes based on the delimiter
 	// 3. Limit results
 	if delimiter != "" {
+		if !filter.IsEmpty() {
+			return nil, fmt.Errorf("%w: filter can not be used with delimiter", ErrInvalidListObjectsFilter)
+		}
 		results, err = b.ListObjects(bucketName, continuationToken, prefix, maxKeys)
 	} else {
 		// If delimiter is not specified, retrieve objects directly
 
 		if continuationToken != "" {
-			filters = append(filters, ContinuationTokenFilter(continuationToken))
+			tokenFilter, tokenErr := filter.continuationTokenFilter(continuationToken)
+			if tokenErr != nil {
+				return nil, tokenErr
+			}
+			filters = append(filters, tokenFilter)
 		}
 		if prefix != "" {
 			filters = append(filters, PrefixFilter(prefix))
 		}
-
-		if includeRemoved {
-			err = b.db.Table((&Object{}).TableName()).
-				Select("*").
-				Where("bucket_name = ?", bucketName).
-				Scopes(filters...).
-				Limit(limit).
-				Order("object_name asc").
-				Find(&results).Error
-		} else {
-			err = b.db.Table((&Object{}).TableName()).
-				Select("*").
-				Where("bucket_name = ? and removed = false", bucketName).
-				Scopes(filters...).
-				Limit(limit).
-				Order("object_name asc").
-				Find(&results).Error
+		if !includeRemoved {
+			filters = append(filters, RemovedFilter(false))
 		}
+		filters = append(filters, filter.scopes()...)
+
+		err = b.db.Table((&Object{}).TableName()).
+			Select("*").
+			Where("bucket_name = ?", bucketName).
+			Scopes(filters...).
+			Limit(limit).
+			Order(filter.order()).
+			Find(&results).Error
 	}
 	return results, err
 }
@@ -136,3 +145,28 @@ func (b *BsDBImpl) ListObjectsByObjectID(ids []common.Hash, includeRemoved bool)
 		Find(&objects).Error
 	return objects, err
 }
+
+// ListObjectsBySp list the sealed objects whose primary or secondary sp is the sp, the
+// objects are ordered by the db id which is greater than startID.
+func (b *BsDBImpl) ListObjectsBySp(spAddress string, startID uint64, limit int) ([]*Object, error) {
+	var (
+		objects []*Object
+		err     error
+	)
+
+	if limit < 1 || limit > ObjectsBySpDefaultSize {
+		limit = ObjectsBySpDefaultSize
+	}
+
+	// the objects of the sp are looked up by the primary key of the mapping of the sealed objects
+	err = b.db.Table((&Object{}).TableName()).
+		Select("objects.*").
+		Joins("join object_sps on object_sps.object_db_id = objects.id").
+		Where("object_sps.sp_address = ? and object_sps.object_db_id > ? and "+
+			"objects.removed = false and objects.status = 'OBJECT_STATUS_SEALED'",
+			common.HexToAddress(spAddress), startID).
+		Order("object_sps.object_db_id").
+		Limit(limit).
+		Find(&objects).Error
+	return objects, err
+}
@@ -0,0 +1,189 @@
+package bsdb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	"github.com/forbole/juno/v4/common"
+	"gorm.io/gorm"
+)
+
+// define the fields by which the listed objects are sorted
+const (
+	SortByObjectName  = "object_name"
+	SortByCreateTime  = "create_time"
+	SortByPayloadSize = "payload_size"
+)
+
+// ErrInvalidListObjectsFilter defines the filter or the continuation token of list objects is invalid.
+var ErrInvalidListObjectsFilter = errors.New("invalid list objects filter")
+
+// ListObjectsFilter defines the optional conditions and the order of ListObjectsByBucketName,
+// the nil or zero filter lists the objects in the ascending order of the object name.
+type ListObjectsFilter struct {
+	// ContentType limits the objects to the content type
+	ContentType string
+	// MinPayloadSize limits the objects whose payload size is not less than it
+	MinPayloadSize uint64
+	// MaxPayloadSize limits the objects whose payload size is not greater than it, zero means no limit
+	MaxPayloadSize uint64
+	// StartCreateTime limits the objects created at or after the timestamp in seconds, zero means no limit
+	StartCreateTime int64
+	// EndCreateTime limits the objects created before the timestamp in seconds, zero means no limit
+	EndCreateTime int64
+	// ObjectStatus limits the objects to the status, e.g. OBJECT_STATUS_SEALED
+	ObjectStatus string
+	// Owner limits the objects to the owner address
+	Owner string
+	// SortBy defines the field by which the objects are sorted, empty means SortByObjectName
+	SortBy string
+	// SortDesc indicates the objects are sorted in descending order
+	SortDesc bool
+}
+
+// IsEmpty returns true if the filter has no condition and uses the default order.
+func (f *ListObjectsFilter) IsEmpty() bool {
+	return f == nil || *f == ListObjectsFilter{}
+}
+
+// Validate checks the conditions and the sort field of the filter.
+func (f *ListObjectsFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	switch f.SortBy {
+	case "", SortByObjectName, SortByCreateTime, SortByPayloadSize:
+	default:
+		return fmt.Errorf("%w: unknown sort field %s", ErrInvalidListObjectsFilter, f.SortBy)
+	}
+	if f.MaxPayloadSize != 0 && f.MinPayloadSize > f.MaxPayloadSize {
+		return fmt.Errorf("%w: min payload size is greater than max payload size", ErrInvalidListObjectsFilter)
+	}
+	if f.StartCreateTime < 0 || f.EndCreateTime < 0 ||
+		(f.EndCreateTime != 0 && f.StartCreateTime >= f.EndCreateTime) {
+		return fmt.Errorf("%w: invalid create time range", ErrInvalidListObjectsFilter)
+	}
+	if _, ok := storagetypes.ObjectStatus_value[f.ObjectStatus]; f.ObjectStatus != "" && !ok {
+		return fmt.Errorf("%w: unknown object status %s", ErrInvalidListObjectsFilter, f.ObjectStatus)
+	}
+	if f.Owner != "" && !common.IsHexAddress(f.Owner) {
+		return fmt.Errorf("%w: invalid owner address %s", ErrInvalidListObjectsFilter, f.Owner)
+	}
+	return nil
+}
+
+func (f *ListObjectsFilter) sortBy() string {
+	if f == nil || f.SortBy == "" {
+		return SortByObjectName
+	}
+	return f.SortBy
+}
+
+func (f *ListObjectsFilter) sortDesc() bool {
+	return f != nil && f.SortDesc
+}
+
+// scopes returns the gorm scopes of the conditions.
+func (f *ListObjectsFilter) scopes() []func(*gorm.DB) *gorm.DB {
+	var filters []func(*gorm.DB) *gorm.DB
+	if f == nil {
+		return filters
+	}
+	if f.ContentType != "" {
+		filters = append(filters, ContentTypeFilter(f.ContentType))
+	}
+	if f.MinPayloadSize != 0 {
+		filters = append(filters, MinPayloadSizeFilter(f.MinPayloadSize))
+	}
+	if f.MaxPayloadSize != 0 {
+		filters = append(filters, MaxPayloadSizeFilter(f.MaxPayloadSize))
+	}
+	if f.StartCreateTime != 0 {
+		filters = append(filters, StartCreateTimeFilter(f.StartCreateTime))
+	}
+	if f.EndCreateTime != 0 {
+		filters = append(filters, EndCreateTimeFilter(f.EndCreateTime))
+	}
+	if f.ObjectStatus != "" {
+		filters = append(filters, ObjectStatusFilter(f.ObjectStatus))
+	}
+	if f.Owner != "" {
+		filters = append(filters, OwnerFilter(common.HexToAddress(f.Owner)))
+	}
+	return filters
+}
+
+// order returns the order clause, the objects of the same sort value are ordered by the object name.
+func (f *ListObjectsFilter) order() string {
+	direction := " asc"
+	if f.sortDesc() {
+		direction = " desc"
+	}
+	if f.sortBy() == SortByObjectName {
+		return SortByObjectName + direction
+	}
+	return f.sortBy() + direction + ", " + SortByObjectName + direction
+}
+
+// ContinuationToken returns the token to continue listing from the object. The token is the
+// object name if the objects are sorted by the object name, otherwise it is prefixed with the
+// sort field and the sort value, e.g. "payload_size:1024:a.txt", so the objects of the same
+// sort value are not skipped and the token of the other sort field is refused.
+func (f *ListObjectsFilter) ContinuationToken(object *Object) string {
+	switch f.sortBy() {
+	case SortByCreateTime:
+		return SortByCreateTime + ":" + strconv.FormatInt(object.CreateTime, 10) + ":" + object.ObjectName
+	case SortByPayloadSize:
+		return SortByPayloadSize + ":" + strconv.FormatUint(object.PayloadSize, 10) + ":" + object.ObjectName
+	default:
+		return object.ObjectName
+	}
+}
+
+// ParseContinuationToken returns the object name of the token, and the sort value if the
+// objects are not sorted by the object name.
+func (f *ListObjectsFilter) ParseContinuationToken(token string) (interface{}, string, error) {
+	sortBy := f.sortBy()
+	if sortBy == SortByObjectName {
+		return nil, token, nil
+	}
+	field, token, found := strings.Cut(token, ":")
+	if !found || field != sortBy {
+		return nil, "", fmt.Errorf("%w: continuation token is not sorted by %s", ErrInvalidListObjectsFilter, sortBy)
+	}
+	value, objectName, found := strings.Cut(token, ":")
+	if !found || objectName == "" {
+		return nil, "", fmt.Errorf("%w: invalid continuation token %s", ErrInvalidListObjectsFilter, token)
+	}
+	var (
+		sortValue interface{}
+		err       error
+	)
+	if sortBy == SortByCreateTime {
+		sortValue, err = strconv.ParseInt(value, 10, 64)
+	} else {
+		sortValue, err = strconv.ParseUint(value, 10, 64)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: invalid continuation token %s", ErrInvalidListObjectsFilter, token)
+	}
+	return sortValue, objectName, nil
+}
+
+// continuationTokenFilter returns the scope to continue listing from the token.
+func (f *ListObjectsFilter) continuationTokenFilter(token string) (func(*gorm.DB) *gorm.DB, error) {
+	value, objectName, err := f.ParseContinuationToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		if f.sortDesc() {
+			return ReverseContinuationTokenFilter(objectName), nil
+		}
+		return ContinuationTokenFilter(objectName), nil
+	}
+	return SortedContinuationTokenFilter(f.sortBy(), value, objectName, f.sortDesc()), nil
+}
@@ -0,0 +1,169 @@
+package bsdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListObjectsFilter_Validate(t *testing.T) {
+	cases := []struct {
+		name       string
+		filter     *ListObjectsFilter
+		wantedFail bool
+	}{
+		{name: "nil filter", filter: nil},
+		{name: "empty filter", filter: &ListObjectsFilter{}},
+		{name: "all conditions", filter: &ListObjectsFilter{ContentType: "text/plain", MinPayloadSize: 1, MaxPayloadSize: 10,
+			StartCreateTime: 1, EndCreateTime: 2, ObjectStatus: ObjectStatusSealed,
+			Owner: "0x0000000000000000000000000000000000000001", SortBy: SortByPayloadSize, SortDesc: true}},
+		{name: "min size without max size", filter: &ListObjectsFilter{MinPayloadSize: 10}},
+		{name: "equal min and max size", filter: &ListObjectsFilter{MinPayloadSize: 10, MaxPayloadSize: 10}},
+		{name: "unknown sort field", filter: &ListObjectsFilter{SortBy: "owner"}, wantedFail: true},
+		{name: "min size greater than max size", filter: &ListObjectsFilter{MinPayloadSize: 11, MaxPayloadSize: 10}, wantedFail: true},
+		{name: "negative start time", filter: &ListObjectsFilter{StartCreateTime: -1}, wantedFail: true},
+		{name: "negative end time", filter: &ListObjectsFilter{EndCreateTime: -1}, wantedFail: true},
+		{name: "empty time range", filter: &ListObjectsFilter{StartCreateTime: 2, EndCreateTime: 2}, wantedFail: true},
+		{name: "unknown status", filter: &ListObjectsFilter{ObjectStatus: "SEALED"}, wantedFail: true},
+		{name: "invalid owner", filter: &ListObjectsFilter{Owner: "0x01"}, wantedFail: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.filter.Validate()
+			if c.wantedFail {
+				assert.ErrorIs(t, err, ErrInvalidListObjectsFilter)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestListObjectsFilter_Order(t *testing.T) {
+	cases := []struct {
+		filter *ListObjectsFilter
+		order  string
+	}{
+		{filter: nil, order: "object_name asc"},
+		{filter: &ListObjectsFilter{SortDesc: true}, order: "object_name desc"},
+		{filter: &ListObjectsFilter{SortBy: SortByCreateTime}, order: "create_time asc, object_name asc"},
+		{filter: &ListObjectsFilter{SortBy: SortByPayloadSize, SortDesc: true}, order: "payload_size desc, object_name desc"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.order, c.filter.order())
+	}
+}
+
+func TestListObjectsFilter_ContinuationToken(t *testing.T) {
+	object := &Object{ObjectName: "dir/a:b.txt", CreateTime: 1690000000, PayloadSize: 1024}
+	cases := []struct {
+		name   string
+		filter *ListObjectsFilter
+		token  string
+		value  interface{}
+	}{
+		{name: "object name", filter: nil, token: "dir/a:b.txt"},
+		{name: "create time", filter: &ListObjectsFilter{SortBy: SortByCreateTime},
+			token: "create_time:1690000000:dir/a:b.txt", value: int64(1690000000)},
+		{name: "payload size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize, SortDesc: true},
+			token: "payload_size:1024:dir/a:b.txt", value: uint64(1024)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			token := c.filter.ContinuationToken(object)
+			assert.Equal(t, c.token, token)
+			value, objectName, err := c.filter.ParseContinuationToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, c.value, value)
+			assert.Equal(t, object.ObjectName, objectName)
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		filter *ListObjectsFilter
+		token  string
+	}{
+		{name: "object name token sorted by size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize}, token: "a.txt"},
+		{name: "create time token sorted by size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize},
+			token: "create_time:1690000000:a.txt"},
+		{name: "size token sorted by create time", filter: &ListObjectsFilter{SortBy: SortByCreateTime},
+			token: "payload_size:1024:a.txt"},
+		{name: "missing object name", filter: &ListObjectsFilter{SortBy: SortByPayloadSize}, token: "payload_size:1024"},
+		{name: "empty object name", filter: &ListObjectsFilter{SortBy: SortByPayloadSize}, token: "payload_size:1024:"},
+		{name: "tampered size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize}, token: "payload_size:1O24:a.txt"},
+		{name: "negative size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize}, token: "payload_size:-1:a.txt"},
+		{name: "tampered create time", filter: &ListObjectsFilter{SortBy: SortByCreateTime}, token: "create_time:x:a.txt"},
+	}
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := c.filter.ParseContinuationToken(c.token)
+			assert.ErrorIs(t, err, ErrInvalidListObjectsFilter)
+		})
+	}
+}
+
+func TestListObjectsByBucketNameSorted(t *testing.T) {
+	db := newTestBsDB(t)
+	bsDB := &BsDBImpl{db: db}
+	owner := common.HexToAddress("0x01")
+	objects := []*Object{
+		{ObjectName: "a", PayloadSize: 30, CreateTime: 3, ObjectStatus: ObjectStatusSealed},
+		{ObjectName: "b", PayloadSize: 10, CreateTime: 1, ObjectStatus: ObjectStatusSealed},
+		{ObjectName: "c", PayloadSize: 20, CreateTime: 2, ObjectStatus: ObjectStatusCreated},
+		{ObjectName: "d", PayloadSize: 20, CreateTime: 2, ObjectStatus: ObjectStatusSealed},
+		{ObjectName: "e", PayloadSize: 20, CreateTime: 4, ObjectStatus: ObjectStatusSealed},
+		{ObjectName: "f", PayloadSize: 50, CreateTime: 5, ObjectStatus: ObjectStatusSealed, Removed: true},
+	}
+	for i, object := range objects {
+		object.BucketName, object.Owner = "bucket", owner
+		object.ObjectID = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	require.NoError(t, db.Create(objects).Error)
+
+	// list pages of two objects as the metadata service does
+	list := func(filter *ListObjectsFilter) []string {
+		var (
+			names []string
+			token string
+		)
+		for {
+			results, err := bsDB.ListObjectsByBucketName("bucket", token, "", "", 2, false, filter)
+			require.NoError(t, err)
+			if len(results) <= 2 {
+				for _, result := range results {
+					names = append(names, result.ObjectName)
+				}
+				return names
+			}
+			for _, result := range results[:2] {
+				names = append(names, result.ObjectName)
+			}
+			token = filter.ContinuationToken(results[2].Object)
+		}
+	}
+	cases := []struct {
+		name   string
+		filter *ListObjectsFilter
+		names  []string
+	}{
+		{name: "object name", filter: nil, names: []string{"a", "b", "c", "d", "e"}},
+		{name: "object name desc", filter: &ListObjectsFilter{SortDesc: true}, names: []string{"e", "d", "c", "b", "a"}},
+		{name: "payload size", filter: &ListObjectsFilter{SortBy: SortByPayloadSize},
+			names: []string{"b", "c", "d", "e", "a"}},
+		{name: "payload size desc", filter: &ListObjectsFilter{SortBy: SortByPayloadSize, SortDesc: true},
+			names: []string{"a", "e", "d", "c", "b"}},
+		{name: "create time", filter: &ListObjectsFilter{SortBy: SortByCreateTime},
+			names: []string{"b", "c", "d", "a", "e"}},
+		{name: "sealed and sized", filter: &ListObjectsFilter{ObjectStatus: ObjectStatusSealed, MinPayloadSize: 20,
+			SortBy: SortByCreateTime, SortDesc: true}, names: []string{"e", "a", "d"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.names, list(c.filter))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package bsdb
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// ObjectSp maps the sealed object to its primary sp and secondary sps, it is maintained by the
+// object sp module of the block syncer, and the objects of a sp are listed by its primary key
+// instead of scanning the secondary sp addresses of all the objects
+type ObjectSp struct {
+	// SpAddress defines the operator address of the primary sp or a secondary sp of the object
+	SpAddress common.Address `gorm:"column:sp_address;type:BINARY(20);primaryKey"`
+	// ObjectDBID defines the db auto_increment id of the object, the objects of a sp are ordered by it
+	ObjectDBID uint64 `gorm:"column:object_db_id;primaryKey;autoIncrement:false"`
+	// ObjectID is the unique identifier of object
+	ObjectID common.Hash `gorm:"column:object_id;type:BINARY(32);index:idx_object_id"`
+}
+
+// TableName is used to set ObjectSp table name in database
+func (*ObjectSp) TableName() string {
+	return ObjectSpTableName
+}
+
+// NewObjectSps returns the mapping of the object to its primary sp and secondary sps
+func NewObjectSps(objectDBID uint64, objectID common.Hash, primarySpAddress common.Address, secondarySpAddresses []string) []*ObjectSp {
+	objectSps := []*ObjectSp{{SpAddress: primarySpAddress, ObjectDBID: objectDBID, ObjectID: objectID}}
+	for _, address := range secondarySpAddresses {
+		spAddress := common.HexToAddress(address)
+		if spAddress == primarySpAddress {
+			continue
+		}
+		objectSps = append(objectSps, &ObjectSp{SpAddress: spAddress, ObjectDBID: objectDBID, ObjectID: objectID})
+	}
+	return objectSps
+}
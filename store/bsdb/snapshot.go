@@ -0,0 +1,518 @@
+package bsdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
+)
+
+const (
+	// SnapshotFormatVersion defines the version of the snapshot format.
+	SnapshotFormatVersion = 1
+	// SnapshotManifestFile defines the file name of the snapshot manifest.
+	SnapshotManifestFile = "manifest.json"
+	// snapshotBatchSize defines the number of rows that are inserted in one batch by the import.
+	snapshotBatchSize = 1000
+)
+
+var (
+	// ErrSnapshotChecksum defines the snapshot files are modified or corrupted.
+	ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+	// ErrSnapshotHeightMismatch defines the block height of the db is not the expected snapshot height.
+	ErrSnapshotHeightMismatch = errors.New("snapshot block height mismatch")
+	// ErrSnapshotIncompatible defines the snapshot can not be imported by the binary or into the db.
+	ErrSnapshotIncompatible = errors.New("incompatible snapshot")
+	// ErrSnapshotTableExists defines the table of the snapshot already exists in the target db.
+	ErrSnapshotTableExists = errors.New("snapshot table already exists")
+)
+
+// snapshotExcludedTables defines the tables that are not exported, the migration lock is held
+// by the running migration, the master flag belongs to the dual db of the block syncer, and the
+// status is rewritten by the block syncer when it starts.
+var snapshotExcludedTables = map[string]bool{
+	migrate.SchemaMigrationLockTableName: true,
+	MasterDBTableName:                    true,
+	BlockSyncerStatusTableName:           true,
+}
+
+var (
+	// snapshotTableNameRegexp matches the name of the snapshot table.
+	snapshotTableNameRegexp = regexp.MustCompile(`^\w+$`)
+	// snapshotCreateTableRegexp matches the statement that creates the table, the table name is the first group.
+	snapshotCreateTableRegexp = regexp.MustCompile("(?is)^\\s*CREATE\\s+TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?[`\"]?(\\w+)[`\"]?\\s*\\(")
+	// snapshotCreateIndexRegexp matches the statement that creates the index, the table name is the first group.
+	snapshotCreateIndexRegexp = regexp.MustCompile("(?is)^\\s*CREATE\\s+(?:UNIQUE\\s+)?INDEX\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?" +
+		"[`\"]?\\w+[`\"]?\\s+ON\\s+[`\"]?(\\w+)[`\"]?\\s*\\(")
+	// snapshotForbiddenDDLRegexp matches the clauses that read other tables or place the files out of the db.
+	snapshotForbiddenDDLRegexp = regexp.MustCompile(`(?i)\b(SELECT|DIRECTORY)\b`)
+)
+
+// SnapshotManifest describes a bsdb snapshot, the snapshot is a directory that contains the
+// manifest and a gzipped file of every table, the rows are json arrays of the column values.
+type SnapshotManifest struct {
+	FormatVersion int    `json:"format_version"`
+	Dialect       string `json:"dialect"`
+	// BlockHeight and BlockHash are the epoch checkpoint of the snapshot, the block syncer
+	// resumes syncing from the next block after importing.
+	BlockHeight   int64            `json:"block_height"`
+	BlockHash     string           `json:"block_hash"`
+	SchemaVersion uint64           `json:"schema_version"`
+	CreateTime    int64            `json:"create_time"`
+	Tables        []*SnapshotTable `json:"tables"`
+	// Checksum is the sha256 of the manifest that is encoded with the empty checksum.
+	Checksum string `json:"checksum"`
+}
+
+// SnapshotTable describes the exported table of the snapshot.
+type SnapshotTable struct {
+	Name string `json:"name"`
+	// DDL are the statements to create the table and its indexes.
+	DDL     []string `json:"ddl"`
+	Columns []string `json:"columns"`
+	// BinaryColumns are the columns whose values are encoded in base64.
+	BinaryColumns []string `json:"binary_columns,omitempty"`
+	Rows          uint64   `json:"rows"`
+	File          string   `json:"file"`
+	// Checksum is the sha256 of the table file.
+	Checksum string `json:"checksum"`
+}
+
+// computeChecksum returns the sha256 of the manifest that is encoded with the empty checksum.
+func (m *SnapshotManifest) computeChecksum() (string, error) {
+	manifest := *m
+	manifest.Checksum = ""
+	data, err := json.Marshal(&manifest)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ExportSnapshot exports the tables of bsdb into the directory in a read only transaction, so
+// the snapshot is consistent at the epoch block height even if the block syncer is running.
+// The export fails if height is not zero and the epoch block height is not the height.
+func ExportSnapshot(db *gorm.DB, dir string, height int64) (*SnapshotManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, SnapshotManifestFile)); err == nil {
+		return nil, fmt.Errorf("snapshot already exists in %s", dir)
+	}
+
+	manifest := &SnapshotManifest{
+		FormatVersion: SnapshotFormatVersion,
+		Dialect:       db.Dialector.Name(),
+		CreateTime:    time.Now().Unix(),
+	}
+	txOpts := &sql.TxOptions{ReadOnly: true}
+	if manifest.Dialect == "mysql" {
+		txOpts.Isolation = sql.LevelRepeatableRead
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		epoch := &Epoch{}
+		if err := tx.Take(epoch).Error; err != nil {
+			return fmt.Errorf("failed to get epoch: %w", err)
+		}
+		if height != 0 && epoch.BlockHeight != height {
+			return fmt.Errorf("%w: the epoch block height is %d", ErrSnapshotHeightMismatch, epoch.BlockHeight)
+		}
+		manifest.BlockHeight = epoch.BlockHeight
+		manifest.BlockHash = epoch.BlockHash.String()
+		if tx.Migrator().HasTable(&migrate.SchemaMigrationTable{}) {
+			if err := tx.Model(&migrate.SchemaMigrationTable{}).Where("db_name = ?", MigrationDBName).
+				Select("COALESCE(MAX(version), 0)").Scan(&manifest.SchemaVersion).Error; err != nil {
+				return fmt.Errorf("failed to get schema version: %w", err)
+			}
+		}
+
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return fmt.Errorf("failed to list tables: %w", err)
+		}
+		sort.Strings(tables)
+		for _, name := range tables {
+			if snapshotExcludedTables[name] {
+				continue
+			}
+			table, err := exportSnapshotTable(tx, dir, name)
+			if err != nil {
+				return fmt.Errorf("failed to export table %s: %w", name, err)
+			}
+			log.Infow("succeed to export snapshot table", "table", name, "rows", table.Rows)
+			manifest.Tables = append(manifest.Tables, table)
+		}
+		return nil
+	}, txOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Checksum, err = manifest.computeChecksum(); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// the manifest is written at last, the directory without manifest is an incomplete snapshot
+	if err = os.WriteFile(filepath.Join(dir, SnapshotManifestFile), data, 0o644); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// exportSnapshotTable writes the rows of the table into the gzipped file, the values are
+// encoded as strings, and the binary values are encoded in base64.
+func exportSnapshotTable(tx *gorm.DB, dir string, name string) (*SnapshotTable, error) {
+	ddl, err := showCreateTable(tx, name)
+	if err != nil {
+		return nil, err
+	}
+	table := &SnapshotTable{Name: name, DDL: ddl, File: name + ".jsonl.gz"}
+
+	rows, err := tx.Table(name).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	binary := make([]bool, len(columnTypes))
+	for i, columnType := range columnTypes {
+		table.Columns = append(table.Columns, columnType.Name())
+		typeName := strings.ToUpper(columnType.DatabaseTypeName())
+		if strings.Contains(typeName, "BINARY") || strings.Contains(typeName, "BLOB") {
+			binary[i] = true
+			table.BinaryColumns = append(table.BinaryColumns, columnType.Name())
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, table.File))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	gw := gzip.NewWriter(io.MultiWriter(f, hash))
+	encoder := json.NewEncoder(gw)
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	record := make([]*string, len(columnTypes))
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			record[i] = encodeSnapshotValue(value, binary[i])
+		}
+		if err = encoder.Encode(record); err != nil {
+			return nil, err
+		}
+		table.Rows++
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+	if err = f.Sync(); err != nil {
+		return nil, err
+	}
+	table.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return table, nil
+}
+
+// showCreateTable returns the statements to create the table and its indexes.
+func showCreateTable(tx *gorm.DB, name string) ([]string, error) {
+	switch tx.Dialector.Name() {
+	case "mysql":
+		var tableName, ddl string
+		if err := tx.Raw("SHOW CREATE TABLE ?", clause.Table{Name: name}).Row().Scan(&tableName, &ddl); err != nil {
+			return nil, err
+		}
+		return []string{ddl}, nil
+	case "sqlite":
+		var ddl []string
+		if err := tx.Raw("SELECT sql FROM sqlite_master WHERE tbl_name = ? AND sql IS NOT NULL ORDER BY type DESC",
+			name).Scan(&ddl).Error; err != nil {
+			return nil, err
+		}
+		return ddl, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported dialect %s", ErrSnapshotIncompatible, tx.Dialector.Name())
+	}
+}
+
+func encodeSnapshotValue(value interface{}, binary bool) *string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if binary {
+			s = base64.StdEncoding.EncodeToString(v)
+		} else {
+			s = string(v)
+		}
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		s = "0"
+		if v {
+			s = "1"
+		}
+	case time.Time:
+		s = v.UTC().Format("2006-01-02 15:04:05.999999")
+	default:
+		s = fmt.Sprint(v)
+	}
+	return &s
+}
+
+// ReadSnapshotManifest reads the manifest of the snapshot and verifies its checksum.
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &SnapshotManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion != SnapshotFormatVersion {
+		return nil, fmt.Errorf("%w: unknown format version %d", ErrSnapshotIncompatible, manifest.FormatVersion)
+	}
+	checksum, err := manifest.computeChecksum()
+	if err != nil {
+		return nil, err
+	}
+	if checksum != manifest.Checksum {
+		return nil, fmt.Errorf("%w: manifest", ErrSnapshotChecksum)
+	}
+	return manifest, nil
+}
+
+// VerifySnapshot reads the manifest of the snapshot and verifies the checksums of the table files.
+func VerifySnapshot(dir string) (*SnapshotManifest, error) {
+	manifest, err := ReadSnapshotManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range manifest.Tables {
+		f, err := os.Open(filepath.Join(dir, table.File))
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != table.Checksum {
+			return nil, fmt.Errorf("%w: table %s", ErrSnapshotChecksum, table.Name)
+		}
+	}
+	return manifest, nil
+}
+
+// ImportSnapshot verifies the snapshot and imports it into the db, the tables are created by
+// the statements of the snapshot, which may only create the table and its indexes. The import fails if a table exists unless overwrite is set,
+// then the existing table is dropped. Every table file is decoded before any table is dropped,
+// so the corrupted snapshot does not change the db. The tables are dropped and imported in a
+// transaction if the dialect supports the transactional ddl, the mysql ddl commits implicitly,
+// so the import interrupted by the db can be retried with overwrite.
+func ImportSnapshot(db *gorm.DB, dir string, overwrite bool) (*SnapshotManifest, error) {
+	manifest, err := VerifySnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Dialect != db.Dialector.Name() {
+		return nil, fmt.Errorf("%w: the snapshot is exported from %s", ErrSnapshotIncompatible, manifest.Dialect)
+	}
+	if latest := Migrations()[len(Migrations())-1].Version; manifest.SchemaVersion > latest {
+		return nil, fmt.Errorf("%w: the schema version %d is newer than %d", ErrSnapshotIncompatible,
+			manifest.SchemaVersion, latest)
+	}
+	for _, table := range manifest.Tables {
+		if err = validateSnapshotTable(table); err != nil {
+			return nil, err
+		}
+	}
+	for _, table := range manifest.Tables {
+		if !overwrite && db.Migrator().HasTable(table.Name) {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotTableExists, table.Name)
+		}
+	}
+	for _, table := range manifest.Tables {
+		if err = readSnapshotTable(dir, table, func([]map[string]interface{}) error { return nil }); err != nil {
+			return nil, fmt.Errorf("failed to decode table %s: %w", table.Name, err)
+		}
+	}
+
+	importTables := func(tx *gorm.DB) error {
+		for _, table := range manifest.Tables {
+			if !tx.Migrator().HasTable(table.Name) {
+				continue
+			}
+			if err := tx.Migrator().DropTable(table.Name); err != nil {
+				return err
+			}
+		}
+		for _, table := range manifest.Tables {
+			if err := importSnapshotTable(tx, dir, table); err != nil {
+				return fmt.Errorf("failed to import table %s: %w", table.Name, err)
+			}
+			log.Infow("succeed to import snapshot table", "table", table.Name, "rows", table.Rows)
+		}
+		return nil
+	}
+	if manifest.Dialect == "sqlite" {
+		err = db.Transaction(importTables)
+	} else {
+		err = importTables(db)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// validateSnapshotTable checks the names and the statements of the snapshot table, the
+// checksum of the manifest can be recomputed by anyone, so the snapshot from another sp is
+// not trusted. Only the single statement that creates the table or the index of the table
+// is accepted.
+func validateSnapshotTable(table *SnapshotTable) error {
+	if !snapshotTableNameRegexp.MatchString(table.Name) || table.File != table.Name+".jsonl.gz" {
+		return fmt.Errorf("%w: invalid table %q with file %q", ErrSnapshotIncompatible, table.Name, table.File)
+	}
+	if snapshotExcludedTables[table.Name] {
+		return fmt.Errorf("%w: table %s is not allowed", ErrSnapshotIncompatible, table.Name)
+	}
+	if len(table.DDL) == 0 {
+		return fmt.Errorf("%w: table %s has no ddl", ErrSnapshotIncompatible, table.Name)
+	}
+	for _, ddl := range table.DDL {
+		var matches []string
+		if matches = snapshotCreateTableRegexp.FindStringSubmatch(ddl); matches == nil {
+			matches = snapshotCreateIndexRegexp.FindStringSubmatch(ddl)
+		}
+		if matches == nil || matches[1] != table.Name ||
+			strings.Contains(strings.TrimRight(strings.TrimSpace(ddl), ";"), ";") ||
+			snapshotForbiddenDDLRegexp.MatchString(ddl) {
+			return fmt.Errorf("%w: table %s has the unexpected ddl %q", ErrSnapshotIncompatible, table.Name, ddl)
+		}
+	}
+	return nil
+}
+
+// importSnapshotTable creates the table and inserts the rows in batches.
+func importSnapshotTable(db *gorm.DB, dir string, table *SnapshotTable) error {
+	for _, ddl := range table.DDL {
+		if err := db.Exec(ddl).Error; err != nil {
+			return err
+		}
+	}
+	return readSnapshotTable(dir, table, func(batch []map[string]interface{}) error {
+		return db.Table(table.Name).Create(&batch).Error
+	})
+}
+
+// readSnapshotTable decodes the rows of the table file and passes them to flush in batches,
+// it fails if the rows do not match the columns or the row count of the manifest.
+func readSnapshotTable(dir string, table *SnapshotTable, flush func([]map[string]interface{}) error) error {
+	binary := make(map[string]bool, len(table.BinaryColumns))
+	for _, column := range table.BinaryColumns {
+		binary[column] = true
+	}
+
+	f, err := os.Open(filepath.Join(dir, table.File))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	decoder := json.NewDecoder(gr)
+
+	var (
+		rows  uint64
+		batch = make([]map[string]interface{}, 0, snapshotBatchSize)
+	)
+	for {
+		var record []*string
+		if err = decoder.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(record) != len(table.Columns) {
+			return fmt.Errorf("%w: the row has %d columns", ErrSnapshotIncompatible, len(record))
+		}
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			column := table.Columns[i]
+			switch {
+			case value == nil:
+				row[column] = nil
+			case binary[column]:
+				if row[column], err = base64.StdEncoding.DecodeString(*value); err != nil {
+					return err
+				}
+			default:
+				row[column] = *value
+			}
+		}
+		batch = append(batch, row)
+		rows++
+		if len(batch) == snapshotBatchSize {
+			if err = flush(batch); err != nil {
+				return err
+			}
+			batch = make([]map[string]interface{}, 0, snapshotBatchSize)
+		}
+	}
+	if len(batch) != 0 {
+		if err = flush(batch); err != nil {
+			return err
+		}
+	}
+	if rows != table.Rows {
+		return fmt.Errorf("%w: %d rows are imported, %d rows are expected", ErrSnapshotChecksum, rows, table.Rows)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package bsdb
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb/bsdbtest"
+)
+
+// snapshotValue is the row of the test table that has the binary and nullable columns.
+type snapshotValue struct {
+	ID   int64
+	Data []byte
+	Note *string
+}
+
+func newTestSnapshotDB(t *testing.T) *gorm.DB {
+	db := newTestBsDB(t)
+	hash := common.HexToHash("0xab")
+	require.NoError(t, db.Create(&Epoch{OneRowID: true, BlockHeight: 100, BlockHash: hash}).Error)
+	require.NoError(t, db.Create([]*Object{
+		{ObjectID: common.HexToHash("0x21"), BucketID: common.HexToHash("0x11"), BucketName: "bucket", ObjectName: "a", PayloadSize: 10},
+		{ObjectID: common.HexToHash("0x22"), BucketID: common.HexToHash("0x11"), BucketName: "bucket", ObjectName: "b", PayloadSize: 20},
+	}).Error)
+	require.NoError(t, db.Exec("CREATE TABLE snapshot_values (id INTEGER PRIMARY KEY, data BLOB, note TEXT)").Error)
+	note := "note"
+	require.NoError(t, db.Table("snapshot_values").Create([]*snapshotValue{
+		{ID: 1, Data: []byte{0x00, 0xff, 0x10}},
+		{ID: 2, Note: &note},
+	}).Error)
+	return db
+}
+
+func copySnapshot(t *testing.T, dir string) string {
+	target := t.TempDir()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(target, entry.Name()), data, 0o644))
+	}
+	return target
+}
+
+func assertSnapshotImported(t *testing.T, db *gorm.DB) {
+	epoch := &Epoch{}
+	require.NoError(t, db.Take(epoch).Error)
+	assert.Equal(t, int64(100), epoch.BlockHeight)
+	assert.Equal(t, common.HexToHash("0xab"), epoch.BlockHash)
+
+	var objects []*Object
+	require.NoError(t, db.Order("object_name").Find(&objects).Error)
+	require.Len(t, objects, 2)
+	assert.Equal(t, common.HexToHash("0x21"), objects[0].ObjectID)
+	assert.Equal(t, uint64(20), objects[1].PayloadSize)
+
+	var values []*snapshotValue
+	require.NoError(t, db.Table("snapshot_values").Order("id").Find(&values).Error)
+	require.Len(t, values, 2)
+	assert.Equal(t, []byte{0x00, 0xff, 0x10}, values[0].Data)
+	assert.Nil(t, values[0].Note)
+	assert.Nil(t, values[1].Data)
+	require.NotNil(t, values[1].Note)
+	assert.Equal(t, "note", *values[1].Note)
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	db := newTestSnapshotDB(t)
+	dir := t.TempDir()
+
+	// the epoch height must match the expected height
+	_, err := ExportSnapshot(db, t.TempDir(), 99)
+	assert.ErrorIs(t, err, ErrSnapshotHeightMismatch)
+
+	manifest, err := ExportSnapshot(db, dir, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), manifest.BlockHeight)
+	assert.Equal(t, common.HexToHash("0xab").String(), manifest.BlockHash)
+	tables := make(map[string]*SnapshotTable)
+	for _, table := range manifest.Tables {
+		tables[table.Name] = table
+	}
+	require.Contains(t, tables, "snapshot_values")
+	assert.Equal(t, uint64(2), tables["snapshot_values"].Rows)
+	assert.Equal(t, []string{"data"}, tables["snapshot_values"].BinaryColumns)
+	assert.Contains(t, tables[(&Epoch{}).TableName()].BinaryColumns, "block_hash")
+	_, err = ExportSnapshot(db, dir, 0)
+	assert.Error(t, err)
+
+	_, err = VerifySnapshot(dir)
+	require.NoError(t, err)
+	target := bsdbtest.NewDB(t)
+	_, err = ImportSnapshot(target, dir, false)
+	require.NoError(t, err)
+	assertSnapshotImported(t, target)
+
+	// the existing tables are only replaced with overwrite
+	_, err = ImportSnapshot(target, dir, false)
+	assert.ErrorIs(t, err, ErrSnapshotTableExists)
+	_, err = ImportSnapshot(target, dir, true)
+	require.NoError(t, err)
+	assertSnapshotImported(t, target)
+}
+
+func TestImportSnapshotCorrupted(t *testing.T) {
+	db := newTestSnapshotDB(t)
+	dir := t.TempDir()
+	manifest, err := ExportSnapshot(db, dir, 0)
+	require.NoError(t, err)
+	target := bsdbtest.NewDB(t)
+	_, err = ImportSnapshot(target, dir, false)
+	require.NoError(t, err)
+
+	t.Run("tampered table file", func(t *testing.T) {
+		tampered := copySnapshot(t, dir)
+		file := filepath.Join(tampered, manifest.Tables[0].File)
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xff
+		require.NoError(t, os.WriteFile(file, data, 0o644))
+		_, err = VerifySnapshot(tampered)
+		assert.ErrorIs(t, err, ErrSnapshotChecksum)
+		_, err = ImportSnapshot(target, tampered, true)
+		assert.ErrorIs(t, err, ErrSnapshotChecksum)
+		assertSnapshotImported(t, target)
+	})
+
+	t.Run("tampered manifest", func(t *testing.T) {
+		tampered := copySnapshot(t, dir)
+		changed := *manifest
+		changed.BlockHeight++
+		data, err := json.Marshal(&changed)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(tampered, SnapshotManifestFile), data, 0o644))
+		_, err = ImportSnapshot(target, tampered, true)
+		assert.ErrorIs(t, err, ErrSnapshotChecksum)
+	})
+
+	// resign returns the copy of the snapshot whose manifest is changed and signed again
+	resign := func(t *testing.T, change func(*SnapshotManifest)) string {
+		tampered := copySnapshot(t, dir)
+		changed, err := ReadSnapshotManifest(tampered)
+		require.NoError(t, err)
+		change(changed)
+		changed.Checksum, err = changed.computeChecksum()
+		require.NoError(t, err)
+		data, err := json.Marshal(changed)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(tampered, SnapshotManifestFile), data, 0o644))
+		_, err = VerifySnapshot(tampered)
+		require.NoError(t, err)
+		return tampered
+	}
+
+	t.Run("wrong row count", func(t *testing.T) {
+		tampered := resign(t, func(m *SnapshotManifest) { m.Tables[len(m.Tables)-1].Rows++ })
+		_, err = ImportSnapshot(target, tampered, true)
+		assert.ErrorIs(t, err, ErrSnapshotChecksum)
+		// no table is dropped before the snapshot is decoded
+		assertSnapshotImported(t, target)
+	})
+
+	t.Run("failed ddl", func(t *testing.T) {
+		tampered := resign(t, func(m *SnapshotManifest) {
+			table := m.Tables[len(m.Tables)-1]
+			table.DDL = []string{"CREATE TABLE " + table.Name + " ("}
+		})
+		_, err = ImportSnapshot(target, tampered, true)
+		assert.Error(t, err)
+		// the dropped tables are restored by the rollback
+		assertSnapshotImported(t, target)
+	})
+
+	t.Run("untrusted ddl", func(t *testing.T) {
+		for _, ddl := range []string{
+			"DROP TABLE objects",
+			"CREATE TABLE snapshot_values (id INTEGER); DROP TABLE objects",
+			"CREATE TABLE objects (id INTEGER)",
+			"CREATE INDEX idx_id ON objects (id)",
+			"CREATE TABLE snapshot_values (id INTEGER) AS SELECT * FROM objects",
+		} {
+			tampered := resign(t, func(m *SnapshotManifest) {
+				for _, table := range m.Tables {
+					if table.Name == "snapshot_values" {
+						table.DDL = []string{ddl}
+					}
+				}
+			})
+			_, err = ImportSnapshot(target, tampered, true)
+			assert.ErrorIs(t, err, ErrSnapshotIncompatible, ddl)
+			assertSnapshotImported(t, target)
+		}
+	})
+}
+
+func TestSnapshotValueEncoding(t *testing.T) {
+	value := func(s *string) string {
+		require.NotNil(t, s)
+		return *s
+	}
+	assert.Nil(t, encodeSnapshotValue(nil, false))
+	assert.Equal(t, "AP8Q", value(encodeSnapshotValue([]byte{0x00, 0xff, 0x10}, true)))
+	assert.Equal(t, "text", value(encodeSnapshotValue([]byte("text"), false)))
+	assert.Equal(t, "-1", value(encodeSnapshotValue(int64(-1), false)))
+	assert.Equal(t, "1.5", value(encodeSnapshotValue(1.5, false)))
+	assert.Equal(t, "1", value(encodeSnapshotValue(true, false)))
+	assert.Equal(t, "0", value(encodeSnapshotValue(false, false)))
+}
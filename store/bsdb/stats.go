@@ -0,0 +1,175 @@
+package bsdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/forbole/juno/v4/common"
+	"gorm.io/gorm"
+)
+
+// GetBucketStats get the storage stats of a bucket, it returns the zero stats if the bucket has no object
+func (b *BsDBImpl) GetBucketStats(bucketID common.Hash) (*BucketStats, error) {
+	stats := &BucketStats{}
+	err := b.db.Table((&BucketStats{}).TableName()).
+		Where("bucket_id = ?", bucketID).
+		Take(stats).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &BucketStats{BucketID: bucketID}, nil
+	}
+	return stats, err
+}
+
+// GetAccountStats get the storage stats of the objects owned by an account, it returns the zero stats
+// if the account owns no object
+func (b *BsDBImpl) GetAccountStats(owner common.Address) (*AccountStats, error) {
+	stats := &AccountStats{}
+	err := b.db.Table((&AccountStats{}).TableName()).
+		Where("owner = ?", owner).
+		Take(stats).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &AccountStats{Owner: owner}, nil
+	}
+	return stats, err
+}
+
+// StatsMismatch defines the counted stats that differ from the stats recomputed from the objects
+type StatsMismatch struct {
+	// Kind is bucket or account
+	Kind string
+	// Key is the bucket id or the account address
+	Key        string
+	Name       string
+	Counted    StorageStats
+	Recomputed StorageStats
+}
+
+// recomputedStats is the stats aggregated from the objects table
+type recomputedStats struct {
+	Key  []byte `gorm:"column:stats_key"`
+	Name string `gorm:"column:stats_name"`
+	StorageStats
+}
+
+// ReconcileStorageStats recomputes the storage stats from the objects that are not removed and
+// compares them with the stats counted by the block syncer. If fix is true, the counted stats
+// and the object states are replaced by the recomputed ones in one transaction, the object
+// events indexed during the transaction wait for it and are counted on the fixed stats.
+func ReconcileStorageStats(db *gorm.DB, fix bool) ([]*StatsMismatch, error) {
+	var mismatches []*StatsMismatch
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var epoch Epoch
+		if err := tx.Table((&Epoch{}).TableName()).Find(&epoch).Error; err != nil {
+			return err
+		}
+		bucketStats, err := recomputeStats(tx, "bucket_id", "MAX(bucket_name)")
+		if err != nil {
+			return err
+		}
+		accountStats, err := recomputeStats(tx, "owner", "''")
+		if err != nil {
+			return err
+		}
+
+		var countedBuckets []*BucketStats
+		if err = tx.Find(&countedBuckets).Error; err != nil {
+			return err
+		}
+		counted := make(map[string]*recomputedStats, len(countedBuckets))
+		for _, stats := range countedBuckets {
+			counted[string(stats.BucketID.Bytes())] = &recomputedStats{Name: stats.BucketName, StorageStats: stats.StorageStats}
+		}
+		mismatches = append(mismatches, compareStats("bucket", counted, bucketStats, func(key []byte) string {
+			return common.BytesToHash(key).String()
+		})...)
+		var countedAccounts []*AccountStats
+		if err = tx.Find(&countedAccounts).Error; err != nil {
+			return err
+		}
+		counted = make(map[string]*recomputedStats, len(countedAccounts))
+		for _, stats := range countedAccounts {
+			counted[string(stats.Owner.Bytes())] = &recomputedStats{StorageStats: stats.StorageStats}
+		}
+		mismatches = append(mismatches, compareStats("account", counted, accountStats, func(key []byte) string {
+			return common.BytesToAddress(key).String()
+		})...)
+		if !fix || len(mismatches) == 0 {
+			return nil
+		}
+
+		for _, table := range []interface{}{&ObjectStats{}, &BucketStats{}, &AccountStats{}} {
+			if err = tx.Where("1 = 1").Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		if err = tx.Exec(fmt.Sprintf("INSERT INTO %s (object_id, bucket_id, bucket_name, owner, payload_size, status) "+
+			"SELECT object_id, bucket_id, bucket_name, owner, payload_size, status FROM %s WHERE removed = false",
+			ObjectStatsTableName, ObjectTableName)).Error; err != nil {
+			return err
+		}
+		buckets := make([]*BucketStats, 0, len(bucketStats))
+		for _, stats := range bucketStats {
+			buckets = append(buckets, &BucketStats{BucketID: common.BytesToHash(stats.Key), BucketName: stats.Name,
+				StorageStats: stats.StorageStats, UpdateAt: epoch.BlockHeight})
+		}
+		accounts := make([]*AccountStats, 0, len(accountStats))
+		for _, stats := range accountStats {
+			accounts = append(accounts, &AccountStats{Owner: common.BytesToAddress(stats.Key),
+				StorageStats: stats.StorageStats, UpdateAt: epoch.BlockHeight})
+		}
+		if len(buckets) > 0 {
+			if err = tx.CreateInBatches(buckets, 1000).Error; err != nil {
+				return err
+			}
+		}
+		if len(accounts) > 0 {
+			if err = tx.CreateInBatches(accounts, 1000).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return mismatches, err
+}
+
+// recomputeStats aggregates the objects that are not removed by the group column.
+func recomputeStats(db *gorm.DB, groupColumn, nameExpr string) ([]*recomputedStats, error) {
+	var stats []*recomputedStats
+	err := db.Table((&Object{}).TableName()).
+		Select(fmt.Sprintf("%s AS stats_key, %s AS stats_name, COUNT(*) AS object_count, "+
+			"COALESCE(SUM(payload_size), 0) AS payload_size, "+
+			"COALESCE(SUM(CASE WHEN status = '%s' THEN 1 ELSE 0 END), 0) AS sealed_count, "+
+			"COALESCE(SUM(CASE WHEN status = '%s' THEN 1 ELSE 0 END), 0) AS unsealed_count",
+			groupColumn, nameExpr, ObjectStatusSealed, ObjectStatusCreated)).
+		Where("removed = false").
+		Group(groupColumn).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// compareStats returns the mismatches between the counted and the recomputed stats, the
+// missing counted stats are zero.
+func compareStats(kind string, counted map[string]*recomputedStats, recomputed []*recomputedStats,
+	keyString func([]byte) string) []*StatsMismatch {
+	var mismatches []*StatsMismatch
+	for _, stats := range recomputed {
+		countedStats, ok := counted[string(stats.Key)]
+		delete(counted, string(stats.Key))
+		if ok && countedStats.StorageStats == stats.StorageStats {
+			continue
+		}
+		mismatch := &StatsMismatch{Kind: kind, Key: keyString(stats.Key), Name: stats.Name, Recomputed: stats.StorageStats}
+		if ok {
+			mismatch.Counted = countedStats.StorageStats
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	for key, stats := range counted {
+		if stats.StorageStats == (StorageStats{}) {
+			continue
+		}
+		mismatches = append(mismatches, &StatsMismatch{Kind: kind, Key: keyString([]byte(key)), Name: stats.Name,
+			Counted: stats.StorageStats})
+	}
+	return mismatches
+}
@@ -0,0 +1,89 @@
+package bsdb
+
+import (
+	"github.com/forbole/juno/v4/common"
+)
+
+// StorageStats defines the counters of the objects that are not removed
+type StorageStats struct {
+	// ObjectCount defines the number of objects
+	ObjectCount int64 `gorm:"column:object_count"`
+	// PayloadSize defines the total payload size of objects
+	PayloadSize int64 `gorm:"column:payload_size"`
+	// SealedCount defines the number of sealed objects
+	SealedCount int64 `gorm:"column:sealed_count"`
+	// UnsealedCount defines the number of created objects that are not sealed yet
+	UnsealedCount int64 `gorm:"column:unsealed_count"`
+}
+
+// Add adds the other counters to the stats, the sign is 1 to add or -1 to subtract
+func (s *StorageStats) Add(other StorageStats, sign int64) {
+	s.ObjectCount += sign * other.ObjectCount
+	s.PayloadSize += sign * other.PayloadSize
+	s.SealedCount += sign * other.SealedCount
+	s.UnsealedCount += sign * other.UnsealedCount
+}
+
+// BucketStats is the storage stats of bucket, it is maintained by the block syncer
+type BucketStats struct {
+	// BucketID is the unique identification for bucket
+	BucketID common.Hash `gorm:"column:bucket_id;type:BINARY(32);primaryKey"`
+	// BucketName is the name of bucket
+	BucketName   string `gorm:"column:bucket_name;type:varchar(64)"`
+	StorageStats `gorm:"embedded"`
+	// UpdateAt defines the block number when the stats updated
+	UpdateAt int64 `gorm:"column:update_at"`
+}
+
+// TableName is used to set BucketStats table name in database
+func (*BucketStats) TableName() string {
+	return BucketStatsTableName
+}
+
+// AccountStats is the storage stats of the objects owned by account, it is maintained by the block syncer
+type AccountStats struct {
+	// Owner is the account address of objects owner
+	Owner        common.Address `gorm:"column:owner;type:BINARY(20);primaryKey"`
+	StorageStats `gorm:"embedded"`
+	// UpdateAt defines the block number when the stats updated
+	UpdateAt int64 `gorm:"column:update_at"`
+}
+
+// TableName is used to set AccountStats table name in database
+func (*AccountStats) TableName() string {
+	return AccountStatsTableName
+}
+
+// ObjectStats is the state of object that is counted in the stats, the stats are updated by
+// the difference of the state, so the replayed events are not counted twice
+type ObjectStats struct {
+	// ObjectID is the unique identifier of object
+	ObjectID common.Hash `gorm:"column:object_id;type:BINARY(32);primaryKey"`
+	// BucketID is the unique identifier of bucket
+	BucketID common.Hash `gorm:"column:bucket_id;type:BINARY(32)"`
+	// BucketName is the name of the bucket
+	BucketName string `gorm:"column:bucket_name;type:varchar(64)"`
+	// Owner defines the account address of object owner
+	Owner common.Address `gorm:"column:owner;type:BINARY(20)"`
+	// PayloadSize is the total size of the object payload
+	PayloadSize uint64 `gorm:"column:payload_size"`
+	// ObjectStatus defines the upload status of the object
+	ObjectStatus string `gorm:"column:status;type:varchar(64)"`
+}
+
+// TableName is used to set ObjectStats table name in database
+func (*ObjectStats) TableName() string {
+	return ObjectStatsTableName
+}
+
+// Stats returns the counters of the object
+func (o *ObjectStats) Stats() StorageStats {
+	stats := StorageStats{ObjectCount: 1, PayloadSize: int64(o.PayloadSize)}
+	switch o.ObjectStatus {
+	case ObjectStatusSealed:
+		stats.SealedCount = 1
+	case ObjectStatusCreated:
+		stats.UnsealedCount = 1
+	}
+	return stats
+}
@@ -0,0 +1,76 @@
+package bsdb
+
+import (
+	"testing"
+
+	"github.com/forbole/juno/v4/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/bsdb/bsdbtest"
+)
+
+func newTestBsDB(t *testing.T) *gorm.DB {
+	return bsdbtest.NewDB(t, &Object{}, &Epoch{}, &ObjectStats{}, &BucketStats{}, &AccountStats{})
+}
+
+func TestReconcileStorageStats(t *testing.T) {
+	db := newTestBsDB(t)
+	bsDB := &BsDBImpl{db: db}
+	owner, other := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	bucket1, bucket2, stale := common.HexToHash("0x11"), common.HexToHash("0x12"), common.HexToHash("0x13")
+	objects := []*Object{
+		{ObjectID: common.HexToHash("0x21"), BucketID: bucket1, BucketName: "bucket1", Owner: owner, PayloadSize: 10, ObjectStatus: ObjectStatusSealed},
+		{ObjectID: common.HexToHash("0x22"), BucketID: bucket1, BucketName: "bucket1", Owner: other, PayloadSize: 5, ObjectStatus: ObjectStatusCreated},
+		{ObjectID: common.HexToHash("0x23"), BucketID: bucket2, BucketName: "bucket2", Owner: owner, PayloadSize: 7, ObjectStatus: ObjectStatusSealed},
+		{ObjectID: common.HexToHash("0x24"), BucketID: bucket2, BucketName: "bucket2", Owner: owner, PayloadSize: 100, ObjectStatus: ObjectStatusSealed, Removed: true},
+	}
+	require.NoError(t, db.Create(objects).Error)
+	require.NoError(t, db.Create(&Epoch{OneRowID: true, BlockHeight: 100}).Error)
+	// bucket2 is counted correctly, bucket1 misses an object and the stale bucket has no object
+	require.NoError(t, db.Create([]*BucketStats{
+		{BucketID: bucket1, BucketName: "bucket1", StorageStats: StorageStats{ObjectCount: 1, PayloadSize: 10, SealedCount: 1}},
+		{BucketID: bucket2, BucketName: "bucket2", StorageStats: StorageStats{ObjectCount: 1, PayloadSize: 7, SealedCount: 1}},
+		{BucketID: stale, BucketName: "stale", StorageStats: StorageStats{ObjectCount: 1, PayloadSize: 1, SealedCount: 1}},
+	}).Error)
+
+	mismatches, err := ReconcileStorageStats(db, false)
+	require.NoError(t, err)
+	keys := make(map[string]*StatsMismatch)
+	for _, m := range mismatches {
+		keys[m.Kind+"/"+m.Key] = m
+	}
+	assert.Len(t, keys, 4)
+	require.Contains(t, keys, "bucket/"+bucket1.String())
+	assert.Equal(t, StorageStats{ObjectCount: 2, PayloadSize: 15, SealedCount: 1, UnsealedCount: 1},
+		keys["bucket/"+bucket1.String()].Recomputed)
+	require.Contains(t, keys, "bucket/"+stale.String())
+	assert.Equal(t, StorageStats{}, keys["bucket/"+stale.String()].Recomputed)
+	assert.Contains(t, keys, "account/"+owner.String())
+	assert.Contains(t, keys, "account/"+other.String())
+	// the stats are not changed without fix
+	stats, err := bsDB.GetBucketStats(bucket1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.ObjectCount)
+
+	_, err = ReconcileStorageStats(db, true)
+	require.NoError(t, err)
+	stats, err = bsDB.GetBucketStats(bucket1)
+	require.NoError(t, err)
+	assert.Equal(t, StorageStats{ObjectCount: 2, PayloadSize: 15, SealedCount: 1, UnsealedCount: 1}, stats.StorageStats)
+	assert.Equal(t, int64(100), stats.UpdateAt)
+	stats, err = bsDB.GetBucketStats(stale)
+	require.NoError(t, err)
+	assert.Equal(t, StorageStats{}, stats.StorageStats)
+	account, err := bsDB.GetAccountStats(owner)
+	require.NoError(t, err)
+	assert.Equal(t, StorageStats{ObjectCount: 2, PayloadSize: 17, SealedCount: 2}, account.StorageStats)
+	var counted int64
+	require.NoError(t, db.Model(&ObjectStats{}).Count(&counted).Error)
+	assert.Equal(t, int64(3), counted)
+
+	mismatches, err = ReconcileStorageStats(db, false)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
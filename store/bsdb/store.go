@@ -8,7 +8,9 @@ import (
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
 )
 
 var _ BSDB = &BsDBImpl{}
@@ -34,8 +36,28 @@ func NewBsDB(cfg *gfspconfig.GfSpConfig, isBackup bool) (*BsDBImpl, error) {
 	return &BsDBImpl{db: db}, nil
 }
 
-// InitDB init a block syncer db instance
+// InitDB init a block syncer db instance, it refuses the schema that is migrated by the
+// newer binary.
 func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
+	db, err := OpenDB(config)
+	if err != nil {
+		return nil, err
+	}
+	// the block syncer applies the migrations, the reader only refuses the newer schema
+	migrator, err := migrate.NewMigrator(db, MigrationDBName, Migrations())
+	if err != nil {
+		log.Errorw("failed to new bsdb migrator", "error", err)
+		return nil, err
+	}
+	if err = migrator.Check(); err != nil {
+		log.Errorw("failed to check bsdb schema version", "error", err)
+		return nil, err
+	}
+	return db, nil
+}
+
+// OpenDB opens the block syncer db instance without checking the schema.
+func OpenDB(config *config.SQLDBConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		config.User, config.Passwd, config.Address, config.Database)
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
@@ -43,6 +65,9 @@ func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
 		log.Errorw("gorm failed to open db", "error", err)
 		return nil, err
 	}
-
+	if err = db.Use(tracing.NewGormPlugin("bsdb")); err != nil {
+		log.Errorw("gorm failed to use tracing plugin", "error", err)
+		return nil, err
+	}
 	return db, nil
 }
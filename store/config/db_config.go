@@ -2,6 +2,8 @@ package config
 
 // SQLDBConfig is sql db config
 type SQLDBConfig struct {
+	// Driver defines the driver of spdb, mysql, postgres or sqlite, default mysql.
+	Driver          string
 	User            string
 	Passwd          string
 	Address         string
@@ -10,4 +12,12 @@ type SQLDBConfig struct {
 	ConnMaxIdleTime int
 	MaxIdleConns    int
 	MaxOpenConns    int
+	// SkipMigrate defines whether to skip applying the pending schema migrations at startup,
+	// the db.migrate command applies them, and the outdated schema is refused. Only spdb
+	// supports it, bsdb is migrated by the block syncer.
+	SkipMigrate bool
+	// ReadRecordRetentionMonths defines how many months of the read records are kept before
+	// the current month, the monthly shards older than it are dropped and the daily rollups
+	// are kept. Zero keeps all the read records. Only spdb supports it.
+	ReadRecordRetentionMonths int
 }
@@ -0,0 +1,407 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// DefaultLockTimeout defines the default time to wait for the migration lock.
+	DefaultLockTimeout = 5 * time.Minute
+	// lockExpiration defines the time after which the lock of the crashed process is stale,
+	// the holder refreshes the lock every lockRefreshInterval while the migrations run.
+	lockExpiration = 10 * time.Minute
+	// lockRefreshInterval defines the interval to refresh the lock held by the running migrations.
+	lockRefreshInterval = time.Minute
+	// lockRetryInterval defines the interval to retry acquiring the lock.
+	lockRetryInterval = time.Second
+)
+
+var (
+	// ErrSchemaTooNew defines the schema is migrated by the newer binary, the binary must
+	// not run against it.
+	ErrSchemaTooNew = errors.New("schema version is newer than the binary")
+	// ErrSchemaOutdated defines the schema has pending migrations that are not applied.
+	ErrSchemaOutdated = errors.New("schema version is older than the binary, run db.migrate up")
+	// ErrLockTimeout defines the migration lock is held by others until the timeout.
+	ErrLockTimeout = errors.New("timeout to acquire the migration lock")
+)
+
+// Migration defines one version of the schema. The Up migrates the schema from the
+// previous version to this version, and the Down reverts it. Both must be idempotent,
+// because the migration that fails after the DDL is committed is applied again.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+	// NoTransaction runs the Up and Down without the transaction, it is set by the migration
+	// that moves the large table by batches and commits every batch by itself.
+	NoTransaction bool
+}
+
+// MigrationStatus defines the state of the migration in the database.
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Dirty is true if the migration is partially applied or reverted, it is not applied.
+	Dirty bool
+	// Unknown is true if the migration is applied by the newer binary.
+	Unknown bool
+}
+
+// Migrator applies the ordered migrations to the database, the migrations of different
+// databases are recorded by the database name, so they can share the physical database.
+type Migrator struct {
+	db          *gorm.DB
+	name        string
+	migrations  []*Migration
+	owner       string
+	lockTimeout time.Duration
+}
+
+// NewMigrator returns the migrator of the database, the migration tables are created when
+// the first migration is applied, so checking the version does not need the DDL privilege.
+func NewMigrator(db *gorm.DB, name string, migrations []*Migration) (*Migrator, error) {
+	for i, migration := range migrations {
+		if migration.Up == nil || migration.Down == nil {
+			return nil, fmt.Errorf("migration %d of %s misses up or down", migration.Version, name)
+		}
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			return nil, fmt.Errorf("migration %d of %s is out of order", migration.Version, name)
+		}
+	}
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		db:          db,
+		name:        name,
+		migrations:  migrations,
+		owner:       fmt.Sprintf("%s/%d", hostname, os.Getpid()),
+		lockTimeout: DefaultLockTimeout,
+	}, nil
+}
+
+// Latest returns the version of the last migration known by the binary.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version of the last applied migration, it is zero if no migration is
+// applied. The dirty migrations are not applied.
+func (m *Migrator) Version() (uint64, error) {
+	applied, err := m.records()
+	if err != nil {
+		return 0, err
+	}
+	var version uint64
+	for _, record := range applied {
+		if !record.Dirty && record.Version > version {
+			version = record.Version
+		}
+	}
+	return version, nil
+}
+
+// records returns the migration records of the database, the dirty flag is false if the
+// records are written before the flag is added.
+func (m *Migrator) records() ([]SchemaMigrationTable, error) {
+	var applied []SchemaMigrationTable
+	if !m.db.Migrator().HasTable(&SchemaMigrationTable{}) {
+		return applied, nil
+	}
+	if err := m.db.Where("db_name = ?", m.name).Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// Status returns the known migrations and the applied migrations that are unknown, in the
+// order of the version.
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+	appliedMap := make(map[uint64]SchemaMigrationTable, len(applied))
+	for _, record := range applied {
+		appliedMap[record.Version] = record
+	}
+	status := make([]*MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := appliedMap[migration.Version]; ok {
+			s.Applied, s.Dirty = !record.Dirty, record.Dirty
+			s.AppliedAt = time.Unix(record.AppliedTimestampSecond, 0)
+			delete(appliedMap, migration.Version)
+		}
+		status = append(status, s)
+	}
+	for _, record := range appliedMap {
+		status = append(status, &MigrationStatus{Version: record.Version, Name: record.Name, Applied: !record.Dirty,
+			AppliedAt: time.Unix(record.AppliedTimestampSecond, 0), Dirty: record.Dirty, Unknown: true})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Version < status[j].Version })
+	return status, nil
+}
+
+// Check returns ErrSchemaTooNew if the schema is migrated by the newer binary.
+func (m *Migrator) Check() error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("%w: %s version %d, binary version %d", ErrSchemaTooNew, m.name, version, m.Latest())
+	}
+	return nil
+}
+
+// Startup is called when the service starts, it refuses the newer schema, and applies the
+// pending migrations if apply is true, otherwise it refuses the outdated schema.
+func (m *Migrator) Startup(apply bool) error {
+	if err := m.Check(); err != nil {
+		return err
+	}
+	if apply {
+		_, err := m.Up(0, false)
+		return err
+	}
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version < m.Latest() {
+		return fmt.Errorf("%w: %s version %d, binary version %d", ErrSchemaOutdated, m.name, version, m.Latest())
+	}
+	return nil
+}
+
+// Up applies the pending and the dirty migrations until the target version, zero target means
+// the latest version. It returns the migrations that are applied, or to be applied if dryRun.
+func (m *Migrator) Up(target uint64, dryRun bool) ([]*Migration, error) {
+	if target == 0 {
+		target = m.Latest()
+	}
+	return m.run(dryRun, func(status []*MigrationStatus) ([]*Migration, error) {
+		var plan []*Migration
+		for _, s := range status {
+			if s.Unknown {
+				return nil, fmt.Errorf("%w: %s migration %d is unknown", ErrSchemaTooNew, m.name, s.Version)
+			}
+			if !s.Applied && s.Version <= target {
+				plan = append(plan, m.find(s.Version))
+			}
+		}
+		return plan, nil
+	}, true)
+}
+
+// Down reverts the applied and the dirty migrations whose version is greater than the target.
+// It returns the migrations that are reverted, or to be reverted if dryRun.
+func (m *Migrator) Down(target uint64, dryRun bool) ([]*Migration, error) {
+	return m.run(dryRun, func(status []*MigrationStatus) ([]*Migration, error) {
+		var plan []*Migration
+		for i := len(status) - 1; i >= 0; i-- {
+			s := status[i]
+			if (!s.Applied && !s.Dirty) || s.Version <= target {
+				continue
+			}
+			if s.Unknown {
+				return nil, fmt.Errorf("%w: %s migration %d is unknown", ErrSchemaTooNew, m.name, s.Version)
+			}
+			plan = append(plan, m.find(s.Version))
+		}
+		return plan, nil
+	}, false)
+}
+
+func (m *Migrator) find(version uint64) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+// run plans the migrations under the lock and applies them one by one. The record of the
+// migration is marked dirty before the migration runs, and is cleaned in the transaction
+// of the migration. The DDL of mysql commits the transaction implicitly, so the failed
+// migration may be partially applied, its record stays dirty and the migration is applied
+// again by the next run.
+func (m *Migrator) run(dryRun bool, planFn func([]*MigrationStatus) ([]*Migration, error), up bool) ([]*Migration, error) {
+	if !dryRun {
+		if err := m.createTable(&SchemaMigrationLockTable{}); err != nil {
+			return nil, fmt.Errorf("failed to create migration lock table: %w", err)
+		}
+		if err := m.lock(); err != nil {
+			return nil, err
+		}
+		defer m.unlock()
+		defer m.keepLock()()
+		if err := m.db.AutoMigrate(&SchemaMigrationTable{}); err != nil {
+			return nil, fmt.Errorf("failed to create migration table: %w", err)
+		}
+	}
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planFn(status)
+	if err != nil || dryRun {
+		return plan, err
+	}
+	for i, migration := range plan {
+		if err = m.refreshLock(); err != nil {
+			return plan[:i], err
+		}
+		startTime := time.Now()
+		if err = m.markDirty(migration); err != nil {
+			return plan[:i], err
+		}
+		apply := func(tx *gorm.DB) error {
+			if up {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Model(&SchemaMigrationTable{}).Where("db_name = ? and version = ?", m.name, migration.Version).
+					Updates(map[string]interface{}{"dirty": false, "applied_timestamp_second": time.Now().Unix()}).Error
+			}
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Where("db_name = ? and version = ?", m.name, migration.Version).
+				Delete(&SchemaMigrationTable{}).Error
+		}
+		if migration.NoTransaction {
+			err = apply(m.db)
+		} else {
+			err = m.db.Transaction(apply)
+		}
+		if err != nil {
+			log.Errorw("failed to migrate, the migration is dirty and is applied again by the next run", "db", m.name,
+				"version", migration.Version, "name", migration.Name, "up", up, "error", err)
+			return plan[:i], fmt.Errorf("failed to migrate %s version %d %s: %w", m.name, migration.Version, migration.Name, err)
+		}
+		log.Infow("succeed to migrate", "db", m.name, "version", migration.Version, "name", migration.Name,
+			"up", up, "cost", time.Since(startTime))
+	}
+	return plan, nil
+}
+
+// markDirty records the migration as dirty before it is applied or reverted.
+func (m *Migrator) markDirty(migration *Migration) error {
+	err := m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "db_name"}, {Name: "version"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"dirty": true}),
+	}).Create(&SchemaMigrationTable{
+		DBName:                 m.name,
+		Version:                migration.Version,
+		Name:                   migration.Name,
+		AppliedTimestampSecond: time.Now().Unix(),
+		Dirty:                  true,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark %s migration %d dirty: %w", m.name, migration.Version, err)
+	}
+	return nil
+}
+
+// createTable creates the table if it does not exist, the table that is created by the
+// concurrent startup in the meantime is not an error.
+func (m *Migrator) createTable(table interface{}) error {
+	migrator := m.db.Migrator()
+	if migrator.HasTable(table) {
+		return nil
+	}
+	if err := migrator.CreateTable(table); err != nil && !migrator.HasTable(table) {
+		return err
+	}
+	return nil
+}
+
+// lock acquires the migration lock of the database, the concurrent startups wait until the
+// holder finishes. The lock that is not refreshed for a long time is stale and is taken over.
+func (m *Migrator) lock() error {
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		now := time.Now().Unix()
+		result := m.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&SchemaMigrationLockTable{
+			DBName:                m.name,
+			Owner:                 m.owner,
+			LockedTimestampSecond: now,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to acquire the migration lock: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+		holder := &SchemaMigrationLockTable{}
+		if err := m.db.Where("db_name = ?", m.name).First(holder).Error; err != nil &&
+			!errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if holder.LockedTimestampSecond != 0 && now-holder.LockedTimestampSecond > int64(lockExpiration.Seconds()) {
+			log.Warnw("take over the stale migration lock", "db", m.name, "holder", holder.Owner)
+			m.db.Where("db_name = ? and locked_timestamp_second = ?", m.name, holder.LockedTimestampSecond).
+				Delete(&SchemaMigrationLockTable{})
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s is locked by %s", ErrLockTimeout, m.name, holder.Owner)
+		}
+		log.Infow("wait for the migration lock", "db", m.name, "holder", holder.Owner)
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// keepLock refreshes the lock in the background while the migrations run, so the long
+// migration is not taken as the crashed one. It returns the function to stop refreshing.
+func (m *Migrator) keepLock() func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.refreshLock(); err != nil {
+					log.Errorw("failed to refresh the migration lock", "db", m.name, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (m *Migrator) refreshLock() error {
+	return m.db.Model(&SchemaMigrationLockTable{}).Where("db_name = ? and owner = ?", m.name, m.owner).
+		Update("locked_timestamp_second", time.Now().Unix()).Error
+}
+
+func (m *Migrator) unlock() {
+	if err := m.db.Where("db_name = ? and owner = ?", m.name, m.owner).Delete(&SchemaMigrationLockTable{}).Error; err != nil {
+		log.Errorw("failed to release the migration lock", "db", m.name, "error", err)
+	}
+}
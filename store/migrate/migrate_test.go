@@ -0,0 +1,130 @@
+package migrate
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type testTable struct {
+	ID   uint64 `gorm:"primary_key"`
+	Name string
+}
+
+func testMigrations() []*Migration {
+	return []*Migration{
+		{
+			Version: 1,
+			Name:    "create_test",
+			Up:      func(db *gorm.DB) error { return db.AutoMigrate(&testTable{}) },
+			Down:    func(db *gorm.DB) error { return db.Migrator().DropTable(&testTable{}) },
+		},
+		{
+			Version: 2,
+			Name:    "backfill_test",
+			Up:      func(db *gorm.DB) error { return db.Create(&testTable{ID: 1, Name: "backfill"}).Error },
+			Down:    func(db *gorm.DB) error { return db.Delete(&testTable{ID: 1}).Error },
+		},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	migrator, err := NewMigrator(db, "test", testMigrations())
+	require.NoError(t, err)
+	assert.True(t, errors.Is(migrator.Startup(false), ErrSchemaOutdated))
+
+	plan, err := migrator.Up(0, true)
+	require.NoError(t, err)
+	assert.Len(t, plan, 2)
+	assert.False(t, db.Migrator().HasTable(&testTable{}))
+
+	require.NoError(t, migrator.Startup(true))
+	version, err := migrator.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), version)
+	var count int64
+	require.NoError(t, db.Model(&testTable{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	reverted, err := migrator.Down(1, false)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	require.NoError(t, db.Model(&testTable{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// the older binary refuses the schema migrated by the newer binary
+	require.NoError(t, migrator.Startup(true))
+	older, err := NewMigrator(db, "test", testMigrations()[:1])
+	require.NoError(t, err)
+	assert.True(t, errors.Is(older.Startup(true), ErrSchemaTooNew))
+
+	// the lock held by others blocks the migration until the timeout
+	require.NoError(t, db.Create(&SchemaMigrationLockTable{DBName: "test", Owner: "other",
+		LockedTimestampSecond: time.Now().Unix()}).Error)
+	migrator.lockTimeout = 0
+	_, err = migrator.Down(0, false)
+	assert.True(t, errors.Is(err, ErrLockTimeout))
+}
+
+func TestMigratorDirty(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	failed := true
+	migrations := testMigrations()
+	migrations = append(migrations, &Migration{
+		Version: 3,
+		Name:    "partial_test",
+		// the first row is committed before the failure, as the mysql ddl commits implicitly
+		Up: func(db *gorm.DB) error {
+			if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&testTable{ID: 2, Name: "partial"}).Error; err != nil {
+				return err
+			}
+			if failed {
+				return errors.New("mock error")
+			}
+			return db.Create(&testTable{ID: 3, Name: "partial"}).Error
+		},
+		Down:          func(db *gorm.DB) error { return db.Where("id in ?", []uint64{2, 3}).Delete(&testTable{}).Error },
+		NoTransaction: true,
+	})
+	migrator, err := NewMigrator(db, "test", migrations)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(0, false)
+	require.Error(t, err)
+	assert.Len(t, applied, 2)
+	version, err := migrator.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), version)
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, status, 3)
+	assert.True(t, status[2].Dirty)
+	assert.False(t, status[2].Applied)
+	assert.True(t, errors.Is(migrator.Startup(false), ErrSchemaOutdated))
+
+	// the dirty migration is applied again
+	failed = false
+	applied, err = migrator.Up(0, false)
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+	version, err = migrator.Version()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), version)
+	var count int64
+	require.NoError(t, db.Model(&testTable{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.False(t, status[2].Dirty)
+	assert.True(t, status[2].Applied)
+}
@@ -0,0 +1,35 @@
+package migrate
+
+const (
+	// SchemaMigrationTableName defines the applied migrations table name.
+	SchemaMigrationTableName = "schema_migration"
+	// SchemaMigrationLockTableName defines the migration lock table name.
+	SchemaMigrationLockTableName = "schema_migration_lock"
+)
+
+// SchemaMigrationTable table schema, one record per applied migration. The record is dirty
+// while the migration is being applied or reverted, it stays dirty if the migration fails.
+type SchemaMigrationTable struct {
+	DBName                 string `gorm:"primary_key;size:64"`
+	Version                uint64 `gorm:"primary_key;autoIncrement:false"`
+	Name                   string
+	AppliedTimestampSecond int64
+	Dirty                  bool `gorm:"not null;default:false"`
+}
+
+// TableName is used to set SchemaMigrationTable Schema's table name in database.
+func (SchemaMigrationTable) TableName() string {
+	return SchemaMigrationTableName
+}
+
+// SchemaMigrationLockTable table schema, the record exists while the migration is running.
+type SchemaMigrationLockTable struct {
+	DBName                string `gorm:"primary_key;size:64"`
+	Owner                 string
+	LockedTimestampSecond int64
+}
+
+// TableName is used to set SchemaMigrationLockTable Schema's table name in database.
+func (SchemaMigrationLockTable) TableName() string {
+	return SchemaMigrationLockTableName
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"time"
 
@@ -24,6 +25,11 @@ type PieceStoreAPI interface {
 
 var _ corepiecestore.PieceStore = &StoreClient{}
 
+// stagingPieceKeyPrefix defines the key prefix of the staged pieces in piece store
+const stagingPieceKeyPrefix = storage.StagingKeyPrefix
+
+var ErrStagedPieceSize = errors.New("staged piece size mismatch")
+
 type StoreClient struct {
 	name string
 	ps   *piece.PieceStore
@@ -101,3 +107,100 @@ func (client *StoreClient) DeletePiece(ctx context.Context, key string) error {
 	err = client.ps.Delete(ctx, key)
 	return err
 }
+
+// HeadPiece returns the size of the piece by the piece info.
+func (client *StoreClient) HeadPiece(ctx context.Context, key string) (int64, error) {
+	info, err := client.ps.GetPieceInfo(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// staged returns whether the piece is staged before promoting, it is only staged if the
+// piece store can rename it to the final key, copying it would double the I/O.
+func (client *StoreClient) staged(key string) bool {
+	return client.ps.CanMove(stagingPieceKeyPrefix+key, key)
+}
+
+// StagePiece puts piece to the staging area of piece store, or to the final key if it can
+// not be renamed, and verifies the size of the put piece.
+func (client *StoreClient) StagePiece(ctx context.Context, key string, value []byte) error {
+	stagingKey := stagingPieceKeyPrefix + key
+	if !client.staged(key) {
+		if err := client.PutPiece(ctx, key, value); err != nil {
+			log.CtxErrorw(ctx, "failed to put piece without staging", "piece_key", key, "error", err)
+			return err
+		}
+		stagingKey = key
+	} else if err := client.ps.Put(ctx, stagingKey, bytes.NewReader(value)); err != nil {
+		log.CtxErrorw(ctx, "failed to stage piece", "piece_key", key, "error", err)
+		return err
+	}
+	info, err := client.ps.GetPieceInfo(ctx, stagingKey)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get staged piece info", "piece_key", key, "error", err)
+		return err
+	}
+	if info.Size() != int64(len(value)) {
+		log.CtxErrorw(ctx, "staged piece is truncated", "piece_key", key,
+			"expected_size", len(value), "actual_size", info.Size())
+		return ErrStagedPieceSize
+	}
+	return nil
+}
+
+// PromotePiece renames the staged piece to the final piece key.
+func (client *StoreClient) PromotePiece(ctx context.Context, key string) error {
+	if !client.staged(key) {
+		return nil
+	}
+	var (
+		startTime = time.Now()
+		err       error
+		info      storage.Object
+	)
+	defer func() {
+		metrics.PutPieceTimeHistogram.WithLabelValues(client.name).Observe(
+			time.Since(startTime).Seconds())
+		metrics.PutPieceTotalNumberCounter.WithLabelValues(client.name).Inc()
+		if err == nil {
+			metrics.PieceUsageAmountGauge.WithLabelValues(client.name).Add(float64(info.Size()))
+		}
+	}()
+	stagingKey := stagingPieceKeyPrefix + key
+	if info, err = client.ps.GetPieceInfo(ctx, stagingKey); err != nil {
+		log.CtxErrorw(ctx, "failed to get staged piece info", "piece_key", key, "error", err)
+		return err
+	}
+	if err = client.ps.Move(ctx, stagingKey, key); err != nil {
+		log.CtxErrorw(ctx, "failed to promote staged piece", "piece_key", key, "error", err)
+	}
+	return err
+}
+
+// DeleteStagedPiece deletes the staged piece from piece store, or the piece of the final key
+// if it is put without staging.
+func (client *StoreClient) DeleteStagedPiece(ctx context.Context, key string) error {
+	if !client.staged(key) {
+		return client.DeletePiece(ctx, key)
+	}
+	return client.ps.Delete(ctx, stagingPieceKeyPrefix+key)
+}
+
+// CleanStagedPieces deletes the staged pieces whose modified time is before the time.
+func (client *StoreClient) CleanStagedPieces(ctx context.Context, before time.Time) (int, error) {
+	var cleaned int
+	err := client.ps.Walk(ctx, stagingPieceKeyPrefix, func(obj storage.Object) error {
+		if !obj.ModTime().Before(before) {
+			return nil
+		}
+		if err := client.ps.Delete(ctx, obj.Key()); err != nil {
+			log.CtxErrorw(ctx, "failed to delete stale staged piece", "key", obj.Key(), "error", err)
+			return err
+		}
+		cleaned++
+		return nil
+	})
+	return cleaned, err
+}
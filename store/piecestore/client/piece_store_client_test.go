@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corepiecestore "github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
+	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
+)
+
+func setupStoreClient(t *testing.T) (*StoreClient, string) {
+	root := t.TempDir()
+	client, err := NewStoreClient(&storage.PieceStoreConfig{
+		Shards: 0,
+		Store: storage.ObjectStorageConfig{
+			Storage:   storage.DiskFileStore,
+			BucketURL: root,
+		},
+	})
+	assert.Nil(t, err)
+	return client, root
+}
+
+func TestCommitPiece(t *testing.T) {
+	var errCommit = errors.New("commit error")
+	cases := []struct {
+		name      string
+		commit    func() error
+		wantedErr error
+	}{
+		{
+			name:   "commit_piece_without_meta",
+			commit: nil,
+		},
+		{
+			name:   "commit_piece_with_meta",
+			commit: func() error { return nil },
+		},
+		{
+			name:      "commit_piece_with_meta_error",
+			commit:    func() error { return errCommit },
+			wantedErr: errCommit,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client, root := setupStoreClient(t)
+			err := corepiecestore.CommitPiece(context.TODO(), client, "e1_s0", []byte("piece"), tt.commit)
+			assert.Equal(t, tt.wantedErr, err)
+
+			// the piece is promoted before the meta is committed
+			data, err := client.GetPiece(context.TODO(), "e1_s0", 0, -1)
+			assert.Nil(t, err)
+			assert.Equal(t, []byte("piece"), data)
+			_, err = os.Stat(filepath.Join(root, stagingPieceKeyPrefix, "e1_s0"))
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestCommitPiece_StagedPieceInvisible(t *testing.T) {
+	client, _ := setupStoreClient(t)
+	err := client.StagePiece(context.TODO(), "e1_s0", []byte("piece"))
+	assert.Nil(t, err)
+	_, err = client.GetPiece(context.TODO(), "e1_s0", 0, -1)
+	assert.NotNil(t, err)
+
+	err = client.DeleteStagedPiece(context.TODO(), "e1_s0")
+	assert.Nil(t, err)
+	err = client.PromotePiece(context.TODO(), "e1_s0")
+	assert.NotNil(t, err)
+	_, err = client.GetPiece(context.TODO(), "e1_s0", 0, -1)
+	assert.NotNil(t, err)
+}
+
+func TestCleanStagedPieces(t *testing.T) {
+	client, root := setupStoreClient(t)
+	now := time.Now()
+	for _, key := range []string{"e1_s0", "e1_s1", "e2_s0"} {
+		assert.Nil(t, client.StagePiece(context.TODO(), key, []byte("piece")))
+	}
+	// e1_s0 and e1_s1 are left by the crashed writers an hour ago
+	for _, key := range []string{"e1_s0", "e1_s1"} {
+		stale := now.Add(-time.Hour)
+		assert.Nil(t, os.Chtimes(filepath.Join(root, stagingPieceKeyPrefix, key), stale, stale))
+	}
+	assert.Nil(t, client.PutPiece(context.TODO(), "e3_s0", []byte("piece")))
+	stale := now.Add(-time.Hour)
+	assert.Nil(t, os.Chtimes(filepath.Join(root, "e3_s0"), stale, stale))
+
+	cleaned, err := client.CleanStagedPieces(context.TODO(), now.Add(-time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, cleaned)
+
+	// the recently staged piece and the promoted piece are kept
+	err = client.PromotePiece(context.TODO(), "e2_s0")
+	assert.Nil(t, err)
+	_, err = client.GetPiece(context.TODO(), "e3_s0", 0, -1)
+	assert.Nil(t, err)
+	for _, key := range []string{"e1_s0", "e1_s1"} {
+		assert.NotNil(t, client.PromotePiece(context.TODO(), key))
+	}
+
+	cleaned, err = client.CleanStagedPieces(context.TODO(), now.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, cleaned)
+}
+
+func TestCommitPiece_ShardedStore(t *testing.T) {
+	root := t.TempDir()
+	client, err := NewStoreClient(&storage.PieceStoreConfig{
+		Shards: 4,
+		Store: storage.ObjectStorageConfig{
+			Storage:   storage.DiskFileStore,
+			BucketURL: filepath.Join(root, "shard%d"),
+		},
+	})
+	assert.Nil(t, err)
+	// the staged pieces are on the shards of their final keys, so they are renamed
+	for _, key := range []string{"e1_s0", "e1_s1", "e2_s0", "e2_s1", "e3_s0"} {
+		assert.Nil(t, client.StagePiece(context.TODO(), key, []byte("piece")))
+		_, err = client.GetPiece(context.TODO(), key, 0, -1)
+		assert.NotNil(t, err)
+		assert.Nil(t, client.PromotePiece(context.TODO(), key))
+		data, err := client.GetPiece(context.TODO(), key, 0, -1)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("piece"), data)
+	}
+}
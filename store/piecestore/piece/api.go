@@ -4,6 +4,10 @@ import (
 	"context"
 	"io"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
 )
 
@@ -12,21 +16,58 @@ type PieceStore struct {
 }
 
 // Get one piece from PieceStore
-func (p *PieceStore) Get(ctx context.Context, key string, offset, limit int64) (io.ReadCloser, error) {
+func (p *PieceStore) Get(ctx context.Context, key string, offset, limit int64) (rc io.ReadCloser, err error) {
+	ctx, span := startSpan(ctx, "get", key)
+	defer func() { tracing.EndSpan(span, err) }()
 	return p.storeAPI.GetObject(ctx, key, offset, limit)
 }
 
 // Put one piece to PieceStore
-func (p *PieceStore) Put(ctx context.Context, key string, reader io.Reader) error {
+func (p *PieceStore) Put(ctx context.Context, key string, reader io.Reader) (err error) {
+	ctx, span := startSpan(ctx, "put", key)
+	defer func() { tracing.EndSpan(span, err) }()
 	return p.storeAPI.PutObject(ctx, key, reader)
 }
 
 // Delete one piece in PieceStore
-func (p *PieceStore) Delete(ctx context.Context, key string) error {
+func (p *PieceStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, span := startSpan(ctx, "delete", key)
+	defer func() { tracing.EndSpan(span, err) }()
 	return p.storeAPI.DeleteObject(ctx, key)
 }
 
 // GetPieceInfo returns piece info in PieceStore
-func (p *PieceStore) GetPieceInfo(ctx context.Context, key string) (storage.Object, error) {
+func (p *PieceStore) GetPieceInfo(ctx context.Context, key string) (obj storage.Object, err error) {
+	ctx, span := startSpan(ctx, "head", key)
+	defer func() { tracing.EndSpan(span, err) }()
 	return p.storeAPI.HeadObject(ctx, key)
 }
+
+// CanMove returns whether the storage can rename one piece from srcKey to dstKey
+func (p *PieceStore) CanMove(srcKey, dstKey string) bool {
+	mover, ok := p.storeAPI.(storage.ObjectMover)
+	return ok && mover.CanMoveObject(srcKey, dstKey)
+}
+
+// Move renames one piece from srcKey to dstKey in PieceStore, it returns ErrUnsupportedMethod
+// if the storage can not rename it.
+func (p *PieceStore) Move(ctx context.Context, srcKey, dstKey string) (err error) {
+	ctx, span := startSpan(ctx, "move", srcKey)
+	defer func() { tracing.EndSpan(span, err) }()
+	mover, ok := p.storeAPI.(storage.ObjectMover)
+	if !ok {
+		return storage.ErrUnsupportedMethod
+	}
+	return mover.MoveObject(ctx, srcKey, dstKey)
+}
+
+// Walk calls fn for each piece whose key has the prefix in PieceStore
+func (p *PieceStore) Walk(ctx context.Context, prefix string, fn func(storage.Object) error) (err error) {
+	ctx, span := startSpan(ctx, "walk", prefix)
+	defer func() { tracing.EndSpan(span, err) }()
+	return storage.WalkObjects(ctx, p.storeAPI, prefix, fn)
+}
+
+func startSpan(ctx context.Context, op, key string) (context.Context, trace.Span) {
+	return tracing.StartSpan(ctx, "piecestore."+op, trace.WithAttributes(attribute.String("key", key)))
+}
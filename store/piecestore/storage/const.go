@@ -16,6 +16,10 @@ const (
 	MemoryStore = "memory"
 )
 
+// StagingKeyPrefix defines the key prefix of the staged objects, the staged object is put
+// on the shard of its final key so that it can be renamed to the final key.
+const StagingKeyPrefix = "staging/"
+
 // piece store storage config and environment constants
 const (
 	// AKSKIAMType defines IAM type config which uses access key and secret key to access aws s3
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -178,6 +179,92 @@ func (d *diskFileStore) HeadObject(ctx context.Context, key string) (Object, err
 	}, nil
 }
 
+func (d *diskFileStore) CanMoveObject(srcKey, dstKey string) bool {
+	return true
+}
+
+// MoveObject syncs the file of srcKey, renames it to dstKey and syncs the directory of
+// dstKey, so the moved object survives the crash.
+func (d *diskFileStore) MoveObject(ctx context.Context, srcKey, dstKey string) error {
+	if err := syncFile(d.path(srcKey)); err != nil {
+		log.Errorw("failed to move object due to sync file", "error", err)
+		return err
+	}
+	dst := d.path(dstKey)
+	err := os.Rename(d.path(srcKey), dst)
+	if err != nil && os.IsNotExist(err) {
+		if _, statErr := os.Stat(d.path(srcKey)); statErr != nil {
+			return err
+		}
+		if err = os.MkdirAll(filepath.Dir(dst), os.FileMode(0755)); err != nil {
+			log.Errorw("failed to move object due to mkdir", "error", err)
+			return err
+		}
+		err = os.Rename(d.path(srcKey), dst)
+	}
+	if err != nil {
+		log.Errorw("failed to move object due to rename file", "error", err)
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(dst))
+	if err != nil {
+		log.Errorw("failed to move object due to open directory", "error", err)
+		return err
+	}
+	defer dir.Close()
+	if err = dir.Sync(); err != nil && runtime.GOOS != windowsOS {
+		log.Errorw("failed to move object due to sync directory", "error", err)
+		return err
+	}
+	return nil
+}
+
+func syncFile(p string) error {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WalkObjects walks the files under the directory of prefix, the keys of the files are
+// the relative paths to root.
+func (d *diskFileStore) WalkObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	dir := d.path(prefix)
+	if !strings.HasSuffix(prefix, dirSuffix) {
+		dir = filepath.Dir(dir)
+	}
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(d.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(&object{key, info.Size(), info.ModTime(), false})
+	})
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 func (d *diskFileStore) path(key string) string {
 	return filepath.Join(d.root, key)
 }
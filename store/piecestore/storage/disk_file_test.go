@@ -296,6 +296,37 @@ func TestDiskFile_ListAll(t *testing.T) {
 	assert.Equal(t, ErrUnsupportedMethod, err)
 }
 
+func TestDiskFile_MoveAndWalk(t *testing.T) {
+	store := &diskFileStore{root: t.TempDir() + "/"}
+	err := store.PutObject(context.TODO(), "staging/e1_s0_p0", strings.NewReader("piece"))
+	assert.Nil(t, err)
+
+	var keys []string
+	err = store.WalkObjects(context.TODO(), "staging/", func(obj Object) error {
+		keys = append(keys, obj.Key())
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"staging/e1_s0_p0"}, keys)
+
+	err = store.MoveObject(context.TODO(), "staging/e1_s0_p0", "e1_s0_p0")
+	assert.Nil(t, err)
+	obj, err := store.HeadObject(context.TODO(), "e1_s0_p0")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), obj.Size())
+
+	keys = nil
+	err = store.WalkObjects(context.TODO(), "staging/", func(obj Object) error {
+		keys = append(keys, obj.Key())
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+
+	err = store.MoveObject(context.TODO(), "staging/e1_s0_p0", "e1_s0_p0")
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestPath(t *testing.T) {
 	cases := []struct {
 		name         string
@@ -32,6 +32,23 @@ type ObjectStorage interface {
 	ListAllObjects(ctx context.Context, prefix, marker string) (<-chan Object, error)
 }
 
+// ObjectMover is implemented by the object storage that can rename an object atomically,
+// e.g. rename the file of disk file storage.
+type ObjectMover interface {
+	// CanMoveObject returns whether the object can be renamed from srcKey to dstKey
+	CanMoveObject(srcKey, dstKey string) bool
+	// MoveObject renames the object from srcKey to dstKey, returns ErrUnsupportedMethod if
+	// the object can not be renamed
+	MoveObject(ctx context.Context, srcKey, dstKey string) error
+}
+
+// ObjectWalker is implemented by the object storage that can walk the objects with
+// prefix but does not support ListObjects.
+type ObjectWalker interface {
+	// WalkObjects calls fn for each object whose key has the prefix
+	WalkObjects(ctx context.Context, prefix string, fn func(Object) error) error
+}
+
 // Object
 type Object interface {
 	Key() string
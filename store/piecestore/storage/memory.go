@@ -136,3 +136,22 @@ func (m *memoryStore) ListObjects(ctx context.Context, prefix, marker, delimiter
 	}
 	return objs, nil
 }
+
+func (m *memoryStore) CanMoveObject(srcKey, dstKey string) bool {
+	return true
+}
+
+func (m *memoryStore) MoveObject(ctx context.Context, srcKey, dstKey string) error {
+	m.Lock()
+	defer m.Unlock()
+	if srcKey == "" || dstKey == "" {
+		return ErrInvalidObjectKey
+	}
+	o, ok := m.objects[srcKey]
+	if !ok {
+		return ErrNoSuchObject
+	}
+	m.objects[dstKey] = o
+	delete(m.objects, srcKey)
+	return nil
+}
@@ -82,3 +82,30 @@ func getSecretKeyFromEnv(accessKey, secretKey, sessionToken string) *objectStora
 	}
 	return key
 }
+
+// walkObjectsPageSize defines the page size of listing objects when walking objects
+const walkObjectsPageSize = 1000
+
+// WalkObjects calls fn for each object whose key has the prefix, it uses ObjectWalker if
+// the store implements it, otherwise lists the objects page by page.
+func WalkObjects(ctx context.Context, store ObjectStorage, prefix string, fn func(Object) error) error {
+	if walker, ok := store.(ObjectWalker); ok {
+		return walker.WalkObjects(ctx, prefix, fn)
+	}
+	marker := ""
+	for {
+		objs, err := store.ListObjects(ctx, prefix, marker, "", walkObjectsPageSize)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			if err = fn(obj); err != nil {
+				return err
+			}
+		}
+		if len(objs) < walkObjectsPageSize {
+			return nil
+		}
+		marker = objs[len(objs)-1].Key()
+	}
+}
@@ -44,9 +44,10 @@ func (s *sharded) CreateBucket(ctx context.Context) error {
 	return nil
 }
 
+// pick returns the shard of the key, the staged key is on the same shard as its final key.
 func (s *sharded) pick(key string) ObjectStorage {
 	h := fnv.New32a()
-	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte(strings.TrimPrefix(key, StagingKeyPrefix)))
 	i := h.Sum32() % uint32(len(s.stores))
 	return s.stores[i]
 }
@@ -75,3 +76,27 @@ func (s *sharded) HeadBucket(ctx context.Context) error {
 func (s *sharded) HeadObject(ctx context.Context, key string) (Object, error) {
 	return s.pick(key).HeadObject(ctx, key)
 }
+
+// CanMoveObject returns true only if srcKey and dstKey are in the same shard that can rename.
+func (s *sharded) CanMoveObject(srcKey, dstKey string) bool {
+	store := s.pick(srcKey)
+	mover, ok := store.(ObjectMover)
+	return ok && store == s.pick(dstKey) && mover.CanMoveObject(srcKey, dstKey)
+}
+
+// MoveObject renames the object only if srcKey and dstKey are in the same shard.
+func (s *sharded) MoveObject(ctx context.Context, srcKey, dstKey string) error {
+	if !s.CanMoveObject(srcKey, dstKey) {
+		return ErrUnsupportedMethod
+	}
+	return s.pick(srcKey).(ObjectMover).MoveObject(ctx, srcKey, dstKey)
+}
+
+func (s *sharded) WalkObjects(ctx context.Context, prefix string, fn func(Object) error) error {
+	for _, o := range s.stores {
+		if err := WalkObjects(ctx, o, prefix, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ package sqldb
 const (
 	// UploadObjectProgressTableName defines the gc object task table name.
 	UploadObjectProgressTableName = "upload_object_progress"
+	// ReplicatePieceProgressTableName defines the replicated piece progress table name.
+	ReplicatePieceProgressTableName = "replicate_piece_progress"
 	// GCObjectProgressTableName defines the gc object task table name.
 	GCObjectProgressTableName = "gc_object_progress"
 	// PieceHashTableName defines the piece hash table name.
@@ -16,12 +18,27 @@ const (
 	StorageParamsTableName = "storage_params"
 	// BucketTrafficTableName defines the bucket traffic table name, which is used for recoding the used quota by bucket.
 	BucketTrafficTableName = "bucket_traffic"
-	// ReadRecordTableName defines the read record table name.
+	// ReadRecordTableName defines the read record table name, the records are stored in the
+	// monthly shards named with the "_YYYYMM" suffix.
 	ReadRecordTableName = "read_record"
+	// BucketReadRollupTableName defines the daily read usage of bucket table name.
+	BucketReadRollupTableName = "bucket_read_rollup"
 	// ServiceConfigTableName defines the SP configuration table name.
 	ServiceConfigTableName = "service_config"
 	// OffChainAuthKeyTableName defines the off chain auth key table name.
 	OffChainAuthKeyTableName = "off_chain_auth_key"
 	// UploadEventTableName defines the event of uploading object
 	UploadEventTableName = "upload_event"
+	// SecondarySpStatsTableName defines the secondary sp replicate stats table name.
+	SecondarySpStatsTableName = "secondary_sp_stats"
+	// RecoverJobTableName defines the full sp recover job table name.
+	RecoverJobTableName = "recover_job"
+	// WebhookSubscriptionTableName defines the webhook subscription of bucket table name.
+	WebhookSubscriptionTableName = "webhook_subscription"
+	// WebhookDeliveryTableName defines the pending webhook delivery table name.
+	WebhookDeliveryTableName = "webhook_delivery"
+	// WebhookDeadLetterTableName defines the table name of the webhook deliveries that exhaust the attempts.
+	WebhookDeadLetterTableName = "webhook_dead_letter"
+	// WebhookCursorTableName defines the table name of the change feed cursor dispatched to the webhook deliveries.
+	WebhookCursorTableName = "webhook_cursor"
 )
@@ -0,0 +1,112 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+const (
+	// DriverMySQL defines the mysql driver, it is the default driver.
+	DriverMySQL = "mysql"
+	// DriverPostgres defines the postgresql driver.
+	DriverPostgres = "postgres"
+	// DriverSQLite defines the sqlite driver, the Database of the config is the path of
+	// the db file, it is used by the single node deployment and the tests.
+	DriverSQLite = "sqlite"
+)
+
+const (
+	// mysqlDuplicateEntryCode defines the mysql error number of the duplicate key.
+	mysqlDuplicateEntryCode = 1062
+	// postgresUniqueViolationCode defines the postgresql sql state of the duplicate key.
+	postgresUniqueViolationCode = "23505"
+	// sqliteBusyTimeoutMs defines how long the sqlite connection waits for the lock.
+	sqliteBusyTimeoutMs = 5000
+)
+
+// openDialector returns the gorm dialector of the configured driver.
+func openDialector(config *config.SQLDBConfig) (gorm.Dialector, error) {
+	switch config.Driver {
+	case "", DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			config.User, config.Passwd, config.Address, config.Database)
+		return gormmysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := &url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(config.User, config.Passwd),
+			Host:   config.Address,
+			Path:   "/" + config.Database,
+		}
+		return postgres.Open(dsn.String()), nil
+	case DriverSQLite:
+		if config.Database == "" {
+			return nil, fmt.Errorf("sqlite db file path is empty")
+		}
+		dsn := fmt.Sprintf("file:%s?_busy_timeout=%d&_journal_mode=WAL&_txlock=immediate",
+			config.Database, sqliteBusyTimeoutMs)
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported sql db driver %s", config.Driver)
+	}
+}
+
+// IsDuplicateEntry returns whether the error is caused by inserting the duplicate
+// primary key or unique key, it works for all the supported drivers.
+func IsDuplicateEntry(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrDuplicateEntry) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntryCode
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolationCode
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
+
+// ErrDuplicateEntryCode defines the mysql error number of the duplicate key.
+//
+// Deprecated: use IsDuplicateEntry, it works for all the supported drivers.
+var ErrDuplicateEntryCode = mysqlDuplicateEntryCode
+
+// MysqlErrCode returns the mysql error number of the error, it returns zero if the error
+// is not returned by mysql.
+//
+// Deprecated: use IsDuplicateEntry, it works for all the supported drivers.
+func MysqlErrCode(err error) int {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return 0
+	}
+	return int(mysqlErr.Number)
+}
+
+// IsRecordNotFound returns whether the error is caused by querying the record that does
+// not exist, it works for all the supported drivers.
+func IsRecordNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, sql.ErrNoRows)
+}
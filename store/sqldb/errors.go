@@ -5,4 +5,8 @@ import "errors"
 var (
 	// ErrCheckQuotaEnough defines check quota is enough
 	ErrCheckQuotaEnough = errors.New("quota is not enough")
+	// ErrDuplicateEntry defines the record to insert is already existed
+	ErrDuplicateEntry = errors.New("duplicate entry")
+	// ErrSpDBClosed defines the spdb is closed, the read records are not accepted
+	ErrSpDBClosed = errors.New("spdb is closed")
 )
@@ -8,7 +8,7 @@ type GCObjectProgressTable struct {
 	CurrentGCBlockID      uint64
 	LastDeletedObjectID   uint64
 	CreateTimestampSecond int64
-	UpdateTimestampSecond int64 `gorm:"index:update_timestamp_index"`
+	UpdateTimestampSecond int64 `gorm:"index:gc_update_timestamp_index"`
 }
 
 // TableName is used to set GCObjectProgressTable Schema's table name in database
@@ -0,0 +1,278 @@
+package sqldb
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
+)
+
+const (
+	// MigrationDBName defines the name of spdb in the migration records.
+	MigrationDBName = "spdb"
+	// migrateBatchSize defines the number of rows that are moved in one batch by the migration.
+	migrateBatchSize = 1000
+	// gcUpdateTimestampIndex defines the index of the update time of the gc object progress.
+	gcUpdateTimestampIndex = "gc_update_timestamp_index"
+	// legacyGCUpdateTimestampIndex defines the old name of gcUpdateTimestampIndex.
+	legacyGCUpdateTimestampIndex = "update_timestamp_index"
+)
+
+// Migrations returns the ordered schema migrations of spdb. The released migrations must
+// not be modified, the schema changes are appended as the new versions.
+func Migrations() []*migrate.Migration {
+	return []*migrate.Migration{
+		{
+			// the baseline of the tables that are created by AutoMigrate in the early versions,
+			// it is a no-op for the existing databases.
+			Version: 1,
+			Name:    "init",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&uploadObjectProgressV1{}, &replicatePieceProgressV1{}, &uploadEventV1{},
+					&gcObjectProgressV1{}, &spInfoV1{}, &pieceHashV1{}, &integrityMetaV1{},
+					&bucketTrafficV1{}, &readRecordV1{}, &offChainAuthKeyV1{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&uploadObjectProgressV1{}, &replicatePieceProgressV1{}, &uploadEventV1{},
+					&gcObjectProgressV1{}, &spInfoV1{}, &pieceHashV1{}, &integrityMetaV1{},
+					&bucketTrafficV1{}, &readRecordV1{}, &offChainAuthKeyV1{})
+			},
+		},
+		{
+			Version: 2,
+			Name:    "create_secondary_sp_stats",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&secondarySpStatsV2{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&secondarySpStatsV2{})
+			},
+		},
+		{
+			Version: 3,
+			Name:    "create_recover_job",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&recoverJobV3{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&recoverJobV3{})
+			},
+		},
+		{
+			// the read records are moved from the read_record table into the monthly shards,
+			// and are added to the daily rollups. Every batch is committed by itself, so the
+			// large table is not copied in one transaction and the interrupted move resumes.
+			Version:       4,
+			Name:          "shard_read_record",
+			NoTransaction: true,
+			Up: func(db *gorm.DB) error {
+				if err := db.AutoMigrate(&bucketReadRollupV4{}); err != nil {
+					return err
+				}
+				if !db.Migrator().HasTable(&readRecordV1{}) {
+					return nil
+				}
+				if err := moveReadRecordsToShardsV4(db); err != nil {
+					return err
+				}
+				return db.Migrator().DropTable(&readRecordV1{})
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.AutoMigrate(&readRecordV1{}); err != nil {
+					return err
+				}
+				shards, err := listReadRecordShards(db)
+				if err != nil {
+					return err
+				}
+				for _, shard := range shards {
+					if err = moveReadRecordShardBackV4(db, shard); err != nil {
+						return err
+					}
+					if err = db.Migrator().DropTable(shard); err != nil {
+						return err
+					}
+				}
+				return db.Migrator().DropTable(&bucketReadRollupV4{})
+			},
+		},
+		{
+			Version: 5,
+			Name:    "create_webhook",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&webhookSubscriptionV5{}, &webhookDeliveryV5{},
+					&webhookDeadLetterV5{}, &webhookCursorV5{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&webhookSubscriptionV5{}, &webhookDeliveryV5{},
+					&webhookDeadLetterV5{}, &webhookCursorV5{})
+			},
+		},
+		{
+			// the index of the gc object progress is renamed to gc_update_timestamp_index, because
+			// the index names are unique in the whole database of postgresql and sqlite, the old
+			// index is left on the existing mysql databases.
+			Version: 6,
+			Name:    "drop_gc_update_timestamp_index",
+			Up: func(db *gorm.DB) error {
+				if !db.Migrator().HasIndex(GCObjectProgressTableName, gcUpdateTimestampIndex) {
+					if err := db.Exec("CREATE INDEX " + gcUpdateTimestampIndex + " ON " + GCObjectProgressTableName +
+						" (update_timestamp_second)").Error; err != nil {
+						return err
+					}
+				}
+				if !db.Migrator().HasIndex(GCObjectProgressTableName, legacyGCUpdateTimestampIndex) {
+					return nil
+				}
+				return db.Migrator().DropIndex(GCObjectProgressTableName, legacyGCUpdateTimestampIndex)
+			},
+			Down: func(db *gorm.DB) error {
+				// only mysql is able to have the old index besides the upload object progress
+				if db.Dialector.Name() != DriverMySQL ||
+					db.Migrator().HasIndex(GCObjectProgressTableName, legacyGCUpdateTimestampIndex) {
+					return nil
+				}
+				return db.Exec("CREATE INDEX " + legacyGCUpdateTimestampIndex + " ON " + GCObjectProgressTableName +
+					" (update_timestamp_second)").Error
+			},
+		},
+	}
+}
+
+// moveReadRecordsToShardsV4 moves the read records into the monthly shards by batches, every
+// batch is inserted into the shards and the rollups and is deleted from the read_record table
+// in one transaction, so the partially applied migration continues from the remaining records.
+func moveReadRecordsToShardsV4(db *gorm.DB) error {
+	created := make(map[string]struct{})
+	for {
+		var batch []*readRecordV1
+		if err := db.Order("read_record_id").Limit(migrateBatchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		shards := make(map[string][]*readRecordShardV4)
+		rollups := make(map[string]*bucketReadRollupV4)
+		for _, record := range batch {
+			readTime := TimestampUsToTime(record.ReadTimestampUs)
+			name := readRecordShardName(readTime)
+			if _, ok := created[name]; !ok {
+				if err := createReadRecordShardV4(db, name); err != nil {
+					return err
+				}
+				created[name] = struct{}{}
+			}
+			shards[name] = append(shards[name], &readRecordShardV4{
+				BucketID:        record.BucketID,
+				ObjectID:        record.ObjectID,
+				UserAddress:     record.UserAddress,
+				ReadTimestampUs: record.ReadTimestampUs,
+				BucketName:      record.BucketName,
+				ObjectName:      record.ObjectName,
+				ReadSize:        record.ReadSize,
+			})
+			key := fmt.Sprintf("%d/%s", record.BucketID, TimeToDay(readTime))
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &bucketReadRollupV4{BucketID: record.BucketID, Day: TimeToDay(readTime), BucketName: record.BucketName}
+				rollups[key] = rollup
+			}
+			rollup.ReadSize += record.ReadSize
+			rollup.ReadCount++
+		}
+		lastID := batch[len(batch)-1].ReadRecordID
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for name, shardRecords := range shards {
+				if err := tx.Table(name).CreateInBatches(shardRecords, migrateBatchSize).Error; err != nil {
+					return err
+				}
+			}
+			for _, rollup := range rollups {
+				if err := addBucketReadRollupV4(tx, rollup); err != nil {
+					return err
+				}
+			}
+			return tx.Where("read_record_id <= ?", lastID).Delete(&readRecordV1{}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// createReadRecordShardV4 creates the shard and its indexes of the migration v4 if they are
+// not existed.
+func createReadRecordShardV4(db *gorm.DB, name string) error {
+	if !db.Migrator().HasTable(name) {
+		if err := db.Table(name).Migrator().CreateTable(&readRecordShardV4{}); err != nil {
+			return err
+		}
+	}
+	for _, index := range readRecordShardIndexesV4 {
+		indexName := "idx_" + name + "_" + index[0]
+		if db.Migrator().HasIndex(name, indexName) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE INDEX ? ON ? (%s)", index[1]),
+			clause.Column{Name: indexName}, clause.Table{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addBucketReadRollupV4 adds the read size and count to the rollup of the migration v4.
+func addBucketReadRollupV4(db *gorm.DB, rollup *bucketReadRollupV4) error {
+	result := db.Model(&bucketReadRollupV4{}).
+		Where("bucket_id = ? and day = ?", rollup.BucketID, rollup.Day).
+		Updates(map[string]interface{}{
+			"read_size":     gorm.Expr("read_size + ?", rollup.ReadSize),
+			"read_count":    gorm.Expr("read_count + ?", rollup.ReadCount),
+			"modified_time": time.Now(),
+		})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	rollup.ModifiedTime = time.Now()
+	return db.Create(rollup).Error
+}
+
+// moveReadRecordShardBackV4 moves the records of the shard back to the read_record table by
+// batches, every batch is inserted and is deleted from the shard in one transaction.
+func moveReadRecordShardBackV4(db *gorm.DB, shard string) error {
+	for {
+		var batch []*readRecordShardV4
+		if err := db.Table(shard).Order("read_record_id").Limit(migrateBatchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		records := make([]*readRecordV1, 0, len(batch))
+		for _, record := range batch {
+			records = append(records, &readRecordV1{
+				BucketID:        record.BucketID,
+				ObjectID:        record.ObjectID,
+				UserAddress:     record.UserAddress,
+				ReadTimestampUs: record.ReadTimestampUs,
+				BucketName:      record.BucketName,
+				ObjectName:      record.ObjectName,
+				ReadSize:        record.ReadSize,
+			})
+		}
+		lastID := batch[len(batch)-1].ReadRecordID
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(records, migrateBatchSize).Error; err != nil {
+				return err
+			}
+			return tx.Table(shard).Where("read_record_id <= ?", lastID).Delete(&readRecordShardV4{}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package sqldb
+
+import "time"
+
+// The schemas of the tables are frozen at the migration that creates them, so the released
+// migrations are not changed by the later changes of the table schemas. A schema change is
+// appended as the new migration with its own frozen schema.
+
+// uploadObjectProgressV1 is the schema of the upload object progress table in the migration v1.
+type uploadObjectProgressV1 struct {
+	ObjectID              uint64 `gorm:"primary_key"`
+	TaskState             int32  `gorm:"index:state_index"`
+	TaskStateDescription  string
+	ErrorDescription      string
+	SecondaryAddresses    string
+	SecondarySignatures   string
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64 `gorm:"index:update_timestamp_index"`
+}
+
+func (uploadObjectProgressV1) TableName() string {
+	return UploadObjectProgressTableName
+}
+
+// replicatePieceProgressV1 is the schema of the replicate piece progress table in the migration v1.
+type replicatePieceProgressV1 struct {
+	ObjectID              uint64 `gorm:"primary_key"`
+	ReplicateIndex        uint32 `gorm:"primary_key"`
+	PieceIndex            uint32 `gorm:"primary_key"`
+	SpOperatorAddress     string
+	UpdateTimestampSecond int64
+}
+
+func (replicatePieceProgressV1) TableName() string {
+	return ReplicatePieceProgressTableName
+}
+
+// uploadEventV1 is the schema of the upload event table in the migration v1.
+type uploadEventV1 struct {
+	ID          uint64 `gorm:"primary_key;autoIncrement"`
+	ObjectID    uint64
+	UploadState string
+	Description string
+	UpdateTime  string
+}
+
+func (uploadEventV1) TableName() string {
+	return UploadEventTableName
+}
+
+// gcObjectProgressV1 is the schema of the gc object progress table in the migration v1.
+type gcObjectProgressV1 struct {
+	TaskKey               string `gorm:"primary_key"`
+	StartGCBlockID        uint64
+	EndGCBlockID          uint64
+	CurrentGCBlockID      uint64
+	LastDeletedObjectID   uint64
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64 `gorm:"index:gc_update_timestamp_index"`
+}
+
+func (gcObjectProgressV1) TableName() string {
+	return GCObjectProgressTableName
+}
+
+// spInfoV1 is the schema of the sp info table in the migration v1.
+type spInfoV1 struct {
+	OperatorAddress string `gorm:"primary_key"`
+	IsOwn           bool   `gorm:"primary_key"`
+	FundingAddress  string
+	SealAddress     string
+	ApprovalAddress string
+	TotalDeposit    string
+	Status          int32
+	Endpoint        string
+	Moniker         string
+	Identity        string
+	Website         string
+	SecurityContact string
+	Details         string
+}
+
+func (spInfoV1) TableName() string {
+	return SpInfoTableName
+}
+
+// pieceHashV1 is the schema of the piece hash table in the migration v1.
+type pieceHashV1 struct {
+	ObjectID       uint64 `gorm:"primary_key"`
+	ReplicateIndex uint32 `gorm:"primary_key"`
+	PieceIndex     uint32 `gorm:"primary_key"`
+	PieceChecksum  string
+}
+
+func (pieceHashV1) TableName() string {
+	return PieceHashTableName
+}
+
+// integrityMetaV1 is the schema of the integrity meta table in the migration v1.
+type integrityMetaV1 struct {
+	ObjectID          uint64 `gorm:"primary_key"`
+	IntegrityChecksum string
+	PieceChecksumList string
+	Signature         string
+}
+
+func (integrityMetaV1) TableName() string {
+	return IntegrityMetaTableName
+}
+
+// bucketTrafficV1 is the schema of the bucket traffic table in the migration v1.
+type bucketTrafficV1 struct {
+	BucketID         uint64 `gorm:"primary_key"`
+	Month            string `gorm:"primary_key"`
+	BucketName       string
+	ReadConsumedSize uint64
+	ReadQuotaSize    uint64
+	ModifiedTime     time.Time
+}
+
+func (bucketTrafficV1) TableName() string {
+	return BucketTrafficTableName
+}
+
+// readRecordV1 is the schema of the read record table in the migration v1, the table is
+// moved into the monthly shards by the migration v4.
+type readRecordV1 struct {
+	ReadRecordID    uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID        uint64 `gorm:"index:bucket_to_read_record"`
+	ObjectID        uint64 `gorm:"index:object_to_read_record"`
+	UserAddress     string `gorm:"index:user_to_read_record"`
+	ReadTimestampUs int64  `gorm:"index:time_to_read_record"`
+	BucketName      string
+	ObjectName      string
+	ReadSize        uint64
+}
+
+func (readRecordV1) TableName() string {
+	return ReadRecordTableName
+}
+
+// offChainAuthKeyV1 is the schema of the off-chain auth key table in the migration v1.
+type offChainAuthKeyV1 struct {
+	UserAddress string `gorm:"primary_key"`
+	Domain      string `gorm:"primary_key"`
+
+	CurrentNonce     int32
+	CurrentPublicKey string
+	NextNonce        int32
+	ExpiryDate       time.Time
+
+	CreatedTime  time.Time
+	ModifiedTime time.Time
+}
+
+func (offChainAuthKeyV1) TableName() string {
+	return OffChainAuthKeyTableName
+}
+
+// secondarySpStatsV2 is the schema of the secondary sp stats table in the migration v2.
+type secondarySpStatsV2 struct {
+	OperatorAddress       string `gorm:"primary_key"`
+	SucceedCount          uint64
+	FailedCount           uint64
+	AvgLatencyMs          int64
+	UpdateTimestampSecond int64
+}
+
+func (secondarySpStatsV2) TableName() string {
+	return SecondarySpStatsTableName
+}
+
+// recoverJobV3 is the schema of the recover job table in the migration v3.
+type recoverJobV3 struct {
+	JobID                 uint64 `gorm:"primary_key;autoIncrement"`
+	Status                string
+	Cursor                uint64
+	ScannedObjects        uint64
+	GeneratedTasks        uint64
+	SkippedPieces         uint64
+	SucceedPieces         uint64
+	FailedPieces          uint64
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+}
+
+func (recoverJobV3) TableName() string {
+	return RecoverJobTableName
+}
+
+// readRecordShardV4 is the schema of the monthly read record shard in the migration v4, the
+// table name is set by the shard.
+type readRecordShardV4 struct {
+	ReadRecordID    uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID        uint64
+	ObjectID        uint64
+	UserAddress     string `gorm:"size:64"`
+	ReadTimestampUs int64
+	BucketName      string
+	ObjectName      string
+	ReadSize        uint64
+}
+
+// readRecordShardIndexesV4 defines the index suffixes and the columns of the shard in the
+// migration v4.
+var readRecordShardIndexesV4 = [][2]string{
+	{"bucket", "bucket_id, read_timestamp_us"},
+	{"object", "object_id, read_timestamp_us"},
+	{"user", "user_address, read_timestamp_us"},
+	{"time", "read_timestamp_us"},
+}
+
+// bucketReadRollupV4 is the schema of the bucket read rollup table in the migration v4.
+type bucketReadRollupV4 struct {
+	BucketID     uint64 `gorm:"primary_key"`
+	Day          string `gorm:"primary_key"`
+	BucketName   string
+	ReadSize     uint64
+	ReadCount    uint64
+	ModifiedTime time.Time
+}
+
+func (bucketReadRollupV4) TableName() string {
+	return BucketReadRollupTableName
+}
+
+// webhookSubscriptionV5 is the schema of the webhook subscription table in the migration v5.
+type webhookSubscriptionV5 struct {
+	SubscriptionID        uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID              uint64 `gorm:"index:bucket_to_webhook_subscription"`
+	BucketName            string
+	Owner                 string
+	URL                   string `gorm:"size:1024"`
+	Secret                string
+	Events                string
+	QuotaThreshold        uint32
+	QuotaNotifiedMonth    string
+	CreateTimestampSecond int64
+}
+
+func (webhookSubscriptionV5) TableName() string {
+	return WebhookSubscriptionTableName
+}
+
+// webhookDeliveryV5 is the schema of the webhook delivery table in the migration v5.
+type webhookDeliveryV5 struct {
+	DeliveryID                 uint64 `gorm:"primary_key;autoIncrement"`
+	SubscriptionID             uint64 `gorm:"uniqueIndex:subscription_event_to_webhook_delivery,priority:1"`
+	EventID                    string `gorm:"size:128;uniqueIndex:subscription_event_to_webhook_delivery,priority:2"`
+	EventType                  string
+	Payload                    string `gorm:"type:text"`
+	Attempts                   uint32
+	NextAttemptTimestampSecond int64  `gorm:"index:next_attempt_to_webhook_delivery"`
+	LastError                  string `gorm:"type:text"`
+	CreateTimestampSecond      int64
+}
+
+func (webhookDeliveryV5) TableName() string {
+	return WebhookDeliveryTableName
+}
+
+// webhookDeadLetterV5 is the schema of the webhook dead letter table in the migration v5.
+type webhookDeadLetterV5 struct {
+	DeadLetterID          uint64 `gorm:"primary_key;autoIncrement"`
+	SubscriptionID        uint64 `gorm:"index:subscription_to_webhook_dead_letter"`
+	EventID               string `gorm:"size:128"`
+	EventType             string
+	Payload               string `gorm:"type:text"`
+	Attempts              uint32
+	LastError             string `gorm:"type:text"`
+	CreateTimestampSecond int64
+	FailedTimestampSecond int64
+}
+
+func (webhookDeadLetterV5) TableName() string {
+	return WebhookDeadLetterTableName
+}
+
+// webhookCursorV5 is the schema of the webhook cursor table in the migration v5.
+type webhookCursorV5 struct {
+	Name                  string `gorm:"primary_key;size:64"`
+	Cursor                uint64
+	UpdateTimestampSecond int64
+}
+
+func (webhookCursorV5) TableName() string {
+	return WebhookCursorTableName
+}
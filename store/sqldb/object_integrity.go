@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
@@ -45,18 +44,6 @@ func (s *SpDBImpl) GetObjectIntegrity(objectID uint64) (*corespdb.IntegrityMeta,
 	return meta, nil
 }
 
-func MysqlErrCode(err error) int {
-	mysqlErr, ok := err.(*mysql.MySQLError)
-	if !ok {
-		return 0
-	}
-	return int(mysqlErr.Number)
-}
-
-var (
-	ErrDuplicateEntryCode = 1062
-)
-
 // SetObjectIntegrity puts(overwrites) integrity hash info to db
 func (s *SpDBImpl) SetObjectIntegrity(meta *corespdb.IntegrityMeta) error {
 	insertIntegrityMetaRecord := &IntegrityMetaTable{
@@ -66,7 +53,7 @@ func (s *SpDBImpl) SetObjectIntegrity(meta *corespdb.IntegrityMeta) error {
 		Signature:         hex.EncodeToString(meta.Signature),
 	}
 	result := s.db.Create(insertIntegrityMetaRecord)
-	if result.Error != nil && MysqlErrCode(result.Error) == ErrDuplicateEntryCode {
+	if IsDuplicateEntry(result.Error) {
 		return nil
 	}
 	if result.Error != nil || result.RowsAffected != 1 {
@@ -145,7 +132,7 @@ func (s *SpDBImpl) SetReplicatePieceChecksum(objectID uint64, replicateIdx uint3
 		PieceChecksum:  hex.EncodeToString(checksum),
 	}
 	result = s.db.Create(insertPieceHash)
-	if result.Error != nil && MysqlErrCode(result.Error) == ErrDuplicateEntryCode {
+	if IsDuplicateEntry(result.Error) {
 		return nil
 	}
 	if result.Error != nil || result.RowsAffected != 1 {
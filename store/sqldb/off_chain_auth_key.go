@@ -1,13 +1,10 @@
 package sqldb
 
 import (
-	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
-	"gorm.io/gorm"
 )
 
 // InsertAuthKey insert a new record into OffChainAuthKeyTable
@@ -54,10 +51,6 @@ func (s *SpDBImpl) UpdateAuthKey(userAddress string, domain string, oldNonce int
 	return nil
 }
 
-func errIsNotFound(err error) bool {
-	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, gorm.ErrRecordNotFound)
-}
-
 // GetAuthKey get OffChainAuthKey from OffChainAuthKeyTable
 func (s *SpDBImpl) GetAuthKey(userAddress string, domain string) (*corespdb.OffChainAuthKey, error) {
 	if userAddress == "" || domain == "" {
@@ -68,7 +61,7 @@ func (s *SpDBImpl) GetAuthKey(userAddress string, domain string) (*corespdb.OffC
 	result := s.db.First(queryKeyReturn, "user_address = ? and domain =?", userAddress, domain)
 
 	if result.Error != nil {
-		if errIsNotFound(result.Error) {
+		if IsRecordNotFound(result.Error) {
 			// this is a new initial record, not containing any public key but just generate the first nonce as 1
 			newRecord := &corespdb.OffChainAuthKey{
 				UserAddress:      userAddress,
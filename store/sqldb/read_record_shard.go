@@ -0,0 +1,300 @@
+package sqldb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// readRecordShardLayout defines the month suffix of the read record shard name.
+	readRecordShardLayout = "200601"
+	// DefaultReadRecordMaintainInterval defines the interval to create the shards of the
+	// coming month and drop the expired shards.
+	DefaultReadRecordMaintainInterval = time.Hour
+)
+
+// readRecordShardIndexes defines the index suffixes and the columns of the shard, all the
+// queries filter by the timestamp range.
+var readRecordShardIndexes = [][2]string{
+	{"bucket", "bucket_id, read_timestamp_us"},
+	{"object", "object_id, read_timestamp_us"},
+	{"user", "user_address, read_timestamp_us"},
+	{"time", "read_timestamp_us"},
+}
+
+// readRecordShardName returns the name of the shard that stores the records of the month.
+func readRecordShardName(t time.Time) string {
+	return ReadRecordTableName + "_" + t.Format(readRecordShardLayout)
+}
+
+// parseReadRecordShardName returns the first day of the month of the shard, ok is false if
+// the table is not a read record shard.
+func parseReadRecordShardName(name string) (time.Time, bool) {
+	suffix, found := strings.CutPrefix(name, ReadRecordTableName+"_")
+	if !found || len(suffix) != len(readRecordShardLayout) {
+		return time.Time{}, false
+	}
+	month, err := time.ParseInLocation(readRecordShardLayout, suffix, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// createReadRecordShard creates the shard and its indexes if they are not existed.
+func createReadRecordShard(db *gorm.DB, name string) error {
+	if !db.Migrator().HasTable(name) {
+		if err := db.Table(name).Migrator().CreateTable(&ReadRecordShardTable{}); err != nil && !db.Migrator().HasTable(name) {
+			return fmt.Errorf("failed to create read record shard %s: %w", name, err)
+		}
+	}
+	for _, index := range readRecordShardIndexes {
+		indexName := "idx_" + name + "_" + index[0]
+		if db.Migrator().HasIndex(name, indexName) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE INDEX ? ON ? (%s)", index[1]),
+			clause.Column{Name: indexName}, clause.Table{Name: name}).Error; err != nil && !db.Migrator().HasIndex(name, indexName) {
+			return fmt.Errorf("failed to create index %s: %w", indexName, err)
+		}
+	}
+	return nil
+}
+
+// listReadRecordShards returns the existing shards in the order of the month.
+func listReadRecordShards(db *gorm.DB) ([]string, error) {
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var shards []string
+	for _, table := range tables {
+		if _, ok := parseReadRecordShardName(table); ok {
+			shards = append(shards, table)
+		}
+	}
+	sort.Strings(shards)
+	return shards, nil
+}
+
+// insertReadRecords inserts the records into the shards of their month and adds them to
+// the daily rollups in one transaction, the shards must be existed.
+func insertReadRecords(db *gorm.DB, records []*ReadRecordTable, batchSize int) error {
+	shards := make(map[string][]*ReadRecordShardTable)
+	type rollupKey struct {
+		bucketID uint64
+		day      string
+	}
+	rollups := make(map[rollupKey]*BucketReadRollupTable)
+	for _, record := range records {
+		readTime := TimestampUsToTime(record.ReadTimestampUs)
+		name := readRecordShardName(readTime)
+		shards[name] = append(shards[name], &ReadRecordShardTable{
+			BucketID:        record.BucketID,
+			ObjectID:        record.ObjectID,
+			UserAddress:     record.UserAddress,
+			ReadTimestampUs: record.ReadTimestampUs,
+			BucketName:      record.BucketName,
+			ObjectName:      record.ObjectName,
+			ReadSize:        record.ReadSize,
+		})
+		key := rollupKey{bucketID: record.BucketID, day: TimeToDay(readTime)}
+		rollup, ok := rollups[key]
+		if !ok {
+			rollup = &BucketReadRollupTable{BucketID: key.bucketID, Day: key.day, BucketName: record.BucketName}
+			rollups[key] = rollup
+		}
+		rollup.ReadSize += record.ReadSize
+		rollup.ReadCount++
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for name, shardRecords := range shards {
+			if err := tx.Table(name).CreateInBatches(shardRecords, batchSize).Error; err != nil {
+				return fmt.Errorf("failed to insert read records into %s: %w", name, err)
+			}
+		}
+		for _, rollup := range rollups {
+			if err := addBucketReadRollup(tx, rollup); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// addBucketReadRollup adds the read size and count to the rollup of the bucket and day, the
+// rollup is inserted if it is not existed.
+func addBucketReadRollup(db *gorm.DB, rollup *BucketReadRollupTable) error {
+	for inserted := false; ; inserted = true {
+		result := db.Model(&BucketReadRollupTable{}).
+			Where("bucket_id = ? and day = ?", rollup.BucketID, rollup.Day).
+			Updates(map[string]interface{}{
+				"read_size":     gorm.Expr("read_size + ?", rollup.ReadSize),
+				"read_count":    gorm.Expr("read_count + ?", rollup.ReadCount),
+				"modified_time": time.Now(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update bucket read rollup: %w", result.Error)
+		}
+		if result.RowsAffected > 0 || inserted {
+			return nil
+		}
+		rollup.ModifiedTime = time.Now()
+		result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(rollup)
+		if result.Error != nil {
+			return fmt.Errorf("failed to insert bucket read rollup: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return nil
+		}
+		// inserted by others, add to it again
+	}
+}
+
+// readRecordShards creates the shards of the current and the next month, so the writes at
+// the beginning of the month do not wait for the DDL, and drops the shards older than the
+// retention. The rollups are kept after the shards are dropped. The shard names are cached
+// for the queries and refreshed by every maintenance.
+type readRecordShards struct {
+	db              *gorm.DB
+	retentionMonths int
+	mux             sync.RWMutex
+	names           []string // the existing shards in the order of the month, never modified in place
+	stopCh          chan struct{}
+	wg              sync.WaitGroup
+	closeOnce       sync.Once
+}
+
+func newReadRecordShards(db *gorm.DB, retentionMonths int) *readRecordShards {
+	s := &readRecordShards{db: db, retentionMonths: retentionMonths, stopCh: make(chan struct{})}
+	s.maintain(time.Now())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(DefaultReadRecordMaintainInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.maintain(time.Now())
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// ensure creates the shards of the records if they are not created.
+func (s *readRecordShards) ensure(records []*ReadRecordTable) error {
+	for _, record := range records {
+		name := readRecordShardName(TimestampUsToTime(record.ReadTimestampUs))
+		if s.has(name) {
+			continue
+		}
+		if err := createReadRecordShard(s.db, name); err != nil {
+			return err
+		}
+		s.add(name)
+	}
+	return nil
+}
+
+// list returns the cached shards in the order of the month.
+func (s *readRecordShards) list() []string {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.names
+}
+
+func (s *readRecordShards) has(name string) bool {
+	names := s.list()
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name
+}
+
+func (s *readRecordShards) add(name string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i := sort.SearchStrings(s.names, name)
+	if i < len(s.names) && s.names[i] == name {
+		return
+	}
+	names := make([]string, 0, len(s.names)+1)
+	names = append(names, s.names[:i]...)
+	names = append(names, name)
+	s.names = append(names, s.names[i:]...)
+}
+
+func (s *readRecordShards) remove(name string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	i := sort.SearchStrings(s.names, name)
+	if i == len(s.names) || s.names[i] != name {
+		return
+	}
+	names := make([]string, 0, len(s.names)-1)
+	names = append(names, s.names[:i]...)
+	s.names = append(names, s.names[i+1:]...)
+}
+
+// refresh reloads the cached shards from the db, the lock is held while listing so the
+// shards created by ensure meanwhile are not lost.
+func (s *readRecordShards) refresh() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	shards, err := listReadRecordShards(s.db)
+	if err != nil {
+		log.Errorw("failed to list read record shards", "error", err)
+		return
+	}
+	s.names = shards
+}
+
+func (s *readRecordShards) maintain(now time.Time) {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	defer s.refresh()
+	for _, t := range []time.Time{month, month.AddDate(0, 1, 0)} {
+		name := readRecordShardName(t)
+		if err := createReadRecordShard(s.db, name); err != nil {
+			log.Errorw("failed to create read record shard", "shard", name, "error", err)
+		}
+	}
+	if s.retentionMonths <= 0 {
+		return
+	}
+	shards, err := listReadRecordShards(s.db)
+	if err != nil {
+		log.Errorw("failed to list read record shards", "error", err)
+		return
+	}
+	expiration := month.AddDate(0, -s.retentionMonths, 0)
+	for _, name := range shards {
+		shardMonth, _ := parseReadRecordShardName(name)
+		if !shardMonth.Before(expiration) {
+			break
+		}
+		// stop querying the shard before dropping it, it is restored by the refresh if failed
+		s.remove(name)
+		if err = s.db.Migrator().DropTable(name); err != nil {
+			log.Errorw("failed to drop expired read record shard", "shard", name, "error", err)
+			continue
+		}
+		log.Infow("succeed to drop expired read record shard", "shard", name)
+	}
+}
+
+func (s *readRecordShards) close() {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		s.wg.Wait()
+	})
+}
@@ -0,0 +1,145 @@
+package sqldb
+
+import (
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// DefaultReadRecordBatchSize defines the max number of read records inserted in one batch.
+	DefaultReadRecordBatchSize = 500
+	// DefaultReadRecordFlushInterval defines the interval to insert the buffered read records.
+	DefaultReadRecordFlushInterval = time.Second
+	// readRecordBufferSize defines the number of read records can be buffered, the record is
+	// inserted synchronously if the buffer is full.
+	readRecordBufferSize = 10 * DefaultReadRecordBatchSize
+)
+
+// readRecordWriter inserts the read records in batch in the background, so the download
+// does not wait for the insert. The records are visible to the queries after flushing, and
+// the daily rollups are updated with the records in the same transaction. The consumed
+// quota is updated synchronously, only the records buffered in the last flush interval are
+// lost if the process crashes. The batch that fails to insert is retried by the next flush.
+type readRecordWriter struct {
+	db        *gorm.DB
+	shards    *readRecordShards
+	records   chan *ReadRecordTable
+	batchSize int
+	interval  time.Duration
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	mux       sync.RWMutex
+	closed    bool
+}
+
+func newReadRecordWriter(db *gorm.DB, shards *readRecordShards) *readRecordWriter {
+	w := &readRecordWriter{
+		db:        db,
+		shards:    shards,
+		records:   make(chan *ReadRecordTable, readRecordBufferSize),
+		batchSize: DefaultReadRecordBatchSize,
+		interval:  DefaultReadRecordFlushInterval,
+		stopCh:    make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.loop()
+	return w
+}
+
+// add buffers the record, it falls back to insert synchronously if the buffer is full. It
+// returns ErrSpDBClosed after the writer is closed.
+func (w *readRecordWriter) add(record *ReadRecordTable) error {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	if w.closed {
+		return ErrSpDBClosed
+	}
+	select {
+	case w.records <- record:
+		return nil
+	default:
+		return w.insert([]*ReadRecordTable{record})
+	}
+}
+
+// isClosed returns whether the writer is closed.
+func (w *readRecordWriter) isClosed() bool {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.closed
+}
+
+func (w *readRecordWriter) loop() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	batch := make([]*ReadRecordTable, 0, w.batchSize)
+	// the failed batch is only retried by the ticker
+	failed := false
+	for {
+		select {
+		case record := <-w.records:
+			batch = append(batch, record)
+			if len(batch) >= w.batchSize && !failed {
+				batch = w.flush(batch)
+				failed = len(batch) > 0
+			}
+		case <-ticker.C:
+			batch = w.flush(batch)
+			failed = len(batch) > 0
+		case <-w.stopCh:
+			for {
+				select {
+				case record := <-w.records:
+					batch = append(batch, record)
+				default:
+					w.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush inserts the batch and returns the batch to append the next records, the failed batch
+// is kept to retry unless it exceeds the buffer size.
+func (w *readRecordWriter) flush(batch []*ReadRecordTable) []*ReadRecordTable {
+	if len(batch) == 0 {
+		return batch
+	}
+	err := w.insert(batch)
+	if err == nil {
+		return batch[:0]
+	}
+	if len(batch) >= readRecordBufferSize {
+		log.Errorw("failed to insert read records, drop the records", "count", len(batch), "error", err)
+		return batch[:0]
+	}
+	log.Errorw("failed to insert read records, retry later", "count", len(batch), "error", err)
+	return batch
+}
+
+func (w *readRecordWriter) insert(batch []*ReadRecordTable) error {
+	if err := w.shards.ensure(batch); err != nil {
+		return err
+	}
+	return insertReadRecords(w.db, batch, w.batchSize)
+}
+
+// close stops accepting the records, flushes the buffered records and stops the background
+// goroutine.
+func (w *readRecordWriter) close() {
+	w.mux.Lock()
+	if w.closed {
+		w.mux.Unlock()
+		return
+	}
+	w.closed = true
+	w.mux.Unlock()
+	close(w.stopCh)
+	w.wg.Wait()
+}
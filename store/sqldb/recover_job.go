@@ -0,0 +1,123 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+// InsertRecoverJob inserts a new recover job and returns the job id.
+func (s *SpDBImpl) InsertRecoverJob(job *corespdb.RecoverJob) (uint64, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("insertRecoverJob")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	now := time.Now().Unix()
+	insertJob := &RecoverJobTable{
+		Status:                job.Status,
+		Cursor:                job.Cursor,
+		CreateTimestampSecond: now,
+		UpdateTimestampSecond: now,
+	}
+	result := s.db.Create(insertJob)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return 0, fmt.Errorf("failed to insert recover job table: %s", result.Error)
+	}
+	return insertJob.JobID, nil
+}
+
+// GetLatestRecoverJob returns the latest recover job, it returns nil if there is no job.
+func (s *SpDBImpl) GetLatestRecoverJob() (*corespdb.RecoverJob, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("getLatestRecoverJob")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	queryReturn := &RecoverJobTable{}
+	result := s.db.Order("job_id desc").First(queryReturn)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query recover job table: %s", result.Error)
+	}
+	return &corespdb.RecoverJob{
+		JobID:                 queryReturn.JobID,
+		Status:                queryReturn.Status,
+		Cursor:                queryReturn.Cursor,
+		ScannedObjects:        queryReturn.ScannedObjects,
+		GeneratedTasks:        queryReturn.GeneratedTasks,
+		SkippedPieces:         queryReturn.SkippedPieces,
+		SucceedPieces:         queryReturn.SucceedPieces,
+		FailedPieces:          queryReturn.FailedPieces,
+		CreateTimestampSecond: queryReturn.CreateTimestampSecond,
+		UpdateTimestampSecond: queryReturn.UpdateTimestampSecond,
+	}, nil
+}
+
+// UpdateRecoverJobStatus updates the status of the recover job.
+func (s *SpDBImpl) UpdateRecoverJobStatus(jobID uint64, status string) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("updateRecoverJobStatus")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	result := s.db.Model(&RecoverJobTable{}).Where("job_id = ?", jobID).Updates(map[string]interface{}{
+		"status":                  status,
+		"update_timestamp_second": time.Now().Unix(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update recover job status: %s", result.Error)
+	}
+	return nil
+}
+
+// UpdateRecoverJobProgress moves the cursor of the recover job and accumulates the counters.
+func (s *SpDBImpl) UpdateRecoverJobProgress(jobID uint64, cursor uint64, scanned uint64, generated uint64, skipped uint64) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("updateRecoverJobProgress")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	result := s.db.Model(&RecoverJobTable{}).Where("job_id = ?", jobID).Updates(map[string]interface{}{
+		"cursor":                  cursor,
+		"scanned_objects":         gorm.Expr("scanned_objects + ?", scanned),
+		"generated_tasks":         gorm.Expr("generated_tasks + ?", generated),
+		"skipped_pieces":          gorm.Expr("skipped_pieces + ?", skipped),
+		"update_timestamp_second": time.Now().Unix(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update recover job progress: %s", result.Error)
+	}
+	return nil
+}
+
+// IncreaseRecoverJobPieces accumulates the number of the succeed or failed recovered pieces.
+func (s *SpDBImpl) IncreaseRecoverJobPieces(jobID uint64, succeed bool) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("increaseRecoverJobPieces")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	column := "failed_pieces"
+	if succeed {
+		column = "succeed_pieces"
+	}
+	result := s.db.Model(&RecoverJobTable{}).Where("job_id = ?", jobID).
+		Update(column, gorm.Expr(column+" + ?", 1))
+	if result.Error != nil {
+		return fmt.Errorf("failed to increase recover job pieces: %s", result.Error)
+	}
+	return nil
+}
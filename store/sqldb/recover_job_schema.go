@@ -0,0 +1,20 @@
+package sqldb
+
+// RecoverJobTable table schema
+type RecoverJobTable struct {
+	JobID                 uint64 `gorm:"primary_key;autoIncrement"`
+	Status                string
+	Cursor                uint64
+	ScannedObjects        uint64
+	GeneratedTasks        uint64
+	SkippedPieces         uint64
+	SucceedPieces         uint64
+	FailedPieces          uint64
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set RecoverJobTable Schema's table name in database
+func (RecoverJobTable) TableName() string {
+	return RecoverJobTableName
+}
@@ -0,0 +1,48 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestRecoverJob(t *testing.T) {
+	db := newTestSpDB(t)
+	job, err := db.GetLatestRecoverJob()
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	firstID, err := db.InsertRecoverJob(&corespdb.RecoverJob{Status: corespdb.RecoverJobRunning})
+	require.NoError(t, err)
+	require.NoError(t, db.UpdateRecoverJobStatus(firstID, corespdb.RecoverJobFinished))
+	jobID, err := db.InsertRecoverJob(&corespdb.RecoverJob{Status: corespdb.RecoverJobRunning})
+	require.NoError(t, err)
+	assert.Greater(t, jobID, firstID)
+
+	// the progress is accumulated by the batches
+	require.NoError(t, db.UpdateRecoverJobProgress(jobID, 100, 10, 4, 6))
+	require.NoError(t, db.UpdateRecoverJobProgress(jobID, 200, 10, 2, 8))
+	require.NoError(t, db.IncreaseRecoverJobPieces(jobID, true))
+	require.NoError(t, db.IncreaseRecoverJobPieces(jobID, true))
+	require.NoError(t, db.IncreaseRecoverJobPieces(jobID, false))
+	job, err = db.GetLatestRecoverJob()
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.JobID)
+	assert.Equal(t, corespdb.RecoverJobRunning, job.Status)
+	assert.Equal(t, uint64(200), job.Cursor)
+	assert.Equal(t, uint64(20), job.ScannedObjects)
+	assert.Equal(t, uint64(6), job.GeneratedTasks)
+	assert.Equal(t, uint64(14), job.SkippedPieces)
+	assert.Equal(t, uint64(2), job.SucceedPieces)
+	assert.Equal(t, uint64(1), job.FailedPieces)
+
+	// the paused job keeps its cursor for resuming
+	require.NoError(t, db.UpdateRecoverJobStatus(jobID, corespdb.RecoverJobPaused))
+	job, err = db.GetLatestRecoverJob()
+	require.NoError(t, err)
+	assert.Equal(t, corespdb.RecoverJobPaused, job.Status)
+	assert.Equal(t, uint64(200), job.Cursor)
+}
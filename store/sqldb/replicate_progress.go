@@ -0,0 +1,85 @@
+package sqldb
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+// SetReplicatePieceProgress records the piece has been replicated to the secondary sp, the
+// record of the replaced secondary sp is overwritten so the piece is not trusted on it.
+func (s *SpDBImpl) SetReplicatePieceProgress(progress *corespdb.ReplicatePieceProgress) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("setReplicatePieceProgress")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "object_id"}, {Name: "replicate_index"}, {Name: "piece_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sp_operator_address", "update_timestamp_second"}),
+	}).Create(&ReplicatePieceProgressTable{
+		ObjectID:              progress.ObjectID,
+		ReplicateIndex:        progress.ReplicateIdx,
+		PieceIndex:            progress.PieceIdx,
+		SpOperatorAddress:     progress.SpOperatorAddress,
+		UpdateTimestampSecond: time.Now().Unix(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert replicate piece progress record: %s", result.Error)
+	}
+	return nil
+}
+
+// GetReplicatePieceProgress returns all the replicated pieces of the object.
+func (s *SpDBImpl) GetReplicatePieceProgress(objectID uint64) ([]*corespdb.ReplicatePieceProgress, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("getReplicatePieceProgress")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var queryReturns []ReplicatePieceProgressTable
+	result := s.db.Where("object_id = ?", objectID).Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query replicate piece progress table: %s", result.Error)
+	}
+	progresses := make([]*corespdb.ReplicatePieceProgress, 0, len(queryReturns))
+	for _, progress := range queryReturns {
+		progresses = append(progresses, &corespdb.ReplicatePieceProgress{
+			ObjectID:              progress.ObjectID,
+			ReplicateIdx:          progress.ReplicateIndex,
+			PieceIdx:              progress.PieceIndex,
+			SpOperatorAddress:     progress.SpOperatorAddress,
+			UpdateTimestampSecond: progress.UpdateTimestampSecond,
+		})
+	}
+	return progresses, nil
+}
+
+// DeleteReplicatePieceProgress deletes the replicated pieces of the replicate index.
+func (s *SpDBImpl) DeleteReplicatePieceProgress(objectID uint64, replicateIdx uint32) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("deleteReplicatePieceProgress")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	return s.db.Where("object_id = ? and replicate_index = ?", objectID, replicateIdx).
+		Delete(&ReplicatePieceProgressTable{}).Error
+}
+
+// DeleteAllReplicatePieceProgress deletes all the replicated pieces of the object.
+func (s *SpDBImpl) DeleteAllReplicatePieceProgress(objectID uint64) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("deleteAllReplicatePieceProgress")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	return s.db.Where("object_id = ?", objectID).Delete(&ReplicatePieceProgressTable{}).Error
+}
@@ -0,0 +1,15 @@
+package sqldb
+
+// ReplicatePieceProgressTable table schema.
+type ReplicatePieceProgressTable struct {
+	ObjectID              uint64 `gorm:"primary_key"`
+	ReplicateIndex        uint32 `gorm:"primary_key"`
+	PieceIndex            uint32 `gorm:"primary_key"`
+	SpOperatorAddress     string
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set ReplicatePieceProgressTable Schema's table name in database.
+func (ReplicatePieceProgressTable) TableName() string {
+	return ReplicatePieceProgressTableName
+}
@@ -0,0 +1,89 @@
+package sqldb
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+// SecondarySpLatencyEMADivisor defines the divisor of the exponential moving average
+// latency of secondary sp, the newest latency weighs 1/SecondarySpLatencyEMADivisor.
+const SecondarySpLatencyEMADivisor = 5
+
+// UpdateSecondarySpStats accumulates the replicate result of the secondary sp, the
+// counters are increased by a single upsert so that concurrent replications of the
+// same secondary sp neither lose updates nor conflict on the first insert.
+func (s *SpDBImpl) UpdateSecondarySpStats(operatorAddress string, succeed bool, latency time.Duration) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("updateSecondarySpStats")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	column := func(name string) string {
+		return SecondarySpStatsTableName + "." + name
+	}
+	stats := &SecondarySpStatsTable{
+		OperatorAddress:       operatorAddress,
+		UpdateTimestampSecond: time.Now().Unix(),
+	}
+	updates := map[string]interface{}{
+		"update_timestamp_second": stats.UpdateTimestampSecond,
+	}
+	if succeed {
+		stats.SucceedCount = 1
+		updates["succeed_count"] = gorm.Expr(column("succeed_count") + " + 1")
+		// no piece is replicated in this round if the latency is zero, e.g. all pieces are resumed
+		if latencyMs := latency.Milliseconds(); latencyMs > 0 {
+			stats.AvgLatencyMs = latencyMs
+			updates["avg_latency_ms"] = gorm.Expr(fmt.Sprintf("CASE WHEN %s = 0 THEN ? ELSE (%s * ? + ?) / ? END",
+				column("avg_latency_ms"), column("avg_latency_ms")),
+				latencyMs, SecondarySpLatencyEMADivisor-1, latencyMs, SecondarySpLatencyEMADivisor)
+		}
+	} else {
+		stats.FailedCount = 1
+		updates["failed_count"] = gorm.Expr(column("failed_count") + " + 1")
+	}
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "operator_address"}},
+		DoUpdates: clause.Assignments(updates),
+	}).Create(stats)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update secondary sp stats table: %s", result.Error)
+	}
+	return nil
+}
+
+// GetSecondarySpStats returns the stats of the secondary sps.
+func (s *SpDBImpl) GetSecondarySpStats(operatorAddresses []string) (map[string]*corespdb.SecondarySpStats, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("getSecondarySpStats")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var queryReturn []SecondarySpStatsTable
+	statsMap := make(map[string]*corespdb.SecondarySpStats)
+	if len(operatorAddresses) == 0 {
+		return statsMap, nil
+	}
+	result := s.db.Where("operator_address IN ?", operatorAddresses).Find(&queryReturn)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query secondary sp stats table: %s", result.Error)
+	}
+	for _, stats := range queryReturn {
+		statsMap[stats.OperatorAddress] = &corespdb.SecondarySpStats{
+			OperatorAddress:       stats.OperatorAddress,
+			SucceedCount:          stats.SucceedCount,
+			FailedCount:           stats.FailedCount,
+			AvgLatencyMs:          stats.AvgLatencyMs,
+			UpdateTimestampSecond: stats.UpdateTimestampSecond,
+		}
+	}
+	return statsMap, nil
+}
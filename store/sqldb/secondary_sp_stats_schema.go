@@ -0,0 +1,15 @@
+package sqldb
+
+// SecondarySpStatsTable table schema
+type SecondarySpStatsTable struct {
+	OperatorAddress       string `gorm:"primary_key"`
+	SucceedCount          uint64
+	FailedCount           uint64
+	AvgLatencyMs          int64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set SecondarySpStatsTable Schema's table name in database
+func (SecondarySpStatsTable) TableName() string {
+	return SecondarySpStatsTableName
+}
@@ -1,16 +1,17 @@
 package sqldb
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
 	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
 )
 
 const (
@@ -22,6 +23,8 @@ const (
 	SpDBAddress = "SP_DB_ADDRESS"
 	// SpDBDataBase defines env variable name for sp db database.
 	SpDBDataBase = "SP_DB_DATABASE"
+	// SpDBDriver defines env variable name for sp db driver.
+	SpDBDriver = "SP_DB_DRIVER"
 
 	// DefaultConnMaxLifetime defines the default max liveliness time of connection.
 	DefaultConnMaxLifetime = 60
@@ -34,10 +37,13 @@ const (
 )
 
 var _ corespdb.SPDB = &SpDBImpl{}
+var _ corespdb.ContextBinder = &SpDBImpl{}
 
 // SpDBImpl storage provider database, implements SPDB interface
 type SpDBImpl struct {
-	db *gorm.DB
+	db               *gorm.DB
+	readRecordShards *readRecordShards
+	readRecordWriter *readRecordWriter
 }
 
 // NewSpDB return a database instance
@@ -48,64 +54,68 @@ func NewSpDB(config *config.SQLDBConfig) (*SpDBImpl, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &SpDBImpl{db: db}, err
+	shards := newReadRecordShards(db, config.ReadRecordRetentionMonths)
+	return &SpDBImpl{db: db, readRecordShards: shards, readRecordWriter: newReadRecordWriter(db, shards)}, err
 }
 
-// InitDB init a db instance
+// WithContext returns the SpDBImpl whose db calls are made with the context, the read record
+// shards and writer are shared with s.
+func (s *SpDBImpl) WithContext(ctx context.Context) corespdb.SPDB {
+	return &SpDBImpl{db: s.db.WithContext(ctx), readRecordShards: s.readRecordShards, readRecordWriter: s.readRecordWriter}
+}
+
+// Close flushes the buffered read records and stops maintaining the read record shards,
+// the db connections are kept for the in-flight requests.
+func (s *SpDBImpl) Close() error {
+	s.readRecordWriter.close()
+	s.readRecordShards.close()
+	return nil
+}
+
+// InitDB init a db instance, the pending migrations are applied unless SkipMigrate is set,
+// and it refuses the schema that is migrated by the newer binary.
 func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.User, config.Passwd, config.Address, config.Database)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := OpenDB(config)
 	if err != nil {
-		log.Errorw("gorm failed to open db", "error", err)
 		return nil, err
 	}
-	sqlDB, err := db.DB()
+	migrator, err := migrate.NewMigrator(db, MigrationDBName, Migrations())
 	if err != nil {
-		log.Errorw("gorm failed to set db params", "error", err)
-		return nil, err
-	}
-	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime) * time.Second)
-	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTime) * time.Second)
-	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
-	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
-	// create if not exist
-	if err = db.AutoMigrate(&UploadObjectProgressTable{}); err != nil {
-		log.Errorw("failed to upload object progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&UploadEventTable{}); err != nil {
-		log.Errorw("failed to upload event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&GCObjectProgressTable{}); err != nil {
-		log.Errorw("failed to gc object progress table", "error", err)
+		log.Errorw("failed to new spdb migrator", "error", err)
 		return nil, err
 	}
-	if err = db.AutoMigrate(&SpInfoTable{}); err != nil {
-		log.Errorw("failed to create sp info table", "error", err)
+	if err = migrator.Startup(!config.SkipMigrate); err != nil {
+		log.Errorw("failed to migrate spdb", "error", err)
 		return nil, err
 	}
-	if err = db.AutoMigrate(&PieceHashTable{}); err != nil {
-		log.Errorw("failed to create piece hash table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&IntegrityMetaTable{}); err != nil {
-		log.Errorw("failed to create integrity meta table", "error", err)
+	return db, nil
+}
+
+// OpenDB opens the db instance without migrating the schema.
+func OpenDB(config *config.SQLDBConfig) (*gorm.DB, error) {
+	dialector, err := openDialector(config)
+	if err != nil {
+		log.Errorw("failed to open sql db dialector", "driver", config.Driver, "error", err)
 		return nil, err
 	}
-	if err = db.AutoMigrate(&BucketTrafficTable{}); err != nil {
-		log.Errorw("failed to create bucket traffic table", "error", err)
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		log.Errorw("gorm failed to open db", "error", err)
 		return nil, err
 	}
-	if err = db.AutoMigrate(&ReadRecordTable{}); err != nil {
-		log.Errorw("failed to create read record table", "error", err)
+	if err = db.Use(tracing.NewGormPlugin("spdb")); err != nil {
+		log.Errorw("gorm failed to use tracing plugin", "error", err)
 		return nil, err
 	}
-	if err = db.AutoMigrate(&OffChainAuthKeyTable{}); err != nil {
-		log.Errorw("failed to create off-chain authKey table", "error", err)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Errorw("gorm failed to set db params", "error", err)
 		return nil, err
 	}
+	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime) * time.Second)
+	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTime) * time.Second)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	return db, nil
 }
 
@@ -123,6 +133,9 @@ func LoadDBConfigFromEnv(config *config.SQLDBConfig) {
 	if val, ok := os.LookupEnv(SpDBDataBase); ok {
 		config.Database = val
 	}
+	if val, ok := os.LookupEnv(SpDBDriver); ok {
+		config.Driver = val
+	}
 }
 
 // OverrideConfigVacancy override the SQLDB param zero value
@@ -0,0 +1,179 @@
+package sqldb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	storetypes "github.com/bnb-chain/greenfield-storage-provider/store/types"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+)
+
+// testDBConfigs returns the sqlite config and the mysql, postgres configs that are set by
+// SPDB_TEST_MYSQL and SPDB_TEST_POSTGRES env vars in the user:passwd@address/database format.
+func testDBConfigs(t *testing.T) map[string]*config.SQLDBConfig {
+	configs := map[string]*config.SQLDBConfig{
+		DriverSQLite: {Driver: DriverSQLite, Database: filepath.Join(t.TempDir(), "spdb.db")},
+	}
+	for driver, env := range map[string]string{DriverMySQL: "SPDB_TEST_MYSQL", DriverPostgres: "SPDB_TEST_POSTGRES"} {
+		dsn, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+		account, location, _ := strings.Cut(dsn, "@")
+		user, passwd, _ := strings.Cut(account, ":")
+		address, database, _ := strings.Cut(location, "/")
+		configs[driver] = &config.SQLDBConfig{Driver: driver, User: user, Passwd: passwd, Address: address, Database: database}
+	}
+	return configs
+}
+
+func TestSpDBBehaviour(t *testing.T) {
+	for driver, cfg := range testDBConfigs(t) {
+		t.Run(driver, func(t *testing.T) {
+			db, err := NewSpDB(cfg)
+			require.NoError(t, err)
+			// the shared databases may keep the records of the previous run
+			objectID := uint64(time.Now().UnixNano() % 1e12)
+
+			// upload progress
+			require.NoError(t, db.InsertUploadProgress(objectID))
+			err = db.InsertUploadProgress(objectID)
+			assert.True(t, errors.Is(err, ErrDuplicateEntry))
+			require.NoError(t, db.UpdateUploadProgress(&corespdb.UploadObjectMeta{
+				ObjectID:  objectID,
+				TaskState: storetypes.TaskState_TASK_STATE_UPLOAD_OBJECT_DONE,
+			}))
+			metas, err := db.GetUploadMetasToReplicate(1000)
+			require.NoError(t, err)
+			found := false
+			for _, meta := range metas {
+				found = found || meta.ObjectID == objectID
+			}
+			assert.True(t, found)
+
+			// the duplicate inserts are ignored
+			integrity := &corespdb.IntegrityMeta{ObjectID: objectID, IntegrityChecksum: []byte("c"), Signature: []byte("s")}
+			require.NoError(t, db.SetObjectIntegrity(integrity))
+			require.NoError(t, db.SetObjectIntegrity(integrity))
+			require.NoError(t, db.SetReplicatePieceChecksum(objectID, 0, 0, []byte("p")))
+			require.NoError(t, db.SetReplicatePieceChecksum(objectID, 0, 0, []byte("p")))
+			progress := &corespdb.ReplicatePieceProgress{ObjectID: objectID, SpOperatorAddress: "sp"}
+			require.NoError(t, db.SetReplicatePieceProgress(progress))
+			require.NoError(t, db.SetReplicatePieceProgress(progress))
+			// the piece replicated to the backup sp overwrites the replaced sp
+			require.NoError(t, db.SetReplicatePieceProgress(&corespdb.ReplicatePieceProgress{ObjectID: objectID, SpOperatorAddress: "backup"}))
+			progresses, err := db.GetReplicatePieceProgress(objectID)
+			require.NoError(t, err)
+			require.Len(t, progresses, 1)
+			assert.Equal(t, "backup", progresses[0].SpOperatorAddress)
+			_, err = db.GetObjectIntegrity(objectID + 1)
+			assert.True(t, IsRecordNotFound(err))
+
+			// read quota
+			record := &corespdb.ReadRecord{BucketID: objectID, BucketName: "bucket", ReadSize: 60,
+				ReadTimestampUs: GetCurrentTimestampUs()}
+			quota := &corespdb.BucketQuota{ReadQuotaSize: 100}
+			require.NoError(t, db.CheckQuotaAndAddReadRecord(record, quota))
+			assert.Equal(t, ErrCheckQuotaEnough, db.CheckQuotaAndAddReadRecord(record, quota))
+			traffic, err := db.GetBucketTraffic(objectID, TimeToYearMonth(TimestampUsToTime(record.ReadTimestampUs)))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(60), traffic.ReadConsumedSize)
+
+			// auth key
+			user := "0x" + util.Uint64ToString(objectID)
+			key, err := db.GetAuthKey(user, "domain")
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), key.NextNonce)
+			require.NoError(t, db.UpdateAuthKey(user, "domain", 0, 1, "key", time.Now().Add(time.Hour)))
+
+			// sp info
+			require.NoError(t, db.SetOwnSpInfo(&sptypes.StorageProvider{OperatorAddress: user, TotalDeposit: sdkmath.NewInt(1)}))
+			sp, err := db.GetOwnSpInfo()
+			require.NoError(t, err)
+			assert.Equal(t, user, sp.GetOperatorAddress())
+
+			// secondary sp stats in the transaction
+			require.NoError(t, db.UpdateSecondarySpStats(user, true, time.Millisecond))
+			require.NoError(t, db.UpdateSecondarySpStats(user, false, time.Millisecond))
+			stats, err := db.GetSecondarySpStats([]string{user})
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), stats[user].SucceedCount)
+			assert.Equal(t, uint64(1), stats[user].FailedCount)
+			assert.Equal(t, int64(1), stats[user].AvgLatencyMs)
+
+			// recover job
+			jobID, err := db.InsertRecoverJob(&corespdb.RecoverJob{Status: "running"})
+			require.NoError(t, err)
+			require.NoError(t, db.IncreaseRecoverJobPieces(jobID, true))
+			job, err := db.GetLatestRecoverJob()
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), job.SucceedPieces)
+
+			// webhook deliveries are ignored if repeated, and moved to the dead letter table
+			subID, err := db.InsertWebhookSubscription(&corespdb.WebhookSubscription{BucketID: objectID,
+				BucketName: "bucket", URL: "http://localhost", Events: []string{corespdb.WebhookEventObjectSealed}})
+			require.NoError(t, err)
+			delivery := &corespdb.WebhookDelivery{SubscriptionID: subID, EventID: "change-1",
+				EventType: corespdb.WebhookEventObjectSealed, Payload: "{}"}
+			require.NoError(t, db.InsertWebhookDeliveries([]*corespdb.WebhookDelivery{delivery}, objectID))
+			require.NoError(t, db.InsertWebhookDeliveries([]*corespdb.WebhookDelivery{delivery}, objectID+1))
+			cursor, err := db.GetWebhookCursor()
+			require.NoError(t, err)
+			assert.Equal(t, objectID+1, cursor)
+			inserted, err := db.InsertQuotaWebhookDelivery(&corespdb.WebhookDelivery{SubscriptionID: subID,
+				EventID: "quota-2023-02", EventType: corespdb.WebhookEventBucketQuotaNearExhausted}, "2023-02")
+			require.NoError(t, err)
+			assert.True(t, inserted)
+			inserted, err = db.InsertQuotaWebhookDelivery(&corespdb.WebhookDelivery{SubscriptionID: subID,
+				EventID: "quota-2023-02", EventType: corespdb.WebhookEventBucketQuotaNearExhausted}, "2023-02")
+			require.NoError(t, err)
+			assert.False(t, inserted)
+			var subDeliveries []*corespdb.WebhookDelivery
+			deliveries, err := db.ListDueWebhookDeliveries(time.Now().Unix(), 1000)
+			require.NoError(t, err)
+			for _, d := range deliveries {
+				if d.SubscriptionID == subID {
+					subDeliveries = append(subDeliveries, d)
+				}
+			}
+			require.Len(t, subDeliveries, 2)
+			require.NoError(t, db.UpdateWebhookDeliveryRetry(subDeliveries[0].DeliveryID, time.Now().Add(time.Hour).Unix(), "timeout"))
+			require.NoError(t, db.MoveWebhookDeliveryToDeadLetter(subDeliveries[1], "timeout"))
+			deleted, err := db.DeleteWebhookSubscription(objectID, subID)
+			require.NoError(t, err)
+			assert.True(t, deleted)
+			subs, err := db.ListWebhookSubscriptions(objectID)
+			require.NoError(t, err)
+			assert.Empty(t, subs)
+
+			// the subscriptions of the deleted bucket are deleted with their deliveries
+			for i := 0; i < 2; i++ {
+				subID, err = db.InsertWebhookSubscription(&corespdb.WebhookSubscription{BucketID: objectID,
+					BucketName: "bucket", URL: "http://localhost", Events: []string{corespdb.WebhookEventObjectSealed}})
+				require.NoError(t, err)
+				require.NoError(t, db.InsertWebhookDeliveries([]*corespdb.WebhookDelivery{{SubscriptionID: subID,
+					EventID: "change-2", EventType: corespdb.WebhookEventObjectSealed, Payload: "{}"}}, objectID+2))
+			}
+			require.NoError(t, db.DeleteBucketWebhookSubscriptions(objectID))
+			subs, err = db.ListWebhookSubscriptions(objectID)
+			require.NoError(t, err)
+			assert.Empty(t, subs)
+			deliveries, err = db.ListDueWebhookDeliveries(time.Now().Unix(), 1000)
+			require.NoError(t, err)
+			for _, d := range deliveries {
+				assert.NotEqual(t, subID, d.SubscriptionID)
+			}
+		})
+	}
+}
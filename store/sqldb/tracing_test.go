@@ -0,0 +1,52 @@
+package sqldb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/tracing"
+)
+
+func TestSpDBWithContextTraced(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "trace.json")
+	tracer, err := tracing.NewTracing(&tracing.TracingConfig{Enable: true, Exporter: tracing.ExporterFile, FilePath: filePath}, nil)
+	require.NoError(t, err)
+	db := newTestSpDB(t)
+
+	// the db call without the span context is not traced
+	require.NoError(t, db.SetObjectIntegrity(&corespdb.IntegrityMeta{ObjectID: 1, IntegrityChecksum: []byte("c")}))
+	ctx, request := tracing.StartSpan(context.Background(), "request")
+	_, err = corespdb.WithContext(ctx, db).GetObjectIntegrity(1)
+	require.NoError(t, err)
+	request.End()
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer file.Close()
+	type spanRecord struct {
+		SpanID     string            `json:"span_id"`
+		ParentID   string            `json:"parent_id"`
+		Name       string            `json:"name"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	records := make(map[string]*spanRecord)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &spanRecord{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		records[record.Name] = record
+	}
+	require.Len(t, records, 2)
+	require.Contains(t, records, "spdb.query")
+	assert.Equal(t, records["request"].SpanID, records["spdb.query"].ParentID)
+	assert.Equal(t, IntegrityMetaTableName, records["spdb.query"].Attributes["db.table"])
+}
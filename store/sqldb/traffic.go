@@ -6,15 +6,23 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
-	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
 )
 
-// CheckQuotaAndAddReadRecord check current quota, and add read record
-// TODO: Traffic statistics may be inaccurate in extreme cases, optimize it in the future
+// checkQuotaMaxAttempts defines the max attempts of the conditional update that consumes the quota.
+const checkQuotaMaxAttempts = 10
+
+// CheckQuotaAndAddReadRecord check current quota, and add read record. The quota is checked
+// and consumed by one conditional update, so the concurrent downloads of one bucket never
+// exceed the quota. The read record is inserted asynchronously in batch.
 func (s *SpDBImpl) CheckQuotaAndAddReadRecord(record *corespdb.ReadRecord, quota *corespdb.BucketQuota) error {
+	// the quota is not consumed if the record can not be added
+	if s.readRecordWriter.isClosed() {
+		return ErrSpDBClosed
+	}
 	startTime := time.Now()
 	defer func() {
 		observer := metrics.SPDBTimeHistogram.WithLabelValues("checkQuotaAndAddReadRecord")
@@ -22,74 +30,62 @@ func (s *SpDBImpl) CheckQuotaAndAddReadRecord(record *corespdb.ReadRecord, quota
 	}()
 
 	yearMonth := TimeToYearMonth(TimestampUsToTime(record.ReadTimestampUs))
-	bucketTraffic, err := s.GetBucketTraffic(record.BucketID, yearMonth)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
-	}
-	if bucketTraffic == nil {
-		// insert, if not existed
-		insertBucketTraffic := &BucketTrafficTable{
-			BucketID:         record.BucketID,
-			Month:            yearMonth,
-			BucketName:       record.BucketName,
-			ReadConsumedSize: 0,
-			ReadQuotaSize:    quota.ReadQuotaSize,
-			ModifiedTime:     time.Now(),
-		}
-		result := s.db.Create(insertBucketTraffic)
-		if result.Error != nil {
-			return fmt.Errorf("failed to insert bucket traffic table: %s", result.Error)
-		}
-		if result.RowsAffected != 1 {
-			log.Infow("insert traffic", "RowsAffected", result.RowsAffected, "record", record, "quota", quota)
-		}
-		bucketTraffic = &corespdb.BucketTraffic{
-			BucketID:         insertBucketTraffic.BucketID,
-			YearMonth:        insertBucketTraffic.Month,
-			BucketName:       insertBucketTraffic.BucketName,
-			ReadConsumedSize: insertBucketTraffic.ReadConsumedSize,
-			ReadQuotaSize:    insertBucketTraffic.ReadQuotaSize,
-		}
-	}
-	if bucketTraffic.ReadQuotaSize != quota.ReadQuotaSize {
-		// update if chain quota has changed
+	inserted := false
+	for attempt := 1; ; attempt++ {
+		// consume the quota if it is enough, the quota is updated if the chain quota has changed
 		result := s.db.Model(&BucketTrafficTable{}).
-			Where("bucket_id = ? and month = ?", bucketTraffic.BucketID, bucketTraffic.YearMonth).
-			Updates(BucketTrafficTable{
-				ReadQuotaSize: quota.ReadQuotaSize,
-				ModifiedTime:  time.Now(),
+			Where("bucket_id = ? and month = ? and read_consumed_size + ? <= ?",
+				record.BucketID, yearMonth, record.ReadSize, quota.ReadQuotaSize).
+			Updates(map[string]interface{}{
+				"read_consumed_size": gorm.Expr("read_consumed_size + ?", record.ReadSize),
+				"read_quota_size":    quota.ReadQuotaSize,
+				"modified_time":      time.Now(),
 			})
 		if result.Error != nil {
 			return fmt.Errorf("failed to update bucket traffic table: %s", result.Error)
 		}
-		// TODO:: change the transaction way to update
-		if result.RowsAffected != 1 {
-			log.Infow("update traffic", "RowsAffected", result.RowsAffected, "record", record, "quota", quota)
+		if result.RowsAffected == 1 {
+			break
+		}
+		bucketTraffic, err := s.GetBucketTraffic(record.BucketID, yearMonth)
+		if IsRecordNotFound(err) && !inserted {
+			// insert, if not existed, and consume the quota again
+			result = s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&BucketTrafficTable{
+				BucketID:         record.BucketID,
+				Month:            yearMonth,
+				BucketName:       record.BucketName,
+				ReadConsumedSize: 0,
+				ReadQuotaSize:    quota.ReadQuotaSize,
+				ModifiedTime:     time.Now(),
+			})
+			if result.Error != nil {
+				return fmt.Errorf("failed to insert bucket traffic table: %s", result.Error)
+			}
+			inserted = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if bucketTraffic.ReadConsumedSize+record.ReadSize > quota.ReadQuotaSize {
+			if bucketTraffic.ReadQuotaSize != quota.ReadQuotaSize {
+				s.db.Model(&BucketTrafficTable{}).
+					Where("bucket_id = ? and month = ?", record.BucketID, yearMonth).
+					Updates(map[string]interface{}{"read_quota_size": quota.ReadQuotaSize, "modified_time": time.Now()})
+			}
+			return ErrCheckQuotaEnough
+		}
+		// mysql does not count the matched row that is not changed, it only happens to the read of zero size
+		if record.ReadSize == 0 {
+			break
+		}
+		// the row is inserted or changed by others after the conditional update, consume the quota again
+		if attempt >= checkQuotaMaxAttempts {
+			return fmt.Errorf("failed to consume the quota of bucket %d after %d attempts", record.BucketID, attempt)
 		}
-		bucketTraffic.ReadQuotaSize = quota.ReadQuotaSize
-	}
-
-	// check quota
-	if bucketTraffic.ReadConsumedSize+record.ReadSize > quota.ReadQuotaSize {
-		return ErrCheckQuotaEnough
-	}
-
-	// update bucket traffic
-	result := s.db.Model(&BucketTrafficTable{}).
-		Where("bucket_id = ? and month = ?", bucketTraffic.BucketID, bucketTraffic.YearMonth).
-		Updates(BucketTrafficTable{
-			ReadConsumedSize: bucketTraffic.ReadConsumedSize + record.ReadSize,
-			ModifiedTime:     time.Now(),
-		})
-	if result.Error != nil {
-		return fmt.Errorf("failed to update bucket traffic table: %s", result.Error)
-	}
-	if result.RowsAffected != 1 {
-		log.Infow("update traffic", "RowsAffected", result.RowsAffected, "record", record, "quota", quota)
 	}
 
-	// add read record
-	insertReadRecord := &ReadRecordTable{
+	return s.readRecordWriter.add(&ReadRecordTable{
 		BucketID:        record.BucketID,
 		ObjectID:        record.ObjectID,
 		UserAddress:     record.UserAddress,
@@ -97,12 +93,7 @@ func (s *SpDBImpl) CheckQuotaAndAddReadRecord(record *corespdb.ReadRecord, quota
 		BucketName:      record.BucketName,
 		ObjectName:      record.ObjectName,
 		ReadSize:        record.ReadSize,
-	}
-	result = s.db.Create(insertReadRecord)
-	if result.Error != nil || result.RowsAffected != 1 {
-		return fmt.Errorf("failed to insert read record table: %s", result.Error)
-	}
-	return nil
+	})
 }
 
 // GetBucketTraffic return bucket traffic info
@@ -131,137 +122,91 @@ func (s *SpDBImpl) GetBucketTraffic(bucketID uint64, yearMonth string) (*corespd
 
 // GetReadRecord return record list by time range
 func (s *SpDBImpl) GetReadRecord(timeRange *corespdb.TrafficTimeRange) ([]*corespdb.ReadRecord, error) {
-	var (
-		result       *gorm.DB
-		records      []*corespdb.ReadRecord
-		queryReturns []ReadRecordTable
-	)
-
-	if timeRange.LimitNum <= 0 {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ?", timeRange.StartTimestampUs, timeRange.EndTimestampUs).
-			Find(&queryReturns)
-	} else {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ?", timeRange.StartTimestampUs, timeRange.EndTimestampUs).
-			Limit(timeRange.LimitNum).Find(&queryReturns)
-	}
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, result.Error
-	}
-	if result.Error != nil {
-		return records, fmt.Errorf("failed to read record table: %s", result.Error)
-	}
-	for _, record := range queryReturns {
-		records = append(records, &corespdb.ReadRecord{
-			BucketID:        record.BucketID,
-			ObjectID:        record.ObjectID,
-			UserAddress:     record.UserAddress,
-			BucketName:      record.BucketName,
-			ObjectName:      record.ObjectName,
-			ReadSize:        record.ReadSize,
-			ReadTimestampUs: record.ReadTimestampUs,
-		})
-	}
-	return records, nil
+	return s.listReadRecord(timeRange, "")
 }
 
 // GetBucketReadRecord return bucket record list by time range
 func (s *SpDBImpl) GetBucketReadRecord(bucketID uint64, timeRange *corespdb.TrafficTimeRange) ([]*corespdb.ReadRecord, error) {
-	var (
-		result       *gorm.DB
-		records      []*corespdb.ReadRecord
-		queryReturns []ReadRecordTable
-	)
-
-	if timeRange.LimitNum <= 0 {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and bucket_id = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, bucketID).
-			Find(&queryReturns)
-	} else {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and bucket_id = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, bucketID).
-			Limit(timeRange.LimitNum).Find(&queryReturns)
-	}
-	if result.Error != nil {
-		return records, fmt.Errorf("failed to query read record table: %s", result.Error)
-	}
-	for _, record := range queryReturns {
-		records = append(records, &corespdb.ReadRecord{
-			BucketID:        record.BucketID,
-			ObjectID:        record.ObjectID,
-			UserAddress:     record.UserAddress,
-			BucketName:      record.BucketName,
-			ObjectName:      record.ObjectName,
-			ReadSize:        record.ReadSize,
-			ReadTimestampUs: record.ReadTimestampUs,
-		})
-	}
-	return records, nil
+	return s.listReadRecord(timeRange, "bucket_id = ?", bucketID)
 }
 
 // GetObjectReadRecord return object record list by time range
 func (s *SpDBImpl) GetObjectReadRecord(objectID uint64, timeRange *corespdb.TrafficTimeRange) ([]*corespdb.ReadRecord, error) {
-	var (
-		result       *gorm.DB
-		records      []*corespdb.ReadRecord
-		queryReturns []ReadRecordTable
-	)
-
-	if timeRange.LimitNum <= 0 {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and object_id = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, objectID).
-			Find(&queryReturns)
-	} else {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and object_id = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, objectID).
-			Limit(timeRange.LimitNum).Find(&queryReturns)
-	}
-	if result.Error != nil {
-		return records, fmt.Errorf("failed to query read record table: %s", result.Error)
-	}
-	for _, record := range queryReturns {
-		records = append(records, &corespdb.ReadRecord{
-			BucketID:        record.BucketID,
-			ObjectID:        record.ObjectID,
-			UserAddress:     record.UserAddress,
-			BucketName:      record.BucketName,
-			ObjectName:      record.ObjectName,
-			ReadSize:        record.ReadSize,
-			ReadTimestampUs: record.ReadTimestampUs,
-		})
-	}
-	return records, nil
+	return s.listReadRecord(timeRange, "object_id = ?", objectID)
 }
 
 // GetUserReadRecord return user record list by time range
 func (s *SpDBImpl) GetUserReadRecord(userAddress string, timeRange *corespdb.TrafficTimeRange) ([]*corespdb.ReadRecord, error) {
+	return s.listReadRecord(timeRange, "user_address = ?", userAddress)
+}
+
+// listReadRecord returns the records in the time range that match the condition, only the
+// monthly shards overlapping the time range are queried, in the order of the month until
+// the limit is reached. The shards are listed from the cache, a shard dropped by others is
+// skipped and removed from the cache.
+func (s *SpDBImpl) listReadRecord(timeRange *corespdb.TrafficTimeRange, condition string, args ...interface{}) (
+	[]*corespdb.ReadRecord, error) {
 	var (
-		result       *gorm.DB
-		records      []*corespdb.ReadRecord
-		queryReturns []ReadRecordTable
+		records []*corespdb.ReadRecord
+		err     error
 	)
-
-	if timeRange.LimitNum <= 0 {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and user_address = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, userAddress).
-			Find(&queryReturns)
-	} else {
-		result = s.db.Where("read_timestamp_us >= ? and read_timestamp_us < ? and user_address = ?",
-			timeRange.StartTimestampUs, timeRange.EndTimestampUs, userAddress).
-			Limit(timeRange.LimitNum).Find(&queryReturns)
+	for _, shard := range s.readRecordShards.list() {
+		month, _ := parseReadRecordShardName(shard)
+		if month.UnixMicro() >= timeRange.EndTimestampUs || month.AddDate(0, 1, 0).UnixMicro() <= timeRange.StartTimestampUs {
+			continue
+		}
+		var queryReturns []ReadRecordShardTable
+		query := s.db.Table(shard).Where("read_timestamp_us >= ? and read_timestamp_us < ?",
+			timeRange.StartTimestampUs, timeRange.EndTimestampUs)
+		if condition != "" {
+			query = query.Where(condition, args...)
+		}
+		if timeRange.LimitNum > 0 {
+			query = query.Limit(timeRange.LimitNum - len(records))
+		}
+		if err = query.Order("read_timestamp_us").Find(&queryReturns).Error; err != nil {
+			if !s.db.Migrator().HasTable(shard) {
+				s.readRecordShards.remove(shard)
+				continue
+			}
+			return records, fmt.Errorf("failed to query read record table %s: %s", shard, err)
+		}
+		for _, record := range queryReturns {
+			records = append(records, &corespdb.ReadRecord{
+				BucketID:        record.BucketID,
+				ObjectID:        record.ObjectID,
+				UserAddress:     record.UserAddress,
+				BucketName:      record.BucketName,
+				ObjectName:      record.ObjectName,
+				ReadSize:        record.ReadSize,
+				ReadTimestampUs: record.ReadTimestampUs,
+			})
+		}
+		if timeRange.LimitNum > 0 && len(records) >= timeRange.LimitNum {
+			break
+		}
 	}
+	return records, nil
+}
+
+// GetBucketReadUsage return the daily read usage of bucket in [startDay, endDay), it queries
+// the daily rollups instead of the read records.
+func (s *SpDBImpl) GetBucketReadUsage(bucketID uint64, startDay, endDay string) ([]*corespdb.BucketReadUsage, error) {
+	var queryReturns []BucketReadRollupTable
+	result := s.db.Where("bucket_id = ? and day >= ? and day < ?", bucketID, startDay, endDay).
+		Order("day").Find(&queryReturns)
 	if result.Error != nil {
-		return records, fmt.Errorf("failed to query read record table: %s", result.Error)
-	}
-	for _, record := range queryReturns {
-		records = append(records, &corespdb.ReadRecord{
-			BucketID:        record.BucketID,
-			ObjectID:        record.ObjectID,
-			UserAddress:     record.UserAddress,
-			BucketName:      record.BucketName,
-			ObjectName:      record.ObjectName,
-			ReadSize:        record.ReadSize,
-			ReadTimestampUs: record.ReadTimestampUs,
+		return nil, fmt.Errorf("failed to query bucket read rollup table: %s", result.Error)
+	}
+	usages := make([]*corespdb.BucketReadUsage, 0, len(queryReturns))
+	for _, rollup := range queryReturns {
+		usages = append(usages, &corespdb.BucketReadUsage{
+			BucketID:   rollup.BucketID,
+			BucketName: rollup.BucketName,
+			Day:        rollup.Day,
+			ReadSize:   rollup.ReadSize,
+			ReadCount:  rollup.ReadCount,
 		})
 	}
-	return records, nil
+	return usages, nil
 }
@@ -35,3 +35,31 @@ type ReadRecordTable struct {
 func (ReadRecordTable) TableName() string {
 	return ReadRecordTableName
 }
+
+// ReadRecordShardTable is the table schema of the monthly read record shard, the indexes
+// are named by the shard, so they are created by createReadRecordShard.
+type ReadRecordShardTable struct {
+	ReadRecordID    uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID        uint64
+	ObjectID        uint64
+	UserAddress     string `gorm:"size:64"`
+	ReadTimestampUs int64  // microsecond timestamp
+	BucketName      string
+	ObjectName      string
+	ReadSize        uint64
+}
+
+// BucketReadRollupTable table schema, it aggregates the read records by bucket and day.
+type BucketReadRollupTable struct {
+	BucketID     uint64 `gorm:"primary_key"`
+	Day          string `gorm:"primary_key"` // format "2023-02-01"
+	BucketName   string
+	ReadSize     uint64
+	ReadCount    uint64
+	ModifiedTime time.Time
+}
+
+// TableName is used to set BucketReadRollup Schema's table name in database
+func (BucketReadRollupTable) TableName() string {
+	return BucketReadRollupTableName
+}
@@ -0,0 +1,228 @@
+package sqldb
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/migrate"
+)
+
+func newTestSpDB(t testing.TB) *SpDBImpl {
+	db, err := NewSpDB(&config.SQLDBConfig{Driver: DriverSQLite, Database: filepath.Join(t.TempDir(), "spdb.db")})
+	require.NoError(t, err)
+	return db
+}
+
+func TestCheckQuotaAndAddReadRecordConcurrently(t *testing.T) {
+	db := newTestSpDB(t)
+	var (
+		wg      sync.WaitGroup
+		succeed int64
+	)
+	now := GetCurrentTimestampUs()
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := db.CheckQuotaAndAddReadRecord(&corespdb.ReadRecord{BucketID: 1, BucketName: "bucket",
+				ReadSize: 10, ReadTimestampUs: now}, &corespdb.BucketQuota{ReadQuotaSize: 100})
+			if err == nil {
+				atomic.AddInt64(&succeed, 1)
+			} else {
+				assert.Equal(t, ErrCheckQuotaEnough, err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(10), succeed)
+	traffic, err := db.GetBucketTraffic(1, TimeToYearMonth(TimestampUsToTime(now)))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), traffic.ReadConsumedSize)
+
+	require.NoError(t, db.Close())
+	records, err := db.GetBucketReadRecord(1, &corespdb.TrafficTimeRange{StartTimestampUs: now, EndTimestampUs: now + 1})
+	require.NoError(t, err)
+	assert.Len(t, records, 10)
+	readTime := TimestampUsToTime(now)
+	usages, err := db.GetBucketReadUsage(1, TimeToDay(readTime), TimeToDay(readTime.AddDate(0, 0, 1)))
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, uint64(100), usages[0].ReadSize)
+	assert.Equal(t, uint64(10), usages[0].ReadCount)
+}
+
+func TestReadRecordShards(t *testing.T) {
+	db := newTestSpDB(t)
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	var timestamps []int64
+	for _, readTime := range []time.Time{month.AddDate(0, -3, 1), month.AddDate(0, -1, 1), month.AddDate(0, -1, 2), now} {
+		timestamps = append(timestamps, readTime.UnixMicro())
+	}
+	for _, ts := range timestamps {
+		require.NoError(t, db.readRecordWriter.add(&ReadRecordTable{BucketID: 1, BucketName: "bucket", ReadSize: 1, ReadTimestampUs: ts}))
+	}
+	require.NoError(t, db.Close())
+	// the records are not accepted after closing
+	assert.ErrorIs(t, db.readRecordWriter.add(&ReadRecordTable{BucketID: 1, ReadTimestampUs: now.UnixMicro()}), ErrSpDBClosed)
+	assert.ErrorIs(t, db.CheckQuotaAndAddReadRecord(&corespdb.ReadRecord{BucketID: 1, ReadSize: 1,
+		ReadTimestampUs: now.UnixMicro()}, &corespdb.BucketQuota{ReadQuotaSize: 10}), ErrSpDBClosed)
+	require.NoError(t, db.Close())
+
+	// the limit applies across the shards in the order of the timestamp
+	records, err := db.GetBucketReadRecord(1, &corespdb.TrafficTimeRange{StartTimestampUs: 0,
+		EndTimestampUs: now.UnixMicro() + 1, LimitNum: 2})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, timestamps[0], records[0].ReadTimestampUs)
+	assert.Equal(t, timestamps[1], records[1].ReadTimestampUs)
+	records, err = db.GetBucketReadRecord(1, &corespdb.TrafficTimeRange{StartTimestampUs: timestamps[1] + 1,
+		EndTimestampUs: now.UnixMicro() + 1})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// the expired shards are dropped by others and the rollups are kept, the cached shard
+	// is skipped and removed by the query
+	expired := readRecordShardName(month.AddDate(0, -3, 0))
+	assert.True(t, db.readRecordShards.has(expired))
+	shards := &readRecordShards{db: db.db, retentionMonths: 2}
+	shards.maintain(now)
+	names, err := listReadRecordShards(db.db)
+	require.NoError(t, err)
+	assert.Equal(t, names, shards.list())
+	assert.NotContains(t, names, expired)
+	assert.Contains(t, names, readRecordShardName(month.AddDate(0, 1, 0)))
+	records, err = db.GetBucketReadRecord(1, &corespdb.TrafficTimeRange{EndTimestampUs: now.UnixMicro() + 1})
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.False(t, db.readRecordShards.has(expired))
+	usages, err := db.GetBucketReadUsage(1, TimeToDay(month.AddDate(0, -3, 0)), TimeToDay(now.AddDate(0, 0, 1)))
+	require.NoError(t, err)
+	assert.Len(t, usages, 4)
+}
+
+func BenchmarkCheckQuotaAndAddReadRecord(b *testing.B) {
+	db := newTestSpDB(b)
+	defer db.Close()
+	quota := &corespdb.BucketQuota{ReadQuotaSize: 1 << 40}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := db.CheckQuotaAndAddReadRecord(&corespdb.ReadRecord{BucketID: 1, BucketName: "bucket",
+				ReadSize: 1, ReadTimestampUs: GetCurrentTimestampUs()}, quota); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestShardReadRecordMigration(t *testing.T) {
+	cfg := &config.SQLDBConfig{Driver: DriverSQLite, Database: filepath.Join(t.TempDir(), "spdb.db")}
+	gormDB, err := OpenDB(cfg)
+	require.NoError(t, err)
+	migrator, err := migrate.NewMigrator(gormDB, MigrationDBName, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(3, false)
+	require.NoError(t, err)
+	now := time.Now()
+	legacy := []*ReadRecordTable{
+		{BucketID: 1, ReadSize: 1, ReadTimestampUs: now.AddDate(0, -1, 0).UnixMicro()},
+		{BucketID: 1, ReadSize: 2, ReadTimestampUs: now.UnixMicro()},
+		{BucketID: 1, ReadSize: 3, ReadTimestampUs: now.UnixMicro() + 1},
+	}
+	require.NoError(t, gormDB.Create(legacy).Error)
+
+	_, err = migrator.Up(0, false)
+	require.NoError(t, err)
+	assert.False(t, gormDB.Migrator().HasTable(&ReadRecordTable{}))
+	db, err := NewSpDB(cfg)
+	require.NoError(t, err)
+	defer db.Close()
+	records, err := db.GetBucketReadRecord(1, &corespdb.TrafficTimeRange{EndTimestampUs: now.UnixMicro() + 2})
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	usages, err := db.GetBucketReadUsage(1, TimeToDay(now), TimeToDay(now.AddDate(0, 0, 1)))
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, uint64(5), usages[0].ReadSize)
+
+	_, err = migrator.Down(3, false)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, gormDB.Model(&ReadRecordTable{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestShardReadRecordMigrationResume(t *testing.T) {
+	cfg := &config.SQLDBConfig{Driver: DriverSQLite, Database: filepath.Join(t.TempDir(), "spdb.db")}
+	gormDB, err := OpenDB(cfg)
+	require.NoError(t, err)
+	migrator, err := migrate.NewMigrator(gormDB, MigrationDBName, Migrations())
+	require.NoError(t, err)
+	_, err = migrator.Up(3, false)
+	require.NoError(t, err)
+	now := time.Now()
+	legacy := make([]*ReadRecordTable, 0, 2*migrateBatchSize+1)
+	for i := 0; i < cap(legacy); i++ {
+		legacy = append(legacy, &ReadRecordTable{BucketID: 1, ReadSize: 1, ReadTimestampUs: now.UnixMicro() + int64(i)})
+	}
+	require.NoError(t, gormDB.CreateInBatches(legacy, 100).Error)
+
+	// the second batch is interrupted, the first batch is committed by itself
+	var deletes int
+	require.NoError(t, gormDB.Callback().Delete().Before("gorm:delete").Register("test:interrupt", func(tx *gorm.DB) {
+		if deletes++; deletes == 2 {
+			_ = tx.AddError(errors.New("interrupted"))
+		}
+	}))
+	_, err = migrator.Up(0, false)
+	require.Error(t, err)
+	var count int64
+	require.NoError(t, gormDB.Model(&ReadRecordTable{}).Count(&count).Error)
+	assert.Equal(t, int64(migrateBatchSize+1), count)
+	require.NoError(t, gormDB.Callback().Delete().Remove("test:interrupt"))
+
+	_, err = migrator.Up(0, false)
+	require.NoError(t, err)
+	db, err := NewSpDB(cfg)
+	require.NoError(t, err)
+	defer db.Close()
+	usages, err := db.GetBucketReadUsage(1, TimeToDay(now), TimeToDay(now.AddDate(0, 0, 1)))
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, uint64(len(legacy)), usages[0].ReadCount)
+}
+
+func TestDropGCUpdateTimestampIndexMigration(t *testing.T) {
+	gormDB, err := OpenDB(&config.SQLDBConfig{Driver: DriverSQLite, Database: filepath.Join(t.TempDir(), "spdb.db")})
+	require.NoError(t, err)
+	// the table of the existing databases is indexed by the old name
+	require.NoError(t, gormDB.Exec("CREATE TABLE gc_object_progress (task_key TEXT PRIMARY KEY, "+
+		"update_timestamp_second INTEGER)").Error)
+	require.NoError(t, gormDB.Exec("CREATE INDEX update_timestamp_index ON gc_object_progress "+
+		"(update_timestamp_second)").Error)
+	var migration *migrate.Migration
+	for _, m := range Migrations() {
+		if m.Name == "drop_gc_update_timestamp_index" {
+			migration = m
+		}
+	}
+	require.NotNil(t, migration)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, migration.Up(gormDB))
+		assert.False(t, gormDB.Migrator().HasIndex(GCObjectProgressTableName, legacyGCUpdateTimestampIndex))
+		assert.True(t, gormDB.Migrator().HasIndex(GCObjectProgressTableName, gcUpdateTimestampIndex))
+	}
+	require.NoError(t, migration.Down(gormDB))
+}
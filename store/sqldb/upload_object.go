@@ -18,6 +18,9 @@ func (s *SpDBImpl) InsertUploadProgress(objectID uint64) error {
 		CreateTimestampSecond: GetCurrentUnixTime(),
 		UpdateTimestampSecond: GetCurrentUnixTime(),
 	}); result.Error != nil || result.RowsAffected != 1 {
+		if IsDuplicateEntry(result.Error) {
+			return fmt.Errorf("failed to insert upload record: %w", ErrDuplicateEntry)
+		}
 		return fmt.Errorf("failed to insert upload record: %s", result.Error)
 	}
 	return nil
@@ -64,15 +67,34 @@ func (s *SpDBImpl) GetUploadState(objectID uint64) (storetypes.TaskState, error)
 	return storetypes.TaskState(queryReturn.TaskState), nil
 }
 
+func (s *SpDBImpl) GetUploadMeta(objectID uint64) (*corespdb.UploadObjectMeta, error) {
+	queryReturn := &UploadObjectProgressTable{}
+	result := s.db.First(queryReturn, "object_id = ?", objectID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query upload table: %s", result.Error)
+	}
+	secondarySignatures, err := util.StringToBytesSlice(queryReturn.SecondarySignatures)
+	if err != nil {
+		return nil, err
+	}
+	return &corespdb.UploadObjectMeta{
+		ObjectID:            queryReturn.ObjectID,
+		TaskState:           storetypes.TaskState(queryReturn.TaskState),
+		SecondaryAddresses:  util.SplitByComma(queryReturn.SecondaryAddresses),
+		SecondarySignatures: secondarySignatures,
+		ErrorDescription:    queryReturn.ErrorDescription,
+	}, nil
+}
+
 func (s *SpDBImpl) GetUploadMetasToReplicate(limit int) ([]*corespdb.UploadObjectMeta, error) {
 	var (
 		result                  *gorm.DB
 		uploadObjectProgresses  []UploadObjectProgressTable
 		returnUploadObjectMetas []*corespdb.UploadObjectMeta
 	)
-	result = s.db.Where("task_state IN ?", []string{
-		util.Uint32ToString(uint32(storetypes.TaskState_TASK_STATE_UPLOAD_OBJECT_DONE)),
-		util.Uint32ToString(uint32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_DOING)),
+	result = s.db.Where("task_state IN ?", []int32{
+		int32(storetypes.TaskState_TASK_STATE_UPLOAD_OBJECT_DONE),
+		int32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_DOING),
 	}).Order("update_timestamp_second DESC").Limit(limit).Find(&uploadObjectProgresses)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to query upload table: %s", result.Error)
@@ -91,9 +113,9 @@ func (s *SpDBImpl) GetUploadMetasToSeal(limit int) ([]*corespdb.UploadObjectMeta
 		uploadObjectProgresses  []UploadObjectProgressTable
 		returnUploadObjectMetas []*corespdb.UploadObjectMeta
 	)
-	result = s.db.Where("task_state IN ?", []string{
-		util.Uint32ToString(uint32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_DONE)),
-		util.Uint32ToString(uint32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DOING)),
+	result = s.db.Where("task_state IN ?", []int32{
+		int32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_DONE),
+		int32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DOING),
 	}).Order("update_timestamp_second DESC").Limit(limit).Find(&uploadObjectProgresses)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to query upload table: %s", result.Error)
@@ -35,3 +35,8 @@ func TimestampSecToTime(timeUnix int64) time.Time {
 func TimeToYearMonth(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")[0:7]
 }
+
+// TimeToDay convent time.Time to YYYY-MM-DD string
+func TimeToDay(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,348 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+// webhookChangeFeedCursorName defines the name of the change feed cursor in the webhook cursor table.
+const webhookChangeFeedCursorName = "change_feed"
+
+// InsertWebhookSubscription inserts a new webhook subscription and returns the subscription id.
+func (s *SpDBImpl) InsertWebhookSubscription(sub *corespdb.WebhookSubscription) (uint64, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("insertWebhookSubscription")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	insertSub := &WebhookSubscriptionTable{
+		BucketID:              sub.BucketID,
+		BucketName:            sub.BucketName,
+		Owner:                 sub.Owner,
+		URL:                   sub.URL,
+		Secret:                sub.Secret,
+		Events:                strings.Join(sub.Events, ","),
+		QuotaThreshold:        sub.QuotaThreshold,
+		CreateTimestampSecond: time.Now().Unix(),
+	}
+	result := s.db.Create(insertSub)
+	if result.Error != nil || result.RowsAffected != 1 {
+		return 0, fmt.Errorf("failed to insert webhook subscription table: %s", result.Error)
+	}
+	return insertSub.SubscriptionID, nil
+}
+
+// DeleteWebhookSubscription deletes the webhook subscription of the bucket and its pending deliveries.
+func (s *SpDBImpl) DeleteWebhookSubscription(bucketID uint64, subscriptionID uint64) (bool, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("deleteWebhookSubscription")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var deleted bool
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("subscription_id = ? and bucket_id = ?", subscriptionID, bucketID).
+			Delete(&WebhookSubscriptionTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook subscription table: %s", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+		if result = tx.Where("subscription_id = ?", subscriptionID).Delete(&WebhookDeliveryTable{}); result.Error != nil {
+			return fmt.Errorf("failed to delete webhook delivery table: %s", result.Error)
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// DeleteBucketWebhookSubscriptions deletes all the webhook subscriptions of the deleted bucket
+// and their pending deliveries.
+func (s *SpDBImpl) DeleteBucketWebhookSubscriptions(bucketID uint64) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("deleteBucketWebhookSubscriptions")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var subscriptionIDs []uint64
+		result := tx.Model(&WebhookSubscriptionTable{}).Where("bucket_id = ?", bucketID).
+			Pluck("subscription_id", &subscriptionIDs)
+		if result.Error != nil {
+			return fmt.Errorf("failed to query webhook subscription table: %s", result.Error)
+		}
+		if len(subscriptionIDs) == 0 {
+			return nil
+		}
+		if result = tx.Where("subscription_id in ?", subscriptionIDs).Delete(&WebhookDeliveryTable{}); result.Error != nil {
+			return fmt.Errorf("failed to delete webhook delivery table: %s", result.Error)
+		}
+		if result = tx.Where("bucket_id = ?", bucketID).Delete(&WebhookSubscriptionTable{}); result.Error != nil {
+			return fmt.Errorf("failed to delete webhook subscription table: %s", result.Error)
+		}
+		return nil
+	})
+}
+
+// ListWebhookSubscriptions returns the webhook subscriptions of the bucket.
+func (s *SpDBImpl) ListWebhookSubscriptions(bucketID uint64) ([]*corespdb.WebhookSubscription, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("listWebhookSubscriptions")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var queryReturn []*WebhookSubscriptionTable
+	result := s.db.Where("bucket_id = ?", bucketID).Order("subscription_id").Find(&queryReturn)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query webhook subscription table: %s", result.Error)
+	}
+	return toWebhookSubscriptions(queryReturn), nil
+}
+
+// ListAllWebhookSubscriptions returns the webhook subscriptions of all the buckets.
+func (s *SpDBImpl) ListAllWebhookSubscriptions() ([]*corespdb.WebhookSubscription, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("listAllWebhookSubscriptions")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var queryReturn []*WebhookSubscriptionTable
+	result := s.db.Order("subscription_id").Find(&queryReturn)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query webhook subscription table: %s", result.Error)
+	}
+	return toWebhookSubscriptions(queryReturn), nil
+}
+
+func toWebhookSubscriptions(subs []*WebhookSubscriptionTable) []*corespdb.WebhookSubscription {
+	res := make([]*corespdb.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		var events []string
+		if sub.Events != "" {
+			events = strings.Split(sub.Events, ",")
+		}
+		res = append(res, &corespdb.WebhookSubscription{
+			SubscriptionID:        sub.SubscriptionID,
+			BucketID:              sub.BucketID,
+			BucketName:            sub.BucketName,
+			Owner:                 sub.Owner,
+			URL:                   sub.URL,
+			Secret:                sub.Secret,
+			Events:                events,
+			QuotaThreshold:        sub.QuotaThreshold,
+			QuotaNotifiedMonth:    sub.QuotaNotifiedMonth,
+			CreateTimestampSecond: sub.CreateTimestampSecond,
+		})
+	}
+	return res
+}
+
+// GetWebhookCursor returns the cursor of the last change event dispatched to the deliveries.
+func (s *SpDBImpl) GetWebhookCursor() (uint64, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("getWebhookCursor")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	queryReturn := &WebhookCursorTable{}
+	result := s.db.First(queryReturn, "name = ?", webhookChangeFeedCursorName)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to query webhook cursor table: %s", result.Error)
+	}
+	return queryReturn.Cursor, nil
+}
+
+// InsertWebhookDeliveries inserts the pending deliveries and moves the cursor in a transaction.
+func (s *SpDBImpl) InsertWebhookDeliveries(deliveries []*corespdb.WebhookDelivery, cursor uint64) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("insertWebhookDeliveries")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if len(deliveries) > 0 {
+			insertDeliveries := make([]*WebhookDeliveryTable, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				insertDeliveries = append(insertDeliveries, toWebhookDeliveryTable(delivery))
+			}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(insertDeliveries)
+			if result.Error != nil {
+				return fmt.Errorf("failed to insert webhook delivery table: %s", result.Error)
+			}
+		}
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"cursor", "update_timestamp_second"}),
+		}).Create(&WebhookCursorTable{
+			Name:                  webhookChangeFeedCursorName,
+			Cursor:                cursor,
+			UpdateTimestampSecond: time.Now().Unix(),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update webhook cursor table: %s", result.Error)
+		}
+		return nil
+	})
+}
+
+// InsertQuotaWebhookDelivery inserts the pending delivery of the bucket quota event if the
+// subscription is not notified in the month.
+func (s *SpDBImpl) InsertQuotaWebhookDelivery(delivery *corespdb.WebhookDelivery, yearMonth string) (bool, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("insertQuotaWebhookDelivery")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var inserted bool
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// the conditional update makes sure the event is triggered once a month
+		result := tx.Model(&WebhookSubscriptionTable{}).
+			Where("subscription_id = ? and quota_notified_month <> ?", delivery.SubscriptionID, yearMonth).
+			Update("quota_notified_month", yearMonth)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update webhook subscription table: %s", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(toWebhookDeliveryTable(delivery))
+		if result.Error != nil {
+			return fmt.Errorf("failed to insert webhook delivery table: %s", result.Error)
+		}
+		inserted = true
+		return nil
+	})
+	return inserted, err
+}
+
+func toWebhookDeliveryTable(delivery *corespdb.WebhookDelivery) *WebhookDeliveryTable {
+	now := time.Now().Unix()
+	return &WebhookDeliveryTable{
+		SubscriptionID:             delivery.SubscriptionID,
+		EventID:                    delivery.EventID,
+		EventType:                  delivery.EventType,
+		Payload:                    delivery.Payload,
+		NextAttemptTimestampSecond: now,
+		CreateTimestampSecond:      now,
+	}
+}
+
+// ListDueWebhookDeliveries returns the pending deliveries whose next attempt time is not after now.
+func (s *SpDBImpl) ListDueWebhookDeliveries(now int64, limit int) ([]*corespdb.WebhookDelivery, error) {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("listDueWebhookDeliveries")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	var queryReturn []*WebhookDeliveryTable
+	result := s.db.Where("next_attempt_timestamp_second <= ?", now).
+		Order("next_attempt_timestamp_second").Order("delivery_id").Limit(limit).Find(&queryReturn)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query webhook delivery table: %s", result.Error)
+	}
+	deliveries := make([]*corespdb.WebhookDelivery, 0, len(queryReturn))
+	for _, delivery := range queryReturn {
+		deliveries = append(deliveries, &corespdb.WebhookDelivery{
+			DeliveryID:                 delivery.DeliveryID,
+			SubscriptionID:             delivery.SubscriptionID,
+			EventID:                    delivery.EventID,
+			EventType:                  delivery.EventType,
+			Payload:                    delivery.Payload,
+			Attempts:                   delivery.Attempts,
+			NextAttemptTimestampSecond: delivery.NextAttemptTimestampSecond,
+			LastError:                  delivery.LastError,
+			CreateTimestampSecond:      delivery.CreateTimestampSecond,
+		})
+	}
+	return deliveries, nil
+}
+
+// DeleteWebhookDelivery deletes the delivery that has been delivered.
+func (s *SpDBImpl) DeleteWebhookDelivery(deliveryID uint64) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("deleteWebhookDelivery")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	result := s.db.Where("delivery_id = ?", deliveryID).Delete(&WebhookDeliveryTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook delivery table: %s", result.Error)
+	}
+	return nil
+}
+
+// UpdateWebhookDeliveryRetry records the failed attempt and the next attempt time of the delivery.
+func (s *SpDBImpl) UpdateWebhookDeliveryRetry(deliveryID uint64, nextAttemptTime int64, lastError string) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("updateWebhookDeliveryRetry")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	result := s.db.Model(&WebhookDeliveryTable{}).Where("delivery_id = ?", deliveryID).Updates(map[string]interface{}{
+		"attempts":                      gorm.Expr("attempts + ?", 1),
+		"next_attempt_timestamp_second": nextAttemptTime,
+		"last_error":                    lastError,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook delivery table: %s", result.Error)
+	}
+	return nil
+}
+
+// MoveWebhookDeliveryToDeadLetter moves the delivery that exhausts the attempts to the dead letter table.
+func (s *SpDBImpl) MoveWebhookDeliveryToDeadLetter(delivery *corespdb.WebhookDelivery, lastError string) error {
+	startTime := time.Now()
+	defer func() {
+		observer := metrics.SPDBTimeHistogram.WithLabelValues("moveWebhookDeliveryToDeadLetter")
+		observer.Observe(time.Since(startTime).Seconds())
+	}()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("delivery_id = ?", delivery.DeliveryID).Delete(&WebhookDeliveryTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete webhook delivery table: %s", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// the subscription has been deleted together with the delivery
+			return nil
+		}
+		result = tx.Create(&WebhookDeadLetterTable{
+			SubscriptionID:        delivery.SubscriptionID,
+			EventID:               delivery.EventID,
+			EventType:             delivery.EventType,
+			Payload:               delivery.Payload,
+			Attempts:              delivery.Attempts + 1,
+			LastError:             lastError,
+			CreateTimestampSecond: delivery.CreateTimestampSecond,
+			FailedTimestampSecond: time.Now().Unix(),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to insert webhook dead letter table: %s", result.Error)
+		}
+		return nil
+	})
+}
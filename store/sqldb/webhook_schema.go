@@ -0,0 +1,68 @@
+package sqldb
+
+// WebhookSubscriptionTable table schema, the Events are joined by comma.
+type WebhookSubscriptionTable struct {
+	SubscriptionID        uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID              uint64 `gorm:"index:bucket_to_webhook_subscription"`
+	BucketName            string
+	Owner                 string
+	URL                   string `gorm:"size:1024"`
+	Secret                string
+	Events                string
+	QuotaThreshold        uint32
+	QuotaNotifiedMonth    string
+	CreateTimestampSecond int64
+}
+
+// TableName is used to set WebhookSubscriptionTable Schema's table name in database
+func (WebhookSubscriptionTable) TableName() string {
+	return WebhookSubscriptionTableName
+}
+
+// WebhookDeliveryTable table schema
+type WebhookDeliveryTable struct {
+	DeliveryID                 uint64 `gorm:"primary_key;autoIncrement"`
+	SubscriptionID             uint64 `gorm:"uniqueIndex:subscription_event_to_webhook_delivery,priority:1"`
+	EventID                    string `gorm:"size:128;uniqueIndex:subscription_event_to_webhook_delivery,priority:2"`
+	EventType                  string
+	Payload                    string `gorm:"type:text"`
+	Attempts                   uint32
+	NextAttemptTimestampSecond int64  `gorm:"index:next_attempt_to_webhook_delivery"`
+	LastError                  string `gorm:"type:text"`
+	CreateTimestampSecond      int64
+}
+
+// TableName is used to set WebhookDeliveryTable Schema's table name in database
+func (WebhookDeliveryTable) TableName() string {
+	return WebhookDeliveryTableName
+}
+
+// WebhookDeadLetterTable table schema
+type WebhookDeadLetterTable struct {
+	DeadLetterID          uint64 `gorm:"primary_key;autoIncrement"`
+	SubscriptionID        uint64 `gorm:"index:subscription_to_webhook_dead_letter"`
+	EventID               string `gorm:"size:128"`
+	EventType             string
+	Payload               string `gorm:"type:text"`
+	Attempts              uint32
+	LastError             string `gorm:"type:text"`
+	CreateTimestampSecond int64
+	FailedTimestampSecond int64
+}
+
+// TableName is used to set WebhookDeadLetterTable Schema's table name in database
+func (WebhookDeadLetterTable) TableName() string {
+	return WebhookDeadLetterTableName
+}
+
+// WebhookCursorTable table schema
+type WebhookCursorTable struct {
+	Name                  string `gorm:"primary_key;size:64"`
+	Cursor                uint64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set WebhookCursorTable Schema's table name in database
+func (WebhookCursorTable) TableName() string {
+	return WebhookCursorTableName
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// ErrNonPublicAddress defines the address is not reachable from the public network, e.g. the
+// loopback, private and link-local addresses.
+var ErrNonPublicAddress = errors.New("non-public address")
+
+// nonPublicNetworks defines the special-purpose networks that are not covered by the net.IP
+// methods, e.g. the carrier-grade NAT and the benchmarking networks.
+var nonPublicNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// IsPublicIP returns true if the ip is a global unicast address that is not loopback, private,
+// link-local or unspecified.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckPublicHost resolves the host and returns ErrNonPublicAddress if any of its addresses
+// is not public.
+func CheckPublicHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrNonPublicAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// PublicDialControl is the net.Dialer Control that refuses to connect the non-public addresses,
+// it checks the resolved address right before connecting, so the host can not be rebound to a
+// non-public address after it is checked.
+func PublicDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, address)
+	}
+	return nil
+}
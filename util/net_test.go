@@ -0,0 +1,52 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	cases := []struct {
+		ip     string
+		public bool
+	}{
+		{ip: "8.8.8.8", public: true},
+		{ip: "2001:4860:4860::8888", public: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00::1"},
+		{ip: "0.0.0.0"},
+		{ip: "::"},
+		{ip: "100.64.0.1"},
+		{ip: "224.0.0.1"},
+		{ip: "255.255.255.255"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "::ffff:10.0.0.1"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.public, IsPublicIP(net.ParseIP(c.ip)), c.ip)
+	}
+	assert.False(t, IsPublicIP(nil))
+}
+
+func TestCheckPublicHost(t *testing.T) {
+	assert.NoError(t, CheckPublicHost(context.Background(), "8.8.8.8"))
+	assert.True(t, errors.Is(CheckPublicHost(context.Background(), "169.254.169.254"), ErrNonPublicAddress))
+	assert.True(t, errors.Is(CheckPublicHost(context.Background(), "localhost"), ErrNonPublicAddress))
+}
+
+func TestPublicDialControl(t *testing.T) {
+	assert.NoError(t, PublicDialControl("tcp", "8.8.8.8:443", nil))
+	assert.True(t, errors.Is(PublicDialControl("tcp", "127.0.0.1:9333", nil), ErrNonPublicAddress))
+	assert.True(t, errors.Is(PublicDialControl("tcp6", "[::1]:443", nil), ErrNonPublicAddress))
+	assert.Error(t, PublicDialControl("tcp", "127.0.0.1", nil))
+}